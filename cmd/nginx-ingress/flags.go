@@ -7,8 +7,11 @@ import (
 	"net"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/nginx/kubernetes-ingress/internal/audit"
 	internalValidation "github.com/nginx/kubernetes-ingress/internal/validation"
 	api_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -162,13 +165,13 @@ var (
 		"Set the port where the Prometheus metrics are exposed. [1024 - 65535]")
 
 	enableServiceInsight = flag.Bool("enable-service-insight", false,
-		`Enable service insight for external load balancers. Requires -nginx-plus`)
+		`Enable service insight for external load balancers. With NGINX, the stats are derived from the readiness of the EndpointSlices of the upstream Services.`)
 
 	serviceInsightTLSSecretName = flag.String("service-insight-tls-secret", "",
 		`A Secret with a TLS certificate and key for TLS termination of the service insight.`)
 
 	serviceInsightListenPort = flag.Int("service-insight-listen-port", 9114,
-		"Set the port where the Service Insight stats are exposed. Requires -nginx-plus. [1024 - 65535]")
+		"Set the port where the Service Insight stats are exposed. [1024 - 65535]")
 
 	enableCustomResources = flag.Bool("enable-custom-resources", true,
 		"Enable custom resources")
@@ -198,9 +201,35 @@ var (
 
 	readyStatusPort = flag.Int("ready-status-port", 8081, "Set the port where the readiness endpoint is exposed. [1024 - 65535]")
 
+	readyStatusFailedReloadsThreshold = flag.Int("ready-status-failed-reloads-threshold", 0,
+		`Report not ready once NGINX has loaded the config after the startup when the last N reloads of NGINX failed. Set to 0 to disable. Requires -ready-status`)
+
+	readyStatusStalenessThreshold = flag.Duration("ready-status-staleness-threshold", 0,
+		`Report not ready once NGINX has loaded the config after the startup when changes have not been applied successfully for longer than the threshold. Set to 0 to disable. Requires -ready-status`)
+
 	enableLatencyMetrics = flag.Bool("enable-latency-metrics", false,
 		"Enable collection of latency metrics for upstreams. Requires -enable-prometheus-metrics")
 
+	certificateExpiryWarningDays = flag.String("certificate-expiry-warning-days", "30,7,1",
+		`Sets the number of days before the expiry of a certificate referenced by a resource when a Warning event is emitted for the resource. Separate multiple values by commas.`)
+
+	certificateExpiryThresholds []time.Duration
+
+	auditLog = flag.String("audit-log", "",
+		`Enables the audit log of the changes applied to the NGINX configuration and sets its sink: stdout, file or syslog. The audit log is disabled when empty.`)
+
+	auditLogFile = flag.String("audit-log-file", "/var/log/nginx-ingress/audit.log",
+		`Sets the path of the audit log file. Requires -audit-log=file`)
+
+	auditLogFileMaxSize = flag.Int("audit-log-file-max-size", 100,
+		`Sets the size in megabytes of the audit log file that triggers its rotation. Requires -audit-log=file`)
+
+	auditLogFileMaxBackups = flag.Int("audit-log-file-max-backups", 5,
+		`Sets the number of rotated audit log files to keep. Requires -audit-log=file`)
+
+	auditLogSyslogAddress = flag.String("audit-log-syslog-address", "",
+		`Sets the address of the syslog server for the audit log, for example udp://syslog:514. The local syslog server is used when empty. Requires -audit-log=syslog`)
+
 	enableCertManager = flag.Bool("enable-cert-manager", false,
 		"Enable cert-manager controller for VirtualServer resources. Requires -enable-custom-resources")
 
@@ -225,6 +254,10 @@ var (
 
 	enableDynamicWeightChangesReload = flag.Bool(dynamicWeightChangesParam, false, "Enable changing weights of split clients without reloading NGINX. Requires -nginx-plus")
 
+	enableRollouts = flag.Bool("enable-rollouts", false, "Enable the Rollout resources for the progressive delivery of the splits of VirtualServer routes. Requires -nginx-plus, -weight-changes-dynamic-reload and -enable-custom-resources")
+
+	enableReferenceGrants = flag.Bool("enable-reference-grants", false, "Enable the ReferenceGrant resources. References to Policies, Secrets and Services in other namespaces must be allowed by a ReferenceGrant. If not set, references to Policies in other namespaces are allowed. Requires -enable-custom-resources")
+
 	startupCheckFn func() error
 )
 
@@ -254,16 +287,21 @@ func initValidate(ctx context.Context) {
 		*enableLatencyMetrics = false
 	}
 
-	if *enableServiceInsight && !*nginxPlus {
-		nl.Warn(l, "enable-service-insight flag support is for NGINX Plus, service insight endpoint will not be exposed")
-		*enableServiceInsight = false
-	}
-
 	if *enableDynamicWeightChangesReload && !*nginxPlus {
 		nl.Warn(l, "weight-changes-dynamic-reload flag support is for NGINX Plus, Dynamic Weight Changes will not be enabled")
 		*enableDynamicWeightChangesReload = false
 	}
 
+	if *enableRollouts && (!*enableDynamicWeightChangesReload || !*enableCustomResources) {
+		nl.Warn(l, "enable-rollouts flag requires -nginx-plus, -weight-changes-dynamic-reload and -enable-custom-resources, Rollouts will not be enabled")
+		*enableRollouts = false
+	}
+
+	if *enableReferenceGrants && !*enableCustomResources {
+		nl.Warn(l, "enable-reference-grants flag requires -enable-custom-resources, ReferenceGrants will not be enabled")
+		*enableReferenceGrants = false
+	}
+
 	if *mgmtConfigMap != "" && !*nginxPlus {
 		nl.Warn(l, "mgmt-configmap flag requires -nginx-plus, mgmt configmap will not be used")
 		*mgmtConfigMap = ""
@@ -366,12 +404,38 @@ func mustValidateFlags(ctx context.Context) {
 		nl.Fatalf(l, "Invalid value for service-insight-listen-port: %v", metricsPortValidationError)
 	}
 
+	if *readyStatusFailedReloadsThreshold < 0 {
+		nl.Fatalf(l, "Invalid value for ready-status-failed-reloads-threshold: %v must not be negative", *readyStatusFailedReloadsThreshold)
+	}
+
+	if *readyStatusStalenessThreshold < 0 {
+		nl.Fatalf(l, "Invalid value for ready-status-staleness-threshold: %v must not be negative", *readyStatusStalenessThreshold)
+	}
+
+	auditLogValidationError := validateAuditLog(*auditLog)
+	if auditLogValidationError != nil {
+		nl.Fatalf(l, "Invalid value for audit-log: %v", auditLogValidationError)
+	}
+
+	if *auditLogFileMaxSize <= 0 {
+		nl.Fatalf(l, "Invalid value for audit-log-file-max-size: %v must be positive", *auditLogFileMaxSize)
+	}
+
+	if *auditLogFileMaxBackups < 0 {
+		nl.Fatalf(l, "Invalid value for audit-log-file-max-backups: %v must not be negative", *auditLogFileMaxBackups)
+	}
+
 	var err error
 	allowedCIDRs, err = parseNginxStatusAllowCIDRs(*nginxStatusAllowCIDRs)
 	if err != nil {
 		nl.Fatalf(l, "Invalid value for nginx-status-allow-cidrs: %v", err)
 	}
 
+	certificateExpiryThresholds, err = parseCertificateExpiryWarningDays(*certificateExpiryWarningDays)
+	if err != nil {
+		nl.Fatalf(l, "Invalid value for certificate-expiry-warning-days: %v", err)
+	}
+
 	if *appProtectLogLevel != appProtectLogLevelDefault && *appProtect && *nginxPlus {
 		appProtectlogLevelValidationError := validateLogLevel(*appProtectLogLevel)
 		if appProtectlogLevelValidationError != nil {
@@ -489,6 +553,15 @@ func validateLogFormat(logFormat string) error {
 	return fmt.Errorf("invalid log format: %v", logFormat)
 }
 
+// validateAuditLog makes sure a given audit log sink is one of the allowed values or empty
+func validateAuditLog(sink string) error {
+	switch sink {
+	case "", audit.SinkStdout, audit.SinkFile, audit.SinkSyslog:
+		return nil
+	}
+	return fmt.Errorf("invalid audit log sink: %v", sink)
+}
+
 // parseNginxStatusAllowCIDRs converts a comma separated CIDR/IP address string into an array of CIDR/IP addresses.
 // It returns an array of the valid CIDR/IP addresses or an error if given an invalid address.
 func parseNginxStatusAllowCIDRs(input string) (cidrs []string, err error) {
@@ -504,6 +577,21 @@ func parseNginxStatusAllowCIDRs(input string) (cidrs []string, err error) {
 	return cidrs, nil
 }
 
+// parseCertificateExpiryWarningDays converts a comma separated string of days into an array of durations.
+// It returns an error if any of the values is not a positive integer.
+func parseCertificateExpiryWarningDays(input string) ([]time.Duration, error) {
+	var thresholds []time.Duration
+	for _, days := range strings.Split(input, ",") {
+		trimmedDays := strings.TrimSpace(days)
+		d, err := strconv.Atoi(trimmedDays)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid number of days %q: must be a positive integer", trimmedDays)
+		}
+		thresholds = append(thresholds, time.Duration(d)*24*time.Hour)
+	}
+	return thresholds, nil
+}
+
 // validateCIDRorIP makes sure a given string is either a valid CIDR block or IP address.
 // It an error if it is not valid.
 func validateCIDRorIP(cidr string) error {
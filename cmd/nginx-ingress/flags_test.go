@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseNginxStatusAllowCIDRs(t *testing.T) {
@@ -63,6 +64,40 @@ func TestParseNginxStatusAllowCIDRs(t *testing.T) {
 	}
 }
 
+func TestParseCertificateExpiryWarningDays(t *testing.T) {
+	badInputs := []string{"", "7,", "-1", "0", "seven", "1.5"}
+	for _, input := range badInputs {
+		_, err := parseCertificateExpiryWarningDays(input)
+		if err == nil {
+			t.Errorf("parseCertificateExpiryWarningDays(%q) returned no error when it should have returned an error", input)
+		}
+	}
+
+	goodInputs := []struct {
+		input    string
+		expected []time.Duration
+	}{
+		{
+			"7",
+			[]time.Duration{7 * 24 * time.Hour},
+		},
+		{
+			"30, 7,1",
+			[]time.Duration{30 * 24 * time.Hour, 7 * 24 * time.Hour, 24 * time.Hour},
+		},
+	}
+	for _, goodInput := range goodInputs {
+		result, err := parseCertificateExpiryWarningDays(goodInput.input)
+		if err != nil {
+			t.Errorf("parseCertificateExpiryWarningDays(%q) returned an error when it should have returned no error: %q", goodInput.input, err)
+		}
+
+		if !reflect.DeepEqual(result, goodInput.expected) {
+			t.Errorf("parseCertificateExpiryWarningDays(%q) returned %v expected %v", goodInput.input, result, goodInput.expected)
+		}
+	}
+}
+
 func TestValidateCIDRorIP(t *testing.T) {
 	badCIDRs := []string{"localhost", "thing", "~", "!!!", "", " ", "-1"}
 	for _, badCIDR := range badCIDRs {
@@ -155,6 +190,33 @@ func TestValidateNamespaces(t *testing.T) {
 	}
 }
 
+func TestValidateAuditLog(t *testing.T) {
+	badSinks := []string{
+		"stderr",
+		"File",
+		"syslog://",
+	}
+	for _, badSink := range badSinks {
+		err := validateAuditLog(badSink)
+		if err == nil {
+			t.Errorf("validateAuditLog(%v) returned no error when it should have returned an error", badSink)
+		}
+	}
+
+	goodSinks := []string{
+		"",
+		"stdout",
+		"file",
+		"syslog",
+	}
+	for _, goodSink := range goodSinks {
+		err := validateAuditLog(goodSink)
+		if err != nil {
+			t.Errorf("validateAuditLog(%v) returned an error when it should have returned no error: %v", goodSink, err)
+		}
+	}
+}
+
 func TestValidateLogFormat(t *testing.T) {
 	badLogFormats := []string{
 		"",
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
@@ -17,6 +18,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/nginx/kubernetes-ingress/internal/audit"
 	"github.com/nginx/kubernetes-ingress/internal/configs"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
@@ -129,6 +131,7 @@ func main() {
 	nginxManager, useFakeNginxManager := createNginxManager(ctx, managerCollector, licenseReporter)
 
 	nginxVersion := getNginxVersionInfo(ctx, nginxManager)
+	nginxModules := getNginxModulesInfo(ctx, nginxManager)
 
 	var appProtectVersion string
 	var appProtectV5 bool
@@ -213,6 +216,7 @@ func main() {
 		DynamicWeightChangesReload:     *enableDynamicWeightChangesReload,
 		StaticSSLPath:                  staticSSLPath,
 		NginxVersion:                   nginxVersion,
+		NginxModules:                   nginxModules,
 		AppProtectBundlePath:           appProtectBundlePath,
 	}
 
@@ -247,6 +251,7 @@ func main() {
 		IsDynamicSSLReloadEnabled:           *enableDynamicSSLReload,
 		IsDynamicWeightChangesReloadEnabled: *enableDynamicWeightChangesReload,
 		NginxVersion:                        nginxVersion,
+		AuditLogger:                         createAuditLogger(ctx),
 	})
 
 	transportServerValidator := cr_validation.NewTransportServerValidator(*enableTLSPassthrough, *enableSnippets, *nginxPlus)
@@ -255,12 +260,11 @@ func main() {
 		cr_validation.IsDosEnabled(*appProtectDos),
 		cr_validation.IsCertManagerEnabled(*enableCertManager),
 		cr_validation.IsExternalDNSEnabled(*enableExternalDNS),
+		cr_validation.IsBrotliEnabled(nginxModules.Brotli),
+		cr_validation.IsZstdEnabled(nginxModules.Zstd),
+		cr_validation.IsGeoIP2Enabled(nginxModules.GeoIP2),
 	)
 
-	if *enableServiceInsight {
-		createHealthProbeEndpoint(kubeClient, plusClient, cnf)
-	}
-
 	lbcInput := k8s.NewLoadBalancerControllerInput{
 		KubeClient:                   kubeClient,
 		ConfClient:                   confClient,
@@ -275,6 +279,7 @@ func main() {
 		DefaultServerSecret:          *defaultServerSecret,
 		AppProtectEnabled:            *appProtect,
 		AppProtectDosEnabled:         *appProtectDos,
+		IsGeoIP2Enabled:              nginxModules.GeoIP2,
 		AppProtectVersion:            appProtectVersion,
 		IsNginxPlus:                  *nginxPlus,
 		IngressClass:                 *ingressClass,
@@ -292,6 +297,10 @@ func main() {
 		AreCustomResourcesEnabled:    *enableCustomResources,
 		EnableOIDC:                   *enableOIDC,
 		MetricsCollector:             controllerCollector,
+		CertificateCollector:         createCertificateCollector(ctx, registry, constLabels),
+		CertificateExpiryThresholds:  certificateExpiryThresholds,
+		ReadyFailedReloadsThreshold:  *readyStatusFailedReloadsThreshold,
+		ReadyStalenessThreshold:      *readyStatusStalenessThreshold,
 		GlobalConfigurationValidator: globalConfigurationValidator,
 		TransportServerValidator:     transportServerValidator,
 		VirtualServerValidator:       virtualServerValidator,
@@ -311,16 +320,23 @@ func main() {
 		BuildOS:                      buildOS,
 		NICVersion:                   version,
 		DynamicWeightChangesReload:   *enableDynamicWeightChangesReload,
+		EnableRollouts:               *enableRollouts,
+		EnableReferenceGrants:        *enableReferenceGrants,
 		InstallationFlags:            parsedFlags,
 	}
 
 	lbc := k8s.NewLoadBalancerController(lbcInput)
 
+	if *enableServiceInsight {
+		createHealthProbeEndpoint(kubeClient, plusClient, lbc, cnf)
+	}
+
 	if *readyStatus {
 		go func() {
 			port := fmt.Sprintf(":%v", *readyStatusPort)
 			s := http.NewServeMux()
 			s.HandleFunc("/nginx-ready", ready(lbc))
+			s.HandleFunc("/readyz", readyz(lbc))
 			nl.Fatal(l, http.ListenAndServe(port, s)) // nolint:gosec
 		}()
 	}
@@ -575,6 +591,13 @@ func getNginxVersionInfo(ctx context.Context, nginxManager nginx.Manager) nginx.
 	return nginxInfo
 }
 
+func getNginxModulesInfo(ctx context.Context, nginxManager nginx.Manager) nginx.Modules {
+	l := nl.LoggerFromContext(ctx)
+	modules := nginxManager.Modules()
+	nl.Infof(l, "NGINX modules: brotli %v, zstd %v, geoip2 %v", modules.Brotli, modules.Zstd, modules.GeoIP2)
+	return modules
+}
+
 func getAppProtectVersionInfo(ctx context.Context) string {
 	l := nl.LoggerFromContext(ctx)
 	v, err := os.ReadFile(appProtectVersionPath)
@@ -833,7 +856,7 @@ func handleTermination(lbc *k8s.LoadBalancerController, nginxManager nginx.Manag
 
 func ready(lbc *k8s.LoadBalancerController) http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
-		if !lbc.IsNginxReady() {
+		if !lbc.GetReadinessStatus().Ready {
 			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
 			return
 		}
@@ -842,6 +865,30 @@ func ready(lbc *k8s.LoadBalancerController) http.HandlerFunc {
 	}
 }
 
+// readyz reports the readiness of the Ingress Controller along with the resources
+// that are pending or failing as JSON.
+func readyz(lbc *k8s.LoadBalancerController) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		status := lbc.GetReadinessStatus()
+		data, err := json.Marshal(status)
+		if err != nil {
+			nl.Errorf(lbc.Logger, "Error marshaling the readiness status: %v", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if !status.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		if _, err := w.Write(data); err != nil {
+			nl.Errorf(lbc.Logger, "Error writing the readiness status: %v", err)
+		}
+	}
+}
+
 func createManagerAndControllerCollectors(ctx context.Context, constLabels map[string]string) (collectors.ManagerCollector, collectors.ControllerCollector, *prometheus.Registry) {
 	l := nl.LoggerFromContext(ctx)
 	var err error
@@ -882,6 +929,18 @@ func createManagerAndControllerCollectors(ctx context.Context, constLabels map[s
 	return mc, cc, registry
 }
 
+func createCertificateCollector(ctx context.Context, registry *prometheus.Registry, constLabels map[string]string) collectors.CertificateCollector {
+	if !*enablePrometheusMetrics {
+		return collectors.NewCertificateFakeCollector()
+	}
+
+	cc := collectors.NewCertificateMetricsCollector(constLabels)
+	if err := cc.Register(registry); err != nil {
+		nl.Errorf(nl.LoggerFromContext(ctx), "Error registering Certificate Prometheus metrics: %v", err)
+	}
+	return cc
+}
+
 func createPlusAndLatencyCollectors(
 	ctx context.Context,
 	registry *prometheus.Registry,
@@ -940,7 +999,7 @@ func createPlusAndLatencyCollectors(
 	return plusCollector, syslogListener, lc
 }
 
-func createHealthProbeEndpoint(kubeClient *kubernetes.Clientset, plusClient *client.NginxClient, cnf *configs.Configurator) {
+func createHealthProbeEndpoint(kubeClient *kubernetes.Clientset, plusClient *client.NginxClient, lbc *k8s.LoadBalancerController, cnf *configs.Configurator) {
 	l := nl.LoggerFromContext(cnf.CfgParams.Context)
 	if !*enableServiceInsight {
 		return
@@ -954,7 +1013,33 @@ func createHealthProbeEndpoint(kubeClient *kubernetes.Clientset, plusClient *cli
 			nl.Fatalf(l, "Error trying to get the service insight TLS secret %v: %v", *serviceInsightTLSSecretName, err)
 		}
 	}
-	go healthcheck.RunHealthCheck(*serviceInsightListenPort, plusClient, cnf, serviceInsightSecret)
+	go healthcheck.RunHealthCheck(*serviceInsightListenPort, plusClient, lbc.GetUpstreamPeers, cnf, serviceInsightSecret)
+}
+
+func createAuditLogger(ctx context.Context) *audit.Logger {
+	l := nl.LoggerFromContext(ctx)
+	if *auditLog == "" {
+		return nil
+	}
+	sink, err := audit.NewSink(audit.SinkParams{
+		Sink:           *auditLog,
+		FilePath:       *auditLogFile,
+		FileMaxSize:    int64(*auditLogFileMaxSize) * 1024 * 1024,
+		FileMaxBackups: *auditLogFileMaxBackups,
+		SyslogAddress:  *auditLogSyslogAddress,
+	})
+	if err != nil {
+		nl.Fatalf(l, "Error creating the audit log: %v", err)
+	}
+
+	var lastHash string
+	if *auditLog == audit.SinkFile {
+		lastHash, err = audit.ReadLastHash(*auditLogFile)
+		if err != nil {
+			nl.Errorf(l, "Error reading the last audit record, starting a new chain of records: %v", err)
+		}
+	}
+	return audit.NewLogger(sink, lastHash)
 }
 
 // mustProcessGlobalConfiguration calls internally os.Exit
@@ -0,0 +1,214 @@
+// Package audit provides a tamper-evident log of the changes applied to the NGINX configuration.
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Operations of the changes of resources.
+const (
+	OperationAddOrUpdate = "AddOrUpdate"
+	OperationDelete      = "Delete"
+)
+
+// Outcomes of the reload of NGINX after a change.
+const (
+	// ReloadSucceeded means NGINX was reloaded successfully.
+	ReloadSucceeded = "succeeded"
+	// ReloadFailed means the reload of NGINX failed.
+	ReloadFailed = "failed"
+	// ReloadDeferred means reloads were disabled, for example during a batch of changes,
+	// and the change will be applied by a later reload.
+	ReloadDeferred = "deferred"
+	// ReloadSkipped means NGINX was not reloaded because the configuration didn't change.
+	ReloadSkipped = "skipped"
+)
+
+// File describes a configuration file written or deleted for a change.
+type File struct {
+	Name string `json:"name"`
+	// SHA256 is the hash of the content of the file. It is empty for deleted files.
+	SHA256  string `json:"sha256,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// NewFile creates a File for a configuration file with the given content.
+func NewFile(name string, content []byte) File {
+	sum := sha256.Sum256(content)
+	return File{
+		Name:   name,
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+}
+
+// NewDeletedFile creates a File for a deleted configuration file.
+func NewDeletedFile(name string) File {
+	return File{
+		Name:    name,
+		Deleted: true,
+	}
+}
+
+// Record is an audit record of a change of a resource applied to the NGINX configuration.
+//
+// Records are chained: each record holds the hash of the previous record, and its own hash
+// is computed over its content, including the hash of the previous record.
+// Removing or modifying a record breaks the chain.
+type Record struct {
+	Time            time.Time `json:"time"`
+	Kind            string    `json:"kind"`
+	Key             string    `json:"key"`
+	ResourceVersion string    `json:"resourceVersion"`
+	Operation       string    `json:"operation"`
+	Files           []File    `json:"files"`
+	Reload          string    `json:"reload"`
+	Error           string    `json:"error,omitempty"`
+	PreviousHash    string    `json:"previousHash"`
+	Hash            string    `json:"hash"`
+}
+
+// computeHash returns the hash of the record computed over all its fields except the hash itself.
+func (r Record) computeHash() (string, error) {
+	r.Hash = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Logger writes audit records as JSON lines to a sink.
+type Logger struct {
+	sink     io.Writer
+	lastHash string
+	now      func() time.Time
+	lock     sync.Mutex
+}
+
+// NewLogger creates a Logger that writes to the given sink. The first record is chained to the record
+// with the given hash, which is empty for a new audit log.
+func NewLogger(sink io.Writer, lastHash string) *Logger {
+	return &Logger{
+		sink:     sink,
+		lastHash: lastHash,
+		now:      time.Now,
+	}
+}
+
+// ReadLastHash returns the hash of the last record of the audit log file at the given path, or of its most
+// recent rotated file when the file is empty, so that the records written after a restart continue the chain.
+// It returns an empty string when there are no records.
+func ReadLastHash(path string) (string, error) {
+	for _, name := range []string{path, path + ".1"} {
+		line, err := readLastLine(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read the audit log file %s: %w", name, err)
+		}
+		if len(line) == 0 {
+			continue
+		}
+
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			return "", fmt.Errorf("failed to parse the last audit record of %s: %w", name, err)
+		}
+		return r.Hash, nil
+	}
+	return "", nil
+}
+
+// readLastLine returns the last non-empty line of a file. The file is read backwards in chunks,
+// so that only the tail of a large file is read.
+func readLastLine(name string) ([]byte, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 4096
+	var tail []byte
+	for end := info.Size(); end > 0; {
+		start := end - chunkSize
+		if start < 0 {
+			start = 0
+		}
+		chunk := make([]byte, end-start)
+		if _, err := file.ReadAt(chunk, start); err != nil {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+
+		trimmed := bytes.TrimRight(tail, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+		end = start
+	}
+	return bytes.TrimRight(tail, "\n"), nil
+}
+
+// Log sets the time and the hashes of the record and writes it to the sink.
+func (l *Logger) Log(r Record) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if r.Files == nil {
+		r.Files = []File{}
+	}
+	r.Time = l.now().UTC()
+	r.PreviousHash = l.lastHash
+
+	hash, err := r.computeHash()
+	if err != nil {
+		return fmt.Errorf("failed to compute the hash of the audit record: %w", err)
+	}
+	r.Hash = hash
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the audit record: %w", err)
+	}
+
+	if _, err := l.sink.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write the audit record: %w", err)
+	}
+
+	l.lastHash = hash
+	return nil
+}
+
+// Verify checks that the records form an unbroken chain and that none of them was modified.
+// It returns the index of the first invalid record or -1 if all records are valid.
+func Verify(records []Record) int {
+	previousHash := ""
+	for i, r := range records {
+		if i > 0 && r.PreviousHash != previousHash {
+			return i
+		}
+		hash, err := r.computeHash()
+		if err != nil || hash != r.Hash {
+			return i
+		}
+		previousHash = r.Hash
+	}
+	return -1
+}
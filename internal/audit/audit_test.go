@@ -0,0 +1,217 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerChainsRecords(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, "")
+	logger.now = func() time.Time { return time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC) }
+
+	inputs := []Record{
+		{
+			Kind:            "VirtualServer",
+			Key:             "default/cafe",
+			ResourceVersion: "1",
+			Operation:       OperationAddOrUpdate,
+			Files:           []File{NewFile("conf.d/vs_default_cafe.conf", []byte("server {}"))},
+			Reload:          ReloadSucceeded,
+		},
+		{
+			Kind:            "VirtualServer",
+			Key:             "default/cafe",
+			ResourceVersion: "2",
+			Operation:       OperationDelete,
+			Files:           []File{NewDeletedFile("conf.d/vs_default_cafe.conf")},
+			Reload:          ReloadFailed,
+			Error:           "reload failed",
+		},
+	}
+	for _, r := range inputs {
+		if err := logger.Log(r); err != nil {
+			t.Fatalf("Log() returned unexpected error: %v", err)
+		}
+	}
+
+	var records []Record
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("failed to unmarshal the audit record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, r)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Log() wrote %d records, expected 2", len(records))
+	}
+	if records[0].PreviousHash != "" {
+		t.Errorf("the first record has the previous hash %q, expected none", records[0].PreviousHash)
+	}
+	if records[1].PreviousHash != records[0].Hash {
+		t.Errorf("the second record has the previous hash %q, expected %q", records[1].PreviousHash, records[0].Hash)
+	}
+	if idx := Verify(records); idx != -1 {
+		t.Errorf("Verify() returned %d for a valid chain of records, expected -1", idx)
+	}
+
+	records[0].ResourceVersion = "3"
+	if idx := Verify(records); idx != 0 {
+		t.Errorf("Verify() returned %d for a modified record, expected 0", idx)
+	}
+
+	if idx := Verify(records[1:]); idx != -1 {
+		t.Errorf("Verify() returned %d for a chain of a single record, expected -1", idx)
+	}
+}
+
+func TestNewFile(t *testing.T) {
+	t.Parallel()
+	f := NewFile("conf.d/vs_default_cafe.conf", []byte(""))
+	expected := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if f.SHA256 != expected {
+		t.Errorf("NewFile() returned the hash %q, expected %q", f.SHA256, expected)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+
+	rf, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("NewRotatingFile() returned unexpected error: %v", err)
+	}
+	defer rf.Close() //nolint:errcheck
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := rf.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() returned unexpected error: %v", err)
+		}
+	}
+
+	expected := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for name, content := range expected {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if string(data) != content {
+			t.Errorf("%s holds %q, expected %q", name, data, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%s.3 exists, expected only 2 backups", path)
+	}
+}
+
+func TestReadLastHash(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+
+	hash, err := ReadLastHash(path)
+	if err != nil || hash != "" {
+		t.Errorf("ReadLastHash() returned (%q, %v) for a missing file, expected no hash", hash, err)
+	}
+
+	// the records are larger than the chunks the file is read in
+	padding := strings.Repeat("a", 5000)
+	var content strings.Builder
+	for _, h := range []string{"first", "second"} {
+		data, err := json.Marshal(Record{Key: padding, Hash: h})
+		if err != nil {
+			t.Fatal(err)
+		}
+		content.Write(append(data, '\n'))
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err = ReadLastHash(path)
+	if err != nil || hash != "second" {
+		t.Errorf("ReadLastHash() returned (%q, %v), expected %q", hash, err, "second")
+	}
+
+	// right after a rotation, the last record is in the rotated file
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err = ReadLastHash(path)
+	if err != nil || hash != "second" {
+		t.Errorf("ReadLastHash() returned (%q, %v) after a rotation, expected %q", hash, err, "second")
+	}
+
+	if err := os.WriteFile(path, []byte("{\"hash\":"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadLastHash(path); err == nil {
+		t.Error("ReadLastHash() returned no error for a truncated record")
+	}
+}
+
+func TestLoggerContinuesChain(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, "previous")
+
+	if err := logger.Log(Record{Kind: "VirtualServer", Key: "default/cafe"}); err != nil {
+		t.Fatalf("Log() returned unexpected error: %v", err)
+	}
+
+	var r Record
+	if err := json.Unmarshal(buf.Bytes(), &r); err != nil {
+		t.Fatalf("failed to unmarshal the audit record %q: %v", buf.String(), err)
+	}
+	if r.PreviousHash != "previous" {
+		t.Errorf("the first record has the previous hash %q, expected %q", r.PreviousHash, "previous")
+	}
+}
+
+func TestParseSyslogAddress(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		address         string
+		expectedNetwork string
+		expectedAddr    string
+	}{
+		{address: "", expectedNetwork: "", expectedAddr: ""},
+		{address: "udp://syslog:514", expectedNetwork: "udp", expectedAddr: "syslog:514"},
+		{address: "unix:///dev/log", expectedNetwork: "unix", expectedAddr: "/dev/log"},
+	}
+	for _, test := range tests {
+		network, addr, err := parseSyslogAddress(test.address)
+		if err != nil {
+			t.Errorf("parseSyslogAddress(%q) returned unexpected error: %v", test.address, err)
+		}
+		if network != test.expectedNetwork || addr != test.expectedAddr {
+			t.Errorf("parseSyslogAddress(%q) returned (%q, %q), expected (%q, %q)", test.address, network, addr, test.expectedNetwork, test.expectedAddr)
+		}
+	}
+
+	for _, address := range []string{"http://syslog:514", "udp://"} {
+		_, _, err := parseSyslogAddress(address)
+		if err == nil || !strings.Contains(err.Error(), "invalid syslog address") {
+			t.Errorf("parseSyslogAddress(%q) returned error %v, expected an invalid syslog address error", address, err)
+		}
+	}
+}
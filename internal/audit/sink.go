@@ -0,0 +1,178 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Sinks of the audit log.
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+)
+
+const syslogTag = "nginx-ingress-audit"
+
+// SinkParams configures the sink of the audit log.
+type SinkParams struct {
+	// Sink is one of stdout, file or syslog.
+	Sink string
+	// FilePath is the path of the audit log file.
+	FilePath string
+	// FileMaxSize is the size in bytes of the audit log file that triggers the rotation.
+	FileMaxSize int64
+	// FileMaxBackups is the number of rotated audit log files to keep.
+	FileMaxBackups int
+	// SyslogAddress is the address of the syslog server, for example udp://syslog:514.
+	// The local syslog server is used when it is empty.
+	SyslogAddress string
+}
+
+// NewSink creates the sink of the audit log.
+func NewSink(p SinkParams) (io.WriteCloser, error) {
+	switch p.Sink {
+	case SinkStdout:
+		return nopCloser{os.Stdout}, nil
+	case SinkFile:
+		return NewRotatingFile(p.FilePath, p.FileMaxSize, p.FileMaxBackups)
+	case SinkSyslog:
+		network, addr, err := parseSyslogAddress(p.SyslogAddress)
+		if err != nil {
+			return nil, err
+		}
+		return syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_AUTH, syslogTag)
+	}
+	return nil, fmt.Errorf("unknown audit log sink %q: must be one of %s, %s or %s", p.Sink, SinkStdout, SinkFile, SinkSyslog)
+}
+
+func parseSyslogAddress(address string) (network string, addr string, err error) {
+	if address == "" {
+		return "", "", nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid syslog address %q: %w", address, err)
+	}
+	switch u.Scheme {
+	case "udp", "tcp":
+		if u.Host == "" {
+			return "", "", fmt.Errorf("invalid syslog address %q: missing host", address)
+		}
+		return u.Scheme, u.Host, nil
+	case "unix", "unixgram":
+		return u.Scheme, u.Path, nil
+	}
+	return "", "", fmt.Errorf("invalid syslog address %q: the scheme must be one of udp, tcp, unix or unixgram", address)
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// RotatingFile is a file that is rotated once its size exceeds a maximum size.
+// The rotated files get the suffixes .1, .2 and so on, .1 being the most recent.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	lock       sync.Mutex
+}
+
+// NewRotatingFile opens or creates the file at the given path for appending.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the path of the audit log file must not be empty")
+	}
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("the maximum size of the audit log file must be positive")
+	}
+
+	rf := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(rf.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create the directory of the audit log file: %w", err)
+	}
+
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open the audit log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close() //nolint:errcheck
+		return fmt.Errorf("failed to stat the audit log file: %w", err)
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	return nil
+}
+
+// Write writes the data to the file. It rotates the file before writing if the data doesn't fit.
+func (rf *RotatingFile) Write(data []byte) (int, error) {
+	rf.lock.Lock()
+	defer rf.lock.Unlock()
+
+	if rf.size > 0 && rf.size+int64(len(data)) > rf.maxSize {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(data)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return fmt.Errorf("failed to close the audit log file: %w", err)
+	}
+
+	if rf.maxBackups > 0 {
+		for i := rf.maxBackups - 1; i > 0; i-- {
+			from := fmt.Sprintf("%s.%d", rf.path, i)
+			if _, err := os.Stat(from); err == nil {
+				if err := os.Rename(from, fmt.Sprintf("%s.%d", rf.path, i+1)); err != nil {
+					return fmt.Errorf("failed to rotate the audit log file: %w", err)
+				}
+			}
+		}
+		if err := os.Rename(rf.path, rf.path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate the audit log file: %w", err)
+		}
+	} else if err := os.Remove(rf.path); err != nil {
+		return fmt.Errorf("failed to rotate the audit log file: %w", err)
+	}
+
+	return rf.open()
+}
+
+// Close closes the file.
+func (rf *RotatingFile) Close() error {
+	rf.lock.Lock()
+	defer rf.lock.Unlock()
+
+	return rf.file.Close()
+}
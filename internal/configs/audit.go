@@ -0,0 +1,176 @@
+package configs
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/nginx/kubernetes-ingress/internal/audit"
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kinds of the resources in the audit records.
+const (
+	ingressKind         = "Ingress"
+	virtualServerKind   = "VirtualServer"
+	transportServerKind = "TransportServer"
+)
+
+const mainConfigFile = "nginx.conf"
+
+// auditChange collects the configuration files written and the outcome of the reload of NGINX
+// for the audit record of a change of a resource.
+type auditChange struct {
+	files  []audit.File
+	reload string
+	// changedOnly means only the files whose content changed are collected.
+	changedOnly bool
+}
+
+// BeginAuditRecord starts collecting the configuration files written and the outcome of the reload of NGINX
+// for the audit record of a change of a resource. It does nothing when the audit log is disabled.
+func (cnf *Configurator) BeginAuditRecord() {
+	if cnf.auditLogger == nil {
+		return
+	}
+	cnf.auditChange = &auditChange{reload: audit.ReloadSkipped}
+}
+
+// BeginAuditRecordOfChangedFiles is like BeginAuditRecord, but only collects the configuration files whose
+// content changed. It is used for the changes that regenerate the whole configuration, such as an update of the ConfigMap.
+func (cnf *Configurator) BeginAuditRecordOfChangedFiles() {
+	if cnf.auditLogger == nil {
+		return
+	}
+	cnf.auditChange = &auditChange{reload: audit.ReloadSkipped, changedOnly: true}
+}
+
+// WriteAuditRecord writes the audit record of a change of a resource with the configuration files
+// and the outcome of the reload collected since BeginAuditRecord was called.
+func (cnf *Configurator) WriteAuditRecord(kind string, meta *meta_v1.ObjectMeta, operation string, err error) {
+	change := cnf.endAuditChange()
+	if change == nil {
+		return
+	}
+	cnf.logAuditRecord(kind, meta, operation, change.files, change.reload, err)
+}
+
+func (cnf *Configurator) endAuditChange() *auditChange {
+	change := cnf.auditChange
+	cnf.auditChange = nil
+	return change
+}
+
+func (cnf *Configurator) logAuditRecord(kind string, meta *meta_v1.ObjectMeta, operation string, files []audit.File, reload string, err error) {
+	r := audit.Record{
+		Kind:            kind,
+		Key:             fmt.Sprintf("%s/%s", meta.Namespace, meta.Name),
+		ResourceVersion: meta.ResourceVersion,
+		Operation:       operation,
+		Files:           files,
+		Reload:          reload,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	if logErr := cnf.auditLogger.Log(r); logErr != nil {
+		nl.Errorf(nl.LoggerFromContext(cnf.CfgParams.Context), "Error writing the audit record for %s %s: %v", kind, r.Key, logErr)
+	}
+}
+
+func (cnf *Configurator) auditReload(err error) {
+	if cnf.auditChange == nil {
+		return
+	}
+	switch {
+	case !cnf.isReloadsEnabled:
+		cnf.auditChange.reload = audit.ReloadDeferred
+	case err != nil:
+		cnf.auditChange.reload = audit.ReloadFailed
+	default:
+		cnf.auditChange.reload = audit.ReloadSucceeded
+	}
+}
+
+func (cnf *Configurator) auditFile(f audit.File, changed bool) {
+	if cnf.auditChange == nil || (cnf.auditChange.changedOnly && !changed) {
+		return
+	}
+	cnf.auditChange.files = append(cnf.auditChange.files, f)
+}
+
+// createMainConfig writes the main configuration file and records it for the audit log.
+func (cnf *Configurator) createMainConfig(content []byte) bool {
+	changed := cnf.nginxManager.CreateMainConfig(content)
+	cnf.auditFile(audit.NewFile(mainConfigFile, content), changed)
+	return changed
+}
+
+// createConfig writes the configuration file of a resource and records it for the audit log.
+func (cnf *Configurator) createConfig(name string, content []byte) bool {
+	changed := cnf.nginxManager.CreateConfig(name, content)
+	cnf.auditFile(audit.NewFile(path.Join("conf.d", name+".conf"), content), changed)
+	return changed
+}
+
+// deleteConfig deletes the configuration file of a resource and records it for the audit log.
+func (cnf *Configurator) deleteConfig(name string) {
+	cnf.auditFile(audit.NewDeletedFile(path.Join("conf.d", name+".conf")), true)
+	cnf.nginxManager.DeleteConfig(name)
+}
+
+// createStreamConfig writes the stream configuration file of a resource and records it for the audit log.
+func (cnf *Configurator) createStreamConfig(name string, content []byte) bool {
+	changed := cnf.nginxManager.CreateStreamConfig(name, content)
+	cnf.auditFile(audit.NewFile(path.Join("stream-conf.d", name+".conf"), content), changed)
+	return changed
+}
+
+// deleteStreamConfig deletes the stream configuration file of a resource and records it for the audit log.
+func (cnf *Configurator) deleteStreamConfig(name string) {
+	cnf.auditFile(audit.NewDeletedFile(path.Join("stream-conf.d", name+".conf")), true)
+	cnf.nginxManager.DeleteStreamConfig(name)
+}
+
+// auditBatch collects the audit records of the resources updated together with a single reload of NGINX.
+type auditBatch struct {
+	records []auditBatchRecord
+}
+
+type auditBatchRecord struct {
+	kind  string
+	meta  *meta_v1.ObjectMeta
+	files []audit.File
+}
+
+// auditUpdate runs the update of a resource of the batch and collects the configuration files it writes.
+// The audit record of a failed update is written right away.
+func (cnf *Configurator) auditUpdate(batch *auditBatch, kind string, meta *meta_v1.ObjectMeta, update func() error) error {
+	cnf.BeginAuditRecord()
+	err := update()
+	change := cnf.endAuditChange()
+	if change == nil {
+		return err
+	}
+	if err != nil {
+		cnf.logAuditRecord(kind, meta, audit.OperationAddOrUpdate, change.files, change.reload, err)
+		return err
+	}
+	batch.records = append(batch.records, auditBatchRecord{kind: kind, meta: meta, files: change.files})
+	return nil
+}
+
+// auditBatchReload runs the reload of NGINX for the batch and writes the audit records of its resources.
+func (cnf *Configurator) auditBatchReload(batch *auditBatch, reload func() error) error {
+	cnf.BeginAuditRecord()
+	err := reload()
+	change := cnf.endAuditChange()
+	if change == nil {
+		return err
+	}
+	for _, r := range batch.records {
+		cnf.logAuditRecord(r.kind, r.meta, audit.OperationAddOrUpdate, r.files, change.reload, err)
+	}
+	return err
+}
@@ -45,6 +45,8 @@ type ConfigParams struct {
 	MainStreamSnippets                     []string
 	MainMapHashBucketSize                  string
 	MainMapHashMaxSize                     string
+	MainGeoIP2CountryDatabase              string
+	MainGeoIP2ASNDatabase                  string
 	MainWorkerConnections                  string
 	MainWorkerCPUAffinity                  string
 	MainWorkerProcesses                    string
@@ -161,6 +163,7 @@ type StaticConfigParams struct {
 	StaticSSLPath                  string
 	DynamicWeightChangesReload     bool
 	NginxVersion                   nginx.Version
+	NginxModules                   nginx.Modules
 	AppProtectBundlePath           string
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -20,6 +21,9 @@ const (
 	minimumInterval = 60
 )
 
+// geoIP2DatabaseRegexp matches the absolute paths of the MaxMind databases that are safe to use in the config.
+var geoIP2DatabaseRegexp = regexp.MustCompile(`^/[^\s;{}"'$\\]*$`)
+
 // ParseConfigMap parses ConfigMap into ConfigParams.
 //
 //nolint:gocyclo
@@ -257,6 +261,25 @@ func ParseConfigMap(ctx context.Context, cfgm *v1.ConfigMap, nginxPlus bool, has
 		cfgParams.MainErrorLogLevel = errorLogLevel
 	}
 
+	for _, db := range []struct {
+		key   string
+		value *string
+	}{
+		{key: "geoip2-country-database", value: &cfgParams.MainGeoIP2CountryDatabase},
+		{key: "geoip2-asn-database", value: &cfgParams.MainGeoIP2ASNDatabase},
+	} {
+		if value, exists := cfgm.Data[db.key]; exists {
+			if !geoIP2DatabaseRegexp.MatchString(value) {
+				errorText := fmt.Sprintf("ConfigMap %s/%s: invalid value for '%s': %q, must be an absolute path, ignoring", cfgm.GetNamespace(), cfgm.GetName(), db.key, value)
+				nl.Warn(l, errorText)
+				eventLog.Event(cfgm, v1.EventTypeWarning, nl.EventReasonInvalidValue, errorText)
+				configOk = false
+			} else {
+				*db.value = value
+			}
+		}
+	}
+
 	if accessLog, exists := cfgm.Data["access-log"]; exists {
 		if !strings.HasPrefix(accessLog, "syslog:") {
 			errorText := fmt.Sprintf("ConfigMap %s/%s: invalid value for 'access-log': %q, ignoring", cfgm.GetNamespace(), cfgm.GetName(), accessLog)
@@ -903,6 +926,10 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 		DynamicSSLReloadEnabled:            staticCfgParams.DynamicSSLReload,
 		StaticSSLPath:                      staticCfgParams.StaticSSLPath,
 		NginxVersion:                       staticCfgParams.NginxVersion,
+		LoadModules:                        staticCfgParams.NginxModules.LoadModules,
+		GeoIP2:                             staticCfgParams.NginxModules.GeoIP2,
+		GeoIP2CountryDatabase:              config.MainGeoIP2CountryDatabase,
+		GeoIP2ASNDatabase:                  config.MainGeoIP2ASNDatabase,
 	}
 	return nginxCfg
 }
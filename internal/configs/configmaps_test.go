@@ -289,6 +289,57 @@ func TestParseConfigMapAccessLogDefault(t *testing.T) {
 	}
 }
 
+func TestParseConfigMapGeoIP2Databases(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		countryDatabase string
+		asnDatabase     string
+		wantCountry     string
+		wantASN         string
+		wantOk          bool
+		msg             string
+	}{
+		{
+			countryDatabase: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+			asnDatabase:     "/etc/nginx/geoip/GeoLite2-ASN.mmdb",
+			wantCountry:     "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+			wantASN:         "/etc/nginx/geoip/GeoLite2-ASN.mmdb",
+			wantOk:          true,
+			msg:             "valid databases",
+		},
+		{
+			countryDatabase: "geoip/GeoLite2-Country.mmdb",
+			asnDatabase:     "/etc/nginx/geoip/GeoLite2-ASN.mmdb; return 200",
+			wantOk:          false,
+			msg:             "invalid databases",
+		},
+	}
+	nginxPlus := false
+	hasAppProtect := false
+	hasAppProtectDos := false
+	hasTLSPassthrough := false
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			cm := &v1.ConfigMap{
+				Data: map[string]string{
+					"geoip2-country-database": test.countryDatabase,
+					"geoip2-asn-database":     test.asnDatabase,
+				},
+			}
+			result, configOk := ParseConfigMap(context.Background(), cm, nginxPlus, hasAppProtect, hasAppProtectDos, hasTLSPassthrough, makeEventLogger())
+			if configOk != test.wantOk {
+				t.Errorf("want configOk %t, got %t", test.wantOk, configOk)
+			}
+			if result.MainGeoIP2CountryDatabase != test.wantCountry {
+				t.Errorf("want %q, got %q", test.wantCountry, result.MainGeoIP2CountryDatabase)
+			}
+			if result.MainGeoIP2ASNDatabase != test.wantASN {
+				t.Errorf("want %q, got %q", test.wantASN, result.MainGeoIP2ASNDatabase)
+			}
+		})
+	}
+}
+
 func TestParseMGMTConfigMapError(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
 
 	"github.com/nginx/kubernetes-ingress/pkg/apis/dos/v1beta1"
 
+	"github.com/nginx/kubernetes-ingress/internal/audit"
 	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
 	"github.com/nginx/nginx-prometheus-exporter/collector"
 	"github.com/spiffe/go-spiffe/v2/workloadapi"
@@ -145,6 +148,34 @@ type Configurator struct {
 	isReloadsEnabled          bool
 	isDynamicSSLReloadEnabled bool
 	ingressControllerReplicas int
+	reloadStatus              ReloadStatus
+	reloadStatusLock          sync.RWMutex
+	auditLogger               *audit.Logger
+	auditChange               *auditChange
+	// splitClientsOverrides holds the values of the keyvals of split clients set by Rollouts, by zone name.
+	// They take precedence over the weights of the splits of the VirtualServers.
+	splitClientsOverrides map[string]WeightUpdate
+	// now returns the current time. The configuration of a resource reports the certificates that expired at that time.
+	now func() time.Time
+}
+
+// maxRecentReloadFailures is the number of the most recent reload failures kept by the Configurator.
+const maxRecentReloadFailures = 10
+
+// ReloadFailure describes a failed reload of NGINX.
+type ReloadFailure struct {
+	Time  time.Time `json:"time"`
+	Error string    `json:"error"`
+}
+
+// ReloadStatus describes the outcome of the recent reloads of NGINX.
+type ReloadStatus struct {
+	// ConsecutiveFailures is the number of reloads that failed since the last successful reload.
+	ConsecutiveFailures int
+	// LastSuccess is the time of the last successful reload. It is zero if NGINX was never reloaded successfully.
+	LastSuccess time.Time
+	// RecentFailures holds the most recent reload failures, the latest failure last.
+	RecentFailures []ReloadFailure
 }
 
 // ConfiguratorParams is a collection of parameters used for the
@@ -165,6 +196,7 @@ type ConfiguratorParams struct {
 	IsDynamicSSLReloadEnabled           bool
 	IsDynamicWeightChangesReloadEnabled bool
 	NginxVersion                        nginx.Version
+	AuditLogger                         *audit.Logger
 }
 
 // NewConfigurator creates a new Configurator.
@@ -203,6 +235,9 @@ func NewConfigurator(p ConfiguratorParams) *Configurator {
 		isLatencyMetricsEnabled:   p.IsLatencyMetricsEnabled,
 		isDynamicSSLReloadEnabled: p.IsDynamicSSLReloadEnabled,
 		isReloadsEnabled:          false,
+		auditLogger:               p.AuditLogger,
+		splitClientsOverrides:     make(map[string]WeightUpdate),
+		now:                       time.Now,
 	}
 	return &cnf
 }
@@ -361,6 +396,97 @@ func (cnf *Configurator) StreamUpstreamsForName(name string) []string {
 	return nil
 }
 
+// UpstreamService describes an upstream generated for a VirtualServer, a VirtualServerRoute
+// or a TransportServer and the Service of the upstream.
+type UpstreamService struct {
+	Name         string
+	Service      string
+	Port         uint16
+	Subselector  map[string]string
+	Namespace    string
+	ResourceType string
+	ResourceName string
+}
+
+// UpstreamServicesForHost takes a hostname and returns the upstreams of the VirtualServer
+// for the given hostname along with their Services.
+func (cnf *Configurator) UpstreamServicesForHost(hostname string) []UpstreamService {
+	vs := cnf.virtualServerForHost(hostname)
+	if vs == nil {
+		return nil
+	}
+	return upstreamServicesForVirtualServer(vs)
+}
+
+// UpstreamServicesForVirtualServer takes the namespace and the name of a VirtualServer and returns
+// the upstreams of the VirtualServer and of its VirtualServerRoutes along with their Services.
+func (cnf *Configurator) UpstreamServicesForVirtualServer(namespace string, name string) []UpstreamService {
+	for _, vsEx := range cnf.virtualServers {
+		vs := vsEx.VirtualServer
+		if vs.Namespace != namespace || vs.Name != name {
+			continue
+		}
+
+		upstreams := upstreamServicesForVirtualServer(vs)
+		for _, vsr := range vsEx.VirtualServerRoutes {
+			namer := NewUpstreamNamerForVirtualServerRoute(vs, vsr)
+			for _, u := range vsr.Spec.Upstreams {
+				upstreams = append(upstreams, UpstreamService{
+					Name:         namer.GetNameForUpstream(u.Name),
+					Service:      u.Service,
+					Port:         u.Port,
+					Subselector:  u.Subselector,
+					Namespace:    vsr.Namespace,
+					ResourceType: "virtualserverroute",
+					ResourceName: vsr.Name,
+				})
+			}
+		}
+		return upstreams
+	}
+	return nil
+}
+
+func upstreamServicesForVirtualServer(vs *conf_v1.VirtualServer) []UpstreamService {
+	upstreams := make([]UpstreamService, 0, len(vs.Spec.Upstreams))
+	namer := NewUpstreamNamerForVirtualServer(vs)
+	for _, u := range vs.Spec.Upstreams {
+		upstreams = append(upstreams, UpstreamService{
+			Name:         namer.GetNameForUpstream(u.Name),
+			Service:      u.Service,
+			Port:         u.Port,
+			Subselector:  u.Subselector,
+			Namespace:    vs.Namespace,
+			ResourceType: "virtualserver",
+			ResourceName: vs.Name,
+		})
+	}
+	return upstreams
+}
+
+// StreamUpstreamServicesForName takes a TransportServer action name and returns
+// the stream upstreams of the TransportServer along with their Services.
+func (cnf *Configurator) StreamUpstreamServicesForName(name string) []UpstreamService {
+	ts := cnf.transportServerForActionName(name)
+	if ts == nil {
+		return nil
+	}
+
+	upstreams := make([]UpstreamService, 0, len(ts.Spec.Upstreams))
+	namer := newUpstreamNamerForTransportServer(ts)
+	for _, u := range ts.Spec.Upstreams {
+		upstreams = append(upstreams, UpstreamService{
+			Name:         namer.GetNameForUpstream(u.Name),
+			Service:      u.Service,
+			Port:         uint16(u.Port),
+			Namespace:    ts.Namespace,
+			ResourceType: "transportserver",
+			ResourceName: ts.Name,
+		})
+	}
+	return upstreams
+}
+
 // transportServerForActionName takes an action name and returns
 // Transport Server obj associated with that name.
 func (cnf *Configurator) transportServerForActionName(name string) *conf_v1.TransportServer {
@@ -416,6 +542,7 @@ func (cnf *Configurator) addOrUpdateIngress(ingEx *IngressEx) (bool, Warnings, e
 		isResolverConfigured:      cnf.IsResolverConfigured(),
 		isWildcardEnabled:         cnf.isWildcardEnabled,
 		ingressControllerReplicas: cnf.ingressControllerReplicas,
+		now:                       cnf.now(),
 	})
 
 	name := objectMetaToFileName(&ingEx.Ingress.ObjectMeta)
@@ -423,7 +550,7 @@ func (cnf *Configurator) addOrUpdateIngress(ingEx *IngressEx) (bool, Warnings, e
 	if err != nil {
 		return false, warnings, fmt.Errorf("error generating Ingress Config %v: %w", name, err)
 	}
-	configChanged := cnf.nginxManager.CreateConfig(name, content)
+	configChanged := cnf.createConfig(name, content)
 
 	cnf.ingresses[name] = ingEx
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
@@ -479,6 +606,7 @@ func (cnf *Configurator) addOrUpdateMergeableIngress(mergeableIngs *MergeableIng
 		staticParams:              cnf.staticCfgParams,
 		isWildcardEnabled:         cnf.isWildcardEnabled,
 		ingressControllerReplicas: cnf.ingressControllerReplicas,
+		now:                       cnf.now(),
 	})
 
 	name := objectMetaToFileName(&mergeableIngs.Master.Ingress.ObjectMeta)
@@ -486,7 +614,7 @@ func (cnf *Configurator) addOrUpdateMergeableIngress(mergeableIngs *MergeableIng
 	if err != nil {
 		return false, warnings, fmt.Errorf("error generating Ingress Config %v: %w", name, err)
 	}
-	changed := cnf.nginxManager.CreateConfig(name, content)
+	changed := cnf.createConfig(name, content)
 
 	cnf.ingresses[name] = mergeableIngs.Master
 	cnf.minions[name] = make(map[string]bool)
@@ -625,12 +753,13 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 
 	vsc := newVirtualServerConfigurator(cnf.CfgParams, cnf.isPlus, cnf.IsResolverConfigured(), cnf.staticCfgParams, cnf.isWildcardEnabled, nil)
 	vsc.IngressControllerReplicas = cnf.ingressControllerReplicas
+	vsc.now = cnf.now()
 	vsCfg, warnings := vsc.GenerateVirtualServerConfig(virtualServerEx, apResources, dosResources)
 	content, err := cnf.templateExecutorV2.ExecuteVirtualServerTemplate(&vsCfg)
 	if err != nil {
 		return false, warnings, weightUpdates, fmt.Errorf("error generating VirtualServer config: %v: %w", name, err)
 	}
-	changed := cnf.nginxManager.CreateConfig(name, content)
+	changed := cnf.createConfig(name, content)
 
 	cnf.virtualServers[name] = virtualServerEx
 
@@ -645,17 +774,36 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 			}
 			variableNamer := *NewVSVariableNamer(virtualServerEx.VirtualServer)
 			value := variableNamer.GetNameOfKeyOfMapForWeights(splitClient.SplitClientsIndex, splitClient.Weights[0], splitClient.Weights[1])
+			if override, exists := cnf.splitClientsOverrides[splitClient.ZoneName]; exists {
+				value = override.Value
+			}
 			weightUpdates = append(weightUpdates, WeightUpdate{Zone: splitClient.ZoneName, Key: splitClient.Key, Value: value})
 		}
 	}
 	return changed, warnings, weightUpdates, nil
 }
 
-// AddOrUpdateVirtualServers adds or updates NGINX configuration for multiple VirtualServer resources.
-func (cnf *Configurator) AddOrUpdateVirtualServers(virtualServerExes []*VirtualServerEx) (Warnings, error) {
+// AddOrUpdateResourcesThatUsePolicy adds or updates NGINX configuration for the Ingress and VirtualServer resources that use a Policy.
+func (cnf *Configurator) AddOrUpdateResourcesThatUsePolicy(ingExes []*IngressEx, mergeableIngresses []*MergeableIngresses, virtualServerExes []*VirtualServerEx) (Warnings, error) {
 	allWarnings := newWarnings()
 	allWeightUpdates := []WeightUpdate{}
 
+	for _, ingEx := range ingExes {
+		_, warnings, err := cnf.addOrUpdateIngress(ingEx)
+		if err != nil {
+			return allWarnings, fmt.Errorf("error adding or updating ingress %v/%v: %w", ingEx.Ingress.Namespace, ingEx.Ingress.Name, err)
+		}
+		allWarnings.Add(warnings)
+	}
+
+	for _, m := range mergeableIngresses {
+		_, warnings, err := cnf.addOrUpdateMergeableIngress(m)
+		if err != nil {
+			return allWarnings, fmt.Errorf("error adding or updating mergeableIngress %v/%v: %w", m.Master.Ingress.Namespace, m.Master.Ingress.Name, err)
+		}
+		allWarnings.Add(warnings)
+	}
+
 	for _, vsEx := range virtualServerExes {
 		_, warnings, weightUpdates, err := cnf.addOrUpdateVirtualServer(vsEx)
 		if err != nil {
@@ -682,6 +830,8 @@ func (cnf *Configurator) updateTransportServerMetricsLabels(transportServerEx *T
 	var newUpstreamsNames []string
 
 	upstreamServerPeerLabels := make(map[string][]string)
+	// the latency collector has the pod owner label when NGINX Service Mesh is enabled, unlike the stream collector of NGINX Plus
+	latencyUpstreamServerPeerLabels := make(map[string][]string)
 	newPeers := make(map[string]bool)
 	var newPeersIPs []string
 
@@ -691,9 +841,14 @@ func (cnf *Configurator) updateTransportServerMetricsLabels(transportServerEx *T
 		newUpstreamsNames = append(newUpstreamsNames, u.Name)
 
 		for _, server := range u.Servers {
-			podName := transportServerEx.PodsByIP[server.Address]
+			podInfo := transportServerEx.PodsByIP[server.Address]
 			labelKey := fmt.Sprintf("%v/%v", u.Name, server.Address)
-			upstreamServerPeerLabels[labelKey] = []string{podName}
+			upstreamServerPeerLabels[labelKey] = []string{podInfo.Name}
+			latencyUpstreamServerPeerLabels[labelKey] = []string{podInfo.Name}
+			if cnf.staticCfgParams.NginxServiceMesh {
+				ownerLabelVal := fmt.Sprintf("%s/%s", podInfo.OwnerType, podInfo.OwnerName)
+				latencyUpstreamServerPeerLabels[labelKey] = append(latencyUpstreamServerPeerLabels[labelKey], ownerLabelVal)
+			}
 
 			newPeers[labelKey] = true
 			newPeersIPs = append(newPeersIPs, labelKey)
@@ -707,36 +862,51 @@ func (cnf *Configurator) updateTransportServerMetricsLabels(transportServerEx *T
 
 	removedUpstreams := findRemovedKeys(cnf.metricLabelsIndex.transportServerUpstreams[key], newUpstreams)
 	cnf.metricLabelsIndex.transportServerUpstreams[key] = newUpstreamsNames
-	cnf.labelUpdater.UpdateStreamUpstreamServerPeerLabels(upstreamServerPeerLabels)
-	cnf.labelUpdater.DeleteStreamUpstreamServerPeerLabels(removedPeers)
-	cnf.labelUpdater.UpdateStreamUpstreamServerLabels(labels)
-	cnf.labelUpdater.DeleteStreamUpstreamServerLabels(removedUpstreams)
 
-	streamServerZoneLabels := make(map[string][]string)
-	newZones := make(map[string]bool)
-	zoneName := transportServerEx.TransportServer.Spec.Listener.Name
+	cnf.latencyCollector.UpdateUpstreamServerPeerLabels(latencyUpstreamServerPeerLabels)
+	cnf.latencyCollector.DeleteUpstreamServerPeerLabels(removedPeers)
+	cnf.latencyCollector.UpdateUpstreamServerLabels(labels)
+	cnf.latencyCollector.DeleteUpstreamServerLabels(removedUpstreams)
+	cnf.latencyCollector.DeleteMetrics(removedPeers)
+
+	if cnf.isPlus {
+		cnf.labelUpdater.UpdateStreamUpstreamServerPeerLabels(upstreamServerPeerLabels)
+		cnf.labelUpdater.DeleteStreamUpstreamServerPeerLabels(removedPeers)
+		cnf.labelUpdater.UpdateStreamUpstreamServerLabels(labels)
+		cnf.labelUpdater.DeleteStreamUpstreamServerLabels(removedUpstreams)
 
-	if transportServerEx.TransportServer.Spec.Host != "" {
-		zoneName = transportServerEx.TransportServer.Spec.Host
-	}
+		streamServerZoneLabels := make(map[string][]string)
+		newZones := make(map[string]bool)
+		zoneName := transportServerEx.TransportServer.Spec.Listener.Name
 
-	newZonesNames := []string{zoneName}
+		if transportServerEx.TransportServer.Spec.Host != "" {
+			zoneName = transportServerEx.TransportServer.Spec.Host
+		}
 
-	streamServerZoneLabels[zoneName] = []string{
-		"transportserver", transportServerEx.TransportServer.Name, transportServerEx.TransportServer.Namespace,
-	}
+		newZonesNames := []string{zoneName}
 
-	newZones[zoneName] = true
-	removedZones := findRemovedKeys(cnf.metricLabelsIndex.transportServerServerZones[key], newZones)
-	cnf.metricLabelsIndex.transportServerServerZones[key] = newZonesNames
-	cnf.labelUpdater.UpdateStreamServerZoneLabels(streamServerZoneLabels)
-	cnf.labelUpdater.DeleteStreamServerZoneLabels(removedZones)
+		streamServerZoneLabels[zoneName] = []string{
+			"transportserver", transportServerEx.TransportServer.Name, transportServerEx.TransportServer.Namespace,
+		}
+
+		newZones[zoneName] = true
+		removedZones := findRemovedKeys(cnf.metricLabelsIndex.transportServerServerZones[key], newZones)
+		cnf.metricLabelsIndex.transportServerServerZones[key] = newZonesNames
+		cnf.labelUpdater.UpdateStreamServerZoneLabels(streamServerZoneLabels)
+		cnf.labelUpdater.DeleteStreamServerZoneLabels(removedZones)
+	}
 }
 
 func (cnf *Configurator) deleteTransportServerMetricsLabels(key string) {
-	cnf.labelUpdater.DeleteStreamUpstreamServerLabels(cnf.metricLabelsIndex.transportServerUpstreams[key])
-	cnf.labelUpdater.DeleteStreamServerZoneLabels(cnf.metricLabelsIndex.transportServerServerZones[key])
-	cnf.labelUpdater.DeleteStreamUpstreamServerPeerLabels(cnf.metricLabelsIndex.transportServerUpstreamPeers[key])
+	cnf.latencyCollector.DeleteUpstreamServerLabels(cnf.metricLabelsIndex.transportServerUpstreams[key])
+	cnf.latencyCollector.DeleteUpstreamServerPeerLabels(cnf.metricLabelsIndex.transportServerUpstreamPeers[key])
+	cnf.latencyCollector.DeleteMetrics(cnf.metricLabelsIndex.transportServerUpstreamPeers[key])
+
+	if cnf.isPlus {
+		cnf.labelUpdater.DeleteStreamUpstreamServerLabels(cnf.metricLabelsIndex.transportServerUpstreams[key])
+		cnf.labelUpdater.DeleteStreamServerZoneLabels(cnf.metricLabelsIndex.transportServerServerZones[key])
+		cnf.labelUpdater.DeleteStreamUpstreamServerPeerLabels(cnf.metricLabelsIndex.transportServerUpstreamPeers[key])
+	}
 
 	delete(cnf.metricLabelsIndex.transportServerUpstreams, key)
 	delete(cnf.metricLabelsIndex.transportServerServerZones, key)
@@ -759,22 +929,24 @@ func (cnf *Configurator) AddOrUpdateTransportServer(transportServerEx *Transport
 func (cnf *Configurator) addOrUpdateTransportServer(transportServerEx *TransportServerEx) (bool, Warnings, error) {
 	name := getFileNameForTransportServer(transportServerEx.TransportServer)
 	tsCfg, warnings := generateTransportServerConfig(transportServerConfigParams{
-		transportServerEx:      transportServerEx,
-		listenerPort:           transportServerEx.ListenerPort,
-		isPlus:                 cnf.isPlus,
-		isResolverConfigured:   cnf.IsResolverConfigured(),
-		isDynamicReloadEnabled: cnf.staticCfgParams.DynamicSSLReload,
-		staticSSLPath:          cnf.staticCfgParams.StaticSSLPath,
+		transportServerEx:       transportServerEx,
+		listenerPort:            transportServerEx.ListenerPort,
+		isPlus:                  cnf.isPlus,
+		isResolverConfigured:    cnf.IsResolverConfigured(),
+		isDynamicReloadEnabled:  cnf.staticCfgParams.DynamicSSLReload,
+		staticSSLPath:           cnf.staticCfgParams.StaticSSLPath,
+		isLatencyMetricsEnabled: cnf.isLatencyMetricsEnabled,
+		now:                     cnf.now(),
 	})
 
 	content, err := cnf.templateExecutorV2.ExecuteTransportServerTemplate(tsCfg)
 	if err != nil {
 		return false, nil, fmt.Errorf("error generating TransportServer config %v: %w", name, err)
 	}
-	if cnf.isPlus && cnf.isPrometheusEnabled {
+	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.updateTransportServerMetricsLabels(transportServerEx, tsCfg.Upstreams)
 	}
-	changed := cnf.nginxManager.CreateStreamConfig(name, content)
+	changed := cnf.createStreamConfig(name, content)
 
 	cnf.transportServers[name] = transportServerEx
 
@@ -852,6 +1024,7 @@ func (cnf *Configurator) AddOrUpdateResources(resources ExtendedResources, reloa
 	allWarnings := newWarnings()
 	allWeightUpdates := []WeightUpdate{}
 	configsChanged := false
+	batch := &auditBatch{}
 
 	updateResource := func(updateFunc func() (bool, Warnings, error), namespace, name string) error {
 		changed, warnings, err := updateFunc()
@@ -880,45 +1053,57 @@ func (cnf *Configurator) AddOrUpdateResources(resources ExtendedResources, reloa
 	}
 
 	for _, ingEx := range resources.IngressExes {
-		err := updateResource(func() (bool, Warnings, error) {
-			return cnf.addOrUpdateIngress(ingEx)
-		}, ingEx.Ingress.Namespace, ingEx.Ingress.Name)
+		err := cnf.auditUpdate(batch, ingressKind, &ingEx.Ingress.ObjectMeta, func() error {
+			return updateResource(func() (bool, Warnings, error) {
+				return cnf.addOrUpdateIngress(ingEx)
+			}, ingEx.Ingress.Namespace, ingEx.Ingress.Name)
+		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	for _, m := range resources.MergeableIngresses {
-		err := updateResource(func() (bool, Warnings, error) {
-			return cnf.addOrUpdateMergeableIngress(m)
-		}, m.Master.Ingress.Namespace, m.Master.Ingress.Name)
+		err := cnf.auditUpdate(batch, ingressKind, &m.Master.Ingress.ObjectMeta, func() error {
+			return updateResource(func() (bool, Warnings, error) {
+				return cnf.addOrUpdateMergeableIngress(m)
+			}, m.Master.Ingress.Namespace, m.Master.Ingress.Name)
+		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	for _, vsEx := range resources.VirtualServerExes {
-		err := updateVSResource(func() (bool, Warnings, []WeightUpdate, error) {
-			return cnf.addOrUpdateVirtualServer(vsEx)
-		}, vsEx.VirtualServer.Namespace, vsEx.VirtualServer.Name)
+		err := cnf.auditUpdate(batch, virtualServerKind, &vsEx.VirtualServer.ObjectMeta, func() error {
+			return updateVSResource(func() (bool, Warnings, []WeightUpdate, error) {
+				return cnf.addOrUpdateVirtualServer(vsEx)
+			}, vsEx.VirtualServer.Namespace, vsEx.VirtualServer.Name)
+		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	for _, tsEx := range resources.TransportServerExes {
-		err := updateResource(func() (bool, Warnings, error) {
-			return cnf.addOrUpdateTransportServer(tsEx)
-		}, tsEx.TransportServer.Namespace, tsEx.TransportServer.Name)
+		err := cnf.auditUpdate(batch, transportServerKind, &tsEx.TransportServer.ObjectMeta, func() error {
+			return updateResource(func() (bool, Warnings, error) {
+				return cnf.addOrUpdateTransportServer(tsEx)
+			}, tsEx.TransportServer.Namespace, tsEx.TransportServer.Name)
+		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if configsChanged || reloadIfUnchanged {
-		if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
-			return nil, fmt.Errorf("error when reloading NGINX when updating resources: %w", err)
+	err := cnf.auditBatchReload(batch, func() error {
+		if configsChanged || reloadIfUnchanged {
+			return cnf.Reload(nginx.ReloadForOtherUpdate)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error when reloading NGINX when updating resources: %w", err)
 	}
 	return allWarnings, nil
 }
@@ -997,7 +1182,7 @@ func GenerateLicenseSecret(secret *api_v1.Secret) ([]byte, error) {
 // DeleteIngress deletes NGINX configuration for the Ingress resource.
 func (cnf *Configurator) DeleteIngress(key string, skipReload bool) error {
 	name := keyToFileName(key)
-	cnf.nginxManager.DeleteConfig(name)
+	cnf.deleteConfig(name)
 
 	delete(cnf.ingresses, name)
 	delete(cnf.minions, name)
@@ -1019,7 +1204,7 @@ func (cnf *Configurator) DeleteIngress(key string, skipReload bool) error {
 // DeleteVirtualServer deletes NGINX configuration for the VirtualServer resource.
 func (cnf *Configurator) DeleteVirtualServer(key string, skipReload bool) error {
 	name := getFileNameForVirtualServerFromKey(key)
-	cnf.nginxManager.DeleteConfig(name)
+	cnf.deleteConfig(name)
 
 	if cnf.isPlus {
 		cnf.nginxManager.DeleteKeyValStateFiles(name)
@@ -1041,7 +1226,7 @@ func (cnf *Configurator) DeleteVirtualServer(key string, skipReload bool) error
 
 // DeleteTransportServer deletes NGINX configuration for the TransportServer resource.
 func (cnf *Configurator) DeleteTransportServer(key string) error {
-	if cnf.isPlus && cnf.isPrometheusEnabled {
+	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.deleteTransportServerMetricsLabels(key)
 	}
 
@@ -1060,7 +1245,7 @@ func (cnf *Configurator) DeleteTransportServer(key string) error {
 
 func (cnf *Configurator) deleteTransportServer(key string) error {
 	name := getFileNameForTransportServerFromKey(key)
-	cnf.nginxManager.DeleteStreamConfig(name)
+	cnf.deleteStreamConfig(name)
 
 	delete(cnf.transportServers, name)
 	// update TLS Passthrough Hosts config in case we have a TLS Passthrough TransportServer
@@ -1304,10 +1489,43 @@ func (cnf *Configurator) DisableReloads() {
 // Reload reloads nginx if reloads is enabled
 func (cnf *Configurator) Reload(isEndpointsUpdate bool) error {
 	if !cnf.isReloadsEnabled {
+		cnf.auditReload(nil)
 		return nil
 	}
 
-	return cnf.nginxManager.Reload(isEndpointsUpdate)
+	err := cnf.nginxManager.Reload(isEndpointsUpdate)
+	cnf.recordReload(err)
+	cnf.auditReload(err)
+	return err
+}
+
+func (cnf *Configurator) recordReload(err error) {
+	cnf.reloadStatusLock.Lock()
+	defer cnf.reloadStatusLock.Unlock()
+
+	now := time.Now()
+	if err == nil {
+		cnf.reloadStatus.ConsecutiveFailures = 0
+		cnf.reloadStatus.LastSuccess = now
+		return
+	}
+
+	cnf.reloadStatus.ConsecutiveFailures++
+	failures := append(cnf.reloadStatus.RecentFailures, ReloadFailure{Time: now, Error: err.Error()})
+	if len(failures) > maxRecentReloadFailures {
+		failures = failures[len(failures)-maxRecentReloadFailures:]
+	}
+	cnf.reloadStatus.RecentFailures = failures
+}
+
+// GetReloadStatus returns the outcome of the recent reloads of NGINX.
+func (cnf *Configurator) GetReloadStatus() ReloadStatus {
+	cnf.reloadStatusLock.RLock()
+	defer cnf.reloadStatusLock.RUnlock()
+
+	status := cnf.reloadStatus
+	status.RecentFailures = append([]ReloadFailure(nil), cnf.reloadStatus.RecentFailures...)
+	return status
 }
 
 func (cnf *Configurator) updateServersInPlus(upstream string, servers []string, config nginx.ServerConfig) error {
@@ -1387,7 +1605,7 @@ func (cnf *Configurator) UpdateConfig(resources ExtendedResources) (Warnings, er
 	if err != nil {
 		return allWarnings, fmt.Errorf("error when writing main Config")
 	}
-	cnf.nginxManager.CreateMainConfig(mainCfgContent)
+	cnf.createMainConfig(mainCfgContent)
 
 	for _, ingEx := range resources.IngressExes {
 		_, warnings, err := cnf.addOrUpdateIngress(ingEx)
@@ -1993,7 +2211,7 @@ func (cnf *Configurator) AddInternalRouteConfig() error {
 	if err != nil {
 		return fmt.Errorf("error when writing main Config: %w", err)
 	}
-	cnf.nginxManager.CreateMainConfig(mainCfgContent)
+	cnf.createMainConfig(mainCfgContent)
 	if err := cnf.Reload(nginx.ReloadForOtherUpdate); err != nil {
 		return fmt.Errorf("error when reloading nginx: %w", err)
 	}
@@ -2018,6 +2236,15 @@ func (cnf *Configurator) AddOrUpdateSecret(secret *api_v1.Secret) string {
 	case secrets.SecretTypeAPIKey:
 		// APIKey ClientSecret is not required on the filesystem, it is written directly to the config file.
 		return ""
+	case secrets.SecretTypeObjectStorage:
+		// The access keys are not required on the filesystem, they are written directly to the config file.
+		return ""
+	case secrets.SecretTypeOAuth2Introspection:
+		// The client credentials are not required on the filesystem, they are written directly to the config file.
+		return ""
+	case secrets.SecretTypeHMAC:
+		// The HMAC key is not required on the filesystem, it is written directly to the config file.
+		return ""
 	case secrets.SecretTypeLicense:
 		return ""
 	default:
@@ -2037,9 +2264,41 @@ func (cnf *Configurator) DynamicSSLReloadEnabled() bool {
 
 // UpsertSplitClientsKeyVal upserts a key-value pair in a keyzal zone for weight changes without reloads.
 func (cnf *Configurator) UpsertSplitClientsKeyVal(zoneName, key, value string) {
+	if _, exists := cnf.splitClientsOverrides[zoneName]; exists {
+		nl.Debugf(nl.LoggerFromContext(cnf.CfgParams.Context), "Ignoring the weight change of the split clients zone %v managed by a Rollout", zoneName)
+		return
+	}
 	cnf.nginxManager.UpsertSplitClientsKeyVal(zoneName, key, value)
 }
 
+// SetSplitClientsKeyValOverride upserts the key value pair of the split clients zone and keeps it
+// for the later updates of the VirtualServer, overriding the weights of its splits.
+func (cnf *Configurator) SetSplitClientsKeyValOverride(update WeightUpdate) {
+	cnf.splitClientsOverrides[update.Zone] = update
+	cnf.nginxManager.UpsertSplitClientsKeyVal(update.Zone, update.Key, update.Value)
+}
+
+// DeleteSplitClientsKeyValOverride removes the override of the split clients zone.
+// The weights of the splits of the VirtualServer apply again after its next update.
+func (cnf *Configurator) DeleteSplitClientsKeyValOverride(zoneName string) {
+	delete(cnf.splitClientsOverrides, zoneName)
+}
+
+// GetUpstreamStats returns the statistics of the requests to the upstream. With the latency metrics enabled,
+// the statistics come from the latency collector and include the response time. Otherwise, they come from
+// the NGINX Plus API without the response time.
+func (cnf *Configurator) GetUpstreamStats(upstream string) (nginx.UpstreamStats, error) {
+	if !cnf.staticCfgParams.EnableLatencyMetrics {
+		return cnf.nginxManager.GetUpstreamStats(upstream)
+	}
+	totals, _ := cnf.latencyCollector.GetUpstreamTotals(upstream)
+	return nginx.UpstreamStats{
+		Requests:          totals.Requests,
+		Responses5xx:      totals.Responses5xx,
+		TotalResponseTime: totals.ResponseTime,
+	}, nil
+}
+
 // GetIngressControllerReplicas returns the number of ingresscontroller-replicas (previously stored via SetIngressControllerReplicas)
 func (cnf *Configurator) GetIngressControllerReplicas() int {
 	return cnf.ingressControllerReplicas
@@ -193,9 +193,9 @@ func BenchmarkAddTransportServerMetricsLabels(b *testing.B) {
 				},
 			},
 		},
-		PodsByIP: map[string]string{
-			"10.0.0.1:80": "pod-1",
-			"10.0.0.2:80": "pod-2",
+		PodsByIP: map[string]PodInfo{
+			"10.0.0.1:80": {Name: "pod-1"},
+			"10.0.0.2:80": {Name: "pod-2"},
 		},
 	}
 
@@ -1,12 +1,15 @@
 package configs
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/prometheus/client_golang/prometheus"
@@ -14,9 +17,11 @@ import (
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/nginx/kubernetes-ingress/internal/audit"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
 	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
+	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
 	"github.com/nginx/kubernetes-ingress/internal/nginx"
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	"github.com/nginx/kubernetes-ingress/pkg/apis/dos/v1beta1"
@@ -717,6 +722,7 @@ type mockLatencyCollector struct {
 	upstreamServerLabels        map[string][]string
 	upstreamServerPeerLabels    map[string][]string
 	upstreamServerPeersToDelete []string
+	upstreamTotals              map[string]collectors.UpstreamTotals
 }
 
 func newMockLatencyCollector() *mockLatencyCollector {
@@ -765,6 +771,34 @@ func (u *mockLatencyCollector) RecordLatency(string) {}
 // Register implements a fake Register method
 func (u *mockLatencyCollector) Register(*prometheus.Registry) error { return nil }
 
+// GetUpstreamTotals returns the totals of the upstream
+func (u *mockLatencyCollector) GetUpstreamTotals(upstreamName string) (collectors.UpstreamTotals, bool) {
+	totals, ok := u.upstreamTotals[upstreamName]
+	return totals, ok
+}
+
+func TestGetUpstreamStatsFromLatencyCollector(t *testing.T) {
+	t.Parallel()
+	cnf := createTestConfigurator(t)
+	cnf.staticCfgParams.EnableLatencyMetrics = true
+	lc := newMockLatencyCollector()
+	lc.upstreamTotals = map[string]collectors.UpstreamTotals{
+		"vs_default_cafe_tea": {Requests: 10, Responses5xx: 1, ResponseTime: 2 * time.Second},
+	}
+	cnf.latencyCollector = lc
+
+	expected := nginx.UpstreamStats{Requests: 10, Responses5xx: 1, TotalResponseTime: 2 * time.Second}
+	stats, err := cnf.GetUpstreamStats("vs_default_cafe_tea")
+	if err != nil || stats != expected {
+		t.Errorf("GetUpstreamStats() returned %+v, %v, expected %+v, nil", stats, err, expected)
+	}
+
+	stats, err = cnf.GetUpstreamStats("vs_default_cafe_coffee")
+	if err != nil || stats != (nginx.UpstreamStats{}) {
+		t.Errorf("GetUpstreamStats() returned %+v, %v for an upstream without requests, expected empty stats", stats, err)
+	}
+}
+
 func TestUpdateIngressMetricsLabels(t *testing.T) {
 	t.Parallel()
 	cnf := createTestConfigurator(t)
@@ -1118,6 +1152,8 @@ func TestUpdateTransportServerMetricsLabels(t *testing.T) {
 
 	cnf.isPlus = true
 	cnf.labelUpdater = newFakeLabelUpdater()
+	testLatencyCollector := newMockLatencyCollector()
+	cnf.latencyCollector = testLatencyCollector
 
 	tsEx := &TransportServerEx{
 		TransportServer: &conf_v1.TransportServer{
@@ -1132,9 +1168,9 @@ func TestUpdateTransportServerMetricsLabels(t *testing.T) {
 				},
 			},
 		},
-		PodsByIP: map[string]string{
-			"10.0.0.1:80": "pod-1",
-			"10.0.0.2:80": "pod-2",
+		PodsByIP: map[string]PodInfo{
+			"10.0.0.1:80": {Name: "pod-1"},
+			"10.0.0.2:80": {Name: "pod-2"},
 		},
 	}
 
@@ -1191,11 +1227,18 @@ func TestUpdateTransportServerMetricsLabels(t *testing.T) {
 		cacheZoneLabels:                make(map[string][]string),
 		workerPIDVariableLabels:        make(map[string][]string),
 	}
+	expectedLatencyCollector := &mockLatencyCollector{
+		upstreamServerLabels:     streamUpstreamServerLabels,
+		upstreamServerPeerLabels: streamUpstreamServerPeerLabels,
+	}
 
 	cnf.updateTransportServerMetricsLabels(tsEx, streamUpstreams)
 	if !reflect.DeepEqual(cnf.labelUpdater, expectedLabelUpdater) {
 		t.Errorf("updateTransportServerMetricsLabels() updated labels to \n%+v but expected \n%+v", cnf.labelUpdater, expectedLabelUpdater)
 	}
+	if !reflect.DeepEqual(testLatencyCollector, expectedLatencyCollector) {
+		t.Errorf("updateTransportServerMetricsLabels() updated latency collector labels to \n%+v but expected \n%+v", testLatencyCollector, expectedLatencyCollector)
+	}
 
 	updatedStreamUpstreams := []version2.StreamUpstream{
 		{
@@ -1234,11 +1277,19 @@ func TestUpdateTransportServerMetricsLabels(t *testing.T) {
 		cacheZoneLabels:                map[string][]string{},
 		workerPIDVariableLabels:        map[string][]string{},
 	}
+	expectedLatencyCollector = &mockLatencyCollector{
+		upstreamServerLabels:        streamUpstreamServerLabels,
+		upstreamServerPeerLabels:    streamUpstreamServerPeerLabels,
+		upstreamServerPeersToDelete: []string{"upstream-2/10.0.0.2:80"},
+	}
 
 	cnf.updateTransportServerMetricsLabels(tsEx, updatedStreamUpstreams)
 	if !reflect.DeepEqual(cnf.labelUpdater, expectedLabelUpdater) {
 		t.Errorf("updateTransportServerMetricsLabels() updated labels to \n%+v but expected \n%+v", cnf.labelUpdater, expectedLabelUpdater)
 	}
+	if !reflect.DeepEqual(testLatencyCollector, expectedLatencyCollector) {
+		t.Errorf("updateTransportServerMetricsLabels() updated latency collector labels to \n%+v but expected \n%+v", testLatencyCollector, expectedLatencyCollector)
+	}
 
 	expectedLabelUpdater = &mockLabelUpdater{
 		upstreamServerLabels:           map[string][]string{},
@@ -1251,10 +1302,19 @@ func TestUpdateTransportServerMetricsLabels(t *testing.T) {
 		workerPIDVariableLabels:        map[string][]string{},
 	}
 
+	expectedLatencyCollector = &mockLatencyCollector{
+		upstreamServerLabels:        map[string][]string{},
+		upstreamServerPeerLabels:    map[string][]string{},
+		upstreamServerPeersToDelete: []string{"upstream-1/10.0.0.1:80"},
+	}
+
 	cnf.deleteTransportServerMetricsLabels("default/test-transportserver")
 	if !reflect.DeepEqual(cnf.labelUpdater, expectedLabelUpdater) {
 		t.Errorf("deleteTransportServerMetricsLabels() updated labels to \n%+v but expected \n%+v", cnf.labelUpdater, expectedLabelUpdater)
 	}
+	if !reflect.DeepEqual(testLatencyCollector, expectedLatencyCollector) {
+		t.Errorf("deleteTransportServerMetricsLabels() updated latency collector labels to \n%+v but expected \n%+v", testLatencyCollector, expectedLatencyCollector)
+	}
 
 	tsExTLS := &TransportServerEx{
 		TransportServer: &conf_v1.TransportServer{
@@ -1270,8 +1330,8 @@ func TestUpdateTransportServerMetricsLabels(t *testing.T) {
 				Host: "example.com",
 			},
 		},
-		PodsByIP: map[string]string{
-			"10.0.0.3:80": "pod-3",
+		PodsByIP: map[string]PodInfo{
+			"10.0.0.3:80": {Name: "pod-3"},
 		},
 	}
 
@@ -1335,6 +1395,85 @@ func TestUpdateTransportServerMetricsLabels(t *testing.T) {
 	}
 }
 
+func TestUpdateTransportServerMetricsLabelsWithNginxServiceMesh(t *testing.T) {
+	t.Parallel()
+	cnf := createTestConfigurator(t)
+
+	cnf.staticCfgParams.NginxServiceMesh = true
+	cnf.isPlus = true
+	cnf.labelUpdater = newFakeLabelUpdater()
+	testLatencyCollector := newMockLatencyCollector()
+	cnf.latencyCollector = testLatencyCollector
+
+	tsEx := &TransportServerEx{
+		TransportServer: &conf_v1.TransportServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "test-transportserver",
+				Namespace: "default",
+			},
+			Spec: conf_v1.TransportServerSpec{
+				Listener: conf_v1.TransportServerListener{
+					Name:     "dns-tcp",
+					Protocol: "TCP",
+				},
+			},
+		},
+		PodsByIP: map[string]PodInfo{
+			"10.0.0.1:80": {Name: "pod-1", MeshPodOwner: MeshPodOwner{OwnerType: "deployment", OwnerName: "deploy-1"}},
+		},
+	}
+
+	streamUpstreams := []version2.StreamUpstream{
+		{
+			Name: "upstream-1",
+			Servers: []version2.StreamUpstreamServer{
+				{
+					Address: "10.0.0.1:80",
+				},
+			},
+			UpstreamLabels: version2.UpstreamLabels{
+				Service:           "service-1",
+				ResourceType:      "transportserver",
+				ResourceName:      tsEx.TransportServer.Name,
+				ResourceNamespace: tsEx.TransportServer.Namespace,
+			},
+		},
+	}
+
+	streamUpstreamServerLabels := map[string][]string{
+		"upstream-1": {"service-1", "transportserver", "test-transportserver", "default"},
+	}
+
+	expectedLabelUpdater := &mockLabelUpdater{
+		streamUpstreamServerLabels: streamUpstreamServerLabels,
+		streamServerZoneLabels: map[string][]string{
+			"dns-tcp": {"transportserver", "test-transportserver", "default"},
+		},
+		streamUpstreamServerPeerLabels: map[string][]string{
+			"upstream-1/10.0.0.1:80": {"pod-1"},
+		},
+		upstreamServerPeerLabels: make(map[string][]string),
+		upstreamServerLabels:     make(map[string][]string),
+		serverZoneLabels:         make(map[string][]string),
+		cacheZoneLabels:          make(map[string][]string),
+		workerPIDVariableLabels:  make(map[string][]string),
+	}
+	expectedLatencyCollector := &mockLatencyCollector{
+		upstreamServerLabels: streamUpstreamServerLabels,
+		upstreamServerPeerLabels: map[string][]string{
+			"upstream-1/10.0.0.1:80": {"pod-1", "deployment/deploy-1"},
+		},
+	}
+
+	cnf.updateTransportServerMetricsLabels(tsEx, streamUpstreams)
+	if !reflect.DeepEqual(cnf.labelUpdater, expectedLabelUpdater) {
+		t.Errorf("updateTransportServerMetricsLabels() updated labels to \n%+v but expected \n%+v", cnf.labelUpdater, expectedLabelUpdater)
+	}
+	if !reflect.DeepEqual(testLatencyCollector, expectedLatencyCollector) {
+		t.Errorf("updateTransportServerMetricsLabels() updated latency collector labels to \n%+v but expected \n%+v", testLatencyCollector, expectedLatencyCollector)
+	}
+}
+
 func TestUpdateApResources(t *testing.T) {
 	t.Parallel()
 	conf := createTestConfigurator(t)
@@ -1620,6 +1759,95 @@ func TestStreamUpstreamsForName_ReturnsStreamUpstreamsNamesOnValidServiceName(t
 	}
 }
 
+func TestUpstreamServicesForVirtualServer_ReturnsNilForBogusVirtualServer(t *testing.T) {
+	t.Parallel()
+
+	tcnf := createTestConfigurator(t)
+	tcnf.virtualServers = map[string]*VirtualServerEx{
+		"vs": validVirtualServerExWithUpstreams,
+	}
+
+	got := tcnf.UpstreamServicesForVirtualServer("default", "bogus-vs")
+	if got != nil {
+		t.Errorf("want nil, got %+v", got)
+	}
+}
+
+func TestUpstreamServicesForVirtualServer_ReturnsUpstreamsOfVirtualServerAndVirtualServerRoutes(t *testing.T) {
+	t.Parallel()
+
+	tcnf := createTestConfigurator(t)
+	tcnf.virtualServers = map[string]*VirtualServerEx{
+		"vs": {
+			VirtualServer: validVirtualServerExWithUpstreams.VirtualServer,
+			VirtualServerRoutes: []*conf_v1.VirtualServerRoute{
+				{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "coffee",
+						Namespace: "coffee-ns",
+					},
+					Spec: conf_v1.VirtualServerRouteSpec{
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:        "coffee-app",
+								Service:     "coffee-svc",
+								Port:        80,
+								Subselector: map[string]string{"version": "v1"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	want := []UpstreamService{
+		{
+			Name:         "vs_default_test-vs_tea-app",
+			Namespace:    "default",
+			ResourceType: "virtualserver",
+			ResourceName: "test-vs",
+		},
+		{
+			Name:         "vs_default_test-vs_vsr_coffee-ns_coffee_coffee-app",
+			Service:      "coffee-svc",
+			Port:         80,
+			Subselector:  map[string]string{"version": "v1"},
+			Namespace:    "coffee-ns",
+			ResourceType: "virtualserverroute",
+			ResourceName: "coffee",
+		},
+	}
+	got := tcnf.UpstreamServicesForVirtualServer("default", "test-vs")
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestStreamUpstreamServicesForName_ReturnsStreamUpstreamsOnValidServiceName(t *testing.T) {
+	t.Parallel()
+
+	tcnf := createTestConfigurator(t)
+	tcnf.transportServers = map[string]*TransportServerEx{
+		"ts": validTransportServerExWithUpstreams,
+	}
+
+	want := []UpstreamService{
+		{
+			Name:         "ts_default_secure-app_secure-app",
+			Service:      "secure-app",
+			Port:         8443,
+			Namespace:    "default",
+			ResourceType: "transportserver",
+			ResourceName: "secure-app",
+		},
+	}
+	got := tcnf.StreamUpstreamServicesForName("secure-app")
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
 func TestGetIngressAnnotations(t *testing.T) {
 	t.Parallel()
 
@@ -3413,3 +3641,169 @@ server {
     {{- end }}
 }`
 )
+
+func TestGetReloadStatus(t *testing.T) {
+	t.Parallel()
+
+	cnf := createTestConfigurator(t)
+
+	for i := 0; i < maxRecentReloadFailures+2; i++ {
+		cnf.recordReload(fmt.Errorf("reload failure %d", i))
+	}
+
+	status := cnf.GetReloadStatus()
+	if status.ConsecutiveFailures != maxRecentReloadFailures+2 {
+		t.Errorf("GetReloadStatus() returned %d consecutive failures, expected %d", status.ConsecutiveFailures, maxRecentReloadFailures+2)
+	}
+	if len(status.RecentFailures) != maxRecentReloadFailures {
+		t.Fatalf("GetReloadStatus() returned %d recent failures, expected %d", len(status.RecentFailures), maxRecentReloadFailures)
+	}
+	if status.RecentFailures[0].Error != "reload failure 2" {
+		t.Errorf("GetReloadStatus() returned %q as the oldest failure, expected %q", status.RecentFailures[0].Error, "reload failure 2")
+	}
+	if !status.LastSuccess.IsZero() {
+		t.Errorf("GetReloadStatus() returned last success %v, expected none", status.LastSuccess)
+	}
+
+	cnf.recordReload(nil)
+
+	status = cnf.GetReloadStatus()
+	if status.ConsecutiveFailures != 0 {
+		t.Errorf("GetReloadStatus() returned %d consecutive failures after a successful reload, expected 0", status.ConsecutiveFailures)
+	}
+	if status.LastSuccess.IsZero() {
+		t.Error("GetReloadStatus() returned no last success after a successful reload")
+	}
+	if len(status.RecentFailures) != maxRecentReloadFailures {
+		t.Errorf("GetReloadStatus() returned %d recent failures after a successful reload, expected %d", len(status.RecentFailures), maxRecentReloadFailures)
+	}
+}
+
+func TestAuditRecords(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	cnf := createTestConfigurator(t)
+	cnf.auditLogger = audit.NewLogger(&buf, "")
+
+	vsEx := &VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:            "cafe",
+				Namespace:       "default",
+				ResourceVersion: "1",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+			},
+		},
+	}
+
+	cnf.BeginAuditRecord()
+	_, err := cnf.AddOrUpdateVirtualServer(vsEx)
+	cnf.WriteAuditRecord(virtualServerKind, &vsEx.VirtualServer.ObjectMeta, audit.OperationAddOrUpdate, err)
+	if err != nil {
+		t.Fatalf("AddOrUpdateVirtualServer() returned unexpected error: %v", err)
+	}
+
+	_, err = cnf.AddOrUpdateResources(ExtendedResources{VirtualServerExes: []*VirtualServerEx{vsEx}}, false)
+	if err != nil {
+		t.Fatalf("AddOrUpdateResources() returned unexpected error: %v", err)
+	}
+
+	cnf.BeginAuditRecord()
+	err = cnf.DeleteVirtualServer("default/cafe", false)
+	cnf.WriteAuditRecord(virtualServerKind, &vsEx.VirtualServer.ObjectMeta, audit.OperationDelete, err)
+	if err != nil {
+		t.Fatalf("DeleteVirtualServer() returned unexpected error: %v", err)
+	}
+
+	var records []audit.Record
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var r audit.Record
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("failed to decode the audit record: %v", err)
+		}
+		records = append(records, r)
+	}
+
+	file := "conf.d/vs_default_cafe.conf"
+	expected := []struct {
+		operation string
+		deleted   bool
+		reload    string
+	}{
+		{operation: audit.OperationAddOrUpdate, reload: audit.ReloadSucceeded},
+		{operation: audit.OperationAddOrUpdate, reload: audit.ReloadSucceeded},
+		{operation: audit.OperationDelete, deleted: true, reload: audit.ReloadSucceeded},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("the configurator wrote %d audit records, expected %d", len(records), len(expected))
+	}
+	for i, e := range expected {
+		r := records[i]
+		if r.Kind != virtualServerKind || r.Key != "default/cafe" || r.ResourceVersion != "1" {
+			t.Errorf("record %d is for %s %s version %s, expected VirtualServer default/cafe version 1", i, r.Kind, r.Key, r.ResourceVersion)
+		}
+		if r.Operation != e.operation || r.Reload != e.reload {
+			t.Errorf("record %d has operation %s and reload %s, expected %s and %s", i, r.Operation, r.Reload, e.operation, e.reload)
+		}
+		if len(r.Files) != 1 || r.Files[0].Name != file || r.Files[0].Deleted != e.deleted {
+			t.Errorf("record %d has files %v, expected %s with deleted %v", i, r.Files, file, e.deleted)
+		}
+	}
+	if idx := audit.Verify(records); idx != -1 {
+		t.Errorf("audit.Verify() returned %d for the records of the configurator, expected -1", idx)
+	}
+}
+
+func TestAuditRecordOfConfigMapUpdate(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	cnf := createTestConfigurator(t)
+	cnf.auditLogger = audit.NewLogger(&buf, "")
+
+	vsEx := &VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+			},
+		},
+	}
+	configMap := &meta_v1.ObjectMeta{
+		Name:            "nginx-config",
+		Namespace:       "nginx-ingress",
+		ResourceVersion: "5",
+	}
+
+	cnf.BeginAuditRecordOfChangedFiles()
+	_, err := cnf.UpdateConfig(ExtendedResources{VirtualServerExes: []*VirtualServerEx{vsEx}})
+	cnf.WriteAuditRecord("ConfigMap", configMap, audit.OperationAddOrUpdate, err)
+	if err != nil {
+		t.Fatalf("UpdateConfig() returned unexpected error: %v", err)
+	}
+
+	var r audit.Record
+	if err := json.NewDecoder(&buf).Decode(&r); err != nil {
+		t.Fatalf("failed to decode the audit record: %v", err)
+	}
+	if r.Kind != "ConfigMap" || r.Key != "nginx-ingress/nginx-config" || r.ResourceVersion != "5" || r.Reload != audit.ReloadSucceeded {
+		t.Errorf("the record is for %s %s version %s with reload %s, expected ConfigMap nginx-ingress/nginx-config version 5 with reload %s",
+			r.Kind, r.Key, r.ResourceVersion, r.Reload, audit.ReloadSucceeded)
+	}
+
+	var files []string
+	for _, f := range r.Files {
+		files = append(files, f.Name)
+	}
+	expected := []string{"nginx.conf", "conf.d/vs_default_cafe.conf"}
+	if diff := cmp.Diff(expected, files); diff != "" {
+		t.Errorf("the record has unexpected files (-want +got):\n%s", diff)
+	}
+}
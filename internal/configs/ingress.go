@@ -5,7 +5,9 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	"github.com/nginx/kubernetes-ingress/pkg/apis/dos/v1beta1"
 
 	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
@@ -17,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/nginx/kubernetes-ingress/internal/configs/version1"
+	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
 )
 
 const emptyHost = ""
@@ -46,6 +49,8 @@ type IngressEx struct {
 	AppProtectLogs   []AppProtectLog
 	DosEx            *DosEx
 	SecretRefs       map[string]*secrets.SecretReference
+	Policies         map[string]*conf_v1.Policy
+	DeniedReferences map[string]error
 }
 
 // DosEx holds a DosProtectedResource and the dos policy and log confs it references.
@@ -89,6 +94,17 @@ type NginxCfgParams struct {
 	isResolverConfigured      bool
 	isWildcardEnabled         bool
 	ingressControllerReplicas int
+	masterPolicies            *policiesCfg
+	// policies are the Policies of the Ingress generated by the caller, so that they are not generated again.
+	policies *ingressPolicies
+	now      time.Time
+}
+
+// ingressPolicies holds the result of generateIngressPolicies.
+type ingressPolicies struct {
+	cfg           policiesCfg
+	oidcProviders []*version2.OIDC
+	warnings      Warnings
 }
 
 //nolint:gocyclo
@@ -131,6 +147,64 @@ func generateNginxCfg(p NginxCfgParams) (version1.IngressNginxConfig, Warnings)
 
 	var servers []version1.Server
 	var limitReqZones []version1.LimitReqZone
+	var maps []version2.Map
+	var authJWTClaimSets []version2.AuthJWTClaimSet
+
+	var policies policiesCfg
+	var oidcProviders []*version2.OIDC
+	var locPolicies *version1.Policies
+
+	_, hasPolicies := p.ingEx.Ingress.Annotations[PoliciesAnnotation]
+	if hasPolicies {
+		generated := p.policies
+		if generated == nil {
+			generated = &ingressPolicies{}
+			generated.cfg, generated.oidcProviders, generated.warnings = generateIngressPolicies(p)
+		}
+		policies, oidcProviders = generated.cfg, generated.oidcProviders
+		allWarnings.Add(generated.warnings)
+	}
+
+	if p.isMinion && p.masterPolicies != nil {
+		inheritIngressPolicies(&policies, p.masterPolicies)
+		hasPolicies = true
+	}
+
+	if hasPolicies {
+		if policies.JWTAuth.Auth != nil && cfgParams.JWTKey != "" {
+			allWarnings.AddWarningf(p.ingEx.Ingress, "The %s annotation is ignored because a JWT policy is applied", JWTKeyAnnotation)
+			cfgParams.JWTKey = ""
+		}
+		if policies.BasicAuth != nil && cfgParams.BasicAuthSecret != "" {
+			allWarnings.AddWarningf(p.ingEx.Ingress, "The %s annotation is ignored because a basicAuth policy is applied", BasicAuthSecretAnnotation)
+			cfgParams.BasicAuthSecret = ""
+		}
+		if len(policies.RateLimit.Reqs) > 0 && cfgParams.LimitReqRate != "" {
+			allWarnings.AddWarningf(p.ingEx.Ingress, "The nginx.org/limit-req-* annotations are ignored because a rateLimit policy is applied")
+			cfgParams.LimitReqRate = ""
+		}
+
+		locPolicies = generateIngressLocationPolicies(policies, p.isMinion)
+
+		for _, z := range policies.RateLimit.Zones {
+			if !limitReqZoneExists(limitReqZones, z.ZoneName) {
+				limitReqZones = append(limitReqZones, version1.LimitReqZone{
+					Name: z.ZoneName,
+					Key:  z.Key,
+					Size: z.ZoneSize,
+					Rate: z.Rate,
+				})
+			}
+		}
+		maps = append(maps, policies.RateLimit.GroupMaps...)
+		maps = append(maps, policies.RateLimit.PolicyGroupMaps...)
+		maps = append(maps, policies.JWTAuth.Maps...)
+		if policies.APIKey.Key != nil {
+			maps = append(maps, generateAPIKeyClientMaps(policies.APIKey.Key.MapName, policies.APIKey.Clients)...)
+		}
+		authJWTClaimSets = append(authJWTClaimSets, policies.RateLimit.AuthJWTClaimSets...)
+		authJWTClaimSets = append(authJWTClaimSets, policies.JWTAuth.AuthJWTClaimSets...)
+	}
 
 	for _, rule := range p.ingEx.Ingress.Spec.Rules {
 		// skipping invalid hosts
@@ -176,7 +250,7 @@ func generateNginxCfg(p NginxCfgParams) (version1.IngressNginxConfig, Warnings)
 			DisableIPV6:           p.staticParams.DisableIPV6,
 		}
 
-		warnings := addSSLConfig(&server, p.ingEx.Ingress, rule.Host, p.ingEx.Ingress.Spec.TLS, p.ingEx.SecretRefs, p.isWildcardEnabled)
+		warnings := addSSLConfig(&server, p.ingEx.Ingress, rule.Host, p.ingEx.Ingress.Spec.TLS, p.ingEx.SecretRefs, p.isWildcardEnabled, p.now)
 		allWarnings.Add(warnings)
 
 		if hasAppProtect {
@@ -197,6 +271,10 @@ func generateNginxCfg(p NginxCfgParams) (version1.IngressNginxConfig, Warnings)
 			server.AppProtectDosLogConfFile = p.dosResource.AppProtectDosLogConfFile
 		}
 
+		if hasPolicies {
+			addIngressPoliciesToServer(&server, policies, oidcProviders, p.ingEx.Ingress, p.isMinion, allWarnings)
+		}
+
 		if !p.isMinion && cfgParams.JWTKey != "" {
 			jwtAuth, redirectLoc, warnings := generateJWTConfig(p.ingEx.Ingress, p.ingEx.SecretRefs, &cfgParams, getNameForRedirectLocation(p.ingEx.Ingress))
 			server.JWTAuth = jwtAuth
@@ -253,6 +331,7 @@ func generateNginxCfg(p NginxCfgParams) (version1.IngressNginxConfig, Warnings)
 			proxySSLName := generateProxySSLName(path.Backend.Service.Name, p.ingEx.Ingress.Namespace)
 			loc := createLocation(pathOrDefault(path.Path), upstreams[upsName], &cfgParams, wsServices[path.Backend.Service.Name], rewrites[path.Backend.Service.Name],
 				ssl, grpcServices[path.Backend.Service.Name], proxySSLName, path.PathType, path.Backend.Service.Name)
+			loc.Policies = locPolicies
 
 			if p.isMinion && cfgParams.JWTKey != "" {
 				jwtAuth, redirectLoc, warnings := generateJWTConfig(p.ingEx.Ingress, p.ingEx.SecretRefs, &cfgParams, getNameForRedirectLocation(p.ingEx.Ingress))
@@ -309,6 +388,7 @@ func generateNginxCfg(p NginxCfgParams) (version1.IngressNginxConfig, Warnings)
 
 			loc := createLocation(pathOrDefault("/"), upstreams[upsName], &cfgParams, wsServices[p.ingEx.Ingress.Spec.DefaultBackend.Service.Name], rewrites[p.ingEx.Ingress.Spec.DefaultBackend.Service.Name],
 				ssl, grpcServices[p.ingEx.Ingress.Spec.DefaultBackend.Service.Name], proxySSLName, &pathtype, p.ingEx.Ingress.Spec.DefaultBackend.Service.Name)
+			loc.Policies = locPolicies
 			locations = append(locations, loc)
 
 			if cfgParams.HealthCheckEnabled {
@@ -347,9 +427,143 @@ func generateNginxCfg(p NginxCfgParams) (version1.IngressNginxConfig, Warnings)
 		DynamicSSLReloadEnabled: p.staticParams.DynamicSSLReload,
 		StaticSSLPath:           p.staticParams.StaticSSLPath,
 		LimitReqZones:           limitReqZones,
+		Maps:                    removeDuplicateMaps(maps),
+		AuthJWTClaimSets:        removeDuplicateAuthJWTClaimSets(authJWTClaimSets),
+		JWKSAuthEnabled:         policies.JWTAuth.JWKSEnabled,
 	}, allWarnings
 }
 
+// generateIngressPolicies generates the configuration of the Policies referenced in the nginx.org/policies annotation.
+// The Policies of standalone and master Ingresses are generated in the spec context, the Policies of minions in the route context.
+func generateIngressPolicies(p NginxCfgParams) (policiesCfg, []*version2.OIDC, Warnings) {
+	ing := p.ingEx.Ingress
+	warnings := newWarnings()
+
+	policyRefs, err := ParsePolicyReferences(ing.Annotations[PoliciesAnnotation])
+	if err != nil {
+		warnings.AddWarningf(ing, "Invalid value for the %s annotation: %v", PoliciesAnnotation, err)
+		return policiesCfg{ErrorReturn: &version2.Return{Code: 500}}, nil, warnings
+	}
+
+	for _, ref := range policyRefs {
+		polNamespace := ref.Namespace
+		if polNamespace == "" {
+			polNamespace = ing.Namespace
+		}
+		key := fmt.Sprintf("%s/%s", polNamespace, ref.Name)
+
+		if pol, exists := p.ingEx.Policies[key]; exists && !isPolicySupportedByIngress(pol) {
+			warnings.AddWarningf(ing, "Policy %s is of a type that is not supported in Ingress resources", key)
+			return policiesCfg{ErrorReturn: &version2.Return{Code: 500}}, nil, warnings
+		}
+	}
+
+	vsc := newVirtualServerConfigurator(p.BaseCfgParams, p.isPlus, p.isResolverConfigured, p.staticParams, p.isWildcardEnabled, nil)
+	vsc.IngressControllerReplicas = p.ingressControllerReplicas
+
+	ownerDetails := policyOwnerDetails{
+		owner:          ing,
+		ownerName:      ing.Name,
+		ownerNamespace: ing.Namespace,
+		vsNamespace:    ing.Namespace,
+		vsName:         ing.Name,
+	}
+
+	context := specContext
+	if p.isMinion {
+		context = routeContext
+	}
+
+	policyOpts := policyOptions{
+		tls:              len(ing.Spec.TLS) > 0,
+		secretRefs:       p.ingEx.SecretRefs,
+		deniedReferences: p.ingEx.DeniedReferences,
+	}
+
+	policies := vsc.generatePolicies(ownerDetails, policyRefs, p.ingEx.Policies, context, policyOpts)
+	warnings.Add(vsc.warnings)
+
+	return policies, vsc.oidcPolCfg.providers, warnings
+}
+
+// isPolicySupportedByIngress checks if the type of the Policy can be referenced by an Ingress.
+// GeoIP2-based AccessControl policies are not supported.
+func isPolicySupportedByIngress(pol *conf_v1.Policy) bool {
+	spec := pol.Spec
+	return (spec.AccessControl != nil && spec.AccessControl.Geo == nil) || spec.RateLimit != nil || spec.JWTAuth != nil || spec.BasicAuth != nil ||
+		spec.IngressMTLS != nil || spec.EgressMTLS != nil || spec.OIDC != nil || spec.APIKey != nil
+}
+
+// inheritIngressPolicies applies the Policies of the master to a minion for every Policy type the minion doesn't reference itself.
+func inheritIngressPolicies(minion *policiesCfg, master *policiesCfg) {
+	if len(minion.Allow) == 0 && len(minion.Deny) == 0 {
+		minion.Allow = master.Allow
+		minion.Deny = master.Deny
+	}
+	if len(minion.RateLimit.Reqs) == 0 {
+		minion.RateLimit = master.RateLimit
+	}
+	if minion.JWTAuth.Auth == nil {
+		minion.JWTAuth = master.JWTAuth
+	}
+	if minion.BasicAuth == nil {
+		minion.BasicAuth = master.BasicAuth
+	}
+	if minion.EgressMTLS == nil {
+		minion.EgressMTLS = master.EgressMTLS
+	}
+	if minion.OIDC == nil {
+		minion.OIDC = master.OIDC
+	}
+	if minion.APIKey.Key == nil {
+		minion.APIKey = master.APIKey
+	}
+}
+
+// generateIngressLocationPolicies generates the Policy configuration of the locations of an Ingress.
+// The error return of standalone and master Ingresses is configured at the server level.
+func generateIngressLocationPolicies(policies policiesCfg, isMinion bool) *version1.Policies {
+	locPolicies := &version1.Policies{
+		Allow:           policies.Allow,
+		Deny:            policies.Deny,
+		LimitReqOptions: policies.RateLimit.Options,
+		LimitReqs:       policies.RateLimit.Reqs,
+		JWTAuth:         policies.JWTAuth.Auth,
+		JWTHeaders:      policies.JWTAuth.Headers,
+		BasicAuth:       policies.BasicAuth,
+		EgressMTLS:      policies.EgressMTLS,
+		OIDC:            policies.OIDC,
+		APIKey:          policies.APIKey.Key,
+	}
+	if isMinion {
+		locPolicies.ErrorReturn = policies.ErrorReturn
+	}
+	return locPolicies
+}
+
+// addIngressPoliciesToServer adds the server level Policy configuration of an Ingress to a server.
+func addIngressPoliciesToServer(server *version1.Server, policies policiesCfg, oidcProviders []*version2.OIDC, owner runtime.Object, isMinion bool, warnings Warnings) {
+	if !isMinion {
+		server.PoliciesErrorReturn = policies.ErrorReturn
+		if policies.IngressMTLS != nil {
+			if server.SSL {
+				server.IngressMTLS = policies.IngressMTLS
+			} else {
+				warnings.AddWarningf(owner, "TLS must be enabled for the host %s for the IngressMTLS policy", server.Name)
+				server.PoliciesErrorReturn = &version2.Return{Code: 500}
+			}
+		}
+	}
+
+	if policies.JWTAuth.JWKSEnabled {
+		server.JWTAuthList = map[string]*version2.JWTAuth{
+			policies.JWTAuth.Auth.Key: policies.JWTAuth.Auth,
+		}
+	}
+	server.APIKeyEnabled = policies.APIKey.Key != nil
+	server.OIDCProviders = oidcProviders
+}
+
 func generateJWTConfig(owner runtime.Object, secretRefs map[string]*secrets.SecretReference, cfgParams *ConfigParams,
 	redirectLocationName string,
 ) (*version1.JWTAuth, *version1.JWTRedirectLocation, Warnings) {
@@ -410,7 +624,7 @@ func generateBasicAuthConfig(owner runtime.Object, secretRefs map[string]*secret
 }
 
 func addSSLConfig(server *version1.Server, owner runtime.Object, host string, ingressTLS []networking.IngressTLS,
-	secretRefs map[string]*secrets.SecretReference, isWildcardEnabled bool,
+	secretRefs map[string]*secrets.SecretReference, isWildcardEnabled bool, now time.Time,
 ) Warnings {
 	warnings := newWarnings()
 
@@ -448,6 +662,9 @@ func addSSLConfig(server *version1.Server, owner runtime.Object, host string, in
 			warnings.AddWarningf(owner, "TLS secret %s is invalid: %v", tlsSecret, secretRef.Error)
 		} else {
 			pemFile = secretRef.Path
+			if expired, notAfter := secrets.IsCertificateExpired(secretRef.Secret, now); expired {
+				warnings.AddWarningf(owner, "TLS secret %s holds a certificate that expired on %s", tlsSecret, notAfter.Format(time.RFC3339))
+			}
 		}
 	} else if isWildcardEnabled {
 		pemFile = pemFileNameForWildcardTLSSecret
@@ -643,7 +860,7 @@ func generateNginxCfgForMergeableIngresses(p NginxCfgParams) (version1.IngressNg
 	}
 	isMinion := false
 
-	masterNginxCfg, warnings := generateNginxCfg(NginxCfgParams{
+	masterParams := NginxCfgParams{
 		staticParams:              p.staticParams,
 		ingEx:                     p.mergeableIngs.Master,
 		apResources:               p.apResources,
@@ -654,7 +871,20 @@ func generateNginxCfgForMergeableIngresses(p NginxCfgParams) (version1.IngressNg
 		isResolverConfigured:      p.isResolverConfigured,
 		isWildcardEnabled:         p.isWildcardEnabled,
 		ingressControllerReplicas: p.ingressControllerReplicas,
-	})
+		now:                       p.now,
+	}
+
+	// the Policies of the master apply to the minions that don't reference a Policy of the same type.
+	// They are generated once for the master and the minions.
+	var masterPolicies *policiesCfg
+	if _, exists := p.mergeableIngs.Master.Ingress.Annotations[PoliciesAnnotation]; exists {
+		generated := &ingressPolicies{}
+		generated.cfg, generated.oidcProviders, generated.warnings = generateIngressPolicies(masterParams)
+		masterParams.policies = generated
+		masterPolicies = &generated.cfg
+	}
+
+	masterNginxCfg, warnings := generateNginxCfg(masterParams)
 
 	// because p.mergeableIngs.Master.Ingress is a deepcopy of the original master
 	// we need to change the key in the warnings to the original master
@@ -667,6 +897,10 @@ func generateNginxCfgForMergeableIngresses(p NginxCfgParams) (version1.IngressNg
 	masterServer.Locations = []version1.Location{}
 
 	upstreams = append(upstreams, masterNginxCfg.Upstreams...)
+	limitReqZones = append(limitReqZones, masterNginxCfg.LimitReqZones...)
+	maps := masterNginxCfg.Maps
+	authJWTClaimSets := masterNginxCfg.AuthJWTClaimSets
+	jwksAuthEnabled := masterNginxCfg.JWKSAuthEnabled
 
 	if masterNginxCfg.Keepalive != "" {
 		keepalive = masterNginxCfg.Keepalive
@@ -705,6 +939,8 @@ func generateNginxCfgForMergeableIngresses(p NginxCfgParams) (version1.IngressNg
 			isResolverConfigured:      p.isResolverConfigured,
 			isWildcardEnabled:         p.isWildcardEnabled,
 			ingressControllerReplicas: p.ingressControllerReplicas,
+			masterPolicies:            masterPolicies,
+			now:                       p.now,
 		})
 		warnings.Add(minionWarnings)
 
@@ -716,8 +952,12 @@ func generateNginxCfgForMergeableIngresses(p NginxCfgParams) (version1.IngressNg
 		}
 
 		for _, server := range nginxCfg.Servers {
+			rejectedOIDC := addMinionPoliciesToMasterServer(&masterServer, server, originalMinion, warnings)
 			for _, loc := range server.Locations {
 				loc.MinionIngress = &nginxCfg.Ingress
+				if loc.Policies != nil && loc.Policies.OIDC != nil && rejectedOIDC[loc.Policies.OIDC] {
+					loc.Policies = &version1.Policies{ErrorReturn: &version2.Return{Code: 500}}
+				}
 				locations = append(locations, loc)
 			}
 			for hcName, healthCheck := range server.HealthChecks {
@@ -727,7 +967,14 @@ func generateNginxCfgForMergeableIngresses(p NginxCfgParams) (version1.IngressNg
 		}
 
 		upstreams = append(upstreams, nginxCfg.Upstreams...)
-		limitReqZones = append(limitReqZones, nginxCfg.LimitReqZones...)
+		for _, zone := range nginxCfg.LimitReqZones {
+			if !limitReqZoneExists(limitReqZones, zone.Name) {
+				limitReqZones = append(limitReqZones, zone)
+			}
+		}
+		maps = append(maps, nginxCfg.Maps...)
+		authJWTClaimSets = append(authJWTClaimSets, nginxCfg.AuthJWTClaimSets...)
+		jwksAuthEnabled = jwksAuthEnabled || nginxCfg.JWKSAuthEnabled
 	}
 
 	masterServer.HealthChecks = healthChecks
@@ -742,9 +989,50 @@ func generateNginxCfgForMergeableIngresses(p NginxCfgParams) (version1.IngressNg
 		DynamicSSLReloadEnabled: p.staticParams.DynamicSSLReload,
 		StaticSSLPath:           p.staticParams.StaticSSLPath,
 		LimitReqZones:           limitReqZones,
+		Maps:                    removeDuplicateMaps(maps),
+		AuthJWTClaimSets:        removeDuplicateAuthJWTClaimSets(authJWTClaimSets),
+		JWKSAuthEnabled:         jwksAuthEnabled,
 	}, warnings
 }
 
+// addMinionPoliciesToMasterServer adds the server level Policy configuration of a minion to the server of the master.
+// An OIDC provider of the minion is rejected if its locations collide with the locations of another provider.
+// It returns the rejected OIDC providers.
+func addMinionPoliciesToMasterServer(masterServer *version1.Server, minionServer version1.Server, minion *networking.Ingress, warnings Warnings) map[*version2.OIDC]bool {
+	for key, auth := range minionServer.JWTAuthList {
+		if masterServer.JWTAuthList == nil {
+			masterServer.JWTAuthList = make(map[string]*version2.JWTAuth)
+		}
+		if _, exists := masterServer.JWTAuthList[key]; !exists {
+			masterServer.JWTAuthList[key] = auth
+		}
+	}
+
+	if minionServer.APIKeyEnabled {
+		masterServer.APIKeyEnabled = true
+	}
+
+	rejected := make(map[*version2.OIDC]bool)
+	for _, provider := range minionServer.OIDCProviders {
+		collision := ""
+		for _, existing := range masterServer.OIDCProviders {
+			for _, uri := range []string{provider.RedirectURI, provider.LogoutURI} {
+				if uri == existing.RedirectURI || uri == existing.LogoutURI {
+					collision = uri
+				}
+			}
+		}
+		if collision != "" {
+			warnings.AddWarningf(minion, "The location %s of the OIDC provider %s collides with a location of another OIDC provider", collision, provider.Key)
+			rejected[provider] = true
+			continue
+		}
+		masterServer.OIDCProviders = append(masterServer.OIDCProviders, provider)
+	}
+
+	return rejected
+}
+
 func limitReqZoneExists(zones []version1.LimitReqZone, zoneName string) bool {
 	for _, zone := range zones {
 		if zone.Name == zoneName {
@@ -7,10 +7,13 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version1"
+	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
 	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	v1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -2089,7 +2092,7 @@ func TestAddSSLConfig(t *testing.T) {
 		var server version1.Server
 
 		// it is ok to use nil as the owner
-		warnings := addSSLConfig(&server, nil, test.host, test.tls, test.secretRefs, test.isWildcardEnabled)
+		warnings := addSSLConfig(&server, nil, test.host, test.tls, test.secretRefs, test.isWildcardEnabled, time.Now())
 
 		if diff := cmp.Diff(test.expectedServer, server); diff != "" {
 			t.Errorf("addSSLConfig() '%s' mismatch (-want +got):\n%s", test.msg, diff)
@@ -2596,3 +2599,337 @@ func TestScaleRatelimit(t *testing.T) {
 		}
 	}
 }
+
+func createIngressPolicies() map[string]*conf_v1.Policy {
+	return map[string]*conf_v1.Policy{
+		"default/allow-policy": {
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "allow-policy",
+				Namespace: "default",
+			},
+			Spec: conf_v1.PolicySpec{
+				AccessControl: &conf_v1.AccessControl{
+					Allow: []string{"10.0.0.0/8"},
+				},
+			},
+		},
+		"default/deny-policy": {
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "deny-policy",
+				Namespace: "default",
+			},
+			Spec: conf_v1.PolicySpec{
+				AccessControl: &conf_v1.AccessControl{
+					Deny: []string{"127.0.0.1"},
+				},
+			},
+		},
+		"default/rate-limit-policy": {
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "rate-limit-policy",
+				Namespace: "default",
+			},
+			Spec: conf_v1.PolicySpec{
+				RateLimit: &conf_v1.RateLimit{
+					Key:      "${binary_remote_addr}",
+					ZoneSize: "10M",
+					Rate:     "10r/s",
+				},
+			},
+		},
+		"default/basic-auth-policy": {
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "basic-auth-policy",
+				Namespace: "default",
+			},
+			Spec: conf_v1.PolicySpec{
+				BasicAuth: &conf_v1.BasicAuth{
+					Secret: "htpasswd-secret",
+					Realm:  "My Cafe",
+				},
+			},
+		},
+		"default/waf-policy": {
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "waf-policy",
+				Namespace: "default",
+			},
+			Spec: conf_v1.PolicySpec{
+				WAF: &conf_v1.WAF{
+					Enable: true,
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateNginxCfgForPolicies(t *testing.T) {
+	t.Parallel()
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/policies"] = "allow-policy,rate-limit-policy"
+	cafeIngressEx.Policies = createIngressPolicies()
+
+	expectedZones := []version1.LimitReqZone{
+		{
+			Name: "pol_rl_default_rate-limit-policy_default_cafe-ingress",
+			Key:  "${binary_remote_addr}",
+			Size: "10M",
+			Rate: "10r/s",
+		},
+	}
+	expectedPolicies := &version1.Policies{
+		Allow: []string{"10.0.0.0/8"},
+		LimitReqOptions: version2.LimitReqOptions{
+			LogLevel:   "error",
+			RejectCode: 503,
+		},
+		LimitReqs: []version2.LimitReq{
+			{
+				ZoneName: "pol_rl_default_rate-limit-policy_default_cafe-ingress",
+			},
+		},
+	}
+
+	isPlus := false
+	configParams := NewDefaultConfigParams(context.Background(), isPlus)
+
+	result, warnings := generateNginxCfg(NginxCfgParams{
+		ingEx:         &cafeIngressEx,
+		BaseCfgParams: configParams,
+		staticParams:  &StaticConfigParams{},
+		isPlus:        isPlus,
+	})
+
+	if !reflect.DeepEqual(result.LimitReqZones, expectedZones) {
+		t.Errorf("generateNginxCfg returned \n%v,  but expected \n%v", result.LimitReqZones, expectedZones)
+	}
+	for _, server := range result.Servers {
+		if server.PoliciesErrorReturn != nil {
+			t.Errorf("generateNginxCfg returned PoliciesErrorReturn %v for server %s, but expected nil", server.PoliciesErrorReturn, server.Name)
+		}
+		for _, location := range server.Locations {
+			if diff := cmp.Diff(expectedPolicies, location.Policies); diff != "" {
+				t.Errorf("generateNginxCfg returned unexpected Policies for location %s (-want +got):\n%s", location.Path, diff)
+			}
+		}
+	}
+	if len(warnings) != 0 {
+		t.Errorf("generateNginxCfg returned warnings: %v", warnings)
+	}
+}
+
+func TestGenerateNginxCfgForPoliciesIgnoresAnnotations(t *testing.T) {
+	t.Parallel()
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/policies"] = "basic-auth-policy"
+	cafeIngressEx.Ingress.Annotations["nginx.org/basic-auth-secret"] = "cafe-htpasswd"
+	cafeIngressEx.Policies = createIngressPolicies()
+	cafeIngressEx.SecretRefs["default/htpasswd-secret"] = &secrets.SecretReference{
+		Secret: &v1.Secret{
+			Type: secrets.SecretTypeHtpasswd,
+		},
+		Path: "/etc/nginx/secrets/default-htpasswd-secret",
+	}
+
+	expectedBasicAuth := &version2.BasicAuth{
+		Secret: "/etc/nginx/secrets/default-htpasswd-secret",
+		Realm:  "My Cafe",
+	}
+	expectedWarnings := Warnings{
+		cafeIngressEx.Ingress: {
+			"The nginx.org/basic-auth-secret annotation is ignored because a basicAuth policy is applied",
+		},
+	}
+
+	isPlus := false
+	configParams := NewDefaultConfigParams(context.Background(), isPlus)
+
+	result, warnings := generateNginxCfg(NginxCfgParams{
+		ingEx:         &cafeIngressEx,
+		BaseCfgParams: configParams,
+		staticParams:  &StaticConfigParams{},
+		isPlus:        isPlus,
+	})
+
+	for _, server := range result.Servers {
+		if server.BasicAuth != nil {
+			t.Errorf("generateNginxCfg returned BasicAuth %v for server %s, but expected nil", server.BasicAuth, server.Name)
+		}
+		for _, location := range server.Locations {
+			if location.BasicAuth != nil {
+				t.Errorf("generateNginxCfg returned BasicAuth %v for location %s, but expected nil", location.BasicAuth, location.Path)
+			}
+			if !reflect.DeepEqual(location.Policies.BasicAuth, expectedBasicAuth) {
+				t.Errorf("generateNginxCfg returned \n%v,  but expected \n%v", location.Policies.BasicAuth, expectedBasicAuth)
+			}
+		}
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("generateNginxCfg returned warnings %v, but expected %v", warnings, expectedWarnings)
+	}
+}
+
+func TestGenerateNginxCfgForPoliciesWithErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		annotation string
+		tls        bool
+		msg        string
+	}{
+		{
+			annotation: "missing-policy",
+			tls:        true,
+			msg:        "missing policy",
+		},
+		{
+			annotation: "waf-policy",
+			tls:        true,
+			msg:        "policy type not supported by Ingress",
+		},
+		{
+			annotation: "ingress-mtls-policy",
+			tls:        false,
+			msg:        "ingressMTLS policy without TLS",
+		},
+	}
+
+	for _, test := range tests {
+		cafeIngressEx := createCafeIngressEx()
+		cafeIngressEx.Ingress.Annotations["nginx.org/policies"] = test.annotation
+		if !test.tls {
+			cafeIngressEx.Ingress.Spec.TLS = nil
+		}
+		cafeIngressEx.Policies = createIngressPolicies()
+		cafeIngressEx.Policies["default/ingress-mtls-policy"] = &conf_v1.Policy{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "ingress-mtls-policy",
+				Namespace: "default",
+			},
+			Spec: conf_v1.PolicySpec{
+				IngressMTLS: &conf_v1.IngressMTLS{
+					ClientCertSecret: "ingress-mtls-secret",
+				},
+			},
+		}
+		cafeIngressEx.SecretRefs["default/ingress-mtls-secret"] = &secrets.SecretReference{
+			Secret: &v1.Secret{
+				Type: secrets.SecretTypeCA,
+			},
+			Path: "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt",
+		}
+
+		isPlus := false
+		configParams := NewDefaultConfigParams(context.Background(), isPlus)
+
+		result, warnings := generateNginxCfg(NginxCfgParams{
+			ingEx:         &cafeIngressEx,
+			BaseCfgParams: configParams,
+			staticParams:  &StaticConfigParams{},
+			isPlus:        isPlus,
+		})
+
+		expectedReturn := &version2.Return{Code: 500}
+		for _, server := range result.Servers {
+			if !reflect.DeepEqual(server.PoliciesErrorReturn, expectedReturn) {
+				t.Errorf("generateNginxCfg returned PoliciesErrorReturn %v, but expected %v for the case of %s", server.PoliciesErrorReturn, expectedReturn, test.msg)
+			}
+		}
+		if len(warnings[cafeIngressEx.Ingress]) != 1 {
+			t.Errorf("generateNginxCfg returned warnings %v, but expected 1 warning for the case of %s", warnings, test.msg)
+		}
+	}
+}
+
+func TestGenerateNginxCfgForMergeableIngressesForPolicies(t *testing.T) {
+	t.Parallel()
+	mergeableIngresses := createMergeableCafeIngress()
+	policies := createIngressPolicies()
+
+	mergeableIngresses.Master.Ingress.Annotations["nginx.org/policies"] = "allow-policy,rate-limit-policy"
+	mergeableIngresses.Master.Policies = policies
+	mergeableIngresses.Minions[0].Ingress.Annotations["nginx.org/policies"] = "deny-policy"
+	mergeableIngresses.Minions[0].Policies = policies
+
+	expectedZones := []version1.LimitReqZone{
+		{
+			Name: "pol_rl_default_rate-limit-policy_default_cafe-ingress-master",
+			Key:  "${binary_remote_addr}",
+			Size: "10M",
+			Rate: "10r/s",
+		},
+	}
+	masterLimitReqOptions := version2.LimitReqOptions{
+		LogLevel:   "error",
+		RejectCode: 503,
+	}
+	masterLimitReqs := []version2.LimitReq{
+		{
+			ZoneName: "pol_rl_default_rate-limit-policy_default_cafe-ingress-master",
+		},
+	}
+	expectedPolicies := map[string]*version1.Policies{
+		"cafe-ingress-coffee-minion": {
+			Deny:            []string{"127.0.0.1"},
+			LimitReqOptions: masterLimitReqOptions,
+			LimitReqs:       masterLimitReqs,
+		},
+		"cafe-ingress-tea-minion": {
+			Allow:           []string{"10.0.0.0/8"},
+			LimitReqOptions: masterLimitReqOptions,
+			LimitReqs:       masterLimitReqs,
+		},
+	}
+
+	isPlus := false
+	configParams := NewDefaultConfigParams(context.Background(), isPlus)
+
+	result, warnings := generateNginxCfgForMergeableIngresses(NginxCfgParams{
+		mergeableIngs: mergeableIngresses,
+		BaseCfgParams: configParams,
+		isPlus:        isPlus,
+		staticParams:  &StaticConfigParams{},
+	})
+
+	if !reflect.DeepEqual(result.LimitReqZones, expectedZones) {
+		t.Errorf("generateNginxCfgForMergeableIngresses returned \n%v,  but expected \n%v", result.LimitReqZones, expectedZones)
+	}
+	for _, server := range result.Servers {
+		for _, location := range server.Locations {
+			expected := expectedPolicies[location.MinionIngress.Name]
+			if diff := cmp.Diff(expected, location.Policies); diff != "" {
+				t.Errorf("generateNginxCfgForMergeableIngresses returned unexpected Policies for minion %s (-want +got):\n%s", location.MinionIngress.Name, diff)
+			}
+		}
+	}
+	if len(warnings) != 0 {
+		t.Errorf("generateNginxCfgForMergeableIngresses returned warnings: %v", warnings)
+	}
+}
+
+func TestGenerateNginxCfgForMergeableIngressesReportsMasterPolicyWarningsOnce(t *testing.T) {
+	t.Parallel()
+	mergeableIngresses := createMergeableCafeIngress()
+	master := mergeableIngresses.Master.Ingress
+
+	mergeableIngresses.Master.Ingress.Annotations["nginx.org/policies"] = "allow-policy,missing-policy"
+	mergeableIngresses.Master.Policies = createIngressPolicies()
+
+	isPlus := false
+	configParams := NewDefaultConfigParams(context.Background(), isPlus)
+
+	_, warnings := generateNginxCfgForMergeableIngresses(NginxCfgParams{
+		mergeableIngs: mergeableIngresses,
+		BaseCfgParams: configParams,
+		isPlus:        isPlus,
+		staticParams:  &StaticConfigParams{},
+	})
+
+	expectedWarnings := Warnings{
+		master: {
+			"Policy default/missing-policy is missing or invalid",
+		},
+	}
+	if diff := cmp.Diff(expectedWarnings, warnings); diff != "" {
+		t.Errorf("generateNginxCfgForMergeableIngresses returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
@@ -6,6 +6,12 @@ function hash(r) {
     return hashed_value;
 }
 
+// The expiry time of a key is checked on every request, so a key expires without a reload of NGINX.
+function expired(r) {
+    const expires = r.variables.apikey_client_expires;
+    return Boolean(expires) && Date.now() >= Number(expires) * 1000;
+}
+
 function validate(r) {
     const client_name_map = r.variables['apikey_auth_local_map'];
     const client_name = r.variables[client_name_map];
@@ -17,6 +23,9 @@ function validate(r) {
     else if (!client_name) {
         r.return(403, "403")
     }
+    else if (expired(r)) {
+        r.return(403, "403")
+    }
     else {
         r.return(204, "204");
     }
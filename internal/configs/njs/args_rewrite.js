@@ -0,0 +1,20 @@
+// The names of the arguments to remove are set in $args_rewrite_remove, separated by spaces.
+function decodeName(name) {
+    try {
+        return decodeURIComponent(name.replace(/\+/g, ' '));
+    } catch (e) {
+        return name;
+    }
+}
+
+// Returns the arguments of the request without every occurrence of the arguments to remove.
+function remove(r) {
+    const names = r.variables.args_rewrite_remove.split(' ');
+    const args = r.variables.args || '';
+
+    return args.split('&').filter(function(arg) {
+        return arg !== '' && names.indexOf(decodeName(arg.split('=')[0])) === -1;
+    }).join('&');
+}
+
+export default { remove };
@@ -0,0 +1,229 @@
+import constantTime from 'constant_time.js';
+const c = require('crypto')
+
+// The cookie of a client that passed the challenge holds the expiration time of the cookie and the signature
+// of the time, the address and the User-Agent of the client, separated by a dot.
+function signature(r, expires) {
+    const key = Buffer.from(r.variables.challenge_secret_key, 'base64');
+    return c.createHmac('sha256', key)
+        .update(expires + '|' + r.variables.remote_addr + '|' + (r.headersIn['User-Agent'] || ''))
+        .digest('hex');
+}
+
+function verified(r) {
+    const cookie = r.variables['cookie_' + r.variables.challenge_cookie_name];
+    if (!cookie) {
+        return false;
+    }
+    const parts = cookie.split('.');
+    if (parts.length !== 2 || !(Number(parts[0]) * 1000 > Date.now())) {
+        return false;
+    }
+    return constantTime.equal(parts[1], signature(r, parts[0]));
+}
+
+// The failed challenges and the blocked clients are kept in the keyval zones in NGINX Plus
+// and in the shared dictionaries in NGINX.
+function useKeyval(r) {
+    return r.variables.challenge_store === 'keyval';
+}
+
+function blocking(r) {
+    return Number(r.variables.challenge_block_threshold) > 0;
+}
+
+function blocked(r) {
+    if (!blocking(r)) {
+        return false;
+    }
+    const name = 'challenge_blocked_' + r.variables.challenge_key;
+    if (useKeyval(r)) {
+        return r.variables[name] === '1';
+    }
+    return ngx.shared[name].has(r.variables.remote_addr);
+}
+
+// fail counts a failed verification of the client and blocks the client once the number of its failed verifications
+// reaches the threshold. It returns true if the client is blocked.
+function fail(r) {
+    if (!blocking(r)) {
+        return false;
+    }
+    const failuresName = 'challenge_failures_' + r.variables.challenge_key;
+    const blockedName = 'challenge_blocked_' + r.variables.challenge_key;
+    const addr = r.variables.remote_addr;
+
+    let failures;
+    if (useKeyval(r)) {
+        failures = Number(r.variables[failuresName] || 0) + 1;
+        r.variables[failuresName] = String(failures);
+    } else {
+        failures = ngx.shared[failuresName].incr(addr, 1, 0);
+    }
+    if (failures < Number(r.variables.challenge_block_threshold)) {
+        return false;
+    }
+
+    if (useKeyval(r)) {
+        r.variables[blockedName] = '1';
+    } else {
+        ngx.shared[blockedName].set(addr, '1');
+    }
+    return true;
+}
+
+// pass resets the failed verifications of the client.
+function pass(r) {
+    if (!blocking(r)) {
+        return;
+    }
+    const failuresName = 'challenge_failures_' + r.variables.challenge_key;
+    if (useKeyval(r)) {
+        r.variables[failuresName] = '0';
+    } else {
+        ngx.shared[failuresName].delete(r.variables.remote_addr);
+    }
+}
+
+// status returns an empty string for the requests that are passed and 1 for the requests that are challenged.
+function status(r) {
+    const key = r.variables.challenge_key;
+    if (!key || r.variables['challenge_exempt_' + key] === '1') {
+        return '';
+    }
+    if (blocked(r)) {
+        return '1';
+    }
+    return verified(r) ? '' : '1';
+}
+
+// The javascript challenge gets the cookie only with a proof of work. The page gets a token that holds its expiration
+// time and a signature of that time, the address and the User-Agent of the client. The script of the page finds a number,
+// such that the SHA-256 hash of the token and the number starts with PROOF_PREFIX, and returns them in the proof cookie.
+const PROOF_PREFIX = '0000';
+const PROOF_LIFETIME = 60;
+
+function proofCookieName(r) {
+    return r.variables.challenge_cookie_name + '_proof';
+}
+
+function token(r) {
+    const expires = String(Math.floor(Date.now() / 1000) + PROOF_LIFETIME);
+    return expires + '.' + signature(r, 'proof|' + expires);
+}
+
+// proof returns true if the proof cookie has a valid token and a number that solves it, false if the proof is invalid
+// or expired, and undefined if the client didn't send a proof.
+function proof(r) {
+    const cookie = r.variables['cookie_' + proofCookieName(r)];
+    if (!cookie) {
+        return undefined;
+    }
+    const parts = cookie.split('.');
+    if (parts.length !== 3 || !(Number(parts[0]) * 1000 > Date.now())) {
+        return false;
+    }
+    if (!constantTime.equal(parts[1], signature(r, 'proof|' + parts[0]))) {
+        return false;
+    }
+    return c.createHash('sha256').update(cookie).digest('hex').startsWith(PROOF_PREFIX);
+}
+
+// The script of the page is self-contained, because crypto.subtle is not available on the pages served over HTTP.
+const SOLVER = `function sha256(s) {
+    function rotr(x, n) { return (x >>> n) | (x << (32 - n)); }
+    var max = Math.pow(2, 32), h = [], k = [], composite = {}, w = [], i, j, out = '';
+    for (var n = 2, p = 0; p < 64; n++) {
+        if (composite[n]) { continue; }
+        for (i = 0; i < 313; i += n) { composite[i] = n; }
+        h[p] = (Math.pow(n, 1 / 2) * max) | 0;
+        k[p++] = (Math.pow(n, 1 / 3) * max) | 0;
+    }
+    var bits = s.length * 8;
+    s += '\\x80';
+    while (s.length % 64 - 56) { s += '\\x00'; }
+    for (i = 0; i < s.length; i++) { w[i >> 2] |= s.charCodeAt(i) << ((3 - i) % 4) * 8; }
+    w[w.length] = (bits / max) | 0;
+    w[w.length] = bits;
+    for (j = 0; j < w.length;) {
+        var m = w.slice(j, j += 16), old = h;
+        h = h.slice(0, 8);
+        for (i = 0; i < 64; i++) {
+            var a = h[0], e = h[4], m15 = m[i - 15], m2 = m[i - 2];
+            if (i >= 16) {
+                m[i] = (m[i - 16] + (rotr(m15, 7) ^ rotr(m15, 18) ^ (m15 >>> 3)) + m[i - 7] +
+                    (rotr(m2, 17) ^ rotr(m2, 19) ^ (m2 >>> 10))) | 0;
+            }
+            var t1 = h[7] + (rotr(e, 6) ^ rotr(e, 11) ^ rotr(e, 25)) + ((e & h[5]) ^ (~e & h[6])) + k[i] + m[i];
+            var t2 = (rotr(a, 2) ^ rotr(a, 13) ^ rotr(a, 22)) + ((a & h[1]) ^ (a & h[2]) ^ (h[1] & h[2]));
+            h = [(t1 + t2) | 0].concat(h);
+            h[4] = (h[4] + t1) | 0;
+        }
+        for (i = 0; i < 8; i++) { h[i] = (h[i] + old[i]) | 0; }
+    }
+    for (i = 0; i < 8; i++) {
+        for (j = 3; j >= 0; j--) {
+            var b = (h[i] >> (j * 8)) & 255;
+            out += (b < 16 ? '0' : '') + b.toString(16);
+        }
+    }
+    return out;
+}`;
+
+function page(r) {
+    let cookie = proofCookieName(r) + '=';
+    let attributes = '; Path=/; Max-Age=' + PROOF_LIFETIME + '; SameSite=Lax';
+    if (r.variables.scheme === 'https') {
+        attributes += '; Secure';
+    }
+    return '<!DOCTYPE html><html><head><meta charset="utf-8"><title>Checking your browser</title></head><body>' +
+        '<noscript>Please enable JavaScript to continue.</noscript>' +
+        '<script>' + SOLVER +
+        'var t = ' + JSON.stringify(token(r)) + ', n = 0;' +
+        'while (sha256(t + "." + n).indexOf(' + JSON.stringify(PROOF_PREFIX) + ') !== 0) { n++; }' +
+        'document.cookie = ' + JSON.stringify(cookie) + ' + t + "." + n + ' + JSON.stringify(attributes) + ';' +
+        'location.reload();</script>' +
+        '</body></html>';
+}
+
+function issue(r) {
+    if (blocked(r)) {
+        r.return(403);
+        return;
+    }
+
+    // Only the failed or expired verifications are counted, so the clients aren't blocked for receiving a challenge.
+    const javascript = r.variables.challenge_type !== 'cookie';
+    const proven = javascript ? proof(r) : undefined;
+    const failed = proven === false ||
+        (proven === undefined && Boolean(r.variables['cookie_' + r.variables.challenge_cookie_name]));
+    if (failed && fail(r)) {
+        r.return(403);
+        return;
+    }
+
+    r.headersOut['Cache-Control'] = 'no-store';
+    if (javascript && !proven) {
+        r.headersOut['Content-Type'] = 'text/html';
+        r.return(503, page(r));
+        return;
+    }
+
+    const lifetime = Number(r.variables.challenge_cookie_lifetime);
+    const expires = String(Math.floor(Date.now() / 1000) + lifetime);
+    let cookie = r.variables.challenge_cookie_name + '=' + expires + '.' + signature(r, expires) +
+        '; Path=/; Max-Age=' + lifetime + '; SameSite=Lax; HttpOnly';
+    if (r.variables.scheme === 'https') {
+        cookie += '; Secure';
+    }
+
+    const cookies = [cookie];
+    if (javascript) {
+        pass(r);
+        cookies.push(proofCookieName(r) + '=; Path=/; Max-Age=0');
+    }
+    r.headersOut['Set-Cookie'] = cookies;
+    r.return(302, r.variables.request_uri);
+}
+
+export default { status, issue };
@@ -0,0 +1,14 @@
+// The values are compared in constant time, so the time of a mismatch doesn't reveal the expected value,
+// for example, a signature.
+function equal(a, b) {
+    if (a.length !== b.length) {
+        return false;
+    }
+    let diff = 0;
+    for (let i = 0; i < a.length; i++) {
+        diff |= a.charCodeAt(i) ^ b.charCodeAt(i);
+    }
+    return diff === 0;
+}
+
+export default { equal };
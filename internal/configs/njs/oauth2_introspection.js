@@ -0,0 +1,114 @@
+const c = require('crypto')
+
+// The fields of the introspection response are passed to the location of the policy in the headers
+// X-Introspection-Field-<index> of the response, in the order of the fields in $oauth2_introspection_fields.
+const FIELD_HEADER_PREFIX = 'X-Introspection-Field-';
+
+function bearerToken(r) {
+    const authorization = r.headersIn['Authorization'];
+    if (!authorization) {
+        return '';
+    }
+    const parts = authorization.match(/^Bearer\s+(\S+)$/i);
+    return parts ? parts[1] : '';
+}
+
+function words(s) {
+    return s ? s.split(' ').filter(function(w) { return w !== ''; }) : [];
+}
+
+// The fields of nested objects are separated by dots.
+function field(response, name) {
+    return name.split('.').reduce(function(value, key) {
+        return value !== undefined && value !== null ? value[key] : undefined;
+    }, response);
+}
+
+function check(r, response) {
+    if (response.active !== true) {
+        return 401;
+    }
+    if (typeof response.exp === 'number' && response.exp * 1000 <= Date.now()) {
+        return 401;
+    }
+
+    const audiences = words(r.variables.oauth2_introspection_audiences);
+    if (audiences.length > 0) {
+        const aud = Array.isArray(response.aud) ? response.aud : [response.aud];
+        if (!aud.some(function(a) { return audiences.includes(a); })) {
+            return 401;
+        }
+    }
+
+    const granted = words(response.scope);
+    if (!words(r.variables.oauth2_introspection_scopes).every(function(s) { return granted.includes(s); })) {
+        return 403;
+    }
+
+    return 204;
+}
+
+function respond(r, response) {
+    const status = check(r, response);
+    if (status === 204) {
+        words(r.variables.oauth2_introspection_fields).forEach(function(name, i) {
+            const value = field(response, name);
+            if (value !== undefined && value !== null) {
+                const s = typeof value === 'object' ? JSON.stringify(value) : String(value);
+                r.headersOut[FIELD_HEADER_PREFIX + i] = s.replace(/[\r\n]/g, ' ');
+            }
+        });
+    }
+    r.return(status);
+}
+
+async function validate(r) {
+    // A location has only one auth_request, so the API key of an APIKey policy of the location or the server
+    // is validated before the token.
+    if (r.variables.apikey_auth_local_map) {
+        const apikey = await r.subrequest('/_validate_apikey_njs');
+        if (apikey.status !== 204) {
+            r.return(apikey.status);
+            return;
+        }
+    }
+
+    const token = bearerToken(r);
+    if (!token) {
+        r.return(401);
+        return;
+    }
+
+    // The results are cached by the hash of the token, so that the tokens are not kept in the shared memory.
+    const cache = ngx.shared[r.variables.oauth2_introspection_zone];
+    const key = c.createHash('sha256').update(token).digest('hex');
+    const cached = cache.get(key);
+    if (cached !== undefined) {
+        respond(r, JSON.parse(cached));
+        return;
+    }
+
+    const reply = await r.subrequest(r.variables.oauth2_introspection_endpoint, {
+        method: 'POST',
+        body: 'token=' + encodeURIComponent(token) + '&token_type_hint=access_token',
+    });
+    if (reply.status !== 200) {
+        r.error('OAuth2 introspection endpoint returned the status ' + reply.status);
+        r.return(500);
+        return;
+    }
+
+    let response;
+    try {
+        response = JSON.parse(reply.responseText);
+    } catch (e) {
+        r.error('OAuth2 introspection endpoint returned an invalid response: ' + e);
+        r.return(500);
+        return;
+    }
+
+    cache.set(key, JSON.stringify(response));
+    respond(r, response);
+}
+
+export default { validate };
@@ -0,0 +1,66 @@
+const c = require('crypto')
+
+// The object storage locations pass only GET and HEAD requests, so the payload is not signed.
+const UNSIGNED_PAYLOAD = 'UNSIGNED-PAYLOAD';
+const SIGNED_HEADERS = 'host;x-amz-content-sha256;x-amz-date';
+
+// The time of the request, so that the date header and the signature of a request always match.
+function now(r) {
+    return new Date(Math.floor(parseFloat(r.variables.msec)) * 1000);
+}
+
+function amzDate(d) {
+    return d.toISOString().replace(/[-:]/g, '').replace(/\.\d{3}/, '');
+}
+
+function encodeSegment(s) {
+    return encodeURIComponent(s).replace(/[!'()*]/g, function(ch) {
+        return '%' + ch.charCodeAt(0).toString(16).toUpperCase();
+    });
+}
+
+function uri(r) {
+    const key = r.variables.uri.split('/').map(encodeSegment).join('/');
+    return '/' + r.variables.object_storage_bucket + key;
+}
+
+function date(r) {
+    return amzDate(now(r));
+}
+
+function authorization(r) {
+    const v = r.variables;
+    const requestDate = amzDate(now(r));
+    const day = requestDate.substring(0, 8);
+    const scope = [day, v.object_storage_region, 's3', 'aws4_request'];
+
+    const canonicalRequest = [
+        r.method,
+        uri(r),
+        '',
+        'host:' + v.object_storage_host,
+        'x-amz-content-sha256:' + UNSIGNED_PAYLOAD,
+        'x-amz-date:' + requestDate,
+        '',
+        SIGNED_HEADERS,
+        UNSIGNED_PAYLOAD,
+    ].join('\n');
+
+    const stringToSign = [
+        'AWS4-HMAC-SHA256',
+        requestDate,
+        scope.join('/'),
+        c.createHash('sha256').update(canonicalRequest).digest('hex'),
+    ].join('\n');
+
+    let key = 'AWS4' + v.object_storage_secret_access_key;
+    for (const part of scope) {
+        key = c.createHmac('sha256', key).update(part).digest();
+    }
+    const signature = c.createHmac('sha256', key).update(stringToSign).digest('hex');
+
+    return 'AWS4-HMAC-SHA256 Credential=' + v.object_storage_access_key_id + '/' + scope.join('/') +
+        ', SignedHeaders=' + SIGNED_HEADERS + ', Signature=' + signature;
+}
+
+export default { uri, date, authorization };
@@ -0,0 +1,59 @@
+import constantTime from 'constant_time.js';
+const c = require('crypto')
+
+function fresh(r) {
+    const header = r.variables.signature_verification_timestamp_header;
+    if (!header) {
+        return true;
+    }
+    const timestamp = Number(r.headersIn[header]);
+    if (!timestamp) {
+        return false;
+    }
+    const tolerance = Number(r.variables.signature_verification_timestamp_tolerance);
+    return Math.abs(Date.now() / 1000 - timestamp) <= tolerance;
+}
+
+// The signed string is the format of $signature_verification_payload with the placeholders {timestamp} and {body}
+// replaced by the value of the timestamp header and the body, so a request can't be replayed with another timestamp.
+function sign(r, hmac) {
+    const header = r.variables.signature_verification_timestamp_header;
+    const format = r.variables.signature_verification_payload
+        .replace('{timestamp}', header ? r.headersIn[header] : '');
+    const i = format.indexOf('{body}');
+    return hmac.update(format.slice(0, i))
+        .update(r.requestBuffer || '')
+        .update(format.slice(i + '{body}'.length));
+}
+
+function verify(r) {
+    // The body must be in memory to be signed. It is written to a file only if it is larger than client_body_buffer_size.
+    if (r.variables.request_body_file) {
+        r.return(413);
+        return;
+    }
+
+    const prefix = r.variables.signature_verification_prefix;
+    const value = r.headersIn[r.variables.signature_verification_header];
+    if (!value || !value.startsWith(prefix) || !fresh(r)) {
+        r.return(401);
+        return;
+    }
+
+    const encoding = r.variables.signature_verification_encoding;
+    const key = Buffer.from(r.variables.signature_verification_key, 'base64');
+    const expected = sign(r, c.createHmac(r.variables.signature_verification_algorithm, key)).digest(encoding);
+
+    let signature = value.slice(prefix.length).trim();
+    if (encoding === 'hex') {
+        signature = signature.toLowerCase();
+    }
+    if (!constantTime.equal(signature, expected)) {
+        r.return(401);
+        return;
+    }
+
+    r.internalRedirect(r.variables.signature_verification_location);
+}
+
+export default { verify };
@@ -5,6 +5,26 @@
  */
 export default {auth, codeExchange, validateIdToken, logout};
 
+// The variables of the key-value zones are defined for every OIDC provider of a VirtualServer
+// and are suffixed with the name of the provider.
+function getSessionVariable(r, name) {
+    return r.variables[name + "_" + r.variables.oidc_provider];
+}
+
+function setSessionVariable(r, name, value) {
+    r.variables[name + "_" + r.variables.oidc_provider] = value;
+}
+
+// The names of the nonce and redirect cookies are the name of the session cookie followed by a suffix.
+function getCookie(r, suffix) {
+    return r.variables["cookie_" + r.variables.oidc_cookie_name + suffix];
+}
+
+function makeCookie(r, suffix, value) {
+    return r.variables.oidc_cookie_name + suffix + "=" + value + "; " + r.variables.oidc_cookie_flags +
+        " SameSite=" + r.variables.oidc_cookie_samesite + ";" + r.variables.oidc_cookie_domain;
+}
+
 function retryOriginalRequest(r) {
     delete r.headersOut["WWW-Authenticate"]; // Remove evidence of original failed auth_jwt
     r.internalRedirect(r.variables.uri + r.variables.is_args + (r.variables.args || ''));
@@ -13,7 +33,7 @@ function retryOriginalRequest(r) {
 // If the ID token has not been synced yet, poll the variable every 100ms until
 // get a value or after a timeout.
 function waitForSessionSync(r, timeLeft) {
-    if (r.variables.session_jwt) {
+    if (getSessionVariable(r, "session_jwt")) {
         retryOriginalRequest(r);
     } else if (timeLeft > 0) {
         setTimeout(waitForSessionSync, 100, r, timeLeft - 100);
@@ -24,12 +44,13 @@ function waitForSessionSync(r, timeLeft) {
 
 function auth(r, afterSyncCheck) {
     // If a cookie was sent but the ID token is not in the key-value database, wait for the token to be in sync.
-    if (r.variables.cookie_auth_token && !r.variables.session_jwt && !afterSyncCheck && r.variables.zone_sync_leeway > 0) {
+    if (getCookie(r, "") && !getSessionVariable(r, "session_jwt") && !afterSyncCheck && r.variables.zone_sync_leeway > 0) {
         waitForSessionSync(r, r.variables.zone_sync_leeway);
         return;
     }
 
-    if (!r.variables.refresh_token || r.variables.refresh_token == "-") {
+    var refreshToken = getSessionVariable(r, "refresh_token");
+    if (!refreshToken || refreshToken == "-") {
         // Check we have all necessary configuration variables (referenced only by njs)
         var oidcConfigurables = ["authz_endpoint", "scopes", "hmac_key", "cookie_flags"];
         var missingConfig = [];
@@ -70,7 +91,7 @@ function auth(r, afterSyncCheck) {
                 r.error(error_log);
 
                 // Clear the refresh token, try again
-                r.variables.refresh_token = "-";
+                setSessionVariable(r, "refresh_token", "-");
                 r.return(302, r.variables.request_uri);
                 return;
             }
@@ -83,7 +104,7 @@ function auth(r, afterSyncCheck) {
                     if (tokenset.error) {
                         r.error("OIDC " + tokenset.error + " " + tokenset.error_description);
                     }
-                    r.variables.refresh_token = "-";
+                    setSessionVariable(r, "refresh_token", "-");
                     r.return(302, r.variables.request_uri);
                     return;
                 }
@@ -92,31 +113,32 @@ function auth(r, afterSyncCheck) {
                 r.subrequest("/_id_token_validation", "token=" + tokenset.id_token,
                     function(reply) {
                         if (reply.status != 204) {
-                            r.variables.refresh_token = "-";
+                            setSessionVariable(r, "refresh_token", "-");
                             r.return(302, r.variables.request_uri);
                             return;
                         }
 
                         // ID Token is valid, update keyval
-                        r.log("OIDC refresh success, updating id_token for " + r.variables.cookie_auth_token);
-                        r.variables.session_jwt = tokenset.id_token; // Update key-value store
+                        r.log("OIDC refresh success, updating id_token for " + getCookie(r, ""));
+                        setSessionVariable(r, "session_jwt", tokenset.id_token); // Update key-value store
                         if (tokenset.access_token) {
-                            r.variables.access_token = tokenset.access_token;
+                            setSessionVariable(r, "access_token", tokenset.access_token);
                         } else {
-                            r.variables.access_token = "";
+                            setSessionVariable(r, "access_token", "");
                         }
 
-                        // Update refresh token (if we got a new one)
-                        if (r.variables.refresh_token != tokenset.refresh_token) {
-                            r.log("OIDC replacing previous refresh token (" + r.variables.refresh_token + ") with new value: " + tokenset.refresh_token);
-                            r.variables.refresh_token = tokenset.refresh_token; // Update key-value store
+                        // Update refresh token (if we got a new one). An IdP that doesn't rotate refresh
+                        // tokens doesn't return one, and the previous refresh token stays valid.
+                        if (tokenset.refresh_token && refreshToken != tokenset.refresh_token) {
+                            r.log("OIDC replacing previous refresh token with a new value");
+                            setSessionVariable(r, "refresh_token", tokenset.refresh_token); // Update key-value store
                         }
 
                         retryOriginalRequest(r); // Continue processing original request
                     }
                 );
             } catch (e) {
-                r.variables.refresh_token = "-";
+                setSessionVariable(r, "refresh_token", "-");
                 r.return(302, r.variables.request_uri);
                 return;
             }
@@ -179,7 +201,7 @@ function codeExchange(r) {
 
                         // If the response includes a refresh token then store it
                         if (tokenset.refresh_token) {
-                            r.variables.new_refresh = tokenset.refresh_token; // Create key-value store entry
+                            setSessionVariable(r, "new_refresh", tokenset.refresh_token); // Create key-value store entry
                             r.log("OIDC refresh token stored");
                         } else {
                             r.warn("OIDC no refresh token");
@@ -187,15 +209,15 @@ function codeExchange(r) {
 
                         // Add opaque token to keyval session store
                         r.log("OIDC success, creating session " + r.variables.request_id);
-                        r.variables.new_session = tokenset.id_token; // Create key-value store entry
+                        setSessionVariable(r, "new_session", tokenset.id_token); // Create key-value store entry
                         if (tokenset.access_token) {
-                            r.variables.new_access_token = tokenset.access_token;
+                            setSessionVariable(r, "new_access_token", tokenset.access_token);
                         } else {
-                            r.variables.new_access_token = "";
+                            setSessionVariable(r, "new_access_token", "");
                         }
 
-                        r.headersOut["Set-Cookie"] = "auth_token=" + r.variables.request_id + "; " + r.variables.oidc_cookie_flags;
-                        r.return(302, r.variables.redirect_base + decodeURIComponent(r.variables.cookie_auth_redir));
+                        r.headersOut["Set-Cookie"] = makeCookie(r, "", r.variables.request_id);
+                        r.return(302, r.variables.redirect_base + decodeURIComponent(getCookie(r, "_redir")));
                    }
                 );
             } catch (e) {
@@ -241,16 +263,17 @@ function validateIdToken(r) {
     // "If present in the ID Token, Clients MUST verify that the nonce Claim Value is equal to the value of the nonce parameter sent in the Authentication Request."
     if (r.variables.jwt_claim_nonce) {
         var client_nonce_hash = "";
-        if (r.variables.cookie_auth_nonce) {
+        var clientNonce = getCookie(r, "_nonce");
+        if (clientNonce) {
             var c = require('crypto');
-            var h = c.createHmac('sha256', r.variables.oidc_hmac_key).update(r.variables.cookie_auth_nonce);
+            var h = c.createHmac('sha256', r.variables.oidc_hmac_key).update(clientNonce);
             client_nonce_hash = h.digest('base64url');
         }
         if (r.variables.jwt_claim_nonce != client_nonce_hash) {
             r.error("OIDC ID Token validation error: nonce from token (" + r.variables.jwt_claim_nonce + ") does not match client (" + client_nonce_hash + ")");
             validToken = false;
         }
-    } else if (!r.variables.refresh_token || r.variables.refresh_token == "-") {
+    } else if (!getSessionVariable(r, "refresh_token") || getSessionVariable(r, "refresh_token") == "-") {
         r.error("OIDC ID Token validation error: missing nonce claim in ID Token during initial authentication.");
         validToken = false;
     }
@@ -263,7 +286,7 @@ function validateIdToken(r) {
 }
 
 function logout(r) {
-    r.log("OIDC logout for " + r.variables.cookie_auth_token);
+    r.log("OIDC logout for " + getCookie(r, ""));
 
     // Determine if oidc_logout_redirect is a full URL or a relative path
     function getLogoutRedirectUrl(base, redirect) {
@@ -274,17 +297,24 @@ function logout(r) {
 
     // Helper function to perform the final logout steps
     function performLogout(redirectUrl) {
-        r.variables.session_jwt = '-';
-        r.variables.access_token = '-';
-        r.variables.refresh_token = '-';
+        setSessionVariable(r, "session_jwt", '-');
+        setSessionVariable(r, "access_token", '-');
+        setSessionVariable(r, "refresh_token", '-');
+        r.headersOut['Set-Cookie'] = [
+            makeCookie(r, "", ""),
+            makeCookie(r, "_nonce", ""),
+            makeCookie(r, "_redir", "")
+        ];
         r.return(302, redirectUrl);
     }
 
     // Check if OIDC end session endpoint is available
     if (r.variables.oidc_end_session_endpoint) {
 
-        if (!r.variables.session_jwt || r.variables.session_jwt === '-') {
-            if (r.variables.refresh_token && r.variables.refresh_token !== '-') {
+        var sessionJwt = getSessionVariable(r, "session_jwt");
+        var refreshToken = getSessionVariable(r, "refresh_token");
+        if (!sessionJwt || sessionJwt === '-') {
+            if (refreshToken && refreshToken !== '-') {
                 // Renew ID token if only refresh token is available
                 auth(r, 0);
             } else {
@@ -295,7 +325,7 @@ function logout(r) {
 
         // Construct logout arguments for RP-initiated logout
         var logoutArgs = "?post_logout_redirect_uri=" + encodeURIComponent(logoutRedirectUrl) +
-                         "&id_token_hint=" + encodeURIComponent(r.variables.session_jwt);
+                         "&id_token_hint=" + encodeURIComponent(getSessionVariable(r, "session_jwt"));
         performLogout(r.variables.oidc_end_session_endpoint + logoutArgs);
     } else {
         // Fallback to traditional logout approach
@@ -318,15 +348,15 @@ function getAuthZArgs(r) {
     var encodedRequestUri = encodeURIComponent(r.variables.request_uri);
 
     r.headersOut['Set-Cookie'] = [
-        "auth_redir=" + encodedRequestUri + "; " + r.variables.oidc_cookie_flags,
-        "auth_nonce=" + noncePlain + "; " + r.variables.oidc_cookie_flags
+        makeCookie(r, "_redir", encodedRequestUri),
+        makeCookie(r, "_nonce", noncePlain)
     ];
 
     if ( r.variables.oidc_pkce_enable == 1 ) {
         var pkce_code_verifier = c.createHmac('sha256', r.variables.oidc_hmac_key).update(String(Math.random())).digest('hex');
         r.variables.pkce_id = c.createHash('sha256').update(String(Math.random())).digest('base64url');
         var pkce_code_challenge = c.createHash('sha256').update(pkce_code_verifier).digest('base64url');
-        r.variables.pkce_code_verifier = pkce_code_verifier;
+        setSessionVariable(r, "pkce_code_verifier", pkce_code_verifier);
 
         authZArgs += "&code_challenge_method=S256&code_challenge=" + pkce_code_challenge + "&state=" + r.variables.pkce_id;
     } else {
@@ -343,11 +373,11 @@ function generateTokenRequestParams(r, grant_type) {
             body += "&code=" + r.variables.arg_code + "&redirect_uri=" + r.variables.redirect_base + r.variables.redir_location;
             if (r.variables.oidc_pkce_enable == 1) {
                 r.variables.pkce_id = r.variables.arg_state;
-                body += "&code_verifier=" + r.variables.pkce_code_verifier;
+                body += "&code_verifier=" + getSessionVariable(r, "pkce_code_verifier");
             }
             break;
         case "refresh_token":
-            body += "&refresh_token=" + r.variables.refresh_token;
+            body += "&refresh_token=" + getSessionVariable(r, "refresh_token");
             break;
         default:
             r.error("Unsupported grant type: " + grant_type);
@@ -6,11 +6,14 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // There seems to be no composite interface in the kubernetes api package,
@@ -211,6 +214,48 @@ func ParseTime(s string) (string, error) {
 	return fmt.Sprintf("%s%s%s%s%s%s%s%s", years, months, weeks, days, hours, mins, secs, millis), nil
 }
 
+var timeUnitRegexp = regexp.MustCompile(`(\d+)(ms|[yMwdhms])`)
+
+// timeUnitDurations are the durations of the units of NGINX times.
+var timeUnitDurations = map[string]time.Duration{
+	"y":  365 * 24 * time.Hour,
+	"M":  30 * 24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"d":  24 * time.Hour,
+	"h":  time.Hour,
+	"m":  time.Minute,
+	"s":  time.Second,
+	"ms": time.Millisecond,
+}
+
+// ParseTimeToDuration converts a valid time string into a duration.
+func ParseTimeToDuration(s string) (time.Duration, error) {
+	t, err := ParseTime(s)
+	if err != nil {
+		return 0, err
+	}
+
+	var d time.Duration
+	for _, unit := range timeUnitRegexp.FindAllStringSubmatch(t, -1) {
+		n, err := strconv.ParseInt(unit[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(n) * timeUnitDurations[unit[2]]
+	}
+	return d, nil
+}
+
+// ParseTimeToSeconds converts a valid time string into seconds. The time is truncated to whole seconds,
+// so milliseconds only count when they add up to a second, for example, 1s500ms is 1 and 1500ms is 1.
+func ParseTimeToSeconds(s string) (int64, error) {
+	d, err := ParseTimeToDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	return int64(d / time.Second), nil
+}
+
 // OffsetFmt http://nginx.org/en/docs/syntax.html
 const OffsetFmt = `\d+[kKmMgG]?`
 
@@ -318,6 +363,39 @@ func parsePort(value string) (int, error) {
 	return int(port), nil
 }
 
+// ParsePolicyReferences ensures that the string is a comma-separated list of Policy references
+// in the form of name or namespace/name
+func ParsePolicyReferences(s string) ([]conf_v1.PolicyReference, error) {
+	var refs []conf_v1.PolicyReference
+	for _, value := range strings.Split(s, ",") {
+		ref, err := parsePolicyReference(strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+func parsePolicyReference(value string) (conf_v1.PolicyReference, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) > 2 {
+		return conf_v1.PolicyReference{}, fmt.Errorf("invalid policy reference %q, must be name or namespace/name", value)
+	}
+
+	for _, part := range parts {
+		if errMsgs := validation.IsDNS1123Subdomain(part); len(errMsgs) > 0 {
+			return conf_v1.PolicyReference{}, fmt.Errorf("invalid policy reference %q: %s", value, strings.Join(errMsgs, ", "))
+		}
+	}
+
+	if len(parts) == 2 {
+		return conf_v1.PolicyReference{Namespace: parts[0], Name: parts[1]}, nil
+	}
+
+	return conf_v1.PolicyReference{Name: parts[0]}, nil
+}
+
 // ParseServiceList ensures that the string is a comma-separated list of services
 func ParseServiceList(s string) map[string]bool {
 	services := make(map[string]bool)
@@ -3,9 +3,11 @@ package configs
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	v1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -134,6 +136,49 @@ func TestParseServicesFromString(t *testing.T) {
 	}
 }
 
+func TestParsePolicyReferences(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		input string
+		want  []conf_v1.PolicyReference
+	}{
+		{
+			input: "jwt-policy",
+			want:  []conf_v1.PolicyReference{{Name: "jwt-policy"}},
+		},
+		{
+			input: "jwt-policy, policies/rate-limit-policy",
+			want: []conf_v1.PolicyReference{
+				{Name: "jwt-policy"},
+				{Namespace: "policies", Name: "rate-limit-policy"},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		got, err := ParsePolicyReferences(tc.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Error(cmp.Diff(tc.want, got))
+		}
+	}
+}
+
+func TestParsePolicyReferences_FailsOnBogusStrings(t *testing.T) {
+	t.Parallel()
+
+	invalidPolicyReferences := []string{"", "policy,", "a/b/c", "/policy", "namespace/", "Policy", "policy_1"}
+	for _, s := range invalidPolicyReferences {
+		_, err := ParsePolicyReferences(s)
+		if err == nil {
+			t.Errorf("ParsePolicyReferences(%q) returned no error", s)
+		}
+	}
+}
+
 func TestParsePortList_FailsOnBogusStrings(t *testing.T) {
 	t.Parallel()
 
@@ -554,6 +599,68 @@ func TestParseTime(t *testing.T) {
 	}
 }
 
+func TestParseTimeToSeconds(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"1h30m 5 100ms", 5405},
+		{"10ms", 0},
+		{"1s500ms", 1},
+		{"1s999ms", 1},
+		{"1500ms", 1},
+		{"2500ms", 2},
+		{"600", 600},
+		{"2d", 172800},
+		{"1w", 604800},
+		{"1y 1M", 34128000},
+	}
+
+	for _, test := range tests {
+		result, err := ParseTimeToSeconds(test.input)
+		if err != nil {
+			t.Fatalf("ParseTimeToSeconds(%q) returned an error for valid input", test.input)
+		}
+		if result != test.expected {
+			t.Errorf("ParseTimeToSeconds(%q) returned %d expected %d", test.input, result, test.expected)
+		}
+	}
+
+	if result, err := ParseTimeToSeconds("5s 5s"); err == nil {
+		t.Errorf("ParseTimeToSeconds(%q) didn't return error. Returned: %d", "5s 5s", result)
+	}
+}
+
+func TestParseTimeToDuration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"1h30m 5 100ms", time.Hour + 30*time.Minute + 5*time.Second + 100*time.Millisecond},
+		{"10ms", 10 * time.Millisecond},
+		{"1s500ms", 1500 * time.Millisecond},
+		{"1500ms", 1500 * time.Millisecond},
+		{"600", 600 * time.Second},
+		{"2d", 48 * time.Hour},
+	}
+
+	for _, test := range tests {
+		result, err := ParseTimeToDuration(test.input)
+		if err != nil {
+			t.Fatalf("ParseTimeToDuration(%q) returned an error for valid input", test.input)
+		}
+		if result != test.expected {
+			t.Errorf("ParseTimeToDuration(%q) returned %v expected %v", test.input, result, test.expected)
+		}
+	}
+
+	if result, err := ParseTimeToDuration("5s 5s"); err == nil {
+		t.Errorf("ParseTimeToDuration(%q) didn't return error. Returned: %v", "5s 5s", result)
+	}
+}
+
 func TestParseOffset(t *testing.T) {
 	t.Parallel()
 	testsWithValidInput := []string{"1", "2k", "2K", "3m", "3M", "4g", "4G"}
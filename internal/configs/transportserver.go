@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	api_v1 "k8s.io/api/core/v1"
 
@@ -19,7 +20,7 @@ type TransportServerEx struct {
 	ListenerPort     int
 	TransportServer  *conf_v1.TransportServer
 	Endpoints        map[string][]string
-	PodsByIP         map[string]string
+	PodsByIP         map[string]PodInfo
 	ExternalNameSvcs map[string]bool
 	DisableIPV6      bool
 	SecretRefs       map[string]*secrets.SecretReference
@@ -44,12 +45,14 @@ func newUpstreamNamerForTransportServer(transportServer *conf_v1.TransportServer
 }
 
 type transportServerConfigParams struct {
-	transportServerEx      *TransportServerEx
-	listenerPort           int
-	isPlus                 bool
-	isResolverConfigured   bool
-	isDynamicReloadEnabled bool
-	staticSSLPath          string
+	transportServerEx       *TransportServerEx
+	listenerPort            int
+	isPlus                  bool
+	isResolverConfigured    bool
+	isDynamicReloadEnabled  bool
+	staticSSLPath           string
+	isLatencyMetricsEnabled bool
+	now                     time.Time
 }
 
 // generateTransportServerConfig generates a full configuration for a TransportServer.
@@ -65,7 +68,7 @@ func generateTransportServerConfig(p transportServerConfigParams) (*version2.Tra
 		upstreamNamer.GetNameForUpstream(p.transportServerEx.TransportServer.Spec.Action.Pass),
 		p.transportServerEx.TransportServer.Spec.Upstreams)
 
-	sslConfig, w := generateSSLConfig(p.transportServerEx.TransportServer, p.transportServerEx.TransportServer.Spec.TLS, p.transportServerEx.TransportServer.Namespace, p.transportServerEx.SecretRefs)
+	sslConfig, w := generateSSLConfig(p.transportServerEx.TransportServer, p.transportServerEx.TransportServer.Spec.TLS, p.transportServerEx.TransportServer.Namespace, p.transportServerEx.SecretRefs, p.now)
 	warnings.Add(w)
 
 	var proxyRequests, proxyResponses *int
@@ -85,6 +88,14 @@ func generateTransportServerConfig(p transportServerConfigParams) (*version2.Tra
 		connectTimeout = p.transportServerEx.TransportServer.Spec.UpstreamParameters.ConnectTimeout
 	}
 
+	var limitConn *version2.StreamLimitConn
+	var uploadRate, downloadRate string
+	if rl := p.transportServerEx.TransportServer.Spec.RateLimit; rl != nil {
+		limitConn = generateStreamLimitConn(rl, p.transportServerEx.TransportServer.Namespace, p.transportServerEx.TransportServer.Name)
+		uploadRate = rl.UploadRate
+		downloadRate = rl.DownloadRate
+	}
+
 	var proxyTimeout string
 	if p.transportServerEx.TransportServer.Spec.SessionParameters != nil {
 		proxyTimeout = p.transportServerEx.TransportServer.Spec.SessionParameters.Timeout
@@ -127,6 +138,11 @@ func generateTransportServerConfig(p transportServerConfigParams) (*version2.Tra
 			SSL:                      sslConfig,
 			IPv4:                     p.transportServerEx.IPv4,
 			IPv6:                     p.transportServerEx.IPv6,
+			LatencyMetrics:           p.isLatencyMetricsEnabled,
+			Maintenance:              generateStreamMaintenance(p.transportServerEx.TransportServer.Spec.Maintenance),
+			LimitConn:                limitConn,
+			ProxyUploadRate:          uploadRate,
+			ProxyDownloadRate:        downloadRate,
 		},
 		Match:                   match,
 		Upstreams:               upstreams,
@@ -144,7 +160,28 @@ func generateUnixSocket(transportServerEx *TransportServerEx) string {
 	return ""
 }
 
-func generateSSLConfig(ts *conf_v1.TransportServer, tls *conf_v1.TransportServerTLS, namespace string, secretRefs map[string]*secrets.SecretReference) (*version2.StreamSSL, Warnings) {
+func generateStreamMaintenance(m *conf_v1.TransportServerMaintenance) *version2.StreamMaintenance {
+	if m == nil || !m.Enable {
+		return nil
+	}
+	return &version2.StreamMaintenance{
+		AllowList: m.AllowList,
+	}
+}
+
+func generateStreamLimitConn(rl *conf_v1.TransportServerRateLimit, namespace string, name string) *version2.StreamLimitConn {
+	if rl.Connections == 0 {
+		return nil
+	}
+	return &version2.StreamLimitConn{
+		ZoneName:    fmt.Sprintf("ts_limit_conn_%s_%s", namespace, name),
+		Key:         generateString(rl.Key, "${binary_remote_addr}"),
+		ZoneSize:    generateString(rl.ZoneSize, "10M"),
+		Connections: rl.Connections,
+	}
+}
+
+func generateSSLConfig(ts *conf_v1.TransportServer, tls *conf_v1.TransportServerTLS, namespace string, secretRefs map[string]*secrets.SecretReference, now time.Time) (*version2.StreamSSL, Warnings) {
 	if tls == nil {
 		return &version2.StreamSSL{Enabled: false}, nil
 	}
@@ -166,6 +203,8 @@ func generateSSLConfig(ts *conf_v1.TransportServer, tls *conf_v1.TransportServer
 		errMsg := fmt.Sprintf("TLS secret %s is invalid: %v. SSL termination will not be enabled for this server.", tls.Secret, secretRef.Error)
 		warnings.AddWarning(ts, errMsg)
 		sslEnabled = false
+	} else if expired, notAfter := secrets.IsCertificateExpired(secretRef.Secret, now); expired {
+		warnings.AddWarningf(ts, "TLS secret %s holds a certificate that expired on %s", tls.Secret, notAfter.Format(time.RFC3339))
 	}
 
 	ssl := version2.StreamSSL{
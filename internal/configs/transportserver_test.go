@@ -4,6 +4,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
@@ -1882,7 +1883,7 @@ func TestGenerateTsSSLConfig(t *testing.T) {
 
 	for _, test := range validTests {
 		// it is ok to use nil as the owner
-		result, warnings := generateSSLConfig(nil, test.inputTLS, namespace, test.inputSecretRefs)
+		result, warnings := generateSSLConfig(nil, test.inputTLS, namespace, test.inputSecretRefs, time.Now())
 		if !reflect.DeepEqual(result, test.expectedSSL) {
 			t.Errorf("generateSSLConfig() returned %v but expected %v for the case of %s", result, test.expectedSSL, test.msg)
 		}
@@ -1892,7 +1893,7 @@ func TestGenerateTsSSLConfig(t *testing.T) {
 	}
 	for _, test := range invalidTests {
 		// it is ok to use nil as the owner
-		result, warnings := generateSSLConfig(nil, test.inputTLS, namespace, test.inputSecretRefs)
+		result, warnings := generateSSLConfig(nil, test.inputTLS, namespace, test.inputSecretRefs, time.Now())
 		if !reflect.DeepEqual(result, test.expectedSSL) {
 			t.Errorf("generateSSLConfig() returned %v but expected %v for the case of %s", result, test.expectedSSL, test.msg)
 		}
@@ -1901,3 +1902,53 @@ func TestGenerateTsSSLConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateStreamLimitConn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		rateLimit *conf_v1.TransportServerRateLimit
+		expected  *version2.StreamLimitConn
+		msg       string
+	}{
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				UploadRate: "100k",
+			},
+			expected: nil,
+			msg:      "no connection limit",
+		},
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				Connections: 10,
+			},
+			expected: &version2.StreamLimitConn{
+				ZoneName:    "ts_limit_conn_default_tcp-server",
+				Key:         "${binary_remote_addr}",
+				ZoneSize:    "10M",
+				Connections: 10,
+			},
+			msg: "default key and zone size",
+		},
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				Connections: 5,
+				Key:         "${server_port}",
+				ZoneSize:    "1M",
+			},
+			expected: &version2.StreamLimitConn{
+				ZoneName:    "ts_limit_conn_default_tcp-server",
+				Key:         "${server_port}",
+				ZoneSize:    "1M",
+				Connections: 5,
+			},
+			msg: "custom key and zone size",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateStreamLimitConn(test.rateLimit, "default", "tcp-server")
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateStreamLimitConn() '%s' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
@@ -23,6 +23,9 @@ type IngressNginxConfig struct {
 	DynamicSSLReloadEnabled bool
 	StaticSSLPath           string
 	LimitReqZones           []LimitReqZone
+	Maps                    []version2.Map
+	AuthJWTClaimSets        []version2.AuthJWTClaimSet
+	JWKSAuthEnabled         bool
 }
 
 // Ingress holds information about an Ingress resource.
@@ -109,6 +112,12 @@ type Server struct {
 	BasicAuth            *BasicAuth
 	JWTRedirectLocations []JWTRedirectLocation
 
+	IngressMTLS         *version2.IngressMTLS
+	PoliciesErrorReturn *version2.Return
+	JWTAuthList         map[string]*version2.JWTAuth
+	APIKeyEnabled       bool
+	OIDCProviders       []*version2.OIDC
+
 	Ports                        []int
 	SSLPorts                     []int
 	AppProtectEnable             string
@@ -162,6 +171,21 @@ type LimitReq struct {
 	LogLevel   string
 }
 
+// Policies holds the configuration of the Policies referenced by an Ingress in the nginx.org/policies annotation.
+type Policies struct {
+	Allow           []string
+	Deny            []string
+	LimitReqOptions version2.LimitReqOptions
+	LimitReqs       []version2.LimitReq
+	JWTAuth         *version2.JWTAuth
+	JWTHeaders      []version2.Header
+	BasicAuth       *version2.BasicAuth
+	EgressMTLS      *version2.EgressMTLS
+	OIDC            *version2.OIDC
+	APIKey          *version2.APIKey
+	ErrorReturn     *version2.Return
+}
+
 // Location describes an NGINX location.
 type Location struct {
 	LocationSnippets     []string
@@ -185,6 +209,7 @@ type Location struct {
 	BasicAuth            *BasicAuth
 	ServiceName          string
 	LimitReq             *LimitReq
+	Policies             *Policies
 
 	MinionIngress *Ingress
 }
@@ -288,6 +313,11 @@ type MainConfig struct {
 	DynamicSSLReloadEnabled            bool
 	StaticSSLPath                      string
 	NginxVersion                       nginx.Version
+	LoadModules                        []string
+	// GeoIP2 enables the lookup of the client IPs in the MaxMind databases. The databases are optional.
+	GeoIP2                bool
+	GeoIP2CountryDatabase string
+	GeoIP2ASNDatabase     string
 }
 
 // NewUpstreamWithDefaultServer creates an upstream with the default server.
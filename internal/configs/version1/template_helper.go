@@ -8,6 +8,7 @@ import (
 	"text/template"
 
 	"github.com/nginx/kubernetes-ingress/internal/configs/commonhelpers"
+	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
 )
 
 func split(s string, delim string) []string {
@@ -221,4 +222,5 @@ var helperFunctions = template.FuncMap{
 	"generateProxySetHeaders": generateProxySetHeaders,
 	"boolToPointerBool":       boolToPointerBool,
 	"makeResolver":            makeResolver,
+	"makeHeaderQueryValue":    version2.MakeHeaderQueryValue,
 }
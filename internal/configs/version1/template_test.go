@@ -2,6 +2,7 @@ package version1
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"text/template"
 
 	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
 	"github.com/nginx/kubernetes-ingress/internal/nginx"
 )
 
@@ -69,6 +71,71 @@ func TestExecuteMainTemplateForNGINX(t *testing.T) {
 	t.Log(buf.String())
 }
 
+func TestExecuteMainTemplateForNGINXWithLoadModules(t *testing.T) {
+	t.Parallel()
+
+	tmpl := newNGINXMainTmpl(t)
+	buf := &bytes.Buffer{}
+
+	cfg := mainCfg
+	cfg.LoadModules = []string{"ngx_http_brotli_filter_module.so", "ngx_http_brotli_static_module.so"}
+	err := tmpl.Execute(buf, cfg)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, module := range cfg.LoadModules {
+		want := fmt.Sprintf("load_module modules/%s;", module)
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("want %q in generated config", want)
+		}
+	}
+}
+
+func TestExecuteMainTemplateForNGINXWithGeoIP2(t *testing.T) {
+	t.Parallel()
+
+	tmpl := newNGINXMainTmpl(t)
+	buf := &bytes.Buffer{}
+
+	cfg := mainCfg
+	cfg.GeoIP2 = true
+	cfg.GeoIP2CountryDatabase = "/etc/nginx/geoip/GeoLite2-Country.mmdb"
+	err := tmpl.Execute(buf, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantDirectives := []string{
+		"geoip2 /etc/nginx/geoip/GeoLite2-Country.mmdb {",
+		"$geoip2_country_code country iso_code;",
+		"map $nginx_version $geoip2_asn {",
+	}
+	mainConf := buf.String()
+	for _, want := range wantDirectives {
+		if !strings.Contains(mainConf, want) {
+			t.Errorf("want %q in generated config", want)
+		}
+	}
+}
+
+func TestExecuteMainTemplateForNGINXWithoutGeoIP2(t *testing.T) {
+	t.Parallel()
+
+	tmpl := newNGINXMainTmpl(t)
+	buf := &bytes.Buffer{}
+
+	cfg := mainCfg
+	cfg.GeoIP2CountryDatabase = "/etc/nginx/geoip/GeoLite2-Country.mmdb"
+	err := tmpl.Execute(buf, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "geoip2") {
+		t.Errorf("unwant geoip2 in generated config")
+	}
+}
+
 func TestExecuteTemplate_ForIngressForNGINXPlus(t *testing.T) {
 	t.Parallel()
 
@@ -1676,6 +1743,123 @@ func TestExecuteTemplate_ForIngressForNGINXPlusWithRequestRateLimitMinions(t *te
 	snaps.MatchSnapshot(t, buf.String())
 }
 
+func TestExecuteTemplate_ForIngressForNGINXWithPolicies(t *testing.T) {
+	t.Parallel()
+
+	tmpl := newNGINXIngressTmpl(t)
+	buf := &bytes.Buffer{}
+
+	err := tmpl.Execute(buf, ingressCfgWithPolicies)
+	t.Log(buf.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ingConf := buf.String()
+
+	wantDirectives := []string{
+		"limit_req_zone ${binary_remote_addr} zone=pol_rl_default_rate-limit-policy_default_cafe-ingress:10M rate=10r/s;",
+		"ssl_client_certificate /etc/nginx/secrets/default-ingress-mtls-secret-ca.crt;",
+		"ssl_verify_client on;",
+		"allow 10.0.0.0/8;",
+		"deny all;",
+		"limit_req_status 503;",
+		"limit_req zone=pol_rl_default_rate-limit-policy_default_cafe-ingress burst=20;",
+		`auth_basic "My Cafe";`,
+		"auth_basic_user_file /etc/nginx/secrets/default-htpasswd-secret;",
+		"location = /_validate_apikey_njs {",
+		"auth_request /_validate_apikey_njs;",
+		"proxy_set_header X-Client-ID $apikey_client_id;",
+	}
+
+	for _, want := range wantDirectives {
+		if !strings.Contains(ingConf, want) {
+			t.Errorf("want %q in generated config", want)
+		}
+	}
+	snaps.MatchSnapshot(t, buf.String())
+}
+
+func TestExecuteTemplate_ForIngressForNGINXPlusWithPolicies(t *testing.T) {
+	t.Parallel()
+
+	tmpl := newNGINXPlusIngressTmpl(t)
+	buf := &bytes.Buffer{}
+
+	err := tmpl.Execute(buf, ingressCfgWithPolicies)
+	t.Log(buf.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ingConf := buf.String()
+
+	wantDirectives := []string{
+		"limit_req_zone ${binary_remote_addr} zone=pol_rl_default_rate-limit-policy_default_cafe-ingress:10M rate=10r/s;",
+		"ssl_client_certificate /etc/nginx/secrets/default-ingress-mtls-secret-ca.crt;",
+		"ssl_verify_client on;",
+		"allow 10.0.0.0/8;",
+		"deny all;",
+		"limit_req_status 503;",
+		"limit_req zone=pol_rl_default_rate-limit-policy_default_cafe-ingress burst=20;",
+		`auth_jwt "My Cafe" token=$http_token;`,
+		"auth_jwt_key_file /etc/nginx/secrets/default-jwk-secret;",
+		"location = /_validate_apikey_njs {",
+		"auth_request /_validate_apikey_njs;",
+		"proxy_set_header X-Client-ID $apikey_client_id;",
+	}
+
+	for _, want := range wantDirectives {
+		if !strings.Contains(ingConf, want) {
+			t.Errorf("want %q in generated config", want)
+		}
+	}
+	snaps.MatchSnapshot(t, buf.String())
+}
+
+func TestExecuteTemplate_ForIngressWithPoliciesErrorReturn(t *testing.T) {
+	t.Parallel()
+
+	ingressCfg := IngressNginxConfig{
+		Ingress: Ingress{
+			Name:      "cafe-ingress",
+			Namespace: "default",
+		},
+		Servers: []Server{
+			{
+				Name:                "cafe.example.com",
+				ServerTokens:        "off",
+				StatusZone:          "cafe.example.com",
+				PoliciesErrorReturn: &version2.Return{Code: 500},
+				Locations: []Location{
+					{
+						Path:                "/coffee",
+						Upstream:            testUpstream,
+						ProxyConnectTimeout: "10s",
+						ProxyReadTimeout:    "10s",
+						ProxySendTimeout:    "10s",
+						ClientMaxBodySize:   "1m",
+						Policies:            &Policies{},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tmpl := range []*template.Template{newNGINXIngressTmpl(t), newNGINXPlusIngressTmpl(t)} {
+		buf := &bytes.Buffer{}
+
+		err := tmpl.Execute(buf, ingressCfg)
+		t.Log(buf.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "return 500;"
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("want %q in generated config", want)
+		}
+	}
+}
+
 func newNGINXPlusIngressTmpl(t *testing.T) *template.Template {
 	t.Helper()
 	tmpl, err := template.New("nginx-plus.ingress.tmpl").Funcs(helperFunctions).ParseFiles("nginx-plus.ingress.tmpl")
@@ -3119,6 +3303,97 @@ var (
 	}
 )
 
+var ingressCfgWithPolicies = IngressNginxConfig{
+	Ingress: Ingress{
+		Name:      "cafe-ingress",
+		Namespace: "default",
+	},
+	Servers: []Server{
+		{
+			Name:              "cafe.example.com",
+			ServerTokens:      "off",
+			StatusZone:        "cafe.example.com",
+			SSL:               true,
+			SSLCertificate:    "secret.pem",
+			SSLCertificateKey: "secret.pem",
+			SSLPorts:          []int{443},
+			IngressMTLS: &version2.IngressMTLS{
+				ClientCert:   "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt",
+				VerifyClient: "on",
+				VerifyDepth:  1,
+			},
+			APIKeyEnabled: true,
+			Locations: []Location{
+				{
+					Path:                "/coffee",
+					Upstream:            testUpstream,
+					ProxyConnectTimeout: "10s",
+					ProxyReadTimeout:    "10s",
+					ProxySendTimeout:    "10s",
+					ClientMaxBodySize:   "1m",
+					Policies: &Policies{
+						Allow: []string{"10.0.0.0/8"},
+						LimitReqOptions: version2.LimitReqOptions{
+							LogLevel:   "error",
+							RejectCode: 503,
+						},
+						LimitReqs: []version2.LimitReq{
+							{
+								ZoneName: "pol_rl_default_rate-limit-policy_default_cafe-ingress",
+								Burst:    20,
+							},
+						},
+						BasicAuth: &version2.BasicAuth{
+							Secret: "/etc/nginx/secrets/default-htpasswd-secret",
+							Realm:  "My Cafe",
+						},
+					},
+				},
+				{
+					Path:                "/tea",
+					Upstream:            testUpstream,
+					ProxyConnectTimeout: "10s",
+					ProxyReadTimeout:    "10s",
+					ProxySendTimeout:    "10s",
+					ClientMaxBodySize:   "1m",
+					Policies: &Policies{
+						Allow: []string{"10.0.0.0/8"},
+						LimitReqOptions: version2.LimitReqOptions{
+							LogLevel:   "error",
+							RejectCode: 503,
+						},
+						LimitReqs: []version2.LimitReq{
+							{
+								ZoneName: "pol_rl_default_rate-limit-policy_default_cafe-ingress",
+								Burst:    20,
+							},
+						},
+						JWTAuth: &version2.JWTAuth{
+							Key:    "default/jwt-policy",
+							Secret: "/etc/nginx/secrets/default-jwk-secret",
+							Realm:  "My Cafe",
+							Token:  "$http_token",
+						},
+						APIKey: &version2.APIKey{
+							Header:         []string{"X-API-Key"},
+							MapName:        "apikey_auth_client_name_default_cafe_ingress_api_key_policy",
+							ClientIDHeader: "X-Client-ID",
+						},
+					},
+				},
+			},
+		},
+	},
+	LimitReqZones: []LimitReqZone{
+		{
+			Name: "pol_rl_default_rate-limit-policy_default_cafe-ingress",
+			Key:  "${binary_remote_addr}",
+			Size: "10M",
+			Rate: "10r/s",
+		},
+	},
+}
+
 var testUpstream = Upstream{
 	Name:             "test",
 	UpstreamZoneSize: "256k",
@@ -97,9 +97,11 @@ type Server struct {
 	BasicAuth                 *BasicAuth
 	IngressMTLS               *IngressMTLS
 	EgressMTLS                *EgressMTLS
-	OIDC                      *OIDC
+	OIDCProviders             []*OIDC
 	APIKey                    *APIKey
 	APIKeyEnabled             bool
+	OAuth2Introspections      []*OAuth2Introspection
+	Challenges                []*Challenge
 	WAF                       *WAF
 	Dos                       *Dos
 	PoliciesErrorReturn       *Return
@@ -107,6 +109,30 @@ type Server struct {
 	VSName                    string
 	DisableIPV6               bool
 	Gunzip                    bool
+	Compression               []Compression
+	Maintenance               *Maintenance
+	RequestLimits             *ServerRequestLimits
+}
+
+// ServerRequestLimits defines the limits of the request headers of a server.
+type ServerRequestLimits struct {
+	ClientHeaderBufferSize   string
+	LargeClientHeaderBuffers string
+	ClientHeaderTimeout      string
+}
+
+// Maintenance defines the maintenance mode of a server.
+// The requests are redirected to the Location unless Variable evaluates to 0.
+type Maintenance struct {
+	Variable     string
+	AddrVariable string
+	AllowList    []string
+	Header       string
+	Token        string
+	Location     string
+	DefaultType  string
+	Body         string
+	RetryAfter   int64
 }
 
 // SSL defines SSL configuration for a server.
@@ -141,6 +167,8 @@ type EgressMTLS struct {
 
 // OIDC holds OIDC configuration data.
 type OIDC struct {
+	// Key is the name of the provider in the names of its key-value zones and variables.
+	Key                   string
 	AuthEndpoint          string
 	ClientID              string
 	ClientSecret          string
@@ -149,10 +177,18 @@ type OIDC struct {
 	TokenEndpoint         string
 	EndSessionEndpoint    string
 	RedirectURI           string
+	LogoutURI             string
 	PostLogoutRedirectURI string
 	ZoneSyncLeeway        int
 	AuthExtraArgs         string
 	AccessTokenEnable     bool
+	PKCEEnable            bool
+	HMACKey               string
+	CookieName            string
+	CookieDomain          string
+	CookieSameSite        string
+	SessionTimeout        string
+	RefreshTimeout        string
 }
 
 // APIKey holds API key configuration.
@@ -160,6 +196,72 @@ type APIKey struct {
 	Header  []string
 	Query   []string
 	MapName string
+	// ExpiresMapName and TierMapName are the names of the maps of the expiry times and of the tiers of the clients.
+	// They are empty if no client has them.
+	ExpiresMapName string
+	TierMapName    string
+	ClientIDHeader string
+}
+
+// OAuth2Introspection holds the configuration of the validation of the access tokens with
+// an OAuth 2.0 token introspection endpoint.
+type OAuth2Introspection struct {
+	// Key is the name of the policy in the names of its shared dictionary zone and locations.
+	Key            string
+	EndpointScheme string
+	EndpointHost   string
+	EndpointPort   string
+	EndpointPath   string
+	// Authorization is the value of the Authorization header of the requests to the introspection endpoint.
+	Authorization string
+	Scopes        string
+	Audiences     string
+	CacheTimeout  string
+	Headers       []OAuth2IntrospectionHeader
+}
+
+// OAuth2IntrospectionHeader defines a request header with the value of a field of the introspection response.
+type OAuth2IntrospectionHeader struct {
+	Name  string
+	Field string
+}
+
+// SignatureVerification holds the configuration of the verification of the HMAC signatures of the request bodies.
+type SignatureVerification struct {
+	Header    string
+	Algorithm string
+	Encoding  string
+	Prefix    string
+	// Key is the base64-encoded HMAC key.
+	Key             string
+	TimestampHeader string
+	// TimestampTolerance is the maximum age of the timestamp of a request in seconds.
+	TimestampTolerance int64
+	// SignedPayload is the format of the signed string with the placeholders {timestamp} and {body}.
+	SignedPayload string
+	// BodyBufferSize keeps the request body in memory for the verification.
+	BodyBufferSize string
+	// Location is the named location that passes the requests with a valid signature to the upstream.
+	Location string
+}
+
+// Challenge holds the configuration of a challenge policy. The clients must pass a JavaScript or a cookie challenge
+// before their requests are proxied.
+type Challenge struct {
+	// Key is the name of the policy in the names of its variables, zones and location.
+	Key  string
+	Type string
+	// SecretKey is the base64-encoded key that signs the cookie of the clients that passed the challenge.
+	SecretKey  string
+	CookieName string
+	// CookieLifetime is the lifetime of the cookie in seconds.
+	CookieLifetime   int64
+	ExemptCIDRs      []string
+	ExemptUserAgents []string
+	// BlockThreshold is the number of the failed verifications after which a client is blocked. 0 disables the blocking.
+	BlockThreshold int
+	BlockInterval  string
+	BlockDuration  string
 }
 
 // WAF defines WAF configuration.
@@ -185,6 +287,13 @@ type Dos struct {
 	ApDosAccessLogDest     string
 }
 
+// ArgsRewrite defines a change of the arguments of the requests in a location.
+// When Regex is set, the arguments are changed only when they match it.
+type ArgsRewrite struct {
+	Regex string
+	Value string
+}
+
 // Location defines a location.
 type Location struct {
 	Path                     string
@@ -194,6 +303,7 @@ type Location struct {
 	ProxyReadTimeout         string
 	ProxySendTimeout         string
 	ClientMaxBodySize        string
+	ClientBodyTimeout        string
 	ProxyMaxTempFileSize     string
 	ProxyBuffering           bool
 	ProxyBuffers             string
@@ -211,27 +321,82 @@ type Location struct {
 	ProxyPassRewrite         string
 	AddHeaders               []AddHeader
 	Rewrites                 []string
-	HasKeepalive             bool
-	ErrorPages               []ErrorPage
-	ProxySSLName             string
-	InternalProxyPass        string
-	Allow                    []string
-	Deny                     []string
-	LimitReqOptions          LimitReqOptions
-	LimitReqs                []LimitReq
-	JWTAuth                  *JWTAuth
-	BasicAuth                *BasicAuth
-	EgressMTLS               *EgressMTLS
-	OIDC                     bool
-	APIKey                   *APIKey
-	WAF                      *WAF
-	Dos                      *Dos
-	PoliciesErrorReturn      *Return
-	ServiceName              string
-	IsVSR                    bool
-	VSRName                  string
-	VSRNamespace             string
-	GRPCPass                 string
+	// URIPattern is the pattern of the URIs served by the location. The requests with other URIs get 404.
+	URIPattern string
+	// RemovedArgs are the names of the arguments to remove from the requests, separated by spaces.
+	RemovedArgs           string
+	ArgsRewrites          []ArgsRewrite
+	HasKeepalive          bool
+	ErrorPages            []ErrorPage
+	ProxySSLName          string
+	InternalProxyPass     string
+	Allow                 []string
+	Deny                  []string
+	GeoAccess             string
+	LimitReqOptions       LimitReqOptions
+	LimitReqs             []LimitReq
+	LimitRate             string
+	LimitRateAfter        string
+	JWTAuth               *JWTAuth
+	BasicAuth             *BasicAuth
+	EgressMTLS            *EgressMTLS
+	OIDC                  *OIDC
+	APIKey                *APIKey
+	OAuth2Introspection   *OAuth2Introspection
+	SignatureVerification *SignatureVerification
+	Challenge             *Challenge
+	WAF                   *WAF
+	Dos                   *Dos
+	PoliciesErrorReturn   *Return
+	AllowedMethods        []string
+	MaxURILength          int
+	ServiceName           string
+	IsVSR                 bool
+	VSRName               string
+	VSRNamespace          string
+	GRPCPass              string
+	Compression           []Compression
+	SubFilters            []SubFilter
+	SubFilterOnce         bool
+	SubFilterTypes        []string
+	Static                *Static
+	ObjectStorage         *ObjectStorage
+}
+
+// Static defines the serving of files in a location.
+type Static struct {
+	// Alias replaces the path of the location in the URI of the requests. When it is empty, Root is used.
+	Alias    string
+	Root     string
+	Index    []string
+	TryFiles []string
+	Expires  string
+}
+
+// ObjectStorage defines the signing of the requests to an S3-compatible object storage in a location.
+type ObjectStorage struct {
+	Host            string
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// SubFilter defines a sub_filter of a location.
+type SubFilter struct {
+	Match       string
+	Replacement string
+}
+
+// Compression defines the compression of responses with an encoding.
+type Compression struct {
+	// Module is the module of the encoding and the prefix of its directives: gzip, brotli or zstd.
+	Module    string
+	Enable    bool
+	Types     []string
+	MinLength string
+	Level     string
+	Static    bool
 }
 
 // ReturnLocation defines a location for returning a fixed response.
@@ -431,6 +596,9 @@ type JWTAuth struct {
 	Token    string
 	KeyCache string
 	JwksURI  JwksURI
+	// Require holds the variables that must be non-empty and not "0" for a token to be accepted.
+	Require          []string
+	RequireErrorCode int
 }
 
 // JwksURI defines the components of a JwksURI
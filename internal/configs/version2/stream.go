@@ -59,6 +59,24 @@ type StreamServer struct {
 	SSL                      *StreamSSL
 	IPv4                     string
 	IPv6                     string
+	LatencyMetrics           bool
+	Maintenance              *StreamMaintenance
+	LimitConn                *StreamLimitConn
+	ProxyUploadRate          string
+	ProxyDownloadRate        string
+}
+
+// StreamLimitConn defines the limit of the simultaneous connections per key of a server in the stream module.
+type StreamLimitConn struct {
+	ZoneName    string
+	Key         string
+	ZoneSize    string
+	Connections int
+}
+
+// StreamMaintenance defines the maintenance mode of a server in the stream module.
+type StreamMaintenance struct {
+	AllowList []string
 }
 
 // StreamSSL defines SSL configuration for a server.
@@ -196,23 +196,8 @@ server {
     set $resource_name "{{$s.VSName}}";
     set $resource_namespace "{{$s.VSNamespace}}";
 
-    {{- with $oidc := $s.OIDC }}
+    {{- if $s.OIDCProviders }}
     include oidc/oidc.conf;
-
-    set $oidc_pkce_enable 0;
-    set $oidc_logout_redirect "{{ $oidc.PostLogoutRedirectURI }}";
-    set $oidc_hmac_key "{{ $s.VSName }}";
-    set $zone_sync_leeway {{ $oidc.ZoneSyncLeeway }};
-
-    set $oidc_authz_endpoint "{{ $oidc.AuthEndpoint }}";
-    set $oidc_authz_extra_args "{{ $oidc.AuthExtraArgs }}";
-    set $oidc_token_endpoint "{{ $oidc.TokenEndpoint }}";
-    set $oidc_end_session_endpoint "{{ $oidc.EndSessionEndpoint }}";
-    set $oidc_jwt_keyfile "{{ $oidc.JwksURI }}";
-    set $oidc_scopes "{{ $oidc.Scope }}";
-    set $oidc_client "{{ $oidc.ClientID }}";
-    set $oidc_client_secret "{{ $oidc.ClientSecret }}";
-    set $redir_location "{{ $oidc.RedirectURI }}";
     {{- end }}
 
     {{- with $ssl := $s.SSL }}
@@ -568,13 +553,13 @@ server {
         {{ $proxyOrGRPC }}_ssl_name {{ .SSLName }};
         {{- end }}
 
-        {{- if $l.OIDC }}
-        auth_jwt "" token=$session_jwt;
+        {{- with $l.OIDC }}
+        auth_jwt "" token=$session_jwt_{{ .Key }};
         error_page 401 = @do_oidc_flow;
         auth_jwt_key_request /_jwks_uri;
         {{- $proxyOrGRPC }}_set_header username $jwt_claim_sub;
-            {{- if $s.OIDC.AccessTokenEnable }}
-        {{ $proxyOrGRPC }}_set_header Authorization "Bearer $access_token";
+            {{- if .AccessTokenEnable }}
+        {{ $proxyOrGRPC }}_set_header Authorization "Bearer $access_token_{{ .Key }}";
             {{- end }}
         {{- end }}
 
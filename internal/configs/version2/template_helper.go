@@ -207,7 +207,8 @@ func makeTransportListener(s StreamServer) string {
 	return directives
 }
 
-func makeHeaderQueryValue(apiKey APIKey) string {
+// MakeHeaderQueryValue returns the NGINX variables of the headers and query parameters an API key is read from.
+func MakeHeaderQueryValue(apiKey APIKey) string {
 	var parts []string
 
 	for _, header := range apiKey.Header {
@@ -243,7 +244,7 @@ var helperFunctions = template.FuncMap{
 	"makeHTTPListener":      makeHTTPListener,
 	"makeHTTPSListener":     makeHTTPSListener,
 	"makeSecretPath":        commonhelpers.MakeSecretPath,
-	"makeHeaderQueryValue":  makeHeaderQueryValue,
+	"makeHeaderQueryValue":  MakeHeaderQueryValue,
 	"makeTransportListener": makeTransportListener,
 	"makeServerName":        makeServerName,
 }
@@ -716,7 +716,7 @@ func TestMakeHeaderQueryValue(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		got := makeHeaderQueryValue(tc.apiKey)
+		got := MakeHeaderQueryValue(tc.apiKey)
 		if !cmp.Equal(tc.expected, got) {
 			t.Error(cmp.Diff(tc.expected, got))
 		}
@@ -93,6 +93,410 @@ func TestExecuteVirtualServerTemplate_RendersTemplateWithServerGunzipNotSet(t *t
 	t.Log(string(got))
 }
 
+func TestExecuteVirtualServerTemplate_RendersTemplateWithCompression(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithCompression)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			"    gzip on;",
+			"    gzip_types application/json text/css;",
+			"    gzip_min_length 1000;",
+			"    gzip_comp_level 5;",
+			"    gzip_static on;",
+			"    gzip_proxied any;",
+			"    brotli on;",
+			"        gzip off;",
+			"        brotli off;",
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithSubFilters(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithSubFilters)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			`proxy_set_header Accept-Encoding "";`,
+			`sub_filter "http://legacy.internal" "https://cafe.example.com";`,
+			"sub_filter_once off;",
+			"sub_filter_types application/json text/css;",
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithArgsRewrites(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithArgsRewrites)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			`set $args_rewrite_remove "debug tenant";`,
+			`set $args $args_rewrite_removed;`,
+			`set $args "${args}&tenant=${http_x_tenant}";`,
+			`if ($args ~ "^&*(.*?)&*$") {`,
+			`rewrite "^/tea(.*)$" "/tenants/${http_x_tenant}$1" break;`,
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithMaintenance(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithMaintenance)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			"geo $vs_maintenance_default_example_addr {",
+			"10.0.0.0/8 0;",
+			"map $http_x_maintenance_token $vs_maintenance_default_example {",
+			`"c2VjcmV0" 0;`,
+			"if ($vs_maintenance_default_example) {",
+			"rewrite ^ /internal_location_maintenance last;",
+			"add_header Retry-After 3600 always;",
+			`return 503 "Service is under maintenance";`,
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithRequestLimits(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithRequestLimits)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			"client_header_buffer_size 2k;",
+			"large_client_header_buffers 4 16k;",
+			"client_header_timeout 30s;",
+			`if ($request_method !~ "^(GET|HEAD|POST)$") {`,
+			"return 405;",
+			`if ($request_uri ~ "^.{2048}.") {`,
+			"return 414;",
+			"client_max_body_size 10m;",
+			"client_body_timeout 20s;",
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithStaticAndObjectStorage(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithStaticAndObjectStorage)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			`if ($uri !~ "^/docs(/|$)") {`,
+			"alias /etc/nginx/static/cafe/docs;",
+			"index index.html;",
+			"try_files $uri $uri/ =404;",
+			"expires 1h;",
+			`add_header X-Frame-Options "DENY" always;`,
+			`rewrite "^/assets(?:/(.*))?$" "/$1" break;`,
+			`set $object_storage_bucket "assets";`,
+			"limit_except GET {",
+			`proxy_set_header Authorization "$object_storage_authorization";`,
+			"proxy_pass http://vs_default_example_minio$object_storage_uri;",
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithJWTAuthorization(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+	got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithJWTAuthorization)
+	if err != nil {
+		t.Error(err)
+	}
+	wantDirectives := []string{
+		"auth_jwt_claim_set $jwt_default_cafe_groups groups;",
+		"map $jwt_default_cafe_groups $pol_jwt_default_jwt_default_cafe_require_0 {",
+		`"~(^|,)admins(,|$)" 1;`,
+		"auth_jwt_require $pol_jwt_default_jwt_default_cafe_require_0 error=403;",
+		"proxy_set_header X-User \"$jwt_default_cafe_sub\";",
+	}
+	for _, want := range wantDirectives {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+	}
+	snaps.MatchSnapshot(t, string(got))
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithMultipleOIDCProviders(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+	got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithOIDCProviders)
+	if err != nil {
+		t.Error(err)
+	}
+	wantDirectives := []string{
+		"keyval_zone zone=oidc_id_tokens_default_cafe_default_oidc_tea:1M timeout=1h sync;",
+		"keyval $cookie_auth_token $session_jwt_default_cafe_default_oidc_tea zone=oidc_id_tokens_default_cafe_default_oidc_tea;",
+		"keyval_zone zone=oidc_refresh_tokens_default_cafe_default_oidc_coffee:1M timeout=1d sync;",
+		"keyval $cookie_coffee_session $session_jwt_default_cafe_default_oidc_coffee zone=oidc_id_tokens_default_cafe_default_oidc_coffee;",
+		"keyval $pkce_id $pkce_code_verifier_default_cafe_default_oidc_coffee zone=oidc_pkce_default_cafe_default_oidc_coffee;",
+		"location = /coffee/callback {",
+		"location = /coffee/logout {",
+		"auth_jwt \"\" token=$session_jwt_default_cafe_default_oidc_coffee;",
+		"set $oidc_cookie_domain \" Domain=.example.com;\";",
+	}
+	for _, want := range wantDirectives {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+	}
+	snaps.MatchSnapshot(t, string(got))
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithOAuth2Introspection(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithOAuth2Introspection)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			"js_shared_dict_zone zone=oauth2_introspection_default_introspection_policy_default_cafe:1M timeout=5m evict;",
+			"location = /_oauth2_introspection_default_introspection_policy_default_cafe {",
+			`set $oauth2_introspection_fields "username client_id";`,
+			"js_content oauth2_introspection.validate;",
+			`proxy_set_header Authorization "Basic bmljOnNlY3JldA==";`,
+			"proxy_ssl_server_name on;",
+			"proxy_pass https://idp.example.com:8443/oauth2/introspect;",
+			"auth_request /_oauth2_introspection_default_introspection_policy_default_cafe;",
+			"auth_request_set $oauth2_introspection_field_1 $sent_http_x_introspection_field_1;",
+			"proxy_set_header X-Client-Id $oauth2_introspection_field_1;",
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithAPIKeyAndOAuth2Introspection(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithAPIKeyAndOAuth2Introspection)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			"location = /_validate_apikey_njs {",
+			`set $apikey_auth_local_map  "apikey_auth_client_name_default_cafe_api_key_policy";`,
+			`set $header_query_value "${http_x_api_key}";`,
+			"auth_request /_oauth2_introspection_default_introspection_policy_default_cafe;",
+			"proxy_set_header X-Client-Id $oauth2_introspection_field_1;",
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		// The API key is validated by the introspection subrequest, as a location has only one auth_request.
+		if bytes.Contains(got, []byte("auth_request /_validate_apikey_njs;")) {
+			t.Error("want no auth_request of the API key in generated template")
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithSignatureVerification(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithSignatureVerification)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			`set $signature_verification_header "X-Hub-Signature-256";`,
+			`set $signature_verification_prefix "sha256=";`,
+			`set $signature_verification_payload "{timestamp}.{body}";`,
+			`set $signature_verification_key "c2VjcmV0";`,
+			"set $signature_verification_timestamp_tolerance 300;",
+			"set $signature_verification_location @signature_verification_0;",
+			"client_body_buffer_size 1m;",
+			"client_body_in_single_buffer on;",
+			"js_content signature_verification.verify;",
+			"location @signature_verification_0 {",
+			`rewrite "^/webhooks(.*)$" "/events$1" break;`,
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		if n := bytes.Count(got, []byte("proxy_pass http://vs_default_cafe_webhooks;")); n != 1 {
+			t.Errorf("want proxy_pass only in the named location, got it %d times", n)
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithGeoAccess(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithGeoAccess)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			`map "$geoip2_country_code:$geoip2_asn" $geo_access_default_allow_eu_default_cafe {`,
+			`"~^DE:" 1;`,
+			"if ($geo_access_default_allow_eu_default_cafe = 0) {",
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithChallenge(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		executor       *TemplateExecutor
+		wantDirectives []string
+	}{
+		{
+			executor: newTmplExecutorNGINXPlus(t),
+			wantDirectives: []string{
+				"keyval_zone zone=challenge_blocked_default_challenge_default_cafe:1M timeout=10m;",
+				"keyval $remote_addr $challenge_failures_default_challenge_default_cafe zone=challenge_failures_default_challenge_default_cafe;",
+				"set $challenge_store keyval;",
+			},
+		},
+		{
+			executor: newTmplExecutorNGINX(t),
+			wantDirectives: []string{
+				"js_shared_dict_zone zone=challenge_failures_default_challenge_default_cafe:1M timeout=1m type=number evict;",
+				"js_shared_dict_zone zone=challenge_blocked_default_challenge_default_cafe:1M timeout=10m evict;",
+				"set $challenge_store shared_dict;",
+			},
+		},
+	}
+	for _, test := range tests {
+		got, err := test.executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithChallenge)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := append([]string{
+			"geo $challenge_exempt_addr_default_challenge_default_cafe {",
+			"default $challenge_exempt_addr_default_challenge_default_cafe;",
+			`"~*Googlebot" 1;`,
+			"location = /_challenge_default_challenge_default_cafe {",
+			"js_content challenge.issue;",
+			`set $challenge_secret_key "c2VjcmV0";`,
+			"set $challenge_cookie_lifetime 3600;",
+			"rewrite ^ /_challenge_default_challenge_default_cafe last;",
+		}, test.wantDirectives...)
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteVirtualServerTemplate_RendersTemplateWithBandwidthLimit(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithBandwidthLimit)
+		if err != nil {
+			t.Error(err)
+		}
+		wantDirectives := []string{
+			`map "${http_x_tenant}" $bandwidth_limit_default_bandwidth_default_cafe {`,
+			`"\\premium" 1m;`,
+			"limit_rate $bandwidth_limit_default_bandwidth_default_cafe;",
+			"limit_rate_after 1m;",
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
 func TestExecuteVirtualServerTemplate_RendersTemplateWithRateLimitJWTClaim(t *testing.T) {
 	t.Parallel()
 	executor := newTmplExecutorNGINXPlus(t)
@@ -547,6 +951,67 @@ func TestTransportServerForNginx(t *testing.T) {
 	t.Log(string(data))
 }
 
+func TestExecuteTemplateForTransportServerWithMaintenance(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		maintenanceTransportServerCfg := transportServerCfg
+		maintenanceTransportServerCfg.Server.Maintenance = &StreamMaintenance{
+			AllowList: []string{"10.0.0.0/8"},
+		}
+
+		got, err := executor.ExecuteTransportServerTemplate(&maintenanceTransportServerCfg)
+		if err != nil {
+			t.Error(err)
+		}
+		wantStrings := []string{
+			"allow 10.0.0.0/8;",
+			"deny all;",
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
+func TestExecuteTemplateForTransportServerWithRateLimit(t *testing.T) {
+	t.Parallel()
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, executor := range executors {
+		rateLimitTransportServerCfg := transportServerCfg
+		rateLimitTransportServerCfg.Server.LimitConn = &StreamLimitConn{
+			ZoneName:    "ts_limit_conn_default_tcp-server",
+			Key:         "${binary_remote_addr}",
+			ZoneSize:    "10M",
+			Connections: 10,
+		}
+		rateLimitTransportServerCfg.Server.ProxyUploadRate = "100k"
+		rateLimitTransportServerCfg.Server.ProxyDownloadRate = "1m"
+
+		got, err := executor.ExecuteTransportServerTemplate(&rateLimitTransportServerCfg)
+		if err != nil {
+			t.Error(err)
+		}
+		wantStrings := []string{
+			"limit_conn_zone ${binary_remote_addr} zone=ts_limit_conn_default_tcp-server:10M;",
+			"limit_conn ts_limit_conn_default_tcp-server 10;",
+			"proxy_upload_rate 100k;",
+			"proxy_download_rate 1m;",
+		}
+		for _, want := range wantStrings {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want `%s` in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
 func TestExecuteTemplateForTransportServerWithTCPIPListener(t *testing.T) {
 	t.Parallel()
 	executor := newTmplExecutorNGINXPlus(t)
@@ -780,6 +1245,41 @@ func TestExecuteVirtualServerTemplateWithAPIKeyPolicyNGINXPlus(t *testing.T) {
 	t.Log(string(got))
 }
 
+func TestExecuteVirtualServerTemplateWithAPIKeyPolicyClientMetadata(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.APIKey = &APIKey{
+		Header:         []string{"X-API-Key"},
+		MapName:        "apikey_auth_client_name_default_cafe_api_key_policy",
+		ExpiresMapName: "apikey_auth_client_expires_default_cafe_api_key_policy",
+		TierMapName:    "apikey_auth_client_tier_default_cafe_api_key_policy",
+		ClientIDHeader: "X-Client-ID",
+	}
+
+	executors := []*TemplateExecutor{newTmplExecutorNGINXPlus(t), newTmplExecutorNGINX(t)}
+	for _, e := range executors {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Error(err)
+		}
+
+		wantDirectives := []string{
+			"set $apikey_client_id $apikey_auth_client_name_default_cafe_api_key_policy;",
+			`set $apikey_client_expires "$apikey_auth_client_expires_default_cafe_api_key_policy";`,
+			`set $apikey_client_tier "$apikey_auth_client_tier_default_cafe_api_key_policy";`,
+			"proxy_set_header X-Client-ID $apikey_client_id;",
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+		snaps.MatchSnapshot(t, string(got))
+		t.Log(string(got))
+	}
+}
+
 func vsConfig() VirtualServerConfig {
 	return VirtualServerConfig{
 		LimitReqZones: []LimitReqZone{
@@ -1529,6 +2029,477 @@ var (
 		},
 	}
 
+	virtualServerCfgWithCompression = VirtualServerConfig{
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "example",
+			Compression: []Compression{
+				{
+					Module:    "gzip",
+					Enable:    true,
+					Types:     []string{"application/json", "text/css"},
+					MinLength: "1000",
+					Level:     "5",
+					Static:    true,
+				},
+				{
+					Module:    "brotli",
+					Enable:    true,
+					Types:     []string{"application/json", "text/css"},
+					MinLength: "1000",
+					Level:     "5",
+				},
+			},
+			Locations: []Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://test-upstream",
+				},
+				{
+					Path:      "/images",
+					ProxyPass: "http://test-upstream",
+					Compression: []Compression{
+						{Module: "gzip"},
+						{Module: "brotli"},
+					},
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithSubFilters = VirtualServerConfig{
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "example",
+			Locations: []Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://test-upstream",
+					ProxySetHeaders: []Header{
+						{Name: "Host", Value: "$host"},
+						{Name: "Accept-Encoding", Value: ""},
+					},
+					SubFilters: []SubFilter{
+						{Match: "http://legacy.internal", Replacement: "https://cafe.example.com"},
+					},
+					SubFilterTypes: []string{"application/json", "text/css"},
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithArgsRewrites = VirtualServerConfig{
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "example",
+			Locations: []Location{
+				{
+					Path:      "/tea",
+					ProxyPass: "http://test-upstream",
+					ProxySetHeaders: []Header{
+						{Name: "Host", Value: "$host"},
+					},
+					Rewrites:    []string{`"^/tea(.*)$" "/tenants/${http_x_tenant}$1" break`},
+					RemovedArgs: "debug tenant",
+					ArgsRewrites: []ArgsRewrite{
+						{Value: "${args}&tenant=${http_x_tenant}"},
+						{Regex: "^&*(.*?)&*$", Value: "$1"},
+					},
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithMaintenance = VirtualServerConfig{
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "example",
+			Locations: []Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://test-upstream",
+				},
+			},
+			Maintenance: &Maintenance{
+				Variable:     "$vs_maintenance_default_example",
+				AddrVariable: "$vs_maintenance_default_example_addr",
+				AllowList:    []string{"10.0.0.0/8", "192.168.1.1"},
+				Header:       "$http_x_maintenance_token",
+				Token:        "c2VjcmV0",
+				Location:     "/internal_location_maintenance",
+				DefaultType:  "text/plain",
+				Body:         "Service is under maintenance",
+				RetryAfter:   3600,
+			},
+		},
+	}
+
+	virtualServerCfgWithRequestLimits = VirtualServerConfig{
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "example",
+			RequestLimits: &ServerRequestLimits{
+				ClientHeaderBufferSize:   "2k",
+				LargeClientHeaderBuffers: "4 16k",
+				ClientHeaderTimeout:      "30s",
+			},
+			Locations: []Location{
+				{
+					Path:              "/",
+					ProxyPass:         "http://test-upstream",
+					ClientMaxBodySize: "10m",
+					ClientBodyTimeout: "20s",
+					AllowedMethods:    []string{"GET", "HEAD", "POST"},
+					MaxURILength:      2048,
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithStaticAndObjectStorage = VirtualServerConfig{
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "example",
+			Locations: []Location{
+				{
+					Path:       "/docs",
+					URIPattern: "^/docs(/|$)",
+					Static: &Static{
+						Alias:    "/etc/nginx/static/cafe/docs",
+						Index:    []string{"index.html"},
+						TryFiles: []string{"$uri", "$uri/", "=404"},
+						Expires:  "1h",
+					},
+					AddHeaders: []AddHeader{
+						{Header: Header{Name: "X-Frame-Options", Value: "DENY"}, Always: true},
+					},
+				},
+				{
+					Path:                    "/assets/",
+					ProxyPass:               "http://vs_default_example_minio",
+					ProxyPassRewrite:        "$object_storage_uri",
+					ProxyPassRequestHeaders: false,
+					ProxySetHeaders: []Header{
+						{Name: "Host", Value: "$object_storage_host"},
+						{Name: "x-amz-date", Value: "$object_storage_date"},
+						{Name: "x-amz-content-sha256", Value: "UNSIGNED-PAYLOAD"},
+						{Name: "Authorization", Value: "$object_storage_authorization"},
+					},
+					Rewrites: []string{`"^/assets(?:/(.*))?$" "/$1" break`},
+					ObjectStorage: &ObjectStorage{
+						Host:            "minio.default.svc",
+						Bucket:          "assets",
+						Region:          "us-east-1",
+						AccessKeyID:     "minioadmin",
+						SecretAccessKey: "miniosecret",
+					},
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithJWTAuthorization = VirtualServerConfig{
+		Maps: []Map{
+			{
+				Source:   "$jwt_default_cafe_groups",
+				Variable: "$pol_jwt_default_jwt_default_cafe_require_0",
+				Parameters: []Parameter{
+					{Value: `"~(^|,)admins(,|$)"`, Result: "1"},
+					{Value: "default", Result: "0"},
+				},
+			},
+		},
+		AuthJWTClaimSets: []AuthJWTClaimSet{
+			{Variable: "$jwt_default_cafe_groups", Claim: "groups"},
+			{Variable: "$jwt_default_cafe_sub", Claim: "sub"},
+		},
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "cafe",
+			JWTAuth: &JWTAuth{
+				Realm:            "My API",
+				Secret:           "/etc/nginx/secrets/default-jwk",
+				Require:          []string{"$pol_jwt_default_jwt_default_cafe_require_0"},
+				RequireErrorCode: 403,
+			},
+			Locations: []Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://vs_default_cafe_backend",
+					ProxySetHeaders: []Header{
+						{Name: "X-User", Value: "$jwt_default_cafe_sub"},
+					},
+				},
+			},
+		},
+	}
+
+	oidcProviderTea = &OIDC{
+		Key:                   "default_cafe_default_oidc_tea",
+		AuthEndpoint:          "https://tea.example.com/auth",
+		TokenEndpoint:         "https://tea.example.com/token",
+		JwksURI:               "https://tea.example.com/certs",
+		ClientID:              "tea",
+		ClientSecret:          "super_secret_123",
+		Scope:                 "openid",
+		RedirectURI:           "/_codexch",
+		LogoutURI:             "/logout",
+		PostLogoutRedirectURI: "/_logout",
+		ZoneSyncLeeway:        200,
+		HMACKey:               "cafe",
+		CookieName:            "auth_token",
+		CookieSameSite:        "lax",
+		SessionTimeout:        "1h",
+		RefreshTimeout:        "8h",
+	}
+
+	oidcProviderCoffee = &OIDC{
+		Key:                   "default_cafe_default_oidc_coffee",
+		AuthEndpoint:          "https://coffee.example.com/auth",
+		TokenEndpoint:         "https://coffee.example.com/token",
+		JwksURI:               "https://coffee.example.com/certs",
+		ClientID:              "coffee",
+		Scope:                 "openid",
+		RedirectURI:           "/coffee/callback",
+		LogoutURI:             "/coffee/logout",
+		PostLogoutRedirectURI: "/_logout",
+		ZoneSyncLeeway:        200,
+		AccessTokenEnable:     true,
+		PKCEEnable:            true,
+		HMACKey:               "cafe",
+		CookieName:            "coffee_session",
+		CookieDomain:          ".example.com",
+		CookieSameSite:        "strict",
+		SessionTimeout:        "30m",
+		RefreshTimeout:        "1d",
+	}
+
+	virtualServerCfgWithOIDCProviders = VirtualServerConfig{
+		Server: Server{
+			ServerName:    "example.com",
+			StatusZone:    "example.com",
+			VSNamespace:   "default",
+			VSName:        "cafe",
+			OIDCProviders: []*OIDC{oidcProviderTea, oidcProviderCoffee},
+			Locations: []Location{
+				{
+					Path:      "/tea",
+					ProxyPass: "http://vs_default_cafe_tea",
+					OIDC:      oidcProviderTea,
+				},
+				{
+					Path:      "/coffee",
+					ProxyPass: "http://vs_default_cafe_coffee",
+					OIDC:      oidcProviderCoffee,
+				},
+			},
+		},
+	}
+
+	oauth2Introspection = &OAuth2Introspection{
+		Key:            "default_introspection_policy_default_cafe",
+		EndpointScheme: "https",
+		EndpointHost:   "idp.example.com",
+		EndpointPort:   "8443",
+		EndpointPath:   "/oauth2/introspect",
+		Authorization:  "Basic bmljOnNlY3JldA==",
+		Scopes:         "read",
+		Audiences:      "cafe",
+		CacheTimeout:   "5m",
+		Headers: []OAuth2IntrospectionHeader{
+			{
+				Name:  "X-User",
+				Field: "username",
+			},
+			{
+				Name:  "X-Client-Id",
+				Field: "client_id",
+			},
+		},
+	}
+
+	virtualServerCfgWithOAuth2Introspection = VirtualServerConfig{
+		Server: Server{
+			ServerName:           "example.com",
+			StatusZone:           "example.com",
+			VSNamespace:          "default",
+			VSName:               "cafe",
+			OAuth2Introspections: []*OAuth2Introspection{oauth2Introspection},
+			Locations: []Location{
+				{
+					Path:                "/tea",
+					ProxyPass:           "http://vs_default_cafe_tea",
+					OAuth2Introspection: oauth2Introspection,
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithAPIKeyAndOAuth2Introspection = VirtualServerConfig{
+		Server: Server{
+			ServerName:           "example.com",
+			StatusZone:           "example.com",
+			VSNamespace:          "default",
+			VSName:               "cafe",
+			APIKeyEnabled:        true,
+			OAuth2Introspections: []*OAuth2Introspection{oauth2Introspection},
+			Locations: []Location{
+				{
+					Path:      "/tea",
+					ProxyPass: "http://vs_default_cafe_tea",
+					APIKey: &APIKey{
+						Header:  []string{"X-API-Key"},
+						MapName: "apikey_auth_client_name_default_cafe_api_key_policy",
+					},
+					OAuth2Introspection: oauth2Introspection,
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithSignatureVerification = VirtualServerConfig{
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "cafe",
+			Locations: []Location{
+				{
+					Path:              "/webhooks",
+					ProxyPass:         "http://vs_default_cafe_webhooks",
+					ClientMaxBodySize: "1m",
+					SignatureVerification: &SignatureVerification{
+						Header:             "X-Hub-Signature-256",
+						Algorithm:          "sha256",
+						Encoding:           "hex",
+						Prefix:             "sha256=",
+						Key:                "c2VjcmV0",
+						TimestampHeader:    "X-Timestamp",
+						TimestampTolerance: 300,
+						SignedPayload:      "{timestamp}.{body}",
+						BodyBufferSize:     "1m",
+						Location:           "@signature_verification_0",
+					},
+				},
+				{
+					Path:              "@signature_verification_0",
+					ProxyPass:         "http://vs_default_cafe_webhooks",
+					ClientMaxBodySize: "1m",
+					Rewrites:          []string{`"^/webhooks(.*)$" "/events$1" break`},
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithGeoAccess = VirtualServerConfig{
+		Maps: []Map{
+			{
+				Source:   `"$geoip2_country_code:$geoip2_asn"`,
+				Variable: "$geo_access_default_allow_eu_default_cafe",
+				Parameters: []Parameter{
+					{Value: "default", Result: "0"},
+					{Value: `"~^DE:"`, Result: "1"},
+					{Value: `"~^FR:"`, Result: "1"},
+				},
+			},
+		},
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "cafe",
+			Locations: []Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://vs_default_cafe_tea",
+					GeoAccess: "$geo_access_default_allow_eu_default_cafe",
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithChallenge = VirtualServerConfig{
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "cafe",
+			Challenges: []*Challenge{
+				{
+					Key:              "default_challenge_default_cafe",
+					Type:             "javascript",
+					SecretKey:        "c2VjcmV0",
+					CookieName:       "nic_challenge",
+					CookieLifetime:   3600,
+					ExemptCIDRs:      []string{"10.0.0.0/8"},
+					ExemptUserAgents: []string{"Googlebot"},
+					BlockThreshold:   10,
+					BlockInterval:    "1m",
+					BlockDuration:    "10m",
+				},
+			},
+			Locations: []Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://vs_default_cafe_tea",
+					Challenge: &Challenge{
+						Key:              "default_challenge_default_cafe",
+						Type:             "javascript",
+						SecretKey:        "c2VjcmV0",
+						CookieName:       "nic_challenge",
+						CookieLifetime:   3600,
+						ExemptCIDRs:      []string{"10.0.0.0/8"},
+						ExemptUserAgents: []string{"Googlebot"},
+						BlockThreshold:   10,
+						BlockInterval:    "1m",
+						BlockDuration:    "10m",
+					},
+				},
+			},
+		},
+	}
+
+	virtualServerCfgWithBandwidthLimit = VirtualServerConfig{
+		Maps: []Map{
+			{
+				Source:   `"${http_x_tenant}"`,
+				Variable: "$bandwidth_limit_default_bandwidth_default_cafe",
+				Parameters: []Parameter{
+					{Value: "default", Result: "100k"},
+					{Value: `"\\premium"`, Result: "1m"},
+				},
+			},
+		},
+		Server: Server{
+			ServerName:  "example.com",
+			StatusZone:  "example.com",
+			VSNamespace: "default",
+			VSName:      "cafe",
+			Locations: []Location{
+				{
+					Path:           "/",
+					ProxyPass:      "http://vs_default_cafe_tea",
+					LimitRate:      "$bandwidth_limit_default_bandwidth_default_cafe",
+					LimitRateAfter: "1m",
+				},
+			},
+		},
+	}
+
 	virtualServerCfgWithGunzipOn = VirtualServerConfig{
 		Server: Server{
 			ServerName: "example.com",
@@ -3,14 +3,18 @@ package configs
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"net/url"
 	"os"
 	"path"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
 	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
@@ -18,6 +22,7 @@ import (
 	"github.com/nginx/kubernetes-ingress/internal/nginx"
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	api_v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +39,9 @@ const (
 	splitClientsKeyValZoneSize                      = "100k"
 	splitClientAmountWhenWeightChangesDynamicReload = 101
 	defaultLogOutput                                = "syslog:server=localhost:514"
+	defaultOIDCRedirectURI                          = "/_codexch"
+	defaultOIDCLogoutURI                            = "/logout"
+	defaultOIDCCookieName                           = "auth_token"
 )
 
 var grpcConflictingErrors = map[int]bool{
@@ -102,6 +110,7 @@ type VirtualServerEx struct {
 	LogConfRefs         map[string]*unstructured.Unstructured
 	DosProtectedRefs    map[string]*unstructured.Unstructured
 	DosProtectedEx      map[string]*DosEx
+	DeniedReferences    map[string]error
 }
 
 func (vsx *VirtualServerEx) String() string {
@@ -177,6 +186,8 @@ func (namer *upstreamNamer) GetNameForUpstreamFromAction(action *conf_v1.Action)
 	var upstream string
 	if action.Proxy != nil && action.Proxy.Upstream != "" {
 		upstream = action.Proxy.Upstream
+	} else if action.ObjectStorage != nil {
+		upstream = action.ObjectStorage.Upstream
 	} else {
 		upstream = action.Pass
 	}
@@ -236,6 +247,17 @@ func (namer *VariableNamer) GetNameForSplitClientVariable(index int) string {
 	return fmt.Sprintf("$vs_%s_splits_%d", namer.safeNsName, index)
 }
 
+// GetNameForCanaryVariable gets the name of the variable of a canary release for a particular scIndex.
+func (namer *VariableNamer) GetNameForCanaryVariable(index int) string {
+	return fmt.Sprintf("$vs_%s_canary_%d", namer.safeNsName, index)
+}
+
+// GetNameForCanaryStickyCookieVariable gets the name of the variable of the sticky cookie of a canary release
+// for a particular scIndex and split.
+func (namer *VariableNamer) GetNameForCanaryStickyCookieVariable(index int, split int) string {
+	return fmt.Sprintf("$vs_%s_canary_%d_sticky_cookie_%d", namer.safeNsName, index, split)
+}
+
 // GetNameForVariableForMatchesRouteMap gets the name of a matches route map
 func (namer *VariableNamer) GetNameForVariableForMatchesRouteMap(
 	matchesIndex int,
@@ -292,11 +314,24 @@ type virtualServerConfigurator struct {
 	DynamicWeightChangesReload bool
 	bundleValidator            bundleValidator
 	IngressControllerReplicas  int
+	now                        time.Time
+	staticCompressionModules   map[string]bool
 }
 
+// oidcPolicyCfg holds the OIDC providers of a VirtualServer and its VirtualServerRoutes.
+// Every OIDC policy referenced by the routes is a separate provider.
 type oidcPolicyCfg struct {
-	oidc *version2.OIDC
-	key  string
+	providers []*version2.OIDC
+	keys      map[string]*version2.OIDC
+	// defaults holds the settings of the providers that use the default values.
+	defaults map[*version2.OIDC]oidcDefaults
+}
+
+// oidcDefaults records which settings of an OIDC provider use the default values.
+type oidcDefaults struct {
+	redirectURI bool
+	logoutURI   bool
+	cookieName  bool
 }
 
 func (vsc *virtualServerConfigurator) addWarningf(obj runtime.Object, msgFmt string, args ...interface{}) {
@@ -341,6 +376,11 @@ func newVirtualServerConfigurator(
 		StaticSSLPath:              staticParams.StaticSSLPath,
 		DynamicWeightChangesReload: staticParams.DynamicWeightChangesReload,
 		bundleValidator:            bundleValidator,
+		staticCompressionModules: map[string]bool{
+			"gzip":   true,
+			"brotli": staticParams.NginxModules.BrotliStatic,
+			"zstd":   staticParams.NginxModules.ZstdStatic,
+		},
 	}
 }
 
@@ -350,6 +390,14 @@ func (vsc *virtualServerConfigurator) generateEndpointsForUpstream(
 	upstream conf_v1.Upstream,
 	virtualServerEx *VirtualServerEx,
 ) []string {
+	if err := getDeniedReference(virtualServerEx.DeniedReferences, owner, conf_v1.ReferenceGrantKindService, GenerateExternalNameSvcKey(namespace, upstream.Service)); err != nil {
+		vsc.addWarningf(owner, "Service %s/%s of upstream %s cannot be referenced: %v", namespace, upstream.Service, upstream.Name, err)
+		if !vsc.isPlus {
+			return []string{nginx502Server}
+		}
+		return []string{}
+	}
+
 	endpointsKey := GenerateEndpointsKey(namespace, upstream.Service, upstream.Subselector, upstream.Port)
 	externalNameSvcKey := GenerateExternalNameSvcKey(namespace, upstream.Service)
 	endpoints := virtualServerEx.Endpoints[endpointsKey]
@@ -376,6 +424,10 @@ func (vsc *virtualServerConfigurator) generateBackupEndpointsForUpstream(
 	if upstream.Backup == "" || upstream.BackupPort == nil {
 		return []string{}
 	}
+	if err := getDeniedReference(virtualServerEx.DeniedReferences, owner, conf_v1.ReferenceGrantKindService, GenerateExternalNameSvcKey(namespace, upstream.Backup)); err != nil {
+		vsc.addWarningf(owner, "Backup service %s/%s of upstream %s cannot be referenced: %v", namespace, upstream.Backup, upstream.Name, err)
+		return []string{}
+	}
 	externalNameSvcKey := GenerateExternalNameSvcKey(namespace, upstream.Backup)
 	_, isExternalNameSvc := virtualServerEx.ExternalNameSvcs[externalNameSvcKey]
 	if isExternalNameSvc && !vsc.isResolverConfigured {
@@ -392,6 +444,479 @@ func (vsc *virtualServerConfigurator) generateBackupEndpointsForUpstream(
 	return backupEndpoints
 }
 
+// addRetryConfig adds the retry policy of a route. The policy sets the cases and limits of passing a request
+// to the next upstream server. It is not allowed in the spec context.
+func (p *policiesCfg) addRetryConfig(retryPol *conf_v1.Retry, polKey string, context string) *validationResults {
+	res := newValidationResults()
+	if context == specContext {
+		res.addWarningf("Retry policy %s is not allowed in the %v context", polKey, context)
+		res.isError = true
+		return res
+	}
+	if p.Retry != nil {
+		res.addWarningf("Multiple retry policies in the same context is not valid. Retry policy %s will be ignored", polKey)
+		return res
+	}
+
+	nextUpstream := "error timeout"
+	if len(retryPol.RetryOn) > 0 {
+		nextUpstream = strings.Join(retryPol.RetryOn, " ")
+	}
+	if retryPol.NonIdempotent {
+		nextUpstream += " non_idempotent"
+	}
+
+	p.Retry = &retry{
+		nextUpstream:      nextUpstream,
+		nextUpstreamTries: retryPol.Tries,
+	}
+	if retryPol.Timeout != "" {
+		p.Retry.nextUpstreamTimeout = generateTime(retryPol.Timeout)
+	}
+	if retryPol.PerTryTimeout != "" {
+		p.Retry.perTryTimeout = generateTime(retryPol.PerTryTimeout)
+	}
+
+	return res
+}
+
+func (p *policiesCfg) addRequestLimitsConfig(limitsPol *conf_v1.RequestLimits, polKey string, context string) *validationResults {
+	res := newValidationResults()
+	if p.RequestLimits != nil {
+		res.addWarningf("Multiple requestLimits policies in the same context is not valid. RequestLimits policy %s will be ignored", polKey)
+		return res
+	}
+
+	limits := &requestLimits{
+		key:            polKey,
+		maxBodySize:    limitsPol.MaxBodySize,
+		allowedMethods: limitsPol.AllowedMethods,
+	}
+	if limitsPol.BodyTimeout != "" {
+		limits.bodyTimeout = generateTime(limitsPol.BodyTimeout)
+	}
+	if limitsPol.MaxURILength != nil {
+		limits.maxURILength = *limitsPol.MaxURILength
+	}
+
+	// NGINX reads the request header before it selects a location
+	if limitsPol.HeaderBufferSize != "" || limitsPol.LargeHeaderBuffers != nil || limitsPol.HeaderTimeout != "" {
+		if context != specContext {
+			res.addWarningf("The headerBufferSize, largeHeaderBuffers and headerTimeout of RequestLimits policy %s are ignored in the %v context", polKey, context)
+		} else {
+			limits.headerBufferSize = limitsPol.HeaderBufferSize
+			limits.largeHeaderBuffers = generateBuffers(limitsPol.LargeHeaderBuffers, "")
+			if limitsPol.HeaderTimeout != "" {
+				limits.headerTimeout = generateTime(limitsPol.HeaderTimeout)
+			}
+		}
+	}
+
+	p.RequestLimits = limits
+	return res
+}
+
+// addCircuitBreakerConfig adds the circuit breaker policy of a route. The policy applies to the upstreams
+// of the route, so only its key is recorded here. It is not allowed in the spec context.
+func (p *policiesCfg) addCircuitBreakerConfig(polKey string, context string) *validationResults {
+	res := newValidationResults()
+	if context == specContext {
+		res.addWarningf("CircuitBreaker policy %s is not allowed in the %v context", polKey, context)
+		res.isError = true
+		return res
+	}
+	if p.CircuitBreaker != nil {
+		res.addWarningf("Multiple circuitBreaker policies in the same context is not valid. CircuitBreaker policy %s will be ignored", polKey)
+		return res
+	}
+
+	p.CircuitBreaker = &circuitBreaker{key: polKey}
+
+	return res
+}
+
+func (p *policiesCfg) addHeadersConfig(
+	headersPol *conf_v1.Headers,
+	polKey string,
+	polNamespace string,
+	polName string,
+	ownerDetails policyOwnerDetails,
+) *validationResults {
+	res := newValidationResults()
+	if p.Headers != nil {
+		res.addWarningf("Multiple headers policies in the same context is not valid. Headers policy %s will be ignored", polKey)
+		return res
+	}
+
+	mapPrefix := rfc1123ToSnake(fmt.Sprintf("pol_hdr_%v_%v_%v_%v", polNamespace, polName, ownerDetails.vsNamespace, ownerDetails.vsName))
+	headers := &policyHeaders{}
+
+	if headersPol.Request != nil {
+		for i, h := range headersPol.Request.Set {
+			value, m := generatePolicyHeaderValue(h, fmt.Sprintf("$%s_req_%d", mapPrefix, i))
+			headers.requestSet = append(headers.requestSet, version2.Header{Name: h.Name, Value: value})
+			if m != nil {
+				headers.maps = append(headers.maps, *m)
+			}
+		}
+		headers.requestRemove = headersPol.Request.Remove
+	}
+
+	if headersPol.Response != nil {
+		for i, h := range headersPol.Response.Add {
+			value, m := generatePolicyHeaderValue(h.PolicyHeader, fmt.Sprintf("$%s_resp_%d", mapPrefix, i))
+			headers.responseAdd = append(headers.responseAdd, version2.AddHeader{
+				Header: version2.Header{Name: h.Name, Value: value},
+				Always: h.Always,
+			})
+			if m != nil {
+				headers.maps = append(headers.maps, *m)
+			}
+		}
+		headers.responseHide = headersPol.Response.Hide
+	}
+
+	p.Headers = headers
+
+	return res
+}
+
+// generatePolicyHeaderValue returns the value of a header of a headers policy. For a header with a value map,
+// the value is the variable of the returned map.
+func generatePolicyHeaderValue(h conf_v1.PolicyHeader, variable string) (string, *version2.Map) {
+	if h.ValueMap == nil {
+		return h.Value, nil
+	}
+
+	var params []version2.Parameter
+	for _, v := range h.ValueMap.Values {
+		params = append(params, version2.Parameter{
+			Value:  fmt.Sprintf("\"%s\"", v.Match),
+			Result: fmt.Sprintf("\"%s\"", v.Value),
+		})
+	}
+	params = append(params, version2.Parameter{
+		Value:  "default",
+		Result: fmt.Sprintf("\"%s\"", h.Value),
+	})
+
+	return variable, &version2.Map{
+		Source:     h.ValueMap.Variable,
+		Variable:   variable,
+		Parameters: params,
+	}
+}
+
+// mergePolicyHeaders merges the headers policy of a route into the headers policy of the spec.
+// The headers of the route take precedence over the headers of the spec with the same name.
+func mergePolicyHeaders(specHeaders *policyHeaders, routeHeaders *policyHeaders) *policyHeaders {
+	if specHeaders == nil {
+		return routeHeaders
+	}
+	if routeHeaders == nil {
+		return specHeaders
+	}
+
+	merged := policyHeaders{
+		requestSet:    slices.Clip(routeHeaders.requestSet),
+		requestRemove: slices.Clip(routeHeaders.requestRemove),
+		responseAdd:   slices.Clip(routeHeaders.responseAdd),
+		responseHide:  slices.Clip(routeHeaders.responseHide),
+	}
+	for _, h := range specHeaders.requestSet {
+		if !hasHeader(merged.requestSet, h.Name) && !containsHeaderName(merged.requestRemove, h.Name) {
+			merged.requestSet = append(merged.requestSet, h)
+		}
+	}
+	for _, name := range specHeaders.requestRemove {
+		if !hasHeader(merged.requestSet, name) && !containsHeaderName(merged.requestRemove, name) {
+			merged.requestRemove = append(merged.requestRemove, name)
+		}
+	}
+	for _, h := range specHeaders.responseAdd {
+		if !hasAddHeader(merged.responseAdd, h.Name) {
+			merged.responseAdd = append(merged.responseAdd, h)
+		}
+	}
+	for _, name := range specHeaders.responseHide {
+		if !containsHeaderName(merged.responseHide, name) {
+			merged.responseHide = append(merged.responseHide, name)
+		}
+	}
+
+	return &merged
+}
+
+// mergeJWTClaimHeaders adds the headers set to the claims of a token by the JWT policy that applies to a route
+// to the headers of the headers policy of the route. The JWT policy of the route takes precedence over the JWT
+// policy of the spec, and the claim headers take precedence over the headers of the headers policy.
+func mergeJWTClaimHeaders(specJWTAuth jwtAuth, routeJWTAuth jwtAuth, headers *policyHeaders) *policyHeaders {
+	claimHeaders := specJWTAuth.Headers
+	if routeJWTAuth.Auth != nil {
+		claimHeaders = routeJWTAuth.Headers
+	}
+	if len(claimHeaders) == 0 {
+		return headers
+	}
+	return mergePolicyHeaders(headers, &policyHeaders{requestSet: claimHeaders})
+}
+
+// addPolicyHeadersToLocation adds the headers of a headers policy to a location that passes requests to an upstream.
+// The headers of the proxy action of the route take precedence over the headers of the policy with the same name.
+func addPolicyHeadersToLocation(headers *policyHeaders, location *version2.Location) {
+	// the slices of the location might share the arrays of the resources
+	location.AddHeaders = slices.Clip(location.AddHeaders)
+
+	for _, h := range headers.responseAdd {
+		if !hasAddHeader(location.AddHeaders, h.Name) {
+			location.AddHeaders = append(location.AddHeaders, h)
+		}
+	}
+
+	// the request headers and the hidden headers only apply to the proxied requests
+	if location.ProxyPass == "" && location.GRPCPass == "" {
+		return
+	}
+
+	location.ProxySetHeaders = slices.Clip(location.ProxySetHeaders)
+	location.ProxyHideHeaders = slices.Clip(location.ProxyHideHeaders)
+
+	for _, h := range headers.requestSet {
+		if !hasHeader(location.ProxySetHeaders, h.Name) {
+			location.ProxySetHeaders = append(location.ProxySetHeaders, h)
+		}
+	}
+	for _, name := range headers.requestRemove {
+		if !hasHeader(location.ProxySetHeaders, name) {
+			location.ProxySetHeaders = append(location.ProxySetHeaders, version2.Header{Name: name, Value: ""})
+		}
+	}
+	for _, name := range headers.responseHide {
+		if !containsHeaderName(location.ProxyHideHeaders, name) && !containsHeaderName(location.ProxyPassHeaders, name) {
+			location.ProxyHideHeaders = append(location.ProxyHideHeaders, name)
+		}
+	}
+}
+
+// addResponseHeadersToReturnLocations adds the response headers of the return routes to their return locations,
+// as NGINX sends the responses of the return routes from the return locations.
+func addResponseHeadersToReturnLocations(locations []version2.Location, returnLocations []version2.ReturnLocation) {
+	for _, loc := range locations {
+		if len(loc.AddHeaders) == 0 || loc.InternalProxyPass == "" {
+			continue
+		}
+		for _, ep := range loc.ErrorPages {
+			for i := range returnLocations {
+				if returnLocations[i].Name != ep.Name {
+					continue
+				}
+				// the slice of the return location might share the array of the resource
+				returnLocations[i].Headers = slices.Clip(returnLocations[i].Headers)
+				for _, h := range loc.AddHeaders {
+					if !hasHeader(returnLocations[i].Headers, h.Name) {
+						returnLocations[i].Headers = append(returnLocations[i].Headers, h.Header)
+					}
+				}
+			}
+		}
+	}
+}
+
+func hasHeader(headers []version2.Header, name string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAddHeader(headers []version2.AddHeader, name string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHeaderName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// circuitBreakerUpstreams maps the names of the upstreams to the keys of the circuit breaker policies of the routes
+// that pass requests to them. An empty key stands for the routes without a circuit breaker policy.
+type circuitBreakerUpstreams map[string][]string
+
+func (c circuitBreakerUpstreams) addRoute(r conf_v1.Route, namer *upstreamNamer, key string) {
+	actions := []*conf_v1.Action{r.Action}
+	for _, s := range r.Splits {
+		actions = append(actions, s.Action)
+	}
+	if r.Canary != nil {
+		actions = append(actions, r.Canary.Action)
+	}
+	for _, m := range r.Matches {
+		actions = append(actions, m.Action)
+		for _, s := range m.Splits {
+			actions = append(actions, s.Action)
+		}
+	}
+
+	for _, action := range actions {
+		if action == nil || (action.Pass == "" && (action.Proxy == nil || action.Proxy.Upstream == "") && action.ObjectStorage == nil) {
+			continue
+		}
+		name := namer.GetNameForUpstreamFromAction(action)
+		if !slices.Contains(c[name], key) {
+			c[name] = append(c[name], key)
+		}
+	}
+}
+
+// getCircuitBreakerPolicyKey returns the key of the first circuit breaker policy of the policy references.
+func getCircuitBreakerPolicyKey(policyRefs []conf_v1.PolicyReference, ownerNamespace string, policies map[string]*conf_v1.Policy) string {
+	for _, p := range policyRefs {
+		polNamespace := p.Namespace
+		if polNamespace == "" {
+			polNamespace = ownerNamespace
+		}
+		key := fmt.Sprintf("%s/%s", polNamespace, p.Name)
+		if pol, exists := policies[key]; exists && pol.Spec.CircuitBreaker != nil {
+			return key
+		}
+	}
+	return ""
+}
+
+// getCircuitBreakerUpstreams returns the circuit breaker policies of the routes of the VirtualServer
+// and its VirtualServerRoutes for each upstream.
+func getCircuitBreakerUpstreams(vsEx *VirtualServerEx) circuitBreakerUpstreams {
+	cbUpstreams := make(circuitBreakerUpstreams)
+	vsrPoliciesFromVs := make(map[string][]conf_v1.PolicyReference)
+
+	namer := NewUpstreamNamerForVirtualServer(vsEx.VirtualServer)
+	for _, r := range vsEx.VirtualServer.Spec.Routes {
+		if r.Route != "" {
+			name := r.Route
+			if !strings.Contains(name, "/") {
+				name = fmt.Sprintf("%v/%v", vsEx.VirtualServer.Namespace, r.Route)
+			}
+			vsrPoliciesFromVs[name] = r.Policies
+			continue
+		}
+		key := getCircuitBreakerPolicyKey(r.Policies, vsEx.VirtualServer.Namespace, vsEx.Policies)
+		cbUpstreams.addRoute(r, namer, key)
+	}
+
+	for _, vsr := range vsEx.VirtualServerRoutes {
+		namer := NewUpstreamNamerForVirtualServerRoute(vsEx.VirtualServer, vsr)
+		for _, r := range vsr.Spec.Subroutes {
+			var key string
+			if len(r.Policies) == 0 {
+				// use the VirtualServer route policies if the route does not define any
+				policyRefs := vsrPoliciesFromVs[fmt.Sprintf("%v/%v", vsr.Namespace, vsr.Name)]
+				key = getCircuitBreakerPolicyKey(policyRefs, vsEx.VirtualServer.Namespace, vsEx.Policies)
+			} else {
+				key = getCircuitBreakerPolicyKey(r.Policies, vsr.Namespace, vsEx.Policies)
+			}
+			cbUpstreams.addRoute(r, namer, key)
+		}
+	}
+
+	return cbUpstreams
+}
+
+func getCircuitBreakerUpstreamName(upstreamName string, polKey string) string {
+	return fmt.Sprintf("%s_cb_%s", upstreamName, strings.ReplaceAll(polKey, "/", "_"))
+}
+
+type circuitBreakerUpstream struct {
+	upstream       version2.Upstream
+	circuitBreaker *conf_v1.CircuitBreaker
+}
+
+// generateCircuitBreakerUpstreams applies the circuit breaker policies of the routes to an upstream.
+// When all routes that pass requests to the upstream share the same policy, the policy is applied to the upstream.
+// Otherwise, the upstream is duplicated for each policy, so that the other routes are not affected.
+func generateCircuitBreakerUpstreams(ups version2.Upstream, polKeys []string, policies map[string]*conf_v1.Policy) []circuitBreakerUpstream {
+	result := []circuitBreakerUpstream{{upstream: ups}}
+
+	for _, key := range polKeys {
+		if key == "" {
+			continue
+		}
+		cb := policies[key].Spec.CircuitBreaker
+
+		if len(polKeys) == 1 {
+			applyCircuitBreaker(&result[0].upstream, cb)
+			result[0].circuitBreaker = cb
+			break
+		}
+
+		cbUps := ups
+		cbUps.Name = getCircuitBreakerUpstreamName(ups.Name, key)
+		applyCircuitBreaker(&cbUps, cb)
+		result = append(result, circuitBreakerUpstream{upstream: cbUps, circuitBreaker: cb})
+	}
+
+	return result
+}
+
+func applyCircuitBreaker(ups *version2.Upstream, cb *conf_v1.CircuitBreaker) {
+	if cb.MaxFails != nil {
+		ups.MaxFails = *cb.MaxFails
+	}
+	if cb.FailTimeout != "" {
+		ups.FailTimeout = generateTime(cb.FailTimeout)
+	}
+}
+
+type circuitBreakerRoute struct {
+	key           string
+	firstLocation int
+	lastLocation  int
+}
+
+// setCircuitBreakerUpstreams makes the locations of the routes with a circuit breaker policy
+// pass requests to the upstreams duplicated for the policy.
+func setCircuitBreakerUpstreams(locations []version2.Location, routes []circuitBreakerRoute, cbUpstreams circuitBreakerUpstreams) {
+	for _, r := range routes {
+		for i := r.firstLocation; i < r.lastLocation; i++ {
+			loc := &locations[i]
+			upstreamName := getLocationUpstreamName(loc)
+			if len(cbUpstreams[upstreamName]) < 2 || !slices.Contains(cbUpstreams[upstreamName], r.key) {
+				continue
+			}
+
+			cbUpstreamName := getCircuitBreakerUpstreamName(upstreamName, r.key)
+			loc.ProxyPass = strings.Replace(loc.ProxyPass, "://"+upstreamName, "://"+cbUpstreamName, 1)
+			loc.GRPCPass = strings.Replace(loc.GRPCPass, "://"+upstreamName, "://"+cbUpstreamName, 1)
+		}
+	}
+}
+
+// getLocationUpstreamName returns the name of the upstream the location passes requests to
+// or an empty string if the location doesn't pass requests to an upstream.
+func getLocationUpstreamName(loc *version2.Location) string {
+	pass := loc.ProxyPass
+	if pass == "" {
+		pass = loc.GRPCPass
+	}
+	_, upstreamName, found := strings.Cut(pass, "://")
+	if !found {
+		return ""
+	}
+	// the URI of the proxy_pass of a location starts with a variable, such as $request_uri
+	upstreamName, _, _ = strings.Cut(upstreamName, "$")
+	return upstreamName
+}
+
 // GenerateVirtualServerConfig generates a full configuration for a VirtualServer
 func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	vsEx *VirtualServerEx,
@@ -411,9 +936,10 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	tlsRedirectConfig := generateTLSRedirectConfig(vsEx.VirtualServer.Spec.TLS)
 
 	policyOpts := policyOptions{
-		tls:         sslConfig != nil,
-		secretRefs:  vsEx.SecretRefs,
-		apResources: apResources,
+		tls:              sslConfig != nil,
+		secretRefs:       vsEx.SecretRefs,
+		apResources:      apResources,
+		deniedReferences: vsEx.DeniedReferences,
 	}
 
 	ownerDetails := policyOwnerDetails{
@@ -445,6 +971,20 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		maps = append(maps, policiesCfg.RateLimit.PolicyGroupMaps...)
 	}
 
+	if policiesCfg.Headers != nil {
+		maps = append(maps, policiesCfg.Headers.maps...)
+	}
+
+	if policiesCfg.GeoAccess != nil {
+		maps = append(maps, policiesCfg.GeoAccess.maps...)
+	}
+
+	if policiesCfg.BandwidthLimit != nil {
+		maps = append(maps, policiesCfg.BandwidthLimit.maps...)
+	}
+
+	maps = append(maps, policiesCfg.JWTAuth.Maps...)
+
 	dosCfg := generateDosCfg(dosResources[""])
 
 	// enabledInternalRoutes controls if a virtual server is configured as an internal route.
@@ -467,6 +1007,9 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 	limitReqZones = append(limitReqZones, policiesCfg.RateLimit.Zones...)
 	authJWTClaimSets = append(authJWTClaimSets, policiesCfg.RateLimit.AuthJWTClaimSets...)
+	authJWTClaimSets = append(authJWTClaimSets, policiesCfg.JWTAuth.AuthJWTClaimSets...)
+
+	cbUpstreams := getCircuitBreakerUpstreams(vsEx)
 
 	// generate upstreams for VirtualServer
 	for _, u := range vsEx.VirtualServer.Spec.Upstreams {
@@ -476,25 +1019,26 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		}
 
 		upstreamName := virtualServerUpstreamNamer.GetNameForUpstream(u.Name)
-		upstreamNamespace := vsEx.VirtualServer.Namespace
+		upstreamNamespace := GetUpstreamNamespace(u, vsEx.VirtualServer.Namespace)
 		endpoints := vsc.generateEndpointsForUpstream(vsEx.VirtualServer, upstreamNamespace, u, vsEx)
 		backupEndpoints := vsc.generateBackupEndpointsForUpstream(vsEx.VirtualServer, upstreamNamespace, u, vsEx)
 
 		// isExternalNameSvc is always false for OSS
 		_, isExternalNameSvc := vsEx.ExternalNameSvcs[GenerateExternalNameSvcKey(upstreamNamespace, u.Service)]
 		ups := vsc.generateUpstream(vsEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints)
-		upstreams = append(upstreams, ups)
 
 		u.TLS.Enable = isTLSEnabled(u, vsc.spiffeCerts, vsEx.VirtualServer.Spec.InternalRoute)
-		crUpstreams[upstreamName] = u
-
-		if hc := generateHealthCheck(u, upstreamName, vsc.cfgParams); hc != nil {
-			healthChecks = append(healthChecks, *hc)
-			if u.HealthCheck.StatusMatch != "" {
-				statusMatches = append(
-					statusMatches,
-					generateUpstreamStatusMatch(upstreamName, u.HealthCheck.StatusMatch),
-				)
+
+		for _, cbUps := range generateCircuitBreakerUpstreams(ups, cbUpstreams[upstreamName], vsEx.Policies) {
+			upstreams = append(upstreams, cbUps.upstream)
+			crUpstreams[cbUps.upstream.Name] = u
+
+			hc, statusMatch := vsc.generateUpstreamHealthCheck(vsEx.VirtualServer, u, cbUps.upstream.Name, cbUps.circuitBreaker)
+			if hc != nil {
+				healthChecks = append(healthChecks, *hc)
+			}
+			if statusMatch != nil {
+				statusMatches = append(statusMatches, *statusMatch)
 			}
 		}
 	}
@@ -507,24 +1051,25 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			}
 
 			upstreamName := upstreamNamer.GetNameForUpstream(u.Name)
-			upstreamNamespace := vsr.Namespace
+			upstreamNamespace := GetUpstreamNamespace(u, vsr.Namespace)
 			endpoints := vsc.generateEndpointsForUpstream(vsr, upstreamNamespace, u, vsEx)
-			backup := vsc.generateBackupEndpointsForUpstream(vsEx.VirtualServer, upstreamNamespace, u, vsEx)
+			backup := vsc.generateBackupEndpointsForUpstream(vsr, upstreamNamespace, u, vsEx)
 
 			// isExternalNameSvc is always false for OSS
 			_, isExternalNameSvc := vsEx.ExternalNameSvcs[GenerateExternalNameSvcKey(upstreamNamespace, u.Service)]
 			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints, backup)
-			upstreams = append(upstreams, ups)
 			u.TLS.Enable = isTLSEnabled(u, vsc.spiffeCerts, vsEx.VirtualServer.Spec.InternalRoute)
-			crUpstreams[upstreamName] = u
 
-			if hc := generateHealthCheck(u, upstreamName, vsc.cfgParams); hc != nil {
-				healthChecks = append(healthChecks, *hc)
-				if u.HealthCheck.StatusMatch != "" {
-					statusMatches = append(
-						statusMatches,
-						generateUpstreamStatusMatch(upstreamName, u.HealthCheck.StatusMatch),
-					)
+			for _, cbUps := range generateCircuitBreakerUpstreams(ups, cbUpstreams[upstreamName], vsEx.Policies) {
+				upstreams = append(upstreams, cbUps.upstream)
+				crUpstreams[cbUps.upstream.Name] = u
+
+				hc, statusMatch := vsc.generateUpstreamHealthCheck(vsr, u, cbUps.upstream.Name, cbUps.circuitBreaker)
+				if hc != nil {
+					healthChecks = append(healthChecks, *hc)
+				}
+				if statusMatch != nil {
+					statusMatches = append(statusMatches, *statusMatch)
 				}
 			}
 		}
@@ -542,6 +1087,8 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	vsrErrorPagesRouteIndex := make(map[string]int)
 	vsrLocationSnippetsFromVs := make(map[string]string)
 	vsrPoliciesFromVs := make(map[string][]conf_v1.PolicyReference)
+	vsrCompressionFromVs := make(map[string]*conf_v1.Compression)
+	var circuitBreakerRoutes []circuitBreakerRoute
 	isVSR := false
 	matchesRoutes := 0
 
@@ -574,6 +1121,11 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				vsrErrorPagesRouteIndex[name] = errorPages.index
 			}
 
+			// store route compression for the referenced VirtualServerRoute in case they don't define their own
+			if r.Compression != nil {
+				vsrCompressionFromVs[name] = r.Compression
+			}
+
 			// store route policies for the referenced VirtualServerRoute in case they don't define their own
 			if len(r.Policies) > 0 {
 				vsrPoliciesFromVs[name] = r.Policies
@@ -591,9 +1143,24 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			vsName:         vsEx.VirtualServer.Name,
 		}
 		routePoliciesCfg := vsc.generatePolicies(ownerDetails, r.Policies, vsEx.Policies, routeContext, policyOpts)
-		if policiesCfg.OIDC {
+		if routePoliciesCfg.OIDC == nil {
 			routePoliciesCfg.OIDC = policiesCfg.OIDC
 		}
+		if routePoliciesCfg.OAuth2Introspection == nil {
+			routePoliciesCfg.OAuth2Introspection = policiesCfg.OAuth2Introspection
+		}
+		if routePoliciesCfg.SignatureVerification == nil {
+			routePoliciesCfg.SignatureVerification = policiesCfg.SignatureVerification
+		}
+		if routePoliciesCfg.Challenge == nil {
+			routePoliciesCfg.Challenge = policiesCfg.Challenge
+		}
+		if routePoliciesCfg.BandwidthLimit == nil {
+			routePoliciesCfg.BandwidthLimit = policiesCfg.BandwidthLimit
+		}
+		if routePoliciesCfg.GeoAccess == nil && len(routePoliciesCfg.Allow) == 0 && len(routePoliciesCfg.Deny) == 0 {
+			routePoliciesCfg.GeoAccess = policiesCfg.GeoAccess
+		}
 		if routePoliciesCfg.JWTAuth.JWKSEnabled {
 			policiesCfg.JWTAuth.JWKSEnabled = routePoliciesCfg.JWTAuth.JWKSEnabled
 
@@ -625,11 +1192,30 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			maps = append(maps, routePoliciesCfg.RateLimit.PolicyGroupMaps...)
 		}
 
+		if routePoliciesCfg.Headers != nil {
+			maps = append(maps, routePoliciesCfg.Headers.maps...)
+		}
+
+		if routePoliciesCfg.GeoAccess != nil {
+			maps = append(maps, routePoliciesCfg.GeoAccess.maps...)
+		}
+
+		if routePoliciesCfg.BandwidthLimit != nil {
+			maps = append(maps, routePoliciesCfg.BandwidthLimit.maps...)
+		}
+		maps = append(maps, routePoliciesCfg.JWTAuth.Maps...)
+		routePoliciesCfg.Headers = mergePolicyHeaders(policiesCfg.Headers, routePoliciesCfg.Headers)
+		routePoliciesCfg.Headers = mergeJWTClaimHeaders(policiesCfg.JWTAuth, routePoliciesCfg.JWTAuth, routePoliciesCfg.Headers)
+		routePoliciesCfg.RequestLimits = mergeRequestLimits(policiesCfg.RequestLimits, routePoliciesCfg.RequestLimits)
+
 		limitReqZones = append(limitReqZones, routePoliciesCfg.RateLimit.Zones...)
 
 		authJWTClaimSets = append(authJWTClaimSets, routePoliciesCfg.RateLimit.AuthJWTClaimSets...)
+		authJWTClaimSets = append(authJWTClaimSets, routePoliciesCfg.JWTAuth.AuthJWTClaimSets...)
 
 		dosRouteCfg := generateDosCfg(dosResources[r.Path])
+		compressionRouteCfg := generateCompression(r.Compression, vsEx.VirtualServer.Spec.Compression, vsc.staticCompressionModules)
+		firstLocation := len(locations)
 
 		if len(r.Matches) > 0 {
 			cfg := generateMatchesConfig(
@@ -651,6 +1237,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			)
 			addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 			addDosConfigToLocations(dosRouteCfg, cfg.Locations)
+			addCompressionToLocations(compressionRouteCfg, cfg.Locations)
 
 			maps = append(maps, cfg.Maps...)
 			locations = append(locations, cfg.Locations...)
@@ -661,11 +1248,12 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			keyVals = append(keyVals, cfg.KeyVals...)
 			twoWaySplitClients = append(twoWaySplitClients, cfg.TwoWaySplitClients...)
 			matchesRoutes++
-		} else if len(r.Splits) > 0 {
+		} else if len(r.Splits) > 0 || r.Canary != nil {
 			cfg := generateDefaultSplitsConfig(r, virtualServerUpstreamNamer, crUpstreams, VariableNamer, len(splitClients),
 				vsc.cfgParams, errorPages, r.Path, vsLocSnippets, vsc.enableSnippets, len(returnLocations), isVSR, "", "", vsc.warnings, vsc.DynamicWeightChangesReload)
 			addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 			addDosConfigToLocations(dosRouteCfg, cfg.Locations)
+			addCompressionToLocations(compressionRouteCfg, cfg.Locations)
 			splitClients = append(splitClients, cfg.SplitClients...)
 			locations = append(locations, cfg.Locations...)
 			internalRedirectLocations = append(internalRedirectLocations, cfg.InternalRedirectLocation)
@@ -678,18 +1266,35 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			upstreamName := virtualServerUpstreamNamer.GetNameForUpstreamFromAction(r.Action)
 			upstream := crUpstreams[upstreamName]
 
-			proxySSLName := generateProxySSLName(upstream.Service, vsEx.VirtualServer.Namespace)
+			proxySSLName := generateProxySSLName(upstream.Service, GetUpstreamNamespace(upstream, vsEx.VirtualServer.Namespace))
 
 			loc, returnLoc := generateLocation(r.Path, upstreamName, upstream, r.Action, vsc.cfgParams, errorPages, false,
 				proxySSLName, r.Path, vsLocSnippets, vsc.enableSnippets, len(returnLocations), isVSR, "", "", vsc.warnings)
+			if r.Action.ObjectStorage != nil {
+				vsc.addObjectStorageToLocation(vsEx.VirtualServer, &loc, r.Path, r.Action.ObjectStorage, vsEx.VirtualServer.Namespace,
+					proxySSLName, vsEx.SecretRefs)
+			}
 			addPoliciesCfgToLocation(routePoliciesCfg, &loc)
 			loc.Dos = dosRouteCfg
+			loc.Compression = compressionRouteCfg
 
 			locations = append(locations, loc)
 			if returnLoc != nil {
 				returnLocations = append(returnLocations, *returnLoc)
 			}
 		}
+
+		if routePoliciesCfg.RequestLimits != nil {
+			vsc.addRequestLimitsToLocations(ownerDetails.owner, routePoliciesCfg.RequestLimits, locations[firstLocation:], crUpstreams)
+		}
+
+		if routePoliciesCfg.CircuitBreaker != nil {
+			circuitBreakerRoutes = append(circuitBreakerRoutes, circuitBreakerRoute{
+				key:           routePoliciesCfg.CircuitBreaker.key,
+				firstLocation: firstLocation,
+				lastLocation:  len(locations),
+			})
+		}
 	}
 
 	// generate config for subroutes of each VirtualServerRoute
@@ -744,9 +1349,24 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				context = subRouteContext
 			}
 			routePoliciesCfg := vsc.generatePolicies(ownerDetails, policyRefs, vsEx.Policies, context, policyOpts)
-			if policiesCfg.OIDC {
+			if routePoliciesCfg.OIDC == nil {
 				routePoliciesCfg.OIDC = policiesCfg.OIDC
 			}
+			if routePoliciesCfg.OAuth2Introspection == nil {
+				routePoliciesCfg.OAuth2Introspection = policiesCfg.OAuth2Introspection
+			}
+			if routePoliciesCfg.SignatureVerification == nil {
+				routePoliciesCfg.SignatureVerification = policiesCfg.SignatureVerification
+			}
+			if routePoliciesCfg.Challenge == nil {
+				routePoliciesCfg.Challenge = policiesCfg.Challenge
+			}
+			if routePoliciesCfg.BandwidthLimit == nil {
+				routePoliciesCfg.BandwidthLimit = policiesCfg.BandwidthLimit
+			}
+			if routePoliciesCfg.GeoAccess == nil && len(routePoliciesCfg.Allow) == 0 && len(routePoliciesCfg.Deny) == 0 {
+				routePoliciesCfg.GeoAccess = policiesCfg.GeoAccess
+			}
 			if routePoliciesCfg.JWTAuth.JWKSEnabled {
 				policiesCfg.JWTAuth.JWKSEnabled = routePoliciesCfg.JWTAuth.JWKSEnabled
 
@@ -778,12 +1398,37 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				maps = append(maps, routePoliciesCfg.RateLimit.PolicyGroupMaps...)
 			}
 
+			if routePoliciesCfg.Headers != nil {
+				maps = append(maps, routePoliciesCfg.Headers.maps...)
+			}
+
+			if routePoliciesCfg.GeoAccess != nil {
+				maps = append(maps, routePoliciesCfg.GeoAccess.maps...)
+			}
+
+			if routePoliciesCfg.BandwidthLimit != nil {
+				maps = append(maps, routePoliciesCfg.BandwidthLimit.maps...)
+			}
+			maps = append(maps, routePoliciesCfg.JWTAuth.Maps...)
+			routePoliciesCfg.Headers = mergePolicyHeaders(policiesCfg.Headers, routePoliciesCfg.Headers)
+			routePoliciesCfg.Headers = mergeJWTClaimHeaders(policiesCfg.JWTAuth, routePoliciesCfg.JWTAuth, routePoliciesCfg.Headers)
+			routePoliciesCfg.RequestLimits = mergeRequestLimits(policiesCfg.RequestLimits, routePoliciesCfg.RequestLimits)
+
 			limitReqZones = append(limitReqZones, routePoliciesCfg.RateLimit.Zones...)
 
 			authJWTClaimSets = append(authJWTClaimSets, routePoliciesCfg.RateLimit.AuthJWTClaimSets...)
+			authJWTClaimSets = append(authJWTClaimSets, routePoliciesCfg.JWTAuth.AuthJWTClaimSets...)
 
 			dosRouteCfg := generateDosCfg(dosResources[r.Path])
 
+			routeCompression := r.Compression
+			// use the VirtualServer route compression if the route does not define any
+			if routeCompression == nil {
+				routeCompression = vsrCompressionFromVs[vsrNamespaceName]
+			}
+			compressionRouteCfg := generateCompression(routeCompression, vsEx.VirtualServer.Spec.Compression, vsc.staticCompressionModules)
+			firstLocation := len(locations)
+
 			if len(r.Matches) > 0 {
 				cfg := generateMatchesConfig(
 					r,
@@ -805,6 +1450,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				)
 				addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 				addDosConfigToLocations(dosRouteCfg, cfg.Locations)
+				addCompressionToLocations(compressionRouteCfg, cfg.Locations)
 
 				maps = append(maps, cfg.Maps...)
 				locations = append(locations, cfg.Locations...)
@@ -815,11 +1461,12 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				keyVals = append(keyVals, cfg.KeyVals...)
 				twoWaySplitClients = append(twoWaySplitClients, cfg.TwoWaySplitClients...)
 				matchesRoutes++
-			} else if len(r.Splits) > 0 {
+			} else if len(r.Splits) > 0 || r.Canary != nil {
 				cfg := generateDefaultSplitsConfig(r, upstreamNamer, crUpstreams, VariableNamer, len(splitClients), vsc.cfgParams,
 					errorPages, r.Path, locSnippets, vsc.enableSnippets, len(returnLocations), isVSR, vsr.Name, vsr.Namespace, vsc.warnings, vsc.DynamicWeightChangesReload)
 				addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 				addDosConfigToLocations(dosRouteCfg, cfg.Locations)
+				addCompressionToLocations(compressionRouteCfg, cfg.Locations)
 
 				splitClients = append(splitClients, cfg.SplitClients...)
 				locations = append(locations, cfg.Locations...)
@@ -832,25 +1479,49 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			} else {
 				upstreamName := upstreamNamer.GetNameForUpstreamFromAction(r.Action)
 				upstream := crUpstreams[upstreamName]
-				proxySSLName := generateProxySSLName(upstream.Service, vsr.Namespace)
+				proxySSLName := generateProxySSLName(upstream.Service, GetUpstreamNamespace(upstream, vsr.Namespace))
 
 				loc, returnLoc := generateLocation(r.Path, upstreamName, upstream, r.Action, vsc.cfgParams, errorPages, false,
 					proxySSLName, r.Path, locSnippets, vsc.enableSnippets, len(returnLocations), isVSR, vsr.Name, vsr.Namespace, vsc.warnings)
+				if r.Action.ObjectStorage != nil {
+					vsc.addObjectStorageToLocation(vsr, &loc, r.Path, r.Action.ObjectStorage, vsr.Namespace, proxySSLName, vsEx.SecretRefs)
+				}
 				addPoliciesCfgToLocation(routePoliciesCfg, &loc)
 				loc.Dos = dosRouteCfg
+				loc.Compression = compressionRouteCfg
 
 				locations = append(locations, loc)
 				if returnLoc != nil {
 					returnLocations = append(returnLocations, *returnLoc)
 				}
 			}
+
+			if routePoliciesCfg.RequestLimits != nil {
+				vsc.addRequestLimitsToLocations(ownerDetails.owner, routePoliciesCfg.RequestLimits, locations[firstLocation:], crUpstreams)
+			}
+
+			if routePoliciesCfg.CircuitBreaker != nil {
+				circuitBreakerRoutes = append(circuitBreakerRoutes, circuitBreakerRoute{
+					key:           routePoliciesCfg.CircuitBreaker.key,
+					firstLocation: firstLocation,
+					lastLocation:  len(locations),
+				})
+			}
 		}
 	}
 
+	setCircuitBreakerUpstreams(locations, circuitBreakerRoutes, cbUpstreams)
+	locations = append(locations, generateSignatureVerificationLocations(locations)...)
+	oidcProviders := vsc.generateOIDCProviders(vsEx.VirtualServer, locations)
+	oauth2Introspections := generateOAuth2Introspections(locations)
+	challenges := generateChallenges(locations)
+
 	for mapName, apiKeyClients := range policiesCfg.APIKey.ClientMap {
-		maps = append(maps, *generateAPIKeyClientMap(mapName, apiKeyClients))
+		maps = append(maps, generateAPIKeyClientMaps(mapName, apiKeyClients)...)
 	}
 
+	addResponseHeadersToReturnLocations(locations, returnLocations)
+
 	httpSnippets := generateSnippets(vsc.enableSnippets, vsEx.VirtualServer.Spec.HTTPSnippets, []string{})
 	serverSnippets := generateSnippets(
 		vsc.enableSnippets,
@@ -873,6 +1544,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		Server: version2.Server{
 			ServerName:                vsEx.VirtualServer.Spec.Host,
 			Gunzip:                    vsEx.VirtualServer.Spec.Gunzip,
+			Compression:               generateCompression(vsEx.VirtualServer.Spec.Compression, nil, vsc.staticCompressionModules),
 			StatusZone:                vsEx.VirtualServer.Spec.Host,
 			HTTPPort:                  vsEx.HTTPPort,
 			HTTPSPort:                 vsEx.HTTPSPort,
@@ -907,13 +1579,17 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			EgressMTLS:                policiesCfg.EgressMTLS,
 			APIKey:                    policiesCfg.APIKey.Key,
 			APIKeyEnabled:             policiesCfg.APIKey.Enabled,
-			OIDC:                      vsc.oidcPolCfg.oidc,
+			OAuth2Introspections:      oauth2Introspections,
+			Challenges:                challenges,
+			OIDCProviders:             oidcProviders,
 			WAF:                       policiesCfg.WAF,
 			Dos:                       dosCfg,
 			PoliciesErrorReturn:       policiesCfg.ErrorReturn,
 			VSNamespace:               vsEx.VirtualServer.Namespace,
 			VSName:                    vsEx.VirtualServer.Name,
 			DisableIPV6:               vsc.isIPV6Disabled,
+			Maintenance:               generateMaintenance(vsEx.VirtualServer),
+			RequestLimits:             generateServerRequestLimits(policiesCfg.RequestLimits),
 		},
 		SpiffeCerts:             enabledInternalRoutes,
 		SpiffeClientCerts:       vsc.spiffeCerts && !enabledInternalRoutes,
@@ -939,9 +1615,12 @@ type rateLimit struct {
 
 // jwtAuth hold the configuration for the JWTAuth & JWKSAuth Policies
 type jwtAuth struct {
-	Auth        *version2.JWTAuth
-	List        map[string]*version2.JWTAuth
-	JWKSEnabled bool
+	Auth             *version2.JWTAuth
+	List             map[string]*version2.JWTAuth
+	JWKSEnabled      bool
+	AuthJWTClaimSets []version2.AuthJWTClaimSet
+	Maps             []version2.Map
+	Headers          []version2.Header
 }
 
 // apiKeyAuth hold the configuration for the APIKey Policy
@@ -953,18 +1632,77 @@ type apiKeyAuth struct {
 }
 
 type policiesCfg struct {
-	Allow           []string
-	Deny            []string
-	RateLimit       rateLimit
-	JWTAuth         jwtAuth
-	BasicAuth       *version2.BasicAuth
-	IngressMTLS     *version2.IngressMTLS
-	EgressMTLS      *version2.EgressMTLS
-	OIDC            bool
-	APIKey          apiKeyAuth
-	WAF             *version2.WAF
-	ErrorReturn     *version2.Return
-	BundleValidator bundleValidator
+	Allow                 []string
+	Deny                  []string
+	GeoAccess             *geoAccess
+	RateLimit             rateLimit
+	JWTAuth               jwtAuth
+	BasicAuth             *version2.BasicAuth
+	IngressMTLS           *version2.IngressMTLS
+	EgressMTLS            *version2.EgressMTLS
+	OIDC                  *version2.OIDC
+	APIKey                apiKeyAuth
+	OAuth2Introspection   *version2.OAuth2Introspection
+	SignatureVerification *version2.SignatureVerification
+	Challenge             *version2.Challenge
+	BandwidthLimit        *bandwidthLimit
+	WAF                   *version2.WAF
+	Retry                 *retry
+	CircuitBreaker        *circuitBreaker
+	Headers               *policyHeaders
+	RequestLimits         *requestLimits
+	ErrorReturn           *version2.Return
+	BundleValidator       bundleValidator
+}
+
+// retry holds the configuration of a retry policy for the locations of a route.
+type retry struct {
+	nextUpstream        string
+	nextUpstreamTimeout string
+	nextUpstreamTries   int
+	perTryTimeout       string
+}
+
+// requestLimits holds the configuration of a requestLimits policy.
+// The header limits apply to the server only.
+type requestLimits struct {
+	key                string
+	maxBodySize        string
+	headerBufferSize   string
+	largeHeaderBuffers string
+	bodyTimeout        string
+	headerTimeout      string
+	allowedMethods     []string
+	maxURILength       int
+}
+
+// policyHeaders holds the configuration of a headers policy for the locations of a route.
+type policyHeaders struct {
+	requestSet    []version2.Header
+	requestRemove []string
+	responseAdd   []version2.AddHeader
+	responseHide  []string
+	maps          []version2.Map
+}
+
+// geoAccess holds the map of the geo access control policy of a route. The map variable is 0 for the denied requests.
+type geoAccess struct {
+	variable string
+	maps     []version2.Map
+}
+
+// bandwidthLimit holds the configuration of a bandwidthLimit policy for the locations of a route.
+// The rate is the variable of the map of the policy if the policy sets the rates per key.
+type bandwidthLimit struct {
+	rate      string
+	rateAfter string
+	maps      []version2.Map
+}
+
+// circuitBreaker holds the key of the circuit breaker policy of a route.
+// The policy is applied to the upstreams of the route by generateCircuitBreakerUpstreams.
+type circuitBreaker struct {
+	key string
 }
 
 type bundleValidator interface {
@@ -979,6 +1717,9 @@ type internalBundleValidator struct {
 type apiKeyClient struct {
 	ClientID  string
 	HashedKey string
+	// Expires is the expiry time of the key in seconds since the epoch, 0 if the key doesn't expire.
+	Expires int64
+	Tier    string
 }
 
 func (i internalBundleValidator) validate(bundle string) (string, error) {
@@ -1008,9 +1749,10 @@ type policyOwnerDetails struct {
 }
 
 type policyOptions struct {
-	tls         bool
-	secretRefs  map[string]*secrets.SecretReference
-	apResources *appProtectResourcesForVS
+	tls              bool
+	secretRefs       map[string]*secrets.SecretReference
+	apResources      *appProtectResourcesForVS
+	deniedReferences map[string]error
 }
 
 type validationResults struct {
@@ -1026,8 +1768,35 @@ func (v *validationResults) addWarningf(msgFmt string, args ...interface{}) {
 	v.warnings = append(v.warnings, fmt.Sprintf(msgFmt, args...))
 }
 
-func (p *policiesCfg) addAccessControlConfig(accessControl *conf_v1.AccessControl) *validationResults {
+func (p *policiesCfg) addAccessControlConfig(
+	accessControl *conf_v1.AccessControl,
+	polKey string,
+	polNamespace string,
+	polName string,
+	ownerDetails policyOwnerDetails,
+	cfgParams *ConfigParams,
+) *validationResults {
 	res := newValidationResults()
+	if accessControl.Geo != nil {
+		if p.GeoAccess != nil {
+			res.addWarningf(
+				"Multiple geo AccessControl policies in the same context is not valid. AccessControl policy %s will be ignored",
+				polKey,
+			)
+			return res
+		}
+		if missing := GetMissingGeoIP2Databases(accessControl.Geo, cfgParams); len(missing) > 0 {
+			res.addWarningf("AccessControl policy %s uses GeoIP2 databases that are not configured: set the ConfigMap key(s) %s", polKey, strings.Join(missing, ", "))
+			res.isError = true
+			return res
+		}
+		variable := rfc1123ToSnake(fmt.Sprintf("$geo_access_%v_%v_%v_%v", polNamespace, polName, ownerDetails.vsNamespace, ownerDetails.vsName))
+		p.GeoAccess = &geoAccess{
+			variable: variable,
+			maps:     []version2.Map{generateGeoAccessMap(variable, accessControl.Geo)},
+		}
+		return res
+	}
 	p.Allow = append(p.Allow, accessControl.Allow...)
 	p.Deny = append(p.Deny, accessControl.Deny...)
 	if len(p.Allow) > 0 && len(p.Deny) > 0 {
@@ -1038,6 +1807,88 @@ func (p *policiesCfg) addAccessControlConfig(accessControl *conf_v1.AccessContro
 	return res
 }
 
+// GetMissingGeoIP2Databases returns the ConfigMap keys of the GeoIP2 databases that the geo AccessControl policy requires
+// but are not set. Without a database, the country code or the autonomous system number of every client is empty.
+func GetMissingGeoIP2Databases(geo *conf_v1.GeoAccessControl, cfgParams *ConfigParams) []string {
+	var missing []string
+	if (len(geo.AllowCountries) > 0 || len(geo.DenyCountries) > 0) && cfgParams.MainGeoIP2CountryDatabase == "" {
+		missing = append(missing, "geoip2-country-database")
+	}
+	if (len(geo.AllowASNs) > 0 || len(geo.DenyASNs) > 0) && cfgParams.MainGeoIP2ASNDatabase == "" {
+		missing = append(missing, "geoip2-asn-database")
+	}
+	return missing
+}
+
+// generateGeoAccessMap returns the map of the country code and the autonomous system number of the client
+// to 1 for the allowed requests and to 0 for the denied requests.
+func generateGeoAccessMap(variable string, geo *conf_v1.GeoAccessControl) version2.Map {
+	countries, asns := geo.AllowCountries, geo.AllowASNs
+	result, defaultResult := "1", "0"
+	if len(countries) == 0 && len(asns) == 0 {
+		countries, asns = geo.DenyCountries, geo.DenyASNs
+		result, defaultResult = "0", "1"
+	}
+
+	params := []version2.Parameter{{Value: "default", Result: defaultResult}}
+	for _, c := range countries {
+		params = append(params, version2.Parameter{Value: fmt.Sprintf(`"~^%s:"`, c), Result: result})
+	}
+	for _, asn := range asns {
+		params = append(params, version2.Parameter{Value: fmt.Sprintf(`"~:%d$"`, asn), Result: result})
+	}
+
+	return version2.Map{
+		Source:     `"$geoip2_country_code:$geoip2_asn"`,
+		Variable:   variable,
+		Parameters: params,
+	}
+}
+
+func (p *policiesCfg) addBandwidthLimitConfig(
+	bl *conf_v1.BandwidthLimit,
+	polKey string,
+	polNamespace string,
+	polName string,
+	ownerDetails policyOwnerDetails,
+) *validationResults {
+	res := newValidationResults()
+	if p.BandwidthLimit != nil {
+		res.addWarningf(
+			"Multiple bandwidthLimit policies in the same context is not valid. BandwidthLimit policy %s will be ignored",
+			polKey,
+		)
+		return res
+	}
+
+	p.BandwidthLimit = &bandwidthLimit{
+		rate:      bl.Rate,
+		rateAfter: bl.RateAfter,
+	}
+	if len(bl.Rates) > 0 {
+		variable := rfc1123ToSnake(fmt.Sprintf("$bandwidth_limit_%v_%v_%v_%v", polNamespace, polName, ownerDetails.vsNamespace, ownerDetails.vsName))
+		p.BandwidthLimit.rate = variable
+		p.BandwidthLimit.maps = []version2.Map{generateBandwidthLimitMap(variable, bl)}
+	}
+	return res
+}
+
+// generateBandwidthLimitMap returns the map of the key of the policy to the rate of the client.
+// The values are prefixed with a backslash, so that they are matched exactly even if they start with ~
+// or are the names of the parameters of the map, like default.
+func generateBandwidthLimitMap(variable string, bl *conf_v1.BandwidthLimit) version2.Map {
+	params := []version2.Parameter{{Value: "default", Result: bl.Rate}}
+	for _, r := range bl.Rates {
+		params = append(params, version2.Parameter{Value: fmt.Sprintf(`"\\%s"`, r.Value), Result: r.Rate})
+	}
+
+	return version2.Map{
+		Source:     fmt.Sprintf("%q", bl.Key),
+		Variable:   variable,
+		Parameters: params,
+	}
+}
+
 func (p *policiesCfg) addRateLimitConfig(
 	rateLimit *conf_v1.RateLimit,
 	polKey string,
@@ -1049,10 +1900,14 @@ func (p *policiesCfg) addRateLimitConfig(
 	res := newValidationResults()
 
 	rlZoneName := fmt.Sprintf("pol_rl_%v_%v_%v_%v", polNamespace, polName, ownerDetails.vsNamespace, ownerDetails.vsName)
-	if rateLimit.Condition != nil && rateLimit.Condition.JWT.Claim != "" && rateLimit.Condition.JWT.Match != "" {
+	if rateLimit.Condition != nil && rateLimit.Condition.JWT != nil && rateLimit.Condition.JWT.Claim != "" && rateLimit.Condition.JWT.Match != "" {
+		lrz := generateGroupedLimitReqZone(rlZoneName, rateLimit, podReplicas, ownerDetails)
+		p.RateLimit.PolicyGroupMaps = append(p.RateLimit.PolicyGroupMaps, *generateLRZPolicyGroupMap(lrz))
+		p.RateLimit.AuthJWTClaimSets = append(p.RateLimit.AuthJWTClaimSets, generateAuthJwtClaimSet(*rateLimit.Condition.JWT, ownerDetails))
+		p.RateLimit.Zones = append(p.RateLimit.Zones, lrz)
+	} else if rateLimit.Condition != nil && rateLimit.Condition.APIKey != nil && rateLimit.Condition.APIKey.Tier != "" {
 		lrz := generateGroupedLimitReqZone(rlZoneName, rateLimit, podReplicas, ownerDetails)
 		p.RateLimit.PolicyGroupMaps = append(p.RateLimit.PolicyGroupMaps, *generateLRZPolicyGroupMap(lrz))
-		p.RateLimit.AuthJWTClaimSets = append(p.RateLimit.AuthJWTClaimSets, generateAuthJwtClaimSet(*rateLimit.Condition.JWT, ownerDetails))
 		p.RateLimit.Zones = append(p.RateLimit.Zones, lrz)
 	} else {
 		p.RateLimit.Zones = append(p.RateLimit.Zones, generateLimitReqZone(rlZoneName, rateLimit, podReplicas))
@@ -1088,7 +1943,7 @@ func (p *policiesCfg) addBasicAuthConfig(
 		return res
 	}
 
-	basicSecretKey := fmt.Sprintf("%v/%v", polNamespace, basicAuth.Secret)
+	basicSecretKey := GeneratePolicySecretKey(polNamespace, basicAuth.Secret)
 	secretRef := secretRefs[basicSecretKey]
 	var secretType api_v1.SecretType
 	if secretRef.Secret != nil {
@@ -1115,6 +1970,8 @@ func (p *policiesCfg) addJWTAuthConfig(
 	jwtAuth *conf_v1.JWTAuth,
 	polKey string,
 	polNamespace string,
+	polName string,
+	ownerDetails policyOwnerDetails,
 	secretRefs map[string]*secrets.SecretReference,
 ) *validationResults {
 	res := newValidationResults()
@@ -1123,7 +1980,7 @@ func (p *policiesCfg) addJWTAuthConfig(
 		return res
 	}
 	if jwtAuth.Secret != "" {
-		jwtSecretKey := fmt.Sprintf("%v/%v", polNamespace, jwtAuth.Secret)
+		jwtSecretKey := GeneratePolicySecretKey(polNamespace, jwtAuth.Secret)
 		secretRef := secretRefs[jwtSecretKey]
 		var secretType api_v1.SecretType
 		if secretRef.Secret != nil {
@@ -1144,6 +2001,7 @@ func (p *policiesCfg) addJWTAuthConfig(
 			Realm:  jwtAuth.Realm,
 			Token:  jwtAuth.Token,
 		}
+		p.addJWTAuthorizationConfig(jwtAuth.Authorization, polNamespace, polName, ownerDetails)
 		return res
 	} else if jwtAuth.JwksURI != "" {
 		uri, _ := url.Parse(jwtAuth.JwksURI)
@@ -1163,11 +2021,74 @@ func (p *policiesCfg) addJWTAuthConfig(
 			KeyCache: jwtAuth.KeyCache,
 		}
 		p.JWTAuth.JWKSEnabled = true
+		p.addJWTAuthorizationConfig(jwtAuth.Authorization, polNamespace, polName, ownerDetails)
 		return res
 	}
 	return res
 }
 
+// addJWTAuthorizationConfig adds the claim-based authorization rules of a JWT policy to the JWT configuration.
+// Every required claim gets a map that evaluates to 1 when the claim contains one of the values. NGINX Plus
+// stores the values of an array claim separated by commas.
+func (p *policiesCfg) addJWTAuthorizationConfig(
+	authz *conf_v1.JWTAuthorization,
+	polNamespace string,
+	polName string,
+	ownerDetails policyOwnerDetails,
+) {
+	if authz == nil {
+		return
+	}
+
+	var requirements []conf_v1.JWTClaimRequirement
+	if len(authz.Audiences) > 0 {
+		requirements = append(requirements, conf_v1.JWTClaimRequirement{Claim: "aud", Values: authz.Audiences})
+	}
+	if len(authz.Issuers) > 0 {
+		requirements = append(requirements, conf_v1.JWTClaimRequirement{Claim: "iss", Values: authz.Issuers})
+	}
+	requirements = append(requirements, authz.Claims...)
+
+	mapPrefix := rfc1123ToSnake(fmt.Sprintf("pol_jwt_%v_%v_%v_%v", polNamespace, polName, ownerDetails.vsNamespace, ownerDetails.vsName))
+
+	for i, r := range requirements {
+		claimSet := generateAuthJwtClaimSet(conf_v1.JWTCondition{Claim: r.Claim}, ownerDetails)
+		p.JWTAuth.AuthJWTClaimSets = append(p.JWTAuth.AuthJWTClaimSets, claimSet)
+
+		variable := fmt.Sprintf("$%s_require_%d", mapPrefix, i)
+		var params []version2.Parameter
+		for _, v := range r.Values {
+			params = append(params, version2.Parameter{
+				Value:  fmt.Sprintf("\"~(^|,)%s(,|$)\"", regexp.QuoteMeta(v)),
+				Result: "1",
+			})
+		}
+		params = append(params, version2.Parameter{
+			Value:  "default",
+			Result: "0",
+		})
+		p.JWTAuth.Maps = append(p.JWTAuth.Maps, version2.Map{
+			Source:     claimSet.Variable,
+			Variable:   variable,
+			Parameters: params,
+		})
+		p.JWTAuth.Auth.Require = append(p.JWTAuth.Auth.Require, variable)
+	}
+
+	if len(requirements) > 0 {
+		p.JWTAuth.Auth.RequireErrorCode = 403
+		if authz.ErrorCode != nil {
+			p.JWTAuth.Auth.RequireErrorCode = *authz.ErrorCode
+		}
+	}
+
+	for _, h := range authz.Headers {
+		claimSet := generateAuthJwtClaimSet(conf_v1.JWTCondition{Claim: h.Claim}, ownerDetails)
+		p.JWTAuth.AuthJWTClaimSets = append(p.JWTAuth.AuthJWTClaimSets, claimSet)
+		p.JWTAuth.Headers = append(p.JWTAuth.Headers, version2.Header{Name: h.Name, Value: claimSet.Variable})
+	}
+}
+
 func (p *policiesCfg) addIngressMTLSConfig(
 	ingressMTLS *conf_v1.IngressMTLS,
 	polKey string,
@@ -1192,7 +2113,7 @@ func (p *policiesCfg) addIngressMTLSConfig(
 		return res
 	}
 
-	secretKey := fmt.Sprintf("%v/%v", polNamespace, ingressMTLS.ClientCertSecret)
+	secretKey := GeneratePolicySecretKey(polNamespace, ingressMTLS.ClientCertSecret)
 	secretRef := secretRefs[secretKey]
 	var secretType api_v1.SecretType
 	if secretRef.Secret != nil {
@@ -1265,7 +2186,7 @@ func (p *policiesCfg) addEgressMTLSConfig(
 	var tlsSecretPath string
 
 	if egressMTLS.TLSSecret != "" {
-		egressTLSSecret := fmt.Sprintf("%v/%v", polNamespace, egressMTLS.TLSSecret)
+		egressTLSSecret := GeneratePolicySecretKey(polNamespace, egressMTLS.TLSSecret)
 
 		secretRef := secretRefs[egressTLSSecret]
 		var secretType api_v1.SecretType
@@ -1288,7 +2209,7 @@ func (p *policiesCfg) addEgressMTLSConfig(
 	var trustedSecretPath string
 
 	if egressMTLS.TrustedCertSecret != "" {
-		trustedCertSecret := fmt.Sprintf("%v/%v", polNamespace, egressMTLS.TrustedCertSecret)
+		trustedCertSecret := GeneratePolicySecretKey(polNamespace, egressMTLS.TrustedCertSecret)
 
 		secretRef := secretRefs[trustedCertSecret]
 		var secretType api_v1.SecretType
@@ -1332,11 +2253,13 @@ func (p *policiesCfg) addOIDCConfig(
 	oidc *conf_v1.OIDC,
 	polKey string,
 	polNamespace string,
+	polName string,
+	ownerDetails policyOwnerDetails,
 	secretRefs map[string]*secrets.SecretReference,
 	oidcPolCfg *oidcPolicyCfg,
 ) *validationResults {
 	res := newValidationResults()
-	if p.OIDC {
+	if p.OIDC != nil {
 		res.addWarningf(
 			"Multiple oidc policies in the same context is not valid. OIDC policy %s will be ignored",
 			polKey,
@@ -1344,18 +2267,14 @@ func (p *policiesCfg) addOIDCConfig(
 		return res
 	}
 
-	if oidcPolCfg.oidc != nil {
-		if oidcPolCfg.key != polKey {
-			res.addWarningf(
-				"Only one oidc policy is allowed in a VirtualServer and its VirtualServerRoutes. Can't use %s. Use %s",
-				polKey,
-				oidcPolCfg.key,
-			)
-			res.isError = true
-			return res
-		}
-	} else {
-		secretKey := fmt.Sprintf("%v/%v", polNamespace, oidc.ClientSecret)
+	if provider, exists := oidcPolCfg.keys[polKey]; exists {
+		p.OIDC = provider
+		return res
+	}
+
+	var clientSecret []byte
+	if !oidc.PKCEEnable {
+		secretKey := GeneratePolicySecretKey(polNamespace, oidc.ClientSecret)
 		secretRef := secretRefs[secretKey]
 
 		var secretType api_v1.SecretType
@@ -1372,47 +2291,401 @@ func (p *policiesCfg) addOIDCConfig(
 			return res
 		}
 
-		clientSecret := secretRef.Secret.Data[ClientSecretKey]
+		clientSecret = secretRef.Secret.Data[ClientSecretKey]
+	}
+
+	redirectURI := oidc.RedirectURI
+	if redirectURI == "" {
+		redirectURI = defaultOIDCRedirectURI
+	}
+	logoutURI := oidc.LogoutURI
+	if logoutURI == "" {
+		logoutURI = defaultOIDCLogoutURI
+	}
+	postLogoutRedirectURI := oidc.PostLogoutRedirectURI
+	if postLogoutRedirectURI == "" {
+		postLogoutRedirectURI = "/_logout"
+	}
+	scope := oidc.Scope
+	if scope == "" {
+		scope = "openid"
+	}
+	authExtraArgs := ""
+	if oidc.AuthExtraArgs != nil {
+		authExtraArgs = strings.Join(oidc.AuthExtraArgs, "&")
+	}
+
+	session := oidc.Session
+	if session == nil {
+		session = &conf_v1.OIDCSession{}
+	}
+
+	provider := &version2.OIDC{
+		Key:                   generateOIDCProviderKey(ownerDetails.vsNamespace, ownerDetails.vsName, polNamespace, polName),
+		AuthEndpoint:          oidc.AuthEndpoint,
+		AuthExtraArgs:         authExtraArgs,
+		TokenEndpoint:         oidc.TokenEndpoint,
+		JwksURI:               oidc.JWKSURI,
+		EndSessionEndpoint:    oidc.EndSessionEndpoint,
+		ClientID:              oidc.ClientID,
+		ClientSecret:          string(clientSecret),
+		Scope:                 scope,
+		RedirectURI:           redirectURI,
+		LogoutURI:             logoutURI,
+		PostLogoutRedirectURI: postLogoutRedirectURI,
+		ZoneSyncLeeway:        generateIntFromPointer(oidc.ZoneSyncLeeway, 200),
+		AccessTokenEnable:     oidc.AccessTokenEnable,
+		PKCEEnable:            oidc.PKCEEnable,
+		HMACKey:               ownerDetails.vsName,
+		CookieName:            generateString(session.CookieName, defaultOIDCCookieName),
+		CookieDomain:          session.CookieDomain,
+		CookieSameSite:        generateString(session.CookieSameSite, "lax"),
+		SessionTimeout:        generateTimeWithDefault(session.Timeout, "1h"),
+		RefreshTimeout:        generateTimeWithDefault(session.RefreshTimeout, "8h"),
+	}
+
+	if oidcPolCfg.keys == nil {
+		oidcPolCfg.keys = make(map[string]*version2.OIDC)
+		oidcPolCfg.defaults = make(map[*version2.OIDC]oidcDefaults)
+	}
+	oidcPolCfg.keys[polKey] = provider
+	oidcPolCfg.defaults[provider] = oidcDefaults{
+		redirectURI: oidc.RedirectURI == "",
+		logoutURI:   oidc.LogoutURI == "",
+		cookieName:  session.CookieName == "",
+	}
+	oidcPolCfg.providers = append(oidcPolCfg.providers, provider)
+
+	p.OIDC = provider
+
+	return res
+}
+
+// generateOIDCProviderKey generates the suffix of the key-value zones and variables of an OIDC provider.
+func generateOIDCProviderKey(vsNamespace, vsName, polNamespace, polName string) string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(fmt.Sprintf("%s_%s_%s_%s", vsNamespace, vsName, polNamespace, polName))
+}
+
+// generateOIDCProviders returns the OIDC providers of the server. The redirect and logout locations of a provider
+// must not collide with the locations of the routes or with the locations of the other providers.
+// The routes that use a provider with a colliding location return 500.
+// When the server has several providers, the default redirect URI, logout URI and cookie name of a provider
+// get the key of the provider as a suffix, so that the providers don't share their locations and sessions.
+func (vsc *virtualServerConfigurator) generateOIDCProviders(owner runtime.Object, locations []version2.Location) []*version2.OIDC {
+	if len(vsc.oidcPolCfg.providers) == 0 {
+		return nil
+	}
+
+	if len(vsc.oidcPolCfg.providers) > 1 {
+		for _, provider := range vsc.oidcPolCfg.providers {
+			defaults := vsc.oidcPolCfg.defaults[provider]
+			if defaults.redirectURI {
+				provider.RedirectURI = fmt.Sprintf("%s_%s", defaultOIDCRedirectURI, provider.Key)
+			}
+			if defaults.logoutURI {
+				provider.LogoutURI = fmt.Sprintf("%s_%s", defaultOIDCLogoutURI, provider.Key)
+			}
+			if defaults.cookieName {
+				provider.CookieName = fmt.Sprintf("%s_%s", defaultOIDCCookieName, provider.Key)
+			}
+		}
+	}
 
-		redirectURI := oidc.RedirectURI
-		if redirectURI == "" {
-			redirectURI = "/_codexch"
+	paths := make(map[string]bool)
+	for _, l := range locations {
+		if strings.HasPrefix(l.Path, "~") {
+			continue
 		}
-		postLogoutRedirectURI := oidc.PostLogoutRedirectURI
-		if postLogoutRedirectURI == "" {
-			postLogoutRedirectURI = "/_logout"
+		paths[strings.TrimSpace(strings.TrimPrefix(l.Path, "="))] = true
+	}
+
+	var providers []*version2.OIDC
+	invalid := make(map[*version2.OIDC]bool)
+	for _, provider := range vsc.oidcPolCfg.providers {
+		collision := ""
+		for _, uri := range []string{provider.RedirectURI, provider.LogoutURI} {
+			if paths[uri] {
+				collision = uri
+				break
+			}
 		}
-		scope := oidc.Scope
-		if scope == "" {
-			scope = "openid"
+		if collision != "" {
+			vsc.addWarningf(owner, "The location %s of the OIDC provider %s collides with another location of the VirtualServer", collision, provider.Key)
+			invalid[provider] = true
+			continue
 		}
-		authExtraArgs := ""
-		if oidc.AuthExtraArgs != nil {
-			authExtraArgs = strings.Join(oidc.AuthExtraArgs, "&")
+		paths[provider.RedirectURI] = true
+		paths[provider.LogoutURI] = true
+		providers = append(providers, provider)
+	}
+
+	for i := range locations {
+		if locations[i].OIDC != nil && invalid[locations[i].OIDC] {
+			locations[i].OIDC = nil
+			locations[i].PoliciesErrorReturn = &version2.Return{Code: 500}
 		}
+	}
+
+	return providers
+}
+
+func (p *policiesCfg) addOAuth2IntrospectionConfig(
+	introspection *conf_v1.OAuth2Introspection,
+	polKey string,
+	polNamespace string,
+	polName string,
+	ownerDetails policyOwnerDetails,
+	secretRefs map[string]*secrets.SecretReference,
+) *validationResults {
+	res := newValidationResults()
+	if p.OAuth2Introspection != nil {
+		res.addWarningf(
+			"Multiple oauth2Introspection policies in the same context is not valid. OAuth2Introspection policy %s will be ignored",
+			polKey,
+		)
+		return res
+	}
+
+	secretKey := GeneratePolicySecretKey(polNamespace, introspection.ClientSecret)
+	secretRef := secretRefs[secretKey]
+
+	var secretType api_v1.SecretType
+	if secretRef.Secret != nil {
+		secretType = secretRef.Secret.Type
+	}
+	if secretType != "" && secretType != secrets.SecretTypeOAuth2Introspection {
+		res.addWarningf("OAuth2Introspection policy %s references a secret %s of a wrong type '%s', must be '%s'",
+			polKey, secretKey, secretType, secrets.SecretTypeOAuth2Introspection)
+		res.isError = true
+		return res
+	} else if secretRef.Error != nil {
+		res.addWarningf("OAuth2Introspection policy %s references an invalid secret %s: %v", polKey, secretKey, secretRef.Error)
+		res.isError = true
+		return res
+	}
+
+	// The client credentials are form-encoded before they are combined, as required by RFC 6749.
+	credentials := url.QueryEscape(string(secretRef.Secret.Data[secrets.ClientIDKey])) + ":" +
+		url.QueryEscape(string(secretRef.Secret.Data[ClientSecretKey]))
+
+	uri, _ := url.Parse(introspection.Endpoint)
+	var headers []version2.OAuth2IntrospectionHeader
+	for _, h := range introspection.Headers {
+		headers = append(headers, version2.OAuth2IntrospectionHeader{Name: h.Name, Field: h.Field})
+	}
+
+	p.OAuth2Introspection = &version2.OAuth2Introspection{
+		Key:            rfc1123ToSnake(fmt.Sprintf("%s_%s_%s_%s", polNamespace, polName, ownerDetails.vsNamespace, ownerDetails.vsName)),
+		EndpointScheme: uri.Scheme,
+		EndpointHost:   uri.Hostname(),
+		EndpointPort:   uri.Port(),
+		EndpointPath:   uri.RequestURI(),
+		Authorization:  "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials)),
+		Scopes:         strings.Join(introspection.Scopes, " "),
+		Audiences:      strings.Join(introspection.Audiences, " "),
+		CacheTimeout:   generateTimeWithDefault(introspection.CacheTimeout, "1m"),
+		Headers:        headers,
+	}
+
+	return res
+}
 
-		oidcPolCfg.oidc = &version2.OIDC{
-			AuthEndpoint:          oidc.AuthEndpoint,
-			AuthExtraArgs:         authExtraArgs,
-			TokenEndpoint:         oidc.TokenEndpoint,
-			JwksURI:               oidc.JWKSURI,
-			EndSessionEndpoint:    oidc.EndSessionEndpoint,
-			ClientID:              oidc.ClientID,
-			ClientSecret:          string(clientSecret),
-			Scope:                 scope,
-			RedirectURI:           redirectURI,
-			PostLogoutRedirectURI: postLogoutRedirectURI,
-			ZoneSyncLeeway:        generateIntFromPointer(oidc.ZoneSyncLeeway, 200),
-			AccessTokenEnable:     oidc.AccessTokenEnable,
+// generateOAuth2Introspections returns the OAuth2Introspection policies applied to the locations of the server.
+// Every policy gets a shared dictionary zone for the introspection results and the locations of the introspection.
+func generateOAuth2Introspections(locations []version2.Location) []*version2.OAuth2Introspection {
+	var introspections []*version2.OAuth2Introspection
+	keys := make(map[string]bool)
+	for _, l := range locations {
+		if l.OAuth2Introspection == nil || keys[l.OAuth2Introspection.Key] {
+			continue
 		}
-		oidcPolCfg.key = polKey
+		keys[l.OAuth2Introspection.Key] = true
+		introspections = append(introspections, l.OAuth2Introspection)
+	}
+	return introspections
+}
+
+func (p *policiesCfg) addSignatureVerificationConfig(
+	sv *conf_v1.SignatureVerification,
+	polKey string,
+	polNamespace string,
+	secretRefs map[string]*secrets.SecretReference,
+) *validationResults {
+	res := newValidationResults()
+	if p.SignatureVerification != nil {
+		res.addWarningf(
+			"Multiple signatureVerification policies in the same context is not valid. SignatureVerification policy %s will be ignored",
+			polKey,
+		)
+		return res
+	}
+
+	secretKey := GeneratePolicySecretKey(polNamespace, sv.Secret)
+	secretRef := secretRefs[secretKey]
+
+	var secretType api_v1.SecretType
+	if secretRef.Secret != nil {
+		secretType = secretRef.Secret.Type
+	}
+	if secretType != "" && secretType != secrets.SecretTypeHMAC {
+		res.addWarningf("SignatureVerification policy %s references a secret %s of a wrong type '%s', must be '%s'",
+			polKey, secretKey, secretType, secrets.SecretTypeHMAC)
+		res.isError = true
+		return res
+	} else if secretRef.Error != nil {
+		res.addWarningf("SignatureVerification policy %s references an invalid secret %s: %v", polKey, secretKey, secretRef.Error)
+		res.isError = true
+		return res
+	}
+
+	var tolerance int64
+	payload := generateString(sv.SignedPayload, "{body}")
+	if sv.TimestampHeader != "" {
+		// the tolerance is validated with the policy.
+		tolerance, _ = ParseTimeToSeconds(generateString(sv.TimestampTolerance, "5m"))
+		payload = generateString(sv.SignedPayload, "{timestamp}.{body}")
+	}
+
+	p.SignatureVerification = &version2.SignatureVerification{
+		Header:             sv.Header,
+		Algorithm:          generateString(sv.Algorithm, "sha256"),
+		Encoding:           generateString(sv.Encoding, "hex"),
+		Prefix:             sv.Prefix,
+		Key:                base64.StdEncoding.EncodeToString(secretRef.Secret.Data[secrets.HMACKeyKey]),
+		TimestampHeader:    sv.TimestampHeader,
+		TimestampTolerance: tolerance,
+		SignedPayload:      payload,
+	}
+
+	return res
+}
+
+func (p *policiesCfg) addChallengeConfig(
+	challenge *conf_v1.Challenge,
+	polKey string,
+	polNamespace string,
+	polName string,
+	ownerDetails policyOwnerDetails,
+	secretRefs map[string]*secrets.SecretReference,
+) *validationResults {
+	res := newValidationResults()
+	if p.Challenge != nil {
+		res.addWarningf(
+			"Multiple challenge policies in the same context is not valid. Challenge policy %s will be ignored",
+			polKey,
+		)
+		return res
 	}
 
-	p.OIDC = true
+	secretKey := GeneratePolicySecretKey(polNamespace, challenge.Secret)
+	secretRef := secretRefs[secretKey]
+
+	var secretType api_v1.SecretType
+	if secretRef.Secret != nil {
+		secretType = secretRef.Secret.Type
+	}
+	if secretType != "" && secretType != secrets.SecretTypeHMAC {
+		res.addWarningf("Challenge policy %s references a secret %s of a wrong type '%s', must be '%s'",
+			polKey, secretKey, secretType, secrets.SecretTypeHMAC)
+		res.isError = true
+		return res
+	} else if secretRef.Error != nil {
+		res.addWarningf("Challenge policy %s references an invalid secret %s: %v", polKey, secretKey, secretRef.Error)
+		res.isError = true
+		return res
+	}
+
+	// the lifetime is validated with the policy.
+	lifetime, _ := ParseTimeToSeconds(generateString(challenge.CookieLifetime, "1h"))
+
+	p.Challenge = &version2.Challenge{
+		Key:              rfc1123ToSnake(fmt.Sprintf("%s_%s_%s_%s", polNamespace, polName, ownerDetails.vsNamespace, ownerDetails.vsName)),
+		Type:             generateString(challenge.Type, "javascript"),
+		SecretKey:        base64.StdEncoding.EncodeToString(secretRef.Secret.Data[secrets.HMACKeyKey]),
+		CookieName:       generateString(challenge.CookieName, "nic_challenge"),
+		CookieLifetime:   lifetime,
+		ExemptCIDRs:      challenge.ExemptCIDRs,
+		ExemptUserAgents: challenge.ExemptUserAgents,
+	}
+	if challenge.Block != nil {
+		p.Challenge.BlockThreshold = challenge.Block.Threshold
+		p.Challenge.BlockInterval = generateTimeWithDefault(challenge.Block.Interval, "1m")
+		p.Challenge.BlockDuration = generateTimeWithDefault(challenge.Block.Duration, "10m")
+	}
 
 	return res
 }
 
+// generateChallenges returns the Challenge policies applied to the locations of the server.
+// Every policy gets the variables of its exempt clients, the zones of its blocked clients and the location of the challenge.
+func generateChallenges(locations []version2.Location) []*version2.Challenge {
+	var challenges []*version2.Challenge
+	keys := make(map[string]bool)
+	for _, l := range locations {
+		if l.Challenge == nil || keys[l.Challenge.Key] {
+			continue
+		}
+		keys[l.Challenge.Key] = true
+		challenges = append(challenges, l.Challenge)
+	}
+	return challenges
+}
+
+// generateSignatureVerificationLocations returns the named locations for the locations with a SignatureVerification policy.
+// A location with the policy verifies the signature of the request body with njs and redirects the request
+// to its named location, which passes the request to the upstream. The access policies of the location are
+// already enforced before the redirect, so they are not repeated in the named location.
+func generateSignatureVerificationLocations(locations []version2.Location) []version2.Location {
+	var namedLocations []version2.Location
+	for i := range locations {
+		l := &locations[i]
+		if l.SignatureVerification == nil {
+			continue
+		}
+		if l.ProxyPass == "" && l.GRPCPass == "" {
+			// the signature of the requests that are not passed to an upstream is not verified.
+			l.SignatureVerification = nil
+			continue
+		}
+
+		named := *l
+		named.Path = fmt.Sprintf("@signature_verification_%d", len(namedLocations))
+		named.Internal = false
+		named.Allow = nil
+		named.Deny = nil
+		named.LimitReqOptions = version2.LimitReqOptions{}
+		named.LimitReqs = nil
+		named.JWTAuth = nil
+		named.BasicAuth = nil
+		named.OIDC = nil
+		named.APIKey = nil
+		named.OAuth2Introspection = nil
+		named.SignatureVerification = nil
+		named.Challenge = nil
+		named.WAF = nil
+		named.Dos = nil
+		named.AllowedMethods = nil
+		named.MaxURILength = 0
+		if named.ProxyPassRewrite != "" {
+			// proxy_pass can't have a URI in a named location, so the URI is rewritten before the request is passed.
+			path := strings.TrimSpace(strings.TrimPrefix(l.Path, "="))
+			named.Rewrites = append(slices.Clone(named.Rewrites), fmt.Sprintf(`"^%v(.*)$" "%v$1" break`, path, named.ProxyPassRewrite))
+			named.ProxyPassRewrite = ""
+		}
+
+		sv := *l.SignatureVerification
+		sv.Location = named.Path
+		sv.BodyBufferSize = l.ClientMaxBodySize
+		if sv.BodyBufferSize == "" || sv.BodyBufferSize == "0" {
+			sv.BodyBufferSize = "1m"
+		}
+		l.SignatureVerification = &sv
+
+		namedLocations = append(namedLocations, named)
+	}
+	return namedLocations
+}
+
 func (p *policiesCfg) addAPIKeyConfig(
 	apiKey *conf_v1.APIKey,
 	polKey string,
@@ -1431,7 +2704,7 @@ func (p *policiesCfg) addAPIKeyConfig(
 		return res
 	}
 
-	secretKey := fmt.Sprintf("%v/%v", polNamespace, apiKey.ClientSecret)
+	secretKey := GeneratePolicySecretKey(polNamespace, apiKey.ClientSecret)
 	secretRef := secretRefs[secretKey]
 	var secretType api_v1.SecretType
 	if secretRef.Secret != nil {
@@ -1447,39 +2720,136 @@ func (p *policiesCfg) addAPIKeyConfig(
 		return res
 	}
 
-	p.APIKey.Clients = generateAPIKeyClients(secretRef.Secret.Data)
+	p.APIKey.Clients = generateAPIKeyClients(secretRef.Secret)
 
-	mapName := fmt.Sprintf(
-		"apikey_auth_client_name_%s_%s_%s",
+	mapSuffix := fmt.Sprintf(
+		"%s_%s_%s",
 		rfc1123ToSnake(vsNamespace),
 		rfc1123ToSnake(vsName),
 		strings.Split(rfc1123ToSnake(polKey), "/")[1],
 	)
 	p.APIKey.Key = &version2.APIKey{
-		Header:  apiKey.SuppliedIn.Header,
-		Query:   apiKey.SuppliedIn.Query,
-		MapName: mapName,
+		Header:         apiKey.SuppliedIn.Header,
+		Query:          apiKey.SuppliedIn.Query,
+		MapName:        apiKeyClientNameMapPrefix + mapSuffix,
+		ClientIDHeader: apiKey.ClientIDHeader,
+	}
+	if apiKeyClientsHaveExpiry(p.APIKey.Clients) {
+		p.APIKey.Key.ExpiresMapName = apiKeyClientExpiresMapPrefix + mapSuffix
+	}
+	if apiKeyClientsHaveTier(p.APIKey.Clients) {
+		p.APIKey.Key.TierMapName = apiKeyClientTierMapPrefix + mapSuffix
 	}
 	p.APIKey.Enabled = true
 	return res
 }
 
+func generateServerRequestLimits(limits *requestLimits) *version2.ServerRequestLimits {
+	if limits == nil || (limits.headerBufferSize == "" && limits.largeHeaderBuffers == "" && limits.headerTimeout == "") {
+		return nil
+	}
+	return &version2.ServerRequestLimits{
+		ClientHeaderBufferSize:   limits.headerBufferSize,
+		LargeClientHeaderBuffers: limits.largeHeaderBuffers,
+		ClientHeaderTimeout:      limits.headerTimeout,
+	}
+}
+
+const (
+	maintenanceLocation    = "/" + internalLocationPrefix + "maintenance"
+	defaultMaintenanceBody = "Service is under maintenance"
+	defaultMaintenanceType = "text/plain"
+)
+
+func generateMaintenance(vs *conf_v1.VirtualServer) *version2.Maintenance {
+	m := vs.Spec.Maintenance
+	if m == nil || !m.Enable {
+		return nil
+	}
+
+	variable := rfc1123ToSnake(fmt.Sprintf("$vs_maintenance_%s_%s", vs.Namespace, vs.Name))
+	cfg := &version2.Maintenance{
+		Variable:     variable,
+		AddrVariable: variable,
+		AllowList:    m.AllowList,
+		Location:     maintenanceLocation,
+		DefaultType:  defaultMaintenanceType,
+		Body:         defaultMaintenanceBody,
+	}
+
+	if m.Header != nil {
+		cfg.AddrVariable = variable + "_addr"
+		cfg.Header = "$http_" + strings.ReplaceAll(strings.ToLower(m.Header.Name), "-", "_")
+		cfg.Token = m.Header.Token
+	}
+	if m.Type != "" {
+		cfg.DefaultType = m.Type
+	}
+	if m.Body != "" {
+		cfg.Body = m.Body
+	}
+	if m.RetryAfter != "" {
+		// the value is validated
+		cfg.RetryAfter, _ = ParseTimeToSeconds(m.RetryAfter)
+	}
+
+	return cfg
+}
+
 func rfc1123ToSnake(rfc1123String string) string {
 	return strings.Replace(rfc1123String, "-", "_", -1)
 }
 
-func generateAPIKeyClients(secretData map[string][]byte) []apiKeyClient {
+const (
+	apiKeyClientNameMapPrefix    = "apikey_auth_client_name_"
+	apiKeyClientExpiresMapPrefix = "apikey_auth_client_expires_"
+	apiKeyClientTierMapPrefix    = "apikey_auth_client_tier_"
+	// apiKeyClientTierVariable holds the tier of the client of the API key policy of a location.
+	apiKeyClientTierVariable = "$apikey_client_tier"
+)
+
+// generateAPIKeyClients returns the enabled clients of an API key secret with the hashes of their keys.
+// The secret is validated, so the errors are ignored.
+func generateAPIKeyClients(secret *api_v1.Secret) []apiKeyClient {
+	parsed, _ := secrets.ParseAPIKeyClients(secret)
+
 	var clients []apiKeyClient
-	for clientID, apiKey := range secretData {
+	for _, client := range parsed {
+		if client.Disabled {
+			continue
+		}
 
 		h := sha256.New()
-		h.Write(apiKey)
+		h.Write(client.Key)
 		sha256Hash := hex.EncodeToString(h.Sum(nil))
-		clients = append(clients, apiKeyClient{ClientID: clientID, HashedKey: sha256Hash}) //
+
+		var expires int64
+		if !client.Expires.IsZero() {
+			expires = client.Expires.Unix()
+		}
+		clients = append(clients, apiKeyClient{ClientID: client.ID, HashedKey: sha256Hash, Expires: expires, Tier: client.Tier})
 	}
 	return clients
 }
 
+func apiKeyClientsHaveExpiry(clients []apiKeyClient) bool {
+	for _, client := range clients {
+		if client.Expires != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func apiKeyClientsHaveTier(clients []apiKeyClient) bool {
+	for _, client := range clients {
+		if client.Tier != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func generateAPIKeyClientMap(mapName string, apiKeyClients []apiKeyClient) *version2.Map {
 	defaultParam := version2.Parameter{
 		Value:  "default",
@@ -1503,6 +2873,49 @@ func generateAPIKeyClientMap(mapName string, apiKeyClients []apiKeyClient) *vers
 	}
 }
 
+// generateAPIKeyClientMaps returns the map of the client IDs of an API key policy and the maps of the expiry times
+// and the tiers of its clients, if any client has them.
+func generateAPIKeyClientMaps(mapName string, apiKeyClients []apiKeyClient) []version2.Map {
+	maps := []version2.Map{*generateAPIKeyClientMap(mapName, apiKeyClients)}
+	mapSuffix := strings.TrimPrefix(mapName, apiKeyClientNameMapPrefix)
+
+	if apiKeyClientsHaveExpiry(apiKeyClients) {
+		params := []version2.Parameter{{Value: "default", Result: "\"\""}}
+		for _, client := range apiKeyClients {
+			if client.Expires != 0 {
+				params = append(params, version2.Parameter{
+					Value:  fmt.Sprintf("\"%s\"", client.HashedKey),
+					Result: fmt.Sprintf("\"%d\"", client.Expires),
+				})
+			}
+		}
+		maps = append(maps, version2.Map{
+			Source:     "$apikey_auth_token",
+			Variable:   "$" + apiKeyClientExpiresMapPrefix + mapSuffix,
+			Parameters: params,
+		})
+	}
+
+	if apiKeyClientsHaveTier(apiKeyClients) {
+		params := []version2.Parameter{{Value: "default", Result: "\"\""}}
+		for _, client := range apiKeyClients {
+			if client.Tier != "" {
+				params = append(params, version2.Parameter{
+					Value:  fmt.Sprintf("\"%s\"", client.HashedKey),
+					Result: fmt.Sprintf("\"%s\"", client.Tier),
+				})
+			}
+		}
+		maps = append(maps, version2.Map{
+			Source:     "$apikey_auth_token",
+			Variable:   "$" + apiKeyClientTierMapPrefix + mapSuffix,
+			Parameters: params,
+		})
+	}
+
+	return maps
+}
+
 func generateLRZGroupMaps(rlzs []version2.LimitReqZone) map[string]*version2.Map {
 	m := make(map[string]*version2.Map)
 
@@ -1654,11 +3067,18 @@ func (vsc *virtualServerConfigurator) generatePolicies(
 
 		key := fmt.Sprintf("%s/%s", polNamespace, p.Name)
 
+		if err := getDeniedReference(policyOpts.deniedReferences, ownerDetails.owner, conf_v1.ReferenceGrantKindPolicy, key); err != nil {
+			vsc.addWarningf(ownerDetails.owner, "Policy %s cannot be referenced: %v", key, err)
+			return policiesCfg{
+				ErrorReturn: &version2.Return{Code: 500},
+			}
+		}
+
 		if pol, exists := policies[key]; exists {
 			var res *validationResults
 			switch {
 			case pol.Spec.AccessControl != nil:
-				res = config.addAccessControlConfig(pol.Spec.AccessControl)
+				res = config.addAccessControlConfig(pol.Spec.AccessControl, key, polNamespace, p.Name, ownerDetails, vsc.cfgParams)
 			case pol.Spec.RateLimit != nil:
 				res = config.addRateLimitConfig(
 					pol.Spec.RateLimit,
@@ -1669,7 +3089,7 @@ func (vsc *virtualServerConfigurator) generatePolicies(
 					vsc.IngressControllerReplicas,
 				)
 			case pol.Spec.JWTAuth != nil:
-				res = config.addJWTAuthConfig(pol.Spec.JWTAuth, key, polNamespace, policyOpts.secretRefs)
+				res = config.addJWTAuthConfig(pol.Spec.JWTAuth, key, polNamespace, p.Name, ownerDetails, policyOpts.secretRefs)
 			case pol.Spec.BasicAuth != nil:
 				res = config.addBasicAuthConfig(pol.Spec.BasicAuth, key, polNamespace, policyOpts.secretRefs)
 			case pol.Spec.IngressMTLS != nil:
@@ -1684,12 +3104,28 @@ func (vsc *virtualServerConfigurator) generatePolicies(
 			case pol.Spec.EgressMTLS != nil:
 				res = config.addEgressMTLSConfig(pol.Spec.EgressMTLS, key, polNamespace, policyOpts.secretRefs)
 			case pol.Spec.OIDC != nil:
-				res = config.addOIDCConfig(pol.Spec.OIDC, key, polNamespace, policyOpts.secretRefs, vsc.oidcPolCfg)
+				res = config.addOIDCConfig(pol.Spec.OIDC, key, polNamespace, p.Name, ownerDetails, policyOpts.secretRefs, vsc.oidcPolCfg)
 			case pol.Spec.APIKey != nil:
 				res = config.addAPIKeyConfig(pol.Spec.APIKey, key, polNamespace, ownerDetails.vsNamespace,
 					ownerDetails.vsName, policyOpts.secretRefs)
 			case pol.Spec.WAF != nil:
 				res = config.addWAFConfig(vsc.cfgParams.Context, pol.Spec.WAF, key, polNamespace, policyOpts.apResources)
+			case pol.Spec.Retry != nil:
+				res = config.addRetryConfig(pol.Spec.Retry, key, context)
+			case pol.Spec.CircuitBreaker != nil:
+				res = config.addCircuitBreakerConfig(key, context)
+			case pol.Spec.Headers != nil:
+				res = config.addHeadersConfig(pol.Spec.Headers, key, polNamespace, p.Name, ownerDetails)
+			case pol.Spec.RequestLimits != nil:
+				res = config.addRequestLimitsConfig(pol.Spec.RequestLimits, key, context)
+			case pol.Spec.OAuth2Introspection != nil:
+				res = config.addOAuth2IntrospectionConfig(pol.Spec.OAuth2Introspection, key, polNamespace, p.Name, ownerDetails, policyOpts.secretRefs)
+			case pol.Spec.SignatureVerification != nil:
+				res = config.addSignatureVerificationConfig(pol.Spec.SignatureVerification, key, polNamespace, policyOpts.secretRefs)
+			case pol.Spec.BandwidthLimit != nil:
+				res = config.addBandwidthLimitConfig(pol.Spec.BandwidthLimit, key, polNamespace, p.Name, ownerDetails)
+			case pol.Spec.Challenge != nil:
+				res = config.addChallengeConfig(pol.Spec.Challenge, key, polNamespace, p.Name, ownerDetails, policyOpts.secretRefs)
 			default:
 				res = newValidationResults()
 			}
@@ -1790,7 +3226,20 @@ func generateGroupedLimitReqZone(zoneName string,
 		lrz.Key = fmt.Sprintf("$%s", strings.Replace(zoneName, "-", "_", -1))
 		lrz.PolicyResult = rateLimitPol.Key
 		lrz.GroupDefault = rateLimitPol.Condition.Default
-		lrz.GroupSource = generateAuthJwtClaimSetVariable(rateLimitPol.Condition.JWT.Claim, ownerDetails.vsNamespace, ownerDetails.vsName)
+		lrz.GroupSource = generateAuthJwtClaimSetVariable(rateLimitPol.Condition.JWT.Claim, ownerDetails.vsNamespace, ownerDetails.vsName)
+	}
+	if rateLimitPol.Condition != nil && rateLimitPol.Condition.APIKey != nil {
+		lrz.GroupValue = rateLimitPol.Condition.APIKey.Tier
+		lrz.PolicyValue = fmt.Sprintf("rl_%s_%s_apikey_tier_%s",
+			ownerDetails.vsNamespace,
+			ownerDetails.vsName,
+			strings.ToLower(rateLimitPol.Condition.APIKey.Tier),
+		)
+		lrz.GroupVariable = fmt.Sprintf("$rl_%s_%s_group_apikey_tier", ownerDetails.vsNamespace, ownerDetails.vsName)
+		lrz.Key = fmt.Sprintf("$%s", strings.Replace(zoneName, "-", "_", -1))
+		lrz.PolicyResult = rateLimitPol.Key
+		lrz.GroupDefault = rateLimitPol.Condition.Default
+		lrz.GroupSource = apiKeyClientTierVariable
 	}
 
 	return lrz
@@ -1858,7 +3307,7 @@ func generateAuthJwtClaimSet(jwtCondition conf_v1.JWTCondition, owner policyOwne
 }
 
 func generateAuthJwtClaimSetVariable(claim string, vsNamespace string, vsName string) string {
-	return fmt.Sprintf("$jwt_%v_%v_%v", vsNamespace, vsName, strings.Join(strings.Split(claim, "."), "_"))
+	return rfc1123ToSnake(fmt.Sprintf("$jwt_%v_%v_%v", vsNamespace, vsName, strings.Join(strings.Split(claim, "."), "_")))
 }
 
 func generateAuthJwtClaimSetClaim(claim string) string {
@@ -1880,15 +3329,106 @@ func hasDuplicateMapDefaults(m *version2.Map) bool {
 func addPoliciesCfgToLocation(cfg policiesCfg, location *version2.Location) {
 	location.Allow = cfg.Allow
 	location.Deny = cfg.Deny
+	if cfg.GeoAccess != nil {
+		location.GeoAccess = cfg.GeoAccess.variable
+	}
 	location.LimitReqOptions = cfg.RateLimit.Options
 	location.LimitReqs = cfg.RateLimit.Reqs
+	if cfg.BandwidthLimit != nil {
+		location.LimitRate = cfg.BandwidthLimit.rate
+		location.LimitRateAfter = cfg.BandwidthLimit.rateAfter
+	}
 	location.JWTAuth = cfg.JWTAuth.Auth
 	location.BasicAuth = cfg.BasicAuth
 	location.EgressMTLS = cfg.EgressMTLS
 	location.OIDC = cfg.OIDC
 	location.WAF = cfg.WAF
 	location.APIKey = cfg.APIKey.Key
+	location.OAuth2Introspection = cfg.OAuth2Introspection
+	location.SignatureVerification = cfg.SignatureVerification
+	location.Challenge = cfg.Challenge
 	location.PoliciesErrorReturn = cfg.ErrorReturn
+
+	if cfg.Headers != nil {
+		addPolicyHeadersToLocation(cfg.Headers, location)
+	}
+
+	if cfg.Retry != nil && (location.ProxyPass != "" || location.GRPCPass != "") {
+		location.ProxyNextUpstream = cfg.Retry.nextUpstream
+		if cfg.Retry.nextUpstreamTimeout != "" {
+			location.ProxyNextUpstreamTimeout = cfg.Retry.nextUpstreamTimeout
+		}
+		location.ProxyNextUpstreamTries = cfg.Retry.nextUpstreamTries
+		if cfg.Retry.perTryTimeout != "" {
+			location.ProxyConnectTimeout = cfg.Retry.perTryTimeout
+			location.ProxyReadTimeout = cfg.Retry.perTryTimeout
+			location.ProxySendTimeout = cfg.Retry.perTryTimeout
+		}
+	}
+}
+
+// mergeRequestLimits merges the request limits of the VirtualServer into the request limits of a route.
+// The limits of the route take precedence.
+func mergeRequestLimits(spec *requestLimits, route *requestLimits) *requestLimits {
+	if spec == nil {
+		return route
+	}
+	if route == nil {
+		return spec
+	}
+
+	merged := *route
+	if merged.maxBodySize == "" {
+		merged.maxBodySize = spec.maxBodySize
+	}
+	if merged.bodyTimeout == "" {
+		merged.bodyTimeout = spec.bodyTimeout
+	}
+	if len(merged.allowedMethods) == 0 {
+		merged.allowedMethods = spec.allowedMethods
+	}
+	if merged.maxURILength == 0 {
+		merged.maxURILength = spec.maxURILength
+	}
+
+	return &merged
+}
+
+// addRequestLimitsToLocations applies the request limits to the locations of a route.
+// The client-max-body-size of an upstream takes precedence over the maxBodySize of the policy.
+func (vsc *virtualServerConfigurator) addRequestLimitsToLocations(
+	owner runtime.Object,
+	limits *requestLimits,
+	locations []version2.Location,
+	crUpstreams map[string]conf_v1.Upstream,
+) {
+	conflicts := make(map[string]bool)
+
+	for i := range locations {
+		loc := &locations[i]
+		loc.AllowedMethods = limits.allowedMethods
+		loc.MaxURILength = limits.maxURILength
+
+		if loc.ProxyPass == "" && loc.GRPCPass == "" {
+			continue
+		}
+		if limits.bodyTimeout != "" {
+			loc.ClientBodyTimeout = limits.bodyTimeout
+		}
+		if limits.maxBodySize == "" {
+			continue
+		}
+
+		upstreamName := getLocationUpstreamName(loc)
+		if ups, exists := crUpstreams[upstreamName]; exists && ups.ClientMaxBodySize != "" {
+			if ups.ClientMaxBodySize != limits.maxBodySize && !conflicts[ups.Name] {
+				vsc.addWarningf(owner, "The maxBodySize of RequestLimits policy %s is ignored for upstream %s, which sets client-max-body-size", limits.key, ups.Name)
+				conflicts[ups.Name] = true
+			}
+			continue
+		}
+		loc.ClientMaxBodySize = limits.maxBodySize
+	}
 }
 
 func addPoliciesCfgToLocations(cfg policiesCfg, locations []version2.Location) {
@@ -1903,6 +3443,12 @@ func addDosConfigToLocations(dosCfg *version2.Dos, locations []version2.Location
 	}
 }
 
+func addCompressionToLocations(compression []version2.Compression, locations []version2.Location) {
+	for i := range locations {
+		locations[i].Compression = compression
+	}
+}
+
 func getUpstreamResourceLabels(owner runtime.Object) version2.UpstreamLabels {
 	var resourceType, resourceName, resourceNamespace string
 
@@ -2075,6 +3621,48 @@ func generateHealthCheck(
 	return hc
 }
 
+// generateUpstreamHealthCheck generates the active health check of an upstream and its status match.
+// For an upstream without an active health check, the ejection of the circuit breaker policy
+// generates a health check that fails for the responses with a 5xx status code.
+func (vsc *virtualServerConfigurator) generateUpstreamHealthCheck(
+	owner runtime.Object,
+	upstream conf_v1.Upstream,
+	upstreamName string,
+	cb *conf_v1.CircuitBreaker,
+) (*version2.HealthCheck, *version2.StatusMatch) {
+	if hc := generateHealthCheck(upstream, upstreamName, vsc.cfgParams); hc != nil {
+		if cb != nil && cb.Ejection != nil {
+			vsc.addWarningf(owner, "The ejection of the circuit breaker policy is ignored for upstream %s with an active health check", upstream.Name)
+		}
+		if upstream.HealthCheck.StatusMatch == "" {
+			return hc, nil
+		}
+		statusMatch := generateUpstreamStatusMatch(upstreamName, upstream.HealthCheck.StatusMatch)
+		return hc, &statusMatch
+	}
+
+	if cb == nil || cb.Ejection == nil || !vsc.isPlus {
+		return nil, nil
+	}
+	if isGRPC(upstream.Type) {
+		vsc.addWarningf(owner, "The ejection of the circuit breaker policy is not supported for gRPC upstream %s", upstream.Name)
+		return nil, nil
+	}
+
+	hc := newHealthCheckWithDefaults(upstream, upstreamName, vsc.cfgParams)
+	hc.Fails = cb.Ejection.Consecutive5xx
+	if cb.Ejection.Interval != "" {
+		hc.Interval = generateTime(cb.Ejection.Interval)
+	}
+	if cb.Ejection.Path != "" {
+		hc.URI = cb.Ejection.Path
+	}
+	hc.Match = generateStatusMatchName(upstreamName)
+	statusMatch := generateUpstreamStatusMatch(upstreamName, "! 500-599")
+
+	return hc, &statusMatch
+}
+
 func generateSessionCookie(sc *conf_v1.SessionCookie) *version2.SessionCookie {
 	if sc == nil || !sc.Enable {
 		return nil
@@ -2108,6 +3696,47 @@ func GenerateExternalNameSvcKey(namespace string, service string) string {
 	return fmt.Sprintf("%v/%v", namespace, service)
 }
 
+// GetUpstreamNamespace returns the namespace of the service and the backup service of the upstream.
+func GetUpstreamNamespace(upstream conf_v1.Upstream, ownerNamespace string) string {
+	if upstream.ServiceNamespace != "" {
+		return upstream.ServiceNamespace
+	}
+	return ownerNamespace
+}
+
+// GeneratePolicySecretKey returns the key to identify a secret referenced by a Policy.
+// The secret is either referenced as <namespace>/<name> or as <name> in the namespace of the Policy.
+func GeneratePolicySecretKey(policyNamespace string, secret string) string {
+	if strings.Contains(secret, "/") {
+		return secret
+	}
+	return fmt.Sprintf("%v/%v", policyNamespace, secret)
+}
+
+// GenerateReferenceKey returns the key to identify a reference from resources of a kind in a namespace to a resource.
+func GenerateReferenceKey(fromKind string, fromNamespace string, toKind string, toKey string) string {
+	return fmt.Sprintf("%v/%v/%v/%v", fromKind, fromNamespace, toKind, toKey)
+}
+
+// getDeniedReference returns the error of the reference from the owner to a resource if the reference is not allowed.
+func getDeniedReference(deniedReferences map[string]error, owner runtime.Object, toKind string, toKey string) error {
+	var fromKind, fromNamespace string
+
+	switch owner := owner.(type) {
+	case *conf_v1.VirtualServer:
+		fromKind = conf_v1.ReferenceGrantKindVirtualServer
+		fromNamespace = owner.Namespace
+	case *conf_v1.VirtualServerRoute:
+		fromKind = conf_v1.ReferenceGrantKindVirtualServerRoute
+		fromNamespace = owner.Namespace
+	case *networking.Ingress:
+		fromKind = conf_v1.ReferenceGrantKindIngress
+		fromNamespace = owner.Namespace
+	}
+
+	return deniedReferences[GenerateReferenceKey(fromKind, fromNamespace, toKind, toKey)]
+}
+
 func generateLBMethod(method string, defaultMethod string) string {
 	if method == "" {
 		return defaultMethod
@@ -2149,7 +3778,7 @@ func generateRewrites(path string, proxy *conf_v1.ActionProxy, internal bool, or
 	}
 
 	trimmedPath := strings.TrimPrefix(strings.TrimPrefix(path, "~"), "*")
-	trimmedPath = strings.TrimSpace(trimmedPath)
+	trimmedPath = strings.TrimSpace(strings.TrimPrefix(trimmedPath, "="))
 
 	var rewrites []string
 
@@ -2163,15 +3792,64 @@ func generateRewrites(path string, proxy *conf_v1.ActionProxy, internal bool, or
 
 	if isRegex {
 		rewrites = append(rewrites, fmt.Sprintf(`"^%v" "%v" break`, trimmedPath, proxy.RewritePath))
-	} else if internal {
+	} else if internal || hasRewriteVariables(proxy.RewritePath) {
 		rewrites = append(rewrites, fmt.Sprintf(`"^%v(.*)$" "%v$1" break`, trimmedPath, proxy.RewritePath))
 	}
 
 	return rewrites
 }
 
+// hasRewriteVariables checks if a rewrite path contains variables enclosed in curly braces.
+// NGINX passes the URI of a proxy_pass with variables as is, so such a rewrite path requires the rewrite directive.
+func hasRewriteVariables(rewritePath string) bool {
+	return strings.Contains(rewritePath, "${")
+}
+
+func hasArgsRewrites(proxy *conf_v1.ActionProxy) bool {
+	return proxy != nil && proxy.RewriteArgs != nil && (len(proxy.RewriteArgs.Set) > 0 || len(proxy.RewriteArgs.Remove) > 0)
+}
+
+// generateRemovedArgs generates the names of the arguments removed from the requests: the arguments to remove
+// and the arguments to set. Every occurrence of the arguments is removed, so that a client can't pass its own value
+// of an argument to set.
+func generateRemovedArgs(proxy *conf_v1.ActionProxy) string {
+	if !hasArgsRewrites(proxy) {
+		return ""
+	}
+
+	names := slices.Clone(proxy.RewriteArgs.Remove)
+	for _, a := range proxy.RewriteArgs.Set {
+		names = append(names, a.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+// generateArgsRewrites generates the changes of the arguments of the requests after the removal of the arguments
+// generated by generateRemovedArgs. The arguments to set are appended to the arguments.
+// The last change removes the leading and trailing ampersands.
+func generateArgsRewrites(proxy *conf_v1.ActionProxy) []version2.ArgsRewrite {
+	if !hasArgsRewrites(proxy) {
+		return nil
+	}
+
+	var rewrites []version2.ArgsRewrite
+
+	for _, a := range proxy.RewriteArgs.Set {
+		rewrites = append(rewrites, version2.ArgsRewrite{
+			Value: fmt.Sprintf("${args}&%s=%s", a.Name, a.Value),
+		})
+	}
+
+	rewrites = append(rewrites, version2.ArgsRewrite{
+		Regex: "^&*(.*?)&*$",
+		Value: "$1",
+	})
+
+	return rewrites
+}
+
 func generateProxyPassRewrite(path string, proxy *conf_v1.ActionProxy, internal bool) string {
-	if proxy == nil || internal {
+	if proxy == nil || internal || hasRewriteVariables(proxy.RewritePath) {
 		return ""
 	}
 
@@ -2185,6 +3863,11 @@ func generateProxyPassRewrite(path string, proxy *conf_v1.ActionProxy, internal
 func generateProxyPass(tlsEnabled bool, upstreamName string, internal bool, proxy *conf_v1.ActionProxy) string {
 	proxyPass := fmt.Sprintf("%v://%v", generateProxyPassProtocol(tlsEnabled), upstreamName)
 
+	if hasArgsRewrites(proxy) && proxy.RewritePath == "" {
+		// without a rewrite, NGINX passes the original arguments of the request, so the changed arguments are passed explicitly
+		return fmt.Sprintf("%v$request_uri_no_args$is_args$args", proxyPass)
+	}
+
 	if internal && (proxy == nil || proxy.RewritePath == "") {
 		return fmt.Sprintf("%v$request_uri", proxyPass)
 	}
@@ -2306,6 +3989,10 @@ func generateLocation(path string, upstreamName string, upstream conf_v1.Upstrea
 		return generateLocationForReturn(path, cfgParams.LocationSnippets, action.Return, retLocIndex)
 	}
 
+	if action.Static != nil {
+		return generateLocationForStatic(path, locationSnippets, action.Static, errorPages), nil
+	}
+
 	checkGrpcErrorPageCodes(errorPages, isGRPC(upstream.Type), upstream.Name, vscWarnings)
 
 	return generateLocationForProxying(path, upstreamName, upstream, cfgParams, errorPages.pages, internal,
@@ -2316,6 +4003,7 @@ func generateProxySetHeaders(proxy *conf_v1.ActionProxy) []version2.Header {
 	var headers []version2.Header
 
 	hasHostHeader := false
+	hasAcceptEncodingHeader := false
 
 	if proxy != nil && proxy.RequestHeaders != nil {
 		for _, h := range proxy.RequestHeaders.Set {
@@ -2324,8 +4012,11 @@ func generateProxySetHeaders(proxy *conf_v1.ActionProxy) []version2.Header {
 				Value: h.Value,
 			})
 
-			if strings.ToLower(h.Name) == "host" {
+			switch strings.ToLower(h.Name) {
+			case "host":
 				hasHostHeader = true
+			case "accept-encoding":
+				hasAcceptEncodingHeader = true
 			}
 		}
 	}
@@ -2334,9 +4025,54 @@ func generateProxySetHeaders(proxy *conf_v1.ActionProxy) []version2.Header {
 		headers = append(headers, version2.Header{Name: "Host", Value: "$host"})
 	}
 
+	// sub_filter can't rewrite compressed responses, so we don't let the upstream compress them
+	if proxy != nil && len(proxy.ResponseBodyRewrite) > 0 && !hasAcceptEncodingHeader {
+		headers = append(headers, version2.Header{Name: "Accept-Encoding", Value: ""})
+	}
+
 	return headers
 }
 
+func generateSubFilters(proxy *conf_v1.ActionProxy) []version2.SubFilter {
+	if proxy == nil {
+		return nil
+	}
+
+	var filters []version2.SubFilter
+	for _, r := range proxy.ResponseBodyRewrite {
+		filters = append(filters, version2.SubFilter{
+			Match:       r.Match,
+			Replacement: r.Replacement,
+		})
+	}
+	return filters
+}
+
+func generateSubFilterOnce(proxy *conf_v1.ActionProxy) bool {
+	if proxy == nil || len(proxy.ResponseBodyRewrite) == 0 {
+		return false
+	}
+	return proxy.ResponseBodyRewrite[0].Once
+}
+
+func generateSubFilterTypes(proxy *conf_v1.ActionProxy) []string {
+	if proxy == nil {
+		return nil
+	}
+
+	var types []string
+	seen := make(map[string]bool)
+	for _, r := range proxy.ResponseBodyRewrite {
+		for _, t := range r.Types {
+			if !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+	}
+	return types
+}
+
 func generateProxyPassRequestHeaders(proxy *conf_v1.ActionProxy) bool {
 	if proxy == nil || proxy.RequestHeaders == nil {
 		return true
@@ -2419,8 +4155,13 @@ func generateLocationForProxying(path string, upstreamName string, upstream conf
 		ProxyPassHeaders:         generateProxyPassHeaders(proxy),
 		ProxyIgnoreHeaders:       generateProxyIgnoreHeaders(proxy),
 		AddHeaders:               generateProxyAddHeaders(proxy),
+		SubFilters:               generateSubFilters(proxy),
+		SubFilterOnce:            generateSubFilterOnce(proxy),
+		SubFilterTypes:           generateSubFilterTypes(proxy),
 		ProxyPassRewrite:         generateProxyPassRewrite(path, proxy, internal),
 		Rewrites:                 generateRewrites(path, proxy, internal, originalPath, isGRPC(upstream.Type)),
+		RemovedArgs:              generateRemovedArgs(proxy),
+		ArgsRewrites:             generateArgsRewrites(proxy),
 		HasKeepalive:             upstreamHasKeepalive(upstream, cfgParams),
 		ErrorPages:               generateErrorPages(errPageIndex, errorPages),
 		ProxySSLName:             proxySSLName,
@@ -2461,6 +4202,131 @@ func generateLocationForRedirect(
 	}
 }
 
+// staticDir is the directory under which the static actions serve the files.
+const staticDir = "/etc/nginx/static"
+
+func generateLocationForStatic(path string, locationSnippets []string, static *conf_v1.ActionStatic,
+	errorPages errorPageDetails,
+) version2.Location {
+	dir := fmt.Sprintf("%s/%s", staticDir, static.Path)
+
+	staticCfg := &version2.Static{
+		Index:    static.Index,
+		TryFiles: static.TryFiles,
+		Expires:  static.Expires,
+	}
+	// NGINX replaces a prefix path with the directory, so that the files are located by the rest of the URI.
+	// The directory gets a trailing slash only with the path, so that /path/file maps to dir/file for both /path and /path/.
+	// For a path without a trailing slash, the URIs like /path../file and /path-suffix are not served,
+	// as NGINX would map them to the files outside of the directory.
+	if strings.HasPrefix(path, "/") {
+		staticCfg.Alias = dir
+		if strings.HasSuffix(path, "/") {
+			staticCfg.Alias += "/"
+		}
+	} else {
+		staticCfg.Root = dir
+	}
+
+	return version2.Location{
+		Path:       generatePath(path),
+		Snippets:   locationSnippets,
+		ErrorPages: generateErrorPages(errorPages.index, errorPages.pages),
+		URIPattern: generateURIPatternForPrefix(path),
+		Static:     staticCfg,
+	}
+}
+
+// generateURIPatternForPrefix returns the pattern of the URIs that are the prefix path or start with the path
+// followed by a slash. It is empty for the paths that end with a slash, and for the exact and regex paths.
+func generateURIPatternForPrefix(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+		return ""
+	}
+	return fmt.Sprintf("^%s(/|$)", quotePathForRegex(path))
+}
+
+// quotePathForRegex escapes the regex metacharacters of a path and the double quotes,
+// so that the path can be used in a quoted regex of the NGINX configuration.
+func quotePathForRegex(path string) string {
+	return strings.ReplaceAll(regexp.QuoteMeta(path), `"`, `\"`)
+}
+
+// objectStorageDefaultRegion is the region used for signing the requests when the objectStorage action doesn't set it.
+const objectStorageDefaultRegion = "us-east-1"
+
+// addObjectStorageToLocation configures the location of an objectStorage action to pass the requests to the bucket
+// with the signature of the access keys of the Secret. The signature is computed by the object_storage.js njs module.
+func (vsc *virtualServerConfigurator) addObjectStorageToLocation(
+	owner runtime.Object,
+	loc *version2.Location,
+	path string,
+	objectStorage *conf_v1.ActionObjectStorage,
+	namespace string,
+	proxySSLName string,
+	secretRefs map[string]*secrets.SecretReference,
+) {
+	secretKey := fmt.Sprintf("%v/%v", namespace, objectStorage.Secret)
+	secretRef := secretRefs[secretKey]
+	if secretRef == nil || secretRef.Secret == nil {
+		vsc.addWarningf(owner, "ObjectStorage action of the route %s references a secret %s that doesn't exist", path, secretKey)
+		loc.PoliciesErrorReturn = &version2.Return{Code: 500}
+		return
+	}
+	if secretRef.Secret.Type != secrets.SecretTypeObjectStorage {
+		vsc.addWarningf(owner, "ObjectStorage action of the route %s references a secret %s of a wrong type '%s', must be '%s'",
+			path, secretKey, secretRef.Secret.Type, secrets.SecretTypeObjectStorage)
+		loc.PoliciesErrorReturn = &version2.Return{Code: 500}
+		return
+	}
+	if secretRef.Error != nil {
+		vsc.addWarningf(owner, "ObjectStorage action of the route %s references an invalid secret %s: %v", path, secretKey, secretRef.Error)
+		loc.PoliciesErrorReturn = &version2.Return{Code: 500}
+		return
+	}
+
+	host := objectStorage.Host
+	if host == "" {
+		host = proxySSLName
+	}
+	region := objectStorage.Region
+	if region == "" {
+		region = objectStorageDefaultRegion
+	}
+
+	loc.ObjectStorage = &version2.ObjectStorage{
+		Host:            host,
+		Bucket:          objectStorage.Bucket,
+		Region:          region,
+		AccessKeyID:     string(secretRef.Secret.Data[secrets.AccessKeyIDKey]),
+		SecretAccessKey: string(secretRef.Secret.Data[secrets.SecretAccessKeyKey]),
+	}
+
+	// the headers of the clients are not passed, because the object storage rejects the requests
+	// with unsigned x-amz-* headers. The headers for the conditional and range requests are passed explicitly.
+	loc.ProxyPassRequestHeaders = false
+	loc.ProxySetHeaders = []version2.Header{
+		{Name: "Host", Value: "$object_storage_host"},
+		{Name: "x-amz-date", Value: "$object_storage_date"},
+		{Name: "x-amz-content-sha256", Value: "UNSIGNED-PAYLOAD"},
+		{Name: "Authorization", Value: "$object_storage_authorization"},
+		{Name: "Range", Value: "$http_range"},
+		{Name: "If-Match", Value: "$http_if_match"},
+		{Name: "If-None-Match", Value: "$http_if_none_match"},
+		{Name: "If-Modified-Since", Value: "$http_if_modified_since"},
+		{Name: "If-Unmodified-Since", Value: "$http_if_unmodified_since"},
+	}
+	loc.ProxyHideHeaders = []string{"x-amz-id-2", "x-amz-request-id"}
+	loc.ProxyPassRewrite = "$object_storage_uri"
+
+	// the key of the object is the rest of the URI for a prefix path and the whole URI otherwise.
+	// The URIs that only start with the characters of a path without a trailing slash, like /path../key, are not passed.
+	if strings.HasPrefix(path, "/") && path != "/" {
+		loc.Rewrites = []string{fmt.Sprintf(`"^%v(?:/(.*))?$" "/$1" break`, quotePathForRegex(strings.TrimSuffix(path, "/")))}
+		loc.URIPattern = generateURIPatternForPrefix(path)
+	}
+}
+
 func generateLocationForReturn(path string, locationSnippets []string, actionReturn *conf_v1.ActionReturn,
 	retLocIndex int,
 ) (version2.Location, *version2.ReturnLocation) {
@@ -2595,7 +4461,7 @@ func generateSplits(
 		path := fmt.Sprintf("/%vsplits_%d_split_%d", internalLocationPrefix, scIndex, i)
 		upstreamName := upstreamNamer.GetNameForUpstreamFromAction(s.Action)
 		upstream := crUpstreams[upstreamName]
-		proxySSLName := generateProxySSLName(upstream.Service, upstreamNamer.namespace)
+		proxySSLName := generateProxySSLName(upstream.Service, GetUpstreamNamespace(upstream, upstreamNamer.namespace))
 		newRetLocIndex := retLocIndex + len(returnLocations)
 		loc, returnLoc := generateLocation(path, upstreamName, upstream, s.Action, cfgParams, errorPages, true,
 			proxySSLName, originalPath, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
@@ -2626,6 +4492,10 @@ func generateDefaultSplitsConfig(
 	vscWarnings Warnings,
 	weightChangesDynamicReload bool,
 ) routingCfg {
+	if route.Canary != nil {
+		route.Splits = generateCanarySplits(route.Action, route.Canary)
+	}
+
 	scs, locs, returnLocs, maps, keyValZones, keyVals, twoWaySplitClients := generateSplits(route.Splits, upstreamNamer, crUpstreams, VariableNamer, scIndex, cfgParams, errorPages, originalPath, locSnippets, enableSnippets, retLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings, weightChangesDynamicReload)
 
 	var irl version2.InternalRedirectLocation
@@ -2641,6 +4511,25 @@ func generateDefaultSplitsConfig(
 		}
 	}
 
+	if route.Canary != nil {
+		canaryMaps := generateCanaryMaps(route.Canary, scIndex, irl.Destination, VariableNamer)
+		if len(canaryMaps) > 0 {
+			irl.Destination = VariableNamer.GetNameForCanaryVariable(scIndex)
+			maps = append(maps, canaryMaps...)
+		}
+		if route.Canary.StickyCookie != nil {
+			for i := range locs {
+				locs[i].AddHeaders = append(locs[i].AddHeaders, version2.AddHeader{
+					Header: version2.Header{
+						Name:  "Set-Cookie",
+						Value: VariableNamer.GetNameForCanaryStickyCookieVariable(scIndex, i),
+					},
+					Always: true,
+				})
+			}
+		}
+	}
+
 	return routingCfg{
 		SplitClients:             scs,
 		Locations:                locs,
@@ -2653,6 +4542,128 @@ func generateDefaultSplitsConfig(
 	}
 }
 
+// Values of the force header and cookie of a canary release.
+const (
+	canaryForceAlways = "always"
+	canaryForceNever  = "never"
+)
+
+// Values of the sticky cookie of a canary release for the action of the route and the action of the canary.
+var canaryStickyCookieValues = []string{"stable", "canary"}
+
+// generateCanarySplits returns the two splits of a canary release: the action of the route and the action of the canary.
+func generateCanarySplits(action *conf_v1.Action, canary *conf_v1.Canary) []conf_v1.Split {
+	return []conf_v1.Split{
+		{Weight: 100 - canary.Weight, Action: action},
+		{Weight: canary.Weight, Action: canary.Action},
+	}
+}
+
+// generateCanaryMaps generates the maps of a canary release. The first map chooses the split location for a request:
+// the force header and the force cookie take precedence over the sticky cookie, and the requests without any of them
+// go to the destination of the weighted split clients. The other maps produce the sticky cookie of each split
+// for the requests assigned by the weights.
+func generateCanaryMaps(canary *conf_v1.Canary, scIndex int, splitsDestination string, VariableNamer *VariableNamer) []version2.Map {
+	var forceHeader, forceCookie, stickyCookie string
+	if canary.ForceHeader != "" {
+		forceHeader = fmt.Sprintf("$http_%s", strings.ReplaceAll(strings.ToLower(canary.ForceHeader), "-", "_"))
+	}
+	if canary.ForceCookie != "" {
+		forceCookie = fmt.Sprintf("$cookie_%s", canary.ForceCookie)
+	}
+	if canary.StickyCookie != nil {
+		stickyCookie = fmt.Sprintf("$cookie_%s", canary.StickyCookie.Name)
+	}
+	if forceHeader == "" && forceCookie == "" && stickyCookie == "" {
+		return nil
+	}
+
+	source := fmt.Sprintf(`"%s|%s|%s"`, forceHeader, forceCookie, stickyCookie)
+	splitLocation := func(i int) string {
+		return fmt.Sprintf("/%vsplits_%d_split_%d", internalLocationPrefix, scIndex, i)
+	}
+
+	// the values of the parts of the source are separated by |
+	var forcePatterns []string
+	if forceHeader != "" {
+		forcePatterns = append(forcePatterns, `"~^%s\|"`)
+	}
+	if forceCookie != "" {
+		forcePatterns = append(forcePatterns, `"~^[^|]*\|%s\|"`)
+	}
+
+	var params []version2.Parameter
+	for _, pattern := range forcePatterns {
+		params = append(params,
+			version2.Parameter{Value: fmt.Sprintf(pattern, canaryForceAlways), Result: splitLocation(1)},
+			version2.Parameter{Value: fmt.Sprintf(pattern, canaryForceNever), Result: splitLocation(0)},
+		)
+	}
+	if stickyCookie != "" {
+		for i, value := range canaryStickyCookieValues {
+			params = append(params, version2.Parameter{Value: fmt.Sprintf(`"~\|%s$"`, value), Result: splitLocation(i)})
+		}
+	}
+	params = append(params, version2.Parameter{Value: "default", Result: splitsDestination})
+
+	maps := []version2.Map{
+		{
+			Source:     source,
+			Variable:   VariableNamer.GetNameForCanaryVariable(scIndex),
+			Parameters: params,
+		},
+	}
+
+	if canary.StickyCookie == nil {
+		return maps
+	}
+
+	// the sticky cookie is only set for the requests assigned by the weights
+	var skipParams []version2.Parameter
+	for _, pattern := range forcePatterns {
+		skipParams = append(skipParams, version2.Parameter{
+			Value:  fmt.Sprintf(pattern, fmt.Sprintf("(%s|%s)", canaryForceAlways, canaryForceNever)),
+			Result: `""`,
+		})
+	}
+	skipParams = append(skipParams, version2.Parameter{
+		Value:  fmt.Sprintf(`"~\|(%s)$"`, strings.Join(canaryStickyCookieValues, "|")),
+		Result: `""`,
+	})
+
+	for i, value := range canaryStickyCookieValues {
+		cookieParams := append([]version2.Parameter{}, skipParams...)
+		cookieParams = append(cookieParams, version2.Parameter{
+			Value:  "default",
+			Result: fmt.Sprintf(`"%s"`, generateCanaryStickyCookie(canary.StickyCookie, value)),
+		})
+		maps = append(maps, version2.Map{
+			Source:     source,
+			Variable:   VariableNamer.GetNameForCanaryStickyCookieVariable(scIndex, i),
+			Parameters: cookieParams,
+		})
+	}
+
+	return maps
+}
+
+func generateCanaryStickyCookie(sc *conf_v1.CanaryStickyCookie, value string) string {
+	path := sc.Path
+	if path == "" {
+		path = "/"
+	}
+	cookie := fmt.Sprintf("%s=%s; Path=%s", sc.Name, value, path)
+
+	if sc.Expires != "" {
+		// the expires is validated by the VirtualServer validator
+		if maxAge, err := ParseTimeToSeconds(sc.Expires); err == nil {
+			cookie += fmt.Sprintf("; Max-Age=%d", maxAge)
+		}
+	}
+
+	return cookie
+}
+
 func generateSplitsForWeightChangesDynamicReload(splits []conf_v1.Split, scIndex int, VariableNamer *VariableNamer) ([]version2.SplitClient, version2.Map) {
 	var splitClients []version2.SplitClient
 	var mapParameters []version2.Parameter
@@ -2828,7 +4839,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 			path := fmt.Sprintf("/%vmatches_%d_match_%d", internalLocationPrefix, index, i)
 			upstreamName := upstreamNamer.GetNameForUpstreamFromAction(m.Action)
 			upstream := crUpstreams[upstreamName]
-			proxySSLName := generateProxySSLName(upstream.Service, upstreamNamer.namespace)
+			proxySSLName := generateProxySSLName(upstream.Service, GetUpstreamNamespace(upstream, upstreamNamer.namespace))
 			newRetLocIndex := retLocIndex + len(returnLocations)
 			loc, returnLoc := generateLocation(path, upstreamName, upstream, m.Action, cfgParams, errorPages, true,
 				proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
@@ -2871,7 +4882,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 		path := fmt.Sprintf("/%vmatches_%d_default", internalLocationPrefix, index)
 		upstreamName := upstreamNamer.GetNameForUpstreamFromAction(route.Action)
 		upstream := crUpstreams[upstreamName]
-		proxySSLName := generateProxySSLName(upstream.Service, upstreamNamer.namespace)
+		proxySSLName := generateProxySSLName(upstream.Service, GetUpstreamNamespace(upstream, upstreamNamer.namespace))
 		newRetLocIndex := retLocIndex + len(returnLocations)
 		loc, returnLoc := generateLocation(path, upstreamName, upstream, route.Action, cfgParams, errorPages, true,
 			proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
@@ -2998,6 +5009,9 @@ func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tl
 		vsc.addWarningf(owner, "TLS secret %s is invalid: %v", tls.Secret, secretRef.Error)
 	} else {
 		name = secretRef.Path
+		if expired, notAfter := secrets.IsCertificateExpired(secretRef.Secret, vsc.now); expired {
+			vsc.addWarningf(owner, "TLS secret %s holds a certificate that expired on %s", tls.Secret, notAfter.Format(time.RFC3339))
+		}
 	}
 
 	ssl := version2.SSL{
@@ -3051,58 +5065,64 @@ func createUpstreamsForPlus(
 	isPlus := true
 	upstreamNamer := NewUpstreamNamerForVirtualServer(virtualServerEx.VirtualServer)
 	vsc := newVirtualServerConfigurator(baseCfgParams, isPlus, false, staticParams, false, nil)
+	cbUpstreams := getCircuitBreakerUpstreams(virtualServerEx)
 
 	for _, u := range virtualServerEx.VirtualServer.Spec.Upstreams {
-		isExternalNameSvc := virtualServerEx.ExternalNameSvcs[GenerateExternalNameSvcKey(virtualServerEx.VirtualServer.Namespace, u.Service)]
+		upstreamNamespace := GetUpstreamNamespace(u, virtualServerEx.VirtualServer.Namespace)
+		isExternalNameSvc := virtualServerEx.ExternalNameSvcs[GenerateExternalNameSvcKey(upstreamNamespace, u.Service)]
 		if isExternalNameSvc {
 			nl.Debugf(l, "Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", u.Service)
 			continue
 		}
 
 		upstreamName := upstreamNamer.GetNameForUpstream(u.Name)
-		upstreamNamespace := virtualServerEx.VirtualServer.Namespace
-
-		endpointsKey := GenerateEndpointsKey(upstreamNamespace, u.Service, u.Subselector, u.Port)
-		endpoints := virtualServerEx.Endpoints[endpointsKey]
-
-		backupEndpoints := []string{}
-		if u.Backup != "" {
-			backupEndpointsKey := GenerateEndpointsKey(upstreamNamespace, u.Backup, u.Subselector, *u.BackupPort)
-			backupEndpoints = virtualServerEx.Endpoints[backupEndpointsKey]
-		}
+		endpoints, backupEndpoints := getEndpointsForUpstreamForPlus(virtualServerEx, virtualServerEx.VirtualServer, upstreamNamespace, u)
 		ups := vsc.generateUpstream(virtualServerEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints)
-		upstreams = append(upstreams, ups)
+		for _, cbUps := range generateCircuitBreakerUpstreams(ups, cbUpstreams[upstreamName], virtualServerEx.Policies) {
+			upstreams = append(upstreams, cbUps.upstream)
+		}
 	}
 
 	for _, vsr := range virtualServerEx.VirtualServerRoutes {
 		upstreamNamer = NewUpstreamNamerForVirtualServerRoute(virtualServerEx.VirtualServer, vsr)
 		for _, u := range vsr.Spec.Upstreams {
-			isExternalNameSvc := virtualServerEx.ExternalNameSvcs[GenerateExternalNameSvcKey(vsr.Namespace, u.Service)]
+			upstreamNamespace := GetUpstreamNamespace(u, vsr.Namespace)
+			isExternalNameSvc := virtualServerEx.ExternalNameSvcs[GenerateExternalNameSvcKey(upstreamNamespace, u.Service)]
 			if isExternalNameSvc {
 				nl.Debugf(l, "Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", u.Service)
 				continue
 			}
 
 			upstreamName := upstreamNamer.GetNameForUpstream(u.Name)
-			upstreamNamespace := vsr.Namespace
-
-			endpointsKey := GenerateEndpointsKey(upstreamNamespace, u.Service, u.Subselector, u.Port)
-			endpoints := virtualServerEx.Endpoints[endpointsKey]
-
-			// BackupService
-			backupEndpoints := []string{}
-			if u.Backup != "" {
-				backupEndpointsKey := GenerateEndpointsKey(upstreamNamespace, u.Backup, u.Subselector, *u.BackupPort)
-				backupEndpoints = virtualServerEx.Endpoints[backupEndpointsKey]
-			}
+			endpoints, backupEndpoints := getEndpointsForUpstreamForPlus(virtualServerEx, vsr, upstreamNamespace, u)
 			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints)
-			upstreams = append(upstreams, ups)
+			for _, cbUps := range generateCircuitBreakerUpstreams(ups, cbUpstreams[upstreamName], virtualServerEx.Policies) {
+				upstreams = append(upstreams, cbUps.upstream)
+			}
 		}
 	}
 
 	return upstreams
 }
 
+// getEndpointsForUpstreamForPlus returns the endpoints of the service and the backup service of the upstream.
+// Services that the owner is not allowed to reference get no endpoints.
+func getEndpointsForUpstreamForPlus(virtualServerEx *VirtualServerEx, owner runtime.Object, namespace string, u conf_v1.Upstream) ([]string, []string) {
+	var endpoints []string
+	if getDeniedReference(virtualServerEx.DeniedReferences, owner, conf_v1.ReferenceGrantKindService, GenerateExternalNameSvcKey(namespace, u.Service)) == nil {
+		endpointsKey := GenerateEndpointsKey(namespace, u.Service, u.Subselector, u.Port)
+		endpoints = virtualServerEx.Endpoints[endpointsKey]
+	}
+
+	backupEndpoints := []string{}
+	if u.Backup != "" && getDeniedReference(virtualServerEx.DeniedReferences, owner, conf_v1.ReferenceGrantKindService, GenerateExternalNameSvcKey(namespace, u.Backup)) == nil {
+		backupEndpointsKey := GenerateEndpointsKey(namespace, u.Backup, u.Subselector, *u.BackupPort)
+		backupEndpoints = virtualServerEx.Endpoints[backupEndpointsKey]
+	}
+
+	return endpoints, backupEndpoints
+}
+
 func createUpstreamServersConfigForPlus(upstream version2.Upstream) nginx.ServerConfig {
 	if len(upstream.Servers) == 0 {
 		return nginx.ServerConfig{}
@@ -3241,6 +5261,72 @@ func isGRPC(protocolType string) bool {
 	return protocolType == "grpc"
 }
 
+// compressionEncodings maps the encodings of the compressed responses to the modules that produce them.
+var compressionEncodings = []struct {
+	encoding string
+	module   string
+}{
+	{encoding: "gzip", module: "gzip"},
+	{encoding: "br", module: "brotli"},
+	{encoding: "zstd", module: "zstd"},
+}
+
+// generateCompression generates the compression of responses of a server or a location.
+// Precompressed files are sent only by the modules in staticModules.
+// For a location, the compression of the VirtualServer is passed as vsCompression: the encodings that are enabled
+// for the VirtualServer but not for the location are turned off in the location.
+func generateCompression(compression *conf_v1.Compression, vsCompression *conf_v1.Compression, staticModules map[string]bool) []version2.Compression {
+	if compression == nil {
+		return nil
+	}
+
+	enabled := enabledCompressionEncodings(compression)
+	vsEnabled := enabledCompressionEncodings(vsCompression)
+
+	var minLength string
+	if compression.MinLength != nil {
+		minLength = strconv.Itoa(*compression.MinLength)
+	}
+	var level string
+	if compression.Level != nil {
+		level = strconv.Itoa(*compression.Level)
+	}
+
+	var cfg []version2.Compression
+	for _, e := range compressionEncodings {
+		switch {
+		case enabled[e.encoding]:
+			cfg = append(cfg, version2.Compression{
+				Module:    e.module,
+				Enable:    true,
+				Types:     compression.Types,
+				MinLength: minLength,
+				Level:     level,
+				Static:    compression.Static && staticModules[e.module],
+			})
+		case e.encoding == "gzip" || vsEnabled[e.encoding]:
+			cfg = append(cfg, version2.Compression{
+				Module: e.module,
+			})
+		}
+	}
+	return cfg
+}
+
+func enabledCompressionEncodings(compression *conf_v1.Compression) map[string]bool {
+	enabled := make(map[string]bool)
+	if compression == nil || !compression.Enable {
+		return enabled
+	}
+	if len(compression.Encodings) == 0 {
+		enabled["gzip"] = true
+	}
+	for _, e := range compression.Encodings {
+		enabled[e] = true
+	}
+	return enabled
+}
+
 func generateDosCfg(dosResource *appProtectDosResource) *version2.Dos {
 	if dosResource == nil {
 		return nil
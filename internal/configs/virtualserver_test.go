@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
@@ -8019,6 +8022,40 @@ func TestGeneratePolicies(t *testing.T) {
 					},
 				},
 			},
+			"default/api-key-secret-metadata": {
+				Secret: &api_v1.Secret{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Annotations: map[string]string{
+							secrets.APIKeyClientMetadataAnnotation: "true",
+						},
+					},
+					Type: secrets.SecretTypeAPIKey,
+					Data: map[string][]byte{
+						"client1":         []byte("password"),
+						"client1.expires": []byte("2030-01-01T00:00:00Z"),
+						"client1.tier":    []byte("gold"),
+						"client2":         []byte("password2"),
+						"client2.enabled": []byte("false"),
+					},
+				},
+			},
+			"default/introspection-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeOAuth2Introspection,
+					Data: map[string][]byte{
+						"client-id":     []byte("nic"),
+						"client-secret": []byte("secret"),
+					},
+				},
+			},
+			"default/webhook-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeHMAC,
+					Data: map[string][]byte{
+						"hmac-key": []byte("secret"),
+					},
+				},
+			},
 		},
 		apResources: &appProtectResourcesForVS{
 			Policies: map[string]string{
@@ -8597,7 +8634,26 @@ func TestGeneratePolicies(t *testing.T) {
 				},
 			},
 			expected: policiesCfg{
-				OIDC: true,
+				OIDC: &version2.OIDC{
+					Key:                   "default_test_default_oidc_policy",
+					AuthEndpoint:          "http://example.com/auth",
+					TokenEndpoint:         "http://example.com/token",
+					JwksURI:               "http://example.com/jwks",
+					EndSessionEndpoint:    "http://example.com/logout",
+					ClientID:              "client-id",
+					ClientSecret:          "super_secret_123",
+					Scope:                 "scope",
+					RedirectURI:           "/redirect",
+					LogoutURI:             "/logout",
+					PostLogoutRedirectURI: "/_logout",
+					ZoneSyncLeeway:        20,
+					AccessTokenEnable:     true,
+					HMACKey:               "test",
+					CookieName:            "auth_token",
+					CookieSameSite:        "lax",
+					SessionTimeout:        "1h",
+					RefreshTimeout:        "8h",
+				},
 			},
 			msg: "oidc reference",
 		},
@@ -8687,6 +8743,368 @@ func TestGeneratePolicies(t *testing.T) {
 			},
 			msg: "api key same secrets for different policies",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "introspection-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/introspection-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "introspection-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						OAuth2Introspection: &conf_v1.OAuth2Introspection{
+							Endpoint:     "https://idp.example.com:8443/oauth2/introspect",
+							ClientSecret: "introspection-secret",
+							Scopes:       []string{"read", "write"},
+							Audiences:    []string{"cafe"},
+							Headers: []conf_v1.OAuth2IntrospectionHeader{
+								{
+									Name:  "X-User",
+									Field: "username",
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				OAuth2Introspection: &version2.OAuth2Introspection{
+					Key:            "default_introspection_policy_default_test",
+					EndpointScheme: "https",
+					EndpointHost:   "idp.example.com",
+					EndpointPort:   "8443",
+					EndpointPath:   "/oauth2/introspect",
+					Authorization:  "Basic bmljOnNlY3JldA==",
+					Scopes:         "read write",
+					Audiences:      "cafe",
+					CacheTimeout:   "1m",
+					Headers: []version2.OAuth2IntrospectionHeader{
+						{
+							Name:  "X-User",
+							Field: "username",
+						},
+					},
+				},
+			},
+			msg: "oauth2 introspection reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "introspection-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "api-key-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/introspection-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "introspection-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						OAuth2Introspection: &conf_v1.OAuth2Introspection{
+							Endpoint:     "https://idp.example.com:8443/oauth2/introspect",
+							ClientSecret: "introspection-secret",
+						},
+					},
+				},
+				"default/api-key-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "api-key-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						APIKey: &conf_v1.APIKey{
+							SuppliedIn: &conf_v1.SuppliedIn{
+								Header: []string{"X-API-Key"},
+							},
+							ClientSecret: "api-key-secret",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				OAuth2Introspection: &version2.OAuth2Introspection{
+					Key:            "default_introspection_policy_default_test",
+					EndpointScheme: "https",
+					EndpointHost:   "idp.example.com",
+					EndpointPort:   "8443",
+					EndpointPath:   "/oauth2/introspect",
+					Authorization:  "Basic bmljOnNlY3JldA==",
+					CacheTimeout:   "1m",
+				},
+				APIKey: apiKeyAuth{
+					Key: &version2.APIKey{
+						Header:  []string{"X-API-Key"},
+						MapName: "apikey_auth_client_name_default_test_api_key_policy",
+					},
+					Enabled: true,
+					Clients: []apiKeyClient{
+						{
+							ClientID:  "client1",
+							HashedKey: "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8",
+						},
+					},
+				},
+			},
+			msg: "oauth2 introspection with api key",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "signature-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/signature-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "signature-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						SignatureVerification: &conf_v1.SignatureVerification{
+							Header:          "X-Hub-Signature-256",
+							Prefix:          "sha256=",
+							Secret:          "webhook-secret",
+							TimestampHeader: "X-Timestamp",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				SignatureVerification: &version2.SignatureVerification{
+					Header:             "X-Hub-Signature-256",
+					Algorithm:          "sha256",
+					Encoding:           "hex",
+					Prefix:             "sha256=",
+					Key:                "c2VjcmV0",
+					TimestampHeader:    "X-Timestamp",
+					TimestampTolerance: 300,
+					SignedPayload:      "{timestamp}.{body}",
+				},
+			},
+			msg: "signature verification reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "signature-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/signature-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "signature-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						SignatureVerification: &conf_v1.SignatureVerification{
+							Header:          "X-Slack-Signature",
+							Prefix:          "v0=",
+							Secret:          "webhook-secret",
+							TimestampHeader: "X-Slack-Request-Timestamp",
+							SignedPayload:   "v0:{timestamp}:{body}",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				SignatureVerification: &version2.SignatureVerification{
+					Header:             "X-Slack-Signature",
+					Algorithm:          "sha256",
+					Encoding:           "hex",
+					Prefix:             "v0=",
+					Key:                "c2VjcmV0",
+					TimestampHeader:    "X-Slack-Request-Timestamp",
+					TimestampTolerance: 300,
+					SignedPayload:      "v0:{timestamp}:{body}",
+				},
+			},
+			msg: "signature verification with signed payload",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "challenge-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/challenge-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "challenge-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						Challenge: &conf_v1.Challenge{
+							Secret:           "webhook-secret",
+							ExemptCIDRs:      []string{"10.0.0.0/8"},
+							ExemptUserAgents: []string{"Googlebot"},
+							Block: &conf_v1.ChallengeBlock{
+								Threshold: 10,
+							},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				Challenge: &version2.Challenge{
+					Key:              "default_challenge_policy_default_test",
+					Type:             "javascript",
+					SecretKey:        "c2VjcmV0",
+					CookieName:       "nic_challenge",
+					CookieLifetime:   3600,
+					ExemptCIDRs:      []string{"10.0.0.0/8"},
+					ExemptUserAgents: []string{"Googlebot"},
+					BlockThreshold:   10,
+					BlockInterval:    "1m",
+					BlockDuration:    "10m",
+				},
+			},
+			msg: "challenge reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "api-key-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/api-key-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "api-key-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						APIKey: &conf_v1.APIKey{
+							SuppliedIn: &conf_v1.SuppliedIn{
+								Header: []string{"X-API-Key"},
+							},
+							ClientSecret:   "api-key-secret-metadata",
+							ClientIDHeader: "X-Client-ID",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				APIKey: apiKeyAuth{
+					Key: &version2.APIKey{
+						Header:         []string{"X-API-Key"},
+						MapName:        "apikey_auth_client_name_default_test_api_key_policy",
+						ExpiresMapName: "apikey_auth_client_expires_default_test_api_key_policy",
+						TierMapName:    "apikey_auth_client_tier_default_test_api_key_policy",
+						ClientIDHeader: "X-Client-ID",
+					},
+					Enabled: true,
+					Clients: []apiKeyClient{
+						{
+							ClientID:  "client1",
+							HashedKey: "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8",
+							Expires:   1893456000,
+							Tier:      "gold",
+						},
+					},
+				},
+			},
+			msg: "api key with client metadata",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "rate-limit-gold",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/rate-limit-gold": {
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Key:      "${apikey_client_id}",
+							ZoneSize: "10M",
+							Rate:     "10r/s",
+							Condition: &conf_v1.RateLimitCondition{
+								APIKey: &conf_v1.APIKeyCondition{
+									Tier: "Gold",
+								},
+								Default: true,
+							},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				RateLimit: rateLimit{
+					Reqs: []version2.LimitReq{
+						{
+							ZoneName: "pol_rl_default_rate-limit-gold_default_test",
+						},
+					},
+					Zones: []version2.LimitReqZone{
+						{
+							Key:           "$pol_rl_default_rate_limit_gold_default_test",
+							ZoneSize:      "10M",
+							Rate:          "10r/s",
+							ZoneName:      "pol_rl_default_rate-limit-gold_default_test",
+							GroupValue:    "Gold",
+							GroupVariable: "$rl_default_test_group_apikey_tier",
+							PolicyValue:   "rl_default_test_apikey_tier_gold",
+							PolicyResult:  "${apikey_client_id}",
+							GroupDefault:  true,
+							GroupSource:   "$apikey_client_tier",
+						},
+					},
+					PolicyGroupMaps: []version2.Map{
+						{
+							Source:   "$rl_default_test_group_apikey_tier",
+							Variable: "$pol_rl_default_rate_limit_gold_default_test",
+							Parameters: []version2.Parameter{
+								{
+									Value:  "default",
+									Result: "''",
+								},
+								{
+									Value:  "rl_default_test_apikey_tier_gold",
+									Result: "Val${apikey_client_id}",
+								},
+							},
+						},
+					},
+					GroupMaps: []version2.Map{
+						{
+							Source:   "$apikey_client_tier",
+							Variable: "$rl_default_test_group_apikey_tier",
+							Parameters: []version2.Parameter{
+								{
+									Value:  "Gold",
+									Result: "rl_default_test_apikey_tier_gold",
+								},
+								{
+									Value:  "default",
+									Result: "rl_default_test_apikey_tier_gold",
+								},
+							},
+						},
+					},
+					Options: version2.LimitReqOptions{
+						LogLevel:   "error",
+						RejectCode: 503,
+					},
+				},
+			},
+			msg: "rate limit api key tier",
+		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
@@ -8832,6 +9250,27 @@ func TestGeneratePolicies_GeneratesWAFPolicyOnValidApBundle(t *testing.T) {
 	}
 }
 
+var testOIDCProvider = &version2.OIDC{
+	Key:                   "default_test_default_oidc_policy",
+	AuthEndpoint:          "https://foo.com/auth",
+	TokenEndpoint:         "https://foo.com/token",
+	JwksURI:               "https://foo.com/certs",
+	ClientID:              "foo",
+	ClientSecret:          "super_secret_123",
+	RedirectURI:           "/_codexch",
+	LogoutURI:             "/logout",
+	Scope:                 "openid",
+	EndSessionEndpoint:    "https://foo.com/logout",
+	PostLogoutRedirectURI: "/_logout",
+	ZoneSyncLeeway:        200,
+	AccessTokenEnable:     true,
+	HMACKey:               "test",
+	CookieName:            "auth_token",
+	CookieSameSite:        "lax",
+	SessionTimeout:        "1h",
+	RefreshTimeout:        "8h",
+}
+
 func TestGeneratePoliciesFails(t *testing.T) {
 	t.Parallel()
 	ownerDetails := policyOwnerDetails{
@@ -9991,137 +10430,36 @@ func TestGeneratePoliciesFails(t *testing.T) {
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
-					Name:      "oidc-policy-2",
+					Name:      "oidc-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "oidc-policy2",
 					Namespace: "default",
 				},
 			},
 			policies: map[string]*conf_v1.Policy{
-				"default/oidc-policy-1": {
+				"default/oidc-policy": {
 					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "oidc-policy-1",
+						Name:      "oidc-policy",
 						Namespace: "default",
 					},
 					Spec: conf_v1.PolicySpec{
 						OIDC: &conf_v1.OIDC{
-							ClientID:              "foo",
 							ClientSecret:          "oidc-secret",
 							AuthEndpoint:          "https://foo.com/auth",
 							TokenEndpoint:         "https://foo.com/token",
 							JWKSURI:               "https://foo.com/certs",
 							EndSessionEndpoint:    "https://foo.com/logout",
 							PostLogoutRedirectURI: "/_logout",
+							ClientID:              "foo",
 							AccessTokenEnable:     true,
 						},
 					},
 				},
-				"default/oidc-policy-2": {
+				"default/oidc-policy2": {
 					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "oidc-policy-2",
-						Namespace: "default",
-					},
-					Spec: conf_v1.PolicySpec{
-						OIDC: &conf_v1.OIDC{
-							ClientID:              "foo",
-							ClientSecret:          "oidc-secret",
-							AuthEndpoint:          "https://bar.com/auth",
-							TokenEndpoint:         "https://bar.com/token",
-							JWKSURI:               "https://bar.com/certs",
-							EndSessionEndpoint:    "https://bar.com/logout",
-							PostLogoutRedirectURI: "/_logout",
-							AccessTokenEnable:     true,
-						},
-					},
-				},
-			},
-			policyOpts: policyOptions{
-				secretRefs: map[string]*secrets.SecretReference{
-					"default/oidc-secret": {
-						Secret: &api_v1.Secret{
-							Type: secrets.SecretTypeOIDC,
-							Data: map[string][]byte{
-								"client-secret": []byte("super_secret_123"),
-							},
-						},
-					},
-				},
-			},
-			context: "route",
-			oidcPolCfg: &oidcPolicyCfg{
-				oidc: &version2.OIDC{
-					AuthEndpoint:          "https://foo.com/auth",
-					TokenEndpoint:         "https://foo.com/token",
-					JwksURI:               "https://foo.com/certs",
-					ClientID:              "foo",
-					ClientSecret:          "super_secret_123",
-					RedirectURI:           "/_codexch",
-					Scope:                 "openid",
-					ZoneSyncLeeway:        0,
-					EndSessionEndpoint:    "https://foo.com/logout",
-					PostLogoutRedirectURI: "/_logout",
-					AccessTokenEnable:     true,
-				},
-				key: "default/oidc-policy-1",
-			},
-			expected: policiesCfg{
-				ErrorReturn: &version2.Return{
-					Code: 500,
-				},
-			},
-			expectedWarnings: Warnings{
-				nil: {
-					`Only one oidc policy is allowed in a VirtualServer and its VirtualServerRoutes. Can't use default/oidc-policy-2. Use default/oidc-policy-1`,
-				},
-			},
-			expectedOidc: &oidcPolicyCfg{
-				oidc: &version2.OIDC{
-					AuthEndpoint:          "https://foo.com/auth",
-					TokenEndpoint:         "https://foo.com/token",
-					JwksURI:               "https://foo.com/certs",
-					ClientID:              "foo",
-					ClientSecret:          "super_secret_123",
-					RedirectURI:           "/_codexch",
-					Scope:                 "openid",
-					EndSessionEndpoint:    "https://foo.com/logout",
-					PostLogoutRedirectURI: "/_logout",
-					AccessTokenEnable:     true,
-				},
-				key: "default/oidc-policy-1",
-			},
-			msg: "multiple oidc policies",
-		},
-		{
-			policyRefs: []conf_v1.PolicyReference{
-				{
-					Name:      "oidc-policy",
-					Namespace: "default",
-				},
-				{
-					Name:      "oidc-policy2",
-					Namespace: "default",
-				},
-			},
-			policies: map[string]*conf_v1.Policy{
-				"default/oidc-policy": {
-					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "oidc-policy",
-						Namespace: "default",
-					},
-					Spec: conf_v1.PolicySpec{
-						OIDC: &conf_v1.OIDC{
-							ClientSecret:          "oidc-secret",
-							AuthEndpoint:          "https://foo.com/auth",
-							TokenEndpoint:         "https://foo.com/token",
-							JWKSURI:               "https://foo.com/certs",
-							EndSessionEndpoint:    "https://foo.com/logout",
-							PostLogoutRedirectURI: "/_logout",
-							ClientID:              "foo",
-							AccessTokenEnable:     true,
-						},
-					},
-				},
-				"default/oidc-policy2": {
-					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "oidc-policy2",
+						Name:      "oidc-policy2",
 						Namespace: "default",
 					},
 					Spec: conf_v1.PolicySpec{
@@ -10152,7 +10490,7 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			},
 			context: "route",
 			expected: policiesCfg{
-				OIDC: true,
+				OIDC: testOIDCProvider,
 			},
 			expectedWarnings: Warnings{
 				nil: {
@@ -10160,20 +10498,10 @@ func TestGeneratePoliciesFails(t *testing.T) {
 				},
 			},
 			expectedOidc: &oidcPolicyCfg{
-				&version2.OIDC{
-					AuthEndpoint:          "https://foo.com/auth",
-					TokenEndpoint:         "https://foo.com/token",
-					JwksURI:               "https://foo.com/certs",
-					ClientID:              "foo",
-					ClientSecret:          "super_secret_123",
-					RedirectURI:           "/_codexch",
-					Scope:                 "openid",
-					EndSessionEndpoint:    "https://foo.com/logout",
-					PostLogoutRedirectURI: "/_logout",
-					ZoneSyncLeeway:        200,
-					AccessTokenEnable:     true,
+				providers: []*version2.OIDC{testOIDCProvider},
+				keys: map[string]*version2.OIDC{
+					"default/oidc-policy": testOIDCProvider,
 				},
-				"default/oidc-policy",
 			},
 			msg: "multi oidc",
 		},
@@ -10445,6 +10773,120 @@ func TestGeneratePoliciesFails(t *testing.T) {
 			expectedOidc: &oidcPolicyCfg{},
 			msg:          "multi waf",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "introspection-policy",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/introspection-policy": {
+					Spec: conf_v1.PolicySpec{
+						OAuth2Introspection: &conf_v1.OAuth2Introspection{
+							Endpoint:     "https://idp.example.com/introspect",
+							ClientSecret: "introspection-secret",
+						},
+					},
+				},
+			},
+			policyOpts: policyOptions{
+				secretRefs: map[string]*secrets.SecretReference{
+					"default/introspection-secret": {
+						Secret: &api_v1.Secret{
+							Type: secrets.SecretTypeOIDC,
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				ErrorReturn: &version2.Return{
+					Code: 500,
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					"OAuth2Introspection policy default/introspection-policy references a secret default/introspection-secret of a wrong type 'nginx.org/oidc', must be 'nginx.org/oauth2-introspection'",
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "oauth2 introspection references wrong secret type",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "signature-policy",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/signature-policy": {
+					Spec: conf_v1.PolicySpec{
+						SignatureVerification: &conf_v1.SignatureVerification{
+							Header: "X-Signature",
+							Secret: "webhook-secret",
+						},
+					},
+				},
+			},
+			policyOpts: policyOptions{
+				secretRefs: map[string]*secrets.SecretReference{
+					"default/webhook-secret": {
+						Secret: &api_v1.Secret{
+							Type: secrets.SecretTypeAPIKey,
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				ErrorReturn: &version2.Return{
+					Code: 500,
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					"SignatureVerification policy default/signature-policy references a secret default/webhook-secret of a wrong type 'nginx.org/apikey', must be 'nginx.org/hmac'",
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "signature verification references wrong secret type",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name: "challenge-policy",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/challenge-policy": {
+					Spec: conf_v1.PolicySpec{
+						Challenge: &conf_v1.Challenge{
+							Secret: "challenge-secret",
+						},
+					},
+				},
+			},
+			policyOpts: policyOptions{
+				secretRefs: map[string]*secrets.SecretReference{
+					"default/challenge-secret": {
+						Secret: &api_v1.Secret{
+							Type: secrets.SecretTypeHMAC,
+						},
+						Error: errors.New("secret is invalid"),
+					},
+				},
+			},
+			expected: policiesCfg{
+				ErrorReturn: &version2.Return{
+					Code: 500,
+				},
+			},
+			expectedWarnings: Warnings{
+				nil: {
+					"Challenge policy default/challenge-policy references an invalid secret default/challenge-secret: secret is invalid",
+				},
+			},
+			expectedOidc: &oidcPolicyCfg{},
+			msg:          "challenge references invalid secret",
+		},
 	}
 
 	for _, test := range tests {
@@ -10468,78 +10910,400 @@ func TestGeneratePoliciesFails(t *testing.T) {
 					test.msg,
 				)
 			}
-			if diff := cmp.Diff(test.expectedOidc.oidc, vsc.oidcPolCfg.oidc); diff != "" {
+			if diff := cmp.Diff(test.expectedOidc.providers, vsc.oidcPolCfg.providers); diff != "" {
 				t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
 			}
-			if diff := cmp.Diff(test.expectedOidc.key, vsc.oidcPolCfg.key); diff != "" {
+			if diff := cmp.Diff(test.expectedOidc.keys, vsc.oidcPolCfg.keys); diff != "" {
 				t.Errorf("generatePolicies() '%v' mismatch (-want +got):\n%s", test.msg, diff)
 			}
 		})
 	}
 }
 
-func TestRemoveDuplicateLimitReqZones(t *testing.T) {
+func TestGeneratePoliciesFailsForDeniedReferences(t *testing.T) {
 	t.Parallel()
-	tests := []struct {
-		rlz      []version2.LimitReqZone
-		expected []version2.LimitReqZone
-	}{
-		{
-			rlz: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
-			},
-			expected: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
-			},
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
 		},
+	}
+	ownerDetails := policyOwnerDetails{
+		owner:          vs,
+		ownerName:      "cafe",
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "cafe",
+	}
+	policyRefs := []conf_v1.PolicyReference{
 		{
-			rlz: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
-				{ZoneName: "test3"},
-			},
-			expected: []version2.LimitReqZone{
-				{ZoneName: "test"},
-				{ZoneName: "test2"},
-				{ZoneName: "test3"},
+			Name:      "allow-policy",
+			Namespace: "policies",
+		},
+	}
+	policies := map[string]*conf_v1.Policy{
+		"policies/allow-policy": {
+			Spec: conf_v1.PolicySpec{
+				AccessControl: &conf_v1.AccessControl{
+					Allow: []string{"127.0.0.1"},
+				},
 			},
 		},
 	}
-	for _, test := range tests {
-		result := removeDuplicateLimitReqZones(test.rlz)
-		if !reflect.DeepEqual(result, test.expected) {
-			t.Errorf("removeDuplicateLimitReqZones() returned \n%v, but expected \n%v", result, test.expected)
-		}
+	policyOpts := policyOptions{
+		deniedReferences: map[string]error{
+			GenerateReferenceKey(conf_v1.ReferenceGrantKindVirtualServer, "default", conf_v1.ReferenceGrantKindPolicy, "policies/allow-policy"): errors.New("not allowed"),
+		},
+	}
+
+	expected := policiesCfg{
+		ErrorReturn: &version2.Return{
+			Code: 500,
+		},
+	}
+	expectedWarnings := Warnings{
+		vs: {
+			"Policy policies/allow-policy cannot be referenced: not allowed",
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+	result := vsc.generatePolicies(ownerDetails, policyRefs, policies, specContext, policyOpts)
+	result.BundleValidator = nil
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generatePolicies() mismatch (-want +got):\n%s", diff)
+	}
+	if !reflect.DeepEqual(vsc.warnings, expectedWarnings) {
+		t.Errorf("generatePolicies() returned warnings of \n%v but expected \n%v", vsc.warnings, expectedWarnings)
 	}
 }
 
-func TestRemoveDuplicateMaps(t *testing.T) {
+func TestGeneratePoliciesFailsForGeoAccessControlWithoutGeoIP2Database(t *testing.T) {
 	t.Parallel()
-	tests := []struct {
-		maps     []version2.Map
-		expected []version2.Map
-	}{
-		{
-			maps: []version2.Map{
-				{Source: "test", Variable: "test"},
-				{Source: "test", Variable: "test"},
-				{Source: "test2", Variable: "test2"},
-				{Source: "test3", Variable: "test3"},
-			},
-			expected: []version2.Map{
-				{Source: "test", Variable: "test"},
-				{Source: "test2", Variable: "test2"},
-				{Source: "test3", Variable: "test3"},
-			},
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
 		},
-		{
+	}
+	ownerDetails := policyOwnerDetails{
+		owner:          vs,
+		ownerName:      "cafe",
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "cafe",
+	}
+	policyRefs := []conf_v1.PolicyReference{
+		{
+			Name: "deny-geo",
+		},
+	}
+	policies := map[string]*conf_v1.Policy{
+		"default/deny-geo": {
+			Spec: conf_v1.PolicySpec{
+				AccessControl: &conf_v1.AccessControl{
+					Geo: &conf_v1.GeoAccessControl{
+						DenyCountries: []string{"US"},
+						DenyASNs:      []int{64496},
+					},
+				},
+			},
+		},
+	}
+
+	expected := policiesCfg{
+		ErrorReturn: &version2.Return{
+			Code: 500,
+		},
+	}
+	expectedWarnings := Warnings{
+		vs: {
+			"AccessControl policy default/deny-geo uses GeoIP2 databases that are not configured: set the ConfigMap key(s) geoip2-asn-database",
+		},
+	}
+
+	cfgParams := &ConfigParams{Context: context.Background(), MainGeoIP2CountryDatabase: "/etc/nginx/geoip2/GeoLite2-Country.mmdb"}
+	vsc := newVirtualServerConfigurator(cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+	result := vsc.generatePolicies(ownerDetails, policyRefs, policies, specContext, policyOptions{})
+	result.BundleValidator = nil
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generatePolicies() mismatch (-want +got):\n%s", diff)
+	}
+	if !reflect.DeepEqual(vsc.warnings, expectedWarnings) {
+		t.Errorf("generatePolicies() returned warnings of \n%v but expected \n%v", vsc.warnings, expectedWarnings)
+	}
+}
+
+func TestGeneratePoliciesWithMultipleOIDCProviders(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		owner:          nil, // nil is OK for the unit test
+		ownerName:      "cafe",
+		ownerNamespace: "default",
+		vsNamespace:    "default",
+		vsName:         "cafe",
+	}
+	policies := map[string]*conf_v1.Policy{
+		"default/oidc-tea": {
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "oidc-tea",
+				Namespace: "default",
+			},
+			Spec: conf_v1.PolicySpec{
+				OIDC: &conf_v1.OIDC{
+					ClientID:      "tea",
+					ClientSecret:  "oidc-secret",
+					AuthEndpoint:  "https://tea.example.com/auth",
+					TokenEndpoint: "https://tea.example.com/token",
+					JWKSURI:       "https://tea.example.com/certs",
+				},
+			},
+		},
+		"default/oidc-coffee": {
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "oidc-coffee",
+				Namespace: "default",
+			},
+			Spec: conf_v1.PolicySpec{
+				OIDC: &conf_v1.OIDC{
+					ClientID:      "coffee",
+					PKCEEnable:    true,
+					AuthEndpoint:  "https://coffee.example.com/auth",
+					TokenEndpoint: "https://coffee.example.com/token",
+					JWKSURI:       "https://coffee.example.com/certs",
+					RedirectURI:   "/coffee/callback",
+					LogoutURI:     "/coffee/logout",
+					Session: &conf_v1.OIDCSession{
+						CookieName:     "coffee_session",
+						CookieDomain:   ".example.com",
+						CookieSameSite: "strict",
+						Timeout:        "30m",
+						RefreshTimeout: "1d",
+					},
+				},
+			},
+		},
+	}
+	policyOpts := policyOptions{
+		secretRefs: map[string]*secrets.SecretReference{
+			"default/oidc-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeOIDC,
+					Data: map[string][]byte{
+						"client-secret": []byte("super_secret_123"),
+					},
+				},
+			},
+		},
+	}
+
+	tea := &version2.OIDC{
+		Key:                   "default_cafe_default_oidc_tea",
+		AuthEndpoint:          "https://tea.example.com/auth",
+		TokenEndpoint:         "https://tea.example.com/token",
+		JwksURI:               "https://tea.example.com/certs",
+		ClientID:              "tea",
+		ClientSecret:          "super_secret_123",
+		Scope:                 "openid",
+		RedirectURI:           "/_codexch",
+		LogoutURI:             "/logout",
+		PostLogoutRedirectURI: "/_logout",
+		ZoneSyncLeeway:        200,
+		HMACKey:               "cafe",
+		CookieName:            "auth_token",
+		CookieSameSite:        "lax",
+		SessionTimeout:        "1h",
+		RefreshTimeout:        "8h",
+	}
+	coffee := &version2.OIDC{
+		Key:                   "default_cafe_default_oidc_coffee",
+		AuthEndpoint:          "https://coffee.example.com/auth",
+		TokenEndpoint:         "https://coffee.example.com/token",
+		JwksURI:               "https://coffee.example.com/certs",
+		ClientID:              "coffee",
+		Scope:                 "openid",
+		RedirectURI:           "/coffee/callback",
+		LogoutURI:             "/coffee/logout",
+		PostLogoutRedirectURI: "/_logout",
+		ZoneSyncLeeway:        200,
+		PKCEEnable:            true,
+		HMACKey:               "cafe",
+		CookieName:            "coffee_session",
+		CookieDomain:          ".example.com",
+		CookieSameSite:        "strict",
+		SessionTimeout:        "30m",
+		RefreshTimeout:        "1d",
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, true, false, &StaticConfigParams{}, false, &fakeBV)
+
+	teaRefs := []conf_v1.PolicyReference{{Name: "oidc-tea"}}
+	coffeeRefs := []conf_v1.PolicyReference{{Name: "oidc-coffee"}}
+
+	for _, refs := range [][]conf_v1.PolicyReference{teaRefs, coffeeRefs, teaRefs} {
+		result := vsc.generatePolicies(ownerDetails, refs, policies, routeContext, policyOpts)
+		if result.ErrorReturn != nil {
+			t.Fatalf("generatePolicies() returned an error return for the policy %s", refs[0].Name)
+		}
+	}
+
+	if diff := cmp.Diff([]*version2.OIDC{tea, coffee}, vsc.oidcPolCfg.providers); diff != "" {
+		t.Errorf("generatePolicies() mismatch (-want +got):\n%s", diff)
+	}
+	if len(vsc.warnings) > 0 {
+		t.Errorf("generatePolicies() returned unexpected warnings %v", vsc.warnings)
+	}
+}
+
+func TestGenerateOIDCProviders(t *testing.T) {
+	t.Parallel()
+	tea := &version2.OIDC{Key: "tea", RedirectURI: "/_codexch", LogoutURI: "/logout"}
+	coffee := &version2.OIDC{Key: "coffee", RedirectURI: "/coffee/callback", LogoutURI: "/logout"}
+	juice := &version2.OIDC{Key: "juice", RedirectURI: "/juice/callback", LogoutURI: "/juice/logout"}
+	water := &version2.OIDC{Key: "water", RedirectURI: "/water/callback", LogoutURI: "/water/logout"}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, true, false, &StaticConfigParams{}, false, &fakeBV)
+	vsc.oidcPolCfg.providers = []*version2.OIDC{tea, coffee, juice, water}
+
+	locations := []version2.Location{
+		{Path: "/tea", OIDC: tea},
+		{Path: "/coffee", OIDC: coffee},
+		{Path: "=/juice/logout", OIDC: juice},
+		{Path: "~ ^/water/callback", OIDC: water},
+	}
+
+	providers := vsc.generateOIDCProviders(nil, locations)
+
+	if diff := cmp.Diff([]*version2.OIDC{tea, water}, providers); diff != "" {
+		t.Errorf("generateOIDCProviders() mismatch (-want +got):\n%s", diff)
+	}
+
+	expectedLocations := []version2.Location{
+		{Path: "/tea", OIDC: tea},
+		{Path: "/coffee", PoliciesErrorReturn: &version2.Return{Code: 500}},
+		{Path: "=/juice/logout", PoliciesErrorReturn: &version2.Return{Code: 500}},
+		{Path: "~ ^/water/callback", OIDC: water},
+	}
+	if diff := cmp.Diff(expectedLocations, locations); diff != "" {
+		t.Errorf("generateOIDCProviders() mismatch of locations (-want +got):\n%s", diff)
+	}
+
+	expectedWarnings := Warnings{
+		nil: {
+			"The location /logout of the OIDC provider coffee collides with another location of the VirtualServer",
+			"The location /juice/logout of the OIDC provider juice collides with another location of the VirtualServer",
+		},
+	}
+	if !reflect.DeepEqual(vsc.warnings, expectedWarnings) {
+		t.Errorf("generateOIDCProviders() returned warnings %v, expected %v", vsc.warnings, expectedWarnings)
+	}
+}
+
+func TestGenerateOIDCProvidersDerivesDefaultsOfSeveralProviders(t *testing.T) {
+	t.Parallel()
+	tea := &version2.OIDC{Key: "default_cafe_default_tea", RedirectURI: "/_codexch", LogoutURI: "/logout", CookieName: "auth_token"}
+	coffee := &version2.OIDC{Key: "default_cafe_default_coffee", RedirectURI: "/coffee/callback", LogoutURI: "/logout", CookieName: "coffee_session"}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, true, false, &StaticConfigParams{}, false, &fakeBV)
+	vsc.oidcPolCfg.providers = []*version2.OIDC{tea, coffee}
+	vsc.oidcPolCfg.defaults = map[*version2.OIDC]oidcDefaults{
+		tea:    {redirectURI: true, logoutURI: true, cookieName: true},
+		coffee: {logoutURI: true},
+	}
+
+	locations := []version2.Location{
+		{Path: "/tea", OIDC: tea},
+		{Path: "/coffee", OIDC: coffee},
+	}
+
+	providers := vsc.generateOIDCProviders(nil, locations)
+
+	expected := []*version2.OIDC{
+		{
+			Key:         "default_cafe_default_tea",
+			RedirectURI: "/_codexch_default_cafe_default_tea",
+			LogoutURI:   "/logout_default_cafe_default_tea",
+			CookieName:  "auth_token_default_cafe_default_tea",
+		},
+		{
+			Key:         "default_cafe_default_coffee",
+			RedirectURI: "/coffee/callback",
+			LogoutURI:   "/logout_default_cafe_default_coffee",
+			CookieName:  "coffee_session",
+		},
+	}
+	if diff := cmp.Diff(expected, providers); diff != "" {
+		t.Errorf("generateOIDCProviders() mismatch (-want +got):\n%s", diff)
+	}
+	if len(vsc.warnings) > 0 {
+		t.Errorf("generateOIDCProviders() returned unexpected warnings %v", vsc.warnings)
+	}
+}
+
+func TestRemoveDuplicateLimitReqZones(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		rlz      []version2.LimitReqZone
+		expected []version2.LimitReqZone
+	}{
+		{
+			rlz: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+			expected: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+		},
+		{
+			rlz: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+				{ZoneName: "test3"},
+			},
+			expected: []version2.LimitReqZone{
+				{ZoneName: "test"},
+				{ZoneName: "test2"},
+				{ZoneName: "test3"},
+			},
+		},
+	}
+	for _, test := range tests {
+		result := removeDuplicateLimitReqZones(test.rlz)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("removeDuplicateLimitReqZones() returned \n%v, but expected \n%v", result, test.expected)
+		}
+	}
+}
+
+func TestRemoveDuplicateMaps(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		maps     []version2.Map
+		expected []version2.Map
+	}{
+		{
+			maps: []version2.Map{
+				{Source: "test", Variable: "test"},
+				{Source: "test", Variable: "test"},
+				{Source: "test2", Variable: "test2"},
+				{Source: "test3", Variable: "test3"},
+			},
+			expected: []version2.Map{
+				{Source: "test", Variable: "test"},
+				{Source: "test2", Variable: "test2"},
+				{Source: "test3", Variable: "test3"},
+			},
+		},
+		{
 			maps: []version2.Map{
 				{Source: "test", Variable: "test"},
 				{Source: "test", Variable: "test"},
@@ -10643,6 +11407,134 @@ func TestRemoveDuplicateAuthJWTClaimSets(t *testing.T) {
 	}
 }
 
+func TestGenerateSignatureVerificationLocations(t *testing.T) {
+	t.Parallel()
+	sv := &version2.SignatureVerification{
+		Header:    "X-Signature",
+		Algorithm: "sha256",
+		Encoding:  "hex",
+		Key:       "c2VjcmV0",
+	}
+	locations := []version2.Location{
+		{
+			Path:                  "/webhooks",
+			ProxyPass:             "http://vs_default_cafe_webhooks",
+			ProxyPassRewrite:      "/events",
+			ClientMaxBodySize:     "2m",
+			Allow:                 []string{"10.0.0.0/8"},
+			SignatureVerification: sv,
+		},
+		{
+			Path:                  "/internal_location_splits_0_split_0",
+			ProxyPass:             "http://vs_default_cafe_hooks$request_uri",
+			ClientMaxBodySize:     "0",
+			Internal:              true,
+			SignatureVerification: sv,
+		},
+		{
+			Path:                  "/redirect",
+			SignatureVerification: sv,
+		},
+		{
+			Path:      "/tea",
+			ProxyPass: "http://vs_default_cafe_tea",
+		},
+	}
+
+	expectedNamed := []version2.Location{
+		{
+			Path:              "@signature_verification_0",
+			ProxyPass:         "http://vs_default_cafe_webhooks",
+			ClientMaxBodySize: "2m",
+			Rewrites:          []string{`"^/webhooks(.*)$" "/events$1" break`},
+		},
+		{
+			Path:              "@signature_verification_1",
+			ProxyPass:         "http://vs_default_cafe_hooks$request_uri",
+			ClientMaxBodySize: "0",
+		},
+	}
+	expectedVerification := []*version2.SignatureVerification{
+		{
+			Header:         "X-Signature",
+			Algorithm:      "sha256",
+			Encoding:       "hex",
+			Key:            "c2VjcmV0",
+			BodyBufferSize: "2m",
+			Location:       "@signature_verification_0",
+		},
+		{
+			Header:         "X-Signature",
+			Algorithm:      "sha256",
+			Encoding:       "hex",
+			Key:            "c2VjcmV0",
+			BodyBufferSize: "1m",
+			Location:       "@signature_verification_1",
+		},
+		nil,
+		nil,
+	}
+
+	named := generateSignatureVerificationLocations(locations)
+	if diff := cmp.Diff(expectedNamed, named); diff != "" {
+		t.Errorf("generateSignatureVerificationLocations() mismatch (-want +got):\n%s", diff)
+	}
+	for i, l := range locations {
+		if diff := cmp.Diff(expectedVerification[i], l.SignatureVerification); diff != "" {
+			t.Errorf("generateSignatureVerificationLocations() location %s mismatch (-want +got):\n%s", l.Path, diff)
+		}
+	}
+}
+
+func TestGenerateAPIKeyClientMaps(t *testing.T) {
+	t.Parallel()
+	clients := []apiKeyClient{
+		{
+			ClientID:  "client1",
+			HashedKey: "hash1",
+			Expires:   1893456000,
+			Tier:      "gold",
+		},
+		{
+			ClientID:  "client2",
+			HashedKey: "hash2",
+		},
+	}
+
+	expected := []version2.Map{
+		{
+			Source:   "$apikey_auth_token",
+			Variable: "$apikey_auth_client_name_default_cafe_api_key_policy",
+			Parameters: []version2.Parameter{
+				{Value: "default", Result: `""`},
+				{Value: `"hash1"`, Result: `"client1"`},
+				{Value: `"hash2"`, Result: `"client2"`},
+			},
+		},
+		{
+			Source:   "$apikey_auth_token",
+			Variable: "$apikey_auth_client_expires_default_cafe_api_key_policy",
+			Parameters: []version2.Parameter{
+				{Value: "default", Result: `""`},
+				{Value: `"hash1"`, Result: `"1893456000"`},
+			},
+		},
+		{
+			Source:   "$apikey_auth_token",
+			Variable: "$apikey_auth_client_tier_default_cafe_api_key_policy",
+			Parameters: []version2.Parameter{
+				{Value: "default", Result: `""`},
+				{Value: `"hash1"`, Result: `"gold"`},
+			},
+		},
+	}
+
+	result := generateAPIKeyClientMaps("apikey_auth_client_name_default_cafe_api_key_policy", clients)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateAPIKeyClientMaps() mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestGenerateLRZPolicyGroupMap(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -10951,73 +11843,902 @@ func TestHasDuplicateMapDefaults(t *testing.T) {
 					},
 				},
 			},
-			msg:      "doesn't have duplicate defaults",
-			expected: false,
+			msg:      "doesn't have duplicate defaults",
+			expected: false,
+		},
+		{
+			m: version2.Map{
+				Source:   "$my_source_var",
+				Variable: "$my_targetvar",
+				Parameters: []version2.Parameter{
+					{
+						Value:  "default",
+						Result: "my_result1",
+					},
+					{
+						Value:  "other_value",
+						Result: "duplicate_value",
+					},
+					{
+						Value:  "other_value",
+						Result: "duplicate_value",
+					},
+				},
+			},
+			msg:      "has other duplicate values",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		result := hasDuplicateMapDefaults(&test.m)
+
+		if result != test.expected {
+			t.Errorf("hasDuplicateMapDefaults() returned \n%t, but expected \n%t for the case of %v", result, test.expected, test.msg)
+		}
+
+	}
+}
+
+func TestAddPoliciesCfgToLocations(t *testing.T) {
+	t.Parallel()
+	cfg := policiesCfg{
+		Allow: []string{"127.0.0.1"},
+		Deny:  []string{"127.0.0.2"},
+		ErrorReturn: &version2.Return{
+			Code: 400,
+		},
+	}
+
+	locations := []version2.Location{
+		{
+			Path: "/",
+		},
+	}
+
+	expectedLocations := []version2.Location{
+		{
+			Path:  "/",
+			Allow: []string{"127.0.0.1"},
+			Deny:  []string{"127.0.0.2"},
+			PoliciesErrorReturn: &version2.Return{
+				Code: 400,
+			},
+		},
+	}
+
+	addPoliciesCfgToLocations(cfg, locations)
+	if !reflect.DeepEqual(locations, expectedLocations) {
+		t.Errorf("addPoliciesCfgToLocations() returned \n%+v but expected \n%+v", locations, expectedLocations)
+	}
+}
+
+func TestAddPoliciesCfgToLocationsWithRetry(t *testing.T) {
+	t.Parallel()
+	cfg := policiesCfg{}
+	res := cfg.addRetryConfig(&conf_v1.Retry{
+		RetryOn:       []string{"error", "http_503"},
+		Tries:         3,
+		PerTryTimeout: "2s",
+		NonIdempotent: true,
+	}, "default/retry", routeContext)
+	if len(res.warnings) > 0 {
+		t.Fatalf("addRetryConfig() returned unexpected warnings %v", res.warnings)
+	}
+
+	// the retry policy without a timeout keeps the next upstream timeout of the upstream
+	locations := []version2.Location{
+		{
+			Path:                     "/",
+			ProxyPass:                "http://vs_default_cafe_tea",
+			ProxyNextUpstreamTimeout: "10s",
+		},
+		{
+			Path: "/return",
+		},
+	}
+
+	expectedLocations := []version2.Location{
+		{
+			Path:                     "/",
+			ProxyPass:                "http://vs_default_cafe_tea",
+			ProxyNextUpstream:        "error http_503 non_idempotent",
+			ProxyNextUpstreamTimeout: "10s",
+			ProxyNextUpstreamTries:   3,
+			ProxyConnectTimeout:      "2s",
+			ProxyReadTimeout:         "2s",
+			ProxySendTimeout:         "2s",
+		},
+		{
+			Path: "/return",
+		},
+	}
+
+	addPoliciesCfgToLocations(cfg, locations)
+	if !reflect.DeepEqual(locations, expectedLocations) {
+		t.Errorf("addPoliciesCfgToLocations() returned \n%+v but expected \n%+v", locations, expectedLocations)
+	}
+
+	cfg = policiesCfg{}
+	cfg.addRetryConfig(&conf_v1.Retry{Timeout: "30s"}, "default/retry", routeContext)
+	locations = []version2.Location{
+		{
+			Path:                     "/",
+			ProxyPass:                "http://vs_default_cafe_tea",
+			ProxyNextUpstreamTimeout: "10s",
+		},
+	}
+
+	addPoliciesCfgToLocations(cfg, locations)
+	if locations[0].ProxyNextUpstreamTimeout != "30s" {
+		t.Errorf("addPoliciesCfgToLocations() set the next upstream timeout %q, expected the timeout of the retry policy 30s", locations[0].ProxyNextUpstreamTimeout)
+	}
+}
+
+func TestAddPoliciesCfgToLocationsWithGeoAccess(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		vsNamespace: "default",
+		vsName:      "cafe",
+	}
+	cfgParams := &ConfigParams{MainGeoIP2CountryDatabase: "/etc/nginx/geoip2/GeoLite2-Country.mmdb"}
+	cfg := policiesCfg{}
+	res := cfg.addAccessControlConfig(&conf_v1.AccessControl{
+		Geo: &conf_v1.GeoAccessControl{
+			AllowCountries: []string{"DE", "FR"},
+		},
+	}, "default/allow-eu", "default", "allow-eu", ownerDetails, cfgParams)
+	if len(res.warnings) > 0 {
+		t.Fatalf("addAccessControlConfig() returned unexpected warnings %v", res.warnings)
+	}
+
+	res = cfg.addAccessControlConfig(&conf_v1.AccessControl{
+		Geo: &conf_v1.GeoAccessControl{
+			DenyCountries: []string{"US"},
+		},
+	}, "default/deny-us", "default", "deny-us", ownerDetails, cfgParams)
+	expectedWarnings := []string{
+		"Multiple geo AccessControl policies in the same context is not valid. AccessControl policy default/deny-us will be ignored",
+	}
+	if !reflect.DeepEqual(res.warnings, expectedWarnings) {
+		t.Errorf("addAccessControlConfig() returned warnings %v but expected %v", res.warnings, expectedWarnings)
+	}
+
+	locations := []version2.Location{
+		{
+			Path:      "/",
+			ProxyPass: "http://vs_default_cafe_tea",
+		},
+	}
+
+	expectedLocations := []version2.Location{
+		{
+			Path:      "/",
+			ProxyPass: "http://vs_default_cafe_tea",
+			GeoAccess: "$geo_access_default_allow_eu_default_cafe",
+		},
+	}
+
+	addPoliciesCfgToLocations(cfg, locations)
+	if !reflect.DeepEqual(locations, expectedLocations) {
+		t.Errorf("addPoliciesCfgToLocations() returned \n%+v but expected \n%+v", locations, expectedLocations)
+	}
+}
+
+func TestGenerateGeoAccessMap(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		geo      *conf_v1.GeoAccessControl
+		expected version2.Map
+		msg      string
+	}{
+		{
+			geo: &conf_v1.GeoAccessControl{
+				AllowCountries: []string{"DE", "FR"},
+				AllowASNs:      []int{3320},
+			},
+			expected: version2.Map{
+				Source:   `"$geoip2_country_code:$geoip2_asn"`,
+				Variable: "$geo_access",
+				Parameters: []version2.Parameter{
+					{Value: "default", Result: "0"},
+					{Value: `"~^DE:"`, Result: "1"},
+					{Value: `"~^FR:"`, Result: "1"},
+					{Value: `"~:3320$"`, Result: "1"},
+				},
+			},
+			msg: "allow lists",
+		},
+		{
+			geo: &conf_v1.GeoAccessControl{
+				DenyASNs: []int{64496, 64497},
+			},
+			expected: version2.Map{
+				Source:   `"$geoip2_country_code:$geoip2_asn"`,
+				Variable: "$geo_access",
+				Parameters: []version2.Parameter{
+					{Value: "default", Result: "1"},
+					{Value: `"~:64496$"`, Result: "0"},
+					{Value: `"~:64497$"`, Result: "0"},
+				},
+			},
+			msg: "deny lists",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateGeoAccessMap("$geo_access", test.geo)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateGeoAccessMap() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestAddPoliciesCfgToLocationsWithBandwidthLimit(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		vsNamespace: "default",
+		vsName:      "cafe",
+	}
+	cfg := policiesCfg{}
+	res := cfg.addBandwidthLimitConfig(&conf_v1.BandwidthLimit{
+		Rate:      "100k",
+		RateAfter: "1m",
+		Key:       "${http_x_tenant}",
+		Rates: []conf_v1.BandwidthLimitRate{
+			{Value: "premium", Rate: "1m"},
+		},
+	}, "default/bandwidth", "default", "bandwidth", ownerDetails)
+	if len(res.warnings) > 0 {
+		t.Fatalf("addBandwidthLimitConfig() returned unexpected warnings %v", res.warnings)
+	}
+
+	res = cfg.addBandwidthLimitConfig(&conf_v1.BandwidthLimit{
+		Rate: "10k",
+	}, "default/bandwidth-low", "default", "bandwidth-low", ownerDetails)
+	expectedWarnings := []string{
+		"Multiple bandwidthLimit policies in the same context is not valid. BandwidthLimit policy default/bandwidth-low will be ignored",
+	}
+	if !reflect.DeepEqual(res.warnings, expectedWarnings) {
+		t.Errorf("addBandwidthLimitConfig() returned warnings %v but expected %v", res.warnings, expectedWarnings)
+	}
+
+	expectedMaps := []version2.Map{
+		{
+			Source:   `"${http_x_tenant}"`,
+			Variable: "$bandwidth_limit_default_bandwidth_default_cafe",
+			Parameters: []version2.Parameter{
+				{Value: "default", Result: "100k"},
+				{Value: `"\\premium"`, Result: "1m"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedMaps, cfg.BandwidthLimit.maps); diff != "" {
+		t.Errorf("addBandwidthLimitConfig() maps mismatch (-want +got):\n%s", diff)
+	}
+
+	locations := []version2.Location{
+		{
+			Path:      "/",
+			ProxyPass: "http://vs_default_cafe_tea",
+		},
+	}
+
+	expectedLocations := []version2.Location{
+		{
+			Path:           "/",
+			ProxyPass:      "http://vs_default_cafe_tea",
+			LimitRate:      "$bandwidth_limit_default_bandwidth_default_cafe",
+			LimitRateAfter: "1m",
+		},
+	}
+
+	addPoliciesCfgToLocations(cfg, locations)
+	if !reflect.DeepEqual(locations, expectedLocations) {
+		t.Errorf("addPoliciesCfgToLocations() returned \n%+v but expected \n%+v", locations, expectedLocations)
+	}
+}
+
+func TestAddBandwidthLimitConfigWithoutRates(t *testing.T) {
+	t.Parallel()
+	cfg := policiesCfg{}
+	res := cfg.addBandwidthLimitConfig(&conf_v1.BandwidthLimit{
+		Rate: "100k",
+	}, "default/bandwidth", "default", "bandwidth", policyOwnerDetails{vsNamespace: "default", vsName: "cafe"})
+	if len(res.warnings) > 0 {
+		t.Fatalf("addBandwidthLimitConfig() returned unexpected warnings %v", res.warnings)
+	}
+
+	if cfg.BandwidthLimit.rate != "100k" {
+		t.Errorf("addBandwidthLimitConfig() set rate %q but expected %q", cfg.BandwidthLimit.rate, "100k")
+	}
+	if len(cfg.BandwidthLimit.maps) != 0 {
+		t.Errorf("addBandwidthLimitConfig() returned unexpected maps %v", cfg.BandwidthLimit.maps)
+	}
+}
+
+func TestAddPoliciesCfgToLocationsWithHeaders(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		vsNamespace: "default",
+		vsName:      "cafe",
+	}
+
+	specCfg := policiesCfg{}
+	specCfg.addHeadersConfig(&conf_v1.Headers{
+		Request: &conf_v1.HeadersRequest{
+			Set: []conf_v1.PolicyHeader{
+				{Name: "X-Team", Value: "cafe"},
+				{Name: "X-Env", Value: "prod"},
+			},
+		},
+		Response: &conf_v1.HeadersResponse{
+			Add: []conf_v1.PolicyAddHeader{
+				{PolicyHeader: conf_v1.PolicyHeader{Name: "Strict-Transport-Security", Value: "max-age=31536000"}, Always: true},
+				{PolicyHeader: conf_v1.PolicyHeader{Name: "X-Frame-Options", Value: "DENY"}},
+			},
+			Hide: []string{"X-Powered-By"},
+		},
+	}, "default/security-headers", "default", "security-headers", ownerDetails)
+
+	routeCfg := policiesCfg{}
+	res := routeCfg.addHeadersConfig(&conf_v1.Headers{
+		Request: &conf_v1.HeadersRequest{
+			Set: []conf_v1.PolicyHeader{
+				{
+					Name:  "X-Env",
+					Value: "other",
+					ValueMap: &conf_v1.HeaderValueMap{
+						Variable: "$scheme",
+						Values:   []conf_v1.HeaderMapValue{{Match: "https", Value: "secure"}},
+					},
+				},
+			},
+			Remove: []string{"X-Debug"},
+		},
+	}, "default/route-headers", "default", "route-headers", ownerDetails)
+	if len(res.warnings) > 0 {
+		t.Fatalf("addHeadersConfig() returned unexpected warnings %v", res.warnings)
+	}
+
+	expectedMaps := []version2.Map{
+		{
+			Source:   "$scheme",
+			Variable: "$pol_hdr_default_route_headers_default_cafe_req_0",
+			Parameters: []version2.Parameter{
+				{Value: `"https"`, Result: `"secure"`},
+				{Value: "default", Result: `"other"`},
+			},
+		},
+	}
+	if !cmp.Equal(expectedMaps, routeCfg.Headers.maps) {
+		t.Errorf("addHeadersConfig() mismatch (-want +got):\n%s", cmp.Diff(expectedMaps, routeCfg.Headers.maps))
+	}
+
+	routeCfg.Headers = mergePolicyHeaders(specCfg.Headers, routeCfg.Headers)
+
+	locations := []version2.Location{
+		{
+			Path:      "/tea",
+			ProxyPass: "http://vs_default_cafe_tea",
+			ProxySetHeaders: []version2.Header{
+				{Name: "x-team", Value: "tea"},
+				{Name: "Host", Value: "$host"},
+			},
+			AddHeaders: []version2.AddHeader{
+				{Header: version2.Header{Name: "X-Frame-Options", Value: "SAMEORIGIN"}},
+			},
+		},
+	}
+
+	expectedLocations := []version2.Location{
+		{
+			Path:      "/tea",
+			ProxyPass: "http://vs_default_cafe_tea",
+			ProxySetHeaders: []version2.Header{
+				{Name: "x-team", Value: "tea"},
+				{Name: "Host", Value: "$host"},
+				{Name: "X-Env", Value: "$pol_hdr_default_route_headers_default_cafe_req_0"},
+				{Name: "X-Debug", Value: ""},
+			},
+			AddHeaders: []version2.AddHeader{
+				{Header: version2.Header{Name: "X-Frame-Options", Value: "SAMEORIGIN"}},
+				{Header: version2.Header{Name: "Strict-Transport-Security", Value: "max-age=31536000"}, Always: true},
+			},
+			ProxyHideHeaders: []string{"X-Powered-By"},
+		},
+	}
+
+	addPoliciesCfgToLocations(routeCfg, locations)
+	if !cmp.Equal(expectedLocations, locations) {
+		t.Errorf("addPoliciesCfgToLocations() mismatch (-want +got):\n%s", cmp.Diff(expectedLocations, locations))
+	}
+}
+
+func TestAddPoliciesCfgToLocationsWithHeadersForNonProxyRoutes(t *testing.T) {
+	t.Parallel()
+	cfg := policiesCfg{}
+	res := cfg.addHeadersConfig(&conf_v1.Headers{
+		Request: &conf_v1.HeadersRequest{
+			Set:    []conf_v1.PolicyHeader{{Name: "X-Team", Value: "cafe"}},
+			Remove: []string{"X-Debug"},
+		},
+		Response: &conf_v1.HeadersResponse{
+			Add: []conf_v1.PolicyAddHeader{
+				{PolicyHeader: conf_v1.PolicyHeader{Name: "X-Frame-Options", Value: "DENY"}, Always: true},
+				{PolicyHeader: conf_v1.PolicyHeader{Name: "Content-Language", Value: "en"}},
+			},
+			Hide: []string{"X-Powered-By"},
+		},
+	}, "default/security-headers", "default", "security-headers", policyOwnerDetails{vsNamespace: "default", vsName: "cafe"})
+	if len(res.warnings) > 0 {
+		t.Fatalf("addHeadersConfig() returned unexpected warnings %v", res.warnings)
+	}
+
+	returnLoc, returnLocation := generateLocationForReturn("/return", nil, &conf_v1.ActionReturn{
+		Body:    "hello",
+		Headers: []conf_v1.Header{{Name: "content-language", Value: "fr"}},
+	}, 0)
+	locations := []version2.Location{
+		returnLoc,
+		generateLocationForRedirect("/redirect", nil, &conf_v1.ActionRedirect{URL: "http://example.com"}),
+		generateLocationForStatic("/docs/", nil, &conf_v1.ActionStatic{Path: "cafe/docs"}, errorPageDetails{}),
+	}
+	returnLocations := []version2.ReturnLocation{*returnLocation}
+
+	addPoliciesCfgToLocations(cfg, locations)
+	addResponseHeadersToReturnLocations(locations, returnLocations)
+
+	expectedAddHeaders := []version2.AddHeader{
+		{Header: version2.Header{Name: "X-Frame-Options", Value: "DENY"}, Always: true},
+		{Header: version2.Header{Name: "Content-Language", Value: "en"}},
+	}
+	for _, loc := range locations {
+		if !cmp.Equal(expectedAddHeaders, loc.AddHeaders) {
+			t.Errorf("addPoliciesCfgToLocations() returned unexpected AddHeaders for %s (-want +got):\n%s", loc.Path, cmp.Diff(expectedAddHeaders, loc.AddHeaders))
+		}
+		if len(loc.ProxySetHeaders) > 0 || len(loc.ProxyHideHeaders) > 0 {
+			t.Errorf("addPoliciesCfgToLocations() added proxy headers %v and hidden headers %v to %s", loc.ProxySetHeaders, loc.ProxyHideHeaders, loc.Path)
+		}
+	}
+
+	expectedReturnHeaders := []version2.Header{
+		{Name: "content-language", Value: "fr"},
+		{Name: "X-Frame-Options", Value: "DENY"},
+	}
+	if !cmp.Equal(expectedReturnHeaders, returnLocations[0].Headers) {
+		t.Errorf("addResponseHeadersToReturnLocations() mismatch (-want +got):\n%s", cmp.Diff(expectedReturnHeaders, returnLocations[0].Headers))
+	}
+}
+
+func TestAddJWTAuthConfigWithAuthorization(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		vsNamespace: "default",
+		vsName:      "cafe-app",
+	}
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/jwk-secret": {
+			Secret: &api_v1.Secret{Type: secrets.SecretTypeJWK},
+			Path:   "/etc/nginx/secrets/default-jwk-secret",
+		},
+	}
+
+	cfg := policiesCfg{}
+	res := cfg.addJWTAuthConfig(&conf_v1.JWTAuth{
+		Realm:  "My API",
+		Secret: "jwk-secret",
+		Authorization: &conf_v1.JWTAuthorization{
+			Audiences: []string{"cafe"},
+			Claims: []conf_v1.JWTClaimRequirement{
+				{Claim: "realm_access.roles", Values: []string{"admins", "tea.lovers"}},
+			},
+			Headers: []conf_v1.JWTClaimHeader{
+				{Name: "X-User", Claim: "sub"},
+			},
+			ErrorCode: createPointerFromInt(401),
+		},
+	}, "default/jwt-policy", "default", "jwt-policy", ownerDetails, secretRefs)
+	if len(res.warnings) > 0 {
+		t.Fatalf("addJWTAuthConfig() returned unexpected warnings %v", res.warnings)
+	}
+
+	expected := jwtAuth{
+		Auth: &version2.JWTAuth{
+			Secret: "/etc/nginx/secrets/default-jwk-secret",
+			Realm:  "My API",
+			Require: []string{
+				"$pol_jwt_default_jwt_policy_default_cafe_app_require_0",
+				"$pol_jwt_default_jwt_policy_default_cafe_app_require_1",
+			},
+			RequireErrorCode: 401,
+		},
+		AuthJWTClaimSets: []version2.AuthJWTClaimSet{
+			{Variable: "$jwt_default_cafe_app_aud", Claim: "aud"},
+			{Variable: "$jwt_default_cafe_app_realm_access_roles", Claim: "realm_access roles"},
+			{Variable: "$jwt_default_cafe_app_sub", Claim: "sub"},
+		},
+		Maps: []version2.Map{
+			{
+				Source:   "$jwt_default_cafe_app_aud",
+				Variable: "$pol_jwt_default_jwt_policy_default_cafe_app_require_0",
+				Parameters: []version2.Parameter{
+					{Value: `"~(^|,)cafe(,|$)"`, Result: "1"},
+					{Value: "default", Result: "0"},
+				},
+			},
+			{
+				Source:   "$jwt_default_cafe_app_realm_access_roles",
+				Variable: "$pol_jwt_default_jwt_policy_default_cafe_app_require_1",
+				Parameters: []version2.Parameter{
+					{Value: `"~(^|,)admins(,|$)"`, Result: "1"},
+					{Value: `"~(^|,)tea\.lovers(,|$)"`, Result: "1"},
+					{Value: "default", Result: "0"},
+				},
+			},
+		},
+		Headers: []version2.Header{
+			{Name: "X-User", Value: "$jwt_default_cafe_app_sub"},
+		},
+	}
+	if !cmp.Equal(expected, cfg.JWTAuth) {
+		t.Errorf("addJWTAuthConfig() mismatch (-want +got):\n%s", cmp.Diff(expected, cfg.JWTAuth))
+	}
+}
+
+func TestMergeJWTClaimHeaders(t *testing.T) {
+	t.Parallel()
+	specJWTAuth := jwtAuth{
+		Auth:    &version2.JWTAuth{Realm: "spec"},
+		Headers: []version2.Header{{Name: "X-User", Value: "$jwt_default_cafe_sub"}},
+	}
+	headers := &policyHeaders{
+		requestSet: []version2.Header{
+			{Name: "x-user", Value: "anonymous"},
+			{Name: "X-Team", Value: "cafe"},
+		},
+	}
+
+	tests := []struct {
+		routeJWTAuth jwtAuth
+		expected     []version2.Header
+		msg          string
+	}{
+		{
+			routeJWTAuth: jwtAuth{},
+			expected: []version2.Header{
+				{Name: "X-User", Value: "$jwt_default_cafe_sub"},
+				{Name: "X-Team", Value: "cafe"},
+			},
+			msg: "claim headers of the spec",
+		},
+		{
+			routeJWTAuth: jwtAuth{
+				Auth:    &version2.JWTAuth{Realm: "route"},
+				Headers: []version2.Header{{Name: "X-Email", Value: "$jwt_default_cafe_email"}},
+			},
+			expected: []version2.Header{
+				{Name: "X-Email", Value: "$jwt_default_cafe_email"},
+				{Name: "x-user", Value: "anonymous"},
+				{Name: "X-Team", Value: "cafe"},
+			},
+			msg: "claim headers of the route",
+		},
+		{
+			routeJWTAuth: jwtAuth{Auth: &version2.JWTAuth{Realm: "route"}},
+			expected: []version2.Header{
+				{Name: "x-user", Value: "anonymous"},
+				{Name: "X-Team", Value: "cafe"},
+			},
+			msg: "route JWT policy without claim headers",
+		},
+	}
+
+	for _, test := range tests {
+		result := mergeJWTClaimHeaders(specJWTAuth, test.routeJWTAuth, headers)
+		if !cmp.Equal(test.expected, result.requestSet) {
+			t.Errorf("mergeJWTClaimHeaders() mismatch for the case of %s (-want +got):\n%s", test.msg, cmp.Diff(test.expected, result.requestSet))
+		}
+	}
+}
+
+func TestGetCircuitBreakerUpstreams(t *testing.T) {
+	t.Parallel()
+	vsEx := &VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Routes: []conf_v1.Route{
+					{
+						Path:     "/tea",
+						Policies: []conf_v1.PolicyReference{{Name: "allow"}, {Name: "cb"}},
+						Action:   &conf_v1.Action{Pass: "tea"},
+					},
+					{
+						Path: "/tea-no-cb",
+						Splits: []conf_v1.Split{
+							{Weight: 90, Action: &conf_v1.Action{Pass: "tea"}},
+							{Weight: 10, Action: &conf_v1.Action{Proxy: &conf_v1.ActionProxy{Upstream: "tea-v2"}}},
+						},
+					},
+					{
+						Path:     "/coffee",
+						Route:    "coffee",
+						Policies: []conf_v1.PolicyReference{{Name: "cb"}},
+					},
+				},
+			},
+		},
+		VirtualServerRoutes: []*conf_v1.VirtualServerRoute{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "coffee",
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerRouteSpec{
+					Subroutes: []conf_v1.Route{
+						{
+							Path:   "/coffee",
+							Action: &conf_v1.Action{Pass: "coffee"},
+						},
+					},
+				},
+			},
+		},
+		Policies: map[string]*conf_v1.Policy{
+			"default/allow": {
+				Spec: conf_v1.PolicySpec{
+					AccessControl: &conf_v1.AccessControl{Allow: []string{"127.0.0.1"}},
+				},
+			},
+			"default/cb": {
+				Spec: conf_v1.PolicySpec{
+					CircuitBreaker: &conf_v1.CircuitBreaker{FailTimeout: "10s"},
+				},
+			},
+		},
+	}
+
+	expected := circuitBreakerUpstreams{
+		"vs_default_cafe_tea":                       {"default/cb", ""},
+		"vs_default_cafe_tea-v2":                    {""},
+		"vs_default_cafe_vsr_default_coffee_coffee": {"default/cb"},
+	}
+
+	result := getCircuitBreakerUpstreams(vsEx)
+	if !cmp.Equal(expected, result) {
+		t.Errorf("getCircuitBreakerUpstreams() mismatch (-want +got):\n%s", cmp.Diff(expected, result))
+	}
+}
+
+func TestAddRequestLimitsToLocations(t *testing.T) {
+	t.Parallel()
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "cafe", Namespace: "default"},
+	}
+
+	specCfg := policiesCfg{}
+	res := specCfg.addRequestLimitsConfig(&conf_v1.RequestLimits{
+		MaxBodySize:        "10m",
+		HeaderBufferSize:   "2k",
+		LargeHeaderBuffers: &conf_v1.UpstreamBuffers{Number: 4, Size: "16k"},
+		HeaderTimeout:      "30s",
+		AllowedMethods:     []string{"GET", "HEAD"},
+		MaxURILength:       createPointerFromInt(2048),
+	}, "default/spec-limits", specContext)
+	if len(res.warnings) > 0 {
+		t.Errorf("addRequestLimitsConfig() returned unexpected warnings %v for the spec context", res.warnings)
+	}
+
+	expectedServerLimits := &version2.ServerRequestLimits{
+		ClientHeaderBufferSize:   "2k",
+		LargeClientHeaderBuffers: "4 16k",
+		ClientHeaderTimeout:      "30s",
+	}
+	if diff := cmp.Diff(expectedServerLimits, generateServerRequestLimits(specCfg.RequestLimits)); diff != "" {
+		t.Errorf("generateServerRequestLimits() mismatch (-want +got):\n%s", diff)
+	}
+
+	routeCfg := policiesCfg{}
+	res = routeCfg.addRequestLimitsConfig(&conf_v1.RequestLimits{
+		BodyTimeout:    "20s",
+		HeaderTimeout:  "10s",
+		AllowedMethods: []string{"POST"},
+	}, "default/route-limits", routeContext)
+	expectedWarnings := []string{"The headerBufferSize, largeHeaderBuffers and headerTimeout of RequestLimits policy default/route-limits are ignored in the route context"}
+	if diff := cmp.Diff(expectedWarnings, res.warnings); diff != "" {
+		t.Errorf("addRequestLimitsConfig() returned unexpected warnings for the route context (-want +got):\n%s", diff)
+	}
+
+	limits := mergeRequestLimits(specCfg.RequestLimits, routeCfg.RequestLimits)
+
+	locations := []version2.Location{
+		{Path: "/tea", ProxyPass: "http://vs_default_cafe_tea", ClientMaxBodySize: "1m"},
+		{Path: "/coffee", ProxyPass: "http://vs_default_cafe_coffee$request_uri", ClientMaxBodySize: "2m"},
+		{Path: "/juice"},
+	}
+	crUpstreams := map[string]conf_v1.Upstream{
+		"vs_default_cafe_tea":    {Name: "tea"},
+		"vs_default_cafe_coffee": {Name: "coffee", ClientMaxBodySize: "2m"},
+	}
+
+	expected := []version2.Location{
+		{
+			Path:              "/tea",
+			ProxyPass:         "http://vs_default_cafe_tea",
+			ClientMaxBodySize: "10m",
+			ClientBodyTimeout: "20s",
+			AllowedMethods:    []string{"POST"},
+			MaxURILength:      2048,
+		},
+		{
+			Path:              "/coffee",
+			ProxyPass:         "http://vs_default_cafe_coffee$request_uri",
+			ClientMaxBodySize: "2m",
+			ClientBodyTimeout: "20s",
+			AllowedMethods:    []string{"POST"},
+			MaxURILength:      2048,
+		},
+		{
+			Path:           "/juice",
+			AllowedMethods: []string{"POST"},
+			MaxURILength:   2048,
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	vsc.addRequestLimitsToLocations(vs, limits, locations, crUpstreams)
+	if diff := cmp.Diff(expected, locations); diff != "" {
+		t.Errorf("addRequestLimitsToLocations() mismatch (-want +got):\n%s", diff)
+	}
+
+	expectedVSWarnings := Warnings{
+		vs: {"The maxBodySize of RequestLimits policy default/route-limits is ignored for upstream coffee, which sets client-max-body-size"},
+	}
+	if diff := cmp.Diff(expectedVSWarnings, vsc.warnings); diff != "" {
+		t.Errorf("addRequestLimitsToLocations() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateCircuitBreakerUpstreams(t *testing.T) {
+	t.Parallel()
+	maxFails := 3
+	policies := map[string]*conf_v1.Policy{
+		"default/cb": {
+			Spec: conf_v1.PolicySpec{
+				CircuitBreaker: &conf_v1.CircuitBreaker{
+					MaxFails:    &maxFails,
+					FailTimeout: "30s",
+				},
+			},
+		},
+	}
+	ups := version2.Upstream{
+		Name:        "vs_default_cafe_tea",
+		MaxFails:    1,
+		FailTimeout: "10s",
+	}
+
+	tests := []struct {
+		polKeys  []string
+		expected []version2.Upstream
+		msg      string
+	}{
+		{
+			polKeys: []string{""},
+			expected: []version2.Upstream{
+				{Name: "vs_default_cafe_tea", MaxFails: 1, FailTimeout: "10s"},
+			},
+			msg: "no circuit breaker",
 		},
 		{
-			m: version2.Map{
-				Source:   "$my_source_var",
-				Variable: "$my_targetvar",
-				Parameters: []version2.Parameter{
-					{
-						Value:  "default",
-						Result: "my_result1",
-					},
-					{
-						Value:  "other_value",
-						Result: "duplicate_value",
-					},
-					{
-						Value:  "other_value",
-						Result: "duplicate_value",
-					},
-				},
+			polKeys: []string{"default/cb"},
+			expected: []version2.Upstream{
+				{Name: "vs_default_cafe_tea", MaxFails: 3, FailTimeout: "30s"},
 			},
-			msg:      "has other duplicate values",
-			expected: false,
+			msg: "circuit breaker shared by all routes",
+		},
+		{
+			polKeys: []string{"", "default/cb"},
+			expected: []version2.Upstream{
+				{Name: "vs_default_cafe_tea", MaxFails: 1, FailTimeout: "10s"},
+				{Name: "vs_default_cafe_tea_cb_default_cb", MaxFails: 3, FailTimeout: "30s"},
+			},
+			msg: "circuit breaker of some routes",
 		},
 	}
 
 	for _, test := range tests {
-		result := hasDuplicateMapDefaults(&test.m)
-
-		if result != test.expected {
-			t.Errorf("hasDuplicateMapDefaults() returned \n%t, but expected \n%t for the case of %v", result, test.expected, test.msg)
+		var result []version2.Upstream
+		for _, cbUps := range generateCircuitBreakerUpstreams(ups, test.polKeys, policies) {
+			result = append(result, cbUps.upstream)
+		}
+		if !cmp.Equal(test.expected, result) {
+			t.Errorf("generateCircuitBreakerUpstreams() mismatch for the case of %s (-want +got):\n%s", test.msg, cmp.Diff(test.expected, result))
 		}
+	}
+}
 
+func TestSetCircuitBreakerUpstreams(t *testing.T) {
+	t.Parallel()
+	cbUpstreams := circuitBreakerUpstreams{
+		"vs_default_cafe_tea":    {"", "default/cb"},
+		"vs_default_cafe_coffee": {"default/cb"},
+	}
+	locations := []version2.Location{
+		{Path: "/tea-no-cb", ProxyPass: "http://vs_default_cafe_tea"},
+		{Path: "/tea", ProxyPass: "http://vs_default_cafe_tea$request_uri"},
+		{Path: "/coffee", ProxyPass: "http://vs_default_cafe_coffee"},
+	}
+	routes := []circuitBreakerRoute{
+		{key: "default/cb", firstLocation: 1, lastLocation: 3},
+	}
+
+	expected := []version2.Location{
+		{Path: "/tea-no-cb", ProxyPass: "http://vs_default_cafe_tea"},
+		{Path: "/tea", ProxyPass: "http://vs_default_cafe_tea_cb_default_cb$request_uri"},
+		{Path: "/coffee", ProxyPass: "http://vs_default_cafe_coffee"},
+	}
+
+	setCircuitBreakerUpstreams(locations, routes, cbUpstreams)
+	if !cmp.Equal(expected, locations) {
+		t.Errorf("setCircuitBreakerUpstreams() mismatch (-want +got):\n%s", cmp.Diff(expected, locations))
 	}
 }
 
-func TestAddPoliciesCfgToLocations(t *testing.T) {
+func TestGenerateUpstreamHealthCheckForCircuitBreaker(t *testing.T) {
 	t.Parallel()
-	cfg := policiesCfg{
-		Allow: []string{"127.0.0.1"},
-		Deny:  []string{"127.0.0.2"},
-		ErrorReturn: &version2.Return{
-			Code: 400,
+	cb := &conf_v1.CircuitBreaker{
+		Ejection: &conf_v1.CircuitBreakerEjection{
+			Consecutive5xx: 3,
+			Interval:       "10s",
+			Path:           "/healthz",
 		},
 	}
+	upstream := conf_v1.Upstream{Name: "tea"}
+	cfgParams := &ConfigParams{
+		Context:             context.Background(),
+		ProxyConnectTimeout: "60s",
+		ProxyReadTimeout:    "60s",
+		ProxySendTimeout:    "60s",
+	}
 
-	locations := []version2.Location{
-		{
-			Path: "/",
-		},
+	expectedHC := &version2.HealthCheck{
+		Name:                "vs_default_cafe_tea",
+		URI:                 "/healthz",
+		Interval:            "10s",
+		Jitter:              "0s",
+		KeepaliveTime:       "60s",
+		Fails:               3,
+		Passes:              1,
+		ProxyPass:           "http://vs_default_cafe_tea",
+		ProxyConnectTimeout: "60s",
+		ProxyReadTimeout:    "60s",
+		ProxySendTimeout:    "60s",
+		Headers:             map[string]string{},
+		Match:               "vs_default_cafe_tea_match",
+	}
+	expectedStatusMatch := &version2.StatusMatch{
+		Name: "vs_default_cafe_tea_match",
+		Code: "! 500-599",
 	}
 
-	expectedLocations := []version2.Location{
-		{
-			Path:  "/",
-			Allow: []string{"127.0.0.1"},
-			Deny:  []string{"127.0.0.2"},
-			PoliciesErrorReturn: &version2.Return{
-				Code: 400,
-			},
-		},
+	vsc := newVirtualServerConfigurator(cfgParams, true, false, &StaticConfigParams{}, false, &fakeBV)
+	hc, statusMatch := vsc.generateUpstreamHealthCheck(nil, upstream, "vs_default_cafe_tea", cb)
+	if !cmp.Equal(expectedHC, hc) {
+		t.Errorf("generateUpstreamHealthCheck() mismatch (-want +got):\n%s", cmp.Diff(expectedHC, hc))
+	}
+	if !cmp.Equal(expectedStatusMatch, statusMatch) {
+		t.Errorf("generateUpstreamHealthCheck() mismatch (-want +got):\n%s", cmp.Diff(expectedStatusMatch, statusMatch))
 	}
 
-	addPoliciesCfgToLocations(cfg, locations)
-	if !reflect.DeepEqual(locations, expectedLocations) {
-		t.Errorf("addPoliciesCfgToLocations() returned \n%+v but expected \n%+v", locations, expectedLocations)
+	vsc = newVirtualServerConfigurator(cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	hc, statusMatch = vsc.generateUpstreamHealthCheck(nil, upstream, "vs_default_cafe_tea", cb)
+	if hc != nil || statusMatch != nil {
+		t.Errorf("generateUpstreamHealthCheck() returned %v and %v for NGINX, expected no health check", hc, statusMatch)
 	}
 }
 
@@ -11275,6 +12996,47 @@ func TestGenerateProxyPass(t *testing.T) {
 	}
 }
 
+func TestGenerateProxyPassWithArgsRewrites(t *testing.T) {
+	t.Parallel()
+	rewriteArgs := &conf_v1.RewriteArgs{Remove: []string{"debug"}}
+	tests := []struct {
+		internal bool
+		proxy    *conf_v1.ActionProxy
+		expected string
+		msg      string
+	}{
+		{
+			proxy:    &conf_v1.ActionProxy{RewriteArgs: rewriteArgs},
+			expected: "http://test-upstream$request_uri_no_args$is_args$args",
+			msg:      "changed arguments without a rewrite",
+		},
+		{
+			internal: true,
+			proxy:    &conf_v1.ActionProxy{RewriteArgs: rewriteArgs},
+			expected: "http://test-upstream$request_uri_no_args$is_args$args",
+			msg:      "changed arguments without a rewrite in an internal location",
+		},
+		{
+			proxy:    &conf_v1.ActionProxy{RewriteArgs: rewriteArgs, RewritePath: "/rewrite"},
+			expected: "http://test-upstream",
+			msg:      "changed arguments with a rewrite",
+		},
+		{
+			internal: true,
+			proxy:    &conf_v1.ActionProxy{RewriteArgs: rewriteArgs, RewritePath: "/rewrite"},
+			expected: "http://test-upstream",
+			msg:      "changed arguments with a rewrite in an internal location",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateProxyPass(false, "test-upstream", test.internal, test.proxy)
+		if result != test.expected {
+			t.Errorf("generateProxyPass() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
 func TestGenerateProxyPassProtocol(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -11417,6 +13179,18 @@ func TestGenerateAuthJwtClaimSetVariable(t *testing.T) {
 			vsName:      "webapp",
 			expected:    "$jwt_default_webapp_a_b_c",
 		},
+		{
+			claim:       "sub",
+			vsNamespace: "cafe-ns",
+			vsName:      "cafe-app",
+			expected:    "$jwt_cafe_ns_cafe_app_sub",
+		},
+		{
+			claim:       "x-groups",
+			vsNamespace: "default",
+			vsName:      "webapp",
+			expected:    "$jwt_default_webapp_x_groups",
+		},
 	}
 
 	for _, test := range tests {
@@ -11795,6 +13569,251 @@ func TestGenerateLocationForRedirect(t *testing.T) {
 	}
 }
 
+func TestGenerateLocationForStatic(t *testing.T) {
+	t.Parallel()
+	static := &conf_v1.ActionStatic{
+		Path:     "cafe/docs",
+		Index:    []string{"index.html", "index.htm"},
+		TryFiles: []string{"$uri", "$uri/", "=404"},
+		Expires:  "1h",
+	}
+	errorPages := errorPageDetails{
+		pages: []conf_v1.ErrorPage{{Codes: []int{404}, Return: &conf_v1.ErrorPageReturn{ActionReturn: conf_v1.ActionReturn{Body: "Not Found"}}}},
+		index: 0,
+	}
+
+	tests := []struct {
+		path     string
+		expected version2.Location
+		msg      string
+	}{
+		{
+			path: "/docs",
+			expected: version2.Location{
+				Path:       "/docs",
+				Snippets:   []string{"# location snippet"},
+				ErrorPages: []version2.ErrorPage{{Name: "@error_page_0_0", Codes: "404"}},
+				URIPattern: "^/docs(/|$)",
+				Static: &version2.Static{
+					Alias:    "/etc/nginx/static/cafe/docs",
+					Index:    []string{"index.html", "index.htm"},
+					TryFiles: []string{"$uri", "$uri/", "=404"},
+					Expires:  "1h",
+				},
+			},
+			msg: "prefix path",
+		},
+		{
+			path: "/docs/",
+			expected: version2.Location{
+				Path:       "/docs/",
+				Snippets:   []string{"# location snippet"},
+				ErrorPages: []version2.ErrorPage{{Name: "@error_page_0_0", Codes: "404"}},
+				Static: &version2.Static{
+					Alias:    "/etc/nginx/static/cafe/docs/",
+					Index:    []string{"index.html", "index.htm"},
+					TryFiles: []string{"$uri", "$uri/", "=404"},
+					Expires:  "1h",
+				},
+			},
+			msg: "prefix path with a trailing slash",
+		},
+		{
+			path: `~ \.(css|js)$`,
+			expected: version2.Location{
+				Path:       `~ "\.(css|js)$"`,
+				Snippets:   []string{"# location snippet"},
+				ErrorPages: []version2.ErrorPage{{Name: "@error_page_0_0", Codes: "404"}},
+				Static: &version2.Static{
+					Root:     "/etc/nginx/static/cafe/docs",
+					Index:    []string{"index.html", "index.htm"},
+					TryFiles: []string{"$uri", "$uri/", "=404"},
+					Expires:  "1h",
+				},
+			},
+			msg: "regex path",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateLocationForStatic(test.path, []string{"# location snippet"}, static, errorPages)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateLocationForStatic() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateLocationForStaticServesFilesWithinDirectory(t *testing.T) {
+	t.Parallel()
+	static := &conf_v1.ActionStatic{Path: "cafe/docs"}
+	dir := "/etc/nginx/static/cafe/docs"
+
+	// the file of a request is located like NGINX does: the location matches the prefix of the URI,
+	// and the alias replaces the prefix.
+	for _, path := range []string{"/docs", "/docs/"} {
+		loc := generateLocationForStatic(path, nil, static, errorPageDetails{})
+		for _, uri := range []string{"/docs", "/docs/", "/docs/index.html", "/docs../", "/docs../cafe/secret", "/docsfoo", "/docs-private/secret"} {
+			if !strings.HasPrefix(uri, path) {
+				continue
+			}
+			if loc.URIPattern != "" && !regexp.MustCompile(loc.URIPattern).MatchString(uri) {
+				continue
+			}
+			file := filepath.Clean(loc.Static.Alias + strings.TrimPrefix(uri, path))
+			if file != dir && !strings.HasPrefix(file, dir+"/") {
+				t.Errorf("generateLocationForStatic() for the path %s serves the URI %s from the file %s outside of the directory %s", path, uri, file, dir)
+			}
+		}
+	}
+}
+
+func TestAddObjectStorageToLocationWithRegexMetacharactersInPath(t *testing.T) {
+	t.Parallel()
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "cafe", Namespace: "default"},
+	}
+	objectStorage := &conf_v1.ActionObjectStorage{
+		Upstream: "minio",
+		Bucket:   "assets",
+		Secret:   "minio-credentials",
+	}
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/minio-credentials": {
+			Secret: &api_v1.Secret{
+				Type: secrets.SecretTypeObjectStorage,
+			},
+		},
+	}
+
+	loc := version2.Location{Path: "/v1.0+(beta)", ProxyPass: "http://vs_default_cafe_minio"}
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	vsc.addObjectStorageToLocation(vs, &loc, "/v1.0+(beta)", objectStorage, "default", "minio.default.svc", secretRefs)
+
+	expectedRewrites := []string{`"^/v1\.0\+\(beta\)(?:/(.*))?$" "/$1" break`}
+	if diff := cmp.Diff(expectedRewrites, loc.Rewrites); diff != "" {
+		t.Errorf("addObjectStorageToLocation() mismatch of rewrites (-want +got):\n%s", diff)
+	}
+	expectedURIPattern := `^/v1\.0\+\(beta\)(/|$)`
+	if loc.URIPattern != expectedURIPattern {
+		t.Errorf("addObjectStorageToLocation() returned the URI pattern %q, expected %q", loc.URIPattern, expectedURIPattern)
+	}
+
+	uriPattern := regexp.MustCompile(loc.URIPattern)
+	keyPattern := regexp.MustCompile(`^/v1\.0\+\(beta\)(?:/(.*))?$`)
+	tests := []struct {
+		uri     string
+		allowed bool
+		key     string
+	}{
+		{uri: "/v1.0+(beta)/images/logo.png", allowed: true, key: "images/logo.png"},
+		{uri: "/v1.0+(beta)", allowed: true, key: ""},
+		{uri: "/v1.0+(beta)../secret", allowed: false},
+		{uri: "/v1.0+(beta)foo", allowed: false},
+		{uri: "/v1x0+(beta)/images/logo.png", allowed: false},
+	}
+	for _, test := range tests {
+		if uriPattern.MatchString(test.uri) != test.allowed {
+			t.Errorf("the URI pattern %q allows the URI %s: %v, expected %v", loc.URIPattern, test.uri, !test.allowed, test.allowed)
+			continue
+		}
+		if !test.allowed {
+			continue
+		}
+		if key := keyPattern.FindStringSubmatch(test.uri)[1]; key != test.key {
+			t.Errorf("the rewrite of the URI %s returned the object key %q, expected %q", test.uri, key, test.key)
+		}
+	}
+}
+
+func TestAddObjectStorageToLocation(t *testing.T) {
+	t.Parallel()
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "cafe", Namespace: "default"},
+	}
+	objectStorage := &conf_v1.ActionObjectStorage{
+		Upstream: "minio",
+		Bucket:   "assets",
+		Secret:   "minio-credentials",
+	}
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/minio-credentials": {
+			Secret: &api_v1.Secret{
+				Type: secrets.SecretTypeObjectStorage,
+				Data: map[string][]byte{
+					"access-key-id":     []byte("minioadmin"),
+					"secret-access-key": []byte("miniosecret"),
+				},
+			},
+		},
+		"default/tls-secret": {
+			Secret: &api_v1.Secret{
+				Type: api_v1.SecretTypeTLS,
+			},
+		},
+	}
+
+	loc := version2.Location{Path: "/assets/", ProxyPass: "http://vs_default_cafe_minio"}
+	expected := version2.Location{
+		Path:                    "/assets/",
+		ProxyPass:               "http://vs_default_cafe_minio",
+		ProxyPassRewrite:        "$object_storage_uri",
+		ProxyPassRequestHeaders: false,
+		ProxySetHeaders: []version2.Header{
+			{Name: "Host", Value: "$object_storage_host"},
+			{Name: "x-amz-date", Value: "$object_storage_date"},
+			{Name: "x-amz-content-sha256", Value: "UNSIGNED-PAYLOAD"},
+			{Name: "Authorization", Value: "$object_storage_authorization"},
+			{Name: "Range", Value: "$http_range"},
+			{Name: "If-Match", Value: "$http_if_match"},
+			{Name: "If-None-Match", Value: "$http_if_none_match"},
+			{Name: "If-Modified-Since", Value: "$http_if_modified_since"},
+			{Name: "If-Unmodified-Since", Value: "$http_if_unmodified_since"},
+		},
+		ProxyHideHeaders: []string{"x-amz-id-2", "x-amz-request-id"},
+		Rewrites:         []string{`"^/assets(?:/(.*))?$" "/$1" break`},
+		ObjectStorage: &version2.ObjectStorage{
+			Host:            "minio.default.svc",
+			Bucket:          "assets",
+			Region:          "us-east-1",
+			AccessKeyID:     "minioadmin",
+			SecretAccessKey: "miniosecret",
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	vsc.addObjectStorageToLocation(vs, &loc, "/assets/", objectStorage, "default", "minio.default.svc", secretRefs)
+	if diff := cmp.Diff(expected, loc); diff != "" {
+		t.Errorf("addObjectStorageToLocation() mismatch (-want +got):\n%s", diff)
+	}
+	if len(vsc.warnings) > 0 {
+		t.Errorf("addObjectStorageToLocation() returned unexpected warnings %v", vsc.warnings)
+	}
+
+	invalidStorage := &conf_v1.ActionObjectStorage{
+		Upstream: "minio",
+		Bucket:   "assets",
+		Secret:   "tls-secret",
+	}
+	loc = version2.Location{Path: "/assets/", ProxyPass: "http://vs_default_cafe_minio"}
+	expected = version2.Location{
+		Path:                "/assets/",
+		ProxyPass:           "http://vs_default_cafe_minio",
+		PoliciesErrorReturn: &version2.Return{Code: 500},
+	}
+	expectedWarnings := Warnings{
+		vs: {"ObjectStorage action of the route /assets/ references a secret default/tls-secret of a wrong type 'kubernetes.io/tls', must be 'nginx.org/object-storage'"},
+	}
+
+	vsc = newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	vsc.addObjectStorageToLocation(vs, &loc, "/assets/", invalidStorage, "default", "minio.default.svc", secretRefs)
+	if diff := cmp.Diff(expected, loc); diff != "" {
+		t.Errorf("addObjectStorageToLocation() mismatch for the secret of a wrong type (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedWarnings, vsc.warnings); diff != "" {
+		t.Errorf("addObjectStorageToLocation() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
 func TestGenerateSSLConfig(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -11802,6 +13821,7 @@ func TestGenerateSSLConfig(t *testing.T) {
 		inputSecretRefs  map[string]*secrets.SecretReference
 		inputCfgParams   *ConfigParams
 		wildcard         bool
+		now              time.Time
 		expectedSSL      *version2.SSL
 		expectedWarnings Warnings
 		msg              string
@@ -11907,12 +13927,69 @@ func TestGenerateSSLConfig(t *testing.T) {
 			expectedWarnings: Warnings{},
 			msg:              "normal case with HTTPS",
 		},
+		{
+			inputTLS: &conf_v1.TLS{
+				Secret: "expired",
+			},
+			inputSecretRefs: map[string]*secrets.SecretReference{
+				"default/expired": {
+					Secret: &api_v1.Secret{
+						Type: api_v1.SecretTypeTLS,
+						Data: map[string][]byte{
+							"tls.crt": expiredCert,
+						},
+					},
+					Path: "expired.pem",
+				},
+			},
+			inputCfgParams: &ConfigParams{Context: context.Background()},
+			wildcard:       false,
+			now:            time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expectedSSL: &version2.SSL{
+				HTTP2:           false,
+				Certificate:     "expired.pem",
+				CertificateKey:  "expired.pem",
+				RejectHandshake: false,
+			},
+			expectedWarnings: Warnings{
+				nil: []string{"TLS secret expired holds a certificate that expired on 2023-09-11T16:15:35Z"},
+			},
+			msg: "expired certificate with HTTPS",
+		},
+		{
+			inputTLS: &conf_v1.TLS{
+				Secret: "expired",
+			},
+			inputSecretRefs: map[string]*secrets.SecretReference{
+				"default/expired": {
+					Secret: &api_v1.Secret{
+						Type: api_v1.SecretTypeTLS,
+						Data: map[string][]byte{
+							"tls.crt": expiredCert,
+						},
+					},
+					Path: "expired.pem",
+				},
+			},
+			inputCfgParams: &ConfigParams{Context: context.Background()},
+			wildcard:       false,
+			now:            time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expectedSSL: &version2.SSL{
+				HTTP2:           false,
+				Certificate:     "expired.pem",
+				CertificateKey:  "expired.pem",
+				RejectHandshake: false,
+			},
+			expectedWarnings: Warnings{},
+			msg:              "certificate not expired yet with HTTPS",
+		},
 	}
 
 	namespace := "default"
 
 	for _, test := range tests {
 		vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, test.wildcard, &fakeBV)
+		vsc.now = test.now
 
 		// it is ok to use nil as the owner
 		result := vsc.generateSSLConfig(nil, test.inputTLS, namespace, test.inputSecretRefs, test.inputCfgParams)
@@ -14286,16 +16363,101 @@ func TestGenerateDefaultSplitsConfig(t *testing.T) {
 				VSRNamespace:             "default",
 			},
 		},
-		InternalRedirectLocation: version2.InternalRedirectLocation{
-			Path:        "/",
-			Destination: "$vs_default_cafe_splits_1",
+		InternalRedirectLocation: version2.InternalRedirectLocation{
+			Path:        "/",
+			Destination: "$vs_default_cafe_splits_1",
+		},
+	}
+
+	cfgParams := ConfigParams{Context: context.Background()}
+	locSnippet := ""
+	enableSnippets := false
+	weightChangesDynamicReload := false
+	crUpstreams := map[string]conf_v1.Upstream{
+		"vs_default_cafe_coffee-v1": {
+			Service: "coffee-v1",
+		},
+		"vs_default_cafe_coffee-v2": {
+			Service: "coffee-v2",
+		},
+	}
+
+	errorPageDetails := errorPageDetails{
+		pages: route.ErrorPages,
+		index: 0,
+		owner: nil,
+	}
+
+	result := generateDefaultSplitsConfig(route, upstreamNamer, crUpstreams, variableNamer, index, &cfgParams,
+		errorPageDetails, "", locSnippet, enableSnippets, 0, true, "coffee", "default", Warnings{}, weightChangesDynamicReload)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateDefaultSplitsConfig() returned \n%+v but expected \n%+v", result, expected)
+	}
+}
+
+func TestGenerateDefaultSplitsConfigForCanary(t *testing.T) {
+	t.Parallel()
+	route := conf_v1.Route{
+		Path: "/",
+		Action: &conf_v1.Action{
+			Pass: "coffee-v1",
+		},
+		Canary: &conf_v1.Canary{
+			Weight: 10,
+			Action: &conf_v1.Action{
+				Pass: "coffee-v2",
+			},
+			ForceHeader: "X-Canary",
+			ForceCookie: "canary_force",
+			StickyCookie: &conf_v1.CanaryStickyCookie{
+				Name:    "canary",
+				Expires: "1h",
+			},
+		},
+	}
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := NewUpstreamNamerForVirtualServer(&virtualServer)
+	variableNamer := NewVSVariableNamer(&virtualServer)
+	index := 1
+
+	source := `"$http_x_canary|$cookie_canary_force|$cookie_canary"`
+	skipParams := []version2.Parameter{
+		{Value: `"~^(always|never)\|"`, Result: `""`},
+		{Value: `"~^[^|]*\|(always|never)\|"`, Result: `""`},
+		{Value: `"~\|(stable|canary)$"`, Result: `""`},
+	}
+	expectedMaps := []version2.Map{
+		{
+			Source:   source,
+			Variable: "$vs_default_cafe_canary_1",
+			Parameters: []version2.Parameter{
+				{Value: `"~^always\|"`, Result: "/internal_location_splits_1_split_1"},
+				{Value: `"~^never\|"`, Result: "/internal_location_splits_1_split_0"},
+				{Value: `"~^[^|]*\|always\|"`, Result: "/internal_location_splits_1_split_1"},
+				{Value: `"~^[^|]*\|never\|"`, Result: "/internal_location_splits_1_split_0"},
+				{Value: `"~\|stable$"`, Result: "/internal_location_splits_1_split_0"},
+				{Value: `"~\|canary$"`, Result: "/internal_location_splits_1_split_1"},
+				{Value: "default", Result: "$vs_default_cafe_splits_1"},
+			},
+		},
+		{
+			Source:     source,
+			Variable:   "$vs_default_cafe_canary_1_sticky_cookie_0",
+			Parameters: append(append([]version2.Parameter{}, skipParams...), version2.Parameter{Value: "default", Result: `"canary=stable; Path=/; Max-Age=3600"`}),
+		},
+		{
+			Source:     source,
+			Variable:   "$vs_default_cafe_canary_1_sticky_cookie_1",
+			Parameters: append(append([]version2.Parameter{}, skipParams...), version2.Parameter{Value: "default", Result: `"canary=canary; Path=/; Max-Age=3600"`}),
 		},
 	}
 
 	cfgParams := ConfigParams{Context: context.Background()}
-	locSnippet := ""
-	enableSnippets := false
-	weightChangesDynamicReload := false
 	crUpstreams := map[string]conf_v1.Upstream{
 		"vs_default_cafe_coffee-v1": {
 			Service: "coffee-v1",
@@ -14304,7 +16466,6 @@ func TestGenerateDefaultSplitsConfig(t *testing.T) {
 			Service: "coffee-v2",
 		},
 	}
-
 	errorPageDetails := errorPageDetails{
 		pages: route.ErrorPages,
 		index: 0,
@@ -14312,9 +16473,53 @@ func TestGenerateDefaultSplitsConfig(t *testing.T) {
 	}
 
 	result := generateDefaultSplitsConfig(route, upstreamNamer, crUpstreams, variableNamer, index, &cfgParams,
-		errorPageDetails, "", locSnippet, enableSnippets, 0, true, "coffee", "default", Warnings{}, weightChangesDynamicReload)
-	if !reflect.DeepEqual(result, expected) {
-		t.Errorf("generateDefaultSplitsConfig() returned \n%+v but expected \n%+v", result, expected)
+		errorPageDetails, "", "", false, 0, false, "", "", Warnings{}, false)
+
+	expectedSplitClients := []version2.SplitClient{
+		{
+			Source:   "$request_id",
+			Variable: "$vs_default_cafe_splits_1",
+			Distributions: []version2.Distribution{
+				{Weight: "90%", Value: "/internal_location_splits_1_split_0"},
+				{Weight: "10%", Value: "/internal_location_splits_1_split_1"},
+			},
+		},
+	}
+	if !cmp.Equal(expectedSplitClients, result.SplitClients) {
+		t.Errorf("generateDefaultSplitsConfig() returned unexpected split clients (-want +got):\n%s", cmp.Diff(expectedSplitClients, result.SplitClients))
+	}
+	if !cmp.Equal(expectedMaps, result.Maps) {
+		t.Errorf("generateDefaultSplitsConfig() returned unexpected maps (-want +got):\n%s", cmp.Diff(expectedMaps, result.Maps))
+	}
+
+	expectedIRL := version2.InternalRedirectLocation{Path: "/", Destination: "$vs_default_cafe_canary_1"}
+	if result.InternalRedirectLocation != expectedIRL {
+		t.Errorf("generateDefaultSplitsConfig() returned internal redirect location %+v but expected %+v", result.InternalRedirectLocation, expectedIRL)
+	}
+
+	for i, loc := range result.Locations {
+		expectedAddHeaders := []version2.AddHeader{
+			{
+				Header: version2.Header{Name: "Set-Cookie", Value: fmt.Sprintf("$vs_default_cafe_canary_1_sticky_cookie_%d", i)},
+				Always: true,
+			},
+		}
+		if !cmp.Equal(expectedAddHeaders, loc.AddHeaders) {
+			t.Errorf("generateDefaultSplitsConfig() returned unexpected headers for location %s (-want +got):\n%s", loc.Path, cmp.Diff(expectedAddHeaders, loc.AddHeaders))
+		}
+	}
+
+	// with dynamic weight changes, the canary map falls back to the map of the weights
+	result = generateDefaultSplitsConfig(route, upstreamNamer, crUpstreams, variableNamer, index, &cfgParams,
+		errorPageDetails, "", "", false, 0, false, "", "", Warnings{}, true)
+
+	if len(result.TwoWaySplitClients) != 1 || !cmp.Equal([]int{90, 10}, result.TwoWaySplitClients[0].Weights) {
+		t.Errorf("generateDefaultSplitsConfig() returned two way split clients %+v but expected the weights 90 and 10", result.TwoWaySplitClients)
+	}
+	canaryMap := result.Maps[len(result.Maps)-3]
+	defaultParam := canaryMap.Parameters[len(canaryMap.Parameters)-1]
+	if canaryMap.Variable != "$vs_default_cafe_canary_1" || defaultParam.Result != "$vs_default_cafe_map_split_clients_1" {
+		t.Errorf("generateDefaultSplitsConfig() returned the canary map %+v but expected the default result $vs_default_cafe_map_split_clients_1", canaryMap)
 	}
 }
 
@@ -15852,6 +18057,31 @@ func TestGenerateEndpointsForUpstream(t *testing.T) {
 			expected:             []string{nginx502Server},
 			msg:                  "Upstream with subselector, without a matching endpoint",
 		},
+		{
+			upstream: conf_v1.Upstream{
+				Service: name,
+				Port:    8080,
+			},
+			vsEx: &VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      name,
+						Namespace: namespace,
+					},
+				},
+				Endpoints: map[string][]string{
+					"test-namespace/test:8080": {"192.168.10.10:8080"},
+				},
+				DeniedReferences: map[string]error{
+					"VirtualServer/test-namespace/Service/test-namespace/test": errors.New("not allowed"),
+				},
+			},
+			isPlus:               false,
+			isResolverConfigured: false,
+			warningsExpected:     true,
+			expected:             []string{nginx502Server},
+			msg:                  "Service that is not allowed to be referenced",
+		},
 	}
 
 	for _, test := range tests {
@@ -15886,6 +18116,54 @@ func TestGenerateEndpointsForUpstream(t *testing.T) {
 	}
 }
 
+func TestGetUpstreamNamespace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		upstream conf_v1.Upstream
+		expected string
+	}{
+		{
+			upstream: conf_v1.Upstream{Service: "tea-svc"},
+			expected: "default",
+		},
+		{
+			upstream: conf_v1.Upstream{Service: "tea-svc", ServiceNamespace: "backend"},
+			expected: "backend",
+		},
+	}
+
+	for _, test := range tests {
+		result := GetUpstreamNamespace(test.upstream, "default")
+		if result != test.expected {
+			t.Errorf("GetUpstreamNamespace(%+v) returned %q but expected %q", test.upstream, result, test.expected)
+		}
+	}
+}
+
+func TestGeneratePolicySecretKey(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		secret   string
+		expected string
+	}{
+		{
+			secret:   "jwk-secret",
+			expected: "default/jwk-secret",
+		},
+		{
+			secret:   "secrets/jwk-secret",
+			expected: "secrets/jwk-secret",
+		},
+	}
+
+	for _, test := range tests {
+		result := GeneratePolicySecretKey("default", test.secret)
+		if result != test.expected {
+			t.Errorf("GeneratePolicySecretKey(%q) returned %q but expected %q", test.secret, result, test.expected)
+		}
+	}
+}
+
 func TestGenerateSlowStartForPlusWithInCompatibleLBMethods(t *testing.T) {
 	t.Parallel()
 	serviceName := "test-slowstart-with-incompatible-LBMethods"
@@ -16489,6 +18767,30 @@ func TestGenerateRewrites(t *testing.T) {
 			expected:     []string{`^ $request_uri break`},
 			msg:          "empty rewrite for internal location with grpc enabled",
 		},
+		{
+			path: "/path",
+			proxy: &conf_v1.ActionProxy{
+				RewritePath: "/tenants/${http_x_tenant}",
+			},
+			expected: []string{`"^/path(.*)$" "/tenants/${http_x_tenant}$1" break`},
+			msg:      "non-regex rewrite with variables for non-internal location",
+		},
+		{
+			path: "=/path",
+			proxy: &conf_v1.ActionProxy{
+				RewritePath: "/tenants/${http_x_tenant}",
+			},
+			expected: []string{`"^/path(.*)$" "/tenants/${http_x_tenant}$1" break`},
+			msg:      "exact rewrite with variables for non-internal location",
+		},
+		{
+			path: `~ ^/users/(?<id>\d+)`,
+			proxy: &conf_v1.ActionProxy{
+				RewritePath: "/api/users/${id}",
+			},
+			expected: []string{`"^^/users/(?<id>\d+)" "/api/users/${id}" break`},
+			msg:      "regex rewrite with named captures for non-internal location",
+		},
 	}
 
 	for _, test := range tests {
@@ -16538,6 +18840,13 @@ func TestGenerateProxyPassRewrite(t *testing.T) {
 			},
 			expected: "",
 		},
+		{
+			path: "/path",
+			proxy: &conf_v1.ActionProxy{
+				RewritePath: "/rewrite/${host}",
+			},
+			expected: "",
+		},
 	}
 
 	for _, test := range tests {
@@ -16549,6 +18858,92 @@ func TestGenerateProxyPassRewrite(t *testing.T) {
 	}
 }
 
+func TestGenerateArgsRewrites(t *testing.T) {
+	t.Parallel()
+	proxy := &conf_v1.ActionProxy{
+		RewriteArgs: &conf_v1.RewriteArgs{
+			Set:    []conf_v1.Argument{{Name: "tenant", Value: "${http_x_tenant}"}},
+			Remove: []string{"debug"},
+		},
+	}
+	expected := []version2.ArgsRewrite{
+		{Value: "${args}&tenant=${http_x_tenant}"},
+		{Regex: "^&*(.*?)&*$", Value: "$1"},
+	}
+
+	result := generateArgsRewrites(proxy)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("generateArgsRewrites() mismatch (-want +got):\n%s", diff)
+	}
+
+	if result := generateArgsRewrites(&conf_v1.ActionProxy{}); result != nil {
+		t.Errorf("generateArgsRewrites() returned %v for a proxy without rewriteArgs, expected nil", result)
+	}
+}
+
+func TestGenerateRemovedArgs(t *testing.T) {
+	t.Parallel()
+	proxy := &conf_v1.ActionProxy{
+		RewriteArgs: &conf_v1.RewriteArgs{
+			Set:    []conf_v1.Argument{{Name: "tenant", Value: "${http_x_tenant}"}},
+			Remove: []string{"debug", "trace"},
+		},
+	}
+
+	// the arguments to set are removed too, so that the values of the clients don't reach the upstream
+	if result := generateRemovedArgs(proxy); result != "debug trace tenant" {
+		t.Errorf("generateRemovedArgs() returned %q, expected %q", result, "debug trace tenant")
+	}
+
+	if result := generateRemovedArgs(&conf_v1.ActionProxy{}); result != "" {
+		t.Errorf("generateRemovedArgs() returned %q for a proxy without rewriteArgs, expected an empty string", result)
+	}
+}
+
+func TestGenerateSubFilters(t *testing.T) {
+	t.Parallel()
+	proxy := &conf_v1.ActionProxy{
+		ResponseBodyRewrite: []conf_v1.ResponseBodyRewrite{
+			{
+				Match:       "http://legacy.internal",
+				Replacement: "https://cafe.example.com",
+				Once:        true,
+				Types:       []string{"application/json"},
+			},
+			{
+				Match:       "legacy.internal",
+				Replacement: "cafe.example.com",
+				Once:        true,
+				Types:       []string{"application/json", "text/css"},
+			},
+		},
+	}
+
+	expectedFilters := []version2.SubFilter{
+		{Match: "http://legacy.internal", Replacement: "https://cafe.example.com"},
+		{Match: "legacy.internal", Replacement: "cafe.example.com"},
+	}
+	if diff := cmp.Diff(expectedFilters, generateSubFilters(proxy)); diff != "" {
+		t.Errorf("generateSubFilters() mismatch (-want +got):\n%s", diff)
+	}
+	if !generateSubFilterOnce(proxy) {
+		t.Error("generateSubFilterOnce() returned false, expected true")
+	}
+	expectedTypes := []string{"application/json", "text/css"}
+	if diff := cmp.Diff(expectedTypes, generateSubFilterTypes(proxy)); diff != "" {
+		t.Errorf("generateSubFilterTypes() mismatch (-want +got):\n%s", diff)
+	}
+
+	expectedHeaders := []version2.Header{{Name: "Host", Value: "$host"}, {Name: "Accept-Encoding", Value: ""}}
+	if diff := cmp.Diff(expectedHeaders, generateProxySetHeaders(proxy)); diff != "" {
+		t.Errorf("generateProxySetHeaders() mismatch (-want +got):\n%s", diff)
+	}
+
+	if filters := generateSubFilters(nil); filters != nil {
+		t.Errorf("generateSubFilters(nil) returned %v, expected nil", filters)
+	}
+}
+
 func TestGenerateProxySetHeaders(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -16643,6 +19038,56 @@ func TestGenerateProxySetHeaders(t *testing.T) {
 			},
 			msg: "set headers with host in mixed case",
 		},
+		{
+			proxy: &conf_v1.ActionProxy{
+				ResponseBodyRewrite: []conf_v1.ResponseBodyRewrite{
+					{
+						Match:       "legacy.internal",
+						Replacement: "cafe.example.com",
+					},
+				},
+			},
+			expected: []version2.Header{
+				{
+					Name:  "Host",
+					Value: "$host",
+				},
+				{
+					Name:  "Accept-Encoding",
+					Value: "",
+				},
+			},
+			msg: "response body rewrite",
+		},
+		{
+			proxy: &conf_v1.ActionProxy{
+				RequestHeaders: &conf_v1.ProxyRequestHeaders{
+					Set: []conf_v1.Header{
+						{
+							Name:  "accept-encoding",
+							Value: "identity",
+						},
+					},
+				},
+				ResponseBodyRewrite: []conf_v1.ResponseBodyRewrite{
+					{
+						Match:       "legacy.internal",
+						Replacement: "cafe.example.com",
+					},
+				},
+			},
+			expected: []version2.Header{
+				{
+					Name:  "accept-encoding",
+					Value: "identity",
+				},
+				{
+					Name:  "Host",
+					Value: "$host",
+				},
+			},
+			msg: "response body rewrite with accept-encoding set",
+		},
 		{
 			proxy: &conf_v1.ActionProxy{
 				RequestHeaders: &conf_v1.ProxyRequestHeaders{
@@ -17983,3 +20428,176 @@ func TestRFC1123ToSnake(t *testing.T) {
 		})
 	}
 }
+
+// expiredCert is a self-signed certificate that expired on 2023-09-11T16:15:35Z.
+var expiredCert = []byte(`-----BEGIN CERTIFICATE-----
+MIIDLjCCAhYCCQDAOF9tLsaXWjANBgkqhkiG9w0BAQsFADBaMQswCQYDVQQGEwJV
+UzELMAkGA1UECAwCQ0ExITAfBgNVBAoMGEludGVybmV0IFdpZGdpdHMgUHR5IEx0
+ZDEbMBkGA1UEAwwSY2FmZS5leGFtcGxlLmNvbSAgMB4XDTE4MDkxMjE2MTUzNVoX
+DTIzMDkxMTE2MTUzNVowWDELMAkGA1UEBhMCVVMxCzAJBgNVBAgMAkNBMSEwHwYD
+VQQKDBhJbnRlcm5ldCBXaWRnaXRzIFB0eSBMdGQxGTAXBgNVBAMMEGNhZmUuZXhh
+bXBsZS5jb20wggEiMA0GCSqGSIb3DQEBAQUAA4IBDwAwggEKAoIBAQCp6Kn7sy81
+p0juJ/cyk+vCAmlsfjtFM2muZNK0KtecqG2fjWQb55xQ1YFA2XOSwHAYvSdwI2jZ
+ruW8qXXCL2rb4CZCFxwpVECrcxdjm3teViRXVsYImmJHPPSyQgpiobs9x7DlLc6I
+BA0ZjUOyl0PqG9SJexMV73WIIa5rDVSF2r4kSkbAj4Dcj7LXeFlVXH2I5XwXCptC
+n67JCg42f+k8wgzcRVp8XZkZWZVjwq9RUKDXmFB2YyN1XEWdZ0ewRuKYUJlsm692
+skOrKQj0vkoPn41EE/+TaVEpqLTRoUY3rzg7DkdzfdBizFO2dsPNFx2CW0jXkNLv
+Ko25CZrOhXAHAgMBAAEwDQYJKoZIhvcNAQELBQADggEBAKHFCcyOjZvoHswUBMdL
+RdHIb383pWFynZq/LuUovsVA58B0Cg7BEfy5vWVVrq5RIkv4lZ81N29x21d1JH6r
+jSnQx+DXCO/TJEV5lSCUpIGzEUYaUPgRyjsM/NUdCJ8uHVhZJ+S6FA+CnOD9rn2i
+ZBePCI5rHwEXwnnl8ywij3vvQ5zHIuyBglWr/Qyui9fjPpwWUvUm4nv5SMG9zCV7
+PpuwvuatqjO1208BjfE/cZHIg8Hw9mvW9x9C+IQMIMDE7b/g6OcK7LGTLwlFxvA8
+7WjEequnayIphMhKRXVf1N349eN98Ez38fOTHTPbdJjFA/PcC+Gyme+iGt5OQdFh
+yRE=
+-----END CERTIFICATE-----`)
+
+func TestGenerateCompression(t *testing.T) {
+	t.Parallel()
+	vsCompression := &conf_v1.Compression{
+		Enable:    true,
+		Encodings: []string{"gzip", "br"},
+		Types:     []string{"application/json"},
+		MinLength: createPointerFromInt(1000),
+		Level:     createPointerFromInt(5),
+		Static:    true,
+	}
+	staticModules := map[string]bool{"gzip": true, "brotli": true}
+
+	tests := []struct {
+		compression   *conf_v1.Compression
+		vsCompression *conf_v1.Compression
+		staticModules map[string]bool
+		expected      []version2.Compression
+		msg           string
+	}{
+		{
+			compression: nil,
+			expected:    nil,
+			msg:         "no compression",
+		},
+		{
+			compression: &conf_v1.Compression{Enable: true},
+			expected: []version2.Compression{
+				{Module: "gzip", Enable: true},
+			},
+			msg: "gzip by default",
+		},
+		{
+			compression:   vsCompression,
+			staticModules: staticModules,
+			expected: []version2.Compression{
+				{Module: "gzip", Enable: true, Types: []string{"application/json"}, MinLength: "1000", Level: "5", Static: true},
+				{Module: "brotli", Enable: true, Types: []string{"application/json"}, MinLength: "1000", Level: "5", Static: true},
+			},
+			msg: "server compression",
+		},
+		{
+			compression:   vsCompression,
+			staticModules: map[string]bool{"gzip": true},
+			expected: []version2.Compression{
+				{Module: "gzip", Enable: true, Types: []string{"application/json"}, MinLength: "1000", Level: "5", Static: true},
+				{Module: "brotli", Enable: true, Types: []string{"application/json"}, MinLength: "1000", Level: "5"},
+			},
+			msg: "precompressed files without the brotli static module",
+		},
+		{
+			compression: &conf_v1.Compression{Enable: true, Encodings: []string{"br"}, Level: createPointerFromInt(0)},
+			expected: []version2.Compression{
+				{Module: "gzip"},
+				{Module: "brotli", Enable: true, Level: "0"},
+			},
+			msg: "minimum brotli level",
+		},
+		{
+			compression:   &conf_v1.Compression{Enable: false},
+			vsCompression: vsCompression,
+			expected: []version2.Compression{
+				{Module: "gzip"},
+				{Module: "brotli"},
+			},
+			msg: "route turns off the compression of the VirtualServer",
+		},
+		{
+			compression:   &conf_v1.Compression{Enable: true, Encodings: []string{"gzip"}, MinLength: createPointerFromInt(0)},
+			vsCompression: vsCompression,
+			expected: []version2.Compression{
+				{Module: "gzip", Enable: true, MinLength: "0"},
+				{Module: "brotli"},
+			},
+			msg: "route overrides the encodings of the VirtualServer",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateCompression(test.compression, test.vsCompression, test.staticModules)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateCompression() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateMaintenance(t *testing.T) {
+	t.Parallel()
+	meta := meta_v1.ObjectMeta{Name: "cafe-app", Namespace: "default"}
+
+	tests := []struct {
+		maintenance *conf_v1.Maintenance
+		expected    *version2.Maintenance
+		msg         string
+	}{
+		{
+			maintenance: nil,
+			expected:    nil,
+			msg:         "no maintenance",
+		},
+		{
+			maintenance: &conf_v1.Maintenance{Enable: false, AllowList: []string{"10.0.0.0/8"}},
+			expected:    nil,
+			msg:         "disabled maintenance",
+		},
+		{
+			maintenance: &conf_v1.Maintenance{Enable: true},
+			expected: &version2.Maintenance{
+				Variable:     "$vs_maintenance_default_cafe_app",
+				AddrVariable: "$vs_maintenance_default_cafe_app",
+				Location:     "/internal_location_maintenance",
+				DefaultType:  "text/plain",
+				Body:         "Service is under maintenance",
+			},
+			msg: "default maintenance",
+		},
+		{
+			maintenance: &conf_v1.Maintenance{
+				Enable:     true,
+				AllowList:  []string{"10.0.0.0/8"},
+				Header:     &conf_v1.MaintenanceHeader{Name: "X-Maintenance-Token", Token: "c2VjcmV0"},
+				Body:       `{\"status\": \"maintenance\"}`,
+				Type:       "application/json",
+				RetryAfter: "1h30m",
+			},
+			expected: &version2.Maintenance{
+				Variable:     "$vs_maintenance_default_cafe_app",
+				AddrVariable: "$vs_maintenance_default_cafe_app_addr",
+				AllowList:    []string{"10.0.0.0/8"},
+				Header:       "$http_x_maintenance_token",
+				Token:        "c2VjcmV0",
+				Location:     "/internal_location_maintenance",
+				DefaultType:  "application/json",
+				Body:         `{\"status\": \"maintenance\"}`,
+				RetryAfter:   5400,
+			},
+			msg: "maintenance with allow list and header",
+		},
+	}
+
+	for _, test := range tests {
+		vs := &conf_v1.VirtualServer{
+			ObjectMeta: meta,
+			Spec:       conf_v1.VirtualServerSpec{Maintenance: test.maintenance},
+		}
+		result := generateMaintenance(vs)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateMaintenance() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
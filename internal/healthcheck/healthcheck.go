@@ -22,11 +22,15 @@ import (
 	"k8s.io/utils/strings/slices"
 )
 
+// UpstreamPeersFunc returns the peers of an upstream derived from the EndpointSlices of its Service.
+type UpstreamPeersFunc func(upstream configs.UpstreamService) ([]PeerStats, error)
+
 // RunHealthCheck starts the deep healthcheck service.
-func RunHealthCheck(port int, plusClient *client.NginxClient, cnf *configs.Configurator, healthProbeTLSSecret *v1.Secret) {
+// When plusClient is nil, the stats are derived from the readiness of the EndpointSlices of the upstream Services.
+func RunHealthCheck(port int, plusClient *client.NginxClient, upstreamPeers UpstreamPeersFunc, cnf *configs.Configurator, healthProbeTLSSecret *v1.Secret) {
 	l := nl.LoggerFromContext(cnf.CfgParams.Context)
 	addr := fmt.Sprintf(":%s", strconv.Itoa(port))
-	hs, err := NewHealthServer(addr, plusClient, cnf, healthProbeTLSSecret)
+	hs, err := NewHealthServer(addr, plusClient, upstreamPeers, cnf, healthProbeTLSSecret)
 	if err != nil {
 		nl.Fatal(l, err)
 	}
@@ -43,24 +47,41 @@ type HealthServer struct {
 	NginxUpstreams         func(ctx context.Context) (*client.Upstreams, error)
 	StreamUpstreamsForName func(host string) []string
 	NginxStreamUpstreams   func(ctx context.Context) (*client.StreamUpstreams, error)
-	Logger                 *slog.Logger
+
+	UpstreamServicesForHost          func(host string) []configs.UpstreamService
+	UpstreamServicesForVirtualServer func(namespace string, name string) []configs.UpstreamService
+	StreamUpstreamServicesForName    func(name string) []configs.UpstreamService
+	// ServiceEndpoints returns the peers of an upstream derived from the EndpointSlices of its Service.
+	// When set, the stats are computed from the EndpointSlices instead of the NGINX Plus API.
+	ServiceEndpoints UpstreamPeersFunc
+
+	Logger *slog.Logger
 }
 
 // NewHealthServer creates Health Server. If secret is provided,
-// the server is configured with TLS Config.
-func NewHealthServer(addr string, nc *client.NginxClient, cnf *configs.Configurator, secret *v1.Secret) (*HealthServer, error) {
+// the server is configured with TLS Config. If the NGINX Plus client is nil,
+// the stats are derived from the EndpointSlices of the upstream Services.
+func NewHealthServer(addr string, nc *client.NginxClient, upstreamPeers UpstreamPeersFunc, cnf *configs.Configurator, secret *v1.Secret) (*HealthServer, error) {
 	hs := HealthServer{
 		Server: &http.Server{
 			Addr:         addr,
 			ReadTimeout:  10 * time.Second,
 			WriteTimeout: 10 * time.Second,
 		},
-		URL:                    fmt.Sprintf("http://%s/", addr),
-		UpstreamsForHost:       cnf.UpstreamsForHost,
-		NginxUpstreams:         nc.GetUpstreams,
-		StreamUpstreamsForName: cnf.StreamUpstreamsForName,
-		NginxStreamUpstreams:   nc.GetStreamUpstreams,
-		Logger:                 nl.LoggerFromContext(cnf.CfgParams.Context),
+		URL:                              fmt.Sprintf("http://%s/", addr),
+		UpstreamsForHost:                 cnf.UpstreamsForHost,
+		StreamUpstreamsForName:           cnf.StreamUpstreamsForName,
+		UpstreamServicesForHost:          cnf.UpstreamServicesForHost,
+		UpstreamServicesForVirtualServer: cnf.UpstreamServicesForVirtualServer,
+		StreamUpstreamServicesForName:    cnf.StreamUpstreamServicesForName,
+		Logger:                           nl.LoggerFromContext(cnf.CfgParams.Context),
+	}
+
+	if nc != nil {
+		hs.NginxUpstreams = nc.GetUpstreams
+		hs.NginxStreamUpstreams = nc.GetStreamUpstreams
+	} else {
+		hs.ServiceEndpoints = upstreamPeers
 	}
 
 	if secret != nil {
@@ -82,6 +103,7 @@ func (hs *HealthServer) ListenAndServe() error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /probe/{hostname}", hs.UpstreamStats)
 	mux.HandleFunc("GET /probe/ts/{name}", hs.StreamStats)
+	mux.HandleFunc("GET /probe/vs/{namespace}/{name}", hs.VirtualServerStats)
 	hs.Server.Handler = mux
 	if hs.Server.TLSConfig != nil {
 		return hs.Server.ListenAndServeTLS("", "")
@@ -99,6 +121,11 @@ func (hs *HealthServer) UpstreamStats(w http.ResponseWriter, r *http.Request) {
 	hostname := r.PathValue("hostname")
 	host := sanitize(hostname)
 
+	if hs.ServiceEndpoints != nil {
+		hs.serviceStats(w, hs.UpstreamServicesForHost(host), fmt.Sprintf("hostname %s", host))
+		return
+	}
+
 	upstreamNames := hs.UpstreamsForHost(host)
 	if len(upstreamNames) == 0 {
 		nl.Errorf(hs.Logger, "no upstreams for requested hostname %s or hostname does not exist", host)
@@ -138,6 +165,12 @@ func (hs *HealthServer) UpstreamStats(w http.ResponseWriter, r *http.Request) {
 func (hs *HealthServer) StreamStats(w http.ResponseWriter, r *http.Request) {
 	name := r.PathValue("name")
 	n := sanitize(name)
+
+	if hs.ServiceEndpoints != nil {
+		hs.serviceStats(w, hs.StreamUpstreamServicesForName(n), fmt.Sprintf("name '%s'", n))
+		return
+	}
+
 	streamUpstreamNames := hs.StreamUpstreamsForName(n)
 	if len(streamUpstreamNames) == 0 {
 		nl.Errorf(hs.Logger, "no stream upstreams for requested name '%s' or name does not exist", n)
@@ -170,6 +203,119 @@ func (hs *HealthServer) StreamStats(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// VirtualServerStats returns the health stats of the VirtualServer identified by the namespace and the name
+// in the request URL, including the upstreams of its VirtualServerRoutes, and the stats of every upstream and peer.
+func (hs *HealthServer) VirtualServerStats(w http.ResponseWriter, r *http.Request) {
+	namespace := sanitize(r.PathValue("namespace"))
+	name := sanitize(r.PathValue("name"))
+
+	upstreams := hs.UpstreamServicesForVirtualServer(namespace, name)
+	if len(upstreams) == 0 {
+		nl.Errorf(hs.Logger, "no upstreams for requested VirtualServer %s/%s or VirtualServer does not exist", namespace, name)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var stats VirtualServerStats
+	if hs.ServiceEndpoints != nil {
+		upstreamStats, err := hs.upstreamStatsFromEndpointSlices(upstreams)
+		if err != nil {
+			nl.Errorf(hs.Logger, "error retrieving EndpointSlices for requested VirtualServer %s/%s: %v", namespace, name, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		stats = newVirtualServerStats(upstreamStats)
+	} else {
+		nginxUpstreams, err := hs.NginxUpstreams(r.Context())
+		if err != nil {
+			nl.Errorf(hs.Logger, "error retrieving upstreams for requested VirtualServer %s/%s", namespace, name)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		stats = newVirtualServerStats(upstreamStatsFromNginxUpstreams(nginxUpstreams, upstreams))
+	}
+
+	hs.writeStats(w, stats.Up, stats)
+}
+
+// serviceStats writes the health stats derived from the EndpointSlices of the given upstreams.
+func (hs *HealthServer) serviceStats(w http.ResponseWriter, upstreams []configs.UpstreamService, requested string) {
+	if len(upstreams) == 0 {
+		nl.Errorf(hs.Logger, "no upstreams for requested %s or it does not exist", requested)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	upstreamStats, err := hs.upstreamStatsFromEndpointSlices(upstreams)
+	if err != nil {
+		nl.Errorf(hs.Logger, "error retrieving EndpointSlices for requested %s: %v", requested, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	stats := newVirtualServerStats(upstreamStats).HostStats
+	hs.writeStats(w, stats.Up, stats)
+}
+
+// writeStats writes the stats as JSON. It responds with 418 when no peer is up.
+func (hs *HealthServer) writeStats(w http.ResponseWriter, up int, stats interface{}) {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		nl.Error(hs.Logger, "error marshaling result", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	switch up {
+	case 0:
+		w.WriteHeader(http.StatusTeapot)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+	if _, err := w.Write(data); err != nil {
+		nl.Error(hs.Logger, "error writing result", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func (hs *HealthServer) upstreamStatsFromEndpointSlices(upstreams []configs.UpstreamService) ([]UpstreamStats, error) {
+	stats := make([]UpstreamStats, 0, len(upstreams))
+	for _, u := range upstreams {
+		peers, err := hs.ServiceEndpoints(u)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, newUpstreamStats(u, peers))
+	}
+	return stats, nil
+}
+
+func upstreamStatsFromNginxUpstreams(nginxUpstreams *client.Upstreams, upstreams []configs.UpstreamService) []UpstreamStats {
+	stats := make([]UpstreamStats, 0, len(upstreams))
+	for _, u := range upstreams {
+		var peers []PeerStats
+		if nginxUpstream, exists := (*nginxUpstreams)[u.Name]; exists {
+			for _, p := range nginxUpstream.Peers {
+				peers = append(peers, PeerStats{
+					Server: p.Server,
+					State:  p.State,
+					Active: p.Active,
+					Responses: &PeerResponses{
+						Responses1xx: p.Responses.Responses1xx,
+						Responses2xx: p.Responses.Responses2xx,
+						Responses3xx: p.Responses.Responses3xx,
+						Responses4xx: p.Responses.Responses4xx,
+						Responses5xx: p.Responses.Responses5xx,
+						Total:        p.Responses.Total,
+					},
+				})
+			}
+		}
+		stats = append(stats, newUpstreamStats(u, peers))
+	}
+	return stats
+}
+
 func sanitize(s string) string {
 	hostname := strings.TrimSpace(s)
 	hostname = strings.ReplaceAll(hostname, "\n", "")
@@ -200,6 +346,71 @@ type HostStats struct {
 	Unhealthy int
 }
 
+// PeerResponses holds the number of responses of a peer grouped by status code class.
+type PeerResponses struct {
+	Responses1xx uint64 `json:"1xx"`
+	Responses2xx uint64 `json:"2xx"`
+	Responses3xx uint64 `json:"3xx"`
+	Responses4xx uint64 `json:"4xx"`
+	Responses5xx uint64 `json:"5xx"`
+	Total        uint64
+}
+
+// PeerStats holds the state of a peer of an upstream.
+// Active and Responses are only available with NGINX Plus.
+type PeerStats struct {
+	Server    string
+	State     string
+	Active    uint64
+	Responses *PeerResponses `json:",omitempty"`
+}
+
+// UpstreamStats holds the health stats and the peers of an upstream
+// of a VirtualServer or a VirtualServerRoute.
+type UpstreamStats struct {
+	HostStats
+	Name         string
+	Service      string
+	ResourceType string
+	ResourceName string
+	Peers        []PeerStats
+}
+
+// VirtualServerStats holds the health stats of a VirtualServer, including its
+// VirtualServerRoutes, and the stats of each of its upstreams.
+type VirtualServerStats struct {
+	HostStats
+	Upstreams []UpstreamStats
+}
+
+func newUpstreamStats(u configs.UpstreamService, peers []PeerStats) UpstreamStats {
+	stats := UpstreamStats{
+		Name:         u.Name,
+		Service:      u.Service,
+		ResourceType: u.ResourceType,
+		ResourceName: u.ResourceName,
+		Peers:        peers,
+	}
+	for _, p := range peers {
+		stats.Total++
+		if strings.ToLower(p.State) == "up" {
+			stats.Up++
+		}
+	}
+	stats.Unhealthy = stats.Total - stats.Up
+	return stats
+}
+
+func newVirtualServerStats(upstreams []UpstreamStats) VirtualServerStats {
+	stats := VirtualServerStats{Upstreams: upstreams}
+	for _, u := range upstreams {
+		stats.Total += u.Total
+		stats.Up += u.Up
+		stats.Unhealthy += u.Unhealthy
+	}
+	return stats
+}
+
 // countStats calculates and returns statistics for a host.
 func countStats(upstreams *client.Upstreams, upstreamNames []string) HostStats {
 	total, up := 0, 0
@@ -14,6 +14,7 @@ import (
 	"github.com/nginx/kubernetes-ingress/internal/logger/levels"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/nginx/kubernetes-ingress/internal/configs"
 	"github.com/nginx/kubernetes-ingress/internal/healthcheck"
 	"github.com/nginx/nginx-plus-go-client/v2/client"
 )
@@ -23,6 +24,7 @@ func testHandler(hs *healthcheck.HealthServer) http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /probe/{hostname}", hs.UpstreamStats)
 	mux.HandleFunc("GET /probe/ts/{name}", hs.StreamStats)
+	mux.HandleFunc("GET /probe/vs/{namespace}/{name}", hs.VirtualServerStats)
 	return mux
 }
 
@@ -337,6 +339,243 @@ func TestHealthCheckServer_ReturnsCorrectTransportServerStatsForNameOnAllPeersDo
 	}
 }
 
+func TestHealthCheckServer_ReturnsCorrectStatsForVirtualServerWithVirtualServerRoutes(t *testing.T) {
+	hs := healthcheck.HealthServer{
+		UpstreamServicesForVirtualServer: getUpstreamServicesForVirtualServer,
+		NginxUpstreams:                   getUpstreamsFromNGINXWithPeerDetails,
+		Logger:                           slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo})),
+	}
+
+	ts := httptest.NewServer(testHandler(&hs))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/probe/vs/default/cafe") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatal(resp.StatusCode)
+	}
+
+	want := healthcheck.VirtualServerStats{
+		HostStats: healthcheck.HostStats{Total: 3, Up: 2, Unhealthy: 1},
+		Upstreams: []healthcheck.UpstreamStats{
+			{
+				HostStats:    healthcheck.HostStats{Total: 2, Up: 1, Unhealthy: 1},
+				Name:         "upstream1",
+				Service:      "tea-svc",
+				ResourceType: "virtualserver",
+				ResourceName: "cafe",
+				Peers: []healthcheck.PeerStats{
+					{
+						Server:    "10.0.0.1:80",
+						State:     "up",
+						Active:    2,
+						Responses: &healthcheck.PeerResponses{Responses2xx: 10, Responses5xx: 1, Total: 11},
+					},
+					{
+						Server:    "10.0.0.2:80",
+						State:     "unhealthy",
+						Responses: &healthcheck.PeerResponses{},
+					},
+				},
+			},
+			{
+				HostStats:    healthcheck.HostStats{Total: 1, Up: 1, Unhealthy: 0},
+				Name:         "upstream2",
+				Service:      "coffee-svc",
+				ResourceType: "virtualserverroute",
+				ResourceName: "coffee",
+				Peers: []healthcheck.PeerStats{
+					{
+						Server:    "10.0.1.1:80",
+						State:     "up",
+						Responses: &healthcheck.PeerResponses{},
+					},
+				},
+			},
+		},
+	}
+	var got healthcheck.VirtualServerStats
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestHealthCheckServer_RespondsWith404OnNotExistingVirtualServer(t *testing.T) {
+	hs := healthcheck.HealthServer{
+		UpstreamServicesForVirtualServer: getUpstreamServicesForVirtualServer,
+		NginxUpstreams:                   getUpstreamsFromNGINXWithPeerDetails,
+		Logger:                           slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo})),
+	}
+
+	ts := httptest.NewServer(testHandler(&hs))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/probe/vs/default/bogus") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Error(resp.StatusCode)
+	}
+}
+
+func TestHealthCheckServer_ReturnsCorrectStatsForHostnameFromEndpointSlices(t *testing.T) {
+	hs := healthcheck.HealthServer{
+		UpstreamServicesForHost: getUpstreamServicesForHost,
+		ServiceEndpoints:        getServiceEndpoints,
+		Logger:                  slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo})),
+	}
+
+	ts := httptest.NewServer(testHandler(&hs))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/probe/foo.tea.com") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatal(resp.StatusCode)
+	}
+
+	want := healthcheck.HostStats{
+		Total:     3,
+		Up:        2,
+		Unhealthy: 1,
+	}
+	var got healthcheck.HostStats
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestHealthCheckServer_RespondsWith418OnAllEndpointsNotReady(t *testing.T) {
+	hs := healthcheck.HealthServer{
+		UpstreamServicesForHost: getUpstreamServicesForHost,
+		ServiceEndpoints:        getServiceEndpoints,
+		Logger:                  slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo})),
+	}
+
+	ts := httptest.NewServer(testHandler(&hs))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/probe/bar.tea.com") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusTeapot {
+		t.Error(resp.StatusCode)
+	}
+}
+
+func TestHealthCheckServer_RespondsWith500OnErrorFromEndpointSlices(t *testing.T) {
+	hs := healthcheck.HealthServer{
+		UpstreamServicesForHost: getUpstreamServicesForHost,
+		ServiceEndpoints: func(_ configs.UpstreamService) ([]healthcheck.PeerStats, error) {
+			return nil, errors.New("kubernetes api error")
+		},
+		Logger: slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo})),
+	}
+
+	ts := httptest.NewServer(testHandler(&hs))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/probe/foo.tea.com") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Error(resp.StatusCode)
+	}
+}
+
+// getUpstreamServicesForVirtualServer is a helper func faking response from IC.
+func getUpstreamServicesForVirtualServer(namespace string, name string) []configs.UpstreamService {
+	if namespace != "default" || name != "cafe" {
+		return nil
+	}
+	return []configs.UpstreamService{
+		{Name: "upstream1", Service: "tea-svc", Namespace: "default", ResourceType: "virtualserver", ResourceName: "cafe"},
+		{Name: "upstream2", Service: "coffee-svc", Namespace: "default", ResourceType: "virtualserverroute", ResourceName: "coffee"},
+	}
+}
+
+// getUpstreamServicesForHost is a helper func faking response from IC.
+func getUpstreamServicesForHost(host string) []configs.UpstreamService {
+	upstreams := map[string][]configs.UpstreamService{
+		"foo.tea.com": {
+			{Name: "upstream1", Service: "tea-svc", Namespace: "default"},
+			{Name: "upstream2", Service: "coffee-svc", Namespace: "default"},
+		},
+		"bar.tea.com": {
+			{Name: "upstream3", Service: "down-svc", Namespace: "default"},
+		},
+	}
+	return upstreams[host]
+}
+
+// getServiceEndpoints is a helper func faking the peers derived from EndpointSlices.
+func getServiceEndpoints(upstream configs.UpstreamService) ([]healthcheck.PeerStats, error) {
+	peers := map[string][]healthcheck.PeerStats{
+		"tea-svc": {
+			{Server: "10.0.0.1:80", State: "up"},
+			{Server: "10.0.0.2:80", State: "unhealthy"},
+		},
+		"coffee-svc": {
+			{Server: "10.0.1.1:80", State: "up"},
+		},
+		"down-svc": {
+			{Server: "10.0.2.1:80", State: "unhealthy"},
+		},
+	}
+	return peers[upstream.Service], nil
+}
+
+// getUpstreamsFromNGINXWithPeerDetails is a helper func used
+// for faking response data from NGINX API including the details of the peers.
+func getUpstreamsFromNGINXWithPeerDetails(_ context.Context) (*client.Upstreams, error) {
+	ups := client.Upstreams{
+		"upstream1": client.Upstream{
+			Peers: []client.Peer{
+				{
+					Server: "10.0.0.1:80",
+					State:  "up",
+					Active: 2,
+					Responses: client.Responses{
+						Responses2xx: 10,
+						Responses5xx: 1,
+						Total:        11,
+					},
+				},
+				{Server: "10.0.0.2:80", State: "unhealthy"},
+			},
+		},
+		"upstream2": client.Upstream{
+			Peers: []client.Peer{
+				{Server: "10.0.1.1:80", State: "up"},
+			},
+		},
+	}
+	return &ups, nil
+}
+
 // getUpstreamsForHost is a helper func faking response from IC.
 func getUpstreamsForHost(host string) []string {
 	upstreams := map[string][]string{
@@ -0,0 +1,256 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	"github.com/nginx/kubernetes-ingress/internal/metrics/collectors"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	api_v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// certificateExpiryCheckInterval is how often the certificates referenced by resources are checked.
+	// The certificates are not checked on every change of a resource or a secret, because a check parses
+	// the certificates of all referenced secrets.
+	certificateExpiryCheckInterval = 5 * time.Minute
+
+	certificateExpiryTaskKey = "certificate-expiry"
+)
+
+// certificateExpiryWarning records the smallest threshold a Warning event was emitted for
+// a certificate referenced by a resource.
+type certificateExpiryWarning struct {
+	notAfter  time.Time
+	threshold time.Duration
+}
+
+// runCertificateExpiryChecks enqueues a check of the certificates referenced by resources on start
+// and then periodically.
+func (lbc *LoadBalancerController) runCertificateExpiryChecks(ctx context.Context) {
+	lbc.enqueueCertificateExpiryCheck()
+
+	ticker := time.NewTicker(certificateExpiryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			lbc.enqueueCertificateExpiryCheck()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (lbc *LoadBalancerController) enqueueCertificateExpiryCheck() {
+	lbc.syncQueue.EnqueueTask(task{Kind: certificateExpiry, Key: certificateExpiryTaskKey})
+}
+
+// syncCertificateExpiry parses the certificates of all TLS and CA secrets referenced by resources.
+// It updates the certificate metrics, emits Warning events for the resources when a certificate crosses
+// one of the configured thresholds and flags the resources whose certificates have expired.
+func (lbc *LoadBalancerController) syncCertificateExpiry() {
+	now := time.Now()
+	var certificates []collectors.CertificateExpiry
+	warned := make(map[string]bool)
+	expiredIngresses := make(map[string]bool)
+
+	for key, secretRef := range lbc.secretStore.GetSecretReferenceMap() {
+		if secretRef.Secret == nil || !secrets.IsCertificateSecret(secretRef.Secret) {
+			continue
+		}
+
+		notAfter, err := secrets.GetCertificateNotAfter(secretRef.Secret)
+		if err != nil {
+			nl.Debugf(lbc.Logger, "Skipping expiry check of Secret %v: %v", key, err)
+			continue
+		}
+
+		namespace, name := secretRef.Secret.Namespace, secretRef.Secret.Name
+		resources := lbc.findResourcesForSecret(namespace, name)
+		if len(resources) == 0 {
+			continue
+		}
+
+		for _, r := range resources {
+			obj, resourceType, hosts := getCertificateExpiryResourceInfo(r, name)
+			if obj == nil {
+				continue
+			}
+			meta := r.GetObjectMeta()
+
+			certificate := collectors.CertificateExpiry{
+				SecretNamespace:   namespace,
+				SecretName:        name,
+				ResourceType:      resourceType,
+				ResourceName:      meta.Name,
+				ResourceNamespace: meta.Namespace,
+				NotAfter:          notAfter,
+			}
+			if len(hosts) == 0 {
+				certificates = append(certificates, certificate)
+			}
+			for _, host := range hosts {
+				certificate.Host = host
+				certificates = append(certificates, certificate)
+			}
+
+			if ing, ok := obj.(*networking.Ingress); ok && !notAfter.After(now) {
+				expiredIngresses[ing.Namespace+"/"+ing.Name] = true
+			}
+
+			warningKey := fmt.Sprintf("%s/%s", r.GetKeyWithKind(), key)
+			warned[warningKey] = true
+			lbc.warnAboutCertificateExpiry(warningKey, r, obj, key, notAfter, now)
+		}
+	}
+
+	for warningKey := range lbc.certificateExpiryWarnings {
+		if !warned[warningKey] {
+			delete(lbc.certificateExpiryWarnings, warningKey)
+		}
+	}
+
+	lbc.certificateCollector.SetCertificates(certificates)
+	lbc.updateIngressStatusForExpiredCertificates(expiredIngresses)
+}
+
+// updateIngressStatusForExpiredCertificates flags the status of the Ingresses that reference an expired
+// certificate and clears the flag of the Ingresses that no longer do.
+func (lbc *LoadBalancerController) updateIngressStatusForExpiredCertificates(expired map[string]bool) {
+	changed := lbc.statusUpdater.SetExpiredCertificateIngresses(expired)
+	if !lbc.reportStatusEnabled() {
+		return
+	}
+
+	for _, key := range changed {
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			continue
+		}
+		ing := networking.Ingress{ObjectMeta: meta_v1.ObjectMeta{Namespace: namespace, Name: name}}
+		err = lbc.statusUpdater.UpdateIngressStatus(ing)
+		if err != nil {
+			nl.Errorf(lbc.Logger, "Error when updating the status for Ingress %v: %v", key, err)
+		}
+	}
+}
+
+func (lbc *LoadBalancerController) warnAboutCertificateExpiry(warningKey string, r Resource, obj runtime.Object, secretKey string, notAfter time.Time, now time.Time) {
+	threshold, crossed := getCertificateExpiryThreshold(notAfter.Sub(now), lbc.certificateExpiryThresholds)
+	if !crossed {
+		delete(lbc.certificateExpiryWarnings, warningKey)
+		return
+	}
+
+	prev, exists := lbc.certificateExpiryWarnings[warningKey]
+	if exists && prev.notAfter.Equal(notAfter) && prev.threshold <= threshold {
+		return
+	}
+	lbc.certificateExpiryWarnings[warningKey] = certificateExpiryWarning{notAfter: notAfter, threshold: threshold}
+
+	if threshold == 0 {
+		msg := fmt.Sprintf("Secret %s holds a certificate that expired on %s", secretKey, notAfter.Format(time.RFC3339))
+		nl.Warnf(lbc.Logger, "%s: %s", r.GetKeyWithKind(), msg)
+		lbc.recorder.Event(obj, api_v1.EventTypeWarning, nl.EventReasonCertificateExpired, msg)
+		lbc.updateStatusForExpiredCertificate(obj, msg)
+		return
+	}
+
+	lbc.recorder.Eventf(obj, api_v1.EventTypeWarning, nl.EventReasonCertificateExpiring,
+		"Secret %s holds a certificate that expires on %s, in less than %d day(s)", secretKey, notAfter.Format(time.RFC3339), int(threshold.Hours()/24))
+}
+
+// updateStatusForExpiredCertificate sets the state of a VirtualServer or a TransportServer that references
+// an expired certificate to Warning. The status of an Ingress has no state, so it is flagged
+// by updateIngressStatusForExpiredCertificates instead.
+func (lbc *LoadBalancerController) updateStatusForExpiredCertificate(obj runtime.Object, msg string) {
+	if !lbc.reportCustomResourceStatusEnabled() {
+		return
+	}
+
+	switch impl := obj.(type) {
+	case *conf_v1.VirtualServer:
+		err := lbc.statusUpdater.UpdateVirtualServerStatus(impl, conf_v1.StateWarning, nl.EventReasonCertificateExpired, msg)
+		if err != nil {
+			nl.Errorf(lbc.Logger, "Error when updating the status for VirtualServer %v/%v: %v", impl.Namespace, impl.Name, err)
+		}
+	case *conf_v1.TransportServer:
+		err := lbc.statusUpdater.UpdateTransportServerStatus(impl, conf_v1.StateWarning, nl.EventReasonCertificateExpired, msg)
+		if err != nil {
+			nl.Errorf(lbc.Logger, "Error when updating the status for TransportServer %v/%v: %v", impl.Namespace, impl.Name, err)
+		}
+	}
+}
+
+// getCertificateExpiryThreshold returns the smallest threshold crossed by a certificate that expires in
+// the given duration. The thresholds must be positive. A threshold of 0 means the certificate has expired.
+func getCertificateExpiryThreshold(expiresIn time.Duration, thresholds []time.Duration) (time.Duration, bool) {
+	if expiresIn <= 0 {
+		return 0, true
+	}
+
+	var smallest time.Duration
+	crossed := false
+	for _, t := range thresholds {
+		if expiresIn <= t && (!crossed || t < smallest) {
+			smallest = t
+			crossed = true
+		}
+	}
+
+	return smallest, crossed
+}
+
+// getCertificateExpiryResourceInfo returns the object to report events for, the resource type
+// and the hosts of a resource that references a secret.
+func getCertificateExpiryResourceInfo(r Resource, secretName string) (runtime.Object, string, []string) {
+	switch impl := r.(type) {
+	case *VirtualServerConfiguration:
+		return impl.VirtualServer, "virtualserver", []string{impl.VirtualServer.Spec.Host}
+	case *TransportServerConfiguration:
+		var hosts []string
+		if impl.TransportServer.Spec.Host != "" {
+			hosts = append(hosts, impl.TransportServer.Spec.Host)
+		}
+		return impl.TransportServer, "transportserver", hosts
+	case *IngressConfiguration:
+		var hosts []string
+		for _, tls := range impl.Ingress.Spec.TLS {
+			if tls.SecretName == secretName {
+				hosts = append(hosts, tls.Hosts...)
+			}
+		}
+		if len(hosts) == 0 {
+			for _, rule := range impl.Ingress.Spec.Rules {
+				hosts = append(hosts, rule.Host)
+			}
+		}
+		// the same host can be listed in several TLS entries and rules, while the metrics need a unique
+		// set of labels per host
+		return impl.Ingress, "ingress", uniqueHosts(hosts)
+	}
+
+	return nil, "", nil
+}
+
+func uniqueHosts(hosts []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, host := range hosts {
+		if seen[host] {
+			continue
+		}
+		seen[host] = true
+		result = append(result, host)
+	}
+	return result
+}
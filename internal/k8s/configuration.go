@@ -370,6 +370,7 @@ type Configuration struct {
 	appPolicyReferenceChecker  *appProtectResourceReferenceChecker
 	appLogConfReferenceChecker *appProtectResourceReferenceChecker
 	appDosProtectedChecker     *dosResourceReferenceChecker
+	crossNamespaceChecker      *crossNamespaceReferenceChecker
 
 	isPlus                  bool
 	appProtectEnabled       bool
@@ -417,6 +418,7 @@ func NewConfiguration(
 		appPolicyReferenceChecker:    newAppProtectResourceReferenceChecker(configs.AppProtectPolicyAnnotation),
 		appLogConfReferenceChecker:   newAppProtectResourceReferenceChecker(configs.AppProtectLogConfAnnotation),
 		appDosProtectedChecker:       newDosResourceReferenceChecker(configs.AppProtectDosProtectedAnnotation),
+		crossNamespaceChecker:        newCrossNamespaceReferenceChecker(),
 		isPlus:                       isPlus,
 		appProtectEnabled:            appProtectEnabled,
 		appProtectDosEnabled:         appProtectDosEnabled,
@@ -940,6 +942,12 @@ func (c *Configuration) FindResourcesForAppProtectDosProtected(namespace string,
 	return c.findResourcesForResourceReference(namespace, name, c.appDosProtectedChecker)
 }
 
+// FindResourcesForReferenceGrant finds resources that reference Policies or Services in the namespace of the ReferenceGrant
+// from other namespaces.
+func (c *Configuration) FindResourcesForReferenceGrant(namespace string) []Resource {
+	return c.findResourcesForResourceReference(namespace, "", c.crossNamespaceChecker)
+}
+
 // FindIngressesWithRatelimitScaling finds ingresses that use rate limit scaling
 func (c *Configuration) FindIngressesWithRatelimitScaling(svcNamespace string) []Resource {
 	return c.findResourcesForResourceReference(svcNamespace, "", &ratelimitScalingAnnotationChecker{})
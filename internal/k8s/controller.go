@@ -48,6 +48,7 @@ import (
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/record"
 
+	"github.com/nginx/kubernetes-ingress/internal/audit"
 	cm_controller "github.com/nginx/kubernetes-ingress/internal/certmanager"
 	"github.com/nginx/kubernetes-ingress/internal/configs"
 	ed_controller "github.com/nginx/kubernetes-ingress/internal/externaldns"
@@ -123,6 +124,7 @@ type LoadBalancerController struct {
 	restConfig                    *rest.Config
 	cacheSyncs                    []cache.InformerSynced
 	namespacedInformers           map[string]*namespacedInformer
+	namespacedInformersLock       sync.RWMutex
 	configMapController           cache.Controller
 	mgmtConfigMapController       cache.Controller
 	globalConfigurationController cache.Controller
@@ -144,6 +146,7 @@ type LoadBalancerController struct {
 	isNginxPlus                   bool
 	appProtectEnabled             bool
 	appProtectDosEnabled          bool
+	geoIP2Enabled                 bool
 	recorder                      record.EventRecorder
 	specialSecrets                specialSecrets
 	ingressClass                  string
@@ -159,6 +162,12 @@ type LoadBalancerController struct {
 	areCustomResourcesEnabled     bool
 	enableOIDC                    bool
 	metricsCollector              collectors.ControllerCollector
+	certificateCollector          collectors.CertificateCollector
+	certificateExpiryThresholds   []time.Duration
+	certificateExpiryWarnings     map[string]certificateExpiryWarning
+	syncTracker                   *syncTracker
+	readyFailedReloadsThreshold   int
+	readyStalenessThreshold       time.Duration
 	globalConfigurationValidator  *validation.GlobalConfigurationValidator
 	transportServerValidator      *validation.TransportServerValidator
 	spiffeCertFetcher             *spiffe.X509CertFetcher
@@ -183,6 +192,10 @@ type LoadBalancerController struct {
 	telemetryCollector            *telemetry.Collector
 	telemetryChan                 chan struct{}
 	weightChangesDynamicReload    bool
+	enableRollouts                bool
+	rollouts                      map[string]*rolloutState
+	enableReferenceGrants         bool
+	referenceGrantChecker         *referenceGrantChecker
 	nginxConfigMapName            string
 	mgmtConfigMapName             string
 }
@@ -205,6 +218,7 @@ type NewLoadBalancerControllerInput struct {
 	AppProtectEnabled            bool
 	AppProtectDosEnabled         bool
 	AppProtectVersion            string
+	IsGeoIP2Enabled              bool
 	IsNginxPlus                  bool
 	IngressClass                 string
 	ExternalServiceName          string
@@ -221,6 +235,10 @@ type NewLoadBalancerControllerInput struct {
 	AreCustomResourcesEnabled    bool
 	EnableOIDC                   bool
 	MetricsCollector             collectors.ControllerCollector
+	CertificateCollector         collectors.CertificateCollector
+	CertificateExpiryThresholds  []time.Duration
+	ReadyFailedReloadsThreshold  int
+	ReadyStalenessThreshold      time.Duration
 	GlobalConfigurationValidator *validation.GlobalConfigurationValidator
 	TransportServerValidator     *validation.TransportServerValidator
 	VirtualServerValidator       *validation.VirtualServerValidator
@@ -240,6 +258,8 @@ type NewLoadBalancerControllerInput struct {
 	BuildOS                      string
 	NICVersion                   string
 	DynamicWeightChangesReload   bool
+	EnableRollouts               bool
+	EnableReferenceGrants        bool
 	InstallationFlags            []string
 }
 
@@ -265,6 +285,7 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		specialSecrets:               specialSecrets,
 		appProtectEnabled:            input.AppProtectEnabled,
 		appProtectDosEnabled:         input.AppProtectDosEnabled,
+		geoIP2Enabled:                input.IsGeoIP2Enabled,
 		isNginxPlus:                  input.IsNginxPlus,
 		ingressClass:                 input.IngressClass,
 		reportIngressStatus:          input.ReportIngressStatus,
@@ -277,6 +298,12 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		areCustomResourcesEnabled:    input.AreCustomResourcesEnabled,
 		enableOIDC:                   input.EnableOIDC,
 		metricsCollector:             input.MetricsCollector,
+		certificateCollector:         input.CertificateCollector,
+		certificateExpiryThresholds:  input.CertificateExpiryThresholds,
+		certificateExpiryWarnings:    make(map[string]certificateExpiryWarning),
+		syncTracker:                  newSyncTracker(),
+		readyFailedReloadsThreshold:  input.ReadyFailedReloadsThreshold,
+		readyStalenessThreshold:      input.ReadyStalenessThreshold,
 		globalConfigurationValidator: input.GlobalConfigurationValidator,
 		transportServerValidator:     input.TransportServerValidator,
 		internalRoutesEnabled:        input.InternalRoutesEnabled,
@@ -284,11 +311,19 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		isLatencyMetricsEnabled:      input.IsLatencyMetricsEnabled,
 		isIPV6Disabled:               input.IsIPV6Disabled,
 		weightChangesDynamicReload:   input.DynamicWeightChangesReload,
+		enableRollouts:               input.EnableRollouts,
+		rollouts:                     make(map[string]*rolloutState),
+		enableReferenceGrants:        input.EnableReferenceGrants,
 		nginxConfigMapName:           input.ConfigMaps,
 		mgmtConfigMapName:            input.MGMTConfigMap,
 	}
 
-	lbc.syncQueue = newTaskQueue(lbc.Logger, lbc.sync)
+	if lbc.certificateCollector == nil {
+		lbc.certificateCollector = collectors.NewCertificateFakeCollector()
+	}
+
+	lbc.syncQueue = newTaskQueue(lbc.Logger, lbc.sync, lbc.addPendingTask)
+	lbc.referenceGrantChecker = newReferenceGrantChecker(lbc.enableReferenceGrants, lbc.getReferenceGrants)
 	var err error
 	if input.SpireAgentAddress != "" {
 		lbc.spiffeCertFetcher, err = spiffe.NewX509CertFetcher(input.SpireAgentAddress, nil)
@@ -457,6 +492,8 @@ type namespacedInformer struct {
 	appProtectUserSigLister      cache.Store
 	transportServerLister        cache.Store
 	policyLister                 cache.Store
+	rolloutLister                cache.Store
+	referenceGrantLister         cache.Store
 	isSecretsEnabledNamespace    bool
 	areCustomResourcesEnabled    bool
 	appProtectEnabled            bool
@@ -508,7 +545,12 @@ func (lbc *LoadBalancerController) newNamespacedInformer(ns string) *namespacedI
 		nsi.addVirtualServerRouteHandler(createVirtualServerRouteHandlers(lbc))
 		nsi.addTransportServerHandler(createTransportServerHandlers(lbc))
 		nsi.addPolicyHandler(createPolicyHandlers(lbc))
-
+		if lbc.enableRollouts {
+			nsi.addRolloutHandler(createRolloutHandlers(lbc))
+		}
+		if lbc.enableReferenceGrants {
+			nsi.addReferenceGrantHandler(createReferenceGrantHandlers(lbc))
+		}
 	}
 
 	if lbc.appProtectEnabled || lbc.appProtectDosEnabled {
@@ -528,7 +570,9 @@ func (lbc *LoadBalancerController) newNamespacedInformer(ns string) *namespacedI
 		}
 	}
 
+	lbc.namespacedInformersLock.Lock()
 	lbc.namespacedInformers[ns] = nsi
+	lbc.namespacedInformersLock.Unlock()
 	return nsi
 }
 
@@ -673,6 +717,10 @@ func (lbc *LoadBalancerController) Run() {
 	nl.Debugf(lbc.Logger, "Starting the queue with %d initial elements", lbc.syncQueue.Len())
 
 	go lbc.syncQueue.Run(time.Second, lbc.ctx.Done())
+	go lbc.runCertificateExpiryChecks(lbc.ctx)
+	if lbc.enableRollouts {
+		go lbc.runRolloutChecks(lbc.ctx)
+	}
 	<-lbc.ctx.Done()
 }
 
@@ -875,6 +923,16 @@ func (lbc *LoadBalancerController) createExtendedResources(resources []Resource)
 	return result
 }
 
+// getConfigMapMeta returns the metadata of the ConfigMap with the NGINX configuration for the audit records.
+// Only the namespace and the name are known when the ConfigMap doesn't exist.
+func (lbc *LoadBalancerController) getConfigMapMeta() *meta_v1.ObjectMeta {
+	if lbc.configMap != nil {
+		return &lbc.configMap.ObjectMeta
+	}
+	namespace, name, _ := cache.SplitMetaNamespaceKey(lbc.nginxConfigMapName)
+	return &meta_v1.ObjectMeta{Namespace: namespace, Name: name}
+}
+
 func (lbc *LoadBalancerController) updateAllConfigs() {
 	ctx := nl.ContextWithLogger(context.Background(), lbc.Logger)
 	cfgParams := configs.NewDefaultConfigParams(ctx, lbc.isNginxPlus)
@@ -894,8 +952,14 @@ func (lbc *LoadBalancerController) updateAllConfigs() {
 		}
 	}
 
+	geoIP2DatabasesChanged := lbc.configurator.CfgParams != nil &&
+		(lbc.configurator.CfgParams.MainGeoIP2CountryDatabase != cfgParams.MainGeoIP2CountryDatabase ||
+			lbc.configurator.CfgParams.MainGeoIP2ASNDatabase != cfgParams.MainGeoIP2ASNDatabase)
 	lbc.configurator.CfgParams = cfgParams
 	lbc.configurator.MgmtCfgParams = mgmtCfgParams
+	if geoIP2DatabasesChanged {
+		lbc.enqueueGeoAccessControlPolicies()
+	}
 
 	// update special license secret in mgmtConfigParams
 	if lbc.mgmtConfigMap != nil && lbc.isNginxPlus {
@@ -932,7 +996,9 @@ func (lbc *LoadBalancerController) updateAllConfigs() {
 	resources := lbc.configuration.GetResources()
 	nl.Debugf(lbc.Logger, "Updating %v resources", len(resources))
 	resourceExes := lbc.createExtendedResources(resources)
+	lbc.configurator.BeginAuditRecordOfChangedFiles()
 	warnings, updateErr := lbc.configurator.UpdateConfig(resourceExes)
+	lbc.configurator.WriteAuditRecord("ConfigMap", lbc.getConfigMapMeta(), audit.OperationAddOrUpdate, updateErr)
 
 	eventTitle := nl.EventReasonUpdated
 	eventType := api_v1.EventTypeNormal
@@ -1009,11 +1075,12 @@ func (lbc *LoadBalancerController) sync(task task) {
 		nl.Debugf(lbc.Logger, "Batch processing %v items", lbc.syncQueue.Len())
 	}
 	nl.Debugf(lbc.Logger, "Syncing %v", task.Key)
+	lbc.syncTracker.markSynced(task)
 	if lbc.spiffeCertFetcher != nil {
 		lbc.syncLock.Lock()
 		defer lbc.syncLock.Unlock()
 	}
-	if lbc.batchSyncEnabled && task.Kind != endpointslice {
+	if lbc.batchSyncEnabled && task.Kind != endpointslice && task.Kind != certificateExpiry && task.Kind != rollout {
 		nl.Debug(lbc.Logger, "Task is not endpointslice - enabling batch reload")
 		lbc.enableBatchReload = true
 	}
@@ -1069,6 +1136,12 @@ func (lbc *LoadBalancerController) sync(task task) {
 		lbc.syncDosProtectedResource(task)
 	case ingressLink:
 		lbc.syncIngressLink(task)
+	case certificateExpiry:
+		lbc.syncCertificateExpiry()
+	case rollout:
+		lbc.syncRollout(task)
+	case referenceGrant:
+		lbc.syncReferenceGrant(task)
 	}
 
 	if !lbc.isNginxReady && lbc.syncQueue.Len() == 0 {
@@ -1079,6 +1152,7 @@ func (lbc *LoadBalancerController) sync(task task) {
 		nl.Debug(lbc.Logger, "NGINX is ready")
 	}
 
+	applied := true
 	if lbc.batchSyncEnabled && lbc.syncQueue.Len() == 0 {
 		lbc.batchSyncEnabled = false
 		lbc.configurator.EnableReloads()
@@ -1087,19 +1161,39 @@ func (lbc *LoadBalancerController) sync(task task) {
 		} else {
 			if err := lbc.configurator.ReloadForBatchUpdates(lbc.enableBatchReload); err != nil {
 				nl.Errorf(lbc.Logger, "error reloading for batch updates: %v", err)
+				applied = false
 			}
 		}
 
 		lbc.enableBatchReload = false
 		nl.Debug(lbc.Logger, "Batch sync completed - disabling batch reload")
 	}
+
+	// the changes of the synced tasks stay pending until NGINX applies them
+	if lbc.isNginxReady && !lbc.batchSyncEnabled {
+		if applied {
+			lbc.syncTracker.clearSynced()
+		}
+		lbc.syncTracker.removeStaleFailures(lbc.configuration.GetResources())
+	}
+}
+
+// addPendingTask records that the change of a task added to the sync queue is not applied yet.
+// The periodic tasks don't change the configuration.
+func (lbc *LoadBalancerController) addPendingTask(task task) {
+	if task.Kind == certificateExpiry || task.Kind == rollout {
+		return
+	}
+	lbc.syncTracker.addPending(task, time.Now())
 }
 
 func (lbc *LoadBalancerController) removeNamespacedInformer(nsi *namespacedInformer, key string) {
 	nsi.lock.Lock()
 	defer nsi.lock.Unlock()
 	nsi.stop()
+	lbc.namespacedInformersLock.Lock()
 	delete(lbc.namespacedInformers, key)
+	lbc.namespacedInformersLock.Unlock()
 	nsi = nil
 }
 
@@ -1264,32 +1358,42 @@ func (lbc *LoadBalancerController) processChanges(changes []ResourceChange) {
 			case *VirtualServerConfiguration:
 				vsEx := lbc.createVirtualServerEx(impl.VirtualServer, impl.VirtualServerRoutes)
 
+				lbc.configurator.BeginAuditRecord()
 				warnings, addOrUpdateErr := lbc.configurator.AddOrUpdateVirtualServer(vsEx)
-				lbc.updateVirtualServerStatusAndEvents(impl, warnings, addOrUpdateErr)
+				lbc.configurator.WriteAuditRecord(virtualServerKind, &impl.VirtualServer.ObjectMeta, audit.OperationAddOrUpdate, addOrUpdateErr)
+				lbc.updateResourcesStatusAndEvents([]Resource{impl}, warnings, addOrUpdateErr)
 			case *IngressConfiguration:
 				if impl.IsMaster {
 					mergeableIng := lbc.createMergeableIngresses(impl)
 
+					lbc.configurator.BeginAuditRecord()
 					warnings, addOrUpdateErr := lbc.configurator.AddOrUpdateMergeableIngress(mergeableIng)
-					lbc.updateMergeableIngressStatusAndEvents(impl, warnings, addOrUpdateErr)
+					lbc.configurator.WriteAuditRecord(ingressKind, &impl.Ingress.ObjectMeta, audit.OperationAddOrUpdate, addOrUpdateErr)
+					lbc.updateResourcesStatusAndEvents([]Resource{impl}, warnings, addOrUpdateErr)
 				} else {
 					// for regular Ingress, validMinionPaths is nil
 					ingEx := lbc.createIngressEx(impl.Ingress, impl.ValidHosts, nil)
 
+					lbc.configurator.BeginAuditRecord()
 					warnings, addOrUpdateErr := lbc.configurator.AddOrUpdateIngress(ingEx)
-					lbc.updateRegularIngressStatusAndEvents(impl, warnings, addOrUpdateErr)
+					lbc.configurator.WriteAuditRecord(ingressKind, &impl.Ingress.ObjectMeta, audit.OperationAddOrUpdate, addOrUpdateErr)
+					lbc.updateResourcesStatusAndEvents([]Resource{impl}, warnings, addOrUpdateErr)
 				}
 			case *TransportServerConfiguration:
 				tsEx := lbc.createTransportServerEx(impl.TransportServer, impl.ListenerPort, impl.IPv4, impl.IPv6)
+				lbc.configurator.BeginAuditRecord()
 				warnings, addOrUpdateErr := lbc.configurator.AddOrUpdateTransportServer(tsEx)
-				lbc.updateTransportServerStatusAndEvents(impl, warnings, addOrUpdateErr)
+				lbc.configurator.WriteAuditRecord(transportServerKind, &impl.TransportServer.ObjectMeta, audit.OperationAddOrUpdate, addOrUpdateErr)
+				lbc.updateResourcesStatusAndEvents([]Resource{impl}, warnings, addOrUpdateErr)
 			}
 		} else if c.Op == Delete {
 			switch impl := c.Resource.(type) {
 			case *VirtualServerConfiguration:
 				key := getResourceKey(&impl.VirtualServer.ObjectMeta)
 
+				lbc.configurator.BeginAuditRecord()
 				deleteErr := lbc.configurator.DeleteVirtualServer(key, false)
+				lbc.configurator.WriteAuditRecord(virtualServerKind, &impl.VirtualServer.ObjectMeta, audit.OperationDelete, deleteErr)
 				if deleteErr != nil {
 					nl.Errorf(lbc.Logger, "Error when deleting configuration for VirtualServer %v: %v", key, deleteErr)
 				}
@@ -1311,7 +1415,9 @@ func (lbc *LoadBalancerController) processChanges(changes []ResourceChange) {
 
 				nl.Debugf(lbc.Logger, "Deleting Ingress: %v\n", key)
 
+				lbc.configurator.BeginAuditRecord()
 				deleteErr := lbc.configurator.DeleteIngress(key, false)
+				lbc.configurator.WriteAuditRecord(ingressKind, &impl.Ingress.ObjectMeta, audit.OperationDelete, deleteErr)
 				if deleteErr != nil {
 					nl.Errorf(lbc.Logger, "Error when deleting configuration for Ingress %v: %v", key, deleteErr)
 				}
@@ -1331,8 +1437,9 @@ func (lbc *LoadBalancerController) processChanges(changes []ResourceChange) {
 			case *TransportServerConfiguration:
 				key := getResourceKey(&impl.TransportServer.ObjectMeta)
 
+				lbc.configurator.BeginAuditRecord()
 				deleteErr := lbc.configurator.DeleteTransportServer(key)
-
+				lbc.configurator.WriteAuditRecord(transportServerKind, &impl.TransportServer.ObjectMeta, audit.OperationDelete, deleteErr)
 				if deleteErr != nil {
 					nl.Errorf(lbc.Logger, "Error when deleting configuration for TransportServer %v: %v", key, deleteErr)
 				}
@@ -1430,19 +1537,26 @@ func (lbc *LoadBalancerController) UpdateIngressStatusAndEventsOnDelete(ingConfi
 }
 
 func (lbc *LoadBalancerController) updateResourcesStatusAndEvents(resources []Resource, warnings configs.Warnings, operationErr error) {
+	lbc.syncTracker.recordResults(resources, operationErr, time.Now())
 	for _, r := range resources {
-		switch impl := r.(type) {
-		case *VirtualServerConfiguration:
-			lbc.updateVirtualServerStatusAndEvents(impl, warnings, operationErr)
-		case *IngressConfiguration:
-			if impl.IsMaster {
-				lbc.updateMergeableIngressStatusAndEvents(impl, warnings, operationErr)
-			} else {
-				lbc.updateRegularIngressStatusAndEvents(impl, warnings, operationErr)
-			}
-		case *TransportServerConfiguration:
-			lbc.updateTransportServerStatusAndEvents(impl, warnings, operationErr)
+		lbc.updateResourceStatusAndEvents(r, warnings, operationErr)
+	}
+}
+
+// updateResourceStatusAndEvents updates the status and emits the events of a resource
+// without recording the outcome of applying its changes.
+func (lbc *LoadBalancerController) updateResourceStatusAndEvents(r Resource, warnings configs.Warnings, operationErr error) {
+	switch impl := r.(type) {
+	case *VirtualServerConfiguration:
+		lbc.updateVirtualServerStatusAndEvents(impl, warnings, operationErr)
+	case *IngressConfiguration:
+		if impl.IsMaster {
+			lbc.updateMergeableIngressStatusAndEvents(impl, warnings, operationErr)
+		} else {
+			lbc.updateRegularIngressStatusAndEvents(impl, warnings, operationErr)
 		}
+	case *TransportServerConfiguration:
+		lbc.updateTransportServerStatusAndEvents(impl, warnings, operationErr)
 	}
 }
 
@@ -1592,6 +1706,12 @@ func (lbc *LoadBalancerController) updateVirtualServerStatusAndEvents(vsConfig *
 		state = conf_v1.StateInvalid
 	}
 
+	if state == conf_v1.StateValid && vsConfig.VirtualServer.Spec.Maintenance != nil && vsConfig.VirtualServer.Spec.Maintenance.Enable {
+		eventTitle = nl.EventReasonAddedOrUpdatedInMaintenance
+		eventWarningMessage = "in maintenance mode"
+		state = conf_v1.StateMaintenance
+	}
+
 	msg := fmt.Sprintf("Configuration for %v was added or updated %s", getResourceKey(&vsConfig.VirtualServer.ObjectMeta), eventWarningMessage)
 	lbc.recorder.Eventf(vsConfig.VirtualServer, eventType, eventTitle, msg)
 
@@ -1757,16 +1877,7 @@ func (lbc *LoadBalancerController) syncSecret(task task) {
 		return
 	}
 
-	resources := lbc.configuration.FindResourcesForSecret(namespace, name)
-
-	if lbc.areCustomResourcesEnabled {
-		secretPols := lbc.getPoliciesForSecret(namespace, name)
-		for _, pol := range secretPols {
-			resources = append(resources, lbc.configuration.FindResourcesForPolicy(pol.Namespace, pol.Name)...)
-		}
-
-		resources = removeDuplicateResources(resources)
-	}
+	resources := lbc.findResourcesForSecret(namespace, name)
 
 	nl.Debugf(lbc.Logger, "Found %v Resources with Secret %v", len(resources), key)
 
@@ -1802,6 +1913,22 @@ func (lbc *LoadBalancerController) syncSecret(task task) {
 	}
 }
 
+// findResourcesForSecret finds the resources that reference the secret directly or through a policy.
+func (lbc *LoadBalancerController) findResourcesForSecret(namespace string, name string) []Resource {
+	resources := lbc.configuration.FindResourcesForSecret(namespace, name)
+
+	if lbc.areCustomResourcesEnabled {
+		secretPols := lbc.getPoliciesForSecret(namespace, name)
+		for _, pol := range secretPols {
+			resources = append(resources, lbc.configuration.FindResourcesForPolicy(pol.Namespace, pol.Name)...)
+		}
+
+		resources = removeDuplicateResources(resources)
+	}
+
+	return resources
+}
+
 func removeDuplicateResources(resources []Resource) []Resource {
 	encountered := make(map[string]bool)
 	var uniqueResources []Resource
@@ -2012,6 +2139,8 @@ func getStatusFromEventTitle(eventTitle string) string {
 		return conf_v1.StateWarning
 	case "AddedOrUpdated", "Updated":
 		return conf_v1.StateValid
+	case "AddedOrUpdatedInMaintenance":
+		return conf_v1.StateMaintenance
 	}
 
 	return ""
@@ -2163,6 +2292,10 @@ func (lbc *LoadBalancerController) createIngressEx(ing *networking.Ingress, vali
 		ingEx.SecretRefs[secretName] = secretRef
 	}
 
+	if _, exists := ingEx.Ingress.Annotations[configs.PoliciesAnnotation]; exists && lbc.areCustomResourcesEnabled {
+		lbc.addIngressPolicies(ingEx)
+	}
+
 	if lbc.isNginxPlus {
 		if jwtKey, exists := ingEx.Ingress.Annotations[configs.JWTKeyAnnotation]; exists {
 			secretName := jwtKey
@@ -2340,11 +2473,12 @@ func (lbc *LoadBalancerController) createIngressEx(ing *networking.Ingress, vali
 
 func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.VirtualServer, virtualServerRoutes []*conf_v1.VirtualServerRoute) *configs.VirtualServerEx {
 	virtualServerEx := configs.VirtualServerEx{
-		VirtualServer:  virtualServer,
-		SecretRefs:     make(map[string]*secrets.SecretReference),
-		ApPolRefs:      make(map[string]*unstructured.Unstructured),
-		LogConfRefs:    make(map[string]*unstructured.Unstructured),
-		DosProtectedEx: make(map[string]*configs.DosEx),
+		VirtualServer:    virtualServer,
+		SecretRefs:       make(map[string]*secrets.SecretReference),
+		ApPolRefs:        make(map[string]*unstructured.Unstructured),
+		LogConfRefs:      make(map[string]*unstructured.Unstructured),
+		DosProtectedEx:   make(map[string]*configs.DosEx),
+		DeniedReferences: make(map[string]error),
 	}
 
 	resource := lbc.configuration.hosts[virtualServer.Spec.Host]
@@ -2372,6 +2506,7 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 	for _, err := range policyErrors {
 		nl.Warnf(lbc.Logger, "Error getting policy for VirtualServer %s/%s: %v", virtualServer.Namespace, virtualServer.Name, err)
 	}
+	policies = lbc.referenceGrantChecker.removeDeniedPolicies(virtualServerEx.DeniedReferences, conf_v1.ReferenceGrantKindVirtualServer, virtualServer.Namespace, policies)
 
 	err := lbc.addJWTSecretRefs(virtualServerEx.SecretRefs, policies)
 	if err != nil {
@@ -2397,12 +2532,29 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 	if err != nil {
 		nl.Warnf(lbc.Logger, "Error getting APIKey secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
 	}
+	err = lbc.addOAuth2IntrospectionSecretRefs(virtualServerEx.SecretRefs, policies)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting OAuth2Introspection secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+	}
+	err = lbc.addSignatureVerificationSecretRefs(virtualServerEx.SecretRefs, policies)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting SignatureVerification secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+	}
+	err = lbc.addChallengeSecretRefs(virtualServerEx.SecretRefs, policies)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting Challenge secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+	}
 
 	err = lbc.addWAFPolicyRefs(virtualServerEx.ApPolRefs, virtualServerEx.LogConfRefs, policies)
 	if err != nil {
 		nl.Warnf(lbc.Logger, "Error getting App Protect resource for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
 	}
 
+	err = lbc.addObjectStorageSecretRefs(virtualServerEx.SecretRefs, virtualServer.Namespace, virtualServer.Spec.Routes)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting ObjectStorage secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+	}
+
 	if virtualServer.Spec.Dos != "" {
 		dosEx, err := lbc.dosConfiguration.GetValidDosEx(virtualServer.Namespace, virtualServer.Spec.Dos)
 		if err != nil {
@@ -2420,28 +2572,33 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 	// generateBackupEndpoints takes the Upstream, determines if backup and backup port are defined.
 	// If backup and backup port are defined it generates a backup server entry for the upstream.
 	// Backup Service is of type ExternalName.
-	generateBackupEndpoints := func(endpoints map[string][]string, u conf_v1.Upstream) {
+	generateBackupEndpoints := func(endpoints map[string][]string, upstreamNamespace string, u conf_v1.Upstream) {
 		if u.Backup == "" || u.BackupPort == nil {
 			return
 		}
-		backupEndpointsKey := configs.GenerateEndpointsKey(virtualServer.Namespace, u.Backup, u.Subselector, *u.BackupPort)
-		backupEndps, external, err := lbc.getEndpointsForUpstream(virtualServer.Namespace, u.Backup, *u.BackupPort)
+		backupEndpointsKey := configs.GenerateEndpointsKey(upstreamNamespace, u.Backup, u.Subselector, *u.BackupPort)
+		backupEndps, external, err := lbc.getEndpointsForUpstream(upstreamNamespace, u.Backup, *u.BackupPort)
 		if err != nil {
 			nl.Warnf(lbc.Logger, "Error getting Endpoints for Upstream %v: %v", u.Name, err)
 		}
 		if err == nil && external {
-			externalNameSvcs[configs.GenerateExternalNameSvcKey(virtualServer.Namespace, u.Backup)] = true
+			externalNameSvcs[configs.GenerateExternalNameSvcKey(upstreamNamespace, u.Backup)] = true
 		}
 		bendps := getIPAddressesFromEndpoints(backupEndps)
 		endpoints[backupEndpointsKey] = bendps
 	}
 
 	for _, u := range virtualServer.Spec.Upstreams {
-		endpointsKey := configs.GenerateEndpointsKey(virtualServer.Namespace, u.Service, u.Subselector, u.Port)
+		if !lbc.referenceGrantChecker.addDeniedServiceReferences(virtualServerEx.DeniedReferences, conf_v1.ReferenceGrantKindVirtualServer, virtualServer.Namespace, u) {
+			continue
+		}
+
+		upstreamNamespace := configs.GetUpstreamNamespace(u, virtualServer.Namespace)
+		endpointsKey := configs.GenerateEndpointsKey(upstreamNamespace, u.Service, u.Subselector, u.Port)
 
 		var endps []string
 		if u.UseClusterIP {
-			s, err := lbc.getServiceForUpstream(virtualServer.Namespace, u.Service, u.Port)
+			s, err := lbc.getServiceForUpstream(upstreamNamespace, u.Service, u.Port)
 			if err != nil {
 				nl.Warnf(lbc.Logger, "Error getting Service for Upstream %v: %v", u.Service, err)
 			} else {
@@ -2453,13 +2610,13 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 			var err error
 
 			if len(u.Subselector) > 0 {
-				podEndps, err = lbc.getEndpointsForSubselector(virtualServer.Namespace, u)
+				podEndps, err = lbc.getEndpointsForSubselector(upstreamNamespace, u)
 			} else {
 				var external bool
-				podEndps, external, err = lbc.getEndpointsForUpstream(virtualServer.Namespace, u.Service, u.Port)
+				podEndps, external, err = lbc.getEndpointsForUpstream(upstreamNamespace, u.Service, u.Port)
 
 				if err == nil && external && lbc.isNginxPlus {
-					externalNameSvcs[configs.GenerateExternalNameSvcKey(virtualServer.Namespace, u.Service)] = true
+					externalNameSvcs[configs.GenerateExternalNameSvcKey(upstreamNamespace, u.Service)] = true
 				}
 			}
 
@@ -2479,7 +2636,7 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 			}
 		}
 
-		generateBackupEndpoints(endpoints, u)
+		generateBackupEndpoints(endpoints, upstreamNamespace, u)
 		endpoints[endpointsKey] = endps
 	}
 
@@ -2488,6 +2645,7 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 		for _, err := range policyErrors {
 			nl.Warnf(lbc.Logger, "Error getting policy for VirtualServer %s/%s: %v", virtualServer.Namespace, virtualServer.Name, err)
 		}
+		vsRoutePolicies = lbc.referenceGrantChecker.removeDeniedPolicies(virtualServerEx.DeniedReferences, conf_v1.ReferenceGrantKindVirtualServer, virtualServer.Namespace, vsRoutePolicies)
 		policies = append(policies, vsRoutePolicies...)
 
 		err = lbc.addJWTSecretRefs(virtualServerEx.SecretRefs, vsRoutePolicies)
@@ -2526,14 +2684,33 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 			nl.Warnf(lbc.Logger, "Error getting APIKey secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
 		}
 
+		err = lbc.addOAuth2IntrospectionSecretRefs(virtualServerEx.SecretRefs, vsRoutePolicies)
+		if err != nil {
+			nl.Warnf(lbc.Logger, "Error getting OAuth2Introspection secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+		}
+		err = lbc.addSignatureVerificationSecretRefs(virtualServerEx.SecretRefs, vsRoutePolicies)
+		if err != nil {
+			nl.Warnf(lbc.Logger, "Error getting SignatureVerification secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+		}
+		err = lbc.addChallengeSecretRefs(virtualServerEx.SecretRefs, vsRoutePolicies)
+		if err != nil {
+			nl.Warnf(lbc.Logger, "Error getting Challenge secrets for VirtualServer %v/%v: %v", virtualServer.Namespace, virtualServer.Name, err)
+		}
+
 	}
 
 	for _, vsr := range virtualServerRoutes {
+		err = lbc.addObjectStorageSecretRefs(virtualServerEx.SecretRefs, vsr.Namespace, vsr.Spec.Subroutes)
+		if err != nil {
+			nl.Warnf(lbc.Logger, "Error getting ObjectStorage secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
+		}
+
 		for _, sr := range vsr.Spec.Subroutes {
 			vsrSubroutePolicies, policyErrors := lbc.getPolicies(sr.Policies, vsr.Namespace)
 			for _, err := range policyErrors {
 				nl.Warnf(lbc.Logger, "Error getting policy for VirtualServerRoute %s/%s: %v", vsr.Namespace, vsr.Name, err)
 			}
+			vsrSubroutePolicies = lbc.referenceGrantChecker.removeDeniedPolicies(virtualServerEx.DeniedReferences, conf_v1.ReferenceGrantKindVirtualServerRoute, vsr.Namespace, vsrSubroutePolicies)
 			policies = append(policies, vsrSubroutePolicies...)
 
 			err = lbc.addJWTSecretRefs(virtualServerEx.SecretRefs, vsrSubroutePolicies)
@@ -2561,6 +2738,19 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 				nl.Warnf(lbc.Logger, "Error getting APIKey secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
 			}
 
+			err = lbc.addOAuth2IntrospectionSecretRefs(virtualServerEx.SecretRefs, vsrSubroutePolicies)
+			if err != nil {
+				nl.Warnf(lbc.Logger, "Error getting OAuth2Introspection secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
+			}
+			err = lbc.addSignatureVerificationSecretRefs(virtualServerEx.SecretRefs, vsrSubroutePolicies)
+			if err != nil {
+				nl.Warnf(lbc.Logger, "Error getting SignatureVerification secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
+			}
+			err = lbc.addChallengeSecretRefs(virtualServerEx.SecretRefs, vsrSubroutePolicies)
+			if err != nil {
+				nl.Warnf(lbc.Logger, "Error getting Challenge secrets for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
+			}
+
 			err = lbc.addWAFPolicyRefs(virtualServerEx.ApPolRefs, virtualServerEx.LogConfRefs, vsrSubroutePolicies)
 			if err != nil {
 				nl.Warnf(lbc.Logger, "Error getting WAF policies for VirtualServerRoute %v/%v: %v", vsr.Namespace, vsr.Name, err)
@@ -2576,11 +2766,16 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 		}
 
 		for _, u := range vsr.Spec.Upstreams {
-			endpointsKey := configs.GenerateEndpointsKey(vsr.Namespace, u.Service, u.Subselector, u.Port)
+			if !lbc.referenceGrantChecker.addDeniedServiceReferences(virtualServerEx.DeniedReferences, conf_v1.ReferenceGrantKindVirtualServerRoute, vsr.Namespace, u) {
+				continue
+			}
+
+			upstreamNamespace := configs.GetUpstreamNamespace(u, vsr.Namespace)
+			endpointsKey := configs.GenerateEndpointsKey(upstreamNamespace, u.Service, u.Subselector, u.Port)
 
 			var endps []string
 			if u.UseClusterIP {
-				s, err := lbc.getServiceForUpstream(vsr.Namespace, u.Service, u.Port)
+				s, err := lbc.getServiceForUpstream(upstreamNamespace, u.Service, u.Port)
 				if err != nil {
 					nl.Warnf(lbc.Logger, "Error getting Service for Upstream %v: %v", u.Service, err)
 				} else {
@@ -2591,13 +2786,13 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 				var podEndps []podEndpoint
 				var err error
 				if len(u.Subselector) > 0 {
-					podEndps, err = lbc.getEndpointsForSubselector(vsr.Namespace, u)
+					podEndps, err = lbc.getEndpointsForSubselector(upstreamNamespace, u)
 				} else {
 					var external bool
-					podEndps, external, err = lbc.getEndpointsForUpstream(vsr.Namespace, u.Service, u.Port)
+					podEndps, external, err = lbc.getEndpointsForUpstream(upstreamNamespace, u.Service, u.Port)
 
 					if err == nil && external && lbc.isNginxPlus {
-						externalNameSvcs[configs.GenerateExternalNameSvcKey(vsr.Namespace, u.Service)] = true
+						externalNameSvcs[configs.GenerateExternalNameSvcKey(upstreamNamespace, u.Service)] = true
 					}
 				}
 				if err != nil {
@@ -2616,7 +2811,7 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 				}
 			}
 
-			generateBackupEndpoints(endpoints, u)
+			generateBackupEndpoints(endpoints, upstreamNamespace, u)
 			endpoints[endpointsKey] = endps
 		}
 	}
@@ -2648,7 +2843,7 @@ func (lbc *LoadBalancerController) getAllPolicies() []*conf_v1.Policy {
 		for _, obj := range nsi.policyLister.List() {
 			pol := obj.(*conf_v1.Policy)
 
-			err := validation.ValidatePolicy(pol, lbc.isNginxPlus, lbc.enableOIDC, lbc.appProtectEnabled)
+			err := validation.ValidatePolicy(pol, lbc.isNginxPlus, lbc.enableOIDC, lbc.appProtectEnabled, lbc.geoIP2Enabled)
 			if err != nil {
 				nl.Debugf(lbc.Logger, "Skipping invalid Policy %s/%s: %v", pol.Namespace, pol.Name, err)
 				continue
@@ -2661,6 +2856,52 @@ func (lbc *LoadBalancerController) getAllPolicies() []*conf_v1.Policy {
 	return policies
 }
 
+// addIngressPolicies adds the Policies referenced in the nginx.org/policies annotation of an Ingress
+// and the Secrets referenced by those Policies to the IngressEx.
+func (lbc *LoadBalancerController) addIngressPolicies(ingEx *configs.IngressEx) {
+	ing := ingEx.Ingress
+
+	policyRefs, err := configs.ParsePolicyReferences(ing.Annotations[configs.PoliciesAnnotation])
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error parsing the policies of Ingress %s/%s: %v", ing.Namespace, ing.Name, err)
+		return
+	}
+
+	policies, policyErrors := lbc.getPolicies(policyRefs, ing.Namespace)
+	for _, err := range policyErrors {
+		nl.Warnf(lbc.Logger, "Error getting policy for Ingress %s/%s: %v", ing.Namespace, ing.Name, err)
+	}
+	ingEx.DeniedReferences = make(map[string]error)
+	policies = lbc.referenceGrantChecker.removeDeniedPolicies(ingEx.DeniedReferences, conf_v1.ReferenceGrantKindIngress, ing.Namespace, policies)
+
+	err = lbc.addJWTSecretRefs(ingEx.SecretRefs, policies)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting JWT secrets for Ingress %v/%v: %v", ing.Namespace, ing.Name, err)
+	}
+	err = lbc.addBasicSecretRefs(ingEx.SecretRefs, policies)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting Basic Auth secrets for Ingress %v/%v: %v", ing.Namespace, ing.Name, err)
+	}
+	err = lbc.addIngressMTLSSecretRefs(ingEx.SecretRefs, policies)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting IngressMTLS secret for Ingress %v/%v: %v", ing.Namespace, ing.Name, err)
+	}
+	err = lbc.addEgressMTLSSecretRefs(ingEx.SecretRefs, policies)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting EgressMTLS secrets for Ingress %v/%v: %v", ing.Namespace, ing.Name, err)
+	}
+	err = lbc.addOIDCSecretRefs(ingEx.SecretRefs, policies)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting OIDC secrets for Ingress %v/%v: %v", ing.Namespace, ing.Name, err)
+	}
+	err = lbc.addAPIKeySecretRefs(ingEx.SecretRefs, policies)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "Error getting APIKey secrets for Ingress %v/%v: %v", ing.Namespace, ing.Name, err)
+	}
+
+	ingEx.Policies = createPolicyMap(policies)
+}
+
 func (lbc *LoadBalancerController) getPolicies(policies []conf_v1.PolicyReference, ownerNamespace string) ([]*conf_v1.Policy, []error) {
 	var result []*conf_v1.Policy
 	var errors []error
@@ -2701,7 +2942,7 @@ func (lbc *LoadBalancerController) getPolicies(policies []conf_v1.PolicyReferenc
 			continue
 		}
 
-		err = validation.ValidatePolicy(policy, lbc.isNginxPlus, lbc.enableOIDC, lbc.appProtectEnabled)
+		err = validation.ValidatePolicy(policy, lbc.isNginxPlus, lbc.enableOIDC, lbc.appProtectEnabled, lbc.geoIP2Enabled)
 		if err != nil {
 			errors = append(errors, fmt.Errorf("policy %s is invalid: %w", policyKey, err))
 			continue
@@ -2723,7 +2964,7 @@ func (lbc *LoadBalancerController) addJWTSecretRefs(secretRefs map[string]*secre
 			continue
 		}
 
-		secretKey := fmt.Sprintf("%v/%v", pol.Namespace, pol.Spec.JWTAuth.Secret)
+		secretKey := configs.GeneratePolicySecretKey(pol.Namespace, pol.Spec.JWTAuth.Secret)
 		secretRef := lbc.secretStore.GetSecret(secretKey)
 
 		secretRefs[secretKey] = secretRef
@@ -2742,7 +2983,7 @@ func (lbc *LoadBalancerController) addBasicSecretRefs(secretRefs map[string]*sec
 			continue
 		}
 
-		secretKey := fmt.Sprintf("%v/%v", pol.Namespace, pol.Spec.BasicAuth.Secret)
+		secretKey := configs.GeneratePolicySecretKey(pol.Namespace, pol.Spec.BasicAuth.Secret)
 		secretRef := lbc.secretStore.GetSecret(secretKey)
 
 		secretRefs[secretKey] = secretRef
@@ -2761,7 +3002,7 @@ func (lbc *LoadBalancerController) addIngressMTLSSecretRefs(secretRefs map[strin
 			continue
 		}
 
-		secretKey := fmt.Sprintf("%v/%v", pol.Namespace, pol.Spec.IngressMTLS.ClientCertSecret)
+		secretKey := configs.GeneratePolicySecretKey(pol.Namespace, pol.Spec.IngressMTLS.ClientCertSecret)
 		secretRef := lbc.secretStore.GetSecret(secretKey)
 
 		secretRefs[secretKey] = secretRef
@@ -2778,7 +3019,7 @@ func (lbc *LoadBalancerController) addEgressMTLSSecretRefs(secretRefs map[string
 			continue
 		}
 		if pol.Spec.EgressMTLS.TLSSecret != "" {
-			secretKey := fmt.Sprintf("%v/%v", pol.Namespace, pol.Spec.EgressMTLS.TLSSecret)
+			secretKey := configs.GeneratePolicySecretKey(pol.Namespace, pol.Spec.EgressMTLS.TLSSecret)
 			secretRef := lbc.secretStore.GetSecret(secretKey)
 
 			secretRefs[secretKey] = secretRef
@@ -2788,7 +3029,7 @@ func (lbc *LoadBalancerController) addEgressMTLSSecretRefs(secretRefs map[string
 			}
 		}
 		if pol.Spec.EgressMTLS.TrustedCertSecret != "" {
-			secretKey := fmt.Sprintf("%v/%v", pol.Namespace, pol.Spec.EgressMTLS.TrustedCertSecret)
+			secretKey := configs.GeneratePolicySecretKey(pol.Namespace, pol.Spec.EgressMTLS.TrustedCertSecret)
 			secretRef := lbc.secretStore.GetSecret(secretKey)
 
 			secretRefs[secretKey] = secretRef
@@ -2804,11 +3045,12 @@ func (lbc *LoadBalancerController) addEgressMTLSSecretRefs(secretRefs map[string
 
 func (lbc *LoadBalancerController) addOIDCSecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
 	for _, pol := range policies {
-		if pol.Spec.OIDC == nil {
+		// With PKCE, the client is public and has no client secret.
+		if pol.Spec.OIDC == nil || pol.Spec.OIDC.PKCEEnable {
 			continue
 		}
 
-		secretKey := fmt.Sprintf("%v/%v", pol.Namespace, pol.Spec.OIDC.ClientSecret)
+		secretKey := configs.GeneratePolicySecretKey(pol.Namespace, pol.Spec.OIDC.ClientSecret)
 		secretRef := lbc.secretStore.GetSecret(secretKey)
 
 		secretRefs[secretKey] = secretRef
@@ -2826,7 +3068,7 @@ func (lbc *LoadBalancerController) addAPIKeySecretRefs(secretRefs map[string]*se
 			continue
 		}
 
-		secretKey := fmt.Sprintf("%v/%v", pol.Namespace, pol.Spec.APIKey.ClientSecret)
+		secretKey := configs.GeneratePolicySecretKey(pol.Namespace, pol.Spec.APIKey.ClientSecret)
 		secretRef := lbc.secretStore.GetSecret(secretKey)
 
 		secretRefs[secretKey] = secretRef
@@ -2839,6 +3081,78 @@ func (lbc *LoadBalancerController) addAPIKeySecretRefs(secretRefs map[string]*se
 	return nil
 }
 
+func (lbc *LoadBalancerController) addOAuth2IntrospectionSecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
+	for _, pol := range policies {
+		if pol.Spec.OAuth2Introspection == nil {
+			continue
+		}
+
+		secretKey := configs.GeneratePolicySecretKey(pol.Namespace, pol.Spec.OAuth2Introspection.ClientSecret)
+		secretRef := lbc.secretStore.GetSecret(secretKey)
+
+		secretRefs[secretKey] = secretRef
+
+		if secretRef.Error != nil {
+			return secretRef.Error
+		}
+	}
+	return nil
+}
+
+func (lbc *LoadBalancerController) addSignatureVerificationSecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
+	for _, pol := range policies {
+		if pol.Spec.SignatureVerification == nil {
+			continue
+		}
+
+		secretKey := configs.GeneratePolicySecretKey(pol.Namespace, pol.Spec.SignatureVerification.Secret)
+		secretRef := lbc.secretStore.GetSecret(secretKey)
+
+		secretRefs[secretKey] = secretRef
+
+		if secretRef.Error != nil {
+			return secretRef.Error
+		}
+	}
+	return nil
+}
+
+func (lbc *LoadBalancerController) addChallengeSecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
+	for _, pol := range policies {
+		if pol.Spec.Challenge == nil {
+			continue
+		}
+
+		secretKey := configs.GeneratePolicySecretKey(pol.Namespace, pol.Spec.Challenge.Secret)
+		secretRef := lbc.secretStore.GetSecret(secretKey)
+
+		secretRefs[secretKey] = secretRef
+
+		if secretRef.Error != nil {
+			return secretRef.Error
+		}
+	}
+	return nil
+}
+
+func (lbc *LoadBalancerController) addObjectStorageSecretRefs(secretRefs map[string]*secrets.SecretReference, namespace string, routes []conf_v1.Route) error {
+	for _, r := range routes {
+		if r.Action == nil || r.Action.ObjectStorage == nil {
+			continue
+		}
+
+		secretKey := fmt.Sprintf("%v/%v", namespace, r.Action.ObjectStorage.Secret)
+		secretRef := lbc.secretStore.GetSecret(secretKey)
+
+		secretRefs[secretKey] = secretRef
+
+		if secretRef.Error != nil {
+			return secretRef.Error
+		}
+	}
+	return nil
+}
+
 func (lbc *LoadBalancerController) getPoliciesForSecret(secretNamespace string, secretName string) []*conf_v1.Policy {
 	return findPoliciesForSecret(lbc.getAllPolicies(), secretNamespace, secretName)
 }
@@ -2846,20 +3160,9 @@ func (lbc *LoadBalancerController) getPoliciesForSecret(secretNamespace string,
 func findPoliciesForSecret(policies []*conf_v1.Policy, secretNamespace string, secretName string) []*conf_v1.Policy {
 	var res []*conf_v1.Policy
 
+	secretKey := fmt.Sprintf("%s/%s", secretNamespace, secretName)
 	for _, pol := range policies {
-		if pol.Spec.IngressMTLS != nil && pol.Spec.IngressMTLS.ClientCertSecret == secretName && pol.Namespace == secretNamespace {
-			res = append(res, pol)
-		} else if pol.Spec.JWTAuth != nil && pol.Spec.JWTAuth.Secret == secretName && pol.Namespace == secretNamespace {
-			res = append(res, pol)
-		} else if pol.Spec.BasicAuth != nil && pol.Spec.BasicAuth.Secret == secretName && pol.Namespace == secretNamespace {
-			res = append(res, pol)
-		} else if pol.Spec.EgressMTLS != nil && pol.Spec.EgressMTLS.TLSSecret == secretName && pol.Namespace == secretNamespace {
-			res = append(res, pol)
-		} else if pol.Spec.EgressMTLS != nil && pol.Spec.EgressMTLS.TrustedCertSecret == secretName && pol.Namespace == secretNamespace {
-			res = append(res, pol)
-		} else if pol.Spec.OIDC != nil && pol.Spec.OIDC.ClientSecret == secretName && pol.Namespace == secretNamespace {
-			res = append(res, pol)
-		} else if pol.Spec.APIKey != nil && pol.Spec.APIKey.ClientSecret == secretName && pol.Namespace == secretNamespace {
+		if slices.Contains(getPolicySecretKeys(pol), secretKey) {
 			res = append(res, pol)
 		}
 	}
@@ -2867,6 +3170,41 @@ func findPoliciesForSecret(policies []*conf_v1.Policy, secretNamespace string, s
 	return res
 }
 
+// getPolicySecretKeys returns the keys of the secrets referenced by the policy.
+func getPolicySecretKeys(pol *conf_v1.Policy) []string {
+	var secretNames []string
+
+	switch {
+	case pol.Spec.IngressMTLS != nil:
+		secretNames = append(secretNames, pol.Spec.IngressMTLS.ClientCertSecret)
+	case pol.Spec.JWTAuth != nil:
+		secretNames = append(secretNames, pol.Spec.JWTAuth.Secret)
+	case pol.Spec.BasicAuth != nil:
+		secretNames = append(secretNames, pol.Spec.BasicAuth.Secret)
+	case pol.Spec.EgressMTLS != nil:
+		secretNames = append(secretNames, pol.Spec.EgressMTLS.TLSSecret, pol.Spec.EgressMTLS.TrustedCertSecret)
+	case pol.Spec.OIDC != nil:
+		secretNames = append(secretNames, pol.Spec.OIDC.ClientSecret)
+	case pol.Spec.APIKey != nil:
+		secretNames = append(secretNames, pol.Spec.APIKey.ClientSecret)
+	case pol.Spec.OAuth2Introspection != nil:
+		secretNames = append(secretNames, pol.Spec.OAuth2Introspection.ClientSecret)
+	case pol.Spec.SignatureVerification != nil:
+		secretNames = append(secretNames, pol.Spec.SignatureVerification.Secret)
+	case pol.Spec.Challenge != nil:
+		secretNames = append(secretNames, pol.Spec.Challenge.Secret)
+	}
+
+	var keys []string
+	for _, name := range secretNames {
+		if name != "" {
+			keys = append(keys, configs.GeneratePolicySecretKey(pol.Namespace, name))
+		}
+	}
+
+	return keys
+}
+
 func (lbc *LoadBalancerController) getTransportServerBackupEndpointsAndKey(transportServer *conf_v1.TransportServer, u conf_v1.TransportServerUpstream, externalNameSvcs map[string]bool) ([]string, string) {
 	backupEndpointsKey := configs.GenerateEndpointsKey(transportServer.Namespace, u.Backup, nil, *u.BackupPort)
 	backupEndps, external, err := lbc.getEndpointsForUpstream(transportServer.Namespace, u.Backup, *u.BackupPort)
@@ -3270,6 +3608,8 @@ func (lbc *LoadBalancerController) HasCorrectIngressClass(obj interface{}) bool
 		class = obj.Spec.IngressClass
 	case *conf_v1.Policy:
 		class = obj.Spec.IngressClass
+	case *conf_v1.Rollout:
+		class = obj.Spec.IngressClass
 	case *networking.Ingress:
 		class = obj.Annotations[ingressClassKey]
 		if class == "" && obj.Spec.IngressClassName != nil {
@@ -3326,41 +3666,8 @@ func (lbc *LoadBalancerController) addInternalRouteServer() {
 }
 
 func (lbc *LoadBalancerController) processVSWeightChangesDynamicReload(vsOld *conf_v1.VirtualServer, vsNew *conf_v1.VirtualServer) {
-	var weightUpdates []configs.WeightUpdate
-	var splitClientsIndex int
 	variableNamer := configs.NewVSVariableNamer(vsNew)
-
-	for i, routeNew := range vsNew.Spec.Routes {
-		routeOld := vsOld.Spec.Routes[i]
-		for j, matchNew := range routeNew.Matches {
-			matchOld := routeOld.Matches[j]
-			if len(matchNew.Splits) == 2 {
-				if matchNew.Splits[0].Weight != matchOld.Splits[0].Weight || matchNew.Splits[1].Weight != matchOld.Splits[1].Weight {
-					weightUpdates = append(weightUpdates, configs.WeightUpdate{
-						Zone:  variableNamer.GetNameOfKeyvalZoneForSplitClientIndex(splitClientsIndex),
-						Key:   variableNamer.GetNameOfKeyvalKeyForSplitClientIndex(splitClientsIndex),
-						Value: variableNamer.GetNameOfKeyOfMapForWeights(splitClientsIndex, matchNew.Splits[0].Weight, matchNew.Splits[1].Weight),
-					})
-				}
-				splitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-			} else if len(matchNew.Splits) > 0 {
-				splitClientsIndex++
-			}
-		}
-		if len(routeNew.Splits) == 2 {
-			if routeNew.Splits[0].Weight != routeOld.Splits[0].Weight || routeNew.Splits[1].Weight != routeOld.Splits[1].Weight {
-				weightUpdates = append(weightUpdates, configs.WeightUpdate{
-					Zone:  variableNamer.GetNameOfKeyvalZoneForSplitClientIndex(splitClientsIndex),
-					Key:   variableNamer.GetNameOfKeyvalKeyForSplitClientIndex(splitClientsIndex),
-					Value: variableNamer.GetNameOfKeyOfMapForWeights(splitClientsIndex, routeNew.Splits[0].Weight, routeNew.Splits[1].Weight),
-				})
-				splitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-			}
-			splitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-		} else if len(routeNew.Splits) > 0 {
-			splitClientsIndex++
-		}
-	}
+	weightUpdates := generateWeightUpdates(variableNamer, vsOld.Spec.Routes, vsNew.Spec.Routes, 0)
 
 	if len(weightUpdates) == 0 {
 		return
@@ -3397,14 +3704,27 @@ func (lbc *LoadBalancerController) processVSRWeightChangesDynamicReload(vsrOld *
 		return
 	}
 
-	var weightUpdates []configs.WeightUpdate
-
 	splitClientsIndex := lbc.getStartingSplitClientsIndex(vsrNew, vsEx)
-
 	variableNamer := configs.NewVSVariableNamer(vsEx.VirtualServer)
+	weightUpdates := generateWeightUpdates(variableNamer, vsrOld.Spec.Subroutes, vsrNew.Spec.Subroutes, splitClientsIndex)
 
-	for i, routeNew := range vsrNew.Spec.Subroutes {
-		routeOld := vsrOld.Spec.Subroutes[i]
+	if halt {
+		return
+	}
+
+	for _, weight := range weightUpdates {
+		lbc.configurator.UpsertSplitClientsKeyVal(weight.Zone, weight.Key, weight.Value)
+	}
+}
+
+// generateWeightUpdates returns the weight updates of the split clients of the routes with changed weights.
+// The split clients are numbered from splitClientsIndex in the same order as in the generated config,
+// where each route or match with two splits uses splitClientAmountWhenWeightChangesDynamicReload split clients.
+func generateWeightUpdates(variableNamer *configs.VariableNamer, routesOld []conf_v1.Route, routesNew []conf_v1.Route, splitClientsIndex int) []configs.WeightUpdate {
+	var weightUpdates []configs.WeightUpdate
+
+	for i, routeNew := range routesNew {
+		routeOld := routesOld[i]
 		for j, matchNew := range routeNew.Matches {
 			matchOld := routeOld.Matches[j]
 			if len(matchNew.Splits) == 2 {
@@ -3420,46 +3740,29 @@ func (lbc *LoadBalancerController) processVSRWeightChangesDynamicReload(vsrOld *
 				splitClientsIndex++
 			}
 		}
-		if len(routeNew.Splits) == 2 {
-			if routeNew.Splits[0].Weight != routeOld.Splits[0].Weight || routeNew.Splits[1].Weight != routeOld.Splits[1].Weight {
+		splitsNew, splitsOld := routeSplits(routeNew), routeSplits(routeOld)
+		if len(splitsNew) == 2 {
+			if splitsNew[0].Weight != splitsOld[0].Weight || splitsNew[1].Weight != splitsOld[1].Weight {
 				weightUpdates = append(weightUpdates, configs.WeightUpdate{
 					Zone:  variableNamer.GetNameOfKeyvalZoneForSplitClientIndex(splitClientsIndex),
 					Key:   variableNamer.GetNameOfKeyvalKeyForSplitClientIndex(splitClientsIndex),
-					Value: variableNamer.GetNameOfKeyOfMapForWeights(splitClientsIndex, routeNew.Splits[0].Weight, routeNew.Splits[1].Weight),
+					Value: variableNamer.GetNameOfKeyOfMapForWeights(splitClientsIndex, splitsNew[0].Weight, splitsNew[1].Weight),
 				})
 			}
 			splitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-		} else if len(routeNew.Splits) > 0 {
+		} else if len(splitsNew) > 0 {
 			splitClientsIndex++
 		}
 	}
 
-	if halt {
-		return
-	}
-
-	for _, weight := range weightUpdates {
-		lbc.configurator.UpsertSplitClientsKeyVal(weight.Zone, weight.Key, weight.Value)
-	}
+	return weightUpdates
 }
 
 func (lbc *LoadBalancerController) getStartingSplitClientsIndex(vsr *conf_v1.VirtualServerRoute, vsEx *configs.VirtualServerEx) int {
 	var startingSplitClientsIndex int
 
 	for _, r := range vsEx.VirtualServer.Spec.Routes {
-		for _, match := range r.Matches {
-			if len(match.Splits) == 2 {
-				startingSplitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-			} else if len(match.Splits) > 0 {
-				startingSplitClientsIndex++
-			}
-		}
-		if len(r.Splits) == 2 {
-			startingSplitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-		} else if len(r.Splits) > 0 {
-			startingSplitClientsIndex++
-		}
-
+		startingSplitClientsIndex += getSplitClientsAmount(r)
 	}
 
 	for _, vsRoute := range vsEx.VirtualServerRoutes {
@@ -3467,24 +3770,32 @@ func (lbc *LoadBalancerController) getStartingSplitClientsIndex(vsr *conf_v1.Vir
 			return startingSplitClientsIndex
 		}
 		for _, r := range vsRoute.Spec.Subroutes {
-			for _, match := range r.Matches {
-				if len(match.Splits) == 2 {
-					startingSplitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-				} else if len(match.Splits) > 0 {
-					startingSplitClientsIndex++
-				}
-			}
-			if len(r.Splits) == 2 {
-				startingSplitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-			} else if len(r.Splits) > 0 {
-				startingSplitClientsIndex++
-			}
+			startingSplitClientsIndex += getSplitClientsAmount(r)
 		}
 	}
 
 	return startingSplitClientsIndex
 }
 
+// getSplitClientsAmount returns the number of the split clients generated for the splits of a route and its matches
+// when weight changes are applied without reloads.
+func getSplitClientsAmount(r conf_v1.Route) int {
+	var amount int
+	for _, match := range r.Matches {
+		if len(match.Splits) == 2 {
+			amount += splitClientAmountWhenWeightChangesDynamicReload
+		} else if len(match.Splits) > 0 {
+			amount++
+		}
+	}
+	if splits := routeSplits(r); len(splits) == 2 {
+		amount += splitClientAmountWhenWeightChangesDynamicReload
+	} else if len(splits) > 0 {
+		amount++
+	}
+	return amount
+}
+
 func (lbc *LoadBalancerController) haltIfVSConfigInvalid(vsNew *conf_v1.VirtualServer) bool {
 	lbc.configuration.lock.Lock()
 	defer lbc.configuration.lock.Unlock()
@@ -3537,7 +3848,9 @@ func (lbc *LoadBalancerController) haltIfVSConfigInvalid(vsNew *conf_v1.VirtualS
 			case *VirtualServerConfiguration:
 				key := getResourceKey(&impl.VirtualServer.ObjectMeta)
 
+				lbc.configurator.BeginAuditRecord()
 				deleteErr := lbc.configurator.DeleteVirtualServer(key, false)
+				lbc.configurator.WriteAuditRecord(virtualServerKind, &impl.VirtualServer.ObjectMeta, audit.OperationDelete, deleteErr)
 				if deleteErr != nil {
 					nl.Errorf(lbc.Logger, "Error when deleting configuration for VirtualServer %v: %v", key, deleteErr)
 				}
@@ -3610,9 +3923,22 @@ func (lbc *LoadBalancerController) vsrHasWeightChanges(vsrOld *conf_v1.VirtualSe
 				return true
 			}
 		}
-		if len(routeNew.Splits) == 2 && (routeNew.Splits[0].Weight != routeOld.Splits[0].Weight || routeNew.Splits[1].Weight != routeOld.Splits[1].Weight) {
+		splitsNew, splitsOld := routeSplits(routeNew), routeSplits(routeOld)
+		if len(splitsNew) == 2 && (splitsNew[0].Weight != splitsOld[0].Weight || splitsNew[1].Weight != splitsOld[1].Weight) {
 			return true
 		}
 	}
 	return false
 }
+
+// routeSplits returns the splits of a route. For a route with a canary release, it returns the splits
+// between the action of the route and the action of the canary with only their weights set.
+func routeSplits(route conf_v1.Route) []conf_v1.Split {
+	if route.Canary != nil {
+		return []conf_v1.Split{
+			{Weight: 100 - route.Canary.Weight},
+			{Weight: route.Canary.Weight},
+		}
+	}
+	return route.Splits
+}
@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 )
 
 func TestHasCorrectIngressClass(t *testing.T) {
@@ -2009,6 +2010,10 @@ func TestGetStatusFromEventTitle(t *testing.T) {
 			eventTitle: "Updated",
 			expected:   "Valid",
 		},
+		{
+			eventTitle: "AddedOrUpdatedInMaintenance",
+			expected:   "Maintenance",
+		},
 		{
 			eventTitle: "New State",
 			expected:   "",
@@ -2109,7 +2114,7 @@ func TestGetPoliciesGlobalWatch(t *testing.T) {
 
 	expectedPolicies := []*conf_v1.Policy{validPolicy}
 	expectedErrors := []error{
-		errors.New("policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`, `jwt`, `oidc`, `waf`"),
+		errors.New("policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`, `retry`, `circuitBreaker`, `headers`, `requestLimits`, `oauth2Introspection`, `signatureVerification`, `challenge`, `bandwidthLimit`, `jwt`, `oidc`, `waf`"),
 		errors.New("policy nginx-ingress/valid-policy doesn't exist"),
 		errors.New("failed to get policy nginx-ingress/some-policy: GetByKey error"),
 		errors.New("referenced policy default/valid-policy-ingress-class has incorrect ingress class: test-class (controller ingress class: )"),
@@ -2207,7 +2212,7 @@ func TestGetPoliciesNamespacedWatch(t *testing.T) {
 
 	expectedPolicies := []*conf_v1.Policy{validPolicy}
 	expectedErrors := []error{
-		errors.New("policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`, `jwt`, `oidc`, `waf`"),
+		errors.New("policy default/invalid-policy is invalid: spec: Invalid value: \"\": must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`, `retry`, `circuitBreaker`, `headers`, `requestLimits`, `oauth2Introspection`, `signatureVerification`, `challenge`, `bandwidthLimit`, `jwt`, `oidc`, `waf`"),
 		errors.New("failed to get namespace nginx-ingress"),
 		errors.New("referenced policy default/valid-policy-ingress-class has incorrect ingress class: test-class (controller ingress class: )"),
 	}
@@ -2554,6 +2559,50 @@ func TestFindPoliciesForSecret(t *testing.T) {
 			},
 		},
 	}
+	introspectionPol := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "introspection-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			OAuth2Introspection: &conf_v1.OAuth2Introspection{
+				ClientSecret: "introspection-secret",
+			},
+		},
+	}
+	signaturePol := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "signature-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			SignatureVerification: &conf_v1.SignatureVerification{
+				Secret: "webhook-secret",
+			},
+		},
+	}
+	challengePol := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "challenge-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			Challenge: &conf_v1.Challenge{
+				Secret: "challenge-secret",
+			},
+		},
+	}
+	crossNamespaceJWTPol := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "jwt-policy",
+			Namespace: "ns-1",
+		},
+		Spec: conf_v1.PolicySpec{
+			JWTAuth: &conf_v1.JWTAuth{
+				Secret: "default/jwk-secret",
+			},
+		},
+	}
 
 	tests := []struct {
 		policies        []*conf_v1.Policy
@@ -2583,6 +2632,13 @@ func TestFindPoliciesForSecret(t *testing.T) {
 			expected:        []*conf_v1.Policy{jwtPol1},
 			msg:             "Find policy in default ns, ignore other",
 		},
+		{
+			policies:        []*conf_v1.Policy{jwtPol2, crossNamespaceJWTPol},
+			secretNamespace: "default",
+			secretName:      "jwk-secret",
+			expected:        []*conf_v1.Policy{crossNamespaceJWTPol},
+			msg:             "Find policy in other ns referencing a secret in default ns",
+		},
 		{
 			policies:        []*conf_v1.Policy{basicPol1},
 			secretNamespace: "default",
@@ -2660,6 +2716,27 @@ func TestFindPoliciesForSecret(t *testing.T) {
 			expected:        []*conf_v1.Policy{oidcPol},
 			msg:             "Find policy in default ns, ignore other types",
 		},
+		{
+			policies:        []*conf_v1.Policy{oidcPol, introspectionPol},
+			secretNamespace: "default",
+			secretName:      "introspection-secret",
+			expected:        []*conf_v1.Policy{introspectionPol},
+			msg:             "Find policy in default ns, ignore other types",
+		},
+		{
+			policies:        []*conf_v1.Policy{introspectionPol, signaturePol},
+			secretNamespace: "default",
+			secretName:      "webhook-secret",
+			expected:        []*conf_v1.Policy{signaturePol},
+			msg:             "Find policy in default ns, ignore other types",
+		},
+		{
+			policies:        []*conf_v1.Policy{signaturePol, challengePol},
+			secretNamespace: "default",
+			secretName:      "challenge-secret",
+			expected:        []*conf_v1.Policy{challengePol},
+			msg:             "Find policy in default ns, ignore other types",
+		},
 	}
 	for _, test := range tests {
 		result := findPoliciesForSecret(test.policies, test.secretNamespace, test.secretName)
@@ -3534,3 +3611,220 @@ func TestGenerateSecretNSName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCertificateExpiryThreshold(t *testing.T) {
+	t.Parallel()
+	day := 24 * time.Hour
+	thresholds := []time.Duration{30 * day, 7 * day, day}
+
+	tests := []struct {
+		expiresIn         time.Duration
+		expectedThreshold time.Duration
+		expectedCrossed   bool
+		msg               string
+	}{
+		{
+			expiresIn:         60 * day,
+			expectedThreshold: 0,
+			expectedCrossed:   false,
+			msg:               "no threshold crossed",
+		},
+		{
+			expiresIn:         20 * day,
+			expectedThreshold: 30 * day,
+			expectedCrossed:   true,
+			msg:               "largest threshold crossed",
+		},
+		{
+			expiresIn:         12 * time.Hour,
+			expectedThreshold: day,
+			expectedCrossed:   true,
+			msg:               "smallest threshold crossed",
+		},
+		{
+			expiresIn:         -time.Hour,
+			expectedThreshold: 0,
+			expectedCrossed:   true,
+			msg:               "certificate expired",
+		},
+	}
+
+	for _, test := range tests {
+		threshold, crossed := getCertificateExpiryThreshold(test.expiresIn, thresholds)
+		if threshold != test.expectedThreshold || crossed != test.expectedCrossed {
+			t.Errorf("getCertificateExpiryThreshold() returned (%v, %v) but expected (%v, %v) for the case of %s",
+				threshold, crossed, test.expectedThreshold, test.expectedCrossed, test.msg)
+		}
+	}
+}
+
+func TestGenerateWeightUpdates(t *testing.T) {
+	t.Parallel()
+
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	variableNamer := configs.NewVSVariableNamer(vs)
+
+	createRoutes := func(firstWeight int, secondWeight int) []conf_v1.Route {
+		return []conf_v1.Route{
+			{
+				Path: "/tea",
+				Splits: []conf_v1.Split{
+					{Weight: firstWeight, Action: &conf_v1.Action{Pass: "tea-v1"}},
+					{Weight: 100 - firstWeight, Action: &conf_v1.Action{Pass: "tea-v2"}},
+				},
+			},
+			{
+				Path: "/coffee",
+				Splits: []conf_v1.Split{
+					{Weight: secondWeight, Action: &conf_v1.Action{Pass: "coffee-v1"}},
+					{Weight: 100 - secondWeight, Action: &conf_v1.Action{Pass: "coffee-v2"}},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		routesOld         []conf_v1.Route
+		routesNew         []conf_v1.Route
+		splitClientsIndex int
+		expected          []configs.WeightUpdate
+		msg               string
+	}{
+		{
+			routesOld: createRoutes(90, 90),
+			routesNew: createRoutes(80, 90),
+			expected: []configs.WeightUpdate{
+				{
+					Zone:  "vs_default_cafe_keyval_zone_split_clients_0",
+					Key:   `"vs_default_cafe_keyval_key_split_clients_0"`,
+					Value: `"vs_default_cafe_split_clients_0_80_20"`,
+				},
+			},
+			msg: "weights of the first route changed",
+		},
+		{
+			routesOld: createRoutes(90, 90),
+			routesNew: createRoutes(90, 80),
+			expected: []configs.WeightUpdate{
+				{
+					Zone:  "vs_default_cafe_keyval_zone_split_clients_101",
+					Key:   `"vs_default_cafe_keyval_key_split_clients_101"`,
+					Value: `"vs_default_cafe_split_clients_101_80_20"`,
+				},
+			},
+			msg: "weights of the second route changed",
+		},
+		{
+			routesOld: createRoutes(90, 90),
+			routesNew: createRoutes(80, 70),
+			expected: []configs.WeightUpdate{
+				{
+					Zone:  "vs_default_cafe_keyval_zone_split_clients_0",
+					Key:   `"vs_default_cafe_keyval_key_split_clients_0"`,
+					Value: `"vs_default_cafe_split_clients_0_80_20"`,
+				},
+				{
+					Zone:  "vs_default_cafe_keyval_zone_split_clients_101",
+					Key:   `"vs_default_cafe_keyval_key_split_clients_101"`,
+					Value: `"vs_default_cafe_split_clients_101_70_30"`,
+				},
+			},
+			msg: "weights of both routes changed",
+		},
+		{
+			routesOld:         createRoutes(90, 90),
+			routesNew:         createRoutes(90, 80),
+			splitClientsIndex: 202,
+			expected: []configs.WeightUpdate{
+				{
+					Zone:  "vs_default_cafe_keyval_zone_split_clients_303",
+					Key:   `"vs_default_cafe_keyval_key_split_clients_303"`,
+					Value: `"vs_default_cafe_split_clients_303_80_20"`,
+				},
+			},
+			msg: "weights of the second subroute changed",
+		},
+		{
+			routesOld: createRoutes(90, 90),
+			routesNew: createRoutes(90, 90),
+			expected:  nil,
+			msg:       "no weights changed",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateWeightUpdates(variableNamer, test.routesOld, test.routesNew, test.splitClientsIndex)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateWeightUpdates() returned unexpected result for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGetCertificateExpiryResourceInfoDeduplicatesIngressHosts(t *testing.T) {
+	t.Parallel()
+	ing := &networking.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+		Spec: networking.IngressSpec{
+			TLS: []networking.IngressTLS{
+				{Hosts: []string{"cafe.example.com", "tea.example.com"}, SecretName: "tls-secret"},
+				{Hosts: []string{"cafe.example.com"}, SecretName: "tls-secret"},
+				{Hosts: []string{"coffee.example.com"}, SecretName: "other-secret"},
+			},
+		},
+	}
+	ingConfig := &IngressConfiguration{Ingress: ing}
+
+	_, resourceType, hosts := getCertificateExpiryResourceInfo(ingConfig, "tls-secret")
+	if resourceType != "ingress" {
+		t.Errorf("getCertificateExpiryResourceInfo() returned resource type %q but expected %q", resourceType, "ingress")
+	}
+	expected := []string{"cafe.example.com", "tea.example.com"}
+	if diff := cmp.Diff(expected, hosts); diff != "" {
+		t.Errorf("getCertificateExpiryResourceInfo() returned unexpected hosts (-want +got):\n%s", diff)
+	}
+}
+
+func TestWarnAboutCertificateExpiry(t *testing.T) {
+	t.Parallel()
+	recorder := record.NewFakeRecorder(10)
+	lbc := LoadBalancerController{
+		recorder:                    recorder,
+		certificateExpiryThresholds: []time.Duration{7 * 24 * time.Hour},
+		certificateExpiryWarnings:   make(map[string]certificateExpiryWarning),
+		// the status is not reported when the controller is not the leader
+		isLeaderElectionEnabled: true,
+		Logger:                  nl.LoggerFromContext(context.Background()),
+	}
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	vsConfig := NewVirtualServerConfiguration(vs, nil, nil)
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := now.Add(-time.Hour)
+
+	lbc.warnAboutCertificateExpiry("VirtualServer default/cafe/default/tls-secret", vsConfig, vs, "default/tls-secret", notAfter, now)
+	lbc.warnAboutCertificateExpiry("VirtualServer default/cafe/default/tls-secret", vsConfig, vs, "default/tls-secret", notAfter, now.Add(time.Hour))
+
+	var events []string
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+
+	expected := []string{
+		"Warning CertificateExpired Secret default/tls-secret holds a certificate that expired on 2023-12-31T23:00:00Z",
+	}
+	if diff := cmp.Diff(expected, events); diff != "" {
+		t.Errorf("warnAboutCertificateExpiry() emitted unexpected events (-want +got):\n%s", diff)
+	}
+}
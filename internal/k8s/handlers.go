@@ -259,6 +259,10 @@ func zeroOutVirtualServerSplitWeights(vs *conf_v1.VirtualServer) {
 			route.Splits[0].Weight = 0
 			route.Splits[1].Weight = 0
 		}
+
+		if route.Canary != nil {
+			route.Canary.Weight = 0
+		}
 	}
 }
 
@@ -275,5 +279,9 @@ func zeroOutVirtualServerRouteSplitWeights(vs *conf_v1.VirtualServerRoute) {
 			route.Splits[0].Weight = 0
 			route.Splits[1].Weight = 0
 		}
+
+		if route.Canary != nil {
+			route.Canary.Weight = 0
+		}
 	}
 }
@@ -78,7 +78,9 @@ func (lbc *LoadBalancerController) syncNamespace(task task) {
 			nsi := lbc.getNamespacedInformer(key)
 			if nsi != nil {
 				lbc.cleanupUnwatchedNamespacedResources(nsi)
+				lbc.namespacedInformersLock.Lock()
 				delete(lbc.namespacedInformers, key)
+				lbc.namespacedInformersLock.Unlock()
 			}
 		} else {
 			nl.Infof(lbc.Logger, "Deleting Watchers for Deleted Namespace: %v", key)
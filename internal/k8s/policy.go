@@ -3,7 +3,9 @@ package k8s
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/nginx/kubernetes-ingress/internal/configs"
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	"github.com/nginx/kubernetes-ingress/pkg/apis/configuration/validation"
@@ -71,7 +73,7 @@ func (lbc *LoadBalancerController) syncPolicy(task task) {
 
 	if polExists && lbc.HasCorrectIngressClass(obj) {
 		pol := obj.(*conf_v1.Policy)
-		err := validation.ValidatePolicy(pol, lbc.isNginxPlus, lbc.enableOIDC, lbc.appProtectEnabled)
+		err := validation.ValidatePolicy(pol, lbc.isNginxPlus, lbc.enableOIDC, lbc.appProtectEnabled, lbc.geoIP2Enabled)
 		if err != nil {
 			msg := fmt.Sprintf("Policy %v/%v is invalid and was rejected: %v", pol.Namespace, pol.Name, err)
 			lbc.recorder.Eventf(pol, api_v1.EventTypeWarning, nl.EventReasonRejected, msg)
@@ -82,6 +84,18 @@ func (lbc *LoadBalancerController) syncPolicy(task task) {
 					nl.Debugf(lbc.Logger, "Failed to update policy %s status: %v", key, err)
 				}
 			}
+		} else if missing := getMissingGeoIP2Databases(pol, lbc.configurator.CfgParams); len(missing) > 0 {
+			// the resources that reference the policy get a warning and return 500 too
+			msg := fmt.Sprintf("Policy %v/%v uses GeoIP2 databases that are not configured: set the ConfigMap key(s) %s",
+				pol.Namespace, pol.Name, strings.Join(missing, ", "))
+			lbc.recorder.Eventf(pol, api_v1.EventTypeWarning, nl.EventReasonAddedOrUpdatedWithWarning, msg)
+
+			if lbc.reportCustomResourceStatusEnabled() {
+				err = lbc.statusUpdater.UpdatePolicyStatus(pol, conf_v1.StateWarning, nl.EventReasonAddedOrUpdatedWithWarning, msg)
+				if err != nil {
+					nl.Debugf(lbc.Logger, "Failed to update policy %s status: %v", key, err)
+				}
+			}
 		} else {
 			msg := fmt.Sprintf("Policy %v/%v was added or updated", pol.Namespace, pol.Name)
 			lbc.recorder.Eventf(pol, api_v1.EventTypeNormal, nl.EventReasonAddedOrUpdated, msg)
@@ -101,13 +115,36 @@ func (lbc *LoadBalancerController) syncPolicy(task task) {
 	resources := lbc.configuration.FindResourcesForPolicy(namespace, name)
 	resourceExes := lbc.createExtendedResources(resources)
 
-	// Only VirtualServers support policies
-	if len(resourceExes.VirtualServerExes) == 0 {
+	if len(resourceExes.IngressExes) == 0 && len(resourceExes.MergeableIngresses) == 0 && len(resourceExes.VirtualServerExes) == 0 {
 		return
 	}
 
-	warnings, updateErr := lbc.configurator.AddOrUpdateVirtualServers(resourceExes.VirtualServerExes)
+	warnings, updateErr := lbc.configurator.AddOrUpdateResourcesThatUsePolicy(resourceExes.IngressExes, resourceExes.MergeableIngresses, resourceExes.VirtualServerExes)
 	lbc.updateResourcesStatusAndEvents(resources, warnings, updateErr)
 
 	// Note: updating the status of a policy based on a reload is not needed.
 }
+
+// getMissingGeoIP2Databases returns the ConfigMap keys of the GeoIP2 databases that a geo AccessControl policy requires but are not set.
+func getMissingGeoIP2Databases(pol *conf_v1.Policy, cfgParams *configs.ConfigParams) []string {
+	if pol.Spec.AccessControl == nil || pol.Spec.AccessControl.Geo == nil || cfgParams == nil {
+		return nil
+	}
+	return configs.GetMissingGeoIP2Databases(pol.Spec.AccessControl.Geo, cfgParams)
+}
+
+// enqueueGeoAccessControlPolicies syncs the geo AccessControl policies again to update their status,
+// which depends on the GeoIP2 databases of the ConfigMap.
+func (lbc *LoadBalancerController) enqueueGeoAccessControlPolicies() {
+	for _, nsi := range lbc.namespacedInformers {
+		if nsi.policyLister == nil {
+			continue
+		}
+		for _, obj := range nsi.policyLister.List() {
+			pol := obj.(*conf_v1.Policy)
+			if pol.Spec.AccessControl != nil && pol.Spec.AccessControl.Geo != nil {
+				lbc.AddSyncQueue(pol)
+			}
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+)
+
+// ReadinessStatus describes the readiness of the Ingress Controller and the state of the sync of the configuration.
+type ReadinessStatus struct {
+	Ready                     bool                    `json:"ready"`
+	NginxReady                bool                    `json:"nginxReady"`
+	Reasons                   []string                `json:"reasons,omitempty"`
+	ConsecutiveReloadFailures int                     `json:"consecutiveReloadFailures"`
+	LastSuccessfulReload      *time.Time              `json:"lastSuccessfulReload,omitempty"`
+	RecentReloadFailures      []configs.ReloadFailure `json:"recentReloadFailures"`
+	PendingResources          []ResourceSyncStatus    `json:"pendingResources"`
+	FailingResources          []ResourceSyncStatus    `json:"failingResources"`
+}
+
+// ResourceSyncStatus describes a resource whose changes are not applied yet or failed to be applied.
+type ResourceSyncStatus struct {
+	Resource string    `json:"resource"`
+	Since    time.Time `json:"since"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// pendingChange records when a task was first enqueued and the sequence number of its last enqueue.
+type pendingChange struct {
+	since time.Time
+	seq   uint64
+}
+
+type failingResource struct {
+	since time.Time
+	err   string
+}
+
+// syncTracker keeps track of the changes that are not applied yet and of the resources whose changes failed to be applied.
+// The task queue updates it when a task is enqueued, the controller when a task is synced and applied,
+// while the readiness endpoint reads it.
+type syncTracker struct {
+	lock    sync.RWMutex
+	seq     uint64
+	pending map[string]pendingChange
+	// synced holds the sequence numbers of the pending changes that were synced but are not applied yet.
+	synced  map[string]uint64
+	failing map[string]failingResource
+}
+
+func newSyncTracker() *syncTracker {
+	return &syncTracker{
+		pending: make(map[string]pendingChange),
+		synced:  make(map[string]uint64),
+		failing: make(map[string]failingResource),
+	}
+}
+
+func pendingKey(t task) string {
+	return fmt.Sprintf("%s/%s", t.Kind, t.Key)
+}
+
+// addPending records that the change of the given task is not applied yet. It is called when the task is enqueued
+// or requeued.
+func (st *syncTracker) addPending(t task, now time.Time) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	key := pendingKey(t)
+	st.seq++
+	p, exists := st.pending[key]
+	if !exists {
+		p.since = now
+	}
+	p.seq = st.seq
+	st.pending[key] = p
+}
+
+// markSynced records that the sync of the given task started.
+func (st *syncTracker) markSynced(t task) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	key := pendingKey(t)
+	if p, exists := st.pending[key]; exists {
+		st.synced[key] = p.seq
+	}
+}
+
+// clearSynced records that the changes of the synced tasks were applied. A task that was enqueued or requeued
+// after its sync started stays pending.
+func (st *syncTracker) clearSynced() {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	for key, seq := range st.synced {
+		if p, exists := st.pending[key]; exists && p.seq == seq {
+			delete(st.pending, key)
+		}
+	}
+	st.synced = make(map[string]uint64)
+}
+
+// recordResults records the outcome of applying the changes of the given resources.
+func (st *syncTracker) recordResults(resources []Resource, operationErr error, now time.Time) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	for _, r := range resources {
+		key := r.GetKeyWithKind()
+		if operationErr == nil {
+			delete(st.failing, key)
+			continue
+		}
+
+		since := now
+		if f, exists := st.failing[key]; exists {
+			since = f.since
+		}
+		st.failing[key] = failingResource{since: since, err: operationErr.Error()}
+	}
+}
+
+// removeStaleFailures forgets the failing resources that no longer exist.
+func (st *syncTracker) removeStaleFailures(resources []Resource) {
+	existing := make(map[string]bool)
+	for _, r := range resources {
+		existing[r.GetKeyWithKind()] = true
+	}
+
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	for key := range st.failing {
+		if !existing[key] {
+			delete(st.failing, key)
+		}
+	}
+}
+
+// getStatuses returns the pending and the failing resources sorted by name along with the time of the oldest change
+// that is not applied. The time is zero if all changes are applied.
+func (st *syncTracker) getStatuses() (pending []ResourceSyncStatus, failing []ResourceSyncStatus, oldest time.Time) {
+	st.lock.RLock()
+	defer st.lock.RUnlock()
+
+	pending = make([]ResourceSyncStatus, 0, len(st.pending))
+	for key, p := range st.pending {
+		pending = append(pending, ResourceSyncStatus{Resource: key, Since: p.since})
+		if oldest.IsZero() || p.since.Before(oldest) {
+			oldest = p.since
+		}
+	}
+
+	failing = make([]ResourceSyncStatus, 0, len(st.failing))
+	for key, f := range st.failing {
+		failing = append(failing, ResourceSyncStatus{Resource: key, Since: f.since, Error: f.err})
+		if oldest.IsZero() || f.since.Before(oldest) {
+			oldest = f.since
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Resource < pending[j].Resource })
+	sort.Slice(failing, func(i, j int) bool { return failing[i].Resource < failing[j].Resource })
+
+	return pending, failing, oldest
+}
+
+// newReadinessStatus computes the readiness of the Ingress Controller.
+// A failedReloadsThreshold or a stalenessThreshold of 0 disables the corresponding check.
+func newReadinessStatus(nginxReady bool, reloadStatus configs.ReloadStatus, st *syncTracker,
+	failedReloadsThreshold int, stalenessThreshold time.Duration, now time.Time,
+) ReadinessStatus {
+	pending, failing, oldest := st.getStatuses()
+
+	status := ReadinessStatus{
+		Ready:                     nginxReady,
+		NginxReady:                nginxReady,
+		ConsecutiveReloadFailures: reloadStatus.ConsecutiveFailures,
+		RecentReloadFailures:      reloadStatus.RecentFailures,
+		PendingResources:          pending,
+		FailingResources:          failing,
+	}
+	if status.RecentReloadFailures == nil {
+		status.RecentReloadFailures = []configs.ReloadFailure{}
+	}
+	if !reloadStatus.LastSuccess.IsZero() {
+		lastSuccess := reloadStatus.LastSuccess
+		status.LastSuccessfulReload = &lastSuccess
+	}
+
+	if !nginxReady {
+		status.Reasons = append(status.Reasons, "NGINX has not loaded the initial configuration yet")
+		return status
+	}
+
+	if failedReloadsThreshold > 0 && reloadStatus.ConsecutiveFailures >= failedReloadsThreshold {
+		status.Ready = false
+		status.Reasons = append(status.Reasons, fmt.Sprintf("the last %d reload(s) of NGINX failed", reloadStatus.ConsecutiveFailures))
+	}
+
+	if stalenessThreshold > 0 && !oldest.IsZero() && now.Sub(oldest) > stalenessThreshold {
+		status.Ready = false
+		status.Reasons = append(status.Reasons, fmt.Sprintf("changes have not been applied for more than %v", stalenessThreshold))
+	}
+
+	return status
+}
+
+// GetReadinessStatus returns the readiness of the Ingress Controller. Once NGINX has loaded the initial configuration,
+// the Ingress Controller is not ready if the last reloads failed or if changes have not been applied for too long,
+// when the corresponding thresholds are configured.
+func (lbc *LoadBalancerController) GetReadinessStatus() ReadinessStatus {
+	return newReadinessStatus(lbc.isNginxReady, lbc.configurator.GetReloadStatus(), lbc.syncTracker,
+		lbc.readyFailedReloadsThreshold, lbc.readyStalenessThreshold, time.Now())
+}
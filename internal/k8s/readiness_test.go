@@ -0,0 +1,178 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncTracker(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	vs := &VirtualServerConfiguration{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+		},
+	}
+
+	st := newSyncTracker()
+	st.addPending(task{Kind: virtualserver, Key: "default/cafe"}, start)
+	st.addPending(task{Kind: virtualserver, Key: "default/cafe"}, start.Add(time.Minute))
+	st.recordResults([]Resource{vs}, errors.New("reload failed"), start.Add(time.Minute))
+	st.recordResults([]Resource{vs}, errors.New("reload failed again"), start.Add(2*time.Minute))
+
+	pending, failing, oldest := st.getStatuses()
+	expectedPending := []ResourceSyncStatus{{Resource: "VirtualServer/default/cafe", Since: start}}
+	expectedFailing := []ResourceSyncStatus{{Resource: "VirtualServer/default/cafe", Since: start.Add(time.Minute), Error: "reload failed again"}}
+	if diff := cmp.Diff(expectedPending, pending); diff != "" {
+		t.Errorf("getStatuses() returned unexpected pending resources (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedFailing, failing); diff != "" {
+		t.Errorf("getStatuses() returned unexpected failing resources (-want +got):\n%s", diff)
+	}
+	if !oldest.Equal(start) {
+		t.Errorf("getStatuses() returned oldest change %v, expected %v", oldest, start)
+	}
+
+	st.markSynced(task{Kind: virtualserver, Key: "default/cafe"})
+	st.clearSynced()
+	st.recordResults([]Resource{vs}, nil, start.Add(3*time.Minute))
+
+	pending, failing, oldest = st.getStatuses()
+	if len(pending) != 0 || len(failing) != 0 || !oldest.IsZero() {
+		t.Errorf("getStatuses() returned pending %v, failing %v and oldest change %v, expected none", pending, failing, oldest)
+	}
+
+	st.recordResults([]Resource{vs}, errors.New("reload failed"), start)
+	st.removeStaleFailures(nil)
+
+	_, failing, _ = st.getStatuses()
+	if len(failing) != 0 {
+		t.Errorf("getStatuses() returned failing resources %v after the resource was removed", failing)
+	}
+}
+
+func TestSyncTrackerKeepsChangesEnqueuedDuringSync(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	cafe := task{Kind: virtualserver, Key: "default/cafe"}
+	tea := task{Kind: virtualserver, Key: "default/tea"}
+
+	st := newSyncTracker()
+	st.addPending(cafe, start)
+	st.addPending(tea, start.Add(time.Minute))
+
+	// the sync of cafe is requeued, while the sync of tea is not applied yet
+	st.markSynced(cafe)
+	st.addPending(cafe, start.Add(2*time.Minute))
+	st.markSynced(tea)
+
+	pending, _, _ := st.getStatuses()
+	expected := []ResourceSyncStatus{
+		{Resource: "VirtualServer/default/cafe", Since: start},
+		{Resource: "VirtualServer/default/tea", Since: start.Add(time.Minute)},
+	}
+	if diff := cmp.Diff(expected, pending); diff != "" {
+		t.Errorf("getStatuses() returned unexpected pending resources before the changes were applied (-want +got):\n%s", diff)
+	}
+
+	st.clearSynced()
+
+	pending, _, _ = st.getStatuses()
+	expected = []ResourceSyncStatus{
+		{Resource: "VirtualServer/default/cafe", Since: start},
+	}
+	if diff := cmp.Diff(expected, pending); diff != "" {
+		t.Errorf("getStatuses() returned unexpected pending resources after the changes were applied (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewReadinessStatus(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, time.January, 1, 1, 0, 0, 0, time.UTC)
+
+	stale := newSyncTracker()
+	stale.addPending(task{Kind: ingress, Key: "default/cafe"}, now.Add(-10*time.Minute))
+
+	fresh := newSyncTracker()
+	fresh.addPending(task{Kind: ingress, Key: "default/cafe"}, now.Add(-time.Minute))
+
+	failedReloads := configs.ReloadStatus{
+		ConsecutiveFailures: 3,
+		RecentFailures: []configs.ReloadFailure{
+			{Time: now, Error: "reload failed"},
+		},
+	}
+
+	tests := []struct {
+		nginxReady             bool
+		reloadStatus           configs.ReloadStatus
+		tracker                *syncTracker
+		failedReloadsThreshold int
+		stalenessThreshold     time.Duration
+		expected               bool
+		msg                    string
+	}{
+		{
+			nginxReady: false,
+			tracker:    newSyncTracker(),
+			expected:   false,
+			msg:        "nginx not ready",
+		},
+		{
+			nginxReady:   true,
+			reloadStatus: failedReloads,
+			tracker:      stale,
+			expected:     true,
+			msg:          "thresholds disabled",
+		},
+		{
+			nginxReady:             true,
+			reloadStatus:           failedReloads,
+			tracker:                newSyncTracker(),
+			failedReloadsThreshold: 3,
+			expected:               false,
+			msg:                    "failed reloads threshold reached",
+		},
+		{
+			nginxReady:             true,
+			reloadStatus:           failedReloads,
+			tracker:                newSyncTracker(),
+			failedReloadsThreshold: 4,
+			expected:               true,
+			msg:                    "failed reloads threshold not reached",
+		},
+		{
+			nginxReady:         true,
+			tracker:            stale,
+			stalenessThreshold: 5 * time.Minute,
+			expected:           false,
+			msg:                "staleness threshold passed",
+		},
+		{
+			nginxReady:         true,
+			tracker:            fresh,
+			stalenessThreshold: 5 * time.Minute,
+			expected:           true,
+			msg:                "staleness threshold not passed",
+		},
+	}
+
+	for _, test := range tests {
+		status := newReadinessStatus(test.nginxReady, test.reloadStatus, test.tracker, test.failedReloadsThreshold, test.stalenessThreshold, now)
+		if status.Ready != test.expected {
+			t.Errorf("newReadinessStatus() returned ready %v but expected %v for the case of %s", status.Ready, test.expected, test.msg)
+		}
+		if !status.Ready && len(status.Reasons) == 0 {
+			t.Errorf("newReadinessStatus() returned no reasons for not ready for the case of %s", test.msg)
+		}
+	}
+}
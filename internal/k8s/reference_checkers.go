@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/nginx/kubernetes-ingress/internal/configs"
@@ -83,10 +84,25 @@ func (rc *secretReferenceChecker) IsReferencedByVirtualServer(secretNamespace st
 		return true
 	}
 
-	return false
+	return isSecretReferencedByRoutes(secretName, vs.Spec.Routes)
+}
+
+func (rc *secretReferenceChecker) IsReferencedByVirtualServerRoute(secretNamespace string, secretName string, vsr *conf_v1.VirtualServerRoute) bool {
+	if vsr.Namespace != secretNamespace {
+		return false
+	}
+
+	return isSecretReferencedByRoutes(secretName, vsr.Spec.Subroutes)
 }
 
-func (rc *secretReferenceChecker) IsReferencedByVirtualServerRoute(_ string, _ string, _ *conf_v1.VirtualServerRoute) bool {
+// isSecretReferencedByRoutes checks if the objectStorage action of a route references the secret.
+func isSecretReferencedByRoutes(secretName string, routes []conf_v1.Route) bool {
+	for _, r := range routes {
+		if r.Action != nil && r.Action.ObjectStorage != nil && r.Action.ObjectStorage.Secret == secretName {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -139,14 +155,13 @@ func (rc *serviceReferenceChecker) IsReferencedByMinion(svcNamespace string, svc
 }
 
 func (rc *serviceReferenceChecker) IsReferencedByVirtualServer(svcNamespace string, svcName string, vs *conf_v1.VirtualServer) bool {
-	if vs.Namespace != svcNamespace {
-		return false
-	}
-
 	for _, u := range vs.Spec.Upstreams {
 		if rc.hasClusterIP && u.UseClusterIP {
 			continue
 		}
+		if configs.GetUpstreamNamespace(u, vs.Namespace) != svcNamespace {
+			continue
+		}
 		if u.Service == svcName || u.Backup == svcName {
 			return true
 		}
@@ -156,14 +171,13 @@ func (rc *serviceReferenceChecker) IsReferencedByVirtualServer(svcNamespace stri
 }
 
 func (rc *serviceReferenceChecker) IsReferencedByVirtualServerRoute(svcNamespace string, svcName string, vsr *conf_v1.VirtualServerRoute) bool {
-	if vsr.Namespace != svcNamespace {
-		return false
-	}
-
 	for _, u := range vsr.Spec.Upstreams {
 		if rc.hasClusterIP && u.UseClusterIP {
 			continue
 		}
+		if configs.GetUpstreamNamespace(u, vsr.Namespace) != svcNamespace {
+			continue
+		}
 		if u.Service == svcName {
 			return true
 		}
@@ -192,12 +206,12 @@ func newPolicyReferenceChecker() *policyReferenceChecker {
 	return &policyReferenceChecker{}
 }
 
-func (rc *policyReferenceChecker) IsReferencedByIngress(_ string, _ string, _ *networking.Ingress) bool {
-	return false
+func (rc *policyReferenceChecker) IsReferencedByIngress(policyNamespace string, policyName string, ing *networking.Ingress) bool {
+	return isPolicyReferencedByIngress(ing, policyNamespace, policyName)
 }
 
-func (rc *policyReferenceChecker) IsReferencedByMinion(_ string, _ string, _ *networking.Ingress) bool {
-	return false
+func (rc *policyReferenceChecker) IsReferencedByMinion(policyNamespace string, policyName string, ing *networking.Ingress) bool {
+	return isPolicyReferencedByIngress(ing, policyNamespace, policyName)
 }
 
 func (rc *policyReferenceChecker) IsReferencedByVirtualServer(policyNamespace string, policyName string, vs *conf_v1.VirtualServer) bool {
@@ -281,6 +295,20 @@ func isPolicyReferenced(policies []conf_v1.PolicyReference, resourceNamespace st
 	return false
 }
 
+func isPolicyReferencedByIngress(ing *networking.Ingress, policyNamespace string, policyName string) bool {
+	value, exists := ing.Annotations[configs.PoliciesAnnotation]
+	if !exists {
+		return false
+	}
+
+	policies, err := configs.ParsePolicyReferences(value)
+	if err != nil {
+		return false
+	}
+
+	return isPolicyReferenced(policies, ing.Namespace, policyNamespace, policyName)
+}
+
 type dosResourceReferenceChecker struct {
 	annotation string
 }
@@ -353,3 +381,220 @@ func (rc *ratelimitScalingAnnotationChecker) IsReferencedByVirtualServerRoute(_
 func (rc *ratelimitScalingAnnotationChecker) IsReferencedByTransportServer(_ string, _ string, _ *conf_v1.TransportServer) bool {
 	return false
 }
+
+// referenceGrantChecker checks if references to Policies, Secrets and Services in other namespaces are allowed.
+// A reference from another namespace is allowed if a ReferenceGrant in the namespace of the referenced resource allows it.
+// When ReferenceGrants are disabled, only references to Policies in other namespaces are allowed.
+type referenceGrantChecker struct {
+	enabled   bool
+	getGrants func(namespace string) []*conf_v1.ReferenceGrant
+}
+
+func newReferenceGrantChecker(enabled bool, getGrants func(namespace string) []*conf_v1.ReferenceGrant) *referenceGrantChecker {
+	return &referenceGrantChecker{
+		enabled:   enabled,
+		getGrants: getGrants,
+	}
+}
+
+// checkReference returns an error if resources of fromKind in fromNamespace are not allowed to reference the resource.
+func (rc *referenceGrantChecker) checkReference(fromKind string, fromNamespace string, toKind string, toNamespace string, toName string) error {
+	if fromNamespace == toNamespace {
+		return nil
+	}
+
+	if !rc.enabled {
+		if toKind == conf_v1.ReferenceGrantKindPolicy {
+			return nil
+		}
+		return fmt.Errorf("references from %s in namespace %s to %s %s/%s require ReferenceGrants to be enabled", fromKind, fromNamespace, toKind, toNamespace, toName)
+	}
+
+	if isReferenceAllowed(rc.getGrants(toNamespace), fromKind, fromNamespace, toKind, toName) {
+		return nil
+	}
+
+	return fmt.Errorf("references from %s in namespace %s to %s %s/%s are not allowed by any ReferenceGrant in namespace %s", fromKind, fromNamespace, toKind, toNamespace, toName, toNamespace)
+}
+
+// removeDeniedPolicies returns the policies that resources of ownerKind in ownerNamespace are allowed to reference.
+// A policy is denied if the reference to it or to any of its secrets is not allowed.
+// The errors of the denied policies are added to deniedReferences.
+func (rc *referenceGrantChecker) removeDeniedPolicies(deniedReferences map[string]error, ownerKind string, ownerNamespace string, policies []*conf_v1.Policy) []*conf_v1.Policy {
+	var result []*conf_v1.Policy
+
+	for _, pol := range policies {
+		policyKey := fmt.Sprintf("%s/%s", pol.Namespace, pol.Name)
+
+		err := rc.checkReference(ownerKind, ownerNamespace, conf_v1.ReferenceGrantKindPolicy, pol.Namespace, pol.Name)
+		if err == nil {
+			err = rc.checkPolicySecretReferences(pol)
+		}
+
+		if err != nil {
+			deniedReferences[configs.GenerateReferenceKey(ownerKind, ownerNamespace, conf_v1.ReferenceGrantKindPolicy, policyKey)] = err
+			continue
+		}
+
+		result = append(result, pol)
+	}
+
+	return result
+}
+
+// checkPolicySecretReferences returns an error if the policy is not allowed to reference any of its secrets.
+func (rc *referenceGrantChecker) checkPolicySecretReferences(pol *conf_v1.Policy) error {
+	for _, secretKey := range getPolicySecretKeys(pol) {
+		secretNamespace, secretName, err := ParseNamespaceName(secretKey)
+		if err != nil {
+			return err
+		}
+
+		err = rc.checkReference(conf_v1.ReferenceGrantKindPolicy, pol.Namespace, conf_v1.ReferenceGrantKindSecret, secretNamespace, secretName)
+		if err != nil {
+			return fmt.Errorf("invalid reference to secret %s: %w", secretKey, err)
+		}
+	}
+
+	return nil
+}
+
+// addDeniedServiceReferences adds the errors of the services of the upstream that resources of ownerKind in ownerNamespace
+// are not allowed to reference to deniedReferences. It returns false if any of the references is denied.
+func (rc *referenceGrantChecker) addDeniedServiceReferences(deniedReferences map[string]error, ownerKind string, ownerNamespace string, u conf_v1.Upstream) bool {
+	upstreamNamespace := configs.GetUpstreamNamespace(u, ownerNamespace)
+
+	services := []string{u.Service}
+	if u.Backup != "" {
+		services = append(services, u.Backup)
+	}
+
+	allowed := true
+	for _, svc := range services {
+		err := rc.checkReference(ownerKind, ownerNamespace, conf_v1.ReferenceGrantKindService, upstreamNamespace, svc)
+		if err != nil {
+			svcKey := configs.GenerateExternalNameSvcKey(upstreamNamespace, svc)
+			deniedReferences[configs.GenerateReferenceKey(ownerKind, ownerNamespace, conf_v1.ReferenceGrantKindService, svcKey)] = err
+			allowed = false
+		}
+	}
+
+	return allowed
+}
+
+// isReferenceAllowed checks if any of the grants allows resources of fromKind in fromNamespace to reference the resource.
+func isReferenceAllowed(grants []*conf_v1.ReferenceGrant, fromKind string, fromNamespace string, toKind string, toName string) bool {
+	for _, g := range grants {
+		if !isReferenceGrantFromMatched(g.Spec.From, fromKind, fromNamespace) {
+			continue
+		}
+
+		for _, to := range g.Spec.To {
+			if to.Kind == toKind && (to.Name == "" || to.Name == toName) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isReferenceGrantFromMatched(from []conf_v1.ReferenceGrantFrom, kind string, namespace string) bool {
+	for _, f := range from {
+		if f.Kind == kind && f.Namespace == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// crossNamespaceReferenceChecker is a reference checker for ReferenceGrants.
+// A resource is referenced if it references Policies or Services in the namespace from another namespace.
+type crossNamespaceReferenceChecker struct{}
+
+func newCrossNamespaceReferenceChecker() *crossNamespaceReferenceChecker {
+	return &crossNamespaceReferenceChecker{}
+}
+
+func (rc *crossNamespaceReferenceChecker) IsReferencedByIngress(namespace string, _ string, ing *networking.Ingress) bool {
+	if ing.Namespace == namespace {
+		return false
+	}
+
+	value, exists := ing.Annotations[configs.PoliciesAnnotation]
+	if !exists {
+		return false
+	}
+
+	policies, err := configs.ParsePolicyReferences(value)
+	if err != nil {
+		return false
+	}
+
+	return hasPolicyInNamespace(policies, namespace)
+}
+
+func (rc *crossNamespaceReferenceChecker) IsReferencedByMinion(namespace string, name string, ing *networking.Ingress) bool {
+	return rc.IsReferencedByIngress(namespace, name, ing)
+}
+
+func (rc *crossNamespaceReferenceChecker) IsReferencedByVirtualServer(namespace string, _ string, vs *conf_v1.VirtualServer) bool {
+	if vs.Namespace == namespace {
+		return false
+	}
+
+	if hasPolicyInNamespace(vs.Spec.Policies, namespace) || hasUpstreamInNamespace(vs.Spec.Upstreams, namespace) {
+		return true
+	}
+
+	for _, r := range vs.Spec.Routes {
+		if hasPolicyInNamespace(r.Policies, namespace) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rc *crossNamespaceReferenceChecker) IsReferencedByVirtualServerRoute(namespace string, _ string, vsr *conf_v1.VirtualServerRoute) bool {
+	if vsr.Namespace == namespace {
+		return false
+	}
+
+	if hasUpstreamInNamespace(vsr.Spec.Upstreams, namespace) {
+		return true
+	}
+
+	for _, sr := range vsr.Spec.Subroutes {
+		if hasPolicyInNamespace(sr.Policies, namespace) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rc *crossNamespaceReferenceChecker) IsReferencedByTransportServer(_ string, _ string, _ *conf_v1.TransportServer) bool {
+	return false
+}
+
+func hasPolicyInNamespace(policies []conf_v1.PolicyReference, namespace string) bool {
+	for _, p := range policies {
+		if p.Namespace == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasUpstreamInNamespace(upstreams []conf_v1.Upstream, namespace string) bool {
+	for _, u := range upstreams {
+		if u.ServiceNamespace == namespace {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,8 +1,10 @@
 package k8s
 
 import (
+	"sort"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/nginx/kubernetes-ingress/internal/configs"
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	networking "k8s.io/api/networking/v1"
@@ -287,6 +289,29 @@ func TestSecretIsReferencedByVirtualServer(t *testing.T) {
 			expected:        false,
 			msg:             "wrong namespace for tls secret",
 		},
+		{
+			vs: &conf_v1.VirtualServer{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Routes: []conf_v1.Route{
+						{
+							Path: "/assets",
+							Action: &conf_v1.Action{
+								ObjectStorage: &conf_v1.ActionObjectStorage{
+									Secret: "test-secret",
+								},
+							},
+						},
+					},
+				},
+			},
+			secretNamespace: "default",
+			secretName:      "test-secret",
+			expected:        true,
+			msg:             "object storage secret is referenced",
+		},
 	}
 
 	for _, test := range tests {
@@ -302,12 +327,90 @@ func TestSecretIsReferencedByVirtualServer(t *testing.T) {
 
 func TestSecretIsReferencedByVirtualServerRoute(t *testing.T) {
 	t.Parallel()
-	isPlus := false // doesn't matter for VirtualServerRoute
-	rc := newSecretReferenceChecker(isPlus)
+	tests := []struct {
+		vsr             *conf_v1.VirtualServerRoute
+		secretNamespace string
+		secretName      string
+		expected        bool
+		msg             string
+	}{
+		{
+			vsr: &conf_v1.VirtualServerRoute{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerRouteSpec{
+					Subroutes: []conf_v1.Route{
+						{
+							Path: "/assets",
+							Action: &conf_v1.Action{
+								ObjectStorage: &conf_v1.ActionObjectStorage{
+									Secret: "test-secret",
+								},
+							},
+						},
+					},
+				},
+			},
+			secretNamespace: "default",
+			secretName:      "test-secret",
+			expected:        true,
+			msg:             "object storage secret is referenced",
+		},
+		{
+			vsr: &conf_v1.VirtualServerRoute{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerRouteSpec{
+					Subroutes: []conf_v1.Route{
+						{
+							Path: "/assets",
+							Action: &conf_v1.Action{
+								ObjectStorage: &conf_v1.ActionObjectStorage{
+									Secret: "test-secret",
+								},
+							},
+						},
+					},
+				},
+			},
+			secretNamespace: "some-namespace",
+			secretName:      "test-secret",
+			expected:        false,
+			msg:             "wrong namespace for object storage secret",
+		},
+		{
+			vsr: &conf_v1.VirtualServerRoute{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerRouteSpec{
+					Subroutes: []conf_v1.Route{
+						{
+							Path: "/tea",
+							Action: &conf_v1.Action{
+								Pass: "tea",
+							},
+						},
+					},
+				},
+			},
+			secretNamespace: "default",
+			secretName:      "test-secret",
+			expected:        false,
+			msg:             "no object storage action",
+		},
+	}
 
-	result := rc.IsReferencedByVirtualServerRoute("", "", nil)
-	if result != false {
-		t.Error("IsReferencedByVirtualServer() returned true but expected false")
+	for _, test := range tests {
+		isPlus := false // doesn't matter for VirtualServerRoute
+		rc := newSecretReferenceChecker(isPlus)
+
+		result := rc.IsReferencedByVirtualServerRoute(test.secretNamespace, test.secretName, test.vsr)
+		if result != test.expected {
+			t.Errorf("IsReferencedByVirtualServerRoute() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
 	}
 }
 
@@ -704,6 +807,67 @@ func TestServiceIsReferencedByVirtualServerAndVirtualServerRoutes(t *testing.T)
 	}
 }
 
+func TestServiceInAnotherNamespaceIsReferencedByVirtualServerAndVirtualServerRoutes(t *testing.T) {
+	t.Parallel()
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Upstreams: []conf_v1.Upstream{
+				{
+					Service:          "test-service",
+					ServiceNamespace: "backend",
+				},
+			},
+		},
+	}
+	vsr := &conf_v1.VirtualServerRoute{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerRouteSpec{
+			Upstreams: []conf_v1.Upstream{
+				{
+					Service:          "test-service",
+					ServiceNamespace: "backend",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		serviceNamespace string
+		expected         bool
+		msg              string
+	}{
+		{
+			serviceNamespace: "backend",
+			expected:         true,
+			msg:              "service is referenced in an upstream in another namespace",
+		},
+		{
+			serviceNamespace: "default",
+			expected:         false,
+			msg:              "service in the namespace of the resource",
+		},
+	}
+
+	for _, test := range tests {
+		rc := newServiceReferenceChecker(false)
+
+		result := rc.IsReferencedByVirtualServer(test.serviceNamespace, "test-service", vs)
+		if result != test.expected {
+			t.Errorf("IsReferencedByVirtualServer() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+
+		result = rc.IsReferencedByVirtualServerRoute(test.serviceNamespace, "test-service", vsr)
+		if result != test.expected {
+			t.Errorf("IsReferencedByVirtualServerRoute() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
 func TestServiceIsReferencedByTransportServer(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -854,21 +1018,104 @@ func TestBackupServiceIsReferencedByTransportServer(t *testing.T) {
 	}
 }
 
-func TestPolicyIsReferencedByIngressesAndTransportServers(t *testing.T) {
+func TestPolicyIsReferencedByIngressesAndMinions(t *testing.T) {
 	t.Parallel()
+	tests := []struct {
+		ing             *networking.Ingress
+		policyNamespace string
+		policyName      string
+		expected        bool
+		msg             string
+	}{
+		{
+			ing: &networking.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+					Annotations: map[string]string{
+						"nginx.org/policies": "test-policy",
+					},
+				},
+			},
+			policyNamespace: "default",
+			policyName:      "test-policy",
+			expected:        true,
+			msg:             "policy is referenced with implicit namespace",
+		},
+		{
+			ing: &networking.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+					Annotations: map[string]string{
+						"nginx.org/policies": "other-policy, policies/test-policy",
+					},
+				},
+			},
+			policyNamespace: "policies",
+			policyName:      "test-policy",
+			expected:        true,
+			msg:             "policy is referenced with explicit namespace",
+		},
+		{
+			ing: &networking.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+					Annotations: map[string]string{
+						"nginx.org/policies": "test-policy",
+					},
+				},
+			},
+			policyNamespace: "policies",
+			policyName:      "test-policy",
+			expected:        false,
+			msg:             "wrong namespace",
+		},
+		{
+			ing: &networking.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+					Annotations: map[string]string{
+						"nginx.org/policies": "policies/test-policy/invalid",
+					},
+				},
+			},
+			policyNamespace: "policies",
+			policyName:      "test-policy",
+			expected:        false,
+			msg:             "invalid annotation",
+		},
+		{
+			ing: &networking.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+				},
+			},
+			policyNamespace: "default",
+			policyName:      "test-policy",
+			expected:        false,
+			msg:             "no annotation",
+		},
+	}
+
 	rc := newPolicyReferenceChecker()
 
-	result := rc.IsReferencedByIngress("", "", nil)
-	if result {
-		t.Error("IsReferencedByIngress() returned true but expected false")
-	}
+	for _, test := range tests {
+		result := rc.IsReferencedByIngress(test.policyNamespace, test.policyName, test.ing)
+		if result != test.expected {
+			t.Errorf("IsReferencedByIngress() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
 
-	result = rc.IsReferencedByMinion("", "", nil)
-	if result {
-		t.Error("IsReferencedByMinion() returned true but expected false")
+		result = rc.IsReferencedByMinion(test.policyNamespace, test.policyName, test.ing)
+		if result != test.expected {
+			t.Errorf("IsReferencedByMinion() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
 	}
+}
 
-	result = rc.IsReferencedByTransportServer("", "", nil)
+func TestPolicyIsReferencedByTransportServers(t *testing.T) {
+	t.Parallel()
+	rc := newPolicyReferenceChecker()
+
+	result := rc.IsReferencedByTransportServer("", "", nil)
 	if result {
 		t.Error("IsReferencedByTransportServer() returned true but expected false")
 	}
@@ -1684,3 +1931,400 @@ func TestReplicaReferenceChecker(t *testing.T) {
 		}
 	}
 }
+
+func TestReferenceGrantCheckerCheckReference(t *testing.T) {
+	t.Parallel()
+	grants := []*conf_v1.ReferenceGrant{
+		{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "allow-frontend",
+				Namespace: "backend",
+			},
+			Spec: conf_v1.ReferenceGrantSpec{
+				From: []conf_v1.ReferenceGrantFrom{
+					{
+						Kind:      conf_v1.ReferenceGrantKindVirtualServer,
+						Namespace: "frontend",
+					},
+					{
+						Kind:      conf_v1.ReferenceGrantKindPolicy,
+						Namespace: "frontend",
+					},
+				},
+				To: []conf_v1.ReferenceGrantTo{
+					{
+						Kind: conf_v1.ReferenceGrantKindService,
+					},
+					{
+						Kind: conf_v1.ReferenceGrantKindSecret,
+						Name: "jwk-secret",
+					},
+				},
+			},
+		},
+	}
+	getGrants := func(namespace string) []*conf_v1.ReferenceGrant {
+		if namespace == "backend" {
+			return grants
+		}
+		return nil
+	}
+
+	tests := []struct {
+		enabled       bool
+		fromKind      string
+		fromNamespace string
+		toKind        string
+		toNamespace   string
+		toName        string
+		expectErr     bool
+		msg           string
+	}{
+		{
+			enabled:       false,
+			fromKind:      conf_v1.ReferenceGrantKindVirtualServer,
+			fromNamespace: "backend",
+			toKind:        conf_v1.ReferenceGrantKindService,
+			toNamespace:   "backend",
+			toName:        "tea-svc",
+			expectErr:     false,
+			msg:           "same namespace",
+		},
+		{
+			enabled:       false,
+			fromKind:      conf_v1.ReferenceGrantKindVirtualServer,
+			fromNamespace: "frontend",
+			toKind:        conf_v1.ReferenceGrantKindPolicy,
+			toNamespace:   "backend",
+			toName:        "rate-limit",
+			expectErr:     false,
+			msg:           "policy in another namespace with disabled ReferenceGrants",
+		},
+		{
+			enabled:       false,
+			fromKind:      conf_v1.ReferenceGrantKindVirtualServer,
+			fromNamespace: "frontend",
+			toKind:        conf_v1.ReferenceGrantKindService,
+			toNamespace:   "backend",
+			toName:        "tea-svc",
+			expectErr:     true,
+			msg:           "service in another namespace with disabled ReferenceGrants",
+		},
+		{
+			enabled:       true,
+			fromKind:      conf_v1.ReferenceGrantKindVirtualServer,
+			fromNamespace: "frontend",
+			toKind:        conf_v1.ReferenceGrantKindService,
+			toNamespace:   "backend",
+			toName:        "tea-svc",
+			expectErr:     false,
+			msg:           "service allowed by a grant for all services",
+		},
+		{
+			enabled:       true,
+			fromKind:      conf_v1.ReferenceGrantKindVirtualServerRoute,
+			fromNamespace: "frontend",
+			toKind:        conf_v1.ReferenceGrantKindService,
+			toNamespace:   "backend",
+			toName:        "tea-svc",
+			expectErr:     true,
+			msg:           "service not allowed for the kind",
+		},
+		{
+			enabled:       true,
+			fromKind:      conf_v1.ReferenceGrantKindVirtualServer,
+			fromNamespace: "other",
+			toKind:        conf_v1.ReferenceGrantKindService,
+			toNamespace:   "backend",
+			toName:        "tea-svc",
+			expectErr:     true,
+			msg:           "service not allowed for the namespace",
+		},
+		{
+			enabled:       true,
+			fromKind:      conf_v1.ReferenceGrantKindPolicy,
+			fromNamespace: "frontend",
+			toKind:        conf_v1.ReferenceGrantKindSecret,
+			toNamespace:   "backend",
+			toName:        "jwk-secret",
+			expectErr:     false,
+			msg:           "secret allowed by name",
+		},
+		{
+			enabled:       true,
+			fromKind:      conf_v1.ReferenceGrantKindPolicy,
+			fromNamespace: "frontend",
+			toKind:        conf_v1.ReferenceGrantKindSecret,
+			toNamespace:   "backend",
+			toName:        "other-secret",
+			expectErr:     true,
+			msg:           "secret not allowed by name",
+		},
+		{
+			enabled:       true,
+			fromKind:      conf_v1.ReferenceGrantKindVirtualServer,
+			fromNamespace: "frontend",
+			toKind:        conf_v1.ReferenceGrantKindPolicy,
+			toNamespace:   "backend",
+			toName:        "rate-limit",
+			expectErr:     true,
+			msg:           "policy not allowed by any grant",
+		},
+		{
+			enabled:       true,
+			fromKind:      conf_v1.ReferenceGrantKindVirtualServer,
+			fromNamespace: "backend",
+			toKind:        conf_v1.ReferenceGrantKindService,
+			toNamespace:   "frontend",
+			toName:        "tea-svc",
+			expectErr:     true,
+			msg:           "no grants in the namespace of the service",
+		},
+	}
+
+	for _, test := range tests {
+		rc := newReferenceGrantChecker(test.enabled, getGrants)
+
+		err := rc.checkReference(test.fromKind, test.fromNamespace, test.toKind, test.toNamespace, test.toName)
+		if (err != nil) != test.expectErr {
+			t.Errorf("checkReference() returned error %v but expected error %v for the case of %s", err, test.expectErr, test.msg)
+		}
+	}
+}
+
+func TestReferenceGrantCheckerRemoveDeniedPolicies(t *testing.T) {
+	t.Parallel()
+	grants := []*conf_v1.ReferenceGrant{
+		{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "allow-frontend",
+				Namespace: "backend",
+			},
+			Spec: conf_v1.ReferenceGrantSpec{
+				From: []conf_v1.ReferenceGrantFrom{
+					{
+						Kind:      conf_v1.ReferenceGrantKindVirtualServer,
+						Namespace: "frontend",
+					},
+				},
+				To: []conf_v1.ReferenceGrantTo{
+					{
+						Kind: conf_v1.ReferenceGrantKindPolicy,
+						Name: "rate-limit",
+					},
+				},
+			},
+		},
+	}
+	getGrants := func(namespace string) []*conf_v1.ReferenceGrant {
+		if namespace == "backend" {
+			return grants
+		}
+		return nil
+	}
+
+	rateLimit := &conf_v1.Policy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "rate-limit",
+			Namespace: "backend",
+		},
+		Spec: conf_v1.PolicySpec{
+			RateLimit: &conf_v1.RateLimit{},
+		},
+	}
+	jwt := &conf_v1.Policy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "jwt",
+			Namespace: "backend",
+		},
+		Spec: conf_v1.PolicySpec{
+			JWTAuth: &conf_v1.JWTAuth{
+				Secret: "jwk-secret",
+			},
+		},
+	}
+	jwtOtherNamespace := &conf_v1.Policy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "jwt-other-namespace",
+			Namespace: "frontend",
+		},
+		Spec: conf_v1.PolicySpec{
+			JWTAuth: &conf_v1.JWTAuth{
+				Secret: "backend/jwk-secret",
+			},
+		},
+	}
+	policies := []*conf_v1.Policy{rateLimit, jwt, jwtOtherNamespace}
+
+	tests := []struct {
+		enabled        bool
+		expected       []*conf_v1.Policy
+		expectedDenied []string
+		msg            string
+	}{
+		{
+			enabled:  false,
+			expected: []*conf_v1.Policy{rateLimit, jwt},
+			expectedDenied: []string{
+				configs.GenerateReferenceKey(conf_v1.ReferenceGrantKindVirtualServer, "frontend", conf_v1.ReferenceGrantKindPolicy, "frontend/jwt-other-namespace"),
+			},
+			msg: "policies in other namespaces are allowed with disabled ReferenceGrants",
+		},
+		{
+			enabled:  true,
+			expected: []*conf_v1.Policy{rateLimit},
+			expectedDenied: []string{
+				configs.GenerateReferenceKey(conf_v1.ReferenceGrantKindVirtualServer, "frontend", conf_v1.ReferenceGrantKindPolicy, "backend/jwt"),
+				configs.GenerateReferenceKey(conf_v1.ReferenceGrantKindVirtualServer, "frontend", conf_v1.ReferenceGrantKindPolicy, "frontend/jwt-other-namespace"),
+			},
+			msg: "policies and secrets must be allowed by a grant",
+		},
+	}
+
+	for _, test := range tests {
+		rc := newReferenceGrantChecker(test.enabled, getGrants)
+		deniedReferences := make(map[string]error)
+
+		result := rc.removeDeniedPolicies(deniedReferences, conf_v1.ReferenceGrantKindVirtualServer, "frontend", policies)
+
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("removeDeniedPolicies() returned unexpected result for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		var denied []string
+		for key := range deniedReferences {
+			denied = append(denied, key)
+		}
+		sort.Strings(denied)
+		if diff := cmp.Diff(test.expectedDenied, denied); diff != "" {
+			t.Errorf("removeDeniedPolicies() returned unexpected denied references for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestReferenceGrantCheckerAddDeniedServiceReferences(t *testing.T) {
+	t.Parallel()
+	getGrants := func(_ string) []*conf_v1.ReferenceGrant {
+		return nil
+	}
+	rc := newReferenceGrantChecker(true, getGrants)
+
+	tests := []struct {
+		upstream       conf_v1.Upstream
+		expected       bool
+		expectedDenied int
+		msg            string
+	}{
+		{
+			upstream: conf_v1.Upstream{
+				Service: "tea-svc",
+			},
+			expected: true,
+			msg:      "service in the same namespace",
+		},
+		{
+			upstream: conf_v1.Upstream{
+				Service:          "tea-svc",
+				Backup:           "coffee-svc",
+				ServiceNamespace: "backend",
+			},
+			expected:       false,
+			expectedDenied: 2,
+			msg:            "service and backup service in another namespace",
+		},
+	}
+
+	for _, test := range tests {
+		deniedReferences := make(map[string]error)
+
+		result := rc.addDeniedServiceReferences(deniedReferences, conf_v1.ReferenceGrantKindVirtualServer, "frontend", test.upstream)
+
+		if result != test.expected {
+			t.Errorf("addDeniedServiceReferences() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+		if len(deniedReferences) != test.expectedDenied {
+			t.Errorf("addDeniedServiceReferences() denied %d references but expected %d for the case of %s", len(deniedReferences), test.expectedDenied, test.msg)
+		}
+	}
+}
+
+func TestCrossNamespaceReferenceChecker(t *testing.T) {
+	t.Parallel()
+	ing := &networking.Ingress{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "frontend",
+			Annotations: map[string]string{
+				configs.PoliciesAnnotation: "backend/rate-limit",
+			},
+		},
+	}
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "frontend",
+		},
+		Spec: conf_v1.VirtualServerSpec{
+			Upstreams: []conf_v1.Upstream{
+				{
+					Service:          "tea-svc",
+					ServiceNamespace: "backend",
+				},
+			},
+		},
+	}
+	vsr := &conf_v1.VirtualServerRoute{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "frontend",
+		},
+		Spec: conf_v1.VirtualServerRouteSpec{
+			Subroutes: []conf_v1.Route{
+				{
+					Policies: []conf_v1.PolicyReference{
+						{
+							Name:      "rate-limit",
+							Namespace: "backend",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		namespace string
+		expected  bool
+		msg       string
+	}{
+		{
+			namespace: "backend",
+			expected:  true,
+			msg:       "references to another namespace",
+		},
+		{
+			namespace: "frontend",
+			expected:  false,
+			msg:       "references in the same namespace",
+		},
+		{
+			namespace: "other",
+			expected:  false,
+			msg:       "no references to the namespace",
+		},
+	}
+
+	rc := newCrossNamespaceReferenceChecker()
+	for _, test := range tests {
+		result := rc.IsReferencedByIngress(test.namespace, "", ing)
+		if result != test.expected {
+			t.Errorf("IsReferencedByIngress() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+
+		result = rc.IsReferencedByVirtualServer(test.namespace, "", vs)
+		if result != test.expected {
+			t.Errorf("IsReferencedByVirtualServer() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+
+		result = rc.IsReferencedByVirtualServerRoute(test.namespace, "", vsr)
+		if result != test.expected {
+			t.Errorf("IsReferencedByVirtualServerRoute() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
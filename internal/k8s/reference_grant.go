@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	"github.com/nginx/kubernetes-ingress/pkg/apis/configuration/validation"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func createReferenceGrantHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			rg := obj.(*conf_v1.ReferenceGrant)
+			nl.Debugf(lbc.Logger, "Adding ReferenceGrant: %v", rg.Name)
+			lbc.AddSyncQueue(rg)
+		},
+		DeleteFunc: func(obj interface{}) {
+			rg, isRg := obj.(*conf_v1.ReferenceGrant)
+			if !isRg {
+				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					nl.Debugf(lbc.Logger, "Error received unexpected object: %v", obj)
+					return
+				}
+				rg, ok = deletedState.Obj.(*conf_v1.ReferenceGrant)
+				if !ok {
+					nl.Debugf(lbc.Logger, "Error DeletedFinalStateUnknown contained non-ReferenceGrant object: %v", deletedState.Obj)
+					return
+				}
+			}
+			nl.Debugf(lbc.Logger, "Removing ReferenceGrant: %v", rg.Name)
+			lbc.AddSyncQueue(rg)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			curRg := cur.(*conf_v1.ReferenceGrant)
+			oldRg := old.(*conf_v1.ReferenceGrant)
+			if !reflect.DeepEqual(oldRg.Spec, curRg.Spec) {
+				nl.Debugf(lbc.Logger, "ReferenceGrant %v changed, syncing", curRg.Name)
+				lbc.AddSyncQueue(curRg)
+			}
+		},
+	}
+}
+
+func (nsi *namespacedInformer) addReferenceGrantHandler(handlers cache.ResourceEventHandlerFuncs) {
+	informer := nsi.confSharedInformerFactory.K8s().V1().ReferenceGrants().Informer()
+	informer.AddEventHandler(handlers) //nolint:errcheck,gosec
+	nsi.referenceGrantLister = informer.GetStore()
+
+	nsi.cacheSyncs = append(nsi.cacheSyncs, informer.HasSynced)
+}
+
+// getReferenceGrants returns the valid ReferenceGrants in the namespace.
+func (lbc *LoadBalancerController) getReferenceGrants(namespace string) []*conf_v1.ReferenceGrant {
+	nsi := lbc.getNamespacedInformer(namespace)
+	if nsi == nil || nsi.referenceGrantLister == nil {
+		return nil
+	}
+
+	var grants []*conf_v1.ReferenceGrant
+	for _, obj := range nsi.referenceGrantLister.List() {
+		rg := obj.(*conf_v1.ReferenceGrant)
+		if err := validation.ValidateReferenceGrant(rg); err != nil {
+			continue
+		}
+		grants = append(grants, rg)
+	}
+
+	return grants
+}
+
+func (lbc *LoadBalancerController) syncReferenceGrant(task task) {
+	key := task.Key
+
+	namespace, _, err := ParseNamespaceName(key)
+	if err != nil {
+		nl.Warnf(lbc.Logger, "ReferenceGrant key %v is invalid: %v", key, err)
+		return
+	}
+
+	nsi := lbc.getNamespacedInformer(namespace)
+	if nsi == nil || nsi.referenceGrantLister == nil {
+		nl.Debugf(lbc.Logger, "ReferenceGrant %v is not in a watched namespace", key)
+		return
+	}
+
+	obj, rgExists, err := nsi.referenceGrantLister.GetByKey(key)
+	if err != nil {
+		lbc.syncQueue.Requeue(task, err)
+		return
+	}
+
+	nl.Debugf(lbc.Logger, "Adding, Updating or Deleting ReferenceGrant: %v", key)
+
+	if rgExists {
+		rg := obj.(*conf_v1.ReferenceGrant)
+		err := validation.ValidateReferenceGrant(rg)
+		if err != nil {
+			msg := fmt.Sprintf("ReferenceGrant %v/%v is invalid and was ignored: %v", rg.Namespace, rg.Name, err)
+			lbc.recorder.Eventf(rg, api_v1.EventTypeWarning, nl.EventReasonRejected, msg)
+		} else {
+			msg := fmt.Sprintf("ReferenceGrant %v/%v was added or updated", rg.Namespace, rg.Name)
+			lbc.recorder.Eventf(rg, api_v1.EventTypeNormal, nl.EventReasonAddedOrUpdated, msg)
+		}
+	}
+
+	resources := lbc.findResourcesForReferenceGrant(namespace)
+	if len(resources) == 0 {
+		return
+	}
+
+	resourceExes := lbc.createExtendedResources(resources)
+
+	warnings, updateErr := lbc.configurator.AddOrUpdateResources(resourceExes, true)
+	lbc.updateResourcesStatusAndEvents(resources, warnings, updateErr)
+}
+
+// findResourcesForReferenceGrant finds the resources that reference Policies, Secrets or Services in the namespace
+// from other namespaces. Secrets are referenced through Policies.
+func (lbc *LoadBalancerController) findResourcesForReferenceGrant(namespace string) []Resource {
+	resources := lbc.configuration.FindResourcesForReferenceGrant(namespace)
+
+	for _, pol := range lbc.getAllPolicies() {
+		if pol.Namespace == namespace {
+			continue
+		}
+		for _, secretKey := range getPolicySecretKeys(pol) {
+			secretNamespace, _, _ := ParseNamespaceName(secretKey)
+			if secretNamespace == namespace {
+				resources = append(resources, lbc.configuration.FindResourcesForPolicy(pol.Namespace, pol.Name)...)
+				break
+			}
+		}
+	}
+
+	return removeDuplicateResources(resources)
+}
@@ -0,0 +1,412 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	"github.com/nginx/kubernetes-ingress/internal/nginx"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	"github.com/nginx/kubernetes-ingress/pkg/apis/configuration/validation"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// rolloutCheckInterval is how often the Rollouts are checked to step up the weights
+	// and to analyze the upstreams of the routes.
+	rolloutCheckInterval = 10 * time.Second
+
+	// defaultRolloutStepPause is the pause of the steps of a Rollout without a pause.
+	defaultRolloutStepPause = time.Minute
+
+	rolloutTaskKey = "rollout-checks"
+)
+
+// rolloutState is the progress of a Rollout.
+type rolloutState struct {
+	status conf_v1.RolloutStatus
+	// baseline holds the statistics of the upstream at the start of the current step.
+	baseline    nginx.UpstreamStats
+	hasBaseline bool
+	// applied is the weight update applied to the split clients of the route, if any.
+	applied *configs.WeightUpdate
+	// restore is the weight update that applies the weights of the splits of the VirtualServer again.
+	restore configs.WeightUpdate
+}
+
+// rolloutTarget is the route of a VirtualServer referenced by a Rollout.
+type rolloutTarget struct {
+	variableNamer     *configs.VariableNamer
+	splitClientsIndex int
+	// weight is the weight of the second split in the spec of the VirtualServer.
+	weight int
+	// upstream is the upstream of the second split. It is empty when the split doesn't pass requests to an upstream.
+	upstream string
+}
+
+func (t rolloutTarget) weightUpdate(weight int) configs.WeightUpdate {
+	return configs.WeightUpdate{
+		Zone:  t.variableNamer.GetNameOfKeyvalZoneForSplitClientIndex(t.splitClientsIndex),
+		Key:   t.variableNamer.GetNameOfKeyvalKeyForSplitClientIndex(t.splitClientsIndex),
+		Value: t.variableNamer.GetNameOfKeyOfMapForWeights(t.splitClientsIndex, 100-weight, weight),
+	}
+}
+
+func createRolloutHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			ro := obj.(*conf_v1.Rollout)
+			nl.Debugf(lbc.Logger, "Adding Rollout: %v", ro.Name)
+			lbc.AddSyncQueue(ro)
+		},
+		DeleteFunc: func(obj interface{}) {
+			ro, isRo := obj.(*conf_v1.Rollout)
+			if !isRo {
+				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					nl.Debugf(lbc.Logger, "Error received unexpected object: %v", obj)
+					return
+				}
+				ro, ok = deletedState.Obj.(*conf_v1.Rollout)
+				if !ok {
+					nl.Debugf(lbc.Logger, "Error DeletedFinalStateUnknown contained non-Rollout object: %v", deletedState.Obj)
+					return
+				}
+			}
+			nl.Debugf(lbc.Logger, "Removing Rollout: %v", ro.Name)
+			lbc.AddSyncQueue(ro)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			curRo := cur.(*conf_v1.Rollout)
+			oldRo := old.(*conf_v1.Rollout)
+			if !reflect.DeepEqual(oldRo.Spec, curRo.Spec) {
+				nl.Debugf(lbc.Logger, "Rollout %v changed, syncing", curRo.Name)
+				lbc.AddSyncQueue(curRo)
+			}
+		},
+	}
+}
+
+func (nsi *namespacedInformer) addRolloutHandler(handlers cache.ResourceEventHandlerFuncs) {
+	informer := nsi.confSharedInformerFactory.K8s().V1().Rollouts().Informer()
+	informer.AddEventHandler(handlers) //nolint:errcheck,gosec
+	nsi.rolloutLister = informer.GetStore()
+
+	nsi.cacheSyncs = append(nsi.cacheSyncs, informer.HasSynced)
+}
+
+// runRolloutChecks periodically enqueues a check of all Rollouts.
+func (lbc *LoadBalancerController) runRolloutChecks(ctx context.Context) {
+	ticker := time.NewTicker(rolloutCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			lbc.syncQueue.EnqueueTask(task{Kind: rollout, Key: rolloutTaskKey})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// syncRollout checks the Rollout of the task or all Rollouts for the periodic check.
+func (lbc *LoadBalancerController) syncRollout(task task) {
+	if task.Key != rolloutTaskKey {
+		lbc.syncRolloutByKey(task.Key)
+		return
+	}
+
+	var keys []string
+	for _, nsi := range lbc.namespacedInformers {
+		if nsi.rolloutLister == nil {
+			continue
+		}
+		keys = append(keys, nsi.rolloutLister.ListKeys()...)
+	}
+	for _, key := range keys {
+		lbc.syncRolloutByKey(key)
+	}
+
+	for key := range lbc.rollouts {
+		ns, _, _ := cache.SplitMetaNamespaceKey(key)
+		if nsi := lbc.getNamespacedInformer(ns); nsi == nil || nsi.rolloutLister == nil {
+			lbc.releaseRollout(lbc.rollouts[key])
+			delete(lbc.rollouts, key)
+		}
+	}
+}
+
+func (lbc *LoadBalancerController) syncRolloutByKey(key string) {
+	ns, _, _ := cache.SplitMetaNamespaceKey(key)
+	nsi := lbc.getNamespacedInformer(ns)
+	if nsi == nil || nsi.rolloutLister == nil {
+		return
+	}
+	obj, roExists, err := nsi.rolloutLister.GetByKey(key)
+	if err != nil {
+		nl.Errorf(lbc.Logger, "Error getting Rollout %v from the Store: %v", key, err)
+		return
+	}
+
+	if !roExists || !lbc.HasCorrectIngressClass(obj) {
+		if state, exists := lbc.rollouts[key]; exists {
+			nl.Debugf(lbc.Logger, "Deleting Rollout: %v", key)
+			lbc.releaseRollout(state)
+			delete(lbc.rollouts, key)
+		}
+		return
+	}
+
+	ro := obj.(*conf_v1.Rollout)
+	state, exists := lbc.rollouts[key]
+	if !exists {
+		// resume from the status, for example, after a restart of the Ingress Controller
+		state = &rolloutState{status: *ro.Status.DeepCopy()}
+		lbc.rollouts[key] = state
+	}
+	previous := state.status
+
+	if err := validation.ValidateRollout(ro, lbc.isLatencyMetricsEnabled); err != nil {
+		lbc.releaseRollout(state)
+		state.status = conf_v1.RolloutStatus{
+			State:              conf_v1.StateInvalid,
+			Reason:             nl.EventReasonRejected,
+			Message:            fmt.Sprintf("Rollout %v is invalid and was rejected: %v", key, err),
+			ObservedGeneration: ro.Generation,
+		}
+	} else if target, err := lbc.getRolloutTarget(ro); err != nil {
+		lbc.releaseRollout(state)
+		state.status = conf_v1.RolloutStatus{
+			State:              conf_v1.StateWarning,
+			Reason:             nl.EventReasonNoVirtualServerFound,
+			Message:            fmt.Sprintf("Rollout %v is not applied: %v", key, err),
+			ObservedGeneration: ro.Generation,
+		}
+	} else if !lbc.reportCustomResourceStatusEnabled() {
+		// only the leader analyzes and advances the Rollout, the other replicas apply the weight of its status
+		state.follow(ro)
+		if state.isApplicable(ro) {
+			lbc.applyRollout(state, target)
+		} else {
+			lbc.releaseRollout(state)
+		}
+		return
+	} else {
+		var stats *nginx.UpstreamStats
+		if ro.Spec.Analysis != nil && target.upstream != "" {
+			upstreamStats, err := lbc.configurator.GetUpstreamStats(target.upstream)
+			if err != nil {
+				nl.Debugf(lbc.Logger, "Error getting the statistics of the upstream %v for Rollout %v: %v", target.upstream, key, err)
+			} else {
+				stats = &upstreamStats
+			}
+		}
+
+		state.advance(ro, stats, time.Now())
+		lbc.applyRollout(state, target)
+	}
+
+	if state.status.State != previous.State || state.status.Message != previous.Message {
+		eventType := api_v1.EventTypeNormal
+		if state.status.State != conf_v1.RolloutStateProgressing && state.status.State != conf_v1.RolloutStateCompleted {
+			eventType = api_v1.EventTypeWarning
+		}
+		lbc.recorder.Eventf(ro, eventType, state.status.Reason, state.status.Message)
+	}
+
+	if lbc.reportCustomResourceStatusEnabled() {
+		if err := lbc.statusUpdater.UpdateRolloutStatus(ro, state.status); err != nil {
+			nl.Errorf(lbc.Logger, "Error updating the status of Rollout %v: %v", key, err)
+		}
+	}
+}
+
+// getRolloutTarget finds the route of the VirtualServer referenced by the Rollout.
+func (lbc *LoadBalancerController) getRolloutTarget(ro *conf_v1.Rollout) (rolloutTarget, error) {
+	vsKey := fmt.Sprintf("%s/%s", ro.Namespace, ro.Spec.VirtualServer)
+	obj, exists, err := lbc.getNamespacedInformer(ro.Namespace).virtualServerLister.GetByKey(vsKey)
+	if err != nil {
+		return rolloutTarget{}, err
+	}
+	if !exists {
+		return rolloutTarget{}, fmt.Errorf("VirtualServer %s doesn't exist", vsKey)
+	}
+	vs := obj.(*conf_v1.VirtualServer)
+
+	var splitClientsIndex int
+	for _, r := range vs.Spec.Routes {
+		if r.Path != ro.Spec.Route {
+			splitClientsIndex += getSplitClientsAmount(r)
+			continue
+		}
+
+		if len(r.Matches) > 0 || len(routeSplits(r)) != 2 {
+			return rolloutTarget{}, fmt.Errorf("route %s of VirtualServer %s must have two splits or a canary and no matches", r.Path, vsKey)
+		}
+
+		var action *conf_v1.Action
+		if r.Canary != nil {
+			action = r.Canary.Action
+		} else {
+			action = r.Splits[1].Action
+		}
+
+		target := rolloutTarget{
+			variableNamer:     configs.NewVSVariableNamer(vs),
+			splitClientsIndex: splitClientsIndex,
+			weight:            routeSplits(r)[1].Weight,
+		}
+		if action != nil && (action.Pass != "" || action.Proxy != nil) {
+			target.upstream = configs.NewUpstreamNamerForVirtualServer(vs).GetNameForUpstreamFromAction(action)
+		}
+		return target, nil
+	}
+
+	return rolloutTarget{}, fmt.Errorf("VirtualServer %s has no route %s", vsKey, ro.Spec.Route)
+}
+
+// applyRollout applies the weight of the Rollout to the split clients of the route.
+func (lbc *LoadBalancerController) applyRollout(state *rolloutState, target rolloutTarget) {
+	update := target.weightUpdate(state.status.Weight)
+	if state.applied != nil && *state.applied == update {
+		return
+	}
+	if state.applied != nil && state.applied.Zone != update.Zone {
+		lbc.releaseRollout(state)
+	}
+
+	lbc.configurator.SetSplitClientsKeyValOverride(update)
+	state.applied = &update
+	state.restore = target.weightUpdate(target.weight)
+}
+
+// releaseRollout applies the weights of the splits of the VirtualServer again.
+func (lbc *LoadBalancerController) releaseRollout(state *rolloutState) {
+	if state.applied == nil {
+		return
+	}
+	lbc.configurator.DeleteSplitClientsKeyValOverride(state.applied.Zone)
+	lbc.configurator.UpsertSplitClientsKeyVal(state.restore.Zone, state.restore.Key, state.restore.Value)
+	state.applied = nil
+}
+
+// advance starts, steps up, completes or rolls back the Rollout based on the statistics of the upstream.
+// The stats are nil when the statistics are not available.
+func (s *rolloutState) advance(ro *conf_v1.Rollout, stats *nginx.UpstreamStats, now time.Time) {
+	if s.status.ObservedGeneration != ro.Generation || !isRolloutState(s.status.State) {
+		s.startStep(ro, 0, stats, now)
+		return
+	}
+	if s.status.State != conf_v1.RolloutStateProgressing {
+		return
+	}
+
+	if stats != nil && (!s.hasBaseline || stats.Requests < s.baseline.Requests) {
+		// the step was resumed or NGINX was restarted
+		s.baseline = *stats
+		s.hasBaseline = true
+	}
+
+	if reason := analyzeRollout(ro.Spec.Analysis, s.baseline, stats); reason != "" {
+		s.status.State = conf_v1.RolloutStateRolledBack
+		s.status.Reason = nl.EventReasonRolledBack
+		s.status.Message = fmt.Sprintf("Rolled back at step %d: %s", s.status.CurrentStep+1, reason)
+		s.status.Weight = 0
+		return
+	}
+
+	if s.status.StepStartTime != nil && now.Before(s.status.StepStartTime.Add(getRolloutStepPause(ro.Spec.Steps[s.status.CurrentStep]))) {
+		return
+	}
+
+	next := s.status.CurrentStep + 1
+	if next == len(ro.Spec.Steps) {
+		s.status.State = conf_v1.RolloutStateCompleted
+		s.status.Reason = nl.EventReasonRolloutCompleted
+		s.status.Message = fmt.Sprintf("Completed all %d steps with the weight %d", len(ro.Spec.Steps), s.status.Weight)
+		return
+	}
+	s.startStep(ro, next, stats, now)
+}
+
+// follow takes the progress of the Rollout from its status.
+// The baseline is dropped, so that the analysis starts from the current statistics if the replica becomes the leader.
+func (s *rolloutState) follow(ro *conf_v1.Rollout) {
+	s.status = *ro.Status.DeepCopy()
+	s.hasBaseline = false
+}
+
+// isApplicable reports whether the status holds the weight of the current generation of the Rollout.
+func (s *rolloutState) isApplicable(ro *conf_v1.Rollout) bool {
+	return s.status.ObservedGeneration == ro.Generation && isRolloutState(s.status.State)
+}
+
+func (s *rolloutState) startStep(ro *conf_v1.Rollout, step int, stats *nginx.UpstreamStats, now time.Time) {
+	startTime := meta_v1.NewTime(now.Truncate(time.Second))
+	weight := ro.Spec.Steps[step].Weight
+
+	s.status = conf_v1.RolloutStatus{
+		State:              conf_v1.RolloutStateProgressing,
+		Reason:             nl.EventReasonRolloutProgressing,
+		Message:            fmt.Sprintf("Step %d of %d with the weight %d", step+1, len(ro.Spec.Steps), weight),
+		ObservedGeneration: ro.Generation,
+		CurrentStep:        step,
+		Weight:             weight,
+		StepStartTime:      &startTime,
+	}
+	s.hasBaseline = stats != nil
+	if stats != nil {
+		s.baseline = *stats
+	}
+}
+
+func isRolloutState(state string) bool {
+	return state == conf_v1.RolloutStateProgressing || state == conf_v1.RolloutStateCompleted || state == conf_v1.RolloutStateRolledBack
+}
+
+func getRolloutStepPause(step conf_v1.RolloutStep) time.Duration {
+	if step.Pause == "" {
+		return defaultRolloutStepPause
+	}
+	pause, err := configs.ParseTimeToDuration(step.Pause)
+	if err != nil {
+		return defaultRolloutStepPause
+	}
+	return pause
+}
+
+// analyzeRollout returns the reason for a rollback when the requests to the upstream since the baseline
+// breach the thresholds of the analysis, or an empty string otherwise.
+func analyzeRollout(analysis *conf_v1.RolloutAnalysis, baseline nginx.UpstreamStats, stats *nginx.UpstreamStats) string {
+	if analysis == nil || stats == nil || stats.Requests < baseline.Requests {
+		return ""
+	}
+
+	requests := stats.Requests - baseline.Requests
+	if requests == 0 || requests < uint64(analysis.MinRequests) { //nolint:gosec
+		return ""
+	}
+
+	if analysis.MaxErrorRate != nil && stats.Responses5xx >= baseline.Responses5xx {
+		errorRate := float64(stats.Responses5xx-baseline.Responses5xx) * 100 / float64(requests)
+		if errorRate > float64(*analysis.MaxErrorRate) {
+			return fmt.Sprintf("the error rate %.1f%% exceeded the maximum %d%%", errorRate, *analysis.MaxErrorRate)
+		}
+	}
+
+	if analysis.MaxLatency != "" && stats.TotalResponseTime >= baseline.TotalResponseTime {
+		maxLatency, err := configs.ParseTimeToDuration(analysis.MaxLatency)
+		latency := (stats.TotalResponseTime - baseline.TotalResponseTime) / time.Duration(requests) //nolint:gosec
+		if err == nil && latency > maxLatency {
+			return fmt.Sprintf("the average response time %v exceeded the maximum %v", latency, maxLatency)
+		}
+	}
+
+	return ""
+}
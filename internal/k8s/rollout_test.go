@@ -0,0 +1,184 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nginx/kubernetes-ingress/internal/nginx"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createTestRollout() *conf_v1.Rollout {
+	maxErrorRate := 10
+	return &conf_v1.Rollout{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:       "cafe",
+			Namespace:  "default",
+			Generation: 1,
+		},
+		Spec: conf_v1.RolloutSpec{
+			VirtualServer: "cafe",
+			Route:         "/tea",
+			Steps: []conf_v1.RolloutStep{
+				{Weight: 10, Pause: "1m"},
+				{Weight: 50},
+			},
+			Analysis: &conf_v1.RolloutAnalysis{
+				MaxErrorRate: &maxErrorRate,
+				MaxLatency:   "500ms",
+				MinRequests:  10,
+			},
+		},
+	}
+}
+
+func TestRolloutStateAdvance(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	ro := createTestRollout()
+	state := &rolloutState{}
+
+	state.advance(ro, &nginx.UpstreamStats{Requests: 100}, start)
+	if state.status.State != conf_v1.RolloutStateProgressing || state.status.CurrentStep != 0 || state.status.Weight != 10 {
+		t.Fatalf("advance() returned status %+v, expected the first step", state.status)
+	}
+
+	state.advance(ro, &nginx.UpstreamStats{Requests: 200, Responses5xx: 5}, start.Add(30*time.Second))
+	if state.status.CurrentStep != 0 {
+		t.Fatalf("advance() started the step %d before the pause elapsed", state.status.CurrentStep)
+	}
+
+	state.advance(ro, &nginx.UpstreamStats{Requests: 300, Responses5xx: 10}, start.Add(time.Minute))
+	if state.status.State != conf_v1.RolloutStateProgressing || state.status.CurrentStep != 1 || state.status.Weight != 50 {
+		t.Fatalf("advance() returned status %+v, expected the second step", state.status)
+	}
+
+	state.advance(ro, nil, start.Add(2*time.Minute))
+	if state.status.State != conf_v1.RolloutStateCompleted || state.status.Weight != 50 {
+		t.Fatalf("advance() returned status %+v, expected the completed Rollout", state.status)
+	}
+
+	ro.Generation = 2
+	state.advance(ro, nil, start.Add(3*time.Minute))
+	if state.status.State != conf_v1.RolloutStateProgressing || state.status.CurrentStep != 0 || state.status.ObservedGeneration != 2 {
+		t.Fatalf("advance() returned status %+v, expected the first step of the new generation", state.status)
+	}
+}
+
+func TestRolloutStateAdvanceRollsBack(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	ro := createTestRollout()
+	state := &rolloutState{}
+
+	state.advance(ro, &nginx.UpstreamStats{Requests: 100, Responses5xx: 50}, start)
+	state.advance(ro, &nginx.UpstreamStats{Requests: 105, Responses5xx: 55}, start.Add(10*time.Second))
+	if state.status.State != conf_v1.RolloutStateProgressing {
+		t.Fatalf("advance() returned status %+v, expected no rollback before the minimum number of requests", state.status)
+	}
+
+	state.advance(ro, &nginx.UpstreamStats{Requests: 120, Responses5xx: 55}, start.Add(20*time.Second))
+	if state.status.State != conf_v1.RolloutStateRolledBack || state.status.Weight != 0 {
+		t.Fatalf("advance() returned status %+v, expected the rolled back Rollout", state.status)
+	}
+
+	state.advance(ro, &nginx.UpstreamStats{Requests: 200}, start.Add(10*time.Minute))
+	if state.status.State != conf_v1.RolloutStateRolledBack {
+		t.Fatalf("advance() returned status %+v, expected the Rollout to stay rolled back", state.status)
+	}
+}
+
+func TestRolloutStateFollow(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	ro := createTestRollout()
+	state := &rolloutState{}
+	state.advance(ro, &nginx.UpstreamStats{Requests: 100}, start)
+
+	ro.Status = conf_v1.RolloutStatus{
+		State:              conf_v1.RolloutStateRolledBack,
+		ObservedGeneration: 1,
+		CurrentStep:        1,
+		Weight:             0,
+	}
+	state.follow(ro)
+	if state.status.State != conf_v1.RolloutStateRolledBack || state.status.Weight != 0 || state.hasBaseline {
+		t.Fatalf("follow() returned status %+v, expected the status of the Rollout without a baseline", state.status)
+	}
+	if !state.isApplicable(ro) {
+		t.Errorf("isApplicable() returned false for the status of the current generation")
+	}
+
+	ro.Generation = 2
+	if state.isApplicable(ro) {
+		t.Errorf("isApplicable() returned true for the status of the previous generation")
+	}
+
+	ro.Status = conf_v1.RolloutStatus{State: conf_v1.StateInvalid, ObservedGeneration: 2}
+	state.follow(ro)
+	if state.isApplicable(ro) {
+		t.Errorf("isApplicable() returned true for an invalid Rollout")
+	}
+}
+
+func TestAnalyzeRollout(t *testing.T) {
+	t.Parallel()
+	maxErrorRate := 10
+	analysis := &conf_v1.RolloutAnalysis{
+		MaxErrorRate: &maxErrorRate,
+		MaxLatency:   "500ms",
+		MinRequests:  10,
+	}
+	// the requests before the step had the average response time of 1s
+	baseline := nginx.UpstreamStats{Requests: 100, Responses5xx: 10, TotalResponseTime: 100 * time.Second}
+
+	tests := []struct {
+		stats    *nginx.UpstreamStats
+		rollback bool
+		msg      string
+	}{
+		{
+			stats:    nil,
+			rollback: false,
+			msg:      "no stats",
+		},
+		{
+			stats:    &nginx.UpstreamStats{Requests: 105, Responses5xx: 15},
+			rollback: false,
+			msg:      "not enough requests",
+		},
+		{
+			stats:    &nginx.UpstreamStats{Requests: 200, Responses5xx: 20, TotalResponseTime: 110 * time.Second},
+			rollback: false,
+			msg:      "within thresholds",
+		},
+		{
+			stats:    &nginx.UpstreamStats{Requests: 200, Responses5xx: 21},
+			rollback: true,
+			msg:      "error rate exceeded",
+		},
+		{
+			stats:    &nginx.UpstreamStats{Requests: 200, Responses5xx: 10, TotalResponseTime: 150100 * time.Millisecond},
+			rollback: true,
+			msg:      "latency exceeded",
+		},
+		{
+			stats:    &nginx.UpstreamStats{Requests: 200, Responses5xx: 10, TotalResponseTime: 90 * time.Second},
+			rollback: false,
+			msg:      "total response time decreased",
+		},
+		{
+			stats:    &nginx.UpstreamStats{Requests: 50, Responses5xx: 50},
+			rollback: false,
+			msg:      "counters reset",
+		},
+	}
+
+	for _, test := range tests {
+		reason := analyzeRollout(analysis, baseline, test.stats)
+		if (reason != "") != test.rollback {
+			t.Errorf("analyzeRollout() returned %q for the case of %s, expected rollback %v", reason, test.msg, test.rollback)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// APIKeyClientMetadataAnnotation enables the metadata of the clients of an API key secret when it is set to true.
+// Without it, every data field of the secret is the key of a client, so the IDs of the clients can have any suffix.
+const APIKeyClientMetadataAnnotation = "nginx.org/apikey-client-metadata"
+
+// The metadata of a client of an API key secret is stored in the data fields <client ID><suffix>.
+const (
+	// APIKeyExpiresSuffix is the suffix of the data field with the expiry time of the key of a client in the RFC 3339 format.
+	APIKeyExpiresSuffix = ".expires"
+	// APIKeyEnabledSuffix is the suffix of the data field that enables or disables the key of a client.
+	APIKeyEnabledSuffix = ".enabled"
+	// APIKeyTierSuffix is the suffix of the data field with the rate limit tier of a client.
+	APIKeyTierSuffix = ".tier"
+)
+
+var apiKeyTierRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// APIKeyClient is a client of an API key secret.
+type APIKeyClient struct {
+	ID       string
+	Key      []byte
+	Expires  time.Time
+	Disabled bool
+	Tier     string
+}
+
+// ParseAPIKeyClients returns the clients of an API key secret sorted by their IDs.
+// Every data field is the key of a client unless the client metadata is enabled by the APIKeyClientMetadataAnnotation
+// and its name ends with the suffix of the client metadata.
+func ParseAPIKeyClients(secret *api_v1.Secret) ([]*APIKeyClient, error) {
+	metadata := secret.Annotations[APIKeyClientMetadataAnnotation] == "true"
+
+	clients := make(map[string]*APIKeyClient)
+	for name, value := range secret.Data {
+		if metadata && isAPIKeyMetadata(name) {
+			continue
+		}
+		clients[name] = &APIKeyClient{ID: name, Key: value}
+	}
+
+	for name, value := range secret.Data {
+		if !metadata || !isAPIKeyMetadata(name) {
+			continue
+		}
+		ext := name[strings.LastIndex(name, "."):]
+		id := strings.TrimSuffix(name, ext)
+		client, exists := clients[id]
+		if !exists {
+			return nil, fmt.Errorf("data field %s refers to a missing client %s", name, id)
+		}
+
+		s := string(value)
+		switch ext {
+		case APIKeyExpiresSuffix:
+			expires, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return nil, fmt.Errorf("data field %s must be a time in the RFC 3339 format: %w", name, err)
+			}
+			client.Expires = expires
+		case APIKeyEnabledSuffix:
+			enabled, err := strconv.ParseBool(s)
+			if err != nil {
+				return nil, fmt.Errorf("data field %s must be true or false", name)
+			}
+			client.Disabled = !enabled
+		case APIKeyTierSuffix:
+			if !apiKeyTierRegexp.MatchString(s) {
+				return nil, fmt.Errorf("data field %s must consist of alphanumeric characters, '-' or '_'", name)
+			}
+			client.Tier = s
+		}
+	}
+
+	result := make([]*APIKeyClient, 0, len(clients))
+	for _, client := range clients {
+		result = append(result, client)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func isAPIKeyMetadata(name string) bool {
+	return strings.HasSuffix(name, APIKeyExpiresSuffix) ||
+		strings.HasSuffix(name, APIKeyEnabledSuffix) ||
+		strings.HasSuffix(name, APIKeyTierSuffix)
+}
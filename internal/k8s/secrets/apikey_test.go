@@ -0,0 +1,79 @@
+package secrets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseAPIKeyClients(t *testing.T) {
+	t.Parallel()
+	secret := &v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Annotations: map[string]string{
+				APIKeyClientMetadataAnnotation: "true",
+			},
+		},
+		Data: map[string][]byte{
+			"client2":         []byte("key2"),
+			"client1":         []byte("key1"),
+			"client1.expires": []byte("2030-01-01T00:00:00Z"),
+			"client1.tier":    []byte("gold"),
+			"client2.enabled": []byte("false"),
+		},
+	}
+
+	expected := []*APIKeyClient{
+		{
+			ID:      "client1",
+			Key:     []byte("key1"),
+			Expires: time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Tier:    "gold",
+		},
+		{
+			ID:       "client2",
+			Key:      []byte("key2"),
+			Disabled: true,
+		},
+	}
+
+	clients, err := ParseAPIKeyClients(secret)
+	if err != nil {
+		t.Fatalf("ParseAPIKeyClients() returned unexpected error %v", err)
+	}
+	if diff := cmp.Diff(expected, clients); diff != "" {
+		t.Errorf("ParseAPIKeyClients() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseAPIKeyClientsWithoutMetadata(t *testing.T) {
+	t.Parallel()
+	secret := &v1.Secret{
+		Data: map[string][]byte{
+			"client1":      []byte("key1"),
+			"client1.tier": []byte("key2"),
+		},
+	}
+
+	expected := []*APIKeyClient{
+		{
+			ID:  "client1",
+			Key: []byte("key1"),
+		},
+		{
+			ID:  "client1.tier",
+			Key: []byte("key2"),
+		},
+	}
+
+	clients, err := ParseAPIKeyClients(secret)
+	if err != nil {
+		t.Fatalf("ParseAPIKeyClients() returned unexpected error %v", err)
+	}
+	if diff := cmp.Diff(expected, clients); diff != "" {
+		t.Errorf("ParseAPIKeyClients() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,56 @@
+package secrets
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// IsCertificateSecret checks if the secret holds a certificate with an expiry date:
+// a TLS secret or a CA secret.
+func IsCertificateSecret(secret *api_v1.Secret) bool {
+	return secret.Type == api_v1.SecretTypeTLS || secret.Type == SecretTypeCA
+}
+
+// GetCertificateNotAfter returns the expiry date of the first certificate stored in a TLS or a CA secret.
+func GetCertificateNotAfter(secret *api_v1.Secret) (time.Time, error) {
+	if secret == nil {
+		return time.Time{}, fmt.Errorf("secret doesn't exist")
+	}
+
+	var dataKey string
+	switch secret.Type {
+	case api_v1.SecretTypeTLS:
+		dataKey = api_v1.TLSCertKey
+	case SecretTypeCA:
+		dataKey = CAKey
+	default:
+		return time.Time{}, fmt.Errorf("secret of the type %v doesn't hold a certificate", secret.Type)
+	}
+
+	block, _ := pem.Decode(secret.Data[dataKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, fmt.Errorf("the data field %s must hold a valid CERTIFICATE PEM block", dataKey)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return cert.NotAfter, nil
+}
+
+// IsCertificateExpired checks if the certificate stored in a TLS or a CA secret has expired at the given time.
+// It also returns the expiry date of the certificate.
+// Secrets that don't hold a parsable certificate are never reported as expired.
+func IsCertificateExpired(secret *api_v1.Secret, now time.Time) (bool, time.Time) {
+	notAfter, err := GetCertificateNotAfter(secret)
+	if err != nil {
+		return false, time.Time{}
+	}
+	return now.After(notAfter), notAfter
+}
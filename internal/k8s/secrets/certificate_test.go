@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"testing"
+	"time"
+
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetCertificateNotAfter(t *testing.T) {
+	t.Parallel()
+	expected := time.Date(2023, time.September, 11, 16, 15, 35, 0, time.UTC)
+	secrets := []*api_v1.Secret{
+		{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "tls-secret",
+				Namespace: "default",
+			},
+			Type: api_v1.SecretTypeTLS,
+			Data: map[string][]byte{
+				"tls.crt": validCert,
+				"tls.key": validKey,
+			},
+		},
+		{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "ca-secret",
+				Namespace: "default",
+			},
+			Type: SecretTypeCA,
+			Data: map[string][]byte{
+				"ca.crt": validCACert,
+			},
+		},
+	}
+
+	for _, secret := range secrets {
+		notAfter, err := GetCertificateNotAfter(secret)
+		if err != nil {
+			t.Errorf("GetCertificateNotAfter() returned unexpected error for secret of type %v: %v", secret.Type, err)
+		}
+		if !notAfter.Equal(expected) {
+			t.Errorf("GetCertificateNotAfter() returned %v for secret of type %v, expected %v", notAfter, secret.Type, expected)
+		}
+	}
+}
+
+func TestGetCertificateNotAfterFails(t *testing.T) {
+	t.Parallel()
+	secrets := []*api_v1.Secret{
+		{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "tls-secret",
+				Namespace: "default",
+			},
+			Type: api_v1.SecretTypeTLS,
+			Data: map[string][]byte{
+				"tls.crt": invalidCert,
+				"tls.key": validKey,
+			},
+		},
+		{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "ca-secret",
+				Namespace: "default",
+			},
+			Type: SecretTypeCA,
+			Data: map[string][]byte{
+				"ca.crt": invalidCACertWithNoPEMBlock,
+			},
+		},
+		{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "jwk-secret",
+				Namespace: "default",
+			},
+			Type: SecretTypeJWK,
+			Data: map[string][]byte{
+				"jwk": nil,
+			},
+		},
+	}
+
+	for _, secret := range secrets {
+		_, err := GetCertificateNotAfter(secret)
+		if err == nil {
+			t.Errorf("GetCertificateNotAfter() returned no error for secret %v of type %v", secret.Name, secret.Type)
+		}
+	}
+}
+
+func TestIsCertificateExpired(t *testing.T) {
+	t.Parallel()
+	secret := &api_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "tls-secret",
+			Namespace: "default",
+		},
+		Type: api_v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"tls.crt": validCert,
+			"tls.key": validKey,
+		},
+	}
+
+	tests := []struct {
+		now      time.Time
+		expected bool
+		msg      string
+	}{
+		{
+			now:      time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC),
+			expected: false,
+			msg:      "certificate is valid",
+		},
+		{
+			now:      time.Date(2023, time.September, 12, 0, 0, 0, 0, time.UTC),
+			expected: true,
+			msg:      "certificate has expired",
+		},
+	}
+
+	for _, test := range tests {
+		expired, _ := IsCertificateExpired(secret, test.now)
+		if expired != test.expected {
+			t.Errorf("IsCertificateExpired() returned %v but expected %v for the case of %s", expired, test.expected, test.msg)
+		}
+	}
+}
@@ -19,9 +19,21 @@ const CAKey = "ca.crt"
 // ClientSecretKey is the key of the data field of a Secret where the OIDC client secret must be stored.
 const ClientSecretKey = "client-secret"
 
+// ClientIDKey is the key of the data field of a Secret where the OAuth2 client ID must be stored.
+const ClientIDKey = "client-id"
+
 // HtpasswdFileKey is the key of the data field of a Secret where the HTTP basic authorization list must be stored
 const HtpasswdFileKey = "htpasswd"
 
+// AccessKeyIDKey is the key of the data field of a Secret where the access key ID of an object storage must be stored.
+const AccessKeyIDKey = "access-key-id"
+
+// SecretAccessKeyKey is the key of the data field of a Secret where the secret access key of an object storage must be stored.
+const SecretAccessKeyKey = "secret-access-key" //nolint:gosec // G101: Potential hardcoded credentials - false positive
+
+// HMACKeyKey is the key of the data field of a Secret where the HMAC key for the signing and the verification of request signatures and cookies must be stored.
+const HMACKeyKey = "hmac-key"
+
 // SecretTypeCA contains a certificate authority for TLS certificate verification. #nosec G101
 const SecretTypeCA api_v1.SecretType = "nginx.org/ca" //nolint:gosec // G101: Potential hardcoded credentials - false positive
 
@@ -37,6 +49,15 @@ const SecretTypeHtpasswd api_v1.SecretType = "nginx.org/htpasswd" // #nosec G101
 // SecretTypeAPIKey contains a list of client ID and key for API key authorization.. #nosec G101
 const SecretTypeAPIKey api_v1.SecretType = "nginx.org/apikey" // #nosec G101
 
+// SecretTypeObjectStorage contains the access keys for signing the requests to an S3-compatible object storage. #nosec G101
+const SecretTypeObjectStorage api_v1.SecretType = "nginx.org/object-storage" // #nosec G101
+
+// SecretTypeOAuth2Introspection contains the client credentials for an OAuth2 token introspection endpoint. #nosec G101
+const SecretTypeOAuth2Introspection api_v1.SecretType = "nginx.org/oauth2-introspection" // #nosec G101
+
+// SecretTypeHMAC contains the key for the HMAC signatures of requests and challenge cookies. #nosec G101
+const SecretTypeHMAC api_v1.SecretType = "nginx.org/hmac" // #nosec G101
+
 // SecretTypeLicense contains the license.jwt required for NGINX Plus. #nosec G101
 const SecretTypeLicense api_v1.SecretType = "nginx.com/license" // #nosec G101
 
@@ -121,12 +142,17 @@ func ValidateAPIKeySecret(secret *api_v1.Secret) error {
 		return fmt.Errorf("APIKey secret must be of the type %v", SecretTypeAPIKey)
 	}
 
+	clients, err := ParseAPIKeyClients(secret)
+	if err != nil {
+		return err
+	}
+
 	uniqueKeys := make(map[string]bool)
-	for _, key := range secret.Data {
-		if uniqueKeys[string(key)] {
+	for _, client := range clients {
+		if uniqueKeys[string(client.Key)] {
 			return fmt.Errorf("API Keys cannot be repeated")
 		}
-		uniqueKeys[string(key)] = true
+		uniqueKeys[string(client.Key)] = true
 	}
 
 	return nil
@@ -148,6 +174,69 @@ func ValidateHtpasswdSecret(secret *api_v1.Secret) error {
 	return nil
 }
 
+// ValidateObjectStorageSecret validates the secret. If it is valid, the function returns nil.
+func ValidateObjectStorageSecret(secret *api_v1.Secret) error {
+	if secret.Type != SecretTypeObjectStorage {
+		return fmt.Errorf("object storage secret must be of the type %v", SecretTypeObjectStorage)
+	}
+
+	for _, key := range []string{AccessKeyIDKey, SecretAccessKeyKey} {
+		value, exists := secret.Data[key]
+		if !exists {
+			return fmt.Errorf("object storage secret must have the data field %v", key)
+		}
+		if len(value) == 0 {
+			return fmt.Errorf("object storage secret must have a non-empty data field %v", key)
+		}
+		if msg, ok := isValidClientSecretValue(string(value)); !ok {
+			return fmt.Errorf("object storage secret data field %v is invalid: %s", key, msg)
+		}
+	}
+
+	return nil
+}
+
+// ValidateOAuth2IntrospectionSecret validates the secret. If it is valid, the function returns nil.
+func ValidateOAuth2IntrospectionSecret(secret *api_v1.Secret) error {
+	if secret.Type != SecretTypeOAuth2Introspection {
+		return fmt.Errorf("OAuth2 introspection secret must be of the type %v", SecretTypeOAuth2Introspection)
+	}
+
+	for _, key := range []string{ClientIDKey, ClientSecretKey} {
+		value, exists := secret.Data[key]
+		if !exists {
+			return fmt.Errorf("OAuth2 introspection secret must have the data field %v", key)
+		}
+		if len(value) == 0 {
+			return fmt.Errorf("OAuth2 introspection secret must have a non-empty data field %v", key)
+		}
+	}
+
+	// we don't validate the characters of the client credentials, because they are encoded
+	// in the Authorization header of the requests to the introspection endpoint.
+
+	return nil
+}
+
+// ValidateHMACSecret validates the secret. If it is valid, the function returns nil.
+func ValidateHMACSecret(secret *api_v1.Secret) error {
+	if secret.Type != SecretTypeHMAC {
+		return fmt.Errorf("HMAC secret must be of the type %v", SecretTypeHMAC)
+	}
+
+	value, exists := secret.Data[HMACKeyKey]
+	if !exists {
+		return fmt.Errorf("HMAC secret must have the data field %v", HMACKeyKey)
+	}
+	if len(value) == 0 {
+		return fmt.Errorf("HMAC secret must have a non-empty data field %v", HMACKeyKey)
+	}
+
+	// the key can contain any bytes, because it is base64-encoded in the config file.
+
+	return nil
+}
+
 // ValidateLicenseSecret validates the secret. If it is valid, the function returns nil.
 func ValidateLicenseSecret(secret *api_v1.Secret) error {
 	if secret.Type != SecretTypeLicense {
@@ -169,6 +258,9 @@ func IsSupportedSecretType(secretType api_v1.SecretType) bool {
 		secretType == SecretTypeOIDC ||
 		secretType == SecretTypeHtpasswd ||
 		secretType == SecretTypeAPIKey ||
+		secretType == SecretTypeObjectStorage ||
+		secretType == SecretTypeOAuth2Introspection ||
+		secretType == SecretTypeHMAC ||
 		secretType == SecretTypeLicense
 }
 
@@ -187,6 +279,12 @@ func ValidateSecret(secret *api_v1.Secret) error {
 		return ValidateHtpasswdSecret(secret)
 	case SecretTypeAPIKey:
 		return ValidateAPIKeySecret(secret)
+	case SecretTypeObjectStorage:
+		return ValidateObjectStorageSecret(secret)
+	case SecretTypeOAuth2Introspection:
+		return ValidateOAuth2IntrospectionSecret(secret)
+	case SecretTypeHMAC:
+		return ValidateHMACSecret(secret)
 	case SecretTypeLicense:
 		return ValidateLicenseSecret(secret)
 	}
@@ -72,11 +72,18 @@ func TestValidateValidateAPIKeySecret(t *testing.T) {
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      "api-key-secret",
 			Namespace: "default",
+			Annotations: map[string]string{
+				APIKeyClientMetadataAnnotation: "true",
+			},
 		},
 		Type: SecretTypeAPIKey,
 		Data: map[string][]byte{
-			"client1": []byte("cGFzc3dvcmQ="),
-			"client2": []byte("N2ViNDMwOGItY2Q1Yi00NDEzLWI0NTUtYjMyZmQ4OTg2MmZk"),
+			"client1":         []byte("cGFzc3dvcmQ="),
+			"client2":         []byte("N2ViNDMwOGItY2Q1Yi00NDEzLWI0NTUtYjMyZmQ4OTg2MmZk"),
+			"client1.expires": []byte("2030-01-01T00:00:00Z"),
+			"client1.tier":    []byte("gold"),
+			"client2.tier":    []byte("gold"),
+			"client2.enabled": []byte("false"),
 		},
 	}
 
@@ -134,6 +141,74 @@ func TestValidateValidateAPIKeyFails(t *testing.T) {
 			},
 			msg: "repeated empty API Keys for API Key secret",
 		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "api-key-secret",
+					Namespace: "default",
+					Annotations: map[string]string{
+						APIKeyClientMetadataAnnotation: "true",
+					},
+				},
+				Type: SecretTypeAPIKey,
+				Data: map[string][]byte{
+					"client1":         []byte("cGFzc3dvcmQ="),
+					"client2.expires": []byte("2030-01-01T00:00:00Z"),
+				},
+			},
+			msg: "metadata of a missing client for API Key secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "api-key-secret",
+					Namespace: "default",
+					Annotations: map[string]string{
+						APIKeyClientMetadataAnnotation: "true",
+					},
+				},
+				Type: SecretTypeAPIKey,
+				Data: map[string][]byte{
+					"client1":         []byte("cGFzc3dvcmQ="),
+					"client1.expires": []byte("2030-01-01"),
+				},
+			},
+			msg: "invalid expiry time for API Key secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "api-key-secret",
+					Namespace: "default",
+					Annotations: map[string]string{
+						APIKeyClientMetadataAnnotation: "true",
+					},
+				},
+				Type: SecretTypeAPIKey,
+				Data: map[string][]byte{
+					"client1":         []byte("cGFzc3dvcmQ="),
+					"client1.enabled": []byte("no"),
+				},
+			},
+			msg: "invalid enabled flag for API Key secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "api-key-secret",
+					Namespace: "default",
+					Annotations: map[string]string{
+						APIKeyClientMetadataAnnotation: "true",
+					},
+				},
+				Type: SecretTypeAPIKey,
+				Data: map[string][]byte{
+					"client1":      []byte("cGFzc3dvcmQ="),
+					"client1.tier": []byte("gold tier"),
+				},
+			},
+			msg: "invalid tier for API Key secret",
+		},
 	}
 
 	for _, test := range tests {
@@ -458,6 +533,248 @@ func TestValidateOIDCSecretFails(t *testing.T) {
 	}
 }
 
+func TestValidateObjectStorageSecret(t *testing.T) {
+	t.Parallel()
+	secret := &v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "minio-credentials",
+			Namespace: "default",
+		},
+		Type: SecretTypeObjectStorage,
+		Data: map[string][]byte{
+			"access-key-id":     []byte("minioadmin"),
+			"secret-access-key": []byte("wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"),
+		},
+	}
+
+	err := ValidateObjectStorageSecret(secret)
+	if err != nil {
+		t.Errorf("ValidateObjectStorageSecret() returned error %v", err)
+	}
+}
+
+func TestValidateObjectStorageSecretFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		secret *v1.Secret
+		msg    string
+	}{
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "minio-credentials",
+					Namespace: "default",
+				},
+				Type: "some-type",
+				Data: map[string][]byte{
+					"access-key-id":     []byte("minioadmin"),
+					"secret-access-key": []byte("minioadmin"),
+				},
+			},
+			msg: "Incorrect type for object storage secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "minio-credentials",
+					Namespace: "default",
+				},
+				Type: SecretTypeObjectStorage,
+				Data: map[string][]byte{
+					"access-key-id": []byte("minioadmin"),
+				},
+			},
+			msg: "Missing secret-access-key for object storage secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "minio-credentials",
+					Namespace: "default",
+				},
+				Type: SecretTypeObjectStorage,
+				Data: map[string][]byte{
+					"access-key-id":     []byte(""),
+					"secret-access-key": []byte("minioadmin"),
+				},
+			},
+			msg: "Empty access-key-id for object storage secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "minio-credentials",
+					Namespace: "default",
+				},
+				Type: SecretTypeObjectStorage,
+				Data: map[string][]byte{
+					"access-key-id":     []byte("minioadmin"),
+					"secret-access-key": []byte("minio$admin"),
+				},
+			},
+			msg: "Invalid characters in secret-access-key for object storage secret",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateObjectStorageSecret(test.secret)
+		if err == nil {
+			t.Errorf("ValidateObjectStorageSecret() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateOAuth2IntrospectionSecret(t *testing.T) {
+	t.Parallel()
+	secret := &v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "introspection-credentials",
+			Namespace: "default",
+		},
+		Type: SecretTypeOAuth2Introspection,
+		Data: map[string][]byte{
+			"client-id":     []byte("nginx-ingress"),
+			"client-secret": []byte("s3cr$t"),
+		},
+	}
+
+	err := ValidateOAuth2IntrospectionSecret(secret)
+	if err != nil {
+		t.Errorf("ValidateOAuth2IntrospectionSecret() returned error %v", err)
+	}
+}
+
+func TestValidateOAuth2IntrospectionSecretFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		secret *v1.Secret
+		msg    string
+	}{
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "introspection-credentials",
+					Namespace: "default",
+				},
+				Type: SecretTypeOIDC,
+				Data: map[string][]byte{
+					"client-id":     []byte("nginx-ingress"),
+					"client-secret": []byte("secret"),
+				},
+			},
+			msg: "Incorrect type for OAuth2 introspection secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "introspection-credentials",
+					Namespace: "default",
+				},
+				Type: SecretTypeOAuth2Introspection,
+				Data: map[string][]byte{
+					"client-secret": []byte("secret"),
+				},
+			},
+			msg: "Missing client-id for OAuth2 introspection secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "introspection-credentials",
+					Namespace: "default",
+				},
+				Type: SecretTypeOAuth2Introspection,
+				Data: map[string][]byte{
+					"client-id":     []byte("nginx-ingress"),
+					"client-secret": []byte(""),
+				},
+			},
+			msg: "Empty client-secret for OAuth2 introspection secret",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateOAuth2IntrospectionSecret(test.secret)
+		if err == nil {
+			t.Errorf("ValidateOAuth2IntrospectionSecret() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateHMACSecret(t *testing.T) {
+	t.Parallel()
+	secret := &v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "webhook-secret",
+			Namespace: "default",
+		},
+		Type: SecretTypeHMAC,
+		Data: map[string][]byte{
+			"hmac-key": []byte("s3cr$t \"key\""),
+		},
+	}
+
+	err := ValidateHMACSecret(secret)
+	if err != nil {
+		t.Errorf("ValidateHMACSecret() returned error %v", err)
+	}
+}
+
+func TestValidateHMACSecretFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		secret *v1.Secret
+		msg    string
+	}{
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "webhook-secret",
+					Namespace: "default",
+				},
+				Type: SecretTypeAPIKey,
+				Data: map[string][]byte{
+					"hmac-key": []byte("secret"),
+				},
+			},
+			msg: "Incorrect type for HMAC secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "webhook-secret",
+					Namespace: "default",
+				},
+				Type: SecretTypeHMAC,
+				Data: map[string][]byte{
+					"key": []byte("secret"),
+				},
+			},
+			msg: "Missing hmac-key for HMAC secret",
+		},
+		{
+			secret: &v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "webhook-secret",
+					Namespace: "default",
+				},
+				Type: SecretTypeHMAC,
+				Data: map[string][]byte{
+					"hmac-key": []byte(""),
+				},
+			},
+			msg: "Empty hmac-key for HMAC secret",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateHMACSecret(test.secret)
+		if err == nil {
+			t.Errorf("ValidateHMACSecret() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateLicenseSecret(t *testing.T) {
 	t.Parallel()
 	secret := &v1.Secret{
@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"fmt"
+
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	"github.com/nginx/kubernetes-ingress/internal/healthcheck"
+	api_v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// GetUpstreamPeers returns the peers of an upstream derived from the EndpointSlices of its Service in the cache.
+// Only the endpoints that serve the port of the upstream and, when the upstream has a subselector,
+// the endpoints of the selected pods are returned. A peer is up when its endpoint is ready.
+func (lbc *LoadBalancerController) GetUpstreamPeers(upstream configs.UpstreamService) ([]healthcheck.PeerStats, error) {
+	if upstream.Service == "" {
+		return nil, nil
+	}
+
+	// the method runs on the goroutine of the health check server, while the sync goroutine changes the watched namespaces
+	lbc.namespacedInformersLock.RLock()
+	nsi := lbc.getNamespacedInformer(upstream.Namespace)
+	lbc.namespacedInformersLock.RUnlock()
+	if nsi == nil {
+		return nil, fmt.Errorf("namespace %s is not watched", upstream.Namespace)
+	}
+
+	svcObj, exists, err := nsi.svcLister.GetByKey(upstream.Namespace + "/" + upstream.Service)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	svc := svcObj.(*api_v1.Service)
+
+	endpointSlices, err := nsi.endpointSliceLister.GetServiceEndpointSlices(svc)
+	if err != nil {
+		// the Service has no EndpointSlices, so the upstream has no peers
+		return nil, nil
+	}
+
+	var targetPort int32
+	for _, port := range svc.Spec.Ports {
+		if port.Port == int32(upstream.Port) {
+			targetPort, err = lbc.getTargetPort(port, svc)
+			if err != nil {
+				return nil, fmt.Errorf("error determining target port for port %v in service %v: %w", upstream.Port, svc.Name, err)
+			}
+			break
+		}
+	}
+	if targetPort == 0 {
+		return nil, fmt.Errorf("no port %v in service %s", upstream.Port, svc.Name)
+	}
+
+	var podIPs map[string]bool
+	if len(upstream.Subselector) > 0 {
+		selector := labels.Merge(svc.Spec.Selector, upstream.Subselector).AsSelector()
+		pods, err := nsi.podLister.ListByNamespace(svc.Namespace, selector)
+		if err != nil {
+			return nil, fmt.Errorf("error getting pods in namespace %v that match the selector %v: %w", svc.Namespace, selector, err)
+		}
+		podIPs = make(map[string]bool)
+		for _, pod := range pods {
+			podIPs[pod.Status.PodIP] = true
+		}
+	}
+
+	return peersFromEndpointSlices(selectEndpointSlicesForPort(targetPort, endpointSlices), targetPort, podIPs), nil
+}
+
+// peersFromEndpointSlices returns the peers of the endpoints of the EndpointSlices. When podIPs is not nil,
+// only the endpoints with the addresses of the pods are returned.
+func peersFromEndpointSlices(endpointSlices []discovery_v1.EndpointSlice, targetPort int32, podIPs map[string]bool) []healthcheck.PeerStats {
+	var peers []healthcheck.PeerStats
+	seen := make(map[string]bool)
+	for _, slice := range endpointSlices {
+		for _, endpoint := range slice.Endpoints {
+			state := "up"
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				state = "unhealthy"
+			}
+			for _, address := range endpoint.Addresses {
+				if podIPs != nil && !podIPs[address] {
+					continue
+				}
+				server := ipv6SafeAddrPort(address, targetPort)
+				if seen[server] {
+					continue
+				}
+				seen[server] = true
+				peers = append(peers, healthcheck.PeerStats{Server: server, State: state})
+			}
+		}
+	}
+	return peers
+}
@@ -0,0 +1,155 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nginx/kubernetes-ingress/internal/configs"
+	"github.com/nginx/kubernetes-ingress/internal/healthcheck"
+	nl "github.com/nginx/kubernetes-ingress/internal/logger"
+	api_v1 "k8s.io/api/core/v1"
+	discovery_v1 "k8s.io/api/discovery/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestGetUpstreamPeers(t *testing.T) {
+	t.Parallel()
+	ready := true
+	notReady := false
+
+	svcLister := cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
+	err := svcLister.Add(&api_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "coffee-svc", Namespace: "default"},
+		Spec: api_v1.ServiceSpec{
+			Selector: map[string]string{"app": "coffee"},
+			Ports: []api_v1.ServicePort{
+				{Port: 80, TargetPort: intstr.FromInt32(8080)},
+				{Port: 90, TargetPort: intstr.FromInt32(9090)},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpointSliceLister := storeToEndpointSliceLister{Store: cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)}
+	port8080 := int32(8080)
+	port9090 := int32(9090)
+	for _, slice := range []*discovery_v1.EndpointSlice{
+		{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "coffee-svc-8080",
+				Namespace: "default",
+				Labels:    map[string]string{discovery_v1.LabelServiceName: "coffee-svc"},
+			},
+			Ports: []discovery_v1.EndpointPort{{Port: &port8080}},
+			Endpoints: []discovery_v1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}, Conditions: discovery_v1.EndpointConditions{Ready: &ready}},
+				{Addresses: []string{"10.0.0.2"}, Conditions: discovery_v1.EndpointConditions{Ready: &notReady}},
+				{Addresses: []string{"10.0.0.3"}, Conditions: discovery_v1.EndpointConditions{Ready: &ready}},
+			},
+		},
+		{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "coffee-svc-9090",
+				Namespace: "default",
+				Labels:    map[string]string{discovery_v1.LabelServiceName: "coffee-svc"},
+			},
+			Ports: []discovery_v1.EndpointPort{{Port: &port9090}},
+			Endpoints: []discovery_v1.Endpoint{
+				{Addresses: []string{"10.0.0.9"}, Conditions: discovery_v1.EndpointConditions{Ready: &ready}},
+			},
+		},
+	} {
+		if err := endpointSliceLister.Add(slice); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	podLister := indexerToPodLister{Indexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})}
+	for name, pod := range map[string]struct {
+		ip      string
+		version string
+	}{
+		"coffee-1": {ip: "10.0.0.1", version: "v1"},
+		"coffee-2": {ip: "10.0.0.2", version: "v1"},
+		"coffee-3": {ip: "10.0.0.3", version: "v2"},
+	} {
+		err := podLister.Add(&api_v1.Pod{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "coffee", "version": pod.version},
+			},
+			Status: api_v1.PodStatus{PodIP: pod.ip},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lbc := LoadBalancerController{
+		namespacedInformers: map[string]*namespacedInformer{
+			"": {
+				svcLister:           svcLister,
+				endpointSliceLister: endpointSliceLister,
+				podLister:           podLister,
+			},
+		},
+		Logger: nl.LoggerFromContext(context.Background()),
+	}
+
+	tests := []struct {
+		upstream configs.UpstreamService
+		expected []healthcheck.PeerStats
+		msg      string
+	}{
+		{
+			upstream: configs.UpstreamService{Service: "coffee-svc", Port: 80, Namespace: "default"},
+			expected: []healthcheck.PeerStats{
+				{Server: "10.0.0.1:8080", State: "up"},
+				{Server: "10.0.0.2:8080", State: "unhealthy"},
+				{Server: "10.0.0.3:8080", State: "up"},
+			},
+			msg: "endpoints of the upstream port",
+		},
+		{
+			upstream: configs.UpstreamService{Service: "coffee-svc", Port: 90, Namespace: "default"},
+			expected: []healthcheck.PeerStats{
+				{Server: "10.0.0.9:9090", State: "up"},
+			},
+			msg: "endpoints of another upstream port",
+		},
+		{
+			upstream: configs.UpstreamService{Service: "coffee-svc", Port: 80, Subselector: map[string]string{"version": "v1"}, Namespace: "default"},
+			expected: []healthcheck.PeerStats{
+				{Server: "10.0.0.1:8080", State: "up"},
+				{Server: "10.0.0.2:8080", State: "unhealthy"},
+			},
+			msg: "endpoints of the pods selected by the subselector",
+		},
+		{
+			upstream: configs.UpstreamService{Service: "tea-svc", Port: 80, Namespace: "default"},
+			expected: nil,
+			msg:      "missing service",
+		},
+	}
+
+	for _, test := range tests {
+		peers, err := lbc.GetUpstreamPeers(test.upstream)
+		if err != nil {
+			t.Errorf("GetUpstreamPeers() returned unexpected error %v for the case of %s", err, test.msg)
+		}
+		if diff := cmp.Diff(test.expected, peers); diff != "" {
+			t.Errorf("GetUpstreamPeers() returned unexpected result for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+
+	_, err = lbc.GetUpstreamPeers(configs.UpstreamService{Service: "coffee-svc", Port: 8000, Namespace: "default"})
+	if err == nil {
+		t.Error("GetUpstreamPeers() returned no error for a port that the service doesn't have")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -37,11 +38,13 @@ type statusUpdater struct {
 	externalEndpoints        []conf_v1.ExternalEndpoint
 	status                   []networking.IngressLoadBalancerIngress
 	statusInitialized        bool
-	keyFunc                  func(obj interface{}) (string, error)
-	namespacedInformers      map[string]*namespacedInformer
-	confClient               k8s_nginx.Interface
-	hasCorrectIngressClass   func(interface{}) bool
-	logger                   *slog.Logger
+	// expiredCertificateIngresses holds the keys of the Ingresses that reference an expired certificate.
+	expiredCertificateIngresses map[string]bool
+	keyFunc                     func(obj interface{}) (string, error)
+	namespacedInformers         map[string]*namespacedInformer
+	confClient                  k8s_nginx.Interface
+	hasCorrectIngressClass      func(interface{}) bool
+	logger                      *slog.Logger
 }
 
 func (su *statusUpdater) UpdateExternalEndpointsForResources(resource []Resource) error {
@@ -135,6 +138,10 @@ func (su *statusUpdater) updateIngressWithStatus(ing networking.Ingress, status
 		return err
 	}
 
+	if su.expiredCertificateIngresses[key] {
+		status = withExpiredCertificateError(status)
+	}
+
 	ns, _, _ := cache.SplitMetaNamespaceKey(key)
 	var ingCopy *networking.Ingress
 	var exists bool
@@ -168,6 +175,48 @@ func (su *statusUpdater) updateIngressWithStatus(ing networking.Ingress, status
 	return nil
 }
 
+// SetExpiredCertificateIngresses replaces the keys of the Ingresses that reference an expired certificate
+// and returns the keys of the Ingresses whose status must be updated.
+func (su *statusUpdater) SetExpiredCertificateIngresses(keys map[string]bool) []string {
+	var changed []string
+	for key := range keys {
+		if !su.expiredCertificateIngresses[key] {
+			changed = append(changed, key)
+		}
+	}
+	for key := range su.expiredCertificateIngresses {
+		if !keys[key] {
+			changed = append(changed, key)
+		}
+	}
+	su.expiredCertificateIngresses = keys
+	sort.Strings(changed)
+	return changed
+}
+
+// withExpiredCertificateError returns a copy of the status that reports the CertificateExpired error
+// for the HTTPS port of every address. The Ingress status has no conditions, so the port status
+// is the only place to flag an Ingress.
+func withExpiredCertificateError(status []networking.IngressLoadBalancerIngress) []networking.IngressLoadBalancerIngress {
+	if len(status) == 0 {
+		return status
+	}
+
+	reason := nl.EventReasonCertificateExpired
+	result := make([]networking.IngressLoadBalancerIngress, 0, len(status))
+	for _, s := range status {
+		s.Ports = []networking.IngressPortStatus{
+			{
+				Port:     443,
+				Protocol: api_v1.ProtocolTCP,
+				Error:    &reason,
+			},
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
 // BulkUpdateIngressStatus sets the status field on the selected Ingresses, specifically
 // the External IP field.
 func (su *statusUpdater) BulkUpdateIngressStatus(ings []networking.Ingress) error {
@@ -732,3 +781,56 @@ func (su *statusUpdater) retryUpdatePolicyStatus(polCopy *conf_v1.Policy) error
 
 	return nil
 }
+
+func hasRolloutStatusChanged(current conf_v1.RolloutStatus, status conf_v1.RolloutStatus) bool {
+	if current.StepStartTime == nil || status.StepStartTime == nil {
+		if current.StepStartTime != status.StepStartTime {
+			return true
+		}
+	} else if !current.StepStartTime.Equal(status.StepStartTime) {
+		return true
+	}
+	current.StepStartTime, status.StepStartTime = nil, nil
+	return current != status
+}
+
+// UpdateRolloutStatus updates the status of a Rollout.
+func (su *statusUpdater) UpdateRolloutStatus(ro *conf_v1.Rollout, status conf_v1.RolloutStatus) error {
+	// Get an up-to-date Rollout from the Store
+	roLatest, exists, err := su.getNamespacedInformer(ro.Namespace).rolloutLister.Get(ro)
+	if err != nil {
+		nl.Infof(su.logger, "error getting rollout from Store: %v", err)
+		return err
+	}
+	if !exists {
+		nl.Infof(su.logger, "Rollout doesn't exist in Store")
+		return nil
+	}
+
+	roCopy := roLatest.(*conf_v1.Rollout).DeepCopy()
+
+	if !hasRolloutStatusChanged(roCopy.Status, status) {
+		return nil
+	}
+
+	roCopy.Status = status
+
+	_, err = su.confClient.K8sV1().Rollouts(roCopy.Namespace).UpdateStatus(context.TODO(), roCopy, metav1.UpdateOptions{})
+	if err != nil {
+		nl.Infof(su.logger, "error setting Rollout %v/%v status, retrying: %v", roCopy.Namespace, roCopy.Name, err)
+		return su.retryUpdateRolloutStatus(roCopy)
+	}
+
+	return nil
+}
+
+func (su *statusUpdater) retryUpdateRolloutStatus(roCopy *conf_v1.Rollout) error {
+	ro, err := su.confClient.K8sV1().Rollouts(roCopy.Namespace).Get(context.TODO(), roCopy.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	ro.Status = roCopy.Status
+	_, err = su.confClient.K8sV1().Rollouts(ro.Namespace).UpdateStatus(context.TODO(), ro, metav1.UpdateOptions{})
+	return err
+}
@@ -436,6 +436,75 @@ func TestStatusUpdateWithExternalStatusAndIngressLink(t *testing.T) {
 	}
 }
 
+func TestUpdateIngressStatusWithExpiredCertificate(t *testing.T) {
+	t.Parallel()
+	ing := networking.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "ing-1",
+			Namespace: "namespace",
+		},
+	}
+	fakeClient := fake.NewSimpleClientset(
+		&networking.IngressList{Items: []networking.Ingress{
+			ing,
+		}},
+	)
+	ingLister := storeToIngressLister{}
+	ingLister.Store = cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
+	err := ingLister.Store.Add(&ing)
+	if err != nil {
+		t.Errorf("Error adding Ingress to the ingress lister: %v", err)
+	}
+
+	nsi := make(map[string]*namespacedInformer)
+	nsi[""] = &namespacedInformer{ingressLister: ingLister}
+
+	l := slog.New(nic_glog.New(io.Discard, &nic_glog.Options{Level: levels.LevelInfo}))
+	su := statusUpdater{
+		client:              fakeClient,
+		namespacedInformers: nsi,
+		keyFunc:             cache.DeletionHandlingMetaNamespaceKeyFunc,
+		logger:              l,
+	}
+	su.SaveStatusFromExternalStatus("1.1.1.1")
+
+	changed := su.SetExpiredCertificateIngresses(map[string]bool{"namespace/ing-1": true})
+	if diff := cmp.Diff([]string{"namespace/ing-1"}, changed); diff != "" {
+		t.Errorf("SetExpiredCertificateIngresses() returned unexpected result (-want +got):\n%s", diff)
+	}
+
+	err = su.UpdateIngressStatus(ing)
+	if err != nil {
+		t.Errorf("error updating ing status: %v", err)
+	}
+
+	reason := "CertificateExpired"
+	expected := []networking.IngressLoadBalancerIngress{
+		{
+			IP: "1.1.1.1",
+			Ports: []networking.IngressPortStatus{
+				{
+					Port:     443,
+					Protocol: v1.ProtocolTCP,
+					Error:    &reason,
+				},
+			},
+		},
+	}
+	ring, _ := fakeClient.NetworkingV1().Ingresses(ing.Namespace).Get(context.TODO(), ing.Name, meta_v1.GetOptions{})
+	if diff := cmp.Diff(expected, ring.Status.LoadBalancer.Ingress); diff != "" {
+		t.Errorf("UpdateIngressStatus() set unexpected status (-want +got):\n%s", diff)
+	}
+	if su.status[0].Ports != nil {
+		t.Errorf("UpdateIngressStatus() changed the saved status: %v", su.status)
+	}
+
+	changed = su.SetExpiredCertificateIngresses(map[string]bool{})
+	if diff := cmp.Diff([]string{"namespace/ing-1"}, changed); diff != "" {
+		t.Errorf("SetExpiredCertificateIngresses() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
+
 func checkStatus(expected string, actual networking.Ingress) bool {
 	if len(actual.Status.LoadBalancer.Ingress) == 0 {
 		return expected == ""
@@ -26,18 +26,22 @@ type taskQueue struct {
 	queue *workqueue.Type
 	// sync is called for each item in the queue
 	sync func(task)
+	// added is called for each item added or re-added to the queue
+	added func(task)
 	// workerDone is closed when the worker exits
 	workerDone chan struct{}
 	// logger
 	logger *slog.Logger
 }
 
-// newTaskQueue creates a new task queue with the given sync function.
+// newTaskQueue creates a new task queue with the given sync and added functions.
 // The sync function is called for every element inserted into the queue.
-func newTaskQueue(logger *slog.Logger, syncFn func(task)) *taskQueue {
+// The added function, if not nil, is called when an element is inserted into the queue.
+func newTaskQueue(logger *slog.Logger, syncFn func(task), addedFn func(task)) *taskQueue {
 	return &taskQueue{
 		queue:      workqueue.NewNamed("taskQueue"),
 		sync:       syncFn,
+		added:      addedFn,
 		workerDone: make(chan struct{}),
 		logger:     logger,
 	}
@@ -63,13 +67,27 @@ func (tq *taskQueue) Enqueue(obj interface{}) {
 	}
 
 	nl.Debugf(tq.logger, "Adding an element with a key: %v", task.Key)
-	tq.queue.Add(task)
+	tq.add(task)
+}
+
+// EnqueueTask enqueues the given task in the task queue.
+// It is used for tasks that are not created from an api object.
+func (tq *taskQueue) EnqueueTask(t task) {
+	nl.Debugf(tq.logger, "Adding an element with a key: %v", t.Key)
+	tq.add(t)
 }
 
 // Requeue adds the task to the queue again and logs the given error
 func (tq *taskQueue) Requeue(task task, err error) {
 	nl.Errorf(tq.logger, "Requeuing %v, err %v", task.Key, err)
-	tq.queue.Add(task)
+	tq.add(task)
+}
+
+func (tq *taskQueue) add(t task) {
+	if tq.added != nil {
+		tq.added(t)
+	}
+	tq.queue.Add(t)
 }
 
 // Len returns the length of the queue
@@ -81,6 +99,9 @@ func (tq *taskQueue) Len() int {
 // RequeueAfter adds the task to the queue after the given duration
 func (tq *taskQueue) RequeueAfter(t task, err error, after time.Duration) {
 	nl.Errorf(tq.logger, "Requeuing %v after %s, err %v", t.Key, after.String(), err)
+	if tq.added != nil {
+		tq.added(t)
+	}
 	go func(t task, after time.Duration) {
 		time.Sleep(after)
 		tq.queue.Add(t)
@@ -130,8 +151,60 @@ const (
 	appProtectDosLogConf
 	appProtectDosProtectedResource
 	ingressLink
+	certificateExpiry
+	rollout
+	referenceGrant
 )
 
+// String returns the name of the kind of the Kubernetes resources of a task.
+func (k kind) String() string {
+	switch k {
+	case ingress:
+		return ingressKind
+	case endpointslice:
+		return "EndpointSlice"
+	case configMap:
+		return "ConfigMap"
+	case secret:
+		return "Secret"
+	case service:
+		return "Service"
+	case namespace:
+		return "Namespace"
+	case virtualserver:
+		return virtualServerKind
+	case virtualServerRoute:
+		return virtualServerRouteKind
+	case globalConfiguration:
+		return "GlobalConfiguration"
+	case transportserver:
+		return transportServerKind
+	case policy:
+		return "Policy"
+	case appProtectPolicy:
+		return appprotect.PolicyGVK.Kind
+	case appProtectLogConf:
+		return appprotect.LogConfGVK.Kind
+	case appProtectUserSig:
+		return appprotect.UserSigGVK.Kind
+	case appProtectDosPolicy:
+		return appprotectdos.DosPolicyGVK.Kind
+	case appProtectDosLogConf:
+		return appprotectdos.DosLogConfGVK.Kind
+	case appProtectDosProtectedResource:
+		return "DosProtectedResource"
+	case ingressLink:
+		return ingressLinkGVK.Kind
+	case certificateExpiry:
+		return "CertificateExpiry"
+	case rollout:
+		return "Rollout"
+	case referenceGrant:
+		return "ReferenceGrant"
+	}
+	return "Unknown"
+}
+
 // task is an element of a taskQueue
 type task struct {
 	Kind kind
@@ -164,6 +237,10 @@ func newTask(key string, obj interface{}) (task, error) {
 		k = globalConfiguration
 	case *conf_v1.TransportServer:
 		k = transportserver
+	case *conf_v1.Rollout:
+		k = rollout
+	case *conf_v1.ReferenceGrant:
+		k = referenceGrant
 	case *v1beta1.DosProtectedResource:
 		k = appProtectDosProtectedResource
 	case *unstructured.Unstructured:
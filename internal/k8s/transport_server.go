@@ -152,6 +152,12 @@ func (lbc *LoadBalancerController) updateTransportServerStatusAndEvents(tsConfig
 		state = conf_v1.StateInvalid
 	}
 
+	if state == conf_v1.StateValid && tsConfig.TransportServer.Spec.Maintenance != nil && tsConfig.TransportServer.Spec.Maintenance.Enable {
+		eventTitle = nl.EventReasonAddedOrUpdatedInMaintenance
+		eventWarningMessage = "in maintenance mode"
+		state = conf_v1.StateMaintenance
+	}
+
 	msg := fmt.Sprintf("Configuration for %v was added or updated %s", getResourceKey(&tsConfig.TransportServer.ObjectMeta), eventWarningMessage)
 	lbc.recorder.Eventf(tsConfig.TransportServer, eventType, eventTitle, msg)
 
@@ -205,7 +211,7 @@ func (lbc *LoadBalancerController) updateTransportServersStatusFromEvents() erro
 func (lbc *LoadBalancerController) createTransportServerEx(transportServer *conf_v1.TransportServer, listenerPort int, ipv4 string, ipv6 string) *configs.TransportServerEx {
 	endpoints := make(map[string][]string)
 	externalNameSvcs := make(map[string]bool)
-	podsByIP := make(map[string]string)
+	podsByIP := make(map[string]configs.PodInfo)
 	disableIPV6 := lbc.configuration.isIPV6Disabled
 
 	for _, u := range transportServer.Spec.Upstreams {
@@ -223,9 +229,12 @@ func (lbc *LoadBalancerController) createTransportServerEx(transportServer *conf
 		endps := getIPAddressesFromEndpoints(podEndps)
 		endpoints[endpointsKey] = endps
 
-		if lbc.isNginxPlus && lbc.isPrometheusEnabled {
+		if (lbc.isNginxPlus && lbc.isPrometheusEnabled) || lbc.isLatencyMetricsEnabled {
 			for _, endpoint := range podEndps {
-				podsByIP[endpoint.Address] = endpoint.PodName
+				podsByIP[endpoint.Address] = configs.PodInfo{
+					Name:         endpoint.PodName,
+					MeshPodOwner: endpoint.MeshPodOwner,
+				}
 			}
 		}
 
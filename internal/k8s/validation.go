@@ -71,6 +71,7 @@ const (
 	stickyCookieServicesAnnotation        = "nginx.com/sticky-cookie-services"
 	pathRegexAnnotation                   = "nginx.org/path-regex"
 	useClusterIPAnnotation                = "nginx.org/use-cluster-ip"
+	policiesAnnotation                    = "nginx.org/policies"
 )
 
 const (
@@ -340,6 +341,10 @@ var (
 		useClusterIPAnnotation: {
 			validateBoolAnnotation,
 		},
+		policiesAnnotation: {
+			validateRequiredAnnotation,
+			validatePoliciesAnnotation,
+		},
 	}
 	annotationNames = sortedAnnotationNames(annotationValidations)
 )
@@ -719,6 +724,13 @@ func validatePortListAnnotation(context *annotationValidationContext) field.Erro
 	return nil
 }
 
+func validatePoliciesAnnotation(context *annotationValidationContext) field.ErrorList {
+	if _, err := configs.ParsePolicyReferences(context.value); err != nil {
+		return field.ErrorList{field.Invalid(context.fieldPath, context.value, "must be a comma-separated list of policies in the format name or namespace/name")}
+	}
+	return nil
+}
+
 func validateServiceListAnnotation(context *annotationValidationContext) field.ErrorList {
 	var unknownServices []string
 	annotationServices := configs.ParseServiceList(context.value)
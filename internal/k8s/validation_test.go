@@ -3176,6 +3176,46 @@ func TestValidateNginxIngressAnnotations(t *testing.T) {
 			expectedErrors:        nil,
 			msg:                   "valid nginx.org/use-cluster-ip annotation",
 		},
+		{
+			annotations: map[string]string{
+				"nginx.org/policies": "jwt-policy,policies/rate-limit-policy",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors:        nil,
+			msg:                   "valid nginx.org/policies annotation",
+		},
+		{
+			annotations: map[string]string{
+				"nginx.org/policies": "",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors: []string{
+				"annotations.nginx.org/policies: Required value",
+			},
+			msg: "invalid nginx.org/policies annotation, empty",
+		},
+		{
+			annotations: map[string]string{
+				"nginx.org/policies": "policies/jwt-policy/invalid",
+			},
+			specServices:          map[string]bool{},
+			isPlus:                false,
+			appProtectEnabled:     false,
+			appProtectDosEnabled:  false,
+			internalRoutesEnabled: false,
+			expectedErrors: []string{
+				`annotations.nginx.org/policies: Invalid value: "policies/jwt-policy/invalid": must be a comma-separated list of policies in the format name or namespace/name`,
+			},
+			msg: "invalid nginx.org/policies annotation",
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,106 @@
+package collectors
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CertificateExpiry describes the expiry date of a certificate stored in a Secret
+// and the host of the resource that references the Secret.
+type CertificateExpiry struct {
+	SecretNamespace   string
+	SecretName        string
+	Host              string
+	ResourceType      string
+	ResourceName      string
+	ResourceNamespace string
+	NotAfter          time.Time
+}
+
+// CertificateCollector is an interface for the metrics of certificates referenced by resources
+type CertificateCollector interface {
+	SetCertificates(certificates []CertificateExpiry)
+	Register(registry *prometheus.Registry) error
+}
+
+// CertificateMetricsCollector implements the CertificateCollector interface and prometheus.Collector interface
+type CertificateMetricsCollector struct {
+	secretNotAfter *prometheus.Desc
+	hostNotAfter   *prometheus.Desc
+	certificates   []CertificateExpiry
+	mutex          sync.RWMutex
+}
+
+// NewCertificateMetricsCollector creates a new CertificateMetricsCollector
+func NewCertificateMetricsCollector(constLabels map[string]string) *CertificateMetricsCollector {
+	return &CertificateMetricsCollector{
+		secretNotAfter: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "certificate_not_after_timestamp_seconds"),
+			"Expiry date of the certificate stored in a TLS or CA secret as a Unix timestamp",
+			[]string{"secret_namespace", "secret_name"},
+			constLabels,
+		),
+		hostNotAfter: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "host_certificate_not_after_timestamp_seconds"),
+			"Expiry date of the certificate served or used for a host of a resource as a Unix timestamp",
+			[]string{"host", "secret_namespace", "secret_name", "resource_type", "resource_name", "resource_namespace"},
+			constLabels,
+		),
+	}
+}
+
+// SetCertificates replaces the certificates published by the collector
+func (cc *CertificateMetricsCollector) SetCertificates(certificates []CertificateExpiry) {
+	cc.mutex.Lock()
+	cc.certificates = certificates
+	cc.mutex.Unlock()
+}
+
+// Describe implements prometheus.Collector interface Describe method
+func (cc *CertificateMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cc.secretNotAfter
+	ch <- cc.hostNotAfter
+}
+
+// Collect implements the prometheus.Collector interface Collect method
+func (cc *CertificateMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	cc.mutex.RLock()
+	defer cc.mutex.RUnlock()
+
+	secretsPublished := make(map[string]bool)
+	for _, c := range cc.certificates {
+		notAfter := float64(c.NotAfter.Unix())
+
+		secretKey := c.SecretNamespace + "/" + c.SecretName
+		if !secretsPublished[secretKey] {
+			secretsPublished[secretKey] = true
+			ch <- prometheus.MustNewConstMetric(cc.secretNotAfter, prometheus.GaugeValue, notAfter, c.SecretNamespace, c.SecretName)
+		}
+
+		if c.Host != "" {
+			ch <- prometheus.MustNewConstMetric(cc.hostNotAfter, prometheus.GaugeValue, notAfter,
+				c.Host, c.SecretNamespace, c.SecretName, c.ResourceType, c.ResourceName, c.ResourceNamespace)
+		}
+	}
+}
+
+// Register registers all the metrics of the collector
+func (cc *CertificateMetricsCollector) Register(registry *prometheus.Registry) error {
+	return registry.Register(cc)
+}
+
+// CertificateFakeCollector is a fake collector that implements the CertificateCollector interface
+type CertificateFakeCollector struct{}
+
+// NewCertificateFakeCollector creates a fake collector that implements the CertificateCollector interface
+func NewCertificateFakeCollector() *CertificateFakeCollector {
+	return &CertificateFakeCollector{}
+}
+
+// Register implements a fake Register
+func (cc *CertificateFakeCollector) Register(_ *prometheus.Registry) error { return nil }
+
+// SetCertificates implements a fake SetCertificates
+func (cc *CertificateFakeCollector) SetCertificates(_ []CertificateExpiry) {}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
 	"github.com/prometheus/client_golang/prometheus"
@@ -43,6 +44,8 @@ var latencyBucketsMilliSeconds = []float64{
 	50000,
 }
 
+var bytesBuckets = prometheus.ExponentialBuckets(64, 4, 10)
+
 // LatencyCollector is an interface for latency metrics
 type LatencyCollector interface {
 	RecordLatency(string)
@@ -52,6 +55,17 @@ type LatencyCollector interface {
 	DeleteUpstreamServerPeerLabels([]string)
 	DeleteMetrics([]string)
 	Register(*prometheus.Registry) error
+	GetUpstreamTotals(string) (UpstreamTotals, bool)
+}
+
+// UpstreamTotals holds the totals of the requests proxied to an HTTP upstream since the collector started.
+// Unlike the histograms, the totals are kept per upstream regardless of the labels, so that the difference
+// between two totals gives the requests made in between.
+type UpstreamTotals struct {
+	Requests     uint64
+	Responses5xx uint64
+	// ResponseTime is the sum of the response times of the requests.
+	ResponseTime time.Duration
 }
 
 // metricsPublishedMap is a map of upstream server peers (upstream/server) to a metricsSet.
@@ -68,12 +82,19 @@ type metricsSet map[string]struct{}
 // LatencyMetricsCollector implements the LatencyCollector interface and prometheus.Collector interface
 type LatencyMetricsCollector struct {
 	httpLatency                  *prometheus.HistogramVec
+	requests                     *prometheus.CounterVec
+	receivedBytes                *prometheus.HistogramVec
+	sentBytes                    *prometheus.HistogramVec
+	streamConnectTime            *prometheus.HistogramVec
+	streamSessionTime            *prometheus.HistogramVec
 	upstreamServerLabelNames     []string
 	upstreamServerPeerLabelNames []string
 	upstreamServerLabels         map[string][]string
 	upstreamServerPeerLabels     map[string][]string
 	metricsPublishedMap          metricsPublishedMap
 	metricsPublishedMutex        sync.Mutex
+	upstreamTotals               map[string]UpstreamTotals
+	upstreamTotalsMutex          sync.RWMutex
 	variableLabelsMutex          sync.RWMutex
 	logger                       *slog.Logger
 }
@@ -95,9 +116,54 @@ func NewLatencyMetricsCollector(
 		},
 			createLatencyLabelNames(upstreamServerLabelNames, upstreamServerPeerLabelNames),
 		),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   metricsNamespace,
+			Name:        "upstream_server_requests_total",
+			Help:        "Number of requests or stream sessions proxied to an upstream server, grouped by the class of the response status",
+			ConstLabels: constLabels,
+		},
+			createStatusClassLabelNames(upstreamServerLabelNames, upstreamServerPeerLabelNames),
+		),
+		receivedBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   metricsNamespace,
+			Name:        "upstream_server_received_bytes",
+			Help:        "Bucketed number of bytes received by NGINX from an upstream server per request or stream session",
+			ConstLabels: constLabels,
+			Buckets:     bytesBuckets,
+		},
+			createStatusClassLabelNames(upstreamServerLabelNames, upstreamServerPeerLabelNames),
+		),
+		sentBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   metricsNamespace,
+			Name:        "upstream_server_sent_bytes",
+			Help:        "Bucketed number of bytes sent by NGINX to an upstream server per request or stream session",
+			ConstLabels: constLabels,
+			Buckets:     bytesBuckets,
+		},
+			createStatusClassLabelNames(upstreamServerLabelNames, upstreamServerPeerLabelNames),
+		),
+		streamConnectTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   metricsNamespace,
+			Name:        "stream_upstream_server_connect_latency_ms",
+			Help:        "Bucketed times spent by NGINX establishing a connection to a TransportServer upstream server",
+			ConstLabels: constLabels,
+			Buckets:     latencyBucketsMilliSeconds,
+		},
+			createLatencyLabelNames(upstreamServerLabelNames, upstreamServerPeerLabelNames),
+		),
+		streamSessionTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   metricsNamespace,
+			Name:        "stream_upstream_server_session_latency_ms",
+			Help:        "Bucketed durations of TransportServer sessions proxied to an upstream server",
+			ConstLabels: constLabels,
+			Buckets:     latencyBucketsMilliSeconds,
+		},
+			createLatencyLabelNames(upstreamServerLabelNames, upstreamServerPeerLabelNames),
+		),
 		upstreamServerLabels:         make(map[string][]string),
 		upstreamServerPeerLabels:     make(map[string][]string),
 		metricsPublishedMap:          make(metricsPublishedMap),
+		upstreamTotals:               make(map[string]UpstreamTotals),
 		upstreamServerLabelNames:     upstreamServerLabelNames,
 		upstreamServerPeerLabelNames: upstreamServerPeerLabelNames,
 		logger:                       nl.LoggerFromContext(ctx),
@@ -138,6 +204,33 @@ func (l *LatencyMetricsCollector) DeleteUpstreamServerLabels(upstreamNames []str
 		delete(l.upstreamServerLabels, k)
 	}
 	l.variableLabelsMutex.Unlock()
+
+	l.upstreamTotalsMutex.Lock()
+	for _, k := range upstreamNames {
+		delete(l.upstreamTotals, k)
+	}
+	l.upstreamTotalsMutex.Unlock()
+}
+
+// GetUpstreamTotals returns the totals of the requests proxied to the upstream.
+// It returns false when no requests to the upstream were recorded.
+func (l *LatencyMetricsCollector) GetUpstreamTotals(upstreamName string) (UpstreamTotals, bool) {
+	l.upstreamTotalsMutex.RLock()
+	defer l.upstreamTotalsMutex.RUnlock()
+	totals, ok := l.upstreamTotals[upstreamName]
+	return totals, ok
+}
+
+func (l *LatencyMetricsCollector) updateUpstreamTotals(lm latencyMetric) {
+	l.upstreamTotalsMutex.Lock()
+	defer l.upstreamTotalsMutex.Unlock()
+	totals := l.upstreamTotals[lm.Upstream]
+	totals.Requests++
+	if statusClass(lm.Code) == "5xx" {
+		totals.Responses5xx++
+	}
+	totals.ResponseTime += time.Duration(lm.Latency * float64(time.Second))
+	l.upstreamTotals[lm.Upstream] = totals
 }
 
 // DeleteMetrics deletes all metrics published associated with the given upstream server peer names.
@@ -149,6 +242,16 @@ func (l *LatencyMetricsCollector) DeleteMetrics(upstreamServerPeerNames []string
 				nl.Warnf(l.logger, "could not delete metric for upstream server peer: %s with values: %v", name, labelValues)
 			}
 		}
+		upstream, server, found := strings.Cut(name, "/")
+		if !found {
+			continue
+		}
+		peerLabels := prometheus.Labels{"upstream": upstream, "server": server}
+		l.requests.DeletePartialMatch(peerLabels)
+		l.receivedBytes.DeletePartialMatch(peerLabels)
+		l.sentBytes.DeletePartialMatch(peerLabels)
+		l.streamConnectTime.DeletePartialMatch(peerLabels)
+		l.streamSessionTime.DeletePartialMatch(peerLabels)
 	}
 }
 
@@ -172,14 +275,25 @@ func (l *LatencyMetricsCollector) Register(registry *prometheus.Registry) error
 // Describe implements prometheus.Collector interface Describe method
 func (l *LatencyMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 	l.httpLatency.Describe(ch)
+	l.requests.Describe(ch)
+	l.receivedBytes.Describe(ch)
+	l.sentBytes.Describe(ch)
+	l.streamConnectTime.Describe(ch)
+	l.streamSessionTime.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface Collect method
 func (l *LatencyMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 	l.httpLatency.Collect(ch)
+	l.requests.Collect(ch)
+	l.receivedBytes.Collect(ch)
+	l.sentBytes.Collect(ch)
+	l.streamConnectTime.Collect(ch)
+	l.streamSessionTime.Collect(ch)
 }
 
-// RecordLatency parses a syslog message and records latency
+// RecordLatency parses a syslog message and records latency, request and bytes metrics
+// for HTTP upstreams and TransportServer (stream) upstreams.
 func (l *LatencyMetricsCollector) RecordLatency(syslogMsg string) {
 	lm, err := parseMessage(syslogMsg)
 	if err != nil {
@@ -192,6 +306,7 @@ func (l *LatencyMetricsCollector) RecordLatency(syslogMsg string) {
 	// Ref:
 	// https://github.com/nginx/kubernetes-ingress/issues/5010
 	// https://github.com/nginx/kubernetes-ingress/issues/6124
+	// Stream servers that are not generated for a TransportServer also log "-" as the upstream name.
 	if lm.Upstream == "-" {
 		nl.Debugf(l.logger, "latency metrics for gRPC upstreams: %v", lm)
 		return
@@ -202,8 +317,25 @@ func (l *LatencyMetricsCollector) RecordLatency(syslogMsg string) {
 		nl.Errorf(l.logger, "cannot record latency for upstream %s and server %s: %v", lm.Upstream, lm.Server, err)
 		return
 	}
-	l.httpLatency.WithLabelValues(labelValues...).Observe(lm.Latency * 1000)
-	l.updateMetricsPublished(lm.Upstream, lm.Server, labelValues)
+	if lm.Stream {
+		if lm.ConnectLatency != nil {
+			l.streamConnectTime.WithLabelValues(labelValues...).Observe(*lm.ConnectLatency * 1000)
+		}
+		l.streamSessionTime.WithLabelValues(labelValues...).Observe(lm.Latency * 1000)
+	} else {
+		l.httpLatency.WithLabelValues(labelValues...).Observe(lm.Latency * 1000)
+		l.updateMetricsPublished(lm.Upstream, lm.Server, labelValues)
+		l.updateUpstreamTotals(lm)
+	}
+
+	statusClassLabelValues := createStatusClassLabelValues(labelValues)
+	l.requests.WithLabelValues(statusClassLabelValues...).Inc()
+	if lm.BytesReceived != nil {
+		l.receivedBytes.WithLabelValues(statusClassLabelValues...).Observe(*lm.BytesReceived)
+	}
+	if lm.BytesSent != nil {
+		l.sentBytes.WithLabelValues(statusClassLabelValues...).Observe(*lm.BytesSent)
+	}
 }
 
 func (l *LatencyMetricsCollector) updateMetricsPublished(upstreamName, server string, labelValues []string) {
@@ -247,18 +379,46 @@ func createLatencyLabelNames(upstreamServerLabelNames, upstreamServerPeerLabelNa
 	return append(append([]string{"upstream", "server", "code"}, upstreamServerLabelNames...), upstreamServerPeerLabelNames...)
 }
 
+func createStatusClassLabelNames(upstreamServerLabelNames, upstreamServerPeerLabelNames []string) []string {
+	return append(append([]string{"upstream", "server", "code_class"}, upstreamServerLabelNames...), upstreamServerPeerLabelNames...)
+}
+
+// createStatusClassLabelValues converts the label values of a latency metric into the label values
+// of a status class metric by replacing the status code with its class, for example 404 with 4xx.
+func createStatusClassLabelValues(latencyLabelValues []string) []string {
+	labelValues := make([]string, len(latencyLabelValues))
+	copy(labelValues, latencyLabelValues)
+	labelValues[2] = statusClass(labelValues[2])
+	return labelValues
+}
+
+func statusClass(code string) string {
+	if len(code) != 3 || code[0] < '1' || code[0] > '5' {
+		return "unknown"
+	}
+	return code[:1] + "xx"
+}
+
 type syslogMsg struct {
-	ProxyHost            string `json:"proxyHost"`
-	UpstreamAddr         string `json:"upstreamAddress"`
-	UpstreamStatus       string `json:"upstreamStatus"`
-	UpstreamResponseTime string `json:"upstreamResponseTime"`
+	ProxyHost             string `json:"proxyHost"`
+	UpstreamAddr          string `json:"upstreamAddress"`
+	UpstreamStatus        string `json:"upstreamStatus"`
+	UpstreamResponseTime  string `json:"upstreamResponseTime"`
+	UpstreamConnectTime   string `json:"upstreamConnectTime"`
+	SessionTime           string `json:"sessionTime"`
+	UpstreamBytesSent     string `json:"upstreamBytesSent"`
+	UpstreamBytesReceived string `json:"upstreamBytesReceived"`
 }
 
 type latencyMetric struct {
-	Upstream string
-	Server   string
-	Code     string
-	Latency  float64
+	Upstream       string
+	Server         string
+	Code           string
+	Latency        float64
+	Stream         bool
+	ConnectLatency *float64
+	BytesSent      *float64
+	BytesReceived  *float64
 }
 
 func parseMessage(msg string) (latencyMetric, error) {
@@ -276,21 +436,59 @@ func parseMessage(msg string) (latencyMetric, error) {
 		return latencyMetric{}, fmt.Errorf("nginx could not connect to upstream")
 	}
 	server := parseMultipartResponse(sm.UpstreamAddr)
+	code := parseMultipartResponse(sm.UpstreamStatus)
+
+	// stream (TransportServer) messages carry the session time instead of the upstream response time
+	if sm.SessionTime != "" {
+		if sm.UpstreamAddr == "" {
+			// the session was finished before connecting to an upstream
+			return latencyMetric{}, fmt.Errorf("nginx could not connect to stream upstream")
+		}
+		latency, err := strconv.ParseFloat(sm.SessionTime, 64)
+		if err != nil {
+			return latencyMetric{}, fmt.Errorf("could not parse float from session time %s: %w", sm.SessionTime, err)
+		}
+		return latencyMetric{
+			Upstream:       sm.ProxyHost,
+			Server:         server,
+			Code:           code,
+			Latency:        latency,
+			Stream:         true,
+			ConnectLatency: parseOptionalFloat(sm.UpstreamConnectTime),
+			BytesSent:      parseOptionalFloat(sm.UpstreamBytesSent),
+			BytesReceived:  parseOptionalFloat(sm.UpstreamBytesReceived),
+		}, nil
+	}
+
 	latency, err := strconv.ParseFloat(parseMultipartResponse(sm.UpstreamResponseTime), 64)
 	if err != nil {
 		return latencyMetric{}, fmt.Errorf("could not parse float from upstream response time %s: %w", sm.UpstreamResponseTime, err)
 	}
-	code := parseMultipartResponse(sm.UpstreamStatus)
 	lm := latencyMetric{
-		Upstream: sm.ProxyHost,
-		Server:   server,
-		Code:     code,
-		Latency:  latency,
+		Upstream:      sm.ProxyHost,
+		Server:        server,
+		Code:          code,
+		Latency:       latency,
+		BytesSent:     parseOptionalFloat(sm.UpstreamBytesSent),
+		BytesReceived: parseOptionalFloat(sm.UpstreamBytesReceived),
 	}
 
 	return lm, nil
 }
 
+// parseOptionalFloat parses the last item of a multipart NGINX variable value.
+// It returns nil if the value is empty or not a number, for example "-" when NGINX could not connect to the upstream.
+func parseOptionalFloat(input string) *float64 {
+	if input == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(parseMultipartResponse(input), 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
 // parseMultipartResponse checks if the input string contains commas.
 // If it does it returns the last item of the list, otherwise it returns input.
 func parseMultipartResponse(input string) string {
@@ -329,3 +527,8 @@ func (l *LatencyFakeCollector) Register(_ *prometheus.Registry) error { return n
 
 // RecordLatency implements a fake RecordLatency
 func (l *LatencyFakeCollector) RecordLatency(_ string) {}
+
+// GetUpstreamTotals implements a fake GetUpstreamTotals
+func (l *LatencyFakeCollector) GetUpstreamTotals(_ string) (UpstreamTotals, bool) {
+	return UpstreamTotals{}, false
+}
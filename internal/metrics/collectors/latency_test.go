@@ -3,6 +3,7 @@ package collectors
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func newTestLatencyMetricsCollector() *LatencyMetricsCollector {
@@ -10,6 +11,7 @@ func newTestLatencyMetricsCollector() *LatencyMetricsCollector {
 		upstreamServerLabels:         make(map[string][]string),
 		upstreamServerPeerLabels:     make(map[string][]string),
 		metricsPublishedMap:          make(metricsPublishedMap),
+		upstreamTotals:               make(map[string]UpstreamTotals),
 		upstreamServerLabelNames:     []string{"service", "resource_type", "resource_name", "resource_namespace"},
 		upstreamServerPeerLabelNames: []string{"pod_name"},
 	}
@@ -80,6 +82,108 @@ func TestParseMessageWithValidInputs(t *testing.T) {
 	}
 }
 
+func TestParseMessageWithStreamAndBytesInputs(t *testing.T) {
+	t.Parallel()
+	connectTime := 0.002
+	bytesSent := 120.0
+	bytesReceived := 2048.0
+	tests := []struct {
+		msg         string
+		expectedErr bool
+		expected    latencyMetric
+	}{
+		{
+			msg:         `nginx: {"upstreamAddress":"10.0.0.1:5353", "upstreamConnectTime":"0.002", "sessionTime":"1.500", "proxyHost":"ts_default_dns_dns-app", "upstreamStatus": "200", "upstreamBytesSent":"120", "upstreamBytesReceived":"2048"}`,
+			expectedErr: false,
+			expected: latencyMetric{
+				Upstream:       "ts_default_dns_dns-app",
+				Server:         "10.0.0.1:5353",
+				Code:           "200",
+				Latency:        1.5,
+				Stream:         true,
+				ConnectLatency: &connectTime,
+				BytesSent:      &bytesSent,
+				BytesReceived:  &bytesReceived,
+			},
+		},
+		{
+			msg:         `nginx: {"upstreamAddress":"10.0.0.1:5353", "upstreamConnectTime":"-", "sessionTime":"0.010", "proxyHost":"ts_default_dns_dns-app", "upstreamStatus": "502", "upstreamBytesSent":"0", "upstreamBytesReceived":"0"}`,
+			expectedErr: false,
+			expected: latencyMetric{
+				Upstream:      "ts_default_dns_dns-app",
+				Server:        "10.0.0.1:5353",
+				Code:          "502",
+				Latency:       0.01,
+				Stream:        true,
+				BytesSent:     new(float64),
+				BytesReceived: new(float64),
+			},
+		},
+		{
+			msg:         `nginx: {"upstreamAddress":"10.0.0.1", "upstreamResponseTime":"0.003", "proxyHost":"upstream-1", "upstreamStatus": "200", "upstreamBytesSent":"120", "upstreamBytesReceived":"2048"}`,
+			expectedErr: false,
+			expected: latencyMetric{
+				Upstream:      "upstream-1",
+				Server:        "10.0.0.1",
+				Code:          "200",
+				Latency:       0.003,
+				BytesSent:     &bytesSent,
+				BytesReceived: &bytesReceived,
+			},
+		},
+		{
+			msg:         `nginx: {"upstreamAddress":"", "upstreamConnectTime":"", "sessionTime":"0.000", "proxyHost":"ts_default_dns_dns-app", "upstreamStatus": "500"}`,
+			expectedErr: true,
+		},
+		{
+			msg:         `nginx: {"upstreamAddress":"10.0.0.1:5353", "upstreamConnectTime":"0.002", "sessionTime":"not-a-float", "proxyHost":"ts_default_dns_dns-app", "upstreamStatus": "200"}`,
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		actual, err := parseMessage(test.msg)
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("parseMessage(%q) should return an error, got nil", test.msg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("parseMessage returned an unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("parseMessage returned: %+v, expected: %+v", actual, test.expected)
+		}
+	}
+}
+
+func TestCreateStatusClassLabelValues(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		labelValues []string
+		expected    []string
+	}{
+		{
+			labelValues: []string{"upstream-1", "10.0.0.1", "200", "service-1", "pod-1"},
+			expected:    []string{"upstream-1", "10.0.0.1", "2xx", "service-1", "pod-1"},
+		},
+		{
+			labelValues: []string{"upstream-1", "10.0.0.1", "504"},
+			expected:    []string{"upstream-1", "10.0.0.1", "5xx"},
+		},
+		{
+			labelValues: []string{"upstream-1", "10.0.0.1", "-"},
+			expected:    []string{"upstream-1", "10.0.0.1", "unknown"},
+		},
+	}
+	for _, test := range tests {
+		actual := createStatusClassLabelValues(test.labelValues)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("createStatusClassLabelValues(%v) returned: %v, expected: %v", test.labelValues, actual, test.expected)
+		}
+	}
+}
+
 func TestCreateLatencyLabelNames(t *testing.T) {
 	t.Parallel()
 	expected := []string{"upstream", "server", "code", "one", "two", "three", "four", "five"}
@@ -240,3 +344,24 @@ func contains(x []string, y [][]string) bool {
 	}
 	return false
 }
+
+func TestUpstreamTotals(t *testing.T) {
+	t.Parallel()
+	collector := newTestLatencyMetricsCollector()
+	collector.updateUpstreamTotals(latencyMetric{Upstream: "upstream-1", Server: "10.0.0.1:80", Code: "200", Latency: 0.1})
+	collector.updateUpstreamTotals(latencyMetric{Upstream: "upstream-1", Server: "10.0.0.2:80", Code: "503", Latency: 0.3})
+	collector.updateUpstreamTotals(latencyMetric{Upstream: "upstream-2", Server: "10.0.0.3:80", Code: "200", Latency: 1})
+
+	expected := UpstreamTotals{Requests: 2, Responses5xx: 1, ResponseTime: 400 * time.Millisecond}
+	if totals, ok := collector.GetUpstreamTotals("upstream-1"); !ok || totals != expected {
+		t.Errorf("GetUpstreamTotals() returned %+v, %v, expected %+v, true", totals, ok, expected)
+	}
+
+	collector.DeleteUpstreamServerLabels([]string{"upstream-1"})
+	if totals, ok := collector.GetUpstreamTotals("upstream-1"); ok {
+		t.Errorf("GetUpstreamTotals() returned %+v for a deleted upstream, expected no totals", totals)
+	}
+	if _, ok := collector.GetUpstreamTotals("upstream-2"); !ok {
+		t.Error("GetUpstreamTotals() returned no totals for upstream-2")
+	}
+}
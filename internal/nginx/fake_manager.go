@@ -111,6 +111,12 @@ func (fm *FakeManager) Version() Version {
 	return NewVersion("nginx version: nginx/1.25.3 (nginx-plus-r31)")
 }
 
+// Modules provides a fake implementation of Modules.
+func (fm *FakeManager) Modules() Modules {
+	nl.Debug(fm.logger, "Printing nginx modules")
+	return Modules{}
+}
+
 // Start provides a fake implementation of Start.
 func (fm *FakeManager) Start(_ chan error) {
 	nl.Debug(fm.logger, "Starting nginx")
@@ -204,6 +210,12 @@ func (fm *FakeManager) UpsertSplitClientsKeyVal(_ string, _ string, _ string) {
 	nl.Debugf(fm.logger, "Creating split clients key")
 }
 
+// GetUpstreamStats is a fake implementation of GetUpstreamStats
+func (fm *FakeManager) GetUpstreamStats(upstream string) (UpstreamStats, error) {
+	nl.Debugf(fm.logger, "Getting stats of upstream %v", upstream)
+	return UpstreamStats{}, nil
+}
+
 // DeleteKeyValStateFiles is a fake implementation of DeleteKeyValStateFiles
 func (fm *FakeManager) DeleteKeyValStateFiles(_ string) {
 	nl.Debugf(fm.logger, "Deleting keyval state files")
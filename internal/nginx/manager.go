@@ -37,6 +37,7 @@ const (
 	jsonFileForOpenTracingTracer = "/var/lib/nginx/tracer-config.json"
 	nginxBinaryPath              = "/usr/sbin/nginx"
 	nginxBinaryPathDebug         = "/usr/sbin/nginx-debug"
+	nginxModulesPath             = "/etc/nginx/modules"
 
 	appProtectPluginStartCmd = "/usr/share/ts/bin/bd-socket-plugin"
 	appProtectLogLevelCmd    = "/opt/app_protect/bin/set_log_level"
@@ -64,6 +65,18 @@ type ServerConfig struct {
 	SlowStart   string
 }
 
+// UpstreamStats holds the statistics of the peers of an upstream in NGINX Plus.
+type UpstreamStats struct {
+	// Requests is the total number of requests.
+	Requests uint64
+	// Responses5xx is the total number of responses with the 5xx status codes.
+	Responses5xx uint64
+	// TotalResponseTime is the sum of the response times of the requests. Its difference between two stats
+	// gives the response time of the requests made in between. It is only reported by the latency collector,
+	// because the NGINX Plus API reports a moving average of the response times.
+	TotalResponseTime time.Duration
+}
+
 // The Manager interface updates NGINX configuration, starts, reloads and quits NGINX,
 // updates NGINX Plus upstream servers.
 type Manager interface {
@@ -83,6 +96,7 @@ type Manager interface {
 	CreateOpenTracingTracerConfig(content string) error
 	Start(done chan error)
 	Version() Version
+	Modules() Modules
 	Reload(isEndpointsUpdate bool) error
 	Quit()
 	UpdateConfigVersionFile(openTracing bool)
@@ -100,6 +114,7 @@ type Manager interface {
 	GetSecretsDir() string
 	UpsertSplitClientsKeyVal(zoneName string, key string, value string)
 	DeleteKeyValStateFiles(virtualServerName string)
+	GetUpstreamStats(upstream string) (UpstreamStats, error)
 }
 
 // LocalManager updates NGINX configuration, starts, reloads and quits NGINX, updates License Reporting file
@@ -385,6 +400,26 @@ func (lm *LocalManager) Version() Version {
 	return NewVersion(string(out))
 }
 
+// Modules returns the optional modules available in NGINX.
+func (lm *LocalManager) Modules() Modules {
+	binaryFilename := getBinaryFileName(lm.debug)
+	out, err := exec.Command(binaryFilename, "-V").CombinedOutput() //nolint:gosec // G204: Subprocess launched with variable - false positive, variable resolves to a const
+	if err != nil {
+		nl.Fatalf(lm.logger, "Failed to get nginx modules: %v", err)
+	}
+
+	var moduleFiles []string
+	entries, err := os.ReadDir(nginxModulesPath)
+	if err != nil && !os.IsNotExist(err) {
+		nl.Warnf(lm.logger, "Failed to read the nginx modules directory %v: %v", nginxModulesPath, err)
+	}
+	for _, e := range entries {
+		moduleFiles = append(moduleFiles, e.Name())
+	}
+
+	return NewModules(string(out), moduleFiles)
+}
+
 // UpdateConfigVersionFile writes the config version file.
 func (lm *LocalManager) UpdateConfigVersionFile(openTracing bool) {
 	cfg, err := lm.verifyConfigGenerator.GenerateVersionConfig(lm.configVersion, openTracing)
@@ -723,6 +758,31 @@ func (lm *LocalManager) tryModifyKeyValPair(zoneName, key, value string) {
 	}
 }
 
+// GetUpstreamStats returns the statistics of the peers of the upstream from the NGINX Plus API.
+// The stats don't include the response time.
+func (lm *LocalManager) GetUpstreamStats(upstream string) (UpstreamStats, error) {
+	upstreams, err := lm.plusClient.GetUpstreams(context.Background())
+	if err != nil {
+		return UpstreamStats{}, err
+	}
+
+	u, ok := (*upstreams)[upstream]
+	if !ok {
+		return UpstreamStats{}, fmt.Errorf("upstream %s not found", upstream)
+	}
+
+	return newUpstreamStats(u.Peers), nil
+}
+
+func newUpstreamStats(peers []client.Peer) UpstreamStats {
+	var stats UpstreamStats
+	for _, p := range peers {
+		stats.Requests += p.Requests
+		stats.Responses5xx += p.Responses.Responses5xx
+	}
+	return stats
+}
+
 // DeleteKeyValStateFiles deletes the state files in the /etc/nginx/state_files folder for the given virtual server.
 func (lm *LocalManager) DeleteKeyValStateFiles(virtualServerName string) {
 	files, err := os.ReadDir(lm.stateFilesPath)
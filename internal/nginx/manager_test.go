@@ -106,3 +106,28 @@ func TestFormatUpdateServersInPlusLog(t *testing.T) {
 		})
 	}
 }
+
+func TestNewUpstreamStats(t *testing.T) {
+	t.Parallel()
+	peers := []client.Peer{
+		{
+			Requests:     300,
+			Responses:    client.Responses{Responses5xx: 3},
+			ResponseTime: 100,
+		},
+		{
+			Requests:     100,
+			Responses:    client.Responses{Responses5xx: 1},
+			ResponseTime: 500,
+		},
+	}
+
+	expected := UpstreamStats{Requests: 400, Responses5xx: 4}
+	if stats := newUpstreamStats(peers); stats != expected {
+		t.Errorf("newUpstreamStats() returned %+v, expected %+v", stats, expected)
+	}
+
+	if stats := newUpstreamStats(nil); stats != (UpstreamStats{}) {
+		t.Errorf("newUpstreamStats(nil) returned %+v, expected empty stats", stats)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 // Version holds the parsed output from `nginx -v`.
@@ -99,3 +100,76 @@ func extractPlusVersionValues(input string) (int, int, error) {
 
 	return rValue, pValue, nil
 }
+
+// Optional dynamic modules for the compression of responses.
+const (
+	brotliFilterModule = "ngx_http_brotli_filter_module.so"
+	brotliStaticModule = "ngx_http_brotli_static_module.so"
+	zstdFilterModule   = "ngx_http_zstd_filter_module.so"
+	zstdStaticModule   = "ngx_http_zstd_static_module.so"
+)
+
+// geoIP2Module is the optional dynamic module for the lookup of the client IPs in MaxMind databases.
+const geoIP2Module = "ngx_http_geoip2_module.so"
+
+var reAddModule = regexp.MustCompile(`--add-module=(\S+)`)
+
+// Modules holds the optional modules available in the NGINX build.
+type Modules struct {
+	Brotli bool
+	Zstd   bool
+	// BrotliStatic and ZstdStatic report the modules that send precompressed files.
+	BrotliStatic bool
+	ZstdStatic   bool
+	GeoIP2       bool
+	// LoadModules are the file names of the dynamic modules that must be loaded with the load_module directive.
+	LoadModules []string
+}
+
+// NewModules detects the optional modules from the output of `nginx -V` and the file names of the dynamic modules
+// in the modules directory. The modules compiled into the binary are listed in the configure arguments of the output.
+func NewModules(output string, moduleFiles []string) Modules {
+	var m Modules
+
+	for _, match := range reAddModule.FindAllStringSubmatch(output, -1) {
+		module := strings.ToLower(match[1])
+		switch {
+		case strings.Contains(module, "brotli"):
+			// the brotli and zstd modules build the filter and the static module together
+			m.Brotli = true
+			m.BrotliStatic = true
+		case strings.Contains(module, "zstd"):
+			m.Zstd = true
+			m.ZstdStatic = true
+		case strings.Contains(module, "geoip2"):
+			m.GeoIP2 = true
+		}
+	}
+
+	files := make(map[string]bool)
+	for _, f := range moduleFiles {
+		files[f] = true
+	}
+	if !m.Brotli && files[brotliFilterModule] {
+		m.Brotli = true
+		m.LoadModules = append(m.LoadModules, brotliFilterModule)
+		if files[brotliStaticModule] {
+			m.BrotliStatic = true
+			m.LoadModules = append(m.LoadModules, brotliStaticModule)
+		}
+	}
+	if !m.Zstd && files[zstdFilterModule] {
+		m.Zstd = true
+		m.LoadModules = append(m.LoadModules, zstdFilterModule)
+		if files[zstdStaticModule] {
+			m.ZstdStatic = true
+			m.LoadModules = append(m.LoadModules, zstdStaticModule)
+		}
+	}
+	if !m.GeoIP2 && files[geoIP2Module] {
+		m.GeoIP2 = true
+		m.LoadModules = append(m.LoadModules, geoIP2Module)
+	}
+
+	return m
+}
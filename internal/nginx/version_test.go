@@ -197,3 +197,52 @@ func TestNginxNewVersionHandlesRawString(t *testing.T) {
 		})
 	}
 }
+
+func TestNewModules(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name        string
+		output      string
+		moduleFiles []string
+		want        nginx.Modules
+	}{
+		{
+			name:   "no optional modules",
+			output: "nginx version: nginx/1.27.2\nconfigure arguments: --prefix=/etc/nginx --with-http_gzip_static_module",
+			want:   nginx.Modules{},
+		},
+		{
+			name:   "modules compiled into the binary",
+			output: "nginx version: nginx/1.27.2\nconfigure arguments: --prefix=/etc/nginx --add-module=/src/ngx_brotli --add-module=/src/zstd-nginx-module --add-module=/src/ngx_http_geoip2_module",
+			want:   nginx.Modules{Brotli: true, Zstd: true, BrotliStatic: true, ZstdStatic: true, GeoIP2: true},
+		},
+		{
+			name:        "dynamic modules",
+			output:      "nginx version: nginx/1.27.2\nconfigure arguments: --prefix=/etc/nginx",
+			moduleFiles: []string{"ngx_http_js_module.so", "ngx_http_brotli_filter_module.so", "ngx_http_brotli_static_module.so", "ngx_http_zstd_filter_module.so", "ngx_http_geoip2_module.so"},
+			want: nginx.Modules{
+				Brotli:       true,
+				Zstd:         true,
+				BrotliStatic: true,
+				GeoIP2:       true,
+				LoadModules:  []string{"ngx_http_brotli_filter_module.so", "ngx_http_brotli_static_module.so", "ngx_http_zstd_filter_module.so", "ngx_http_geoip2_module.so"},
+			},
+		},
+		{
+			name:        "module compiled into the binary is not loaded",
+			output:      "nginx version: nginx/1.27.2\nconfigure arguments: --add-module=/src/ngx_brotli",
+			moduleFiles: []string{"ngx_http_brotli_filter_module.so"},
+			want:        nginx.Modules{Brotli: true, BrotliStatic: true},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := nginx.NewModules(tc.output, tc.moduleFiles)
+			if !cmp.Equal(tc.want, got) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
@@ -11,6 +11,8 @@ const (
 	StateValid = "Valid"
 	// StateInvalid is used when the resource failed validation or NGINX failed to reload the corresponding config.
 	StateInvalid = "Invalid"
+	// StateMaintenance is used when the resource has been validated and accepted and its maintenance mode is enabled.
+	StateMaintenance = "Maintenance"
 	// HTTPProtocol defines a constant for the HTTP protocol in GlobalConfinguration.
 	HTTPProtocol = "HTTP"
 	// TLSPassthroughListenerName is the name of a built-in TLS Passthrough listener.
@@ -47,6 +49,7 @@ type VirtualServerSpec struct {
 	Listener       *VirtualServerListener `json:"listener"`
 	TLS            *TLS                   `json:"tls"`
 	Gunzip         bool                   `json:"gunzip"`
+	Compression    *Compression           `json:"compression"`
 	Policies       []PolicyReference      `json:"policies"`
 	Upstreams      []Upstream             `json:"upstreams"`
 	Routes         []Route                `json:"routes"`
@@ -56,6 +59,32 @@ type VirtualServerSpec struct {
 	ExternalDNS    ExternalDNS            `json:"externalDNS"`
 	// InternalRoute allows for the configuration of internal routing.
 	InternalRoute bool `json:"internalRoute"`
+	// Maintenance configures the maintenance mode of the VirtualServer.
+	Maintenance *Maintenance `json:"maintenance"`
+}
+
+// Maintenance defines the maintenance mode of a VirtualServer.
+// When the maintenance mode is enabled, NGINX responds to the requests with the 503 status code
+// without removing the upstreams.
+type Maintenance struct {
+	// Enable enables the maintenance mode.
+	Enable bool `json:"enable"`
+	// AllowList is the list of IP addresses and CIDRs of the clients whose requests are still passed to the upstreams.
+	AllowList []string `json:"allowList"`
+	// Header is a request header with a token that lets the request pass to the upstreams.
+	Header *MaintenanceHeader `json:"header"`
+	// Body is the body of the response. The default is "Service is under maintenance".
+	Body string `json:"body"`
+	// Type is the MIME type of the response. The default is "text/plain".
+	Type string `json:"type"`
+	// RetryAfter is the value of the Retry-After header of the response, for example, 1h.
+	RetryAfter string `json:"retryAfter"`
+}
+
+// MaintenanceHeader defines a request header with a token that bypasses the maintenance mode.
+type MaintenanceHeader struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
 }
 
 // VirtualServerListener references a custom http and/or https listener defined in GlobalConfiguration.
@@ -127,6 +156,9 @@ type Upstream struct {
 	Type                     string            `json:"type"`
 	Backup                   string            `json:"backup"`
 	BackupPort               *uint16           `json:"backupPort"`
+	// ServiceNamespace is the namespace of the service and the backup service. The default is the namespace of the resource.
+	// A service in another namespace must be allowed by a ReferenceGrant.
+	ServiceNamespace string `json:"serviceNamespace"`
 }
 
 // UpstreamBuffers defines Buffer Configuration for an Upstream.
@@ -191,6 +223,52 @@ type Route struct {
 	ErrorPages       []ErrorPage       `json:"errorPages"`
 	LocationSnippets string            `json:"location-snippets"`
 	Dos              string            `json:"dos"`
+	Compression      *Compression      `json:"compression"`
+	Canary           *Canary           `json:"canary"`
+}
+
+// Canary defines the canary release of a route. The clients are split between the action of the route
+// and the action of the canary.
+type Canary struct {
+	// Weight is the percentage of the clients sent to the canary, from 0 to 100.
+	Weight int `json:"weight"`
+	// Action is the action for the requests sent to the canary.
+	Action *Action `json:"action"`
+	// ForceHeader is the name of a request header that overrides the weight. The value always sends the request
+	// to the canary and the value never sends it to the action of the route.
+	ForceHeader string `json:"forceHeader"`
+	// ForceCookie is the name of a cookie that overrides the weight the same way as the forceHeader.
+	// The forceHeader takes precedence.
+	ForceCookie string `json:"forceCookie"`
+	// StickyCookie is the cookie set on the first response to remember which side a client was sent to.
+	StickyCookie *CanaryStickyCookie `json:"stickyCookie"`
+}
+
+// CanaryStickyCookie defines the cookie that remembers which side of a canary release a client was sent to.
+type CanaryStickyCookie struct {
+	// Name is the name of the cookie.
+	Name string `json:"name"`
+	// Path is the path of the cookie. The default is /.
+	Path string `json:"path"`
+	// Expires is the time after which the cookie expires, for example 1h. The cookie is a session cookie by default.
+	Expires string `json:"expires"`
+}
+
+// Compression defines the compression of responses.
+type Compression struct {
+	// Enable enables the compression of responses. Set to false in a route to disable the compression enabled for the VirtualServer.
+	Enable bool `json:"enable"`
+	// Encodings lists the encodings of the compressed responses: gzip, br and zstd. The br and zstd encodings require
+	// the brotli and zstd modules in NGINX. The default is gzip.
+	Encodings []string `json:"encodings"`
+	// Types lists the MIME types of the responses to compress in addition to text/html.
+	Types []string `json:"types"`
+	// MinLength is the minimum length in bytes of the responses to compress.
+	MinLength *int `json:"minLength"`
+	// Level is the compression level: from 1 to 9 for gzip, from 0 to 11 for br and from 1 to 22 for zstd.
+	Level *int `json:"level"`
+	// Static enables sending precompressed files with the .gz, .br or .zst extension instead of compressing the responses.
+	Static bool `json:"static"`
 }
 
 // Action defines an action.
@@ -199,6 +277,36 @@ type Action struct {
 	Redirect *ActionRedirect `json:"redirect"`
 	Return   *ActionReturn   `json:"return"`
 	Proxy    *ActionProxy    `json:"proxy"`
+	// Static serves files from a directory.
+	Static *ActionStatic `json:"static"`
+	// ObjectStorage proxies requests to a bucket of an S3-compatible object storage.
+	ObjectStorage *ActionObjectStorage `json:"objectStorage"`
+}
+
+// ActionStatic defines the serving of files in an Action.
+type ActionStatic struct {
+	// Path is the directory of the files relative to /etc/nginx/static, for example, a mounted ConfigMap.
+	Path string `json:"path"`
+	// Index lists the files to serve for the requests that end with a slash. The default is index.html.
+	Index []string `json:"index"`
+	// TryFiles lists the files to check in order. The last item is the URI or the code to use when no file exists.
+	TryFiles []string `json:"tryFiles"`
+	// Expires sets the Expires and Cache-Control headers of the responses, for example, 1h.
+	Expires string `json:"expires"`
+}
+
+// ActionObjectStorage defines the proxying of requests to an S3-compatible object storage in an Action.
+type ActionObjectStorage struct {
+	// Upstream is the name of the upstream of the object storage.
+	Upstream string `json:"upstream"`
+	// Host is the host of the object storage. The default is the host of the service of the upstream.
+	Host string `json:"host"`
+	// Bucket is the name of the bucket.
+	Bucket string `json:"bucket"`
+	// Region is the region of the bucket. The default is us-east-1.
+	Region string `json:"region"`
+	// Secret is the name of the Secret with the access keys for signing the requests.
+	Secret string `json:"secret"`
 }
 
 // ActionRedirect defines a redirect in an Action.
@@ -221,6 +329,38 @@ type ActionProxy struct {
 	RewritePath     string                `json:"rewritePath"`
 	RequestHeaders  *ProxyRequestHeaders  `json:"requestHeaders"`
 	ResponseHeaders *ProxyResponseHeaders `json:"responseHeaders"`
+	// ResponseBodyRewrite lists the rewrites of the bodies of the responses.
+	ResponseBodyRewrite []ResponseBodyRewrite `json:"responseBodyRewrite"`
+	// RewriteArgs defines the changes of the arguments of the requests passed to the upstream.
+	RewriteArgs *RewriteArgs `json:"rewriteArgs"`
+}
+
+// RewriteArgs defines the changes of the arguments of the requests in an ActionProxy.
+type RewriteArgs struct {
+	// Set lists the arguments to add or to replace. Every occurrence of an argument in the request is replaced.
+	Set []Argument `json:"set"`
+	// Remove lists the names of the arguments to remove. Every occurrence of an argument in the request is removed.
+	Remove []string `json:"remove"`
+}
+
+// Argument defines an argument of a request.
+type Argument struct {
+	Name string `json:"name"`
+	// Value is the value of the argument. It can contain variables that clients can't set, for example, ${server_name}.
+	Value string `json:"value"`
+}
+
+// ResponseBodyRewrite defines a rewrite of the bodies of the responses in an ActionProxy.
+type ResponseBodyRewrite struct {
+	// Match is the string to replace.
+	Match string `json:"match"`
+	// Replacement is the string that replaces the matched string.
+	Replacement string `json:"replacement"`
+	// Once replaces only the first occurrence of the string. The default is to replace all occurrences.
+	// NGINX applies it to all rewrites of the action, so it must be the same for all rewrites.
+	Once bool `json:"once"`
+	// Types lists the MIME types of the responses to rewrite in addition to text/html.
+	Types []string `json:"types"`
 }
 
 // ProxyRequestHeaders defines the request headers manipulation in an ActionProxy.
@@ -458,6 +598,35 @@ type TransportServerSpec struct {
 	UpstreamParameters *UpstreamParameters       `json:"upstreamParameters"`
 	SessionParameters  *SessionParameters        `json:"sessionParameters"`
 	Action             *TransportServerAction    `json:"action"`
+	// Maintenance configures the maintenance mode of the TransportServer.
+	Maintenance *TransportServerMaintenance `json:"maintenance"`
+	// RateLimit limits the connections and the transfer rates of the clients of the TransportServer.
+	RateLimit *TransportServerRateLimit `json:"rateLimit"`
+}
+
+// TransportServerRateLimit limits the number of the simultaneous connections per key
+// and the rates of the transfer of the data per connection.
+type TransportServerRateLimit struct {
+	// Connections is the maximum number of the simultaneous connections per key. 0 disables the limit.
+	Connections int `json:"connections"`
+	// Key is the key of the connection limit. The default is ${binary_remote_addr}.
+	Key string `json:"key"`
+	// ZoneSize is the size of the shared memory zone of the connection limit. The default is 10M.
+	ZoneSize string `json:"zoneSize"`
+	// UploadRate is the maximum rate of reading the data from the client in bytes per second, for example, 100k.
+	UploadRate string `json:"uploadRate"`
+	// DownloadRate is the maximum rate of reading the data from the upstream in bytes per second, for example, 1m.
+	DownloadRate string `json:"downloadRate"`
+}
+
+// TransportServerMaintenance defines the maintenance mode of a TransportServer.
+// When the maintenance mode is enabled, NGINX closes the connections of the clients
+// that are not in the allow list without removing the upstreams.
+type TransportServerMaintenance struct {
+	// Enable enables the maintenance mode.
+	Enable bool `json:"enable"`
+	// AllowList is the list of IP addresses and CIDRs of the clients whose connections are still passed to the upstreams.
+	AllowList []string `json:"allowList"`
 }
 
 // TransportServerTLS defines TransportServerTLS configuration for a TransportServer.
@@ -570,16 +739,24 @@ type PolicyStatus struct {
 // The spec includes multiple fields, where each field represents a different policy.
 // Only one policy (field) is allowed.
 type PolicySpec struct {
-	IngressClass  string         `json:"ingressClassName"`
-	AccessControl *AccessControl `json:"accessControl"`
-	RateLimit     *RateLimit     `json:"rateLimit"`
-	JWTAuth       *JWTAuth       `json:"jwt"`
-	BasicAuth     *BasicAuth     `json:"basicAuth"`
-	IngressMTLS   *IngressMTLS   `json:"ingressMTLS"`
-	EgressMTLS    *EgressMTLS    `json:"egressMTLS"`
-	OIDC          *OIDC          `json:"oidc"`
-	WAF           *WAF           `json:"waf"`
-	APIKey        *APIKey        `json:"apiKey"`
+	IngressClass          string                 `json:"ingressClassName"`
+	AccessControl         *AccessControl         `json:"accessControl"`
+	RateLimit             *RateLimit             `json:"rateLimit"`
+	JWTAuth               *JWTAuth               `json:"jwt"`
+	BasicAuth             *BasicAuth             `json:"basicAuth"`
+	IngressMTLS           *IngressMTLS           `json:"ingressMTLS"`
+	EgressMTLS            *EgressMTLS            `json:"egressMTLS"`
+	OIDC                  *OIDC                  `json:"oidc"`
+	WAF                   *WAF                   `json:"waf"`
+	APIKey                *APIKey                `json:"apiKey"`
+	Retry                 *Retry                 `json:"retry"`
+	CircuitBreaker        *CircuitBreaker        `json:"circuitBreaker"`
+	Headers               *Headers               `json:"headers"`
+	RequestLimits         *RequestLimits         `json:"requestLimits"`
+	OAuth2Introspection   *OAuth2Introspection   `json:"oauth2Introspection"`
+	SignatureVerification *SignatureVerification `json:"signatureVerification"`
+	Challenge             *Challenge             `json:"challenge"`
+	BandwidthLimit        *BandwidthLimit        `json:"bandwidthLimit"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -596,6 +773,22 @@ type PolicyList struct {
 type AccessControl struct {
 	Allow []string `json:"allow"`
 	Deny  []string `json:"deny"`
+	// Geo defines an access policy based on the country or the autonomous system of the source IP of a request.
+	// It requires the GeoIP2 module and the MaxMind databases configured in the ConfigMap.
+	Geo *GeoAccessControl `json:"geo"`
+}
+
+// GeoAccessControl allows or denies the requests by the location of the source IP looked up in the MaxMind databases.
+// A request matches the rules if its country or its autonomous system is in the lists.
+type GeoAccessControl struct {
+	// AllowCountries are the ISO 3166-1 alpha-2 codes of the allowed countries, for example, US.
+	AllowCountries []string `json:"allowCountries"`
+	// AllowASNs are the numbers of the allowed autonomous systems.
+	AllowASNs []int `json:"allowASNs"`
+	// DenyCountries are the ISO 3166-1 alpha-2 codes of the denied countries.
+	DenyCountries []string `json:"denyCountries"`
+	// DenyASNs are the numbers of the denied autonomous systems.
+	DenyASNs []int `json:"denyASNs"`
 }
 
 // RateLimit defines a rate limit policy.
@@ -614,9 +807,31 @@ type RateLimit struct {
 	Condition *RateLimitCondition `json:"condition"`
 }
 
+// BandwidthLimit defines a policy that limits the rate of the responses to the clients. The rate is limited per connection.
+type BandwidthLimit struct {
+	// Rate is the maximum rate of a response in bytes per second, for example, 100k. 0 disables the limit.
+	Rate string `json:"rate"`
+	// RateAfter is the size of the initial part of a response that is sent without the limit, for example, 1m.
+	RateAfter string `json:"rateAfter"`
+	// Key is the value that selects the rate of a client from Rates, for example, ${http_x_client_tier}.
+	Key string `json:"key"`
+	// Rates are the rates of the clients whose Key matches the value. The other clients get the Rate.
+	Rates []BandwidthLimitRate `json:"rates"`
+}
+
+// BandwidthLimitRate defines the rate of the clients whose key of a bandwidth limit policy is the Value.
+type BandwidthLimitRate struct {
+	Value string `json:"value"`
+	// Rate is the maximum rate of a response in bytes per second, for example, 1m. 0 disables the limit.
+	Rate string `json:"rate"`
+}
+
 // RateLimitCondition defines a condition for a rate limit policy.
 type RateLimitCondition struct {
 	JWT *JWTCondition `json:"jwt"`
+	// The rate limit tier of the client of an API key policy.
+	// +kubebuilder:validation:Optional
+	APIKey *APIKeyCondition `json:"apiKey"`
 	// +kubebuilder:validation:Optional
 	Default bool `json:"default"`
 }
@@ -631,6 +846,14 @@ type JWTCondition struct {
 	Match string `json:"match"`
 }
 
+// APIKeyCondition defines a condition for a rate limit by the tier of the client of an API key policy.
+type APIKeyCondition struct {
+	// The tier of the client in the API key secret.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	Tier string `json:"tier"`
+}
+
 // JWTAuth holds JWT authentication configuration.
 type JWTAuth struct {
 	Realm    string `json:"realm"`
@@ -638,6 +861,35 @@ type JWTAuth struct {
 	Token    string `json:"token"`
 	JwksURI  string `json:"jwksURI"`
 	KeyCache string `json:"keyCache"`
+	// The rules for the claims of a token that must be met to access a resource.
+	Authorization *JWTAuthorization `json:"authorization"`
+}
+
+// JWTAuthorization defines the claim-based authorization rules of a JWT policy.
+type JWTAuthorization struct {
+	// The audiences of which the aud claim of a token must contain at least one.
+	Audiences []string `json:"audiences"`
+	// The issuers of which the iss claim of a token must be one.
+	Issuers []string `json:"issuers"`
+	// The claims of a token that must contain at least one of their values.
+	Claims []JWTClaimRequirement `json:"claims"`
+	// The request headers to set to the values of the claims of a token.
+	Headers []JWTClaimHeader `json:"headers"`
+	// The status code of the response when a token doesn't meet the rules. The default is 403.
+	ErrorCode *int `json:"errorCode"`
+}
+
+// JWTClaimRequirement defines the values of a claim of a token of which the claim must contain at least one.
+type JWTClaimRequirement struct {
+	// The name of the claim. The names of nested claims are separated by dots, for example, realm_access.roles.
+	Claim  string   `json:"claim"`
+	Values []string `json:"values"`
+}
+
+// JWTClaimHeader defines a request header set to the value of a claim of a token.
+type JWTClaimHeader struct {
+	Name  string `json:"name"`
+	Claim string `json:"claim"`
 }
 
 // BasicAuth holds HTTP Basic authentication configuration
@@ -681,6 +933,26 @@ type OIDC struct {
 	ZoneSyncLeeway        *int     `json:"zoneSyncLeeway"`
 	AuthExtraArgs         []string `json:"authExtraArgs"`
 	AccessTokenEnable     bool     `json:"accessTokenEnable"`
+	// Enables the Proof Key for Code Exchange (PKCE) flow for public clients without a client secret.
+	PKCEEnable bool `json:"pkceEnable"`
+	// The path of the location that logs out of the OIDC session. The default is /logout.
+	LogoutURI string `json:"logoutURI"`
+	// The session of the OIDC policy.
+	Session *OIDCSession `json:"session"`
+}
+
+// OIDCSession defines the session cookie and the lifetime of the tokens of an OIDC policy.
+type OIDCSession struct {
+	// The name of the session cookie. The default is auth_token.
+	CookieName string `json:"cookieName"`
+	// The Domain attribute of the session cookie.
+	CookieDomain string `json:"cookieDomain"`
+	// The SameSite attribute of the session cookie: strict, lax or none. The default is lax.
+	CookieSameSite string `json:"cookieSameSite"`
+	// The time the ID and access tokens of a session are kept. The default is 1h.
+	Timeout string `json:"timeout"`
+	// The time the refresh token of a session is kept. The default is 8h.
+	RefreshTimeout string `json:"refreshTimeout"`
 }
 
 // WAF defines an WAF policy.
@@ -704,6 +976,8 @@ type SecurityLog struct {
 type APIKey struct {
 	SuppliedIn   *SuppliedIn `json:"suppliedIn"`
 	ClientSecret string      `json:"clientSecret"`
+	// The name of the request header that passes the ID of the authenticated client to the upstream.
+	ClientIDHeader string `json:"clientIDHeader"`
 }
 
 // SuppliedIn defines the locations API Key should be supplied in.
@@ -711,3 +985,352 @@ type SuppliedIn struct {
 	Header []string `json:"header"`
 	Query  []string `json:"query"`
 }
+
+// Retry defines a retry policy. The policy configures passing a request to the next upstream server.
+type Retry struct {
+	// RetryOn are the cases of passing a request to the next upstream server: error, timeout, invalid_header, http_500, http_502, http_503, http_504, http_403, http_404 or http_429. The default is error and timeout.
+	RetryOn []string `json:"retryOn"`
+	// Tries limits the number of tries of passing a request. The default is 0, no limit.
+	Tries int `json:"tries"`
+	// PerTryTimeout is the timeout for connecting to, sending a request to and reading a response from an upstream server, for example, 5s.
+	PerTryTimeout string `json:"perTryTimeout"`
+	// Timeout limits the time during which a request can be passed to the next upstream server, for example, 30s. If not set, the next-upstream-timeout of the upstream applies.
+	Timeout string `json:"timeout"`
+	// NonIdempotent enables passing requests with non-idempotent methods (POST, LOCK, PATCH) to the next upstream server.
+	NonIdempotent bool `json:"nonIdempotent"`
+}
+
+// CircuitBreaker defines a circuit breaker policy. The policy configures when the servers of the upstreams of a route are considered unavailable.
+type CircuitBreaker struct {
+	// MaxFails is the number of unsuccessful attempts to communicate with a server within the failTimeout that marks the server unavailable.
+	MaxFails *int `json:"maxFails"`
+	// FailTimeout is the time for the unsuccessful attempts and the time the server is considered unavailable, for example, 10s.
+	FailTimeout string `json:"failTimeout"`
+	// Ejection enables active health checks that eject the servers responding with 5xx status codes. Supported in NGINX Plus only.
+	Ejection *CircuitBreakerEjection `json:"ejection"`
+}
+
+// CircuitBreakerEjection defines the ejection of the servers of a circuit breaker.
+type CircuitBreakerEjection struct {
+	// Consecutive5xx is the number of consecutive health checks with a 5xx status code that ejects a server.
+	Consecutive5xx int `json:"consecutive5xx"`
+	// Interval is the interval between the health checks, for example, 5s. The default is 5s.
+	Interval string `json:"interval"`
+	// Path is the path of the health checks. The default is /.
+	Path string `json:"path"`
+}
+
+// Headers defines a headers policy. The policy modifies the headers of the requests passed to the upstreams
+// and of the responses returned to the clients.
+type Headers struct {
+	// Request defines the modification of the request headers.
+	Request *HeadersRequest `json:"request"`
+	// Response defines the modification of the response headers.
+	Response *HeadersResponse `json:"response"`
+}
+
+// HeadersRequest defines the modification of the request headers in a headers policy.
+type HeadersRequest struct {
+	// Set lists the headers to set.
+	Set []PolicyHeader `json:"set"`
+	// Remove lists the names of the headers to remove.
+	Remove []string `json:"remove"`
+}
+
+// HeadersResponse defines the modification of the response headers in a headers policy.
+type HeadersResponse struct {
+	// Add lists the headers to add.
+	Add []PolicyAddHeader `json:"add"`
+	// Hide lists the names of the headers of the responses of the upstreams to hide.
+	Hide []string `json:"hide"`
+}
+
+// PolicyHeader defines a header of a headers policy.
+type PolicyHeader struct {
+	Name string `json:"name"`
+	// Value is the value of the header. With a ValueMap, it is the value for the values of the variable that don't match.
+	Value string `json:"value"`
+	// ValueMap selects the value of the header by the value of a variable.
+	ValueMap *HeaderValueMap `json:"valueMap"`
+}
+
+// PolicyAddHeader defines a header of a headers policy with an optional Always field to use with the add_header NGINX directive.
+type PolicyAddHeader struct {
+	PolicyHeader `json:",inline"`
+	Always       bool `json:"always"`
+}
+
+// HeaderValueMap selects the value of a header by the value of a variable.
+type HeaderValueMap struct {
+	// Variable is the NGINX variable, for example, $scheme or $http_origin.
+	Variable string `json:"variable"`
+	// Values lists the values of the header for the values of the variable.
+	Values []HeaderMapValue `json:"values"`
+}
+
+// HeaderMapValue defines the value of a header for a value of the variable of a HeaderValueMap.
+type HeaderMapValue struct {
+	// Match is the value of the variable.
+	Match string `json:"match"`
+	// Value is the value of the header.
+	Value string `json:"value"`
+}
+
+// RequestLimits defines a requestLimits policy. The policy limits the size, the duration and the methods of the client requests.
+type RequestLimits struct {
+	// MaxBodySize is the maximum size of the request body, for example, 10m. 0 disables the check.
+	MaxBodySize string `json:"maxBodySize"`
+	// HeaderBufferSize is the size of the buffer for reading the request header, for example, 1k.
+	HeaderBufferSize string `json:"headerBufferSize"`
+	// LargeHeaderBuffers are the buffers for reading large request headers.
+	LargeHeaderBuffers *UpstreamBuffers `json:"largeHeaderBuffers"`
+	// BodyTimeout is the timeout between two successive read operations of the request body, for example, 60s.
+	BodyTimeout string `json:"bodyTimeout"`
+	// HeaderTimeout is the timeout for reading the request header, for example, 60s.
+	HeaderTimeout string `json:"headerTimeout"`
+	// AllowedMethods lists the allowed HTTP methods. The requests with other methods are rejected with the 405 status code.
+	AllowedMethods []string `json:"allowedMethods"`
+	// MaxURILength is the maximum length of the request URI. The requests with longer URIs are rejected with the 414 status code.
+	MaxURILength *int `json:"maxURILength"`
+}
+
+// OAuth2Introspection defines an OAuth 2.0 Token Introspection (RFC 7662) policy. The policy validates the opaque
+// access tokens of the requests with the introspection endpoint of an authorization server.
+type OAuth2Introspection struct {
+	// Endpoint is the URL of the introspection endpoint of the authorization server.
+	Endpoint string `json:"endpoint"`
+	// ClientSecret is the name of the Secret with the client ID and the client secret for the introspection endpoint.
+	// The Secret must be of the type nginx.org/oauth2-introspection.
+	ClientSecret string `json:"clientSecret"`
+	// Scopes are the scopes that the scope field of the introspection response must contain.
+	Scopes []string `json:"scopes"`
+	// Audiences are the accepted audiences. The aud field of the introspection response must contain one of them.
+	Audiences []string `json:"audiences"`
+	// CacheTimeout is the time an introspection result is cached, for example, 1m. The default is 1m.
+	CacheTimeout string `json:"cacheTimeout"`
+	// Headers pass the fields of the introspection response to the upstream in request headers.
+	Headers []OAuth2IntrospectionHeader `json:"headers"`
+}
+
+// OAuth2IntrospectionHeader defines a request header with the value of a field of the introspection response.
+type OAuth2IntrospectionHeader struct {
+	// Name is the name of the request header.
+	Name string `json:"name"`
+	// Field is the field of the introspection response, for example, sub. The fields of nested objects are separated by dots.
+	Field string `json:"field"`
+}
+
+// SignatureVerification defines a policy that verifies the HMAC signature of the body of the requests,
+// for example, the requests of webhooks. The requests with a missing or wrong signature are rejected with the 401 status code.
+type SignatureVerification struct {
+	// Header is the name of the request header with the signature, for example, X-Hub-Signature-256.
+	Header string `json:"header"`
+	// Algorithm is the hash algorithm of the HMAC: sha1 or sha256. The default is sha256.
+	// +kubebuilder:validation:Enum=sha1;sha256
+	Algorithm string `json:"algorithm"`
+	// Encoding is the encoding of the signature in the header: hex or base64. The default is hex.
+	// +kubebuilder:validation:Enum=hex;base64
+	Encoding string `json:"encoding"`
+	// Prefix is stripped from the value of the header before the signature is compared, for example, sha256=.
+	Prefix string `json:"prefix"`
+	// Secret is the name of the Secret with the HMAC key. The Secret must be of the type nginx.org/hmac.
+	Secret string `json:"secret"`
+	// TimestampHeader is the name of the request header with the time the request was signed in seconds since the epoch.
+	// If set, the requests with a timestamp outside of the TimestampTolerance are rejected to prevent replays.
+	TimestampHeader string `json:"timestampHeader"`
+	// TimestampTolerance is the maximum difference between the timestamp of the request and the current time,
+	// for example, 5m. The default is 5m.
+	TimestampTolerance string `json:"timestampTolerance"`
+	// SignedPayload is the format of the signed string with the placeholders {timestamp} and {body}, for example,
+	// v0:{timestamp}:{body}. It must include {timestamp} if TimestampHeader is set. The default is {body}, or
+	// {timestamp}.{body} if TimestampHeader is set.
+	SignedPayload string `json:"signedPayload"`
+}
+
+// Challenge defines a policy that requires the clients to pass a JavaScript or a cookie challenge before their requests
+// are proxied. The clients that passed the challenge get a cookie signed with the key from a Secret.
+type Challenge struct {
+	// Type is the type of the challenge: javascript or cookie. The javascript challenge returns a page with a script that
+	// computes a proof of work, and the cookie is set once the proof is verified. The cookie challenge sets the cookie
+	// in the response and redirects the client to the same URI. The default is javascript.
+	// +kubebuilder:validation:Enum=javascript;cookie
+	Type string `json:"type"`
+	// Secret is the name of the Secret with the key that signs the cookie. The Secret must be of the type nginx.org/hmac.
+	Secret string `json:"secret"`
+	// CookieName is the name of the cookie of the clients that passed the challenge. The default is nic_challenge.
+	CookieName string `json:"cookieName"`
+	// CookieLifetime is the time after which the clients must pass the challenge again, for example, 1h. The default is 1h.
+	CookieLifetime string `json:"cookieLifetime"`
+	// ExemptCIDRs are the networks or addresses of the clients that are not challenged.
+	ExemptCIDRs []string `json:"exemptCIDRs"`
+	// ExemptUserAgents are the case-insensitive regular expressions of the User-Agent headers of the clients that are not challenged.
+	ExemptUserAgents []string `json:"exemptUserAgents"`
+	// Block configures the blocking of the clients that repeatedly fail the verification of the challenge.
+	Block *ChallengeBlock `json:"block"`
+}
+
+// ChallengeBlock blocks the clients that fail the verification of the challenge Threshold times with less than Interval
+// between the failures.
+// The requests of the blocked clients are rejected with the 403 status code.
+type ChallengeBlock struct {
+	// Threshold is the number of the failed verifications after which the client is blocked.
+	Threshold int `json:"threshold"`
+	// Interval is the time after which the failed verifications of a client are forgotten, for example, 1m. The default is 1m.
+	Interval string `json:"interval"`
+	// Duration is the time a client is blocked for, for example, 10m. The default is 10m.
+	Duration string `json:"duration"`
+}
+
+// States of a Rollout.
+const (
+	// RolloutStateProgressing is used when the Rollout steps up the weight of the route.
+	RolloutStateProgressing = "Progressing"
+	// RolloutStateCompleted is used when the Rollout applied the weights of all steps.
+	RolloutStateCompleted = "Completed"
+	// RolloutStateRolledBack is used when the Rollout sent all requests back to the first split of the route
+	// because the analysis thresholds were breached.
+	RolloutStateRolledBack = "RolledBack"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:validation:Optional
+// +kubebuilder:resource:shortName=ro
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`,description="Current state of the Rollout."
+// +kubebuilder:printcolumn:name="Weight",type=integer,JSONPath=`.status.weight`,description="Current weight of the second split of the route."
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Rollout defines the progressive delivery of a route of a VirtualServer. The Rollout steps up the weight of the
+// second split of the route and rolls back when the upstream of the split breaches the thresholds of the analysis.
+type Rollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RolloutSpec   `json:"spec"`
+	Status RolloutStatus `json:"status"`
+}
+
+// RolloutSpec is the spec of the Rollout resource.
+type RolloutSpec struct {
+	IngressClass string `json:"ingressClassName"`
+	// VirtualServer is the name of the VirtualServer in the namespace of the Rollout.
+	VirtualServer string `json:"virtualServer"`
+	// Route is the path of the route of the VirtualServer. The route must have two splits or a canary.
+	Route string `json:"route"`
+	// Steps are the weights of the second split of the route, applied one after another.
+	Steps []RolloutStep `json:"steps"`
+	// Analysis defines the thresholds for the upstream of the second split that trigger a rollback.
+	Analysis *RolloutAnalysis `json:"analysis"`
+}
+
+// RolloutStep defines a step of a Rollout.
+type RolloutStep struct {
+	// Weight is the weight of the second split of the route, from 0 to 100.
+	Weight int `json:"weight"`
+	// Pause is the time to wait before the next step, for example 5m. The default is 1m.
+	Pause string `json:"pause"`
+}
+
+// RolloutAnalysis defines the thresholds of a Rollout.
+type RolloutAnalysis struct {
+	// MaxErrorRate is the maximum percentage of the responses with the 5xx status codes.
+	MaxErrorRate *int `json:"maxErrorRate"`
+	// MaxLatency is the maximum average response time, for example 500ms. Requires the latency metrics.
+	MaxLatency string `json:"maxLatency"`
+	// MinRequests is the number of requests of a step before the thresholds are checked.
+	MinRequests int `json:"minRequests"`
+}
+
+// RolloutStatus is the status of the Rollout resource.
+type RolloutStatus struct {
+	State   string `json:"state"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	// ObservedGeneration is the generation of the spec the status is for.
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// CurrentStep is the index of the current step.
+	CurrentStep int `json:"currentStep"`
+	// Weight is the current weight of the second split of the route.
+	Weight int `json:"weight"`
+	// StepStartTime is the time the current step started.
+	StepStartTime *metav1.Time `json:"stepStartTime"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RolloutList is a list of the Rollout resources.
+type RolloutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Rollout `json:"items"`
+}
+
+// Kinds of the resources of a ReferenceGrant.
+const (
+	// ReferenceGrantKindVirtualServer is the kind of the VirtualServer resource.
+	ReferenceGrantKindVirtualServer = "VirtualServer"
+	// ReferenceGrantKindVirtualServerRoute is the kind of the VirtualServerRoute resource.
+	ReferenceGrantKindVirtualServerRoute = "VirtualServerRoute"
+	// ReferenceGrantKindIngress is the kind of the Ingress resource.
+	ReferenceGrantKindIngress = "Ingress"
+	// ReferenceGrantKindPolicy is the kind of the Policy resource.
+	ReferenceGrantKindPolicy = "Policy"
+	// ReferenceGrantKindSecret is the kind of the Secret resource.
+	ReferenceGrantKindSecret = "Secret"
+	// ReferenceGrantKindService is the kind of the Service resource.
+	ReferenceGrantKindService = "Service"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:validation:Optional
+// +kubebuilder:resource:shortName=rg
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ReferenceGrant allows the resources in other namespaces to reference the resources in the namespace of the ReferenceGrant.
+type ReferenceGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ReferenceGrantSpec `json:"spec"`
+}
+
+// ReferenceGrantSpec is the spec of the ReferenceGrant resource.
+type ReferenceGrantSpec struct {
+	// From are the resources in other namespaces that are allowed to reference the resources in To.
+	From []ReferenceGrantFrom `json:"from"`
+	// To are the resources in the namespace of the ReferenceGrant that can be referenced.
+	To []ReferenceGrantTo `json:"to"`
+}
+
+// ReferenceGrantFrom defines the resources in a namespace that are allowed to reference the resources of a ReferenceGrant.
+type ReferenceGrantFrom struct {
+	// Kind is the kind of the resources: VirtualServer, VirtualServerRoute, Ingress or Policy.
+	// +kubebuilder:validation:Enum=VirtualServer;VirtualServerRoute;Ingress;Policy
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the resources.
+	Namespace string `json:"namespace"`
+}
+
+// ReferenceGrantTo defines the resources that can be referenced.
+type ReferenceGrantTo struct {
+	// Kind is the kind of the resources: Policy, Secret or Service.
+	// +kubebuilder:validation:Enum=Policy;Secret;Service
+	Kind string `json:"kind"`
+	// Name is the name of the resource. If empty, all resources of the kind can be referenced.
+	Name string `json:"name"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ReferenceGrantList is a list of the ReferenceGrant resources.
+type ReferenceGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ReferenceGrant `json:"items"`
+}
@@ -141,6 +141,14 @@ func validateStringWithVariables(str string, fieldPath *field.Path, specialVars
 	return allErrs
 }
 
+func validateHeaderName(name string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, msg := range validation.IsHTTPHeaderName(name) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, name, msg))
+	}
+	return allErrs
+}
+
 func validateTime(time string, fieldPath *field.Path) field.ErrorList {
 	if time == "" {
 		return nil
@@ -194,6 +202,23 @@ func validateSecretName(name string, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// validateNamespacedSecretName validates the name of a Secret with an optional namespace, in the format name or namespace/name.
+func validateNamespacedSecretName(name string, fieldPath *field.Path) field.ErrorList {
+	namespace, secretName, found := strings.Cut(name, "/")
+	if !found {
+		return validateSecretName(name, fieldPath)
+	}
+
+	allErrs := field.ErrorList{}
+	for _, msg := range validation.IsDNS1123Label(namespace) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, name, msg))
+	}
+	if secretName == "" {
+		return append(allErrs, field.Invalid(fieldPath, name, "must include the name of the secret after the namespace"))
+	}
+	return append(allErrs, validateSecretName(secretName, fieldPath)...)
+}
+
 func mapToPrettyString(m map[string]bool) string {
 	var out []string
 
@@ -295,6 +295,25 @@ func TestValidateSize(t *testing.T) {
 	}
 }
 
+func TestValidateNamespacedSecretName(t *testing.T) {
+	t.Parallel()
+	validInput := []string{"", "jwk-secret", "auth/jwk-secret"}
+	for _, test := range validInput {
+		allErrs := validateNamespacedSecretName(test, field.NewPath("secret"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateNamespacedSecretName(%q) returned an error for valid input", test)
+		}
+	}
+
+	invalidInput := []string{"-foo-", "auth/", "/jwk-secret", "Auth/jwk-secret", "auth/jwk-secret/key"}
+	for _, test := range invalidInput {
+		allErrs := validateNamespacedSecretName(test, field.NewPath("secret"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateNamespacedSecretName(%q) didn't return error for invalid input.", test)
+		}
+	}
+}
+
 func TestValidateTime(t *testing.T) {
 	t.Parallel()
 	time := "1h 2s"
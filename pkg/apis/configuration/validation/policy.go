@@ -5,6 +5,7 @@ import (
 	"net"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
@@ -15,18 +16,18 @@ import (
 )
 
 // ValidatePolicy validates a Policy.
-func ValidatePolicy(policy *v1.Policy, isPlus, enableOIDC, enableAppProtect bool) error {
-	allErrs := validatePolicySpec(&policy.Spec, field.NewPath("spec"), isPlus, enableOIDC, enableAppProtect)
+func ValidatePolicy(policy *v1.Policy, isPlus, enableOIDC, enableAppProtect, enableGeoIP2 bool) error {
+	allErrs := validatePolicySpec(&policy.Spec, field.NewPath("spec"), isPlus, enableOIDC, enableAppProtect, enableGeoIP2)
 	return allErrs.ToAggregate()
 }
 
-func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enableOIDC, enableAppProtect bool) field.ErrorList {
+func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enableOIDC, enableAppProtect, enableGeoIP2 bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	fieldCount := 0
 
 	if spec.AccessControl != nil {
-		allErrs = append(allErrs, validateAccessControl(spec.AccessControl, fieldPath.Child("accessControl"))...)
+		allErrs = append(allErrs, validateAccessControl(spec.AccessControl, fieldPath.Child("accessControl"), enableGeoIP2)...)
 		fieldCount++
 	}
 
@@ -90,8 +91,48 @@ func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enab
 		fieldCount++
 	}
 
+	if spec.Retry != nil {
+		allErrs = append(allErrs, validateRetry(spec.Retry, fieldPath.Child("retry"))...)
+		fieldCount++
+	}
+
+	if spec.CircuitBreaker != nil {
+		allErrs = append(allErrs, validateCircuitBreaker(spec.CircuitBreaker, fieldPath.Child("circuitBreaker"), isPlus)...)
+		fieldCount++
+	}
+
+	if spec.Headers != nil {
+		allErrs = append(allErrs, validateHeaders(spec.Headers, fieldPath.Child("headers"), isPlus)...)
+		fieldCount++
+	}
+
+	if spec.RequestLimits != nil {
+		allErrs = append(allErrs, validateRequestLimits(spec.RequestLimits, fieldPath.Child("requestLimits"))...)
+		fieldCount++
+	}
+
+	if spec.OAuth2Introspection != nil {
+		allErrs = append(allErrs, validateOAuth2Introspection(spec.OAuth2Introspection, fieldPath.Child("oauth2Introspection"))...)
+		fieldCount++
+	}
+
+	if spec.SignatureVerification != nil {
+		allErrs = append(allErrs, validateSignatureVerification(spec.SignatureVerification, fieldPath.Child("signatureVerification"))...)
+		fieldCount++
+	}
+
+	if spec.Challenge != nil {
+		allErrs = append(allErrs, validateChallenge(spec.Challenge, fieldPath.Child("challenge"))...)
+		fieldCount++
+	}
+
+	if spec.BandwidthLimit != nil {
+		allErrs = append(allErrs, validateBandwidthLimit(spec.BandwidthLimit, fieldPath.Child("bandwidthLimit"), isPlus)...)
+		fieldCount++
+	}
+
 	if fieldCount != 1 {
-		msg := "must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`"
+		msg := "must specify exactly one of: `accessControl`, `rateLimit`, `ingressMTLS`, `egressMTLS`, `basicAuth`, `apiKey`, `retry`, `circuitBreaker`, `headers`, `requestLimits`, `oauth2Introspection`, `signatureVerification`, `challenge`, `bandwidthLimit`"
 		if isPlus {
 			msg = fmt.Sprint(msg, ", `jwt`, `oidc`, `waf`")
 		}
@@ -101,7 +142,7 @@ func validatePolicySpec(spec *v1.PolicySpec, fieldPath *field.Path, isPlus, enab
 	return allErrs
 }
 
-func validateAccessControl(accessControl *v1.AccessControl, fieldPath *field.Path) field.ErrorList {
+func validateAccessControl(accessControl *v1.AccessControl, fieldPath *field.Path, enableGeoIP2 bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	fieldCount := 0
@@ -120,8 +161,59 @@ func validateAccessControl(accessControl *v1.AccessControl, fieldPath *field.Pat
 		fieldCount++
 	}
 
+	if accessControl.Geo != nil {
+		if !enableGeoIP2 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("geo"), "requires the GeoIP2 module"))
+		}
+		allErrs = append(allErrs, validateGeoAccessControl(accessControl.Geo, fieldPath.Child("geo"))...)
+		fieldCount++
+	}
+
 	if fieldCount != 1 {
-		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `allow` or `deny`"))
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `allow`, `deny` or `geo`"))
+	}
+
+	return allErrs
+}
+
+const (
+	countryCodeFmt    = `[A-Z]{2}`
+	countryCodeErrMsg = "must be an ISO 3166-1 alpha-2 country code"
+)
+
+var countryCodeRegexp = regexp.MustCompile("^" + countryCodeFmt + "$")
+
+// maxASN is the largest 4-byte autonomous system number.
+const maxASN = 4294967295
+
+func validateGeoAccessControl(geo *v1.GeoAccessControl, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	validateCountries := func(countries []string, fieldPath *field.Path) {
+		for i, c := range countries {
+			if !countryCodeRegexp.MatchString(c) {
+				msg := validation.RegexError(countryCodeErrMsg, countryCodeFmt, "US", "DE")
+				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i), c, msg))
+			}
+		}
+	}
+	validateASNs := func(asns []int, fieldPath *field.Path) {
+		for i, asn := range asns {
+			for _, msg := range validation.IsInRange(asn, 1, maxASN) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i), asn, msg))
+			}
+		}
+	}
+
+	validateCountries(geo.AllowCountries, fieldPath.Child("allowCountries"))
+	validateASNs(geo.AllowASNs, fieldPath.Child("allowASNs"))
+	validateCountries(geo.DenyCountries, fieldPath.Child("denyCountries"))
+	validateASNs(geo.DenyASNs, fieldPath.Child("denyASNs"))
+
+	allow := len(geo.AllowCountries) > 0 || len(geo.AllowASNs) > 0
+	deny := len(geo.DenyCountries) > 0 || len(geo.DenyASNs) > 0
+	if allow == deny {
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify either the allowed or the denied countries and autonomous systems"))
 	}
 
 	return allErrs
@@ -151,14 +243,63 @@ func validateRateLimit(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bo
 		}
 	}
 
-	if rateLimit.Condition != nil && rateLimit.Condition.JWT == nil {
-		allErrs = append(allErrs, field.Required(fieldPath.Child("jwt"), "jwt cannot be nil"))
+	if rateLimit.Condition != nil && rateLimit.Condition.JWT == nil && rateLimit.Condition.APIKey == nil {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("jwt"), "jwt or apiKey must be specified"))
+	}
+
+	if rateLimit.Condition != nil && rateLimit.Condition.JWT != nil && rateLimit.Condition.APIKey != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("condition"), "", "must specify exactly one of: `jwt` or `apiKey`"))
 	}
 
 	if rateLimit.Condition != nil && rateLimit.Condition.JWT != nil && !isPlus {
 		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("condition.jwt"), "is only supported in NGINX Plus"))
 	}
 
+	if rateLimit.Condition != nil && rateLimit.Condition.APIKey != nil {
+		allErrs = append(allErrs, validateAPIKeyTier(rateLimit.Condition.APIKey.Tier, fieldPath.Child("condition.apiKey.tier"))...)
+	}
+
+	return allErrs
+}
+
+func validateBandwidthLimit(bandwidthLimit *v1.BandwidthLimit, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if bandwidthLimit.Rate == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("rate"), ""))
+	} else {
+		allErrs = append(allErrs, validateSize(bandwidthLimit.Rate, fieldPath.Child("rate"))...)
+	}
+	allErrs = append(allErrs, validateSize(bandwidthLimit.RateAfter, fieldPath.Child("rateAfter"))...)
+
+	if len(bandwidthLimit.Rates) == 0 {
+		if bandwidthLimit.Key != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("key"), "requires rates"))
+		}
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validateRateLimitKey(bandwidthLimit.Key, fieldPath.Child("key"), isPlus)...)
+
+	seen := make(map[string]bool)
+	for i, r := range bandwidthLimit.Rates {
+		idxPath := fieldPath.Child("rates").Index(i)
+		if r.Value == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("value"), ""))
+		} else if err := ValidateEscapedString(r.Value, "gold", `tier\"1\"`); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("value"), r.Value, err.Error()))
+		} else if seen[r.Value] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("value"), r.Value))
+		}
+		seen[r.Value] = true
+
+		if r.Rate == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("rate"), ""))
+		} else {
+			allErrs = append(allErrs, validateSize(r.Rate, idxPath.Child("rate"))...)
+		}
+	}
+
 	return allErrs
 }
 
@@ -174,6 +315,10 @@ func validateJWT(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
 	}
 	allErrs := validateRealm(jwt.Realm, fieldPath.Child("realm"))
 
+	if jwt.Authorization != nil {
+		allErrs = append(allErrs, validateJWTAuthorization(jwt.Authorization, fieldPath.Child("authorization"))...)
+	}
+
 	// Use either JWT Secret or JWKS URI, they are mutually exclusive.
 	if jwt.Secret == "" && jwt.JwksURI == "" {
 		return append(allErrs, field.Required(fieldPath.Child("secret"), "either Secret or JwksURI must be present"))
@@ -184,7 +329,7 @@ func validateJWT(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
 
 	// Verify a case when using JWT Secret
 	if jwt.Secret != "" {
-		allErrs = append(allErrs, validateSecretName(jwt.Secret, fieldPath.Child("secret"))...)
+		allErrs = append(allErrs, validateNamespacedSecretName(jwt.Secret, fieldPath.Child("secret"))...)
 		// jwt.Token is not required field. Verify it when provided.
 		if jwt.Token != "" {
 			allErrs = append(allErrs, validateJWTToken(jwt.Token, fieldPath.Child("token"))...)
@@ -223,14 +368,14 @@ func validateBasic(basic *v1.BasicAuth, fieldPath *field.Path) field.ErrorList {
 	if basic.Realm != "" {
 		allErrs = append(allErrs, validateRealm(basic.Realm, fieldPath.Child("realm"))...)
 	}
-	return append(allErrs, validateSecretName(basic.Secret, fieldPath.Child("secret"))...)
+	return append(allErrs, validateNamespacedSecretName(basic.Secret, fieldPath.Child("secret"))...)
 }
 
 func validateIngressMTLS(ingressMTLS *v1.IngressMTLS, fieldPath *field.Path) field.ErrorList {
 	if ingressMTLS.ClientCertSecret == "" {
 		return field.ErrorList{field.Required(fieldPath.Child("clientCertSecret"), "")}
 	}
-	allErrs := validateSecretName(ingressMTLS.ClientCertSecret, fieldPath.Child("clientCertSecret"))
+	allErrs := validateNamespacedSecretName(ingressMTLS.ClientCertSecret, fieldPath.Child("clientCertSecret"))
 	allErrs = append(allErrs, validateIngressMTLSVerifyClient(ingressMTLS.VerifyClient, fieldPath.Child("verifyClient"))...)
 	if ingressMTLS.VerifyDepth != nil {
 		allErrs = append(allErrs, validatePositiveIntOrZero(*ingressMTLS.VerifyDepth, fieldPath.Child("verifyDepth"))...)
@@ -239,12 +384,12 @@ func validateIngressMTLS(ingressMTLS *v1.IngressMTLS, fieldPath *field.Path) fie
 }
 
 func validateEgressMTLS(egressMTLS *v1.EgressMTLS, fieldPath *field.Path) field.ErrorList {
-	allErrs := validateSecretName(egressMTLS.TLSSecret, fieldPath.Child("tlsSecret"))
+	allErrs := validateNamespacedSecretName(egressMTLS.TLSSecret, fieldPath.Child("tlsSecret"))
 
 	if egressMTLS.VerifyServer && egressMTLS.TrustedCertSecret == "" {
 		return append(allErrs, field.Required(fieldPath.Child("trustedCertSecret"), "must be set when verifyServer is 'true'"))
 	}
-	allErrs = append(allErrs, validateSecretName(egressMTLS.TrustedCertSecret, fieldPath.Child("trustedCertSecret"))...)
+	allErrs = append(allErrs, validateNamespacedSecretName(egressMTLS.TrustedCertSecret, fieldPath.Child("trustedCertSecret"))...)
 
 	if egressMTLS.VerifyDepth != nil {
 		allErrs = append(allErrs, validatePositiveIntOrZero(*egressMTLS.VerifyDepth, fieldPath.Child("verifyDepth"))...)
@@ -265,9 +410,13 @@ func validateOIDC(oidc *v1.OIDC, fieldPath *field.Path) field.ErrorList {
 	if oidc.ClientID == "" {
 		return field.ErrorList{field.Required(fieldPath.Child("clientID"), "")}
 	}
-	if oidc.ClientSecret == "" {
+	if oidc.ClientSecret == "" && !oidc.PKCEEnable {
 		return field.ErrorList{field.Required(fieldPath.Child("clientSecret"), "")}
 	}
+	if oidc.ClientSecret != "" && oidc.PKCEEnable {
+		msg := "clientSecret must not be set when pkceEnable is true"
+		return field.ErrorList{field.Forbidden(fieldPath.Child("clientSecret"), msg)}
+	}
 	if oidc.EndSessionEndpoint == "" && oidc.PostLogoutRedirectURI != "" {
 		msg := "postLogoutRedirectURI can only be set when endSessionEndpoint is set"
 		return field.ErrorList{field.Forbidden(fieldPath.Child("postLogoutRedirectURI"), msg)}
@@ -278,7 +427,23 @@ func validateOIDC(oidc *v1.OIDC, fieldPath *field.Path) field.ErrorList {
 		allErrs = append(allErrs, validateOIDCScope(oidc.Scope, fieldPath.Child("scope"))...)
 	}
 	if oidc.RedirectURI != "" {
-		allErrs = append(allErrs, validatePath(oidc.RedirectURI, fieldPath.Child("redirectURI"))...)
+		allErrs = append(allErrs, validateOIDCLocationPath(oidc.RedirectURI, fieldPath.Child("redirectURI"))...)
+	}
+	if oidc.LogoutURI != "" {
+		allErrs = append(allErrs, validateOIDCLocationPath(oidc.LogoutURI, fieldPath.Child("logoutURI"))...)
+	}
+	redirectURI, logoutURI := oidc.RedirectURI, oidc.LogoutURI
+	if redirectURI == "" {
+		redirectURI = "/_codexch"
+	}
+	if logoutURI == "" {
+		logoutURI = "/logout"
+	}
+	if redirectURI == logoutURI {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("logoutURI"), oidc.LogoutURI, "must be different from redirectURI"))
+	}
+	if oidc.Session != nil {
+		allErrs = append(allErrs, validateOIDCSession(oidc.Session, fieldPath.Child("session"))...)
 	}
 	if oidc.EndSessionEndpoint != "" {
 		allErrs = append(allErrs, validateURL(oidc.EndSessionEndpoint, fieldPath.Child("endSessionEndpoint"))...)
@@ -296,10 +461,55 @@ func validateOIDC(oidc *v1.OIDC, fieldPath *field.Path) field.ErrorList {
 	allErrs = append(allErrs, validateURL(oidc.AuthEndpoint, fieldPath.Child("authEndpoint"))...)
 	allErrs = append(allErrs, validateURL(oidc.TokenEndpoint, fieldPath.Child("tokenEndpoint"))...)
 	allErrs = append(allErrs, validateURL(oidc.JWKSURI, fieldPath.Child("jwksURI"))...)
-	allErrs = append(allErrs, validateSecretName(oidc.ClientSecret, fieldPath.Child("clientSecret"))...)
+	if oidc.ClientSecret != "" {
+		allErrs = append(allErrs, validateNamespacedSecretName(oidc.ClientSecret, fieldPath.Child("clientSecret"))...)
+	}
 	return append(allErrs, validateClientID(oidc.ClientID, fieldPath.Child("clientID"))...)
 }
 
+// oidcInternalLocations are the locations of the OIDC flow that are shared by all OIDC policies of a VirtualServer.
+var oidcInternalLocations = []string{"/_jwks_uri", "/_token", "/_refresh", "/_id_token_validation", "/_logout"}
+
+func validateOIDCLocationPath(path string, fieldPath *field.Path) field.ErrorList {
+	allErrs := validatePath(path, fieldPath)
+	if slices.Contains(oidcInternalLocations, path) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, path, "must not be a location of the OIDC flow: "+strings.Join(oidcInternalLocations, ", ")))
+	}
+	return allErrs
+}
+
+const (
+	oidcCookieNameFmt    = `[a-zA-Z0-9_]+`
+	oidcCookieNameErrMsg = "must consist of alphanumeric characters or '_'"
+)
+
+var oidcCookieNameRegexp = regexp.MustCompile("^" + oidcCookieNameFmt + "$")
+
+var validOIDCCookieSameSite = map[string]bool{
+	"strict": true,
+	"lax":    true,
+	"none":   true,
+}
+
+func validateOIDCSession(session *v1.OIDCSession, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if session.CookieName != "" && !oidcCookieNameRegexp.MatchString(session.CookieName) {
+		msg := validation.RegexError(oidcCookieNameErrMsg, oidcCookieNameFmt, "auth_token", "cafe_session")
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("cookieName"), session.CookieName, msg))
+	}
+	if session.CookieDomain != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(strings.TrimPrefix(session.CookieDomain, ".")) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("cookieDomain"), session.CookieDomain, msg))
+		}
+	}
+	if session.CookieSameSite != "" && !validOIDCCookieSameSite[session.CookieSameSite] {
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("cookieSameSite"), session.CookieSameSite, []string{"strict", "lax", "none"}))
+	}
+	allErrs = append(allErrs, validateTime(session.Timeout, fieldPath.Child("timeout"))...)
+	return append(allErrs, validateTime(session.RefreshTimeout, fieldPath.Child("refreshTimeout"))...)
+}
+
 func validateAPIKey(apiKey *v1.APIKey, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -337,7 +547,202 @@ func validateAPIKey(apiKey *v1.APIKey, fieldPath *field.Path) field.ErrorList {
 	if apiKey.ClientSecret == "" {
 		allErrs = append(allErrs, field.Required(fieldPath.Child("clientSecret"), "clientSecret cannot be empty"))
 	} else {
-		allErrs = append(allErrs, validateSecretName(apiKey.ClientSecret, fieldPath.Child("clientSecret"))...)
+		allErrs = append(allErrs, validateNamespacedSecretName(apiKey.ClientSecret, fieldPath.Child("clientSecret"))...)
+	}
+
+	if apiKey.ClientIDHeader != "" {
+		allErrs = append(allErrs, validateHeaderName(apiKey.ClientIDHeader, fieldPath.Child("clientIDHeader"))...)
+	}
+
+	return allErrs
+}
+
+const (
+	apiKeyTierFmt    = `[a-zA-Z0-9_-]+`
+	apiKeyTierErrMsg = "must consist of alphanumeric characters, '-' or '_'"
+)
+
+var apiKeyTierRegexp = regexp.MustCompile("^" + apiKeyTierFmt + "$")
+
+func validateAPIKeyTier(tier string, fieldPath *field.Path) field.ErrorList {
+	if tier == "" {
+		return field.ErrorList{field.Required(fieldPath, "")}
+	}
+	if !apiKeyTierRegexp.MatchString(tier) {
+		msg := validation.RegexError(apiKeyTierErrMsg, apiKeyTierFmt, "gold", "tier-1")
+		return field.ErrorList{field.Invalid(fieldPath, tier, msg)}
+	}
+	return nil
+}
+
+func validateOAuth2Introspection(introspection *v1.OAuth2Introspection, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if introspection.Endpoint == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("endpoint"), ""))
+	} else {
+		allErrs = append(allErrs, validateURL(introspection.Endpoint, fieldPath.Child("endpoint"))...)
+		if u, err := url.Parse(introspection.Endpoint); err == nil && u.Scheme != "http" && u.Scheme != "https" {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("endpoint"), introspection.Endpoint, "scheme must be http or https"))
+		}
+	}
+
+	if introspection.ClientSecret == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("clientSecret"), ""))
+	} else {
+		allErrs = append(allErrs, validateNamespacedSecretName(introspection.ClientSecret, fieldPath.Child("clientSecret"))...)
+	}
+
+	for i, scope := range introspection.Scopes {
+		allErrs = append(allErrs, validateJWTClaimValue(scope, fieldPath.Child("scopes").Index(i))...)
+	}
+	for i, aud := range introspection.Audiences {
+		allErrs = append(allErrs, validateJWTClaimValue(aud, fieldPath.Child("audiences").Index(i))...)
+	}
+
+	allErrs = append(allErrs, validateTime(introspection.CacheTimeout, fieldPath.Child("cacheTimeout"))...)
+
+	seen := make(map[string]bool)
+	for i, h := range introspection.Headers {
+		idxPath := fieldPath.Child("headers").Index(i)
+		if h.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		}
+		allErrs = append(allErrs, validateHeaderName(h.Name, idxPath.Child("name"))...)
+		if seen[strings.ToLower(h.Name)] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), h.Name))
+		}
+		seen[strings.ToLower(h.Name)] = true
+		allErrs = append(allErrs, validateJWTClaim(h.Field, idxPath.Child("field"))...)
+	}
+
+	return allErrs
+}
+
+var (
+	signatureAlgorithms = []string{"sha1", "sha256"}
+	signatureEncodings  = []string{"hex", "base64"}
+)
+
+const (
+	signaturePrefixFmt    = `[^"$\\\s]*`
+	signaturePrefixErrMsg = `must not contain '"', '$', '\' or whitespaces`
+)
+
+var signaturePrefixRegexp = regexp.MustCompile("^" + signaturePrefixFmt + "$")
+
+const (
+	signaturePayloadFmt    = `([^"$\\\s{}]|\{timestamp\}|\{body\})*`
+	signaturePayloadErrMsg = `must not contain '"', '$', '\', whitespaces or braces other than the placeholders {timestamp} and {body}`
+)
+
+var signaturePayloadRegexp = regexp.MustCompile("^" + signaturePayloadFmt + "$")
+
+func validateSignatureVerification(sv *v1.SignatureVerification, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if sv.Header == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("header"), ""))
+	} else {
+		allErrs = append(allErrs, validateHeaderName(sv.Header, fieldPath.Child("header"))...)
+	}
+
+	if sv.Algorithm != "" && !slices.Contains(signatureAlgorithms, sv.Algorithm) {
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("algorithm"), sv.Algorithm, signatureAlgorithms))
+	}
+
+	if sv.Encoding != "" && !slices.Contains(signatureEncodings, sv.Encoding) {
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("encoding"), sv.Encoding, signatureEncodings))
+	}
+
+	if !signaturePrefixRegexp.MatchString(sv.Prefix) {
+		msg := validation.RegexError(signaturePrefixErrMsg, signaturePrefixFmt, "sha256=", "v1=")
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("prefix"), sv.Prefix, msg))
+	}
+
+	if sv.Secret == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("secret"), ""))
+	} else {
+		allErrs = append(allErrs, validateNamespacedSecretName(sv.Secret, fieldPath.Child("secret"))...)
+	}
+
+	if sv.TimestampHeader != "" {
+		allErrs = append(allErrs, validateHeaderName(sv.TimestampHeader, fieldPath.Child("timestampHeader"))...)
+	} else if sv.TimestampTolerance != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("timestampTolerance"), "requires timestampHeader"))
+	}
+	allErrs = append(allErrs, validateTime(sv.TimestampTolerance, fieldPath.Child("timestampTolerance"))...)
+
+	if sv.SignedPayload != "" {
+		allErrs = append(allErrs, validateSignedPayload(sv.SignedPayload, sv.TimestampHeader != "", fieldPath.Child("signedPayload"))...)
+	}
+
+	return allErrs
+}
+
+// validateSignedPayload validates the format of the signed string. The timestamp must be signed if it is checked,
+// so that a request can't be replayed with a new timestamp.
+func validateSignedPayload(payload string, hasTimestamp bool, fieldPath *field.Path) field.ErrorList {
+	if !signaturePayloadRegexp.MatchString(payload) {
+		msg := validation.RegexError(signaturePayloadErrMsg, signaturePayloadFmt, "v0:{timestamp}:{body}", "{timestamp}.{body}")
+		return field.ErrorList{field.Invalid(fieldPath, payload, msg)}
+	}
+
+	allErrs := field.ErrorList{}
+	if strings.Count(payload, "{body}") != 1 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, payload, "must contain {body} once"))
+	}
+
+	timestamps := strings.Count(payload, "{timestamp}")
+	if hasTimestamp && timestamps != 1 {
+		allErrs = append(allErrs, field.Invalid(fieldPath, payload, "must contain {timestamp} once if timestampHeader is set"))
+	} else if !hasTimestamp && timestamps > 0 {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "{timestamp} requires timestampHeader"))
+	}
+
+	return allErrs
+}
+
+var challengeTypes = []string{"javascript", "cookie"}
+
+func validateChallenge(challenge *v1.Challenge, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if challenge.Type != "" && !slices.Contains(challengeTypes, challenge.Type) {
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("type"), challenge.Type, challengeTypes))
+	}
+
+	if challenge.Secret == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("secret"), ""))
+	} else {
+		allErrs = append(allErrs, validateNamespacedSecretName(challenge.Secret, fieldPath.Child("secret"))...)
+	}
+
+	if challenge.CookieName != "" {
+		for _, msg := range isCookieName(challenge.CookieName) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("cookieName"), challenge.CookieName, msg))
+		}
+	}
+	allErrs = append(allErrs, validateTime(challenge.CookieLifetime, fieldPath.Child("cookieLifetime"))...)
+
+	for i, ipOrCIDR := range challenge.ExemptCIDRs {
+		allErrs = append(allErrs, validateIPorCIDR(ipOrCIDR, fieldPath.Child("exemptCIDRs").Index(i))...)
+	}
+
+	for i, userAgent := range challenge.ExemptUserAgents {
+		idxPath := fieldPath.Child("exemptUserAgents").Index(i)
+		if userAgent == "" {
+			allErrs = append(allErrs, field.Required(idxPath, ""))
+			continue
+		}
+		allErrs = append(allErrs, validateRegexPath(userAgent, idxPath)...)
+	}
+
+	if challenge.Block != nil {
+		blockPath := fieldPath.Child("block")
+		allErrs = append(allErrs, validatePositiveInt(challenge.Block.Threshold, blockPath.Child("threshold"))...)
+		allErrs = append(allErrs, validateTime(challenge.Block.Interval, blockPath.Child("interval"))...)
+		allErrs = append(allErrs, validateTime(challenge.Block.Duration, blockPath.Child("duration"))...)
 	}
 
 	return allErrs
@@ -585,6 +990,7 @@ var rateLimitKeyVariables = map[string]bool{
 	"request_uri":        true,
 	"uri":                true,
 	"args":               true,
+	"apikey_client_id":   true,
 }
 
 func validateRateLimitKey(key string, fieldPath *field.Path, isPlus bool) field.ErrorList {
@@ -627,6 +1033,83 @@ func validateJWTToken(token string, fieldPath *field.Path) field.ErrorList {
 	return validateSpecialVariable(nVar, fieldPath, true)
 }
 
+func validateJWTAuthorization(authz *v1.JWTAuthorization, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(authz.Audiences) == 0 && len(authz.Issuers) == 0 && len(authz.Claims) == 0 && len(authz.Headers) == 0 {
+		return append(allErrs, field.Required(fieldPath, "must specify at least one of: `audiences`, `issuers`, `claims`, `headers`"))
+	}
+
+	for i, aud := range authz.Audiences {
+		allErrs = append(allErrs, validateJWTClaimValue(aud, fieldPath.Child("audiences").Index(i))...)
+	}
+	for i, iss := range authz.Issuers {
+		allErrs = append(allErrs, validateJWTClaimValue(iss, fieldPath.Child("issuers").Index(i))...)
+	}
+
+	for i, c := range authz.Claims {
+		idxPath := fieldPath.Child("claims").Index(i)
+		allErrs = append(allErrs, validateJWTClaim(c.Claim, idxPath.Child("claim"))...)
+		if len(c.Values) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("values"), ""))
+		}
+		for j, v := range c.Values {
+			allErrs = append(allErrs, validateJWTClaimValue(v, idxPath.Child("values").Index(j))...)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, h := range authz.Headers {
+		idxPath := fieldPath.Child("headers").Index(i)
+		if h.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		}
+		allErrs = append(allErrs, validateHeaderName(h.Name, idxPath.Child("name"))...)
+		if seen[strings.ToLower(h.Name)] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), h.Name))
+		}
+		seen[strings.ToLower(h.Name)] = true
+		allErrs = append(allErrs, validateJWTClaim(h.Claim, idxPath.Child("claim"))...)
+	}
+
+	if authz.ErrorCode != nil && *authz.ErrorCode != 401 && *authz.ErrorCode != 403 {
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("errorCode"), *authz.ErrorCode, []string{"401", "403"}))
+	}
+
+	return allErrs
+}
+
+const (
+	jwtClaimFmt         = `[a-zA-Z0-9_]+(\.[a-zA-Z0-9_]+)*`
+	jwtClaimErrMsg      = "must consist of alphanumeric characters or '_', with nested claims separated by '.'"
+	jwtClaimValueFmt    = `[a-zA-Z0-9._~:/@+=-]+`
+	jwtClaimValueErrMsg = "must consist of alphanumeric characters or '.', '_', '~', ':', '/', '@', '+', '=', '-'"
+)
+
+var (
+	jwtClaimRegexp      = regexp.MustCompile("^" + jwtClaimFmt + "$")
+	jwtClaimValueRegexp = regexp.MustCompile("^" + jwtClaimValueFmt + "$")
+)
+
+func validateJWTClaim(claim string, fieldPath *field.Path) field.ErrorList {
+	if claim == "" {
+		return field.ErrorList{field.Required(fieldPath, "")}
+	}
+	if !jwtClaimRegexp.MatchString(claim) {
+		msg := validation.RegexError(jwtClaimErrMsg, jwtClaimFmt, "sub", "realm_access.roles")
+		return field.ErrorList{field.Invalid(fieldPath, claim, msg)}
+	}
+	return nil
+}
+
+func validateJWTClaimValue(value string, fieldPath *field.Path) field.ErrorList {
+	if !jwtClaimValueRegexp.MatchString(value) {
+		msg := validation.RegexError(jwtClaimValueErrMsg, jwtClaimValueFmt, "admins", "https://idp.example.com/realms/cafe")
+		return field.ErrorList{field.Invalid(fieldPath, value, msg)}
+	}
+	return nil
+}
+
 var validLogLevels = map[string]bool{
 	"info":   true,
 	"notice": true,
@@ -671,6 +1154,201 @@ func validateIPorCIDR(ipOrCIDR string, fieldPath *field.Path) field.ErrorList {
 	return field.ErrorList{field.Invalid(fieldPath, ipOrCIDR, "must be a CIDR or IP")}
 }
 
+var validRetryOnParams = []string{
+	"error",
+	"timeout",
+	"invalid_header",
+	"http_500",
+	"http_502",
+	"http_503",
+	"http_504",
+	"http_403",
+	"http_404",
+	"http_429",
+}
+
+func validateRetry(retry *v1.Retry, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	seen := make(map[string]bool)
+	for i, retryOn := range retry.RetryOn {
+		idxPath := fieldPath.Child("retryOn").Index(i)
+		if !slices.Contains(validRetryOnParams, retryOn) {
+			allErrs = append(allErrs, field.NotSupported(idxPath, retryOn, validRetryOnParams))
+		}
+		if seen[retryOn] {
+			allErrs = append(allErrs, field.Duplicate(idxPath, retryOn))
+		}
+		seen[retryOn] = true
+	}
+
+	allErrs = append(allErrs, validatePositiveIntOrZero(retry.Tries, fieldPath.Child("tries"))...)
+	allErrs = append(allErrs, validateTime(retry.PerTryTimeout, fieldPath.Child("perTryTimeout"))...)
+	allErrs = append(allErrs, validateTime(retry.Timeout, fieldPath.Child("timeout"))...)
+
+	return allErrs
+}
+
+func validateCircuitBreaker(circuitBreaker *v1.CircuitBreaker, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if circuitBreaker.MaxFails == nil && circuitBreaker.FailTimeout == "" && circuitBreaker.Ejection == nil {
+		return append(allErrs, field.Required(fieldPath, "must specify at least one of: `maxFails`, `failTimeout`, `ejection`"))
+	}
+
+	if circuitBreaker.MaxFails != nil {
+		allErrs = append(allErrs, validatePositiveIntOrZero(*circuitBreaker.MaxFails, fieldPath.Child("maxFails"))...)
+	}
+	allErrs = append(allErrs, validateTime(circuitBreaker.FailTimeout, fieldPath.Child("failTimeout"))...)
+
+	if ejection := circuitBreaker.Ejection; ejection != nil {
+		ejectionPath := fieldPath.Child("ejection")
+		if !isPlus {
+			return append(allErrs, field.Forbidden(ejectionPath, "ejection is only supported in NGINX Plus"))
+		}
+		allErrs = append(allErrs, validatePositiveInt(ejection.Consecutive5xx, ejectionPath.Child("consecutive5xx"))...)
+		allErrs = append(allErrs, validateTime(ejection.Interval, ejectionPath.Child("interval"))...)
+		if ejection.Path != "" {
+			allErrs = append(allErrs, validatePath(ejection.Path, ejectionPath.Child("path"))...)
+		}
+	}
+
+	return allErrs
+}
+
+var validRequestMethods = []string{
+	"GET",
+	"HEAD",
+	"POST",
+	"PUT",
+	"DELETE",
+	"CONNECT",
+	"OPTIONS",
+	"TRACE",
+	"PATCH",
+}
+
+// maxURILengthLimit is the largest repetition count of a PCRE quantifier.
+const maxURILengthLimit = 65535
+
+func validateRequestLimits(limits *v1.RequestLimits, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if limits.MaxBodySize == "" && limits.HeaderBufferSize == "" && limits.LargeHeaderBuffers == nil &&
+		limits.BodyTimeout == "" && limits.HeaderTimeout == "" && len(limits.AllowedMethods) == 0 && limits.MaxURILength == nil {
+		return append(allErrs, field.Required(fieldPath, "must specify at least one limit"))
+	}
+
+	allErrs = append(allErrs, validateOffset(limits.MaxBodySize, fieldPath.Child("maxBodySize"))...)
+	allErrs = append(allErrs, validateSize(limits.HeaderBufferSize, fieldPath.Child("headerBufferSize"))...)
+	allErrs = append(allErrs, validateBuffer(limits.LargeHeaderBuffers, fieldPath.Child("largeHeaderBuffers"))...)
+	allErrs = append(allErrs, validateTime(limits.BodyTimeout, fieldPath.Child("bodyTimeout"))...)
+	allErrs = append(allErrs, validateTime(limits.HeaderTimeout, fieldPath.Child("headerTimeout"))...)
+
+	seen := make(map[string]bool)
+	for i, method := range limits.AllowedMethods {
+		idxPath := fieldPath.Child("allowedMethods").Index(i)
+		if !slices.Contains(validRequestMethods, method) {
+			allErrs = append(allErrs, field.NotSupported(idxPath, method, validRequestMethods))
+		}
+		if seen[method] {
+			allErrs = append(allErrs, field.Duplicate(idxPath, method))
+		}
+		seen[method] = true
+	}
+
+	if limits.MaxURILength != nil && (*limits.MaxURILength < 1 || *limits.MaxURILength > maxURILengthLimit) {
+		msg := fmt.Sprintf("must be in the range 1..%d", maxURILengthLimit)
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxURILength"), *limits.MaxURILength, msg))
+	}
+
+	return allErrs
+}
+
+func validateHeaders(headers *v1.Headers, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if headers.Request == nil && headers.Response == nil {
+		return append(allErrs, field.Required(fieldPath, "must specify at least one of: `request`, `response`"))
+	}
+
+	if request := headers.Request; request != nil {
+		requestPath := fieldPath.Child("request")
+		seen := make(map[string]bool)
+		for i, h := range request.Set {
+			idxPath := requestPath.Child("set").Index(i)
+			allErrs = append(allErrs, validatePolicyHeader(h, idxPath, isPlus)...)
+			if seen[strings.ToLower(h.Name)] {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), h.Name))
+			}
+			seen[strings.ToLower(h.Name)] = true
+		}
+		for i, name := range request.Remove {
+			idxPath := requestPath.Child("remove").Index(i)
+			allErrs = append(allErrs, validateHeaderName(name, idxPath)...)
+			if seen[strings.ToLower(name)] {
+				allErrs = append(allErrs, field.Duplicate(idxPath, name))
+			}
+			seen[strings.ToLower(name)] = true
+		}
+	}
+
+	if response := headers.Response; response != nil {
+		responsePath := fieldPath.Child("response")
+		for i, h := range response.Add {
+			allErrs = append(allErrs, validatePolicyHeader(h.PolicyHeader, responsePath.Child("add").Index(i), isPlus)...)
+		}
+		for i, name := range response.Hide {
+			allErrs = append(allErrs, validateHeaderName(name, responsePath.Child("hide").Index(i))...)
+		}
+	}
+
+	return allErrs
+}
+
+func validatePolicyHeader(h v1.PolicyHeader, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if h.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), ""))
+	}
+	allErrs = append(allErrs, validateHeaderName(h.Name, fieldPath.Child("name"))...)
+	allErrs = append(allErrs, validateEscapedStringWithVariables(h.Value, fieldPath.Child("value"),
+		actionProxyHeaderSpecialVariables, actionProxyHeaderVariables, isPlus)...)
+
+	if valueMap := h.ValueMap; valueMap != nil {
+		valueMapPath := fieldPath.Child("valueMap")
+		allErrs = append(allErrs, validateHeaderValueMapVariable(valueMap.Variable, valueMapPath.Child("variable"), isPlus)...)
+		if len(valueMap.Values) == 0 {
+			allErrs = append(allErrs, field.Required(valueMapPath.Child("values"), ""))
+		}
+		for i, v := range valueMap.Values {
+			idxPath := valueMapPath.Child("values").Index(i)
+			for _, msg := range isValidMatchValue(v.Match) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("match"), v.Match, msg))
+			}
+			allErrs = append(allErrs, validateEscapedStringWithVariables(v.Value, idxPath.Child("value"),
+				actionProxyHeaderSpecialVariables, actionProxyHeaderVariables, isPlus)...)
+		}
+	}
+
+	return allErrs
+}
+
+var headerValueMapSpecialVariables = []string{"$arg_", "$http_", "$cookie_"}
+
+func validateHeaderValueMapVariable(variable string, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	if variable == "" {
+		return field.ErrorList{field.Required(fieldPath, "")}
+	}
+	for _, specialVar := range headerValueMapSpecialVariables {
+		if strings.HasPrefix(variable, specialVar) {
+			return validateSpecialVariable(strings.TrimPrefix(variable, "$"), fieldPath, isPlus)
+		}
+	}
+	return validateVariableName(variable, fieldPath)
+}
+
 func validatePositiveInt(n int, fieldPath *field.Path) field.ErrorList {
 	if n <= 0 {
 		return field.ErrorList{field.Invalid(fieldPath, n, "must be positive")}
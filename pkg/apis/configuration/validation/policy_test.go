@@ -95,13 +95,104 @@ func TestValidatePolicy_JWTIsNotValidOn(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "empty authorization",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					JWTAuth: &v1.JWTAuth{
+						Realm:         "My Product API",
+						Secret:        "my-jwk",
+						Authorization: &v1.JWTAuthorization{},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid audience in authorization",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					JWTAuth: &v1.JWTAuth{
+						Realm:  "My Product API",
+						Secret: "my-jwk",
+						Authorization: &v1.JWTAuthorization{
+							Audiences: []string{"cafe,api"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid claim in authorization",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					JWTAuth: &v1.JWTAuth{
+						Realm:    "My Product API",
+						JwksURI:  "https://myjwksuri.com",
+						KeyCache: "1h",
+						Authorization: &v1.JWTAuthorization{
+							Claims: []v1.JWTClaimRequirement{
+								{Claim: "cognito:groups", Values: []string{"admins"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "missing claim values in authorization",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					JWTAuth: &v1.JWTAuth{
+						Realm:  "My Product API",
+						Secret: "my-jwk",
+						Authorization: &v1.JWTAuthorization{
+							Claims: []v1.JWTClaimRequirement{
+								{Claim: "groups"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "duplicate header in authorization",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					JWTAuth: &v1.JWTAuth{
+						Realm:  "My Product API",
+						Secret: "my-jwk",
+						Authorization: &v1.JWTAuthorization{
+							Headers: []v1.JWTClaimHeader{
+								{Name: "X-User", Claim: "sub"},
+								{Name: "x-user", Claim: "email"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid error code in authorization",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					JWTAuth: &v1.JWTAuth{
+						Realm:  "My Product API",
+						Secret: "my-jwk",
+						Authorization: &v1.JWTAuthorization{
+							Issuers:   []string{"https://idp.example.com"},
+							ErrorCode: createPointerFromInt(500),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tt {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := ValidatePolicy(tc.policy, true, false, false)
+			err := ValidatePolicy(tc.policy, true, false, false, false)
 			if err == nil {
 				t.Errorf("got no errors on invalid JWTAuth policy spec input")
 			}
@@ -164,13 +255,36 @@ func TestValidatePolicy_IsValidOnJWTPolicy(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with authorization",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					JWTAuth: &v1.JWTAuth{
+						Realm:    "My Product API",
+						KeyCache: "1h",
+						JwksURI:  "https://login.mydomain.com/keys",
+						Authorization: &v1.JWTAuthorization{
+							Audiences: []string{"cafe"},
+							Issuers:   []string{"https://login.mydomain.com/realms/cafe"},
+							Claims: []v1.JWTClaimRequirement{
+								{Claim: "realm_access.roles", Values: []string{"admins", "baristas"}},
+							},
+							Headers: []v1.JWTClaimHeader{
+								{Name: "X-User", Claim: "sub"},
+							},
+							ErrorCode: createPointerFromInt(401),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tt {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := ValidatePolicy(tc.policy, true, false, false)
+			err := ValidatePolicy(tc.policy, true, false, false, false)
 			if err != nil {
 				t.Errorf("want no errors, got %+v\n", err)
 			}
@@ -239,7 +353,7 @@ func TestValidatePolicy_RequiresKeyCacheValueForJWTPolicy(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := ValidatePolicy(tc.policy, true, false, false)
+			err := ValidatePolicy(tc.policy, true, false, false, false)
 			if err != nil {
 				t.Errorf("got error on valid JWT policy: %+v\n", err)
 			}
@@ -255,8 +369,22 @@ func TestValidatePolicy_PassesOnValidInput(t *testing.T) {
 		isPlus           bool
 		enableOIDC       bool
 		enableAppProtect bool
+		enableGeoIP2     bool
 		msg              string
 	}{
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					AccessControl: &v1.AccessControl{
+						Geo: &v1.GeoAccessControl{
+							AllowCountries: []string{"US", "CA"},
+						},
+					},
+				},
+			},
+			enableGeoIP2: true,
+			msg:          "geo access control with the GeoIP2 module",
+		},
 		{
 			policy: &v1.Policy{
 				Spec: v1.PolicySpec{
@@ -321,7 +449,7 @@ func TestValidatePolicy_PassesOnValidInput(t *testing.T) {
 		},
 	}
 	for _, test := range tests {
-		err := ValidatePolicy(test.policy, test.isPlus, test.enableOIDC, test.enableAppProtect)
+		err := ValidatePolicy(test.policy, test.isPlus, test.enableOIDC, test.enableAppProtect, test.enableGeoIP2)
 		if err != nil {
 			t.Errorf("ValidatePolicy() returned error %v for valid input for the case of %v", err, test.msg)
 		}
@@ -335,8 +463,21 @@ func TestValidatePolicy_FailsOnInvalidInput(t *testing.T) {
 		isPlus           bool
 		enableOIDC       bool
 		enableAppProtect bool
+		enableGeoIP2     bool
 		msg              string
 	}{
+		{
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					AccessControl: &v1.AccessControl{
+						Geo: &v1.GeoAccessControl{
+							DenyCountries: []string{"US"},
+						},
+					},
+				},
+			},
+			msg: "geo access control without the GeoIP2 module",
+		},
 		{
 			policy: &v1.Policy{
 				Spec: v1.PolicySpec{},
@@ -491,7 +632,7 @@ func TestValidatePolicy_FailsOnInvalidInput(t *testing.T) {
 		},
 	}
 	for _, test := range tests {
-		err := ValidatePolicy(test.policy, test.isPlus, test.enableOIDC, test.enableAppProtect)
+		err := ValidatePolicy(test.policy, test.isPlus, test.enableOIDC, test.enableAppProtect, test.enableGeoIP2)
 		if err == nil {
 			t.Errorf("ValidatePolicy() returned no error for invalid input")
 		}
@@ -513,10 +654,21 @@ func TestValidateAccessControl_PassesOnValidInput(t *testing.T) {
 		{
 			Deny: []string{"127.0.0.1"},
 		},
+		{
+			Geo: &v1.GeoAccessControl{
+				AllowCountries: []string{"US", "CA"},
+				AllowASNs:      []int{13335},
+			},
+		},
+		{
+			Geo: &v1.GeoAccessControl{
+				DenyASNs: []int{4200000000},
+			},
+		},
 	}
 
 	for _, input := range validInput {
-		allErrs := validateAccessControl(input, field.NewPath("accessControl"))
+		allErrs := validateAccessControl(input, field.NewPath("accessControl"), true)
 		if len(allErrs) > 0 {
 			t.Errorf("validateAccessControl(%+v) returned errors %v for valid input", input, allErrs)
 		}
@@ -555,10 +707,50 @@ func TestValidateAccessControl_FailsOnInvalidInput(t *testing.T) {
 			},
 			msg: "invalid deny",
 		},
+		{
+			accessControl: &v1.AccessControl{
+				Allow: []string{"127.0.0.1"},
+				Geo: &v1.GeoAccessControl{
+					AllowCountries: []string{"US"},
+				},
+			},
+			msg: "both allow and geo are defined",
+		},
+		{
+			accessControl: &v1.AccessControl{
+				Geo: &v1.GeoAccessControl{},
+			},
+			msg: "empty geo",
+		},
+		{
+			accessControl: &v1.AccessControl{
+				Geo: &v1.GeoAccessControl{
+					AllowCountries: []string{"US"},
+					DenyASNs:       []int{13335},
+				},
+			},
+			msg: "both allowed and denied geo rules are defined",
+		},
+		{
+			accessControl: &v1.AccessControl{
+				Geo: &v1.GeoAccessControl{
+					DenyCountries: []string{"usa"},
+				},
+			},
+			msg: "invalid country code",
+		},
+		{
+			accessControl: &v1.AccessControl{
+				Geo: &v1.GeoAccessControl{
+					AllowASNs: []int{0},
+				},
+			},
+			msg: "invalid autonomous system number",
+		},
 	}
 
 	for _, test := range tests {
-		allErrs := validateAccessControl(test.accessControl, field.NewPath("accessControl"))
+		allErrs := validateAccessControl(test.accessControl, field.NewPath("accessControl"), true)
 		if len(allErrs) == 0 {
 			t.Errorf("validateAccessControl() returned no errors for invalid input for the case of %s", test.msg)
 		}
@@ -615,6 +807,21 @@ func TestValidateRateLimit_PassesOnValidInput(t *testing.T) {
 			isPlus: true,
 			msg:    "ratelimit JWT Condition",
 		},
+		{
+			rateLimit: &v1.RateLimit{
+				Rate:     "30r/m",
+				Key:      "${apikey_client_id}",
+				ZoneSize: "10M",
+				Condition: &v1.RateLimitCondition{
+					APIKey: &v1.APIKeyCondition{
+						Tier: "gold",
+					},
+					Default: true,
+				},
+			},
+			isPlus: false,
+			msg:    "ratelimit API key Condition",
+		},
 	}
 
 	for _, test := range tests {
@@ -712,6 +919,32 @@ func TestValidateRateLimit_FailsOnInvalidInput(t *testing.T) {
 			isPlus: true,
 			msg:    "missing JWTCondition",
 		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Condition = &v1.RateLimitCondition{
+					JWT: &v1.JWTCondition{
+						Claim: "sub",
+						Match: "Gold",
+					},
+					APIKey: &v1.APIKeyCondition{
+						Tier: "gold",
+					},
+				}
+			}),
+			isPlus: true,
+			msg:    "both JWT and API key Conditions",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Condition = &v1.RateLimitCondition{
+					APIKey: &v1.APIKeyCondition{
+						Tier: "gold tier",
+					},
+				}
+			}),
+			isPlus: false,
+			msg:    "invalid API key tier",
+		},
 	}
 
 	for _, test := range tests {
@@ -1122,6 +1355,12 @@ func TestValidateIngressMTLS_PassesOnValidInput(t *testing.T) {
 			},
 			msg: "optional parameters",
 		},
+		{
+			ing: &v1.IngressMTLS{
+				ClientCertSecret: "certs/mtls-secret",
+			},
+			msg: "secret in another namespace",
+		},
 	}
 	for _, test := range tests {
 		allErrs := validateIngressMTLS(test.ing, field.NewPath("ingressMTLS"))
@@ -1406,6 +1645,25 @@ func TestValidateOIDC_PassesOnValidOIDC(t *testing.T) {
 			},
 			msg: "no post logout redirect URI",
 		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "http://127.0.0.1:8080/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint: "http://127.0.0.1:8080/realms/master/protocol/openid-connect/token",
+				JWKSURI:       "http://127.0.0.1:8080/realms/master/protocol/openid-connect/certs",
+				RedirectURI:   "/tea/_codexch",
+				LogoutURI:     "/tea/logout",
+				ClientID:      "public-client",
+				PKCEEnable:    true,
+				Session: &v1.OIDCSession{
+					CookieName:     "tea_session",
+					CookieDomain:   ".example.com",
+					CookieSameSite: "strict",
+					Timeout:        "30m",
+					RefreshTimeout: "24h",
+				},
+			},
+			msg: "pkce with session",
+		},
 	}
 
 	for _, test := range tests {
@@ -1626,6 +1884,78 @@ func TestValidateOIDC_FailsOnInvalidOIDC(t *testing.T) {
 			fieldPath: "oidc.zoneSyncLeeway",
 			msg:       "invalid zoneSyncLeeway value",
 		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "http://127.0.0.1:8080/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint: "http://127.0.0.1:8080/realms/master/protocol/openid-connect/token",
+				JWKSURI:       "http://127.0.0.1:8080/realms/master/protocol/openid-connect/certs",
+				ClientID:      "foobar",
+				ClientSecret:  "secret",
+				PKCEEnable:    true,
+			},
+			fieldPath: "oidc.clientSecret",
+			msg:       "client secret with pkce",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "http://127.0.0.1:8080/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint: "http://127.0.0.1:8080/realms/master/protocol/openid-connect/token",
+				JWKSURI:       "http://127.0.0.1:8080/realms/master/protocol/openid-connect/certs",
+				ClientID:      "foobar",
+				ClientSecret:  "secret",
+				RedirectURI:   "/_token",
+			},
+			fieldPath: "oidc.redirectURI",
+			msg:       "redirect URI of an internal location",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "http://127.0.0.1:8080/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint: "http://127.0.0.1:8080/realms/master/protocol/openid-connect/token",
+				JWKSURI:       "http://127.0.0.1:8080/realms/master/protocol/openid-connect/certs",
+				ClientID:      "foobar",
+				ClientSecret:  "secret",
+				RedirectURI:   "/logout",
+			},
+			fieldPath: "oidc.logoutURI",
+			msg:       "same redirect and logout URI",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "http://127.0.0.1:8080/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint: "http://127.0.0.1:8080/realms/master/protocol/openid-connect/token",
+				JWKSURI:       "http://127.0.0.1:8080/realms/master/protocol/openid-connect/certs",
+				ClientID:      "foobar",
+				ClientSecret:  "secret",
+				Session:       &v1.OIDCSession{CookieName: "auth-token"},
+			},
+			fieldPath: "oidc.session.cookieName",
+			msg:       "invalid session cookie name",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "http://127.0.0.1:8080/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint: "http://127.0.0.1:8080/realms/master/protocol/openid-connect/token",
+				JWKSURI:       "http://127.0.0.1:8080/realms/master/protocol/openid-connect/certs",
+				ClientID:      "foobar",
+				ClientSecret:  "secret",
+				Session:       &v1.OIDCSession{CookieSameSite: "Relaxed"},
+			},
+			fieldPath: "oidc.session.cookieSameSite",
+			msg:       "invalid session cookie SameSite",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "http://127.0.0.1:8080/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint: "http://127.0.0.1:8080/realms/master/protocol/openid-connect/token",
+				JWKSURI:       "http://127.0.0.1:8080/realms/master/protocol/openid-connect/certs",
+				ClientID:      "foobar",
+				ClientSecret:  "secret",
+				Session:       &v1.OIDCSession{RefreshTimeout: "1 day"},
+			},
+			fieldPath: "oidc.session.refreshTimeout",
+			msg:       "invalid session refresh timeout",
+		},
 	}
 
 	for _, test := range tests {
@@ -1658,6 +1988,18 @@ func TestValidateAPIKeyPolicy_PassOnValidInput(t *testing.T) {
 				ClientSecret: "secret",
 			},
 		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{
+						"X-API-Key",
+					},
+				},
+				ClientSecret:   "secret",
+				ClientIDHeader: "X-Client-ID",
+			},
+			msg: "client id header",
+		},
 	}
 
 	for _, test := range tests {
@@ -1724,6 +2066,18 @@ func TestValidateAPIKeyPolicy_FailsOnInvalidInput(t *testing.T) {
 			},
 			msg: "invalid secret name",
 		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{
+						"X-API-Key",
+					},
+				},
+				ClientSecret:   "secret",
+				ClientIDHeader: "X Client",
+			},
+			msg: "invalid client id header",
+		},
 		{
 			apiKey: &v1.APIKey{
 				ClientSecret: "secret_1",
@@ -1744,6 +2098,909 @@ func TestValidateAPIKeyPolicy_FailsOnInvalidInput(t *testing.T) {
 	}
 }
 
+func TestValidateRetry_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		retry *v1.Retry
+		msg   string
+	}{
+		{
+			retry: &v1.Retry{},
+			msg:   "empty retry",
+		},
+		{
+			retry: &v1.Retry{
+				RetryOn:       []string{"error", "timeout", "http_502", "http_503"},
+				Tries:         3,
+				PerTryTimeout: "5s",
+				Timeout:       "30s",
+				NonIdempotent: true,
+			},
+			msg: "all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRetry(test.retry, field.NewPath("retry"))
+		if len(allErrs) != 0 {
+			t.Errorf("validateRetry() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateRetry_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		retry *v1.Retry
+		msg   string
+	}{
+		{
+			retry: &v1.Retry{RetryOn: []string{"http_501"}},
+			msg:   "invalid retryOn",
+		},
+		{
+			retry: &v1.Retry{RetryOn: []string{"non_idempotent"}},
+			msg:   "non_idempotent in retryOn",
+		},
+		{
+			retry: &v1.Retry{RetryOn: []string{"error", "error"}},
+			msg:   "duplicate retryOn",
+		},
+		{
+			retry: &v1.Retry{Tries: -1},
+			msg:   "negative tries",
+		},
+		{
+			retry: &v1.Retry{PerTryTimeout: "5 s"},
+			msg:   "invalid perTryTimeout",
+		},
+		{
+			retry: &v1.Retry{Timeout: "-1s"},
+			msg:   "invalid timeout",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRetry(test.retry, field.NewPath("retry"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateRetry() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateCircuitBreaker_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		circuitBreaker *v1.CircuitBreaker
+		isPlus         bool
+		msg            string
+	}{
+		{
+			circuitBreaker: &v1.CircuitBreaker{
+				MaxFails:    createPointerFromInt(0),
+				FailTimeout: "30s",
+			},
+			msg: "max fails and fail timeout",
+		},
+		{
+			circuitBreaker: &v1.CircuitBreaker{
+				Ejection: &v1.CircuitBreakerEjection{
+					Consecutive5xx: 3,
+					Interval:       "10s",
+					Path:           "/healthz",
+				},
+			},
+			isPlus: true,
+			msg:    "ejection",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCircuitBreaker(test.circuitBreaker, field.NewPath("circuitBreaker"), test.isPlus)
+		if len(allErrs) != 0 {
+			t.Errorf("validateCircuitBreaker() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCircuitBreaker_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		circuitBreaker *v1.CircuitBreaker
+		isPlus         bool
+		msg            string
+	}{
+		{
+			circuitBreaker: &v1.CircuitBreaker{},
+			isPlus:         true,
+			msg:            "empty circuit breaker",
+		},
+		{
+			circuitBreaker: &v1.CircuitBreaker{MaxFails: createPointerFromInt(-1)},
+			isPlus:         true,
+			msg:            "negative max fails",
+		},
+		{
+			circuitBreaker: &v1.CircuitBreaker{FailTimeout: "ten seconds"},
+			isPlus:         true,
+			msg:            "invalid fail timeout",
+		},
+		{
+			circuitBreaker: &v1.CircuitBreaker{
+				Ejection: &v1.CircuitBreakerEjection{Consecutive5xx: 3},
+			},
+			isPlus: false,
+			msg:    "ejection in OSS",
+		},
+		{
+			circuitBreaker: &v1.CircuitBreaker{
+				Ejection: &v1.CircuitBreakerEjection{},
+			},
+			isPlus: true,
+			msg:    "missing consecutive5xx",
+		},
+		{
+			circuitBreaker: &v1.CircuitBreaker{
+				Ejection: &v1.CircuitBreakerEjection{Consecutive5xx: 3, Path: "healthz"},
+			},
+			isPlus: true,
+			msg:    "invalid path",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCircuitBreaker(test.circuitBreaker, field.NewPath("circuitBreaker"), test.isPlus)
+		if len(allErrs) == 0 {
+			t.Errorf("validateCircuitBreaker() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateHeaders_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		headers *v1.Headers
+		msg     string
+	}{
+		{
+			headers: &v1.Headers{
+				Request: &v1.HeadersRequest{
+					Set:    []v1.PolicyHeader{{Name: "X-Client-Addr", Value: "${remote_addr}"}},
+					Remove: []string{"X-Debug"},
+				},
+			},
+			msg: "request headers",
+		},
+		{
+			headers: &v1.Headers{
+				Response: &v1.HeadersResponse{
+					Add: []v1.PolicyAddHeader{
+						{PolicyHeader: v1.PolicyHeader{Name: "Strict-Transport-Security", Value: "max-age=31536000"}, Always: true},
+						{PolicyHeader: v1.PolicyHeader{Name: "Content-Security-Policy", Value: "default-src 'self'"}},
+					},
+					Hide: []string{"X-Powered-By"},
+				},
+			},
+			msg: "response headers",
+		},
+		{
+			headers: &v1.Headers{
+				Response: &v1.HeadersResponse{
+					Add: []v1.PolicyAddHeader{
+						{
+							PolicyHeader: v1.PolicyHeader{
+								Name: "Access-Control-Allow-Origin",
+								ValueMap: &v1.HeaderValueMap{
+									Variable: "$http_origin",
+									Values:   []v1.HeaderMapValue{{Match: "https://example.com", Value: "https://example.com"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			msg: "value map",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateHeaders(test.headers, field.NewPath("headers"), false)
+		if len(allErrs) > 0 {
+			t.Errorf("validateHeaders() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateRequestLimits_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		limits *v1.RequestLimits
+		msg    string
+	}{
+		{
+			limits: &v1.RequestLimits{MaxBodySize: "10m"},
+			msg:    "max body size",
+		},
+		{
+			limits: &v1.RequestLimits{
+				MaxBodySize:        "0",
+				HeaderBufferSize:   "2k",
+				LargeHeaderBuffers: &v1.UpstreamBuffers{Number: 4, Size: "16k"},
+				BodyTimeout:        "30s",
+				HeaderTimeout:      "1m",
+				AllowedMethods:     []string{"GET", "HEAD", "POST"},
+				MaxURILength:       createPointerFromInt(2048),
+			},
+			msg: "all limits",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRequestLimits(test.limits, field.NewPath("requestLimits"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateRequestLimits() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateRequestLimits_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		limits *v1.RequestLimits
+		msg    string
+	}{
+		{
+			limits: &v1.RequestLimits{},
+			msg:    "no limits",
+		},
+		{
+			limits: &v1.RequestLimits{MaxBodySize: "10mb"},
+			msg:    "invalid max body size",
+		},
+		{
+			limits: &v1.RequestLimits{HeaderBufferSize: "1g"},
+			msg:    "invalid header buffer size",
+		},
+		{
+			limits: &v1.RequestLimits{LargeHeaderBuffers: &v1.UpstreamBuffers{Number: 0, Size: "8k"}},
+			msg:    "invalid large header buffers",
+		},
+		{
+			limits: &v1.RequestLimits{BodyTimeout: "30 seconds"},
+			msg:    "invalid body timeout",
+		},
+		{
+			limits: &v1.RequestLimits{AllowedMethods: []string{"get"}},
+			msg:    "unsupported method",
+		},
+		{
+			limits: &v1.RequestLimits{AllowedMethods: []string{"GET", "GET"}},
+			msg:    "duplicate method",
+		},
+		{
+			limits: &v1.RequestLimits{MaxURILength: createPointerFromInt(0)},
+			msg:    "zero max URI length",
+		},
+		{
+			limits: &v1.RequestLimits{MaxURILength: createPointerFromInt(65536)},
+			msg:    "too large max URI length",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRequestLimits(test.limits, field.NewPath("requestLimits"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateRequestLimits() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateOAuth2Introspection_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		introspection *v1.OAuth2Introspection
+		msg           string
+	}{
+		{
+			introspection: &v1.OAuth2Introspection{
+				Endpoint:     "https://idp.example.com/oauth2/introspect",
+				ClientSecret: "introspection-secret",
+			},
+			msg: "endpoint and client secret",
+		},
+		{
+			introspection: &v1.OAuth2Introspection{
+				Endpoint:     "http://idp.default.svc:8080/introspect",
+				ClientSecret: "introspection-secret",
+				Scopes:       []string{"orders:read", "orders:write"},
+				Audiences:    []string{"https://api.example.com"},
+				CacheTimeout: "5m",
+				Headers: []v1.OAuth2IntrospectionHeader{
+					{Name: "X-User", Field: "sub"},
+					{Name: "X-Tenant", Field: "ext.tenant"},
+				},
+			},
+			msg: "all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateOAuth2Introspection(test.introspection, field.NewPath("oauth2Introspection"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateOAuth2Introspection() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateOAuth2Introspection_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		introspection *v1.OAuth2Introspection
+		msg           string
+	}{
+		{
+			introspection: &v1.OAuth2Introspection{
+				ClientSecret: "introspection-secret",
+			},
+			msg: "missing endpoint",
+		},
+		{
+			introspection: &v1.OAuth2Introspection{
+				Endpoint:     "ftp://idp.example.com/introspect",
+				ClientSecret: "introspection-secret",
+			},
+			msg: "unsupported scheme of endpoint",
+		},
+		{
+			introspection: &v1.OAuth2Introspection{
+				Endpoint: "https://idp.example.com/introspect",
+			},
+			msg: "missing client secret",
+		},
+		{
+			introspection: &v1.OAuth2Introspection{
+				Endpoint:     "https://idp.example.com/introspect",
+				ClientSecret: "introspection-secret",
+				Scopes:       []string{"orders read"},
+			},
+			msg: "invalid scope",
+		},
+		{
+			introspection: &v1.OAuth2Introspection{
+				Endpoint:     "https://idp.example.com/introspect",
+				ClientSecret: "introspection-secret",
+				Audiences:    []string{"$api"},
+			},
+			msg: "invalid audience",
+		},
+		{
+			introspection: &v1.OAuth2Introspection{
+				Endpoint:     "https://idp.example.com/introspect",
+				ClientSecret: "introspection-secret",
+				CacheTimeout: "5 minutes",
+			},
+			msg: "invalid cache timeout",
+		},
+		{
+			introspection: &v1.OAuth2Introspection{
+				Endpoint:     "https://idp.example.com/introspect",
+				ClientSecret: "introspection-secret",
+				Headers: []v1.OAuth2IntrospectionHeader{
+					{Name: "X-User", Field: "sub"},
+					{Name: "x-user", Field: "username"},
+				},
+			},
+			msg: "duplicate header",
+		},
+		{
+			introspection: &v1.OAuth2Introspection{
+				Endpoint:     "https://idp.example.com/introspect",
+				ClientSecret: "introspection-secret",
+				Headers: []v1.OAuth2IntrospectionHeader{
+					{Name: "X-User", Field: "user-name"},
+				},
+			},
+			msg: "invalid field of header",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateOAuth2Introspection(test.introspection, field.NewPath("oauth2Introspection"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateOAuth2Introspection() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateSignatureVerification_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		sv  *v1.SignatureVerification
+		msg string
+	}{
+		{
+			sv: &v1.SignatureVerification{
+				Header: "X-Hub-Signature-256",
+				Secret: "webhook-secret",
+			},
+			msg: "header and secret",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:             "X-Signature",
+				Algorithm:          "sha1",
+				Encoding:           "base64",
+				Prefix:             "v1=",
+				Secret:             "webhook-secret",
+				TimestampHeader:    "X-Signature-Timestamp",
+				TimestampTolerance: "30s",
+				SignedPayload:      "v0:{timestamp}:{body}",
+			},
+			msg: "all fields",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:        "X-Signature",
+				Secret:        "webhook-secret",
+				SignedPayload: "{body}",
+			},
+			msg: "signed body without timestamp",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateSignatureVerification(test.sv, field.NewPath("signatureVerification"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateSignatureVerification() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateSignatureVerification_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		sv  *v1.SignatureVerification
+		msg string
+	}{
+		{
+			sv: &v1.SignatureVerification{
+				Secret: "webhook-secret",
+			},
+			msg: "missing header",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header: "X Signature",
+				Secret: "webhook-secret",
+			},
+			msg: "invalid header",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header: "X-Signature",
+			},
+			msg: "missing secret",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:    "X-Signature",
+				Algorithm: "md5",
+				Secret:    "webhook-secret",
+			},
+			msg: "unsupported algorithm",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:   "X-Signature",
+				Encoding: "base32",
+				Secret:   "webhook-secret",
+			},
+			msg: "unsupported encoding",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header: "X-Signature",
+				Prefix: "$sha256=",
+				Secret: "webhook-secret",
+			},
+			msg: "invalid prefix",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:             "X-Signature",
+				Secret:             "webhook-secret",
+				TimestampTolerance: "5m",
+			},
+			msg: "timestamp tolerance without timestamp header",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:             "X-Signature",
+				Secret:             "webhook-secret",
+				TimestampHeader:    "X-Signature-Timestamp",
+				TimestampTolerance: "5 minutes",
+			},
+			msg: "invalid timestamp tolerance",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:          "X-Signature",
+				Secret:          "webhook-secret",
+				TimestampHeader: "X-Signature-Timestamp",
+				SignedPayload:   "{body}",
+			},
+			msg: "timestamp header without signed timestamp",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:        "X-Signature",
+				Secret:        "webhook-secret",
+				SignedPayload: "{timestamp}.{body}",
+			},
+			msg: "signed timestamp without timestamp header",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:          "X-Signature",
+				Secret:          "webhook-secret",
+				TimestampHeader: "X-Signature-Timestamp",
+				SignedPayload:   "{timestamp}",
+			},
+			msg: "signed payload without body",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:          "X-Signature",
+				Secret:          "webhook-secret",
+				TimestampHeader: "X-Signature-Timestamp",
+				SignedPayload:   "{timestamp}.{body}.{body}",
+			},
+			msg: "signed payload with body twice",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:          "X-Signature",
+				Secret:          "webhook-secret",
+				TimestampHeader: "X-Signature-Timestamp",
+				SignedPayload:   "{timestamp}.{host}.{body}",
+			},
+			msg: "signed payload with unknown placeholder",
+		},
+		{
+			sv: &v1.SignatureVerification{
+				Header:          "X-Signature",
+				Secret:          "webhook-secret",
+				TimestampHeader: "X-Signature-Timestamp",
+				SignedPayload:   "$host:{timestamp}:{body}",
+			},
+			msg: "signed payload with variable",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateSignatureVerification(test.sv, field.NewPath("signatureVerification"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateSignatureVerification() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateBandwidthLimit_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		bandwidthLimit *v1.BandwidthLimit
+		msg            string
+	}{
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate: "100k",
+			},
+			msg: "rate only",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate:      "100k",
+				RateAfter: "1m",
+				Key:       "${apikey_client_id}",
+				Rates: []v1.BandwidthLimitRate{
+					{Value: "gold", Rate: "0"},
+					{Value: "silver", Rate: "1m"},
+				},
+			},
+			msg: "all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateBandwidthLimit(test.bandwidthLimit, field.NewPath("bandwidthLimit"), false)
+		if len(allErrs) > 0 {
+			t.Errorf("validateBandwidthLimit() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateBandwidthLimit_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		bandwidthLimit *v1.BandwidthLimit
+		msg            string
+	}{
+		{
+			bandwidthLimit: &v1.BandwidthLimit{},
+			msg:            "missing rate",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate: "100kb/s",
+			},
+			msg: "invalid rate",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate:      "100k",
+				RateAfter: "1g",
+			},
+			msg: "invalid rate after",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate: "100k",
+				Key:  "${http_x_tier}",
+			},
+			msg: "key without rates",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate:  "100k",
+				Rates: []v1.BandwidthLimitRate{{Value: "gold", Rate: "1m"}},
+			},
+			msg: "rates without key",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate:  "100k",
+				Key:   "${remote_user}",
+				Rates: []v1.BandwidthLimitRate{{Value: "gold", Rate: "1m"}},
+			},
+			msg: "invalid variable in key",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate: "100k",
+				Key:  "${http_x_tier}",
+				Rates: []v1.BandwidthLimitRate{
+					{Value: "gold", Rate: "1m"},
+					{Value: "gold", Rate: "2m"},
+				},
+			},
+			msg: "duplicate value",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate:  "100k",
+				Key:   "${http_x_tier}",
+				Rates: []v1.BandwidthLimitRate{{Value: `gold"`, Rate: "1m"}},
+			},
+			msg: "unescaped double quote in value",
+		},
+		{
+			bandwidthLimit: &v1.BandwidthLimit{
+				Rate:  "100k",
+				Key:   "${http_x_tier}",
+				Rates: []v1.BandwidthLimitRate{{Value: "gold"}},
+			},
+			msg: "missing rate of value",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateBandwidthLimit(test.bandwidthLimit, field.NewPath("bandwidthLimit"), false)
+		if len(allErrs) == 0 {
+			t.Errorf("validateBandwidthLimit() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateChallenge_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		challenge *v1.Challenge
+		msg       string
+	}{
+		{
+			challenge: &v1.Challenge{
+				Secret: "challenge-secret",
+			},
+			msg: "secret only",
+		},
+		{
+			challenge: &v1.Challenge{
+				Type:             "cookie",
+				Secret:           "challenge-secret",
+				CookieName:       "my_challenge",
+				CookieLifetime:   "30m",
+				ExemptCIDRs:      []string{"10.0.0.0/8", "192.168.1.1"},
+				ExemptUserAgents: []string{"Googlebot", `^curl/\d+`},
+				Block: &v1.ChallengeBlock{
+					Threshold: 10,
+					Interval:  "30s",
+					Duration:  "1h",
+				},
+			},
+			msg: "all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateChallenge(test.challenge, field.NewPath("challenge"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateChallenge() returned errors %v for valid input for the case of %v", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateChallenge_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		challenge *v1.Challenge
+		msg       string
+	}{
+		{
+			challenge: &v1.Challenge{},
+			msg:       "missing secret",
+		},
+		{
+			challenge: &v1.Challenge{
+				Type:   "captcha",
+				Secret: "challenge-secret",
+			},
+			msg: "unsupported type",
+		},
+		{
+			challenge: &v1.Challenge{
+				Secret:     "challenge-secret",
+				CookieName: "my-challenge",
+			},
+			msg: "invalid cookie name",
+		},
+		{
+			challenge: &v1.Challenge{
+				Secret:         "challenge-secret",
+				CookieLifetime: "1 hour",
+			},
+			msg: "invalid cookie lifetime",
+		},
+		{
+			challenge: &v1.Challenge{
+				Secret:      "challenge-secret",
+				ExemptCIDRs: []string{"10.0.0.0/33"},
+			},
+			msg: "invalid exempt CIDR",
+		},
+		{
+			challenge: &v1.Challenge{
+				Secret:           "challenge-secret",
+				ExemptUserAgents: []string{`Googlebot"`},
+			},
+			msg: "unescaped double quote in exempt user agent",
+		},
+		{
+			challenge: &v1.Challenge{
+				Secret:           "challenge-secret",
+				ExemptUserAgents: []string{""},
+			},
+			msg: "empty exempt user agent",
+		},
+		{
+			challenge: &v1.Challenge{
+				Secret: "challenge-secret",
+				Block:  &v1.ChallengeBlock{},
+			},
+			msg: "missing block threshold",
+		},
+		{
+			challenge: &v1.Challenge{
+				Secret: "challenge-secret",
+				Block: &v1.ChallengeBlock{
+					Threshold: 5,
+					Duration:  "10 minutes",
+				},
+			},
+			msg: "invalid block duration",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateChallenge(test.challenge, field.NewPath("challenge"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateChallenge() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
+func TestValidateHeaders_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		headers *v1.Headers
+		msg     string
+	}{
+		{
+			headers: &v1.Headers{},
+			msg:     "empty headers",
+		},
+		{
+			headers: &v1.Headers{
+				Request: &v1.HeadersRequest{
+					Set: []v1.PolicyHeader{{Name: "X Request", Value: "value"}},
+				},
+			},
+			msg: "invalid header name",
+		},
+		{
+			headers: &v1.Headers{
+				Request: &v1.HeadersRequest{
+					Set: []v1.PolicyHeader{{Name: "X-Request", Value: "${unknown}"}},
+				},
+			},
+			msg: "invalid variable in header value",
+		},
+		{
+			headers: &v1.Headers{
+				Request: &v1.HeadersRequest{
+					Set:    []v1.PolicyHeader{{Name: "X-Request", Value: "value"}},
+					Remove: []string{"x-request"},
+				},
+			},
+			msg: "duplicate header",
+		},
+		{
+			headers: &v1.Headers{
+				Response: &v1.HeadersResponse{
+					Hide: []string{"X:Powered"},
+				},
+			},
+			msg: "invalid hidden header name",
+		},
+		{
+			headers: &v1.Headers{
+				Response: &v1.HeadersResponse{
+					Add: []v1.PolicyAddHeader{
+						{
+							PolicyHeader: v1.PolicyHeader{
+								Name:     "X-Scheme",
+								ValueMap: &v1.HeaderValueMap{Variable: "$unknown", Values: []v1.HeaderMapValue{{Match: "a", Value: "b"}}},
+							},
+						},
+					},
+				},
+			},
+			msg: "invalid value map variable",
+		},
+		{
+			headers: &v1.Headers{
+				Response: &v1.HeadersResponse{
+					Add: []v1.PolicyAddHeader{
+						{
+							PolicyHeader: v1.PolicyHeader{
+								Name:     "X-Scheme",
+								ValueMap: &v1.HeaderValueMap{Variable: "$scheme"},
+							},
+						},
+					},
+				},
+			},
+			msg: "value map without values",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateHeaders(test.headers, field.NewPath("headers"), false)
+		if len(allErrs) == 0 {
+			t.Errorf("validateHeaders() returned no errors for invalid input for the case of %v", test.msg)
+		}
+	}
+}
+
 func TestValidateOIDCScope_ErrorsOnInvalidInput(t *testing.T) {
 	t.Parallel()
 
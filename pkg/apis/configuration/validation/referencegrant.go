@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"slices"
+
+	v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var (
+	referenceGrantFromKinds = []string{
+		v1.ReferenceGrantKindVirtualServer,
+		v1.ReferenceGrantKindVirtualServerRoute,
+		v1.ReferenceGrantKindIngress,
+		v1.ReferenceGrantKindPolicy,
+	}
+	referenceGrantToKinds = []string{
+		v1.ReferenceGrantKindPolicy,
+		v1.ReferenceGrantKindSecret,
+		v1.ReferenceGrantKindService,
+	}
+)
+
+// ValidateReferenceGrant validates a ReferenceGrant.
+func ValidateReferenceGrant(referenceGrant *v1.ReferenceGrant) error {
+	allErrs := validateReferenceGrantSpec(&referenceGrant.Spec, field.NewPath("spec"))
+	return allErrs.ToAggregate()
+}
+
+func validateReferenceGrantSpec(spec *v1.ReferenceGrantSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(spec.From) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("from"), "must include at least one resource"))
+	}
+	for i, f := range spec.From {
+		idxPath := fieldPath.Child("from").Index(i)
+		allErrs = append(allErrs, validateReferenceGrantKind(f.Kind, referenceGrantFromKinds, idxPath.Child("kind"))...)
+
+		if f.Namespace == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("namespace"), ""))
+		} else {
+			for _, msg := range validation.IsDNS1123Label(f.Namespace) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("namespace"), f.Namespace, msg))
+			}
+		}
+	}
+
+	if len(spec.To) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("to"), "must include at least one resource"))
+	}
+	for i, t := range spec.To {
+		idxPath := fieldPath.Child("to").Index(i)
+		allErrs = append(allErrs, validateReferenceGrantKind(t.Kind, referenceGrantToKinds, idxPath.Child("kind"))...)
+
+		if t.Name != "" {
+			for _, msg := range validation.IsDNS1123Subdomain(t.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), t.Name, msg))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateReferenceGrantKind(kind string, validKinds []string, fieldPath *field.Path) field.ErrorList {
+	if kind == "" {
+		return field.ErrorList{field.Required(fieldPath, "")}
+	}
+	if !slices.Contains(validKinds, kind) {
+		return field.ErrorList{field.NotSupported(fieldPath, kind, validKinds)}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"testing"
+
+	v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+)
+
+func TestValidateReferenceGrant_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	referenceGrant := &v1.ReferenceGrant{
+		Spec: v1.ReferenceGrantSpec{
+			From: []v1.ReferenceGrantFrom{
+				{Kind: "VirtualServer", Namespace: "cafe"},
+				{Kind: "Ingress", Namespace: "cafe"},
+				{Kind: "Policy", Namespace: "auth"},
+			},
+			To: []v1.ReferenceGrantTo{
+				{Kind: "Policy"},
+				{Kind: "Secret", Name: "jwk-secret"},
+				{Kind: "Service", Name: "tea-svc"},
+			},
+		},
+	}
+
+	if err := ValidateReferenceGrant(referenceGrant); err != nil {
+		t.Errorf("ValidateReferenceGrant() returned error %v for valid input", err)
+	}
+}
+
+func TestValidateReferenceGrant_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	validFrom := []v1.ReferenceGrantFrom{{Kind: "VirtualServer", Namespace: "cafe"}}
+	validTo := []v1.ReferenceGrantTo{{Kind: "Service"}}
+	tests := []struct {
+		spec v1.ReferenceGrantSpec
+		msg  string
+	}{
+		{
+			spec: v1.ReferenceGrantSpec{To: validTo},
+			msg:  "missing from",
+		},
+		{
+			spec: v1.ReferenceGrantSpec{From: validFrom},
+			msg:  "missing to",
+		},
+		{
+			spec: v1.ReferenceGrantSpec{From: []v1.ReferenceGrantFrom{{Kind: "TransportServer", Namespace: "cafe"}}, To: validTo},
+			msg:  "unsupported from kind",
+		},
+		{
+			spec: v1.ReferenceGrantSpec{From: []v1.ReferenceGrantFrom{{Kind: "VirtualServer"}}, To: validTo},
+			msg:  "missing from namespace",
+		},
+		{
+			spec: v1.ReferenceGrantSpec{From: []v1.ReferenceGrantFrom{{Kind: "VirtualServer", Namespace: "Cafe"}}, To: validTo},
+			msg:  "invalid from namespace",
+		},
+		{
+			spec: v1.ReferenceGrantSpec{From: validFrom, To: []v1.ReferenceGrantTo{{Name: "tea-svc"}}},
+			msg:  "missing to kind",
+		},
+		{
+			spec: v1.ReferenceGrantSpec{From: validFrom, To: []v1.ReferenceGrantTo{{Kind: "ConfigMap"}}},
+			msg:  "unsupported to kind",
+		},
+		{
+			spec: v1.ReferenceGrantSpec{From: validFrom, To: []v1.ReferenceGrantTo{{Kind: "Secret", Name: "default/jwk-secret"}}},
+			msg:  "invalid to name",
+		},
+	}
+
+	for _, test := range tests {
+		if err := ValidateReferenceGrant(&v1.ReferenceGrant{Spec: test.spec}); err == nil {
+			t.Errorf("ValidateReferenceGrant() returned no error for invalid input for the case of %s", test.msg)
+		}
+	}
+}
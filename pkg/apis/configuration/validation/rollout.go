@@ -0,0 +1,72 @@
+package validation
+
+import (
+	v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateRollout validates a Rollout.
+func ValidateRollout(rollout *v1.Rollout, isLatencyMetricsEnabled bool) error {
+	allErrs := validateRolloutSpec(&rollout.Spec, field.NewPath("spec"), isLatencyMetricsEnabled)
+	return allErrs.ToAggregate()
+}
+
+func validateRolloutSpec(spec *v1.RolloutSpec, fieldPath *field.Path, isLatencyMetricsEnabled bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.VirtualServer == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("virtualServer"), ""))
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(spec.VirtualServer) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("virtualServer"), spec.VirtualServer, msg))
+		}
+	}
+
+	allErrs = append(allErrs, validateRoutePath(spec.Route, fieldPath.Child("route"))...)
+
+	if len(spec.Steps) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("steps"), "must include at least one step"))
+	}
+	for i, s := range spec.Steps {
+		idxPath := fieldPath.Child("steps").Index(i)
+		for _, msg := range validation.IsInRange(s.Weight, 0, 100) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("weight"), s.Weight, msg))
+		}
+		allErrs = append(allErrs, validateTime(s.Pause, idxPath.Child("pause"))...)
+	}
+
+	if spec.Analysis != nil {
+		allErrs = append(allErrs, validateRolloutAnalysis(spec.Analysis, fieldPath.Child("analysis"), isLatencyMetricsEnabled)...)
+	}
+
+	return allErrs
+}
+
+func validateRolloutAnalysis(analysis *v1.RolloutAnalysis, fieldPath *field.Path, isLatencyMetricsEnabled bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if analysis.MaxErrorRate == nil && analysis.MaxLatency == "" {
+		allErrs = append(allErrs, field.Required(fieldPath, "must specify at least one of `maxErrorRate` or `maxLatency`"))
+	}
+
+	if analysis.MaxErrorRate != nil {
+		for _, msg := range validation.IsInRange(*analysis.MaxErrorRate, 0, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxErrorRate"), *analysis.MaxErrorRate, msg))
+		}
+	}
+
+	if analysis.MaxLatency != "" {
+		// the response times are only recorded by the latency collector
+		if !isLatencyMetricsEnabled {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("maxLatency"), "requires the latency metrics, enabled with -enable-latency-metrics"))
+		}
+		allErrs = append(allErrs, validateTime(analysis.MaxLatency, fieldPath.Child("maxLatency"))...)
+	}
+
+	if analysis.MinRequests < 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("minRequests"), analysis.MinRequests, "must be non-negative"))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,113 @@
+package validation
+
+import (
+	"testing"
+
+	v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+)
+
+func TestValidateRollout_PassesOnValidInput(t *testing.T) {
+	t.Parallel()
+	rollout := &v1.Rollout{
+		Spec: v1.RolloutSpec{
+			VirtualServer: "cafe",
+			Route:         "/coffee",
+			Steps: []v1.RolloutStep{
+				{Weight: 10, Pause: "5m"},
+				{Weight: 50},
+				{Weight: 100, Pause: "1h"},
+			},
+			Analysis: &v1.RolloutAnalysis{
+				MaxErrorRate: createPointerFromInt(5),
+				MaxLatency:   "500ms",
+				MinRequests:  100,
+			},
+		},
+	}
+
+	if err := ValidateRollout(rollout, true); err != nil {
+		t.Errorf("ValidateRollout() returned error %v for valid input", err)
+	}
+}
+
+func TestValidateRollout_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	validSteps := []v1.RolloutStep{{Weight: 10}}
+	tests := []struct {
+		spec v1.RolloutSpec
+		msg  string
+	}{
+		{
+			spec: v1.RolloutSpec{Route: "/coffee", Steps: validSteps},
+			msg:  "missing virtualServer",
+		},
+		{
+			spec: v1.RolloutSpec{VirtualServer: "cafe", Route: "coffee", Steps: validSteps},
+			msg:  "invalid route",
+		},
+		{
+			spec: v1.RolloutSpec{VirtualServer: "cafe", Route: "/coffee"},
+			msg:  "missing steps",
+		},
+		{
+			spec: v1.RolloutSpec{VirtualServer: "cafe", Route: "/coffee", Steps: []v1.RolloutStep{{Weight: 101}}},
+			msg:  "weight out of range",
+		},
+		{
+			spec: v1.RolloutSpec{VirtualServer: "cafe", Route: "/coffee", Steps: []v1.RolloutStep{{Weight: 10, Pause: "5x"}}},
+			msg:  "invalid pause",
+		},
+		{
+			spec: v1.RolloutSpec{VirtualServer: "cafe", Route: "/coffee", Steps: validSteps, Analysis: &v1.RolloutAnalysis{}},
+			msg:  "analysis without thresholds",
+		},
+		{
+			spec: v1.RolloutSpec{
+				VirtualServer: "cafe", Route: "/coffee", Steps: validSteps,
+				Analysis: &v1.RolloutAnalysis{MaxErrorRate: createPointerFromInt(101)},
+			},
+			msg: "error rate out of range",
+		},
+		{
+			spec: v1.RolloutSpec{
+				VirtualServer: "cafe", Route: "/coffee", Steps: validSteps,
+				Analysis: &v1.RolloutAnalysis{MaxLatency: "fast"},
+			},
+			msg: "invalid max latency",
+		},
+		{
+			spec: v1.RolloutSpec{
+				VirtualServer: "cafe", Route: "/coffee", Steps: validSteps,
+				Analysis: &v1.RolloutAnalysis{MaxLatency: "1s", MinRequests: -1},
+			},
+			msg: "negative min requests",
+		},
+	}
+
+	for _, test := range tests {
+		if err := ValidateRollout(&v1.Rollout{Spec: test.spec}, true); err == nil {
+			t.Errorf("ValidateRollout() returned no error for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateRollout_FailsOnMaxLatencyWithoutLatencyMetrics(t *testing.T) {
+	t.Parallel()
+	rollout := &v1.Rollout{
+		Spec: v1.RolloutSpec{
+			VirtualServer: "cafe",
+			Route:         "/coffee",
+			Steps:         []v1.RolloutStep{{Weight: 10}},
+			Analysis:      &v1.RolloutAnalysis{MaxLatency: "500ms"},
+		},
+	}
+
+	if err := ValidateRollout(rollout, false); err == nil {
+		t.Error("ValidateRollout() returned no error for maxLatency without the latency metrics")
+	}
+
+	rollout.Spec.Analysis = &v1.RolloutAnalysis{MaxErrorRate: createPointerFromInt(5)}
+	if err := ValidateRollout(rollout, false); err != nil {
+		t.Errorf("ValidateRollout() returned error %v for maxErrorRate without the latency metrics", err)
+	}
+}
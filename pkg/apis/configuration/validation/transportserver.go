@@ -60,6 +60,50 @@ func (tsv *TransportServerValidator) validateTransportServerSpec(spec *conf_v1.T
 	hostSpecified := spec.Host != ""
 	allErrs = append(allErrs, validateTLS(spec.TLS, isTLSPassthroughListener, fieldPath.Child("tls"), hostSpecified)...)
 
+	if spec.Maintenance != nil {
+		allErrs = append(allErrs, validateMaintenanceAllowList(spec.Maintenance.AllowList, fieldPath.Child("maintenance").Child("allowList"))...)
+	}
+
+	if spec.RateLimit != nil {
+		allErrs = append(allErrs, validateTransportServerRateLimit(spec.RateLimit, fieldPath.Child("rateLimit"), tsv.isPlus)...)
+	}
+
+	return allErrs
+}
+
+// streamRateLimitKeyVariables includes NGINX variables allowed to be used in the key of a TransportServer connection limit.
+var streamRateLimitKeyVariables = map[string]bool{
+	"binary_remote_addr": true,
+	"remote_addr":        true,
+	"server_addr":        true,
+	"server_port":        true,
+}
+
+func validateTransportServerRateLimit(rateLimit *conf_v1.TransportServerRateLimit, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	allErrs := validatePositiveIntOrZero(rateLimit.Connections, fieldPath.Child("connections"))
+
+	if rateLimit.Connections == 0 {
+		if rateLimit.Key != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("key"), "requires connections"))
+		}
+		if rateLimit.ZoneSize != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("zoneSize"), "requires connections"))
+		}
+	} else {
+		if rateLimit.Key != "" {
+			if err := ValidateEscapedString(rateLimit.Key, "${binary_remote_addr}"); err != nil {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("key"), rateLimit.Key, err.Error()))
+			}
+			allErrs = append(allErrs, validateStringWithVariables(rateLimit.Key, fieldPath.Child("key"), nil, streamRateLimitKeyVariables, isPlus)...)
+		}
+		if rateLimit.ZoneSize != "" {
+			allErrs = append(allErrs, validateRateLimitZoneSize(rateLimit.ZoneSize, fieldPath.Child("zoneSize"))...)
+		}
+	}
+
+	allErrs = append(allErrs, validateSize(rateLimit.UploadRate, fieldPath.Child("uploadRate"))...)
+	allErrs = append(allErrs, validateSize(rateLimit.DownloadRate, fieldPath.Child("downloadRate"))...)
+
 	return allErrs
 }
 
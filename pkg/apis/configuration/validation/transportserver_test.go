@@ -63,6 +63,29 @@ func makeTransportServer() conf_v1.TransportServer {
 	}
 }
 
+func TestValidateTransportServer_Maintenance(t *testing.T) {
+	t.Parallel()
+
+	ts := makeTransportServer()
+	ts.Spec.Maintenance = &conf_v1.TransportServerMaintenance{
+		Enable:    true,
+		AllowList: []string{"10.0.0.0/8", "192.168.1.1"},
+	}
+
+	tsv := createTransportServerValidator()
+
+	err := tsv.ValidateTransportServer(&ts)
+	if err != nil {
+		t.Errorf("ValidateTransportServer() returned error %v for valid input", err)
+	}
+
+	ts.Spec.Maintenance.AllowList = []string{"10.0.0.0/33"}
+	err = tsv.ValidateTransportServer(&ts)
+	if err == nil {
+		t.Error("ValidateTransportServer() returned no error for an invalid allow list")
+	}
+}
+
 func TestValidateTransportServer_BackupService(t *testing.T) {
 	t.Parallel()
 
@@ -909,6 +932,104 @@ func TestValidateSessionParameters_FailsOnInvalidInput(t *testing.T) {
 	}
 }
 
+func TestValidateTransportServerRateLimit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		rateLimit *conf_v1.TransportServerRateLimit
+		msg       string
+	}{
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				Connections: 10,
+			},
+			msg: "connections only",
+		},
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				Connections:  10,
+				Key:          "${server_addr}:${binary_remote_addr}",
+				ZoneSize:     "1M",
+				UploadRate:   "100k",
+				DownloadRate: "1m",
+			},
+			msg: "all fields",
+		},
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				DownloadRate: "0",
+			},
+			msg: "rate only",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateTransportServerRateLimit(test.rateLimit, field.NewPath("rateLimit"), false)
+		if len(allErrs) > 0 {
+			t.Errorf("validateTransportServerRateLimit() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateTransportServerRateLimit_FailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		rateLimit *conf_v1.TransportServerRateLimit
+		msg       string
+	}{
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				Connections: -1,
+			},
+			msg: "negative connections",
+		},
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				Key: "${binary_remote_addr}",
+			},
+			msg: "key without connections",
+		},
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				ZoneSize: "10M",
+			},
+			msg: "zone size without connections",
+		},
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				Connections: 10,
+				Key:         "${request_uri}",
+			},
+			msg: "invalid variable in key",
+		},
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				Connections: 10,
+				ZoneSize:    "16k",
+			},
+			msg: "too small zone size",
+		},
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				UploadRate: "100kb",
+			},
+			msg: "invalid upload rate",
+		},
+		{
+			rateLimit: &conf_v1.TransportServerRateLimit{
+				DownloadRate: "1g",
+			},
+			msg: "invalid download rate",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateTransportServerRateLimit(test.rateLimit, field.NewPath("rateLimit"), false)
+		if len(allErrs) == 0 {
+			t.Errorf("validateTransportServerRateLimit() returned no errors for invalid input: %v", test.msg)
+		}
+	}
+}
+
 func TestValidateUDPUpstreamParameter(t *testing.T) {
 	t.Parallel()
 	validInput := []struct {
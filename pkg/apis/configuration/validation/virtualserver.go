@@ -2,7 +2,9 @@ package validation
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -23,6 +25,9 @@ type VirtualServerValidator struct {
 	isDosEnabled         bool
 	isCertManagerEnabled bool
 	isExternalDNSEnabled bool
+	isBrotliEnabled      bool
+	isZstdEnabled        bool
+	isGeoIP2Enabled      bool
 }
 
 // IsPlus modifies the VirtualServerValidator to set the isPlus option.
@@ -53,6 +58,27 @@ func IsExternalDNSEnabled(ed bool) VsvOption {
 	}
 }
 
+// IsBrotliEnabled modifies the VirtualServerValidator to set the isBrotliEnabled option.
+func IsBrotliEnabled(brotli bool) VsvOption {
+	return func(v *VirtualServerValidator) {
+		v.isBrotliEnabled = brotli
+	}
+}
+
+// IsZstdEnabled modifies the VirtualServerValidator to set the isZstdEnabled option.
+func IsZstdEnabled(zstd bool) VsvOption {
+	return func(v *VirtualServerValidator) {
+		v.isZstdEnabled = zstd
+	}
+}
+
+// IsGeoIP2Enabled modifies the VirtualServerValidator to set the isGeoIP2Enabled option.
+func IsGeoIP2Enabled(geoIP2 bool) VsvOption {
+	return func(v *VirtualServerValidator) {
+		v.isGeoIP2Enabled = geoIP2
+	}
+}
+
 // NewVirtualServerValidator creates a new VirtualServerValidator.
 func NewVirtualServerValidator(opts ...VsvOption) *VirtualServerValidator {
 	vsv := VirtualServerValidator{
@@ -60,6 +86,9 @@ func NewVirtualServerValidator(opts ...VsvOption) *VirtualServerValidator {
 		isDosEnabled:         false,
 		isCertManagerEnabled: false,
 		isExternalDNSEnabled: false,
+		isBrotliEnabled:      false,
+		isZstdEnabled:        false,
+		isGeoIP2Enabled:      false,
 	}
 	for _, o := range opts {
 		o(&vsv)
@@ -88,8 +117,61 @@ func (vsv *VirtualServerValidator) validateVirtualServerSpec(spec *v1.VirtualSer
 
 	allErrs = append(allErrs, validateDos(vsv.isDosEnabled, spec.Dos, fieldPath.Child("dos"))...)
 
+	allErrs = append(allErrs, vsv.validateCompression(spec.Compression, fieldPath.Child("compression"))...)
+
 	allErrs = append(allErrs, vsv.validateExternalDNS(&spec.ExternalDNS, fieldPath.Child("externalDNS"))...)
 
+	allErrs = append(allErrs, vsv.validateMaintenance(spec.Maintenance, fieldPath.Child("maintenance"))...)
+
+	return allErrs
+}
+
+func (vsv *VirtualServerValidator) validateMaintenance(m *v1.Maintenance, fieldPath *field.Path) field.ErrorList {
+	if m == nil {
+		return nil
+	}
+
+	allErrs := validateMaintenanceAllowList(m.AllowList, fieldPath.Child("allowList"))
+
+	if m.Header != nil {
+		allErrs = append(allErrs, validateMaintenanceHeader(m.Header, fieldPath.Child("header"))...)
+	}
+	if m.Body != "" {
+		allErrs = append(allErrs, validateEscapedStringWithVariables(m.Body, fieldPath.Child("body"), nil, nil, vsv.isPlus)...)
+	}
+	if m.Type != "" {
+		allErrs = append(allErrs, validateActionReturnType(m.Type, fieldPath.Child("type"))...)
+	}
+	allErrs = append(allErrs, validateTime(m.RetryAfter, fieldPath.Child("retryAfter"))...)
+
+	return allErrs
+}
+
+func validateMaintenanceAllowList(allowList []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, addr := range allowList {
+		allErrs = append(allErrs, validateIPorCIDR(addr, fieldPath.Index(i))...)
+	}
+	return allErrs
+}
+
+const (
+	maintenanceTokenFmt    = `[A-Za-z0-9._~+/=-]+`
+	maintenanceTokenErrMsg = "must consist of alphanumeric characters or '.', '_', '~', '+', '/', '=', '-'"
+)
+
+var maintenanceTokenRegexp = regexp.MustCompile("^" + maintenanceTokenFmt + "$")
+
+func validateMaintenanceHeader(h *v1.MaintenanceHeader, fieldPath *field.Path) field.ErrorList {
+	allErrs := validateHeaderName(h.Name, fieldPath.Child("name"))
+
+	if h.Token == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("token"), ""))
+	} else if !maintenanceTokenRegexp.MatchString(h.Token) {
+		msg := validation.RegexError(maintenanceTokenErrMsg, maintenanceTokenFmt, "c2VjcmV0", "maintenance-token")
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("token"), h.Token, msg))
+	}
+
 	return allErrs
 }
 
@@ -197,6 +279,84 @@ func validateDos(isDosEnabled bool, dos string, fieldPath *field.Path) field.Err
 	return allErrs
 }
 
+func (vsv *VirtualServerValidator) validateCompression(compression *v1.Compression, fieldPath *field.Path) field.ErrorList {
+	if compression == nil {
+		// valid, compression is not required
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	encodings := sets.Set[string]{}
+
+	for i, e := range compression.Encodings {
+		idxPath := fieldPath.Child("encodings").Index(i)
+
+		if encodings.Has(e) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, e))
+			continue
+		}
+		encodings.Insert(e)
+
+		switch e {
+		case "gzip":
+		case "br":
+			if !vsv.isBrotliEnabled {
+				allErrs = append(allErrs, field.Forbidden(idxPath, "br encoding requires the brotli module in NGINX, which is not available"))
+			}
+		case "zstd":
+			if !vsv.isZstdEnabled {
+				allErrs = append(allErrs, field.Forbidden(idxPath, "zstd encoding requires the zstd module in NGINX, which is not available"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath, e, []string{"gzip", "br", "zstd"}))
+		}
+	}
+
+	for i, t := range compression.Types {
+		allErrs = append(allErrs, validateMIMEType(t, fieldPath.Child("types").Index(i))...)
+	}
+
+	if compression.MinLength != nil && *compression.MinLength < 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("minLength"), *compression.MinLength, "must not be negative"))
+	}
+
+	if compression.Level != nil {
+		if len(compression.Encodings) == 0 {
+			encodings.Insert("gzip")
+		}
+		for _, r := range compressionLevelRanges {
+			if !encodings.Has(r.encoding) {
+				continue
+			}
+			if *compression.Level < r.min || *compression.Level > r.max {
+				msg := fmt.Sprintf("must be between %d and %d for the %s encoding", r.min, r.max, r.encoding)
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("level"), *compression.Level, msg))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// compressionLevelRanges are the compression levels supported by the modules of the encodings.
+var compressionLevelRanges = []struct {
+	encoding string
+	min, max int
+}{
+	{encoding: "gzip", min: 1, max: 9},
+	{encoding: "br", min: 0, max: 11},
+	{encoding: "zstd", min: 1, max: 22},
+}
+
+var mimeTypeRegexp = regexp.MustCompile(`^([a-z0-9][a-z0-9!#$&^_.+-]*/[a-z0-9*][a-z0-9!#$&^_.+*-]*|\*)$`)
+
+func validateMIMEType(mimeType string, fieldPath *field.Path) field.ErrorList {
+	if !mimeTypeRegexp.MatchString(mimeType) {
+		return field.ErrorList{field.Invalid(fieldPath, mimeType, "must be a MIME type, for example application/json, or *")}
+	}
+	return nil
+}
+
 func (vsv *VirtualServerValidator) validateExternalDNS(ed *v1.ExternalDNS, fieldPath *field.Path) field.ErrorList {
 	if ed == nil || !ed.Enable {
 		// valid, externalDNS is not required
@@ -542,9 +702,7 @@ func validateHeader(h v1.Header, fieldPath *field.Path) field.ErrorList {
 		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), ""))
 	}
 
-	for _, msg := range validation.IsHTTPHeaderName(h.Name) {
-		allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), h.Name, msg))
-	}
+	allErrs = append(allErrs, validateHeaderName(h.Name, fieldPath.Child("name"))...)
 
 	for _, msg := range isValidHeaderValue(h.Value) {
 		allErrs = append(allErrs, field.Invalid(fieldPath.Child("value"), h.Value, msg))
@@ -590,6 +748,11 @@ func (vsv *VirtualServerValidator) validateUpstreams(upstreams []v1.Upstream, fi
 		}
 
 		allErrs = append(allErrs, validateServiceName(u.Service, idxPath.Child("service"))...)
+		if u.ServiceNamespace != "" {
+			for _, msg := range validation.IsDNS1123Label(u.ServiceNamespace) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("serviceNamespace"), u.ServiceNamespace, msg))
+			}
+		}
 		allErrs = append(allErrs, validateTime(u.ProxyConnectTimeout, idxPath.Child("connect-timeout"))...)
 		allErrs = append(allErrs, validateTime(u.ProxyReadTimeout, idxPath.Child("read-timeout"))...)
 		allErrs = append(allErrs, validateTime(u.ProxySendTimeout, idxPath.Child("send-timeout"))...)
@@ -742,7 +905,9 @@ func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *fiel
 	fieldCount := 0
 
 	if route.Action != nil {
-		allErrs = append(allErrs, vsv.validateAction(route.Action, fieldPath.Child("action"), upstreamNames, route.Path, false)...)
+		// the action of a route with a canary is used in an internal location
+		internal := route.Canary != nil
+		allErrs = append(allErrs, vsv.validateAction(route.Action, fieldPath.Child("action"), upstreamNames, route.Path, internal)...)
 		fieldCount++
 	}
 
@@ -753,6 +918,10 @@ func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *fiel
 
 	// Matches are optional. that's why we don't do fieldCount++
 	if len(route.Matches) > 0 {
+		// the action of a route with matches is used in an internal location
+		if route.Action != nil && (route.Action.Static != nil || route.Action.ObjectStorage != nil) {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("action"), "`static` and `objectStorage` actions are not allowed in routes with matches"))
+		}
 		for i, m := range route.Matches {
 			allErrs = append(allErrs, vsv.validateMatch(m, fieldPath.Child("matches").Index(i), upstreamNames, route.Path)...)
 		}
@@ -780,8 +949,17 @@ func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *fiel
 		allErrs = append(allErrs, field.Invalid(fieldPath, "", msg))
 	}
 
+	if route.Canary != nil {
+		if route.Action == nil || len(route.Matches) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("canary"), "can only be used with `action` and without `matches`"))
+		}
+		allErrs = append(allErrs, vsv.validateCanary(route.Canary, fieldPath.Child("canary"), upstreamNames, route.Path)...)
+	}
+
 	allErrs = append(allErrs, validateDos(vsv.isDosEnabled, route.Dos, fieldPath.Child("dos"))...)
 
+	allErrs = append(allErrs, vsv.validateCompression(route.Compression, fieldPath.Child("compression"))...)
+
 	return allErrs
 }
 
@@ -881,6 +1059,14 @@ func countActions(action *v1.Action) int {
 		count++
 	}
 
+	if action.Static != nil {
+		count++
+	}
+
+	if action.ObjectStorage != nil {
+		count++
+	}
+
 	return count
 }
 
@@ -923,7 +1109,7 @@ var validRedirectVariableNames = map[string]bool{
 
 func (vsv *VirtualServerValidator) validateAction(action *v1.Action, fieldPath *field.Path, upstreamNames sets.Set[string], path string, internal bool) field.ErrorList {
 	if countActions(action) != 1 {
-		return field.ErrorList{field.Required(fieldPath, "action must specify exactly one of `pass`, `redirect`, `return` or `proxy`")}
+		return field.ErrorList{field.Required(fieldPath, "action must specify exactly one of `pass`, `redirect`, `return`, `proxy`, `static` or `objectStorage`")}
 	}
 
 	allErrs := field.ErrorList{}
@@ -943,6 +1129,134 @@ func (vsv *VirtualServerValidator) validateAction(action *v1.Action, fieldPath *
 		allErrs = append(allErrs, vsv.validateActionProxy(action.Proxy, fieldPath.Child("proxy"), upstreamNames, path, internal)...)
 	}
 
+	// static and objectStorage locate the files by the path of the route, which internal locations don't have
+	if action.Static != nil {
+		if internal {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("static"), "is not allowed in splits, matches and routes with a canary"))
+		} else {
+			allErrs = append(allErrs, validateActionStatic(action.Static, fieldPath.Child("static"))...)
+		}
+	}
+
+	if action.ObjectStorage != nil {
+		if internal {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("objectStorage"), "is not allowed in splits, matches and routes with a canary"))
+		} else {
+			allErrs = append(allErrs, validateActionObjectStorage(action.ObjectStorage, fieldPath.Child("objectStorage"), upstreamNames)...)
+		}
+	}
+
+	return allErrs
+}
+
+const (
+	staticPathFmt    = `[a-zA-Z0-9._-]+(/[a-zA-Z0-9._-]+)*`
+	staticPathErrMsg = "must be a relative path of alphanumeric characters, '.', '_' or '-' separated by '/'"
+)
+
+var staticPathRegexp = regexp.MustCompile("^" + staticPathFmt + "$")
+
+const (
+	staticIndexFmt    = `[a-zA-Z0-9._-]+`
+	staticIndexErrMsg = "must be a file name of alphanumeric characters, '.', '_' or '-'"
+)
+
+var staticIndexRegexp = regexp.MustCompile("^" + staticIndexFmt + "$")
+
+const (
+	staticTryFileFmt    = `(\$uri|[a-zA-Z0-9._~/-])+|=[1-5][0-9][0-9]`
+	staticTryFileErrMsg = "must be a URI of alphanumeric characters, '.', '_', '~', '/', '-' or the variable $uri, or a code like =404"
+)
+
+var staticTryFileRegexp = regexp.MustCompile("^(" + staticTryFileFmt + ")$")
+
+var staticExpiresKeywords = map[string]bool{
+	"off":   true,
+	"epoch": true,
+	"max":   true,
+}
+
+func validateActionStatic(static *v1.ActionStatic, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	pathField := fieldPath.Child("path")
+	if static.Path == "" {
+		allErrs = append(allErrs, field.Required(pathField, ""))
+	} else if !staticPathRegexp.MatchString(static.Path) {
+		allErrs = append(allErrs, field.Invalid(pathField, static.Path, validation.RegexError(staticPathErrMsg, staticPathFmt, "cafe", "cafe/static")))
+	} else if slices.Contains(strings.Split(static.Path, "/"), "..") {
+		allErrs = append(allErrs, field.Invalid(pathField, static.Path, "must not contain '..'"))
+	}
+
+	for i, index := range static.Index {
+		if !staticIndexRegexp.MatchString(index) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("index").Index(i), index, validation.RegexError(staticIndexErrMsg, staticIndexFmt, "index.html")))
+		}
+	}
+
+	if len(static.TryFiles) == 1 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("tryFiles"), static.TryFiles, "must contain at least two items"))
+	}
+	for i, file := range static.TryFiles {
+		idxPath := fieldPath.Child("tryFiles").Index(i)
+		if !staticTryFileRegexp.MatchString(file) {
+			allErrs = append(allErrs, field.Invalid(idxPath, file, validation.RegexError(staticTryFileErrMsg, staticTryFileFmt, "$uri", "$uri/", "/index.html", "=404")))
+		} else if strings.HasPrefix(file, "=") && i != len(static.TryFiles)-1 {
+			allErrs = append(allErrs, field.Invalid(idxPath, file, "a code is only allowed as the last item"))
+		}
+	}
+
+	if static.Expires != "" && !staticExpiresKeywords[static.Expires] {
+		allErrs = append(allErrs, validateTime(static.Expires, fieldPath.Child("expires"))...)
+	}
+
+	return allErrs
+}
+
+const (
+	bucketNameFmt    = `[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]`
+	bucketNameErrMsg = "must be 3 to 63 lowercase alphanumeric characters, '.' or '-', and must start and end with an alphanumeric character"
+)
+
+var bucketNameRegexp = regexp.MustCompile("^" + bucketNameFmt + "$")
+
+const (
+	regionFmt    = `[a-z0-9-]+`
+	regionErrMsg = "must consist of lowercase alphanumeric characters or '-'"
+)
+
+var regionRegexp = regexp.MustCompile("^" + regionFmt + "$")
+
+const (
+	objectStorageHostFmt    = `[a-zA-Z0-9.-]+(:[0-9]{1,5})?`
+	objectStorageHostErrMsg = "must be a host name with an optional port"
+)
+
+var objectStorageHostRegexp = regexp.MustCompile("^" + objectStorageHostFmt + "$")
+
+func validateActionObjectStorage(objectStorage *v1.ActionObjectStorage, fieldPath *field.Path, upstreamNames sets.Set[string]) field.ErrorList {
+	allErrs := validateReferencedUpstream(objectStorage.Upstream, fieldPath.Child("upstream"), upstreamNames)
+
+	if objectStorage.Host != "" && !objectStorageHostRegexp.MatchString(objectStorage.Host) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("host"), objectStorage.Host, validation.RegexError(objectStorageHostErrMsg, objectStorageHostFmt, "s3.us-east-1.amazonaws.com", "minio.default.svc:9000")))
+	}
+
+	if objectStorage.Bucket == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("bucket"), ""))
+	} else if !bucketNameRegexp.MatchString(objectStorage.Bucket) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("bucket"), objectStorage.Bucket, validation.RegexError(bucketNameErrMsg, bucketNameFmt, "cafe-assets")))
+	}
+
+	if objectStorage.Region != "" && !regionRegexp.MatchString(objectStorage.Region) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("region"), objectStorage.Region, validation.RegexError(regionErrMsg, regionFmt, "us-east-1")))
+	}
+
+	if objectStorage.Secret == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("secret"), ""))
+	} else {
+		allErrs = append(allErrs, validateSecretName(objectStorage.Secret, fieldPath.Child("secret"))...)
+	}
+
 	return allErrs
 }
 
@@ -1059,16 +1373,58 @@ func (vsv *VirtualServerValidator) validateActionProxy(p *v1.ActionProxy, fieldP
 	allErrs := validateReferencedUpstream(p.Upstream, fieldPath.Child("upstream"), upstreamNames)
 	allErrs = append(allErrs, vsv.validateActionProxyRequestHeaders(p.RequestHeaders, fieldPath.Child("requestHeaders"))...)
 	allErrs = append(allErrs, vsv.validateActionProxyResponseHeaders(p.ResponseHeaders, fieldPath.Child("responseHeaders"))...)
+	allErrs = append(allErrs, validateActionProxyResponseBodyRewrite(p.ResponseBodyRewrite, fieldPath.Child("responseBodyRewrite"))...)
 
 	if strings.HasPrefix(path, "~") || internal {
-		allErrs = append(allErrs, validateActionProxyRewritePathForRegexp(p.RewritePath, fieldPath.Child("rewritePath"))...)
+		allErrs = append(allErrs, vsv.validateActionProxyRewritePathForRegexp(p.RewritePath, path, fieldPath.Child("rewritePath"))...)
 	} else {
-		allErrs = append(allErrs, validateActionProxyRewritePath(p.RewritePath, fieldPath.Child("rewritePath"))...)
+		allErrs = append(allErrs, vsv.validateActionProxyRewritePath(p.RewritePath, path, fieldPath.Child("rewritePath"))...)
+	}
+	allErrs = append(allErrs, validateActionProxyRewriteArgs(p.RewriteArgs, fieldPath.Child("rewriteArgs"))...)
+
+	return allErrs
+}
+
+func validateActionProxyResponseBodyRewrite(rewrites []v1.ResponseBodyRewrite, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	matches := sets.Set[string]{}
+
+	for i, r := range rewrites {
+		idxPath := fieldPath.Index(i)
+
+		if r.Match == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("match"), ""))
+		} else if matches.Has(r.Match) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("match"), r.Match))
+		} else {
+			matches.Insert(r.Match)
+			allErrs = append(allErrs, validateResponseBodyRewriteString(r.Match, idxPath.Child("match"))...)
+		}
+
+		allErrs = append(allErrs, validateResponseBodyRewriteString(r.Replacement, idxPath.Child("replacement"))...)
+
+		if r.Once != rewrites[0].Once {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("once"), r.Once, "must be the same for all rewrites of the action"))
+		}
+
+		for j, t := range r.Types {
+			allErrs = append(allErrs, validateMIMEType(t, idxPath.Child("types").Index(j))...)
+		}
 	}
 
 	return allErrs
 }
 
+func validateResponseBodyRewriteString(s string, fieldPath *field.Path) field.ErrorList {
+	if strings.Contains(s, "$") {
+		return field.ErrorList{field.Invalid(fieldPath, s, "must not contain the `$` character")}
+	}
+	if err := ValidateEscapedString(s, "http://internal.example.com", `say \"hello\"`); err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, s, err.Error())}
+	}
+	return nil
+}
+
 func validateStringNoVariables(s string, fieldPath *field.Path) field.ErrorList {
 	for i, char := range s {
 		charLen := len(string(char))
@@ -1081,26 +1437,128 @@ func validateStringNoVariables(s string, fieldPath *field.Path) field.ErrorList
 	return nil
 }
 
-func validateActionProxyRewritePath(rewritePath string, fieldPath *field.Path) field.ErrorList {
+// rewriteVariables includes NGINX variables allowed to be used in a rewrite path.
+var rewriteVariables = map[string]bool{
+	"host":            true,
+	"scheme":          true,
+	"server_name":     true,
+	"server_port":     true,
+	"remote_addr":     true,
+	"request_method":  true,
+	"ssl_server_name": true,
+}
+
+var rewriteSpecialVariables = []string{"arg_", "http_", "cookie_"}
+
+// rewriteArgsVariables includes NGINX variables allowed to be used in the values of the rewritten arguments.
+// The values of the variables can't be set by clients, because a value with `&` would add arguments.
+var rewriteArgsVariables = map[string]bool{
+	"scheme":         true,
+	"server_name":    true,
+	"server_port":    true,
+	"remote_addr":    true,
+	"request_method": true,
+}
+
+var (
+	positionalCaptureRegexp = regexp.MustCompile(`\$[0-9]`)
+	namedCaptureRegexp      = regexp.MustCompile(`\(\?P?<([A-Za-z_][A-Za-z0-9_]*)>`)
+	enclosedVariableRegexp  = regexp.MustCompile(`\$\{[^}]*\}`)
+)
+
+// getRewriteVariables returns the NGINX variables allowed in the rewrites of a route:
+// the request variables and the named captures of a regex path.
+func getRewriteVariables(path string) map[string]bool {
+	vars := maps.Clone(rewriteVariables)
+	if strings.HasPrefix(path, "~") {
+		for _, m := range namedCaptureRegexp.FindAllStringSubmatch(path, -1) {
+			vars[m[1]] = true
+		}
+	}
+	return vars
+}
+
+func (vsv *VirtualServerValidator) validateRewritePathVariables(rewritePath string, path string, fieldPath *field.Path) field.ErrorList {
+	// outside of the variables enclosed in curly braces, only positional captures, for example $1, are allowed
+	if allErrs := validateStringNoVariables(enclosedVariableRegexp.ReplaceAllString(rewritePath, ""), fieldPath); len(allErrs) > 0 {
+		return allErrs
+	}
+	s := positionalCaptureRegexp.ReplaceAllString(rewritePath, "")
+	return validateStringWithVariables(s, fieldPath, rewriteSpecialVariables, getRewriteVariables(path), vsv.isPlus)
+}
+
+func (vsv *VirtualServerValidator) validateActionProxyRewritePath(rewritePath string, path string, fieldPath *field.Path) field.ErrorList {
 	if rewritePath == "" {
 		return nil
 	}
-	allErrs := validateStringNoVariables(rewritePath, fieldPath)
-	return append(allErrs, validatePath(rewritePath, fieldPath)...)
+	allErrs := vsv.validateRewritePathVariables(rewritePath, path, fieldPath)
+	if !pathRegexp.MatchString(enclosedVariableRegexp.ReplaceAllString(rewritePath, "_")) {
+		msg := validation.RegexError(pathErrMsg, pathFmt, "/", "/path", "/path/${http_x_tenant}")
+		allErrs = append(allErrs, field.Invalid(fieldPath, rewritePath, msg))
+	}
+	return allErrs
 }
 
-func validateActionProxyRewritePathForRegexp(rewritePath string, fieldPath *field.Path) field.ErrorList {
+func (vsv *VirtualServerValidator) validateActionProxyRewritePathForRegexp(rewritePath string, path string, fieldPath *field.Path) field.ErrorList {
 	if rewritePath == "" {
 		return nil
 	}
 
-	allErrs := validateStringNoVariables(rewritePath, fieldPath)
-	if err := ValidateEscapedString(rewritePath, "/rewrite$1", "/images"); err != nil {
+	allErrs := vsv.validateRewritePathVariables(rewritePath, path, fieldPath)
+	if err := ValidateEscapedString(rewritePath, "/rewrite$1", "/images", "/users/${id}"); err != nil {
 		allErrs = append(allErrs, field.Invalid(fieldPath, rewritePath, err.Error()))
 	}
 	return allErrs
 }
 
+const (
+	argumentValueFmt    = `[^\s&#"'\\;{}]*`
+	argumentValueErrMsg = "must not include any whitespace character, `&`, `#`, `\"`, `'`, `\\`, `;`, `{` or `}` outside of variables"
+)
+
+var argumentValueRegexp = regexp.MustCompile("^" + argumentValueFmt + "$")
+
+func validateActionProxyRewriteArgs(args *v1.RewriteArgs, fieldPath *field.Path) field.ErrorList {
+	if args == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	names := sets.Set[string]{}
+
+	for i, a := range args.Set {
+		idxPath := fieldPath.Child("set").Index(i)
+		for _, msg := range isArgumentName(a.Name) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), a.Name, msg))
+		}
+		if names.Has(a.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), a.Name))
+		}
+		names.Insert(a.Name)
+
+		// positional captures are not allowed, because the removal of the arguments overrides them.
+		// Named captures and the request variables are not allowed, because clients can set their values.
+		allErrs = append(allErrs, validateStringWithVariables(a.Value, idxPath.Child("value"), nil, rewriteArgsVariables, false)...)
+		if !argumentValueRegexp.MatchString(enclosedVariableRegexp.ReplaceAllString(a.Value, "")) {
+			msg := validation.RegexError(argumentValueErrMsg, argumentValueFmt, "value", "${server_name}")
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("value"), a.Value, msg))
+		}
+	}
+
+	for i, name := range args.Remove {
+		idxPath := fieldPath.Child("remove").Index(i)
+		for _, msg := range isArgumentName(name) {
+			allErrs = append(allErrs, field.Invalid(idxPath, name, msg))
+		}
+		if names.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, name))
+		}
+		names.Insert(name)
+	}
+
+	return allErrs
+}
+
 var actionProxyHeaderVariables = map[string]bool{
 	"request_uri":             true,
 	"request_method":          true,
@@ -1157,9 +1615,7 @@ func (vsv *VirtualServerValidator) validateActionProxyHeader(h v1.Header, fieldP
 		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), ""))
 	}
 
-	for _, msg := range validation.IsHTTPHeaderName(h.Name) {
-		allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), h.Name, msg))
-	}
+	allErrs = append(allErrs, validateHeaderName(h.Name, fieldPath.Child("name"))...)
 
 	allErrs = append(allErrs, validateEscapedStringWithVariables(h.Value, fieldPath.Child("value"),
 		actionProxyHeaderSpecialVariables, actionProxyHeaderVariables, vsv.isPlus)...)
@@ -1186,15 +1642,11 @@ func (vsv *VirtualServerValidator) validateActionProxyResponseHeaders(responseHe
 
 	allErrs := field.ErrorList{}
 	for i, header := range responseHeaders.Hide {
-		for _, msg := range validation.IsHTTPHeaderName(header) {
-			allErrs = append(allErrs, field.Invalid(fieldPath.Child("hide").Index(i), header, msg))
-		}
+		allErrs = append(allErrs, validateHeaderName(header, fieldPath.Child("hide").Index(i))...)
 	}
 
 	for i, header := range responseHeaders.Pass {
-		for _, msg := range validation.IsHTTPHeaderName(header) {
-			allErrs = append(allErrs, field.Invalid(fieldPath.Child("pass").Index(i), header, msg))
-		}
+		allErrs = append(allErrs, validateHeaderName(header, fieldPath.Child("pass").Index(i))...)
 	}
 
 	for i, header := range responseHeaders.Add {
@@ -1263,6 +1715,53 @@ func (vsv *VirtualServerValidator) validateSplits(splits []v1.Split, fieldPath *
 	return allErrs
 }
 
+func (vsv *VirtualServerValidator) validateCanary(canary *v1.Canary, fieldPath *field.Path, upstreamNames sets.Set[string], path string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, msg := range validation.IsInRange(canary.Weight, 0, 100) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("weight"), canary.Weight, msg))
+	}
+
+	if canary.Action == nil {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("action"), ""))
+	} else {
+		allErrs = append(allErrs, vsv.validateAction(canary.Action, fieldPath.Child("action"), upstreamNames, path, true)...)
+	}
+
+	if canary.ForceHeader != "" {
+		for _, msg := range validation.IsHTTPHeaderName(canary.ForceHeader) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("forceHeader"), canary.ForceHeader, msg))
+		}
+	}
+
+	if canary.ForceCookie != "" {
+		for _, msg := range isCookieName(canary.ForceCookie) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("forceCookie"), canary.ForceCookie, msg))
+		}
+	}
+
+	if sc := canary.StickyCookie; sc != nil {
+		stickyPath := fieldPath.Child("stickyCookie")
+		if sc.Name == "" {
+			allErrs = append(allErrs, field.Required(stickyPath.Child("name"), ""))
+		} else if sc.Name == canary.ForceCookie {
+			allErrs = append(allErrs, field.Invalid(stickyPath.Child("name"), sc.Name, "must be different from the forceCookie"))
+		} else {
+			for _, msg := range isCookieName(sc.Name) {
+				allErrs = append(allErrs, field.Invalid(stickyPath.Child("name"), sc.Name, msg))
+			}
+		}
+
+		if sc.Path != "" {
+			allErrs = append(allErrs, validatePath(sc.Path, stickyPath.Child("path"))...)
+		}
+
+		allErrs = append(allErrs, validateTime(sc.Expires, stickyPath.Child("expires"))...)
+	}
+
+	return allErrs
+}
+
 // We support prefix-based NGINX locations, positive case-sensitive/insensitive regular expressions matches and exact matches.
 // More info http://nginx.org/en/docs/http/ngx_http_core_module.html#location
 func validateRoutePath(path string, fieldPath *field.Path) field.ErrorList {
@@ -1339,7 +1838,7 @@ func (vsv *VirtualServerValidator) validateMatch(match v1.Match, fieldPath *fiel
 		allErrs = append(allErrs, field.Required(fieldPath.Child("conditions"), "must specify at least one condition"))
 	} else {
 		for i, c := range match.Conditions {
-			allErrs = append(allErrs, validateCondition(c, fieldPath.Child("conditions").Index(i))...)
+			allErrs = append(allErrs, vsv.validateCondition(c, fieldPath.Child("conditions").Index(i))...)
 		}
 	}
 
@@ -1362,7 +1861,7 @@ func (vsv *VirtualServerValidator) validateMatch(match v1.Match, fieldPath *fiel
 	return allErrs
 }
 
-func validateCondition(condition v1.Condition, fieldPath *field.Path) field.ErrorList {
+func (vsv *VirtualServerValidator) validateCondition(condition v1.Condition, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	fieldCount := 0
@@ -1389,7 +1888,13 @@ func validateCondition(condition v1.Condition, fieldPath *field.Path) field.Erro
 	}
 
 	if condition.Variable != "" {
-		allErrs = append(allErrs, validateVariableName(condition.Variable, fieldPath.Child("variable"))...)
+		if geoIP2VariableNames[condition.Variable] {
+			if !vsv.isGeoIP2Enabled {
+				allErrs = append(allErrs, field.Forbidden(fieldPath.Child("variable"), "requires the GeoIP2 module"))
+			}
+		} else {
+			allErrs = append(allErrs, validateVariableName(condition.Variable, fieldPath.Child("variable"))...)
+		}
 		fieldCount++
 	}
 
@@ -1448,6 +1953,13 @@ var validVariableNames = map[string]bool{
 	"$scheme":         true,
 }
 
+// geoIP2VariableNames includes the variables of the GeoIP2 module allowed to be used in conditions.
+// The variables are defined in the http context when the module is available.
+var geoIP2VariableNames = map[string]bool{
+	"$geoip2_country_code": true,
+	"$geoip2_asn":          true,
+}
+
 func validateVariableName(name string, fieldPath *field.Path) field.ErrorList {
 	if !strings.HasPrefix(name, "$") {
 		return field.ErrorList{field.Invalid(fieldPath, name, "must start with `$`")}
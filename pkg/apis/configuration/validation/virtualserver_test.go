@@ -277,6 +277,270 @@ func TestValidateDosDisabled(t *testing.T) {
 	}
 }
 
+func TestValidateCompression(t *testing.T) {
+	t.Parallel()
+	vsv := &VirtualServerValidator{isBrotliEnabled: true}
+
+	validCompressions := []*v1.Compression{
+		nil,
+		{
+			Enable: true,
+		},
+		{
+			Enable:    true,
+			Encodings: []string{"gzip", "br"},
+			Types:     []string{"application/json", "text/*", "*"},
+			MinLength: createPointerFromInt(1000),
+			Level:     createPointerFromInt(5),
+			Static:    true,
+		},
+		{
+			Enable: false,
+		},
+	}
+
+	for _, c := range validCompressions {
+		allErrs := vsv.validateCompression(c, field.NewPath("compression"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateCompression(%+v) returned errors %v for valid input", c, allErrs)
+		}
+	}
+
+	invalidCompressions := []struct {
+		compression *v1.Compression
+		msg         string
+	}{
+		{
+			compression: &v1.Compression{Enable: true, Encodings: []string{"deflate"}},
+			msg:         "unsupported encoding",
+		},
+		{
+			compression: &v1.Compression{Enable: true, Encodings: []string{"gzip", "gzip"}},
+			msg:         "duplicate encoding",
+		},
+		{
+			compression: &v1.Compression{Enable: true, Encodings: []string{"zstd"}},
+			msg:         "zstd module not available",
+		},
+		{
+			compression: &v1.Compression{Enable: true, Types: []string{"application json"}},
+			msg:         "invalid MIME type",
+		},
+		{
+			compression: &v1.Compression{Enable: true, MinLength: createPointerFromInt(-1)},
+			msg:         "negative minimum length",
+		},
+		{
+			compression: &v1.Compression{Enable: true, Level: createPointerFromInt(10)},
+			msg:         "level out of range",
+		},
+	}
+
+	for _, test := range invalidCompressions {
+		allErrs := vsv.validateCompression(test.compression, field.NewPath("compression"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateCompression() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateCompressionLevel(t *testing.T) {
+	t.Parallel()
+	vsv := &VirtualServerValidator{isBrotliEnabled: true, isZstdEnabled: true}
+
+	tests := []struct {
+		encodings []string
+		level     int
+		valid     bool
+		msg       string
+	}{
+		{encodings: nil, level: 9, valid: true, msg: "maximum gzip level by default"},
+		{encodings: nil, level: 0, valid: false, msg: "gzip level below range by default"},
+		{encodings: []string{"gzip"}, level: 10, valid: false, msg: "gzip level above range"},
+		{encodings: []string{"br"}, level: 0, valid: true, msg: "minimum br level"},
+		{encodings: []string{"br"}, level: 11, valid: true, msg: "maximum br level"},
+		{encodings: []string{"br"}, level: 12, valid: false, msg: "br level above range"},
+		{encodings: []string{"zstd"}, level: 22, valid: true, msg: "maximum zstd level"},
+		{encodings: []string{"zstd"}, level: 23, valid: false, msg: "zstd level above range"},
+		{encodings: []string{"zstd"}, level: 0, valid: false, msg: "zstd level below range"},
+		{encodings: []string{"br", "zstd"}, level: 11, valid: true, msg: "level in the range of all encodings"},
+		{encodings: []string{"gzip", "br"}, level: 11, valid: false, msg: "level above the gzip range"},
+	}
+
+	for _, test := range tests {
+		compression := &v1.Compression{Enable: true, Encodings: test.encodings, Level: createPointerFromInt(test.level)}
+		allErrs := vsv.validateCompression(compression, field.NewPath("compression"))
+		if test.valid && len(allErrs) > 0 {
+			t.Errorf("validateCompression() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+		if !test.valid && len(allErrs) == 0 {
+			t.Errorf("validateCompression() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateMaintenance(t *testing.T) {
+	t.Parallel()
+	vsv := &VirtualServerValidator{}
+
+	validMaintenances := []*v1.Maintenance{
+		nil,
+		{
+			Enable: true,
+		},
+		{
+			Enable:    true,
+			AllowList: []string{"10.0.0.0/8", "192.168.1.1"},
+			Header: &v1.MaintenanceHeader{
+				Name:  "X-Maintenance-Token",
+				Token: "c2VjcmV0",
+			},
+			Body:       `{\"status\": \"maintenance\"}`,
+			Type:       "application/json",
+			RetryAfter: "1h",
+		},
+	}
+
+	for _, m := range validMaintenances {
+		allErrs := vsv.validateMaintenance(m, field.NewPath("maintenance"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateMaintenance(%+v) returned errors %v for valid input", m, allErrs)
+		}
+	}
+
+	invalidMaintenances := []struct {
+		maintenance *v1.Maintenance
+		msg         string
+	}{
+		{
+			maintenance: &v1.Maintenance{Enable: true, AllowList: []string{"10.0.0.0/33"}},
+			msg:         "invalid CIDR",
+		},
+		{
+			maintenance: &v1.Maintenance{Enable: true, Header: &v1.MaintenanceHeader{Name: "X-Maintenance-Token"}},
+			msg:         "missing token",
+		},
+		{
+			maintenance: &v1.Maintenance{Enable: true, Header: &v1.MaintenanceHeader{Name: "X-Maintenance-Token", Token: `"secret"`}},
+			msg:         "invalid token",
+		},
+		{
+			maintenance: &v1.Maintenance{Enable: true, Header: &v1.MaintenanceHeader{Name: "X Token", Token: "secret"}},
+			msg:         "invalid header name",
+		},
+		{
+			maintenance: &v1.Maintenance{Enable: true, Body: "Back at ${host}"},
+			msg:         "variable in body",
+		},
+		{
+			maintenance: &v1.Maintenance{Enable: true, Type: "text/plain; charset=utf-8"},
+			msg:         "invalid type",
+		},
+		{
+			maintenance: &v1.Maintenance{Enable: true, RetryAfter: "1 hour"},
+			msg:         "invalid retry after",
+		},
+	}
+
+	for _, test := range invalidMaintenances {
+		allErrs := vsv.validateMaintenance(test.maintenance, field.NewPath("maintenance"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateMaintenance() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateCanary(t *testing.T) {
+	t.Parallel()
+	vsv := &VirtualServerValidator{}
+	upstreamNames := sets.New("stable", "canary")
+	action := &v1.Action{Pass: "stable"}
+	canaryAction := &v1.Action{Pass: "canary"}
+
+	validRoutes := []v1.Route{
+		{
+			Path:   "/",
+			Action: action,
+			Canary: &v1.Canary{Weight: 10, Action: canaryAction},
+		},
+		{
+			Path:   "/",
+			Action: action,
+			Canary: &v1.Canary{
+				Weight:      0,
+				Action:      canaryAction,
+				ForceHeader: "X-Canary",
+				ForceCookie: "canary_force",
+				StickyCookie: &v1.CanaryStickyCookie{
+					Name:    "canary",
+					Path:    "/coffee",
+					Expires: "1d",
+				},
+			},
+		},
+	}
+
+	for _, r := range validRoutes {
+		allErrs := vsv.validateRoute(r, field.NewPath("route"), upstreamNames, false, "default")
+		if len(allErrs) > 0 {
+			t.Errorf("validateRoute() returned errors %v for valid canary %+v", allErrs, r.Canary)
+		}
+	}
+
+	invalidRoutes := []struct {
+		route v1.Route
+		msg   string
+	}{
+		{
+			route: v1.Route{Path: "/", Action: action, Canary: &v1.Canary{Weight: 101, Action: canaryAction}},
+			msg:   "weight out of range",
+		},
+		{
+			route: v1.Route{Path: "/", Action: action, Canary: &v1.Canary{Weight: 10}},
+			msg:   "missing canary action",
+		},
+		{
+			route: v1.Route{Path: "/", Action: action, Canary: &v1.Canary{Weight: 10, Action: &v1.Action{Pass: "unknown"}}},
+			msg:   "unknown canary upstream",
+		},
+		{
+			route: v1.Route{Path: "/", Route: "default/coffee", Canary: &v1.Canary{Weight: 10, Action: canaryAction}},
+			msg:   "canary without route action",
+		},
+		{
+			route: v1.Route{Path: "/", Action: action, Canary: &v1.Canary{Weight: 10, Action: canaryAction, ForceHeader: "X Canary"}},
+			msg:   "invalid force header",
+		},
+		{
+			route: v1.Route{Path: "/", Action: action, Canary: &v1.Canary{Weight: 10, Action: canaryAction, ForceCookie: "canary-force"}},
+			msg:   "invalid force cookie",
+		},
+		{
+			route: v1.Route{Path: "/", Action: action, Canary: &v1.Canary{Weight: 10, Action: canaryAction, StickyCookie: &v1.CanaryStickyCookie{}}},
+			msg:   "missing sticky cookie name",
+		},
+		{
+			route: v1.Route{Path: "/", Action: action, Canary: &v1.Canary{
+				Weight: 10, Action: canaryAction, ForceCookie: "canary", StickyCookie: &v1.CanaryStickyCookie{Name: "canary"},
+			}},
+			msg: "sticky cookie same as force cookie",
+		},
+		{
+			route: v1.Route{Path: "/", Action: action, Canary: &v1.Canary{
+				Weight: 10, Action: canaryAction, StickyCookie: &v1.CanaryStickyCookie{Name: "canary", Expires: "1x"},
+			}},
+			msg: "invalid sticky cookie expires",
+		},
+	}
+
+	for _, test := range invalidRoutes {
+		allErrs := vsv.validateRoute(test.route, field.NewPath("route"), upstreamNames, false, "default")
+		if len(allErrs) == 0 {
+			t.Errorf("validateRoute() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidatePolicies(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1206,6 +1470,34 @@ func TestValidateRouteFails(t *testing.T) {
 			isRouteFieldForbidden: true,
 			msg:                   "route field exists but is forbidden",
 		},
+		{
+			route: v1.Route{
+				Path: "/",
+				Matches: []v1.Match{
+					{
+						Conditions: []v1.Condition{
+							{
+								Header: "x-version",
+								Value:  "test-1",
+							},
+						},
+						Action: &v1.Action{
+							Pass: "test-1",
+						},
+					},
+				},
+				Action: &v1.Action{
+					Static: &v1.ActionStatic{
+						Path: "cafe",
+					},
+				},
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test-1": {},
+			},
+			isRouteFieldForbidden: false,
+			msg:                   "static action in a route with matches",
+		},
 	}
 
 	vsv := &VirtualServerValidator{isPlus: false}
@@ -1281,6 +1573,29 @@ func TestValidateAction(t *testing.T) {
 			},
 			msg: "proxy action with rewritePath, requestHeaders and responseHeaders",
 		},
+		{
+			action: &v1.Action{
+				Static: &v1.ActionStatic{
+					Path:     "cafe/docs",
+					Index:    []string{"index.html"},
+					TryFiles: []string{"$uri", "$uri/", "/index.html"},
+					Expires:  "1h",
+				},
+			},
+			msg: "static action",
+		},
+		{
+			action: &v1.Action{
+				ObjectStorage: &v1.ActionObjectStorage{
+					Upstream: "test",
+					Host:     "minio.default.svc:9000",
+					Bucket:   "cafe-assets",
+					Region:   "eu-west-1",
+					Secret:   "minio-credentials",
+				},
+			},
+			msg: "objectStorage action",
+		},
 	}
 
 	vsv := &VirtualServerValidator{isPlus: false}
@@ -1337,6 +1652,23 @@ func TestValidateActionFails(t *testing.T) {
 			},
 			msg: "proxy action with missing upstream field",
 		},
+		{
+			action: &v1.Action{
+				Static: &v1.ActionStatic{
+					Path: "../secrets",
+				},
+			},
+			msg: "static action with a path outside of the static directory",
+		},
+		{
+			action: &v1.Action{
+				ObjectStorage: &v1.ActionObjectStorage{
+					Upstream: "test",
+					Bucket:   "cafe-assets",
+				},
+			},
+			msg: "objectStorage action with a missing upstream and secret",
+		},
 	}
 
 	vsv := &VirtualServerValidator{isPlus: false}
@@ -1349,6 +1681,123 @@ func TestValidateActionFails(t *testing.T) {
 	}
 }
 
+func TestValidateActionFailsForStaticAndObjectStorageInInternalLocations(t *testing.T) {
+	t.Parallel()
+	upstreamNames := map[string]sets.Empty{
+		"test": {},
+	}
+	actions := []*v1.Action{
+		{
+			Static: &v1.ActionStatic{
+				Path: "cafe",
+			},
+		},
+		{
+			ObjectStorage: &v1.ActionObjectStorage{
+				Upstream: "test",
+				Bucket:   "cafe-assets",
+				Secret:   "minio-credentials",
+			},
+		},
+	}
+
+	vsv := &VirtualServerValidator{isPlus: false}
+
+	for _, action := range actions {
+		allErrs := vsv.validateAction(action, field.NewPath("action"), upstreamNames, "/", true)
+		if len(allErrs) == 0 {
+			t.Errorf("validateAction() returned no errors for the action %+v in an internal location", action)
+		}
+	}
+}
+
+func TestValidateActionStaticFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		static *v1.ActionStatic
+		msg    string
+	}{
+		{
+			static: &v1.ActionStatic{},
+			msg:    "missing path",
+		},
+		{
+			static: &v1.ActionStatic{Path: "/etc/nginx/secrets"},
+			msg:    "absolute path",
+		},
+		{
+			static: &v1.ActionStatic{Path: "cafe/../../secrets"},
+			msg:    "path with parent directory",
+		},
+		{
+			static: &v1.ActionStatic{Path: "cafe", Index: []string{"docs/index.html"}},
+			msg:    "index with a directory",
+		},
+		{
+			static: &v1.ActionStatic{Path: "cafe", TryFiles: []string{"$uri"}},
+			msg:    "single tryFiles item",
+		},
+		{
+			static: &v1.ActionStatic{Path: "cafe", TryFiles: []string{"$uri", "$request_uri"}},
+			msg:    "tryFiles item with an invalid variable",
+		},
+		{
+			static: &v1.ActionStatic{Path: "cafe", TryFiles: []string{"=404", "$uri"}},
+			msg:    "tryFiles code that is not the last item",
+		},
+		{
+			static: &v1.ActionStatic{Path: "cafe", Expires: "1 hour"},
+			msg:    "invalid expires",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateActionStatic(test.static, field.NewPath("static"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateActionStatic() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateActionObjectStorageFails(t *testing.T) {
+	t.Parallel()
+	upstreamNames := map[string]sets.Empty{
+		"minio": {},
+	}
+	tests := []struct {
+		objectStorage *v1.ActionObjectStorage
+		msg           string
+	}{
+		{
+			objectStorage: &v1.ActionObjectStorage{Upstream: "s3", Bucket: "assets", Secret: "credentials"},
+			msg:           "upstream not found",
+		},
+		{
+			objectStorage: &v1.ActionObjectStorage{Upstream: "minio", Bucket: "Assets", Secret: "credentials"},
+			msg:           "invalid bucket",
+		},
+		{
+			objectStorage: &v1.ActionObjectStorage{Upstream: "minio", Bucket: "assets", Region: "US East", Secret: "credentials"},
+			msg:           "invalid region",
+		},
+		{
+			objectStorage: &v1.ActionObjectStorage{Upstream: "minio", Host: "minio;", Bucket: "assets", Secret: "credentials"},
+			msg:           "invalid host",
+		},
+		{
+			objectStorage: &v1.ActionObjectStorage{Upstream: "minio", Bucket: "assets", Secret: "Credentials"},
+			msg:           "invalid secret",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateActionObjectStorage(test.objectStorage, field.NewPath("objectStorage"), upstreamNames)
+		if len(allErrs) == 0 {
+			t.Errorf("validateActionObjectStorage() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestCaptureVariables(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1962,10 +2411,18 @@ func TestValidateCondition(t *testing.T) {
 			},
 			msg: "valid variable",
 		},
+		{
+			condition: v1.Condition{
+				Variable: "$geoip2_country_code",
+				Value:    "US",
+			},
+			msg: "valid geoip2 variable",
+		},
 	}
 
+	vsv := &VirtualServerValidator{isGeoIP2Enabled: true}
 	for _, test := range tests {
-		allErrs := validateCondition(test.condition, field.NewPath("condition"))
+		allErrs := vsv.validateCondition(test.condition, field.NewPath("condition"))
 		if len(allErrs) > 0 {
 			t.Errorf("validateCondition() returned errors %v for valid input for the case of %s", allErrs, test.msg)
 		}
@@ -2016,10 +2473,18 @@ func TestValidateConditionFails(t *testing.T) {
 			},
 			msg: "invalid variable",
 		},
+		{
+			condition: v1.Condition{
+				Variable: "$geoip2_asn",
+				Value:    "13335",
+			},
+			msg: "geoip2 variable without the module",
+		},
 	}
 
+	vsv := &VirtualServerValidator{}
 	for _, test := range tests {
-		allErrs := validateCondition(test.condition, field.NewPath("condition"))
+		allErrs := vsv.validateCondition(test.condition, field.NewPath("condition"))
 		if len(allErrs) == 0 {
 			t.Errorf("validateCondition() returned no errors for invalid input for the case of %s", test.msg)
 		}
@@ -3744,9 +4209,10 @@ func TestValidateActionProxyFails(t *testing.T) {
 
 func TestValidateActionProxyRewritePath(t *testing.T) {
 	t.Parallel()
-	tests := []string{"/rewrite", "/rewrite", `/$2`}
+	vsv := &VirtualServerValidator{isPlus: false}
+	tests := []string{"/rewrite", "/rewrite", `/$2`, "/tenants/${http_x_tenant}", "/${host}/${arg_version}"}
 	for _, test := range tests {
-		allErrs := validateActionProxyRewritePath(test, field.NewPath("rewritePath"))
+		allErrs := vsv.validateActionProxyRewritePath(test, "/path", field.NewPath("rewritePath"))
 		if len(allErrs) != 0 {
 			t.Errorf("validateActionProxyRewritePath(%v) returned errors for valid input: %v", test, allErrs)
 		}
@@ -3755,20 +4221,84 @@ func TestValidateActionProxyRewritePath(t *testing.T) {
 
 func TestValidateActionProxyRewritePathFails(t *testing.T) {
 	t.Parallel()
-	tests := []string{`/\d{3}`, `(`, "$request_uri"}
+	vsv := &VirtualServerValidator{isPlus: false}
+	tests := []string{`/\d{3}`, `(`, "$request_uri", "/${request_uri}", "/$host", "/${http_x_tenant};return 200", "/${id}", "/end$"}
 	for _, test := range tests {
-		allErrs := validateActionProxyRewritePath(test, field.NewPath("rewritePath"))
+		allErrs := vsv.validateActionProxyRewritePath(test, "/path", field.NewPath("rewritePath"))
 		if len(allErrs) == 0 {
 			t.Errorf("validateActionProxyRewritePath(%v) returned no errors for invalid input", test)
 		}
 	}
 }
 
+func TestValidateActionProxyResponseBodyRewrite(t *testing.T) {
+	t.Parallel()
+	rewrites := []v1.ResponseBodyRewrite{
+		{
+			Match:       "http://legacy.internal",
+			Replacement: "https://cafe.example.com",
+			Types:       []string{"application/json"},
+		},
+		{
+			Match:       `href=\"/`,
+			Replacement: `href=\"/legacy/`,
+		},
+	}
+	allErrs := validateActionProxyResponseBodyRewrite(rewrites, field.NewPath("responseBodyRewrite"))
+	if len(allErrs) != 0 {
+		t.Errorf("validateActionProxyResponseBodyRewrite() returned errors for valid input: %v", allErrs)
+	}
+}
+
+func TestValidateActionProxyResponseBodyRewriteFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		rewrites []v1.ResponseBodyRewrite
+		msg      string
+	}{
+		{
+			rewrites: []v1.ResponseBodyRewrite{{Replacement: "new"}},
+			msg:      "missing match",
+		},
+		{
+			rewrites: []v1.ResponseBodyRewrite{{Match: "old", Replacement: "new"}, {Match: "old", Replacement: "newer"}},
+			msg:      "duplicate match",
+		},
+		{
+			rewrites: []v1.ResponseBodyRewrite{{Match: "old", Replacement: "$request_uri"}},
+			msg:      "variable in replacement",
+		},
+		{
+			rewrites: []v1.ResponseBodyRewrite{{Match: "$1", Replacement: "new"}},
+			msg:      "variable in match",
+		},
+		{
+			rewrites: []v1.ResponseBodyRewrite{{Match: `old"`, Replacement: "new"}},
+			msg:      "unescaped double quote",
+		},
+		{
+			rewrites: []v1.ResponseBodyRewrite{{Match: "old", Replacement: "new", Once: true}, {Match: "older", Replacement: "new"}},
+			msg:      "different once",
+		},
+		{
+			rewrites: []v1.ResponseBodyRewrite{{Match: "old", Replacement: "new", Types: []string{"json"}}},
+			msg:      "invalid MIME type",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateActionProxyResponseBodyRewrite(test.rewrites, field.NewPath("responseBodyRewrite"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateActionProxyResponseBodyRewrite() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateActionProxyRewritePathForRegexp(t *testing.T) {
 	t.Parallel()
-	tests := []string{"/rewrite$1", "test", `/$2`, `\"test\"`}
+	vsv := &VirtualServerValidator{isPlus: false}
+	tests := []string{"/rewrite$1", "test", `/$2`, `\"test\"`, "/users/${id}/${http_x_tenant}"}
 	for _, test := range tests {
-		allErrs := validateActionProxyRewritePathForRegexp(test, field.NewPath("rewritePath"))
+		allErrs := vsv.validateActionProxyRewritePathForRegexp(test, `~ ^/users/(?<id>\d+)`, field.NewPath("rewritePath"))
 		if len(allErrs) != 0 {
 			t.Errorf("validateActionProxyRewritePathForRegexp(%v) returned errors for valid input: %v", test, allErrs)
 		}
@@ -3777,15 +4307,87 @@ func TestValidateActionProxyRewritePathForRegexp(t *testing.T) {
 
 func TestValidateActionProxyRewritePathForRegexpFails(t *testing.T) {
 	t.Parallel()
-	tests := []string{"$request_uri", `"test"`, `test\`}
+	vsv := &VirtualServerValidator{isPlus: false}
+	tests := []string{"$request_uri", `"test"`, `test\`, "/users/${name}", "/users/$id", "/${jwt_claim_sub}"}
 	for _, test := range tests {
-		allErrs := validateActionProxyRewritePathForRegexp(test, field.NewPath("rewritePath"))
+		allErrs := vsv.validateActionProxyRewritePathForRegexp(test, `~ ^/users/(?<id>\d+)`, field.NewPath("rewritePath"))
 		if len(allErrs) == 0 {
 			t.Errorf("validateActionProxyRewritePathForRegexp(%v) returned no errors for invalid input", test)
 		}
 	}
 }
 
+func TestValidateActionProxyRewriteArgs(t *testing.T) {
+	t.Parallel()
+	args := &v1.RewriteArgs{
+		Set: []v1.Argument{
+			{Name: "source", Value: "${server_name}:${server_port}"},
+			{Name: "version", Value: "v2"},
+			{Name: "client", Value: "${remote_addr}"},
+		},
+		Remove: []string{"debug"},
+	}
+	allErrs := validateActionProxyRewriteArgs(args, field.NewPath("rewriteArgs"))
+	if len(allErrs) != 0 {
+		t.Errorf("validateActionProxyRewriteArgs() returned errors for valid input: %v", allErrs)
+	}
+}
+
+func TestValidateActionProxyRewriteArgsFails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		args *v1.RewriteArgs
+		msg  string
+	}{
+		{
+			args: &v1.RewriteArgs{Set: []v1.Argument{{Name: "a-b", Value: "v"}}},
+			msg:  "invalid name",
+		},
+		{
+			args: &v1.RewriteArgs{Set: []v1.Argument{{Name: "a", Value: "v&b=c"}}},
+			msg:  "ampersand in value",
+		},
+		{
+			args: &v1.RewriteArgs{Set: []v1.Argument{{Name: "a", Value: "\"; return 200"}}},
+			msg:  "directive injection",
+		},
+		{
+			args: &v1.RewriteArgs{Set: []v1.Argument{{Name: "a", Value: "$1"}}},
+			msg:  "positional capture",
+		},
+		{
+			args: &v1.RewriteArgs{Set: []v1.Argument{{Name: "a", Value: "${request_uri}"}}},
+			msg:  "invalid variable",
+		},
+		{
+			args: &v1.RewriteArgs{Set: []v1.Argument{{Name: "a", Value: "${http_x_tenant}"}}},
+			msg:  "header variable",
+		},
+		{
+			args: &v1.RewriteArgs{Set: []v1.Argument{{Name: "a", Value: "${arg_tenant}"}}},
+			msg:  "argument variable",
+		},
+		{
+			args: &v1.RewriteArgs{Set: []v1.Argument{{Name: "a", Value: "${cookie_tenant}"}}},
+			msg:  "cookie variable",
+		},
+		{
+			args: &v1.RewriteArgs{Set: []v1.Argument{{Name: "a", Value: "${host}"}}},
+			msg:  "host variable",
+		},
+		{
+			args: &v1.RewriteArgs{Set: []v1.Argument{{Name: "a", Value: "v"}}, Remove: []string{"a"}},
+			msg:  "duplicate name",
+		},
+	}
+	for _, test := range tests {
+		allErrs := validateActionProxyRewriteArgs(test.args, field.NewPath("rewriteArgs"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateActionProxyRewriteArgs() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateActionProxyHeader(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -14,6 +14,8 @@ type K8sV1Interface interface {
 	RESTClient() rest.Interface
 	GlobalConfigurationsGetter
 	PoliciesGetter
+	ReferenceGrantsGetter
+	RolloutsGetter
 	TransportServersGetter
 	VirtualServersGetter
 	VirtualServerRoutesGetter
@@ -32,6 +34,14 @@ func (c *K8sV1Client) Policies(namespace string) PolicyInterface {
 	return newPolicies(c, namespace)
 }
 
+func (c *K8sV1Client) ReferenceGrants(namespace string) ReferenceGrantInterface {
+	return newReferenceGrants(c, namespace)
+}
+
+func (c *K8sV1Client) Rollouts(namespace string) RolloutInterface {
+	return newRollouts(c, namespace)
+}
+
 func (c *K8sV1Client) TransportServers(namespace string) TransportServerInterface {
 	return newTransportServers(c, namespace)
 }
@@ -20,6 +20,14 @@ func (c *FakeK8sV1) Policies(namespace string) v1.PolicyInterface {
 	return newFakePolicies(c, namespace)
 }
 
+func (c *FakeK8sV1) ReferenceGrants(namespace string) v1.ReferenceGrantInterface {
+	return newFakeReferenceGrants(c, namespace)
+}
+
+func (c *FakeK8sV1) Rollouts(namespace string) v1.RolloutInterface {
+	return newFakeRollouts(c, namespace)
+}
+
 func (c *FakeK8sV1) TransportServers(namespace string) v1.TransportServerInterface {
 	return newFakeTransportServers(c, namespace)
 }
@@ -6,6 +6,10 @@ type GlobalConfigurationExpansion interface{}
 
 type PolicyExpansion interface{}
 
+type ReferenceGrantExpansion interface{}
+
+type RolloutExpansion interface{}
+
 type TransportServerExpansion interface{}
 
 type VirtualServerExpansion interface{}
@@ -12,6 +12,10 @@ type Interface interface {
 	GlobalConfigurations() GlobalConfigurationInformer
 	// Policies returns a PolicyInformer.
 	Policies() PolicyInformer
+	// ReferenceGrants returns a ReferenceGrantInformer.
+	ReferenceGrants() ReferenceGrantInformer
+	// Rollouts returns a RolloutInformer.
+	Rollouts() RolloutInformer
 	// TransportServers returns a TransportServerInformer.
 	TransportServers() TransportServerInformer
 	// VirtualServers returns a VirtualServerInformer.
@@ -41,6 +45,16 @@ func (v *version) Policies() PolicyInformer {
 	return &policyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ReferenceGrants returns a ReferenceGrantInformer.
+func (v *version) ReferenceGrants() ReferenceGrantInformer {
+	return &referenceGrantInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// Rollouts returns a RolloutInformer.
+func (v *version) Rollouts() RolloutInformer {
+	return &rolloutInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TransportServers returns a TransportServerInformer.
 func (v *version) TransportServers() TransportServerInformer {
 	return &transportServerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -96,6 +96,18 @@ test_data = [
     ("/regex2/abc", {"arg": "value"}, {}, "/abc?arg=value"),
 ]
 
+args_test_data = [
+    ("/args", [("arg", "value")], {}, "/args?arg=value&tenant=cafe"),
+    (
+        "/args",
+        [("tenant", "a"), ("debug", "1"), ("arg", "value"), ("tenant", "b"), ("debug", "2")],
+        {},
+        "/args?arg=value&tenant=cafe",
+    ),
+    ("/args-match", [("tenant", "a"), ("tenant", "b")], {}, "/args-match?tenant=cafe"),
+    ("/args-match", [("tenant", "a"), ("tenant", "b")], {"user": "john"}, "/args-match?tenant=john"),
+]
+
 
 @pytest.mark.parametrize("crd_ingress_controller", [{"type": "complete"}], indirect=True)
 class TestRewrites:
@@ -111,6 +123,18 @@ class TestRewrites:
 
         assert f"URI: {expected}\nRequest" in resp.text
 
+    @pytest.mark.vs
+    @pytest.mark.vs_rewrite
+    @pytest.mark.parametrize("path,args,cookies,expected", args_test_data)
+    def test_vs_rewrite_args(self, vs_rewrites_setup, path, args, cookies, expected):
+        """
+        Test VirtualServer rewrite of the arguments, including the arguments passed multiple times
+        """
+        url = vs_rewrites_setup.url_base + path
+        resp = requests.get(url, headers={"host": "vs.example.com"}, params=args, cookies=cookies)
+
+        assert f"URI: {expected}\nRequest" in resp.text
+
     @pytest.mark.vsr
     @pytest.mark.vsr_rewrite
     @pytest.mark.parametrize("path,args,cookies,expected", test_data)
@@ -0,0 +1,105 @@
+import hashlib
+import hmac
+import time
+
+import pytest
+import requests
+from settings import TEST_DATA
+from suite.utils.policy_resources_utils import create_policy_from_yaml, delete_policy
+from suite.utils.resources_utils import create_secret_from_yaml, delete_secret, wait_before_test
+from suite.utils.vs_vsr_resources_utils import delete_and_create_vs_from_yaml
+
+std_vs_src = f"{TEST_DATA}/virtual-server/standard/virtual-server.yaml"
+hmac_sec_src = f"{TEST_DATA}/signature-verification-policy/secret/hmac-secret.yaml"
+signature_pol_src = f"{TEST_DATA}/signature-verification-policy/policies/signature-verification-policy.yaml"
+signature_vs_src = f"{TEST_DATA}/signature-verification-policy/spec/virtual-server-policy.yaml"
+
+hmac_key = b"secret"
+body = '{"event":"test"}'
+
+
+def sign(timestamp, payload):
+    signed = f"v0:{timestamp}:{payload}".encode()
+    return "v0=" + hmac.new(hmac_key, signed, hashlib.sha256).hexdigest()
+
+
+@pytest.mark.policies
+@pytest.mark.parametrize(
+    "crd_ingress_controller, virtual_server_setup",
+    [
+        (
+            {
+                "type": "complete",
+                "extra_args": [
+                    f"-enable-custom-resources",
+                    f"-enable-leader-election=false",
+                ],
+            },
+            {
+                "example": "virtual-server",
+                "app_type": "simple",
+            },
+        )
+    ],
+    indirect=True,
+)
+class TestSignatureVerificationPolicies:
+    def request(self, virtual_server_setup, signature, timestamp, payload):
+        headers = {
+            "host": virtual_server_setup.vs_host,
+            "X-Signature": signature,
+            "X-Request-Timestamp": str(timestamp),
+        }
+        return requests.post(virtual_server_setup.backend_1_url, headers=headers, data=payload)
+
+    def test_signature_verification_policy(
+        self,
+        kube_apis,
+        crd_ingress_controller,
+        virtual_server_setup,
+        test_namespace,
+    ):
+        """
+        Test signature-verification-policy with a valid signature, a tampered timestamp, a tampered body
+        and an expired timestamp
+        """
+        print(f"Create hmac secret")
+        secret = create_secret_from_yaml(kube_apis.v1, test_namespace, hmac_sec_src)
+        print(f"Create signature verification policy")
+        pol_name = create_policy_from_yaml(kube_apis.custom_objects, signature_pol_src, test_namespace)
+        wait_before_test()
+
+        print(f"Patch vs with policy: {signature_vs_src}")
+        delete_and_create_vs_from_yaml(
+            kube_apis.custom_objects,
+            virtual_server_setup.vs_name,
+            signature_vs_src,
+            virtual_server_setup.namespace,
+        )
+        wait_before_test()
+
+        now = int(time.time())
+        valid_resp = self.request(virtual_server_setup, sign(now, body), now, body)
+        # the timestamp is changed after the request was signed, for example, to replay the request.
+        tampered_timestamp_resp = self.request(virtual_server_setup, sign(now, body), now + 1, body)
+        tampered_body_resp = self.request(virtual_server_setup, sign(now, body), now, '{"event":"other"}')
+        expired_resp = self.request(virtual_server_setup, sign(now - 3600, body), now - 3600, body)
+        no_signature_resp = requests.post(
+            virtual_server_setup.backend_1_url, headers={"host": virtual_server_setup.vs_host}, data=body
+        )
+
+        delete_policy(kube_apis.custom_objects, pol_name, test_namespace)
+        delete_secret(kube_apis.v1, secret, test_namespace)
+        delete_and_create_vs_from_yaml(
+            kube_apis.custom_objects,
+            virtual_server_setup.vs_name,
+            std_vs_src,
+            virtual_server_setup.namespace,
+        )
+
+        assert valid_resp.status_code == 200
+        assert f"Request ID:" in valid_resp.text
+        assert tampered_timestamp_resp.status_code == 401
+        assert tampered_body_resp.status_code == 401
+        assert expired_resp.status_code == 401
+        assert no_signature_resp.status_code == 401